@@ -0,0 +1,242 @@
+// Package bridge contains the tooling shared by the importers and exporters
+// of bugs from/to other bug trackers.
+package bridge
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/bug/operations"
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util"
+)
+
+// IdentityMapping translate the login of a user on an upstream bug tracker
+// into a git-bug Person, so that the same human doesn't appear twice.
+//
+// The mapping is stored in git as a JSON blob referenced by a dedicated chain
+// of commits, which keep the history of the changes. It's pushed and pulled
+// along with the bugs of its namespace, see bug.IdentityMappingRef.
+type IdentityMapping struct {
+	users map[string]bug.Person
+
+	// the commit the mapping has been read from, if any
+	lastCommit util.Hash
+}
+
+// ReadIdentityMapping read the identity mapping stored in the repository.
+// If none has been stored yet, an empty mapping is returned.
+func ReadIdentityMapping(repo repository.Repo) (*IdentityMapping, error) {
+	mapping := &IdentityMapping{
+		users: make(map[string]bug.Person),
+	}
+
+	ref := bug.IdentityMappingRef(repo)
+
+	exist, err := repo.RefExist(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exist {
+		return mapping, nil
+	}
+
+	mapping.lastCommit, err = repo.ResolveRef(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := repo.ListEntries(mapping.lastCommit)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		if entry.Name != bug.IdentityMappingEntryName {
+			continue
+		}
+
+		data, err := repo.ReadData(entry.Hash)
+		if err != nil {
+			return nil, err
+		}
+
+		err = json.Unmarshal(data, &mapping.users)
+		if err != nil {
+			return nil, fmt.Errorf("invalid identity mapping: %v", err)
+		}
+
+		return mapping, nil
+	}
+
+	return nil, fmt.Errorf("invalid identity mapping: missing the %s entry", bug.IdentityMappingEntryName)
+}
+
+// Map register the Person to use for an upstream login
+func (im *IdentityMapping) Map(login string, person bug.Person) {
+	im.users[login] = person
+}
+
+// Unmap remove the mapping of an upstream login
+func (im *IdentityMapping) Unmap(login string) {
+	delete(im.users, login)
+}
+
+// Logins return the mapped upstream logins, sorted
+func (im *IdentityMapping) Logins() []string {
+	logins := make([]string, 0, len(im.users))
+	for login := range im.users {
+		logins = append(logins, login)
+	}
+	sort.Strings(logins)
+	return logins
+}
+
+// Lookup return the Person mapped to an upstream login, if any
+func (im *IdentityMapping) Lookup(login string) (bug.Person, bool) {
+	person, ok := im.users[login]
+	return person, ok
+}
+
+// Resolve return the Person to use as the author of an operation for an
+// upstream login. An unknown login is used as is for the name of the Person.
+func (im *IdentityMapping) Resolve(login string) bug.Person {
+	if person, ok := im.users[login]; ok {
+		return person
+	}
+	return bug.Person{Name: login}
+}
+
+// Remaps return the mapping as remaps of the authors of the bugs, sorted by
+// login
+func (im *IdentityMapping) Remaps() []bug.AuthorRemap {
+	logins := im.Logins()
+
+	remaps := make([]bug.AuthorRemap, len(logins))
+	for i, login := range logins {
+		remaps[i] = bug.AuthorRemap{Login: login, Person: im.users[login]}
+	}
+
+	return remaps
+}
+
+// Remap apply the mapping to the bugs imported before the logins were
+// mapped. Rather than rewriting their history, an operation remapping the
+// authors is appended to the bugs that need it, see
+// operations.RemapAuthors. The number of remapped bugs is returned.
+func (im *IdentityMapping) Remap(repo repository.Repo, author bug.Person) (int, error) {
+	remaps := im.Remaps()
+	if len(remaps) == 0 {
+		return 0, nil
+	}
+
+	var remapped []*bug.Bug
+	var readErr error
+
+	// read them all first, the bugs are committed once the refs are listed
+	for streamed := range bug.ReadAllLocalBugs(repo) {
+		if streamed.Err != nil {
+			if readErr == nil {
+				readErr = streamed.Err
+			}
+			continue
+		}
+
+		if operations.RemapAuthors(streamed.Bug, author, remaps) {
+			remapped = append(remapped, streamed.Bug)
+		}
+	}
+
+	if readErr != nil {
+		return 0, readErr
+	}
+
+	for i, b := range remapped {
+		if err := b.Commit(repo); err != nil {
+			return i, err
+		}
+	}
+
+	return len(remapped), nil
+}
+
+// ReverseLookup return the upstream login mapped to a Person, if any.
+// Persons are matched on their email first, then on their name.
+func (im *IdentityMapping) ReverseLookup(person bug.Person) (string, bool) {
+	logins := im.Logins()
+
+	if person.Email != "" {
+		for _, login := range logins {
+			if im.users[login].Email == person.Email {
+				return login, true
+			}
+		}
+	}
+
+	for _, login := range logins {
+		if im.users[login].Name == person.Name {
+			return login, true
+		}
+	}
+
+	return "", false
+}
+
+// Write store the identity mapping in the repository
+func (im *IdentityMapping) Write(repo repository.Repo) error {
+	data, err := json.MarshalIndent(im.users, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	blobHash, err := repo.StoreData(data)
+	if err != nil {
+		return err
+	}
+
+	treeHash, err := repo.StoreTree([]repository.TreeEntry{
+		{ObjectType: repository.Blob, Hash: blobHash, Name: bug.IdentityMappingEntryName},
+	})
+	if err != nil {
+		return err
+	}
+
+	var commitHash util.Hash
+	if im.lastCommit != "" {
+		commitHash, err = repo.StoreCommitWithParent(treeHash, im.lastCommit)
+	} else {
+		commitHash, err = repo.StoreCommit(treeHash)
+	}
+	if err != nil {
+		return err
+	}
+
+	err = repo.UpdateRef(bug.IdentityMappingRef(repo), commitHash)
+	if err != nil {
+		return err
+	}
+
+	im.lastCommit = commitHash
+
+	return nil
+}
+
+// OnBehalfOf format the body of a comment exported with the token of
+// tokenOwner. When the author of the comment is someone else, the body is
+// prefixed to credit the real author.
+func (im *IdentityMapping) OnBehalfOf(tokenOwner string, author bug.Person, body string) string {
+	login, ok := im.ReverseLookup(author)
+
+	if ok && login == tokenOwner {
+		return body
+	}
+
+	if ok {
+		return fmt.Sprintf("On behalf of @%s:\n\n%s", login, body)
+	}
+
+	return fmt.Sprintf("On behalf of %s:\n\n%s", author.Name, body)
+}
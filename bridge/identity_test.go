@@ -0,0 +1,63 @@
+package bridge
+
+import (
+	"testing"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func TestIdentityMapping(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+
+	mapping, err := ReadIdentityMapping(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(mapping.Logins()) != 0 {
+		t.Fatal("expected an empty mapping")
+	}
+
+	jane := bug.Person{Name: "Jane Doe", Email: "jane@example.com"}
+
+	mapping.Map("octocat", jane)
+	if err := mapping.Write(repo); err != nil {
+		t.Fatal(err)
+	}
+
+	mapping.Map("rene", bug.Person{Name: "René Descartes", Email: "rene@descartes.fr"})
+	if err := mapping.Write(repo); err != nil {
+		t.Fatal(err)
+	}
+
+	mapping, err = ReadIdentityMapping(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(mapping.Logins()) != 2 {
+		t.Fatalf("unexpected number of mapped logins: %d", len(mapping.Logins()))
+	}
+
+	if mapping.Resolve("octocat") != jane {
+		t.Fatal("octocat should resolve to Jane")
+	}
+
+	if mapping.Resolve("unknown") != (bug.Person{Name: "unknown"}) {
+		t.Fatal("an unknown login should be used as name")
+	}
+
+	login, ok := mapping.ReverseLookup(jane)
+	if !ok || login != "octocat" {
+		t.Fatal("Jane should be mapped to octocat")
+	}
+
+	if mapping.OnBehalfOf("octocat", jane, "body") != "body" {
+		t.Fatal("no attribution expected for the token owner")
+	}
+
+	if mapping.OnBehalfOf("rene", jane, "body") != "On behalf of @octocat:\n\nbody" {
+		t.Fatal("unexpected attribution")
+	}
+}
@@ -0,0 +1,126 @@
+package jsonl
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util"
+)
+
+// Export write each local bug as a line of the stream read by Import, in the
+// order of their id. The bugs are read and written one at a time, so that
+// the memory used doesn't grow with the number of bugs, and each line is
+// written as soon as it's ready.
+//
+// The deleted and archived comments are left out.
+func Export(repo repository.Repo, w io.Writer) error {
+	ids, err := bug.ListLocalIds(repo)
+	if err != nil {
+		return err
+	}
+	sort.Strings(ids)
+
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+
+	for _, id := range ids {
+		b, err := bug.ReadLocalBug(repo, id)
+		if err != nil {
+			return err
+		}
+
+		data, err := exportBug(b)
+		if err != nil {
+			return err
+		}
+
+		// Encode write the line and its line break at once
+		if err := encoder.Encode(data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func exportBug(b *bug.Bug) (Bug, error) {
+	snap := b.Compile()
+
+	hashes, err := snap.CommentHashes()
+	if err != nil {
+		return Bug{}, err
+	}
+	if len(hashes) != len(snap.Comments) {
+		return Bug{}, fmt.Errorf("bug %s: %d comments for %d hashes", b.Id(), len(snap.Comments), len(hashes))
+	}
+
+	// the comments imported keep their external id
+	externalIds := make(map[util.Hash]string)
+	for _, op := range snap.Operations {
+		if op.OpType() != bug.CreateOp && op.OpType() != bug.AddCommentOp {
+			continue
+		}
+		id, ok := op.GetMetadata(MetadataKey)
+		if !ok {
+			continue
+		}
+		hash, err := bug.HashOperation(op)
+		if err != nil {
+			return Bug{}, err
+		}
+		externalIds[hash] = id
+	}
+
+	externalId, ok := b.FirstOp().GetMetadata(MetadataKey)
+	if !ok {
+		externalId = b.Id()
+	}
+
+	data := Bug{
+		ExternalId: externalId,
+		Title:      snap.Title,
+		Author:     exportAuthor(snap.Author),
+		CreatedAt:  snap.CreatedAt.UTC(),
+		Closed:     snap.Status == bug.ClosedStatus,
+		// empty arrays rather than null, easier to handle with jq
+		Comments: []Comment{},
+		Labels:   []string{},
+	}
+
+	if len(snap.Comments) > 0 && !snap.Comments[0].IsDeleted() {
+		data.Body = snap.Comments[0].GetMessage()
+	}
+
+	for i, comment := range snap.Comments {
+		if i == 0 || comment.IsDeleted() {
+			continue
+		}
+
+		id := string(hashes[i])
+		if externalId, ok := externalIds[hashes[i]]; ok {
+			id = externalId
+		}
+
+		data.Comments = append(data.Comments, Comment{
+			ExternalId: id,
+			Author:     exportAuthor(comment.Author),
+			Body:       comment.GetMessage(),
+			CreatedAt:  time.Unix(comment.UnixTime, 0).UTC(),
+		})
+	}
+
+	for _, label := range snap.Labels {
+		data.Labels = append(data.Labels, string(label))
+	}
+
+	return data, nil
+}
+
+func exportAuthor(person bug.Person) *Author {
+	return &Author{Name: person.Name, Email: person.Email}
+}
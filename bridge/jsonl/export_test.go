@@ -0,0 +1,124 @@
+package jsonl
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/internal/testfixture"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func createBugs(t *testing.T, repo repository.Repo) {
+	author := bug.Person{Name: "Isaac Newton", Email: "isaac@newton.uk"}
+	other := bug.Person{Name: "René Descartes", Email: "rene@descartes.fr"}
+
+	_, err := testfixture.BuildBug(repo, testfixture.New(author).
+		Create("first", "message with \"quotes\"\nand <html>").
+		As(other).Comment("comment").
+		As(author).Label("bug", "ui").
+		As(other).Close())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = testfixture.BuildBug(repo, testfixture.New(other).Create("second", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func exportLines(t *testing.T, repo repository.Repo) []string {
+	var buf bytes.Buffer
+	if err := Export(repo, &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	var lines []string
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines
+}
+
+func TestExportLines(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+	createBugs(t, repo)
+
+	lines := exportLines(t, repo)
+	if len(lines) != 2 {
+		t.Fatalf("Expected a line per bug, got %d", len(lines))
+	}
+
+	for _, line := range lines {
+		if !json.Valid([]byte(line)) {
+			t.Fatalf("Invalid JSON line %s", line)
+		}
+	}
+}
+
+func TestExportRoundTrip(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+	createBugs(t, repo)
+
+	stream := strings.Join(exportLines(t, repo), "\n")
+
+	// importing back in the same repository change nothing
+	result, err := Import(repo, strings.NewReader(stream), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Created != 0 || result.Updated != 0 || result.Unchanged != 2 {
+		t.Fatalf("Importing the export back should change nothing, got %+v", result)
+	}
+
+	other := repository.NewMockRepoForTest()
+
+	result, err = Import(other, strings.NewReader(stream), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Created != 2 {
+		t.Fatalf("Unexpected result %+v", result)
+	}
+
+	original := make(map[string]bug.Snapshot)
+	for _, snap := range readImported(t, repo) {
+		original[snap.Title] = snap
+	}
+
+	for _, snap := range readImported(t, other) {
+		want := original[snap.Title]
+
+		if snap.Author != want.Author || !snap.CreatedAt.Equal(want.CreatedAt) || snap.Status != want.Status {
+			t.Fatalf("Unexpected bug %+v, expected %+v", snap, want)
+		}
+		if len(snap.Labels) != len(want.Labels) {
+			t.Fatalf("Unexpected labels %v, expected %v", snap.Labels, want.Labels)
+		}
+		if len(snap.Comments) != len(want.Comments) {
+			t.Fatalf("Unexpected comments %+v, expected %+v", snap.Comments, want.Comments)
+		}
+		for i, comment := range snap.Comments {
+			if comment.Message != want.Comments[i].Message || comment.Author != want.Comments[i].Author {
+				t.Fatalf("Unexpected comment %+v, expected %+v", comment, want.Comments[i])
+			}
+		}
+	}
+
+	// the export of the imported bugs is the same, their external id being
+	// the id of the original ones, but not in the same order as their ids
+	// differ
+	sorted := func(lines []string) string {
+		sort.Strings(lines)
+		return strings.Join(lines, "\n")
+	}
+	if again := sorted(exportLines(t, other)); again != sorted(exportLines(t, repo)) {
+		t.Fatalf("Unexpected export of the imported bugs\n%s", again)
+	}
+}
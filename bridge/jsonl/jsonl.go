@@ -0,0 +1,368 @@
+// Package jsonl import bugs from a stream of JSON objects, one per line, so
+// that any scriptable source can be imported without a dedicated bridge.
+//
+// Each line describe a bug:
+//
+//	{
+//	  "external_id": "JIRA-42",              required
+//	  "title": "Crash on startup",           required
+//	  "body": "It crash.",
+//	  "author": {"name": "...", "email": "...", "login": "..."},
+//	  "created_at": "2018-09-01T12:00:00Z",  RFC 3339
+//	  "comments": [
+//	    {"external_id": "...", "author": {...}, "body": "...", "created_at": "..."}
+//	  ],
+//	  "labels": ["bug", "ui"],
+//	  "closed": true
+//	}
+//
+// The external ids are stored in the metadata of the operations, so that
+// importing the same stream again update the bugs instead of duplicating
+// them: the new comments are added, and the title, labels and status are
+// changed to match. The description of an imported bug is never changed.
+// A comment without external id is identified by its index.
+//
+// Without author, the identity configured in git is used. An author with a
+// login mapped in the identity mapping of the bridges, see
+// bridge.IdentityMapping, is replaced by the mapped person.
+//
+// Export write the local bugs in the same format, one line at a time, so
+// that they can be imported in another repository or processed by tools like
+// jq. The external id of a bug or comment created in git-bug is its id or
+// hash, so that importing an export back doesn't duplicate anything.
+package jsonl
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/MichaelMure/git-bug/bridge"
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/bug/operations"
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util"
+)
+
+// MetadataKey is the key of the operation metadata holding the external id
+// of the imported bugs and comments
+const MetadataKey = "jsonl-external-id"
+
+// the longest line accepted, a bug with all its comments
+const maxLineSize = 16 * 1024 * 1024
+
+// Author of a bug or a comment
+type Author struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	// The login of the author on the bug tracker the bugs come from
+	Login string `json:"login,omitempty"`
+}
+
+// Comment of a bug
+type Comment struct {
+	ExternalId string    `json:"external_id"`
+	Author     *Author   `json:"author"`
+	Body       string    `json:"body"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Bug is a line of the stream
+type Bug struct {
+	ExternalId string    `json:"external_id"`
+	Title      string    `json:"title"`
+	Body       string    `json:"body"`
+	Author     *Author   `json:"author"`
+	CreatedAt  time.Time `json:"created_at"`
+	Comments   []Comment `json:"comments"`
+	Labels     []string  `json:"labels"`
+	Closed     bool      `json:"closed"`
+}
+
+// LineError is a malformed or invalid line of the stream
+type LineError struct {
+	Line int
+	Err  error
+}
+
+func (e LineError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Err)
+}
+
+// Result summarize an import
+type Result struct {
+	Created   int
+	Updated   int
+	Unchanged int
+	// The skipped lines, when not strict
+	Skipped []LineError
+}
+
+// Import read the bugs from the stream and create or update them. A
+// malformed line is skipped, unless strict is set: the import then stop
+// with a LineError.
+func Import(repo repository.Repo, r io.Reader, strict bool) (*Result, error) {
+	defaultAuthor, err := bug.DefaultIdentity(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	mapping, err := bridge.ReadIdentityMapping(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	imported, err := importedBugs(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxLineSize)
+	line := 0
+
+	for scanner.Scan() {
+		line++
+
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var data Bug
+		err := json.Unmarshal(scanner.Bytes(), &data)
+		if err == nil {
+			err = data.validate()
+		}
+
+		if err == nil {
+			err = importBug(repo, imported, mapping, data, defaultAuthor, result)
+		}
+
+		if err != nil {
+			lineErr := LineError{Line: line, Err: err}
+			if strict {
+				return result, lineErr
+			}
+			result.Skipped = append(result.Skipped, lineErr)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// importedBugs index the ids of the local bugs by the external id of their
+// creation. Only the ids are kept, the bugs are read back when needed.
+func importedBugs(repo repository.Repo) (map[string]string, error) {
+	imported := make(map[string]string)
+
+	for streamed := range bug.ReadAllLocalBugs(repo) {
+		if streamed.Err != nil {
+			return nil, streamed.Err
+		}
+
+		id, ok := streamed.Bug.FirstOp().GetMetadata(MetadataKey)
+		if !ok {
+			// the bugs created in git-bug are exported with their id
+			id = streamed.Bug.Id()
+		}
+		imported[id] = streamed.Bug.Id()
+	}
+
+	return imported, nil
+}
+
+func (data *Bug) validate() error {
+	if data.ExternalId == "" {
+		return errors.New("missing external_id")
+	}
+
+	title, err := util.CleanupText(data.Title)
+	if err != nil {
+		return err
+	}
+	if title == "" {
+		return errors.New("missing title")
+	}
+	data.Title = title
+
+	data.Body, err = util.CleanupText(data.Body)
+	if err != nil {
+		return err
+	}
+
+	for i := range data.Comments {
+		data.Comments[i].Body, err = util.CleanupText(data.Comments[i].Body)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (a *Author) person(mapping *bridge.IdentityMapping, defaultAuthor bug.Person) bug.Person {
+	if a == nil {
+		return defaultAuthor
+	}
+	if a.Login != "" {
+		if person, ok := mapping.Lookup(a.Login); ok {
+			return person
+		}
+		if a.Name == "" {
+			return bug.Person{Name: a.Login}
+		}
+	}
+	if a.Name == "" {
+		return defaultAuthor
+	}
+	return bug.Person{Name: a.Name, Email: a.Email}
+}
+
+// setTime date an operation, now if the time is not given
+func setTime(op *bug.OpBase, t time.Time) {
+	if !t.IsZero() {
+		op.UnixTime = t.Unix()
+	}
+}
+
+func importBug(repo repository.Repo, imported map[string]string, mapping *bridge.IdentityMapping, data Bug, defaultAuthor bug.Person, result *Result) error {
+	author := data.Author.person(mapping, defaultAuthor)
+
+	var b *bug.Bug
+	id, exist := imported[data.ExternalId]
+
+	if exist {
+		var err error
+		b, err = bug.ReadLocalBug(repo, id)
+		if err != nil {
+			return err
+		}
+	} else {
+		b = bug.NewBug()
+
+		createOp := operations.NewCreateOp(author, data.Title, data.Body, nil)
+		setTime(&createOp.OpBase, data.CreatedAt)
+		createOp.SetMetadata(MetadataKey, data.ExternalId)
+		b.Append(createOp)
+	}
+
+	snap := b.Compile()
+
+	if err := updateBug(b, snap, mapping, data, author, defaultAuthor); err != nil {
+		return err
+	}
+
+	if !b.HasPendingOp() {
+		result.Unchanged++
+		return nil
+	}
+
+	if err := b.Commit(repo); err != nil {
+		return err
+	}
+
+	if exist {
+		result.Updated++
+	} else {
+		result.Created++
+		imported[data.ExternalId] = b.Id()
+	}
+
+	return nil
+}
+
+// updateBug append the operations needed for the bug to match the data
+func updateBug(b *bug.Bug, snap bug.Snapshot, mapping *bridge.IdentityMapping, data Bug, author bug.Person, defaultAuthor bug.Person) error {
+	importedComments := make(map[string]bool)
+
+	// the comments created in git-bug are exported with their hash
+	hashes, err := snap.CommentHashes()
+	if err != nil {
+		return err
+	}
+	for _, hash := range hashes {
+		importedComments[string(hash)] = true
+	}
+
+	for _, op := range snap.Operations {
+		if op.OpType() != bug.AddCommentOp {
+			continue
+		}
+		if id, ok := op.GetMetadata(MetadataKey); ok {
+			importedComments[id] = true
+		}
+	}
+
+	for i, comment := range data.Comments {
+		id := comment.ExternalId
+		if id == "" {
+			id = strconv.Itoa(i)
+		}
+
+		if importedComments[id] {
+			continue
+		}
+
+		commentOp := operations.NewAddCommentOp(comment.Author.person(mapping, defaultAuthor), comment.Body, nil)
+		setTime(&commentOp.OpBase, comment.CreatedAt)
+		commentOp.SetMetadata(MetadataKey, id)
+		b.Append(commentOp)
+
+		importedComments[id] = true
+	}
+
+	if snap.Title != data.Title {
+		b.Append(operations.NewSetTitleOp(author, data.Title, snap.Title))
+	}
+
+	added, removed := diffLabels(snap.Labels, data.Labels)
+	if len(added) > 0 || len(removed) > 0 {
+		labelOp := operations.NewLabelChangeOperation(author, added, removed)
+		labelOp.Observe(snap)
+		b.Append(labelOp)
+	}
+
+	status := bug.OpenStatus
+	if data.Closed {
+		status = bug.ClosedStatus
+	}
+	if snap.Status != status {
+		b.Append(operations.NewSetStatusOp(author, status))
+	}
+
+	return nil
+}
+
+func diffLabels(current []bug.Label, wanted []string) (added []bug.Label, removed []bug.Label) {
+	has := make(map[bug.Label]bool)
+	for _, label := range current {
+		has[label] = true
+	}
+
+	want := make(map[bug.Label]bool)
+	for _, label := range wanted {
+		l := bug.Label(label)
+		if !want[l] && !has[l] {
+			added = append(added, l)
+		}
+		want[l] = true
+	}
+
+	for _, label := range current {
+		if !want[label] {
+			removed = append(removed, label)
+		}
+	}
+
+	return added, removed
+}
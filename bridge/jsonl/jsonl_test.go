@@ -0,0 +1,156 @@
+package jsonl
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MichaelMure/git-bug/bridge"
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+const stream = `{"external_id": "1", "title": "first", "body": "body", "author": {"name": "Isaac Newton", "email": "isaac@newton.uk"}, "created_at": "2018-09-01T12:00:00Z", "comments": [{"body": "comment"}], "labels": ["bug"]}
+not json
+{"external_id": "2", "title": "second", "closed": true}
+{"title": "no id"}
+`
+
+func readImported(t *testing.T, repo repository.Repo) map[string]bug.Snapshot {
+	imported, err := importedBugs(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	snaps := make(map[string]bug.Snapshot)
+	for externalId, id := range imported {
+		b, err := bug.ReadLocalBug(repo, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		snaps[externalId] = b.Compile()
+	}
+	return snaps
+}
+
+func TestImport(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+
+	result, err := Import(repo, strings.NewReader(stream), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Created != 2 || len(result.Skipped) != 2 {
+		t.Fatalf("Unexpected result %+v", result)
+	}
+	if result.Skipped[0].Line != 2 || result.Skipped[1].Line != 4 {
+		t.Fatalf("Unexpected skipped lines %v", result.Skipped)
+	}
+
+	snaps := readImported(t, repo)
+
+	first := snaps["1"]
+	if first.Title != "first" || first.Author.Name != "Isaac Newton" || len(first.Comments) != 2 {
+		t.Fatalf("Unexpected first bug %+v", first)
+	}
+	if !first.CreatedAt.Equal(time.Date(2018, 9, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Fatalf("Unexpected creation time %s", first.CreatedAt)
+	}
+	if len(first.Labels) != 1 || first.Labels[0] != "bug" || first.Status != bug.OpenStatus {
+		t.Fatalf("Unexpected labels or status %v %s", first.Labels, first.Status)
+	}
+
+	// the missing author is the default identity
+	second := snaps["2"]
+	if second.Status != bug.ClosedStatus || second.Author.Name != "René Descartes" {
+		t.Fatalf("Unexpected second bug %+v", second)
+	}
+}
+
+func TestImportIdempotent(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+
+	_, err := Import(repo, strings.NewReader(stream), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := Import(repo, strings.NewReader(stream), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Created != 0 || result.Updated != 0 || result.Unchanged != 2 {
+		t.Fatalf("Importing again should change nothing, got %+v", result)
+	}
+
+	update := `{"external_id": "1", "title": "renamed", "comments": [{"body": "comment"}, {"external_id": "c2", "body": "new"}], "labels": ["ui"], "closed": true}`
+
+	result, err = Import(repo, strings.NewReader(update), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Updated != 1 {
+		t.Fatalf("Unexpected result %+v", result)
+	}
+
+	first := readImported(t, repo)["1"]
+	if first.Title != "renamed" || len(first.Comments) != 3 || first.Status != bug.ClosedStatus {
+		t.Fatalf("Unexpected updated bug %+v", first)
+	}
+	if len(first.Labels) != 1 || first.Labels[0] != "ui" {
+		t.Fatalf("Unexpected labels %v", first.Labels)
+	}
+
+	ids, err := bug.ListLocalIds(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 2 {
+		t.Fatalf("The bugs should not be duplicated, got %d", len(ids))
+	}
+}
+
+func TestImportStrict(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+
+	result, err := Import(repo, strings.NewReader(stream), true)
+
+	lineErr, ok := err.(LineError)
+	if !ok || lineErr.Line != 2 {
+		t.Fatalf("Expected an error on line 2, got %v", err)
+	}
+	if result.Created != 1 {
+		t.Fatalf("The lines before the error should be imported, got %+v", result)
+	}
+}
+
+func TestImportIdentityMapping(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+
+	jane := bug.Person{Name: "Jane Doe", Email: "jane@example.com"}
+
+	mapping, err := bridge.ReadIdentityMapping(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mapping.Map("jdoe", jane)
+	if err := mapping.Write(repo); err != nil {
+		t.Fatal(err)
+	}
+
+	data := `{"external_id": "1", "title": "mapped", "author": {"name": "jdoe", "login": "jdoe"}, "comments": [{"author": {"login": "unknown"}, "body": "comment"}]}`
+
+	_, err = Import(repo, strings.NewReader(data), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	snap := readImported(t, repo)["1"]
+	if snap.Author != jane {
+		t.Fatalf("The login should be mapped, got %+v", snap.Author)
+	}
+	if snap.Comments[1].Author != (bug.Person{Name: "unknown"}) {
+		t.Fatalf("An unknown login should be used as name, got %+v", snap.Comments[1].Author)
+	}
+}
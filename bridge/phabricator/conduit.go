@@ -0,0 +1,367 @@
+package phabricator
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// the number of results by page, the most Conduit accept
+const pageSize = 100
+
+// Client call the Conduit API of a Phabricator instance
+type Client struct {
+	// the url of the instance, like https://phabricator.example.com
+	BaseUrl string
+	// a Conduit API token, api-xxxx
+	Token string
+
+	Http *http.Client
+}
+
+// NewClient create a Client for a Phabricator instance
+func NewClient(baseUrl string, token string) *Client {
+	return &Client{
+		BaseUrl: strings.TrimRight(baseUrl, "/"),
+		Token:   token,
+		Http:    &http.Client{Timeout: time.Minute},
+	}
+}
+
+// ConduitError is an error returned by the Conduit API
+type ConduitError struct {
+	Method string
+	Code   string
+	Info   string
+}
+
+func (e ConduitError) Error() string {
+	return fmt.Sprintf("%s: %s: %s", e.Method, e.Code, e.Info)
+}
+
+type conduitResponse struct {
+	Result    json.RawMessage `json:"result"`
+	ErrorCode *string         `json:"error_code"`
+	ErrorInfo *string         `json:"error_info"`
+}
+
+// call a Conduit method. The parameters are form encoded, the nested ones
+// with the PHP syntax, like constraints[phids][0].
+func (c *Client) call(method string, params url.Values, result interface{}) error {
+	form := url.Values{}
+	for key, values := range params {
+		form[key] = values
+	}
+	form.Set("api.token", c.Token)
+
+	resp, err := c.Http.PostForm(c.BaseUrl+"/api/"+method, form)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: unexpected HTTP status %s", method, resp.Status)
+	}
+
+	var response conduitResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return fmt.Errorf("%s: invalid response: %v", method, err)
+	}
+
+	if response.ErrorCode != nil {
+		info := ""
+		if response.ErrorInfo != nil {
+			info = *response.ErrorInfo
+		}
+		return ConduitError{Method: method, Code: *response.ErrorCode, Info: info}
+	}
+
+	if err := json.Unmarshal(response.Result, result); err != nil {
+		return fmt.Errorf("%s: invalid result: %v", method, err)
+	}
+
+	return nil
+}
+
+type searchPage struct {
+	Data   []json.RawMessage `json:"data"`
+	Cursor struct {
+		After json.RawMessage `json:"after"`
+	} `json:"cursor"`
+}
+
+// cursorValue normalize a cursor, given by Conduit as a string, a number or
+// null at the end of the results
+func cursorValue(raw json.RawMessage) (string, error) {
+	value := strings.TrimSpace(string(raw))
+
+	if value == "" || value == "null" {
+		return "", nil
+	}
+
+	if strings.HasPrefix(value, `"`) {
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return "", err
+		}
+		return s, nil
+	}
+
+	if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+		return "", fmt.Errorf("invalid cursor %s", value)
+	}
+
+	return value, nil
+}
+
+// search call a *.search method, page after page, with each result. The
+// "after" cursor of a page is the one to ask for the next page, until it's
+// null. A cursor given twice would loop forever, it's an error.
+func (c *Client) search(method string, params url.Values, fn func(data json.RawMessage) error) error {
+	seen := make(map[string]bool)
+	after := ""
+
+	for {
+		page := url.Values{}
+		for key, values := range params {
+			page[key] = values
+		}
+		page.Set("limit", strconv.Itoa(pageSize))
+		if after != "" {
+			page.Set("after", after)
+		}
+
+		var result searchPage
+		if err := c.call(method, page, &result); err != nil {
+			return err
+		}
+
+		for _, data := range result.Data {
+			if err := fn(data); err != nil {
+				return err
+			}
+		}
+
+		next, err := cursorValue(result.Cursor.After)
+		if err != nil {
+			return fmt.Errorf("%s: %v", method, err)
+		}
+
+		if next == "" {
+			return nil
+		}
+
+		if seen[next] {
+			return fmt.Errorf("%s: the cursor %s is given again", method, next)
+		}
+		seen[next] = true
+		after = next
+	}
+}
+
+// timestamp is a unix time, given by Conduit as a number or a string
+type timestamp int64
+
+func (t *timestamp) UnmarshalJSON(data []byte) error {
+	value := strings.Trim(string(data), `"`)
+	if value == "null" || value == "" {
+		*t = 0
+		return nil
+	}
+
+	i, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid timestamp %s", data)
+	}
+
+	*t = timestamp(i)
+	return nil
+}
+
+func (t timestamp) Time() time.Time {
+	return time.Unix(int64(t), 0)
+}
+
+// object decode a JSON object, PHP serializing an empty one as an empty
+// array
+func object(data json.RawMessage, v interface{}) error {
+	value := strings.TrimSpace(string(data))
+	if value == "" || value == "null" || value == "[]" {
+		return nil
+	}
+	return json.Unmarshal(data, v)
+}
+
+type task struct {
+	Id     int    `json:"id"`
+	Phid   string `json:"phid"`
+	Fields struct {
+		Name        string `json:"name"`
+		Description struct {
+			Raw string `json:"raw"`
+		} `json:"description"`
+		AuthorPhid string `json:"authorPHID"`
+		Status     struct {
+			Value string `json:"value"`
+			Name  string `json:"name"`
+		} `json:"status"`
+		Priority struct {
+			Value int    `json:"value"`
+			Name  string `json:"name"`
+		} `json:"priority"`
+		DateCreated  timestamp `json:"dateCreated"`
+		DateModified timestamp `json:"dateModified"`
+	} `json:"fields"`
+	Attachments json.RawMessage `json:"attachments"`
+}
+
+// projects return the PHIDs of the projects of the task, when attached
+func (t task) projects() ([]string, error) {
+	var attachments struct {
+		Projects json.RawMessage `json:"projects"`
+	}
+	if err := object(t.Attachments, &attachments); err != nil {
+		return nil, err
+	}
+
+	var projects struct {
+		ProjectPhids []string `json:"projectPHIDs"`
+	}
+	if err := object(attachments.Projects, &projects); err != nil {
+		return nil, err
+	}
+
+	return projects.ProjectPhids, nil
+}
+
+// searchTasks call fn with each task modified since the given time, the
+// oldest first
+func (c *Client) searchTasks(modifiedSince int64, fn func(t task) error) error {
+	params := url.Values{}
+	params.Set("order", "oldest")
+	params.Set("attachments[projects]", "1")
+	if modifiedSince > 0 {
+		params.Set("constraints[modifiedStart]", strconv.FormatInt(modifiedSince, 10))
+	}
+
+	return c.search("maniphest.search", params, func(data json.RawMessage) error {
+		var t task
+		if err := json.Unmarshal(data, &t); err != nil {
+			return fmt.Errorf("maniphest.search: invalid task: %v", err)
+		}
+		return fn(t)
+	})
+}
+
+type transactionComment struct {
+	Phid        string    `json:"phid"`
+	Version     int       `json:"version"`
+	AuthorPhid  string    `json:"authorPHID"`
+	DateCreated timestamp `json:"dateCreated"`
+	Removed     bool      `json:"removed"`
+	Content     struct {
+		Raw string `json:"raw"`
+	} `json:"content"`
+}
+
+type transaction struct {
+	Id          int                  `json:"id"`
+	Phid        string               `json:"phid"`
+	Type        *string              `json:"type"`
+	AuthorPhid  string               `json:"authorPHID"`
+	DateCreated timestamp            `json:"dateCreated"`
+	Comments    []transactionComment `json:"comments"`
+}
+
+// comment return the current version of the comment of the transaction, if
+// any and not removed
+func (t transaction) comment() (transactionComment, bool) {
+	var current transactionComment
+	found := false
+
+	for _, comment := range t.Comments {
+		if !found || comment.Version > current.Version {
+			current = comment
+			found = true
+		}
+	}
+
+	if !found || current.Removed {
+		return transactionComment{}, false
+	}
+
+	return current, true
+}
+
+// searchTransactions return the transactions of an object, the oldest first.
+// Conduit list them the newest first.
+func (c *Client) searchTransactions(objectPhid string) ([]transaction, error) {
+	params := url.Values{}
+	params.Set("objectIdentifier", objectPhid)
+
+	var result []transaction
+
+	err := c.search("transaction.search", params, func(data json.RawMessage) error {
+		var t transaction
+		if err := json.Unmarshal(data, &t); err != nil {
+			return fmt.Errorf("transaction.search: invalid transaction: %v", err)
+		}
+		result = append(result, t)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
+	}
+
+	return result, nil
+}
+
+// searchNames return the name of each object with one of the PHIDs, with a
+// *.search method returning it in the given field, like the username of
+// user.search
+func (c *Client) searchNames(method string, field string, phids []string) (map[string]string, error) {
+	names := make(map[string]string)
+
+	for start := 0; start < len(phids); start += pageSize {
+		end := start + pageSize
+		if end > len(phids) {
+			end = len(phids)
+		}
+
+		params := url.Values{}
+		for i, phid := range phids[start:end] {
+			params.Set(fmt.Sprintf("constraints[phids][%d]", i), phid)
+		}
+
+		err := c.search(method, params, func(data json.RawMessage) error {
+			// the other fields are not all strings
+			var result struct {
+				Phid   string                     `json:"phid"`
+				Fields map[string]json.RawMessage `json:"fields"`
+			}
+			if err := json.Unmarshal(data, &result); err != nil {
+				return fmt.Errorf("%s: invalid result: %v", method, err)
+			}
+
+			var name string
+			if err := json.Unmarshal(result.Fields[field], &name); err == nil && name != "" {
+				names[result.Phid] = name
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return names, nil
+}
@@ -0,0 +1,69 @@
+package phabricator
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+// UrlConfigKey is the git config key holding the url of the instance the
+// tasks are imported from, when not given to the bridge
+const UrlConfigKey = repository.PreferencePrefix + urlPreference
+
+const urlPreference = "phabricator.url"
+
+// the paths of the repositories hosted by Diffusion, the code browser of
+// Phabricator, like /source/name.git or /diffusion/CALLSIGN/
+var diffusionPathRegexp = regexp.MustCompile(`^/?(source|diffusion)/[^/]+`)
+
+// an scp-like git url, like git@example.com:path
+var scpUrlRegexp = regexp.MustCompile(`^(?:[^@/]+@)?([^:/]+):(.*)$`)
+
+// DetectInstance tell if a git remote url point to a repository hosted by a
+// Phabricator instance, and return the url of this instance
+func DetectInstance(remoteUrl string) (string, bool) {
+	var host, path string
+	scheme := "https"
+
+	if u, err := url.Parse(remoteUrl); err == nil && u.Scheme != "" && u.Host != "" {
+		host, path = u.Host, u.Path
+		switch u.Scheme {
+		case "http", "https":
+			scheme = u.Scheme
+		default:
+			// the ssh port of the instance is not the one of its website
+			host = u.Hostname()
+		}
+	} else if match := scpUrlRegexp.FindStringSubmatch(remoteUrl); match != nil {
+		host, path = match[1], match[2]
+	} else {
+		return "", false
+	}
+
+	if !diffusionPathRegexp.MatchString(path) {
+		return "", false
+	}
+
+	return scheme + "://" + host, true
+}
+
+// ConfiguredUrl return the url of the instance stored in the git config, or
+// an empty string
+func ConfiguredUrl(repo repository.Repo) (string, error) {
+	value, err := repository.GetUserPreference(repo, urlPreference)
+	if err == repository.ErrNoConfigEntry {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return value, nil
+}
+
+// SetUrl store the url of the instance to import the tasks from
+func SetUrl(repo repository.Repo, instanceUrl string) error {
+	return repository.SetUserPreference(repo, urlPreference, strings.TrimSuffix(instanceUrl, "/"))
+}
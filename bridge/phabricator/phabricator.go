@@ -0,0 +1,424 @@
+// Package phabricator import the tasks of Maniphest, the bug tracker of a
+// Phabricator instance, with its Conduit API.
+//
+// Each task become a bug and its comments become comments of the bug. The
+// status and priority of a task are mapped as close as possible:
+//
+//	open, and any unknown status         open
+//	resolved                             closed
+//	wontfix, invalid, duplicate, spite   closed, with a label of the status
+//	Unbreak Now! (100)                   critical
+//	High (80)                            high
+//	Needs Triage (90)                    normal, with a "needs triage" label
+//	Normal (50)                          normal
+//	Low (25)                             low
+//	Wishlist (0)                         low, with a "wishlist" label
+//
+// The projects of a task become labels, and the authors are translated by
+// the identity mapping of the bridges, from their username. The Remarkup of
+// the texts is converted into something close to markdown.
+//
+// The PHIDs of the tasks and comments are stored in the metadata of the
+// operations, so that importing again update the bugs instead of duplicating
+// them: the new comments are added, and the title, status, priority and
+// labels are changed to match. The description and the comments already
+// imported are never changed, even if edited upstream.
+//
+// Only the tasks modified since the previous import are asked for, unless a
+// full import is requested.
+package phabricator
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/MichaelMure/git-bug/bridge"
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/bug/operations"
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util"
+)
+
+// PhidMetadataKey is the key of the operation metadata holding the PHID of
+// the imported tasks and comments
+const PhidMetadataKey = "phabricator-phid"
+
+// the statuses closing a task, the other ones are open
+var closedStatuses = map[string]bool{
+	"resolved":  true,
+	"wontfix":   true,
+	"invalid":   true,
+	"duplicate": true,
+	"spite":     true,
+}
+
+// Result summarize an import
+type Result struct {
+	Created   int
+	Updated   int
+	Unchanged int
+}
+
+// watermarkPreference is the preference holding the last modification time
+// of the tasks imported from an instance
+func watermarkPreference(client *Client) string {
+	host := client.BaseUrl
+	if u, err := url.Parse(client.BaseUrl); err == nil && u.Host != "" {
+		host = u.Host
+	}
+	return fmt.Sprintf("phabricator.%s.modified-since", host)
+}
+
+// Import create or update a bug for each task of the instance modified since
+// the previous import, or for every task if full is set.
+func Import(repo repository.Repo, client *Client, full bool) (*Result, error) {
+	mapping, err := bridge.ReadIdentityMapping(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	imported, err := importedBugs(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	var modifiedSince int64
+	if !full {
+		value, err := repository.GetUserPreference(repo, watermarkPreference(client))
+		switch {
+		case err == repository.ErrNoConfigEntry:
+		case err != nil:
+			return nil, err
+		default:
+			modifiedSince, err = strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s%s: %v", repository.PreferencePrefix, watermarkPreference(client), err)
+			}
+		}
+	}
+
+	importer := &importer{
+		repo:     repo,
+		client:   client,
+		mapping:  mapping,
+		imported: imported,
+		users:    make(map[string]string),
+		projects: make(map[string]string),
+		result:   &Result{},
+	}
+
+	lastModified := modifiedSince
+
+	err = client.searchTasks(modifiedSince, func(t task) error {
+		if err := importer.importTask(t); err != nil {
+			return fmt.Errorf("T%d: %v", t.Id, err)
+		}
+		if int64(t.Fields.DateModified) > lastModified {
+			lastModified = int64(t.Fields.DateModified)
+		}
+		return nil
+	})
+	if err != nil {
+		return importer.result, err
+	}
+
+	// the tasks modified at this very time are asked for again the next time,
+	// in case some were modified after the search
+	if lastModified > modifiedSince {
+		err = repository.SetUserPreference(repo, watermarkPreference(client), strconv.FormatInt(lastModified, 10))
+		if err != nil {
+			return importer.result, err
+		}
+	}
+
+	return importer.result, nil
+}
+
+// importedBugs index the local bugs by the PHID of the task they are
+// imported from
+func importedBugs(repo repository.Repo) (map[string]*bug.Bug, error) {
+	imported := make(map[string]*bug.Bug)
+
+	for streamed := range bug.ReadAllLocalBugs(repo) {
+		if streamed.Err != nil {
+			return nil, streamed.Err
+		}
+
+		phid, ok := streamed.Bug.FirstOp().GetMetadata(PhidMetadataKey)
+		if ok {
+			imported[phid] = streamed.Bug
+		}
+	}
+
+	return imported, nil
+}
+
+type importer struct {
+	repo     repository.Repo
+	client   *Client
+	mapping  *bridge.IdentityMapping
+	imported map[string]*bug.Bug
+
+	// the username of the users and the name of the projects already asked
+	// for, by PHID
+	users    map[string]string
+	projects map[string]string
+
+	result *Result
+}
+
+// resolveNames ask for the names of the PHIDs not already known
+func (im *importer) resolveNames(method string, field string, cache map[string]string, phids []string) error {
+	var missing []string
+	for _, phid := range phids {
+		if _, ok := cache[phid]; !ok && phid != "" {
+			missing = append(missing, phid)
+			// asked for only once, even if not found
+			cache[phid] = ""
+		}
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	names, err := im.client.searchNames(method, field, missing)
+	if err != nil {
+		return err
+	}
+
+	for phid, name := range names {
+		cache[phid] = name
+	}
+
+	return nil
+}
+
+// person return the Person for a user PHID. A user not found, like a bot or
+// a deleted account, is named by its PHID.
+func (im *importer) person(phid string) bug.Person {
+	login := im.users[phid]
+	if login == "" {
+		login = phid
+	}
+	return im.mapping.Resolve(login)
+}
+
+func (im *importer) importTask(t task) error {
+	title, err := util.CleanupText(t.Fields.Name)
+	if err != nil {
+		return err
+	}
+	if title == "" {
+		title = fmt.Sprintf("T%d", t.Id)
+	}
+
+	description, err := util.CleanupText(remarkupToText(t.Fields.Description.Raw))
+	if err != nil {
+		return err
+	}
+
+	projects, err := t.projects()
+	if err != nil {
+		return err
+	}
+
+	transactions, err := im.client.searchTransactions(t.Phid)
+	if err != nil {
+		return err
+	}
+
+	authors := []string{t.Fields.AuthorPhid}
+	for _, transaction := range transactions {
+		if comment, ok := transaction.comment(); ok {
+			authors = append(authors, transaction.AuthorPhid, comment.AuthorPhid)
+		}
+	}
+
+	if err := im.resolveNames("user.search", "username", im.users, authors); err != nil {
+		return err
+	}
+	if err := im.resolveNames("project.search", "name", im.projects, projects); err != nil {
+		return err
+	}
+
+	author := im.person(t.Fields.AuthorPhid)
+
+	b, exist := im.imported[t.Phid]
+
+	if !exist {
+		b = bug.NewBug()
+
+		createOp := operations.NewCreateOp(author, title, description, nil)
+		createOp.UnixTime = int64(t.Fields.DateCreated)
+		createOp.SetMetadata(PhidMetadataKey, t.Phid)
+		createOp.SetMetadata(bridge.PhabricatorUrlMetadataKey, fmt.Sprintf("%s/T%d", im.client.BaseUrl, t.Id))
+		b.Append(createOp)
+	}
+
+	snap := b.Compile()
+
+	if err := im.updateBug(b, snap, t, title, projects, transactions, author); err != nil {
+		return err
+	}
+
+	if !b.HasPendingOp() {
+		im.result.Unchanged++
+		return nil
+	}
+
+	if err := b.Commit(im.repo); err != nil {
+		return err
+	}
+
+	if exist {
+		im.result.Updated++
+	} else {
+		im.result.Created++
+		im.imported[t.Phid] = b
+	}
+
+	return nil
+}
+
+// updateBug append the operations needed for the bug to match the task
+func (im *importer) updateBug(b *bug.Bug, snap bug.Snapshot, t task, title string, projects []string, transactions []transaction, author bug.Person) error {
+	importedComments := make(map[string]bool)
+	for _, op := range snap.Operations {
+		if op.OpType() != bug.AddCommentOp {
+			continue
+		}
+		if phid, ok := op.GetMetadata(PhidMetadataKey); ok {
+			importedComments[phid] = true
+		}
+	}
+
+	for _, transaction := range transactions {
+		comment, ok := transaction.comment()
+		if !ok || importedComments[transaction.Phid] {
+			continue
+		}
+
+		message, err := util.CleanupText(remarkupToText(comment.Content.Raw))
+		if err != nil {
+			return err
+		}
+		if message == "" {
+			continue
+		}
+
+		commentAuthor := comment.AuthorPhid
+		if commentAuthor == "" {
+			commentAuthor = transaction.AuthorPhid
+		}
+
+		commentOp := operations.NewAddCommentOp(im.person(commentAuthor), message, nil)
+		commentOp.UnixTime = int64(transaction.DateCreated)
+		commentOp.SetMetadata(PhidMetadataKey, transaction.Phid)
+		b.Append(commentOp)
+
+		importedComments[transaction.Phid] = true
+	}
+
+	if snap.Title != title {
+		b.Append(operations.NewSetTitleOp(author, title, snap.Title))
+	}
+
+	status, statusLabel := mapStatus(t.Fields.Status.Value, t.Fields.Status.Name)
+	priority, priorityLabel := mapPriority(t.Fields.Priority.Value, t.Fields.Priority.Name)
+
+	var labels []bug.Label
+	for _, label := range []string{statusLabel, priorityLabel} {
+		if label != "" {
+			labels = append(labels, bug.Label(label))
+		}
+	}
+	for _, project := range projects {
+		// the projects not visible are skipped
+		if label, err := bug.NormalizeLabel(im.projects[project]); err == nil {
+			labels = append(labels, label)
+		}
+	}
+
+	added, removed := diffLabels(snap.Labels, labels)
+	if len(added) > 0 || len(removed) > 0 {
+		labelOp := operations.NewLabelChangeOperation(author, added, removed)
+		labelOp.Observe(snap)
+		b.Append(labelOp)
+	}
+
+	if snap.Status != status {
+		b.Append(operations.NewSetStatusOp(author, status))
+	}
+
+	if snap.Priority != priority {
+		b.Append(operations.NewSetPriorityOp(author, priority))
+	}
+
+	return nil
+}
+
+// mapStatus return the status of a bug for a status of a task, and the label
+// telling why it's closed, if not simply resolved
+func mapStatus(value string, name string) (bug.Status, string) {
+	if !closedStatuses[value] {
+		return bug.OpenStatus, ""
+	}
+
+	if value == "resolved" {
+		return bug.ClosedStatus, ""
+	}
+
+	if name == "" {
+		name = value
+	}
+
+	return bug.ClosedStatus, strings.ToLower(name)
+}
+
+// mapPriority return the priority of a bug for a priority of a task, and the
+// label for the priorities with no equivalent
+func mapPriority(value int, name string) (bug.Priority, string) {
+	switch {
+	case value >= 100:
+		return bug.CriticalPriority, ""
+	case value == 90:
+		return bug.NormalPriority, "needs triage"
+	case value >= 80:
+		return bug.HighPriority, ""
+	case value >= 50:
+		return bug.NormalPriority, ""
+	case value > 0:
+		return bug.LowPriority, ""
+	default:
+		if name == "" {
+			name = "wishlist"
+		}
+		return bug.LowPriority, strings.ToLower(name)
+	}
+}
+
+func diffLabels(current []bug.Label, wanted []bug.Label) (added []bug.Label, removed []bug.Label) {
+	has := make(map[bug.Label]bool)
+	for _, label := range current {
+		has[label] = true
+	}
+
+	want := make(map[bug.Label]bool)
+	for _, label := range wanted {
+		if !want[label] && !has[label] {
+			added = append(added, label)
+		}
+		want[label] = true
+	}
+
+	for _, label := range current {
+		if !want[label] {
+			removed = append(removed, label)
+		}
+	}
+
+	return added, removed
+}
@@ -0,0 +1,289 @@
+package phabricator
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/MichaelMure/git-bug/bridge"
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+const testToken = "api-test"
+
+// fixtureServer answer the Conduit calls with the recorded responses of
+// testdata, named <method>[.<objectIdentifier>][.after-<cursor>].json. The
+// files are looked for in each directory in order.
+func fixtureServer(t *testing.T, modifiedStart *string, dirs ...string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+
+		if r.PostForm.Get("api.token") != testToken {
+			fmt.Fprint(w, `{"result": null, "error_code": "ERR-INVALID-AUTH", "error_info": "API token is not valid."}`)
+			return
+		}
+
+		name := strings.TrimPrefix(r.URL.Path, "/api/")
+		if object := r.PostForm.Get("objectIdentifier"); object != "" {
+			name += "." + object
+		}
+		if after := r.PostForm.Get("after"); after != "" {
+			name += ".after-" + after
+		}
+
+		if modifiedStart != nil && name == "maniphest.search" {
+			*modifiedStart = r.PostForm.Get("constraints[modifiedStart]")
+		}
+
+		for _, dir := range dirs {
+			data, err := ioutil.ReadFile(filepath.Join("testdata", dir, name+".json"))
+			if os.IsNotExist(err) {
+				continue
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			w.Write(data)
+			return
+		}
+
+		t.Errorf("No fixture for %s", name)
+		http.NotFound(w, r)
+	}))
+}
+
+func readImported(t *testing.T, repo repository.Repo) map[string]bug.Snapshot {
+	imported, err := importedBugs(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	snaps := make(map[string]bug.Snapshot)
+	for phid, b := range imported {
+		snaps[phid] = b.Compile()
+	}
+	return snaps
+}
+
+func labels(snap bug.Snapshot) string {
+	result := make([]string, len(snap.Labels))
+	for i, label := range snap.Labels {
+		result[i] = string(label)
+	}
+	return strings.Join(result, ",")
+}
+
+func TestImport(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+
+	mapping, err := bridge.ReadIdentityMapping(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mapping.Map("alice", bug.Person{Name: "Alice Liddell", Email: "alice@example.com"})
+	if err := mapping.Write(repo); err != nil {
+		t.Fatal(err)
+	}
+
+	server := fixtureServer(t, nil, "initial")
+	defer server.Close()
+
+	result, err := Import(repo, NewClient(server.URL, testToken), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *result != (Result{Created: 2}) {
+		t.Fatalf("Unexpected result %+v", result)
+	}
+
+	snaps := readImported(t, repo)
+
+	first := snaps["PHID-TASK-1"]
+	if first.Title != "Crash on startup" || first.Author.Email != "alice@example.com" {
+		t.Fatalf("Unexpected first bug %+v", first)
+	}
+	if first.CreatedAt.Unix() != 1535803200 {
+		t.Fatalf("Unexpected creation time %s", first.CreatedAt)
+	}
+	if first.Status != bug.OpenStatus || first.Priority != bug.NormalPriority || labels(first) != "Frontend" {
+		t.Fatalf("Unexpected status, priority or labels %s %s %v", first.Status, first.Priority, first.Labels)
+	}
+
+	// the removed comment is skipped, the edited one is at its last version
+	if len(first.Comments) != 3 {
+		t.Fatalf("Expected 3 comments, got %d", len(first.Comments))
+	}
+	if first.Comments[1].Message != "It happens since the last update." || first.Comments[1].Author.Name != "Alice Liddell" {
+		t.Fatalf("Unexpected comment %+v", first.Comments[1])
+	}
+	if first.Comments[2].Message != "Fixed in `master`, see [D1](https://example.com/D1)." || first.Comments[2].Author.Name != "bob" {
+		t.Fatalf("Unexpected comment %+v", first.Comments[2])
+	}
+
+	url, ok := bridge.BugUrl(&first)
+	if !ok || url != server.URL+"/T1" {
+		t.Fatalf("Unexpected url %s", url)
+	}
+
+	second := snaps["PHID-TASK-2"]
+	if second.Status != bug.ClosedStatus || second.Priority != bug.LowPriority {
+		t.Fatalf("Unexpected status or priority %s %s", second.Status, second.Priority)
+	}
+	if labels(second) != "wishlist,wontfix" || len(second.Comments) != 1 {
+		t.Fatalf("Unexpected second bug %+v", second)
+	}
+
+	watermark, err := repository.GetUserPreference(repo, watermarkPreference(NewClient(server.URL, testToken)))
+	if err != nil || watermark != "1535950000" {
+		t.Fatalf("Unexpected watermark %s %v", watermark, err)
+	}
+}
+
+func TestImportIdempotent(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+
+	server := fixtureServer(t, nil, "initial")
+	defer server.Close()
+
+	client := NewClient(server.URL, testToken)
+
+	if _, err := Import(repo, client, false); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := Import(repo, client, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *result != (Result{Unchanged: 2}) {
+		t.Fatalf("Importing again should change nothing, got %+v", result)
+	}
+}
+
+func TestImportIncremental(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+
+	initial := fixtureServer(t, nil, "initial")
+	defer initial.Close()
+
+	if _, err := Import(repo, NewClient(initial.URL, testToken), false); err != nil {
+		t.Fatal(err)
+	}
+
+	// the watermark is per host, the update server need to have the same
+	var modifiedStart string
+	update := fixtureServer(t, &modifiedStart, "update", "initial")
+	defer update.Close()
+
+	client := NewClient(update.URL, testToken)
+	if err := repository.SetUserPreference(repo, watermarkPreference(client), "1535950000"); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := Import(repo, client, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if modifiedStart != "1535950000" {
+		t.Fatalf("Expected the tasks modified since the last import, got %q", modifiedStart)
+	}
+	if *result != (Result{Updated: 1}) {
+		t.Fatalf("Unexpected result %+v", result)
+	}
+
+	first := readImported(t, repo)["PHID-TASK-1"]
+	if first.Title != "Crash on startup with --verbose" || first.Status != bug.ClosedStatus || first.Priority != bug.HighPriority {
+		t.Fatalf("Unexpected updated bug %+v", first)
+	}
+	if labels(first) != "" || len(first.Comments) != 4 || first.Comments[3].Message != "Confirmed, thanks!" {
+		t.Fatalf("Unexpected labels or comments %v %+v", first.Labels, first.Comments)
+	}
+	// the description is not changed
+	if !strings.HasPrefix(first.Comments[0].Message, "# Steps") {
+		t.Fatalf("Unexpected description %q", first.Comments[0].Message)
+	}
+}
+
+func TestImportRepeatedCursor(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+
+	server := fixtureServer(t, nil, "loop")
+	defer server.Close()
+
+	_, err := Import(repo, NewClient(server.URL, testToken), false)
+	if err == nil || !strings.Contains(err.Error(), "the cursor 7 is given again") {
+		t.Fatalf("Expected a repeated cursor error, got %v", err)
+	}
+}
+
+func TestImportConduitError(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+
+	server := fixtureServer(t, nil, "initial")
+	defer server.Close()
+
+	_, err := Import(repo, NewClient(server.URL, "api-wrong"), false)
+
+	conduitErr, ok := err.(ConduitError)
+	if !ok || conduitErr.Code != "ERR-INVALID-AUTH" || conduitErr.Method != "maniphest.search" {
+		t.Fatalf("Expected a Conduit error, got %v", err)
+	}
+
+	if _, err := repository.GetUserPreference(repo, watermarkPreference(NewClient(server.URL, "api-wrong"))); err != repository.ErrNoConfigEntry {
+		t.Fatal("The watermark should not be set by a failed import")
+	}
+}
+
+func TestRemarkupToText(t *testing.T) {
+	cases := []struct {
+		remarkup string
+		text     string
+	}{
+		{"= Title =", "# Title"},
+		{"== Section", "## Section"},
+		{"# one\n# two\n## nested", "1. one\n1. two\n   1. nested"},
+		{"some //italic// and **bold**", "some *italic* and **bold**"},
+		{"see https://example.com//path//", "see https://example.com//path//"},
+		{"##mono## and `//code//`", "`mono` and `//code//`"},
+		{"[[ https://example.com | example ]] [[https://example.com]]", "[example](https://example.com) <https://example.com>"},
+		{"!!warning!!", "**warning**"},
+		{"```\n= not a title =\n```", "```\n= not a title =\n```"},
+		{"  # indented code", "  # indented code"},
+	}
+
+	for _, c := range cases {
+		if text := remarkupToText(c.remarkup); text != c.text {
+			t.Errorf("remarkupToText(%q) = %q, expected %q", c.remarkup, text, c.text)
+		}
+	}
+}
+
+func TestDetectInstance(t *testing.T) {
+	cases := []struct {
+		remote   string
+		instance string
+	}{
+		{"https://phab.example.com/source/project.git", "https://phab.example.com"},
+		{"http://phab.example.com:8080/diffusion/P/", "http://phab.example.com:8080"},
+		{"ssh://git@phab.example.com:2222/diffusion/P/project.git", "https://phab.example.com"},
+		{"git@phab.example.com:/source/project.git", "https://phab.example.com"},
+		{"https://github.com/MichaelMure/git-bug.git", ""},
+		{"git@github.com:MichaelMure/git-bug.git", ""},
+		{"/srv/git/source/project.git", ""},
+	}
+
+	for _, c := range cases {
+		instance, ok := DetectInstance(c.remote)
+		if instance != c.instance || ok != (c.instance != "") {
+			t.Fatalf("%s: expected %q, got %q", c.remote, c.instance, instance)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package phabricator
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// = Header =, with the same number of = on both sides, the closing ones
+	// being optional
+	remarkupHeader = regexp.MustCompile(`^(={1,6})\s*(.*?)\s*=*\s*$`)
+	// # item, or ## item for a nested one
+	remarkupOrderedItem = regexp.MustCompile(`^(#+)\s+(.*)$`)
+	// [[ url | text ]] or [[ url ]]
+	remarkupLink = regexp.MustCompile(`\[\[\s*([^|\]]+?)\s*(?:\|\s*([^\]]*?)\s*)?\]\]`)
+	// //italic//
+	remarkupItalic = regexp.MustCompile(`//([^/\s](?:[^/]*[^/\s])?)//`)
+	// ##monospaced##
+	remarkupMonospaced = regexp.MustCompile(`##([^#\s](?:[^#]*[^#\s])?)##`)
+	// !!highlighted!!
+	remarkupHighlight = regexp.MustCompile(`!!([^!\s](?:[^!]*[^!\s])?)!!`)
+	// the urls, left as they are
+	remarkupUrl = regexp.MustCompile(`[a-zA-Z][a-zA-Z0-9+.-]*://[^\s<>()\[\]]+`)
+)
+
+// remarkupToText convert the Remarkup of Phabricator into a plain text close
+// to markdown. Only the common syntax is converted: the headers, the ordered
+// lists, the links and the inline styles. The code blocks are kept as they
+// are.
+func remarkupToText(remarkup string) string {
+	lines := strings.Split(strings.Replace(remarkup, "\r\n", "\n", -1), "\n")
+	inCode := false
+
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			// a one line block, like ```code```, doesn't open a block
+			trimmed := strings.TrimSpace(line)
+			if !(len(trimmed) > 6 && strings.HasSuffix(trimmed, "```")) {
+				inCode = !inCode
+			}
+			continue
+		}
+
+		// the code blocks, fenced or indented by two spaces
+		if inCode || strings.HasPrefix(line, "  ") {
+			continue
+		}
+
+		if m := remarkupHeader.FindStringSubmatch(line); m != nil && m[2] != "" {
+			lines[i] = strings.Repeat("#", len(m[1])) + " " + convertInline(m[2])
+			continue
+		}
+
+		if m := remarkupOrderedItem.FindStringSubmatch(line); m != nil {
+			indent := strings.Repeat("   ", len(m[1])-1)
+			lines[i] = indent + "1. " + convertInline(m[2])
+			continue
+		}
+
+		lines[i] = convertInline(line)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// convertInline convert the inline styles and links of a line, out of the
+// `monospaced` parts
+func convertInline(line string) string {
+	parts := strings.Split(line, "`")
+
+	for i := range parts {
+		// the odd parts are between backquotes
+		if i%2 == 1 {
+			continue
+		}
+
+		part := parts[i]
+		part = remarkupLink.ReplaceAllStringFunc(part, func(match string) string {
+			m := remarkupLink.FindStringSubmatch(match)
+			if m[2] == "" {
+				return "<" + m[1] + ">"
+			}
+			return "[" + m[2] + "](" + m[1] + ")"
+		})
+		parts[i] = convertStyles(part)
+	}
+
+	return strings.Join(parts, "`")
+}
+
+// convertStyles convert the inline styles of a text, out of the urls
+func convertStyles(text string) string {
+	var result strings.Builder
+	last := 0
+
+	convert := func(s string) string {
+		s = remarkupItalic.ReplaceAllString(s, "*$1*")
+		s = remarkupMonospaced.ReplaceAllString(s, "`$1`")
+		return remarkupHighlight.ReplaceAllString(s, "**$1**")
+	}
+
+	for _, loc := range remarkupUrl.FindAllStringIndex(text, -1) {
+		result.WriteString(convert(text[last:loc[0]]))
+		result.WriteString(text[loc[0]:loc[1]])
+		last = loc[1]
+	}
+	result.WriteString(convert(text[last:]))
+
+	return result.String()
+}
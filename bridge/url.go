@@ -0,0 +1,48 @@
+package bridge
+
+import (
+	"net/url"
+
+	"github.com/MichaelMure/git-bug/bug"
+)
+
+// The keys of the metadata of the create operation holding the url of a bug
+// imported from an upstream bug tracker
+const (
+	GithubUrlMetadataKey      = "github-url"
+	GitlabUrlMetadataKey      = "gitlab-url"
+	PhabricatorUrlMetadataKey = "phabricator-url"
+)
+
+var urlMetadataKeys = []string{
+	GithubUrlMetadataKey,
+	GitlabUrlMetadataKey,
+	PhabricatorUrlMetadataKey,
+}
+
+// BugUrl return the url of a bug on the upstream bug tracker it was imported
+// from, if any. Only the http and https urls are returned, as they are meant
+// to be opened in a browser.
+func BugUrl(snap *bug.Snapshot) (string, bool) {
+	if len(snap.Operations) == 0 {
+		return "", false
+	}
+
+	create := snap.Operations[0]
+
+	for _, key := range urlMetadataKeys {
+		value, ok := create.GetMetadata(key)
+		if !ok {
+			continue
+		}
+
+		u, err := url.Parse(value)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			continue
+		}
+
+		return u.String(), true
+	}
+
+	return "", false
+}
@@ -0,0 +1,41 @@
+package bridge
+
+import (
+	"testing"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/bug/operations"
+)
+
+func TestBugUrl(t *testing.T) {
+	author := bug.Person{Name: "Jane Doe", Email: "jane@example.com"}
+
+	cases := []struct {
+		metadata map[string]string
+		url      string
+	}{
+		{nil, ""},
+		{map[string]string{GithubUrlMetadataKey: "https://github.com/a/b/issues/1"}, "https://github.com/a/b/issues/1"},
+		{map[string]string{GitlabUrlMetadataKey: "https://gitlab.com/a/b/issues/2"}, "https://gitlab.com/a/b/issues/2"},
+		{map[string]string{GithubUrlMetadataKey: "javascript:alert(1)"}, ""},
+		{map[string]string{GithubUrlMetadataKey: "file:///etc/passwd"}, ""},
+	}
+
+	for _, c := range cases {
+		create := operations.NewCreateOp(author, "title", "message", nil)
+		for key, value := range c.metadata {
+			create.SetMetadata(key, value)
+		}
+
+		snap := bug.Snapshot{Operations: []bug.Operation{create}}
+
+		url, ok := BugUrl(&snap)
+		if url != c.url || ok != (c.url != "") {
+			t.Fatalf("%v: expected %q, got %q", c.metadata, c.url, url)
+		}
+	}
+
+	if _, ok := BugUrl(&bug.Snapshot{}); ok {
+		t.Fatal("a bug without operation has no url")
+	}
+}
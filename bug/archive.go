@@ -0,0 +1,130 @@
+package bug
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util"
+)
+
+// The old comments of a bug can be archived, to keep its snapshot light when
+// it has thousands of them, like with the bots. The archived comments are
+// stored together in a git blob, out of the snapshot, and read only when
+// asked with LoadArchivedComments. Their operations are still in the history
+// of the bug.
+//
+// The description of the bug, its first comment, is never archived.
+
+// ArchivedComments return the comments that would be archived with the given
+// cutoff: the ones created before it, except the description, with the hashes
+// identifying them
+func (snap Snapshot) ArchivedComments(before int64) ([]Comment, []util.Hash, error) {
+	hashes, err := snap.CommentHashes()
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(hashes) != len(snap.Comments) {
+		return nil, nil, fmt.Errorf("expected %d comment hashes, got %d", len(snap.Comments), len(hashes))
+	}
+
+	var archived []Comment
+	var archivedHashes []util.Hash
+
+	for i, comment := range snap.Comments {
+		if i > 0 && comment.UnixTime < before {
+			archived = append(archived, comment)
+			archivedHashes = append(archivedHashes, hashes[i])
+		}
+	}
+
+	return archived, archivedHashes, nil
+}
+
+// ArchiveComments remove from the snapshot the comments with the given
+// hashes, stored in the given blob, and return how many were archived. Only
+// the comments known when the archive was made are archived, not the ones
+// merged later with an older time, which are in no archive. The archived
+// comments are left out of the hashes as well, see CommentHashes.
+func (snap *Snapshot) ArchiveComments(archived []util.Hash, archive util.Hash) int {
+	hashes, err := snap.CommentHashes()
+	if err != nil || len(hashes) != len(snap.Comments) {
+		return 0
+	}
+
+	selected := make(map[util.Hash]bool, len(archived))
+	for _, hash := range archived {
+		selected[hash] = true
+	}
+
+	// a new slice, as the previous one can be shared with other snapshots
+	var kept []Comment
+	count := 0
+
+	for i, comment := range snap.Comments {
+		if i == 0 || !selected[hashes[i]] {
+			kept = append(kept, comment)
+			continue
+		}
+
+		if snap.archived == nil {
+			snap.archived = make(map[util.Hash]bool)
+		}
+		snap.archived[hashes[i]] = true
+		count++
+	}
+
+	if count == 0 {
+		return 0
+	}
+
+	snap.Comments = kept
+	snap.Archives = append(snap.Archives, archive)
+	snap.ArchivedCount += count
+
+	return count
+}
+
+// IsArchivedComment tell if the comment created by the operation with the
+// given hash is archived
+func (snap Snapshot) IsArchivedComment(hash util.Hash) bool {
+	return snap.archived[hash]
+}
+
+// StoreArchive store the archived comments in a git blob
+func StoreArchive(repo repository.Repo, comments []Comment) (util.Hash, error) {
+	// the messages stored in their own blob are archived along the comments
+	loaded := make([]Comment, len(comments))
+	for i, comment := range comments {
+		loaded[i] = comment.withLoadedMessage()
+	}
+
+	data, err := json.Marshal(loaded)
+	if err != nil {
+		return "", err
+	}
+
+	return repo.StoreData(data)
+}
+
+// LoadArchivedComments read the archived comments of the bug from git, oldest
+// archive first
+func (snap Snapshot) LoadArchivedComments(repo repository.Repo) ([]Comment, error) {
+	var result []Comment
+
+	for _, archive := range snap.Archives {
+		data, err := repo.ReadData(archive)
+		if err != nil {
+			return nil, err
+		}
+
+		var comments []Comment
+		if err := json.Unmarshal(data, &comments); err != nil {
+			return nil, fmt.Errorf("invalid comment archive %s: %s", archive, err)
+		}
+
+		result = append(result, comments...)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,94 @@
+package bug
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util"
+	"github.com/dustin/go-humanize"
+)
+
+// contentSniffLength is the number of bytes read from a file to detect its
+// content type, the most http.DetectContentType consider
+const contentSniffLength = 512
+
+// the extension of the attached files, by detected content type
+var fileExtensions = map[string]string{
+	"image/png":                 ".png",
+	"image/jpeg":                ".jpg",
+	"image/gif":                 ".gif",
+	"image/webp":                ".webp",
+	"image/bmp":                 ".bmp",
+	"video/mp4":                 ".mp4",
+	"video/webm":                ".webm",
+	"application/pdf":           ".pdf",
+	"application/zip":           ".zip",
+	"text/plain; charset=utf-8": ".txt",
+}
+
+// FileExtension return the extension of a file of the given content type,
+// or an empty string if it's unknown
+func FileExtension(contentType string) string {
+	return fileExtensions[contentType]
+}
+
+// Attachment describe a file attached to a comment
+type Attachment struct {
+	Hash util.Hash
+	// the size of the file in bytes
+	Size int64
+	// the content type detected from the first bytes of the file
+	ContentType string
+}
+
+// ReadAttachment read the size and the content type of an attached file,
+// without reading more than its first bytes
+func ReadAttachment(repo repository.Repo, hash util.Hash) (Attachment, error) {
+	size, err := repo.DataSize(hash)
+	if err != nil {
+		return Attachment{}, err
+	}
+
+	reader, err := repo.OpenData(hash)
+	if err != nil {
+		return Attachment{}, err
+	}
+	defer reader.Close()
+
+	head := make([]byte, contentSniffLength)
+	n, err := io.ReadFull(reader, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return Attachment{}, err
+	}
+
+	return Attachment{
+		Hash:        hash,
+		Size:        size,
+		ContentType: http.DetectContentType(head[:n]),
+	}, nil
+}
+
+// Filename return the name of the file, made of its hash and the extension
+// of its content type, as the original name is not recorded
+func (a Attachment) Filename() string {
+	return string(a.Hash) + FileExtension(a.ContentType)
+}
+
+// HumanSize return the size of the file, like "12 kB"
+func (a Attachment) HumanSize() string {
+	return humanize.Bytes(uint64(a.Size))
+}
+
+// Attachments return the files attached to the comments, in order. They are
+// numbered from 1 in this order by git bug show and git bug attachment get.
+// The files of the deleted comments are not included.
+func (snap Snapshot) Attachments() []util.Hash {
+	var result []util.Hash
+
+	for _, comment := range snap.Comments {
+		result = append(result, comment.Files...)
+	}
+
+	return result
+}
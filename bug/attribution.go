@@ -0,0 +1,104 @@
+package bug
+
+import (
+	"time"
+
+	"github.com/MichaelMure/git-bug/util"
+)
+
+// Attribution is the operation that last set a field of a bug, like git
+// blame does for the lines of a file
+type Attribution struct {
+	// the hash of the operation, see HashOperation
+	OpId   util.Hash
+	Author Person
+	Time   time.Time
+}
+
+func newAttribution(op Operation) Attribution {
+	// a hash can't be computed only if the operation can't be serialized,
+	// the attribution is still useful without it
+	hash, _ := HashOperation(op)
+
+	return Attribution{
+		OpId:   hash,
+		Author: op.GetAuthor(),
+		Time:   op.Time(),
+	}
+}
+
+// Attribution map each current field of the bug to the operation that last
+// set it. The fields are named "title", "status", "priority", "label:<label>"
+// for each label and "field:<key>" for each custom field. The creation of the
+// bug set the fields it leaves at their default value.
+//
+// The operations are applied again to find out which one changed what, so
+// it's not meant to be called for every bug. The decoded snapshots have no
+// operation, and no attribution.
+func (snap Snapshot) Attribution() map[string]Attribution {
+	result := make(map[string]Attribution)
+
+	current := Snapshot{
+		id:       snap.id,
+		Status:   OpenStatus,
+		Priority: DefaultPriority,
+	}
+
+	var title string
+	var status Status
+	var priority Priority
+	labels := make(map[Label]bool)
+	fields := make(map[string]string)
+
+	for i, op := range snap.Operations {
+		current = op.Apply(current)
+
+		attribution := newAttribution(op)
+		first := i == 0
+
+		if first || current.Title != title {
+			result["title"] = attribution
+			title = current.Title
+		}
+		if first || current.Status != status {
+			result["status"] = attribution
+			status = current.Status
+		}
+		if first || current.Priority != priority {
+			result["priority"] = attribution
+			priority = current.Priority
+		}
+
+		present := make(map[Label]bool, len(current.Labels))
+		for _, label := range current.Labels {
+			present[label] = true
+			if !labels[label] {
+				result["label:"+string(label)] = attribution
+				labels[label] = true
+			}
+		}
+		for label := range labels {
+			if !present[label] {
+				delete(result, "label:"+string(label))
+				delete(labels, label)
+			}
+		}
+
+		// the custom fields are edited in place, their values are compared
+		// to a copy
+		for key, value := range current.CustomFields {
+			if previous, ok := fields[key]; !ok || previous != value {
+				result["field:"+key] = attribution
+				fields[key] = value
+			}
+		}
+		for key := range fields {
+			if _, ok := current.CustomFields[key]; !ok {
+				delete(result, "field:"+key)
+				delete(fields, key)
+			}
+		}
+	}
+
+	return result
+}
@@ -1,20 +1,21 @@
 package bug
 
 import (
+	"crypto/sha1"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/MichaelMure/git-bug/repository"
 	"github.com/MichaelMure/git-bug/util"
 )
 
-const bugsRefPattern = "refs/bugs/"
-const bugsRemoteRefPattern = "refs/remotes/%s/bugs/"
-
 const opsEntryName = "ops"
 const rootEntryName = "root"
 const mediaEntryName = "media"
+const mergeEntryName = "merge"
 
 const createClockEntryPrefix = "create-clock-"
 const createClockEntryPattern = "create-clock-%d"
@@ -24,6 +25,18 @@ const editClockEntryPattern = "edit-clock-%d"
 const idLength = 40
 const humanIdLength = 7
 
+// ErrNoMatchingBug is returned when no bug match the given id prefix
+var ErrNoMatchingBug = errors.New("No matching bug found.")
+
+// ErrBugNotStored is returned when the id of a bug is requested before its
+// first commit, as the id is derived from the create time given by this
+// commit
+var ErrBugNotStored = errors.New("the bug is not stored yet")
+
+// ErrEmptyCommit is returned by CommitWithOptions when the pending operations
+// are discarded as they leave the bug unchanged, see CommitOptions.SkipEmpty
+var ErrEmptyCommit = errors.New("the pending operations don't change the bug")
+
 // Bug hold the data of a bug thread, organized in a way close to
 // how it will be persisted inside Git. This is the data structure
 // used to merge two different version of the same Bug.
@@ -41,6 +54,15 @@ type Bug struct {
 	lastCommit util.Hash
 	rootPack   util.Hash
 
+	// the history hold merge commits, see MergeStrategyMerge
+	nonLinear bool
+
+	// the bug is stored as a local draft, see SaveDraft
+	draft bool
+
+	// the blob listing the redacted operations, if any, see Redact
+	redactions util.Hash
+
 	// all the committed operations
 	packs []OperationPack
 
@@ -58,10 +80,18 @@ func NewBug() *Bug {
 
 // FindLocalBug find an existing Bug matching a prefix
 func FindLocalBug(repo repository.Repo, prefix string) (*Bug, error) {
-	ids, err := repo.ListIds(bugsRefPattern)
+	b, _, err := FindBugWithSuggestions(repo, prefix)
+	return b, err
+}
+
+// FindBugWithSuggestions find an existing Bug matching a prefix. If no bug
+// match, the returned slice hold the ids of the bugs with a similar prefix.
+// If several bugs match, it hold the ids of the matching bugs.
+func FindBugWithSuggestions(repo repository.Repo, prefix string) (*Bug, []string, error) {
+	ids, err := repo.ListIds(localRefPrefix(repo))
 
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// preallocate but empty
@@ -74,39 +104,40 @@ func FindLocalBug(repo repository.Repo, prefix string) (*Bug, error) {
 	}
 
 	if len(matching) == 0 {
-		return nil, errors.New("No matching bug found.")
+		return nil, suggestIds(ids, prefix), ErrNoMatchingBug
 	}
 
 	if len(matching) > 1 {
-		return nil, fmt.Errorf("Multiple matching bug found:\n%s", strings.Join(matching, "\n"))
+		return nil, matching, fmt.Errorf("Multiple matching bug found:\n%s", strings.Join(matching, "\n"))
 	}
 
-	return ReadLocalBug(repo, matching[0])
+	b, err := ReadLocalBug(repo, matching[0])
+	return b, nil, err
 }
 
 // ReadLocalBug will read a local bug from its hash
 func ReadLocalBug(repo repository.Repo, id string) (*Bug, error) {
-	ref := bugsRefPattern + id
+	ref := localRefPrefix(repo) + id
 	return readBug(repo, ref)
 }
 
 // ReadRemoteBug will read a remote bug from its hash
 func ReadRemoteBug(repo repository.Repo, remote string, id string) (*Bug, error) {
-	ref := fmt.Sprintf(bugsRemoteRefPattern, remote) + id
+	ref := remoteRefPrefix(repo, remote) + id
 	return readBug(repo, ref)
 }
 
 // readBug will read and parse a Bug from git
 func readBug(repo repository.Repo, ref string) (*Bug, error) {
-	hashes, err := repo.ListCommits(ref)
-
-	if err != nil {
-		return nil, err
-	}
-
 	refSplitted := strings.Split(ref, "/")
 	id := refSplitted[len(refSplitted)-1]
 
+	return readBugRevision(repo, id, ref)
+}
+
+// readBugRevision read the bug with the given id at a revision, a ref or a
+// commit hash
+func readBugRevision(repo repository.Repo, id string, revision string) (*Bug, error) {
 	if len(id) != idLength {
 		return nil, fmt.Errorf("Invalid ref length")
 	}
@@ -115,20 +146,21 @@ func readBug(repo repository.Repo, ref string) (*Bug, error) {
 		id: id,
 	}
 
-	// Load each OperationPack
-	for _, hash := range hashes {
+	// Load each OperationPack, oldest first
+	err := repo.WalkCommits(revision, func(hash util.Hash) error {
 		entries, err := repo.ListEntries(hash)
 
 		bug.lastCommit = hash
 
 		if err != nil {
-			return nil, err
+			return readError(repo, id, hash, err)
 		}
 
 		var opsEntry repository.TreeEntry
 		opsFound := false
 		var rootEntry repository.TreeEntry
 		rootFound := false
+		mergeFound := false
 		var createTime uint64
 		var editTime uint64
 
@@ -142,34 +174,53 @@ func readBug(repo repository.Repo, ref string) (*Bug, error) {
 				rootEntry = entry
 				rootFound = true
 			}
+			if entry.Name == mergeEntryName {
+				mergeFound = true
+			}
+			if entry.Name == redactionsEntryName {
+				bug.redactions = entry.Hash
+			}
 			if strings.HasPrefix(entry.Name, createClockEntryPrefix) {
 				n, err := fmt.Sscanf(string(entry.Name), createClockEntryPattern, &createTime)
 				if err != nil {
-					return nil, err
+					return err
 				}
 				if n != 1 {
-					return nil, fmt.Errorf("could not parse create time lamport value")
+					return fmt.Errorf("could not parse create time lamport value")
 				}
 			}
 			if strings.HasPrefix(entry.Name, editClockEntryPrefix) {
 				n, err := fmt.Sscanf(string(entry.Name), editClockEntryPattern, &editTime)
 				if err != nil {
-					return nil, err
+					return err
 				}
 				if n != 1 {
-					return nil, fmt.Errorf("could not parse edit time lamport value")
+					return fmt.Errorf("could not parse edit time lamport value")
 				}
 			}
 		}
 
-		if !opsFound {
-			return nil, errors.New("Invalid tree, missing the ops entry")
-		}
 		if !rootFound {
-			return nil, errors.New("Invalid tree, missing the root entry")
+			return errors.New("Invalid tree, missing the root entry")
+		}
+
+		// a merge commit only join two histories, it has no operation
+		if mergeFound && bug.rootPack != "" {
+			bug.nonLinear = true
+			bug.editTime = util.LamportTime(editTime)
+			return repo.EditWitness(bug.editTime)
+		}
+
+		if !opsFound {
+			return errors.New("Invalid tree, missing the ops entry")
 		}
 
 		if bug.rootPack == "" {
+			err := checkFirstCommit(repo, id, hash, opsEntry.Hash, rootEntry.Hash)
+			if err != nil {
+				return err
+			}
+
 			bug.rootPack = rootEntry.Hash
 			bug.createTime = util.LamportTime(createTime)
 		}
@@ -178,32 +229,46 @@ func readBug(repo repository.Repo, ref string) (*Bug, error) {
 
 		// Update the clocks
 		if err := repo.CreateWitness(bug.createTime); err != nil {
-			return nil, err
+			return err
 		}
 		if err := repo.EditWitness(bug.editTime); err != nil {
-			return nil, err
+			return err
 		}
 
 		data, err := repo.ReadData(opsEntry.Hash)
 
 		if err != nil {
-			return nil, err
+			return readError(repo, id, opsEntry.Hash, err)
 		}
 
 		op, err := ParseOperationPack(data)
 
 		if err != nil {
-			return nil, err
+			return err
 		}
 
-		// tag the pack with the commit hash
+		// the large messages are only read when needed
+		op.attachMessageBlobs(repo)
+
+		// tag the pack with the commit hash and its logical time
 		op.commitHash = hash
+		op.editTime = bug.editTime
 
 		if err != nil {
-			return nil, err
+			return err
 		}
 
 		bug.packs = append(bug.packs, *op)
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := bug.verifyRedactionClaims(repo); err != nil {
+		return nil, err
 	}
 
 	return &bug, nil
@@ -216,12 +281,12 @@ type StreamedBug struct {
 
 // ReadAllLocalBugs read and parse all local bugs
 func ReadAllLocalBugs(repo repository.Repo) <-chan StreamedBug {
-	return readAllBugs(repo, bugsRefPattern)
+	return readAllBugs(repo, localRefPrefix(repo))
 }
 
 // ReadAllRemoteBugs read and parse all remote bugs for a given remote
 func ReadAllRemoteBugs(repo repository.Repo, remote string) <-chan StreamedBug {
-	refPrefix := fmt.Sprintf(bugsRemoteRefPattern, remote)
+	refPrefix := remoteRefPrefix(repo, remote)
 	return readAllBugs(repo, refPrefix)
 }
 
@@ -232,7 +297,13 @@ func readAllBugs(repo repository.Repo, refPrefix string) <-chan StreamedBug {
 	go func() {
 		defer close(out)
 
-		refs, err := repo.ListRefs(refPrefix)
+		// the refs are listed first, so that a consumer that stop reading
+		// doesn't leave git for-each-ref blocked
+		var refs []string
+		err := repo.ForEachRef(refPrefix, func(ref string, hash util.Hash) error {
+			refs = append(refs, ref)
+			return nil
+		})
 		if err != nil {
 			out <- StreamedBug{Err: err}
 			return
@@ -240,7 +311,6 @@ func readAllBugs(repo repository.Repo, refPrefix string) <-chan StreamedBug {
 
 		for _, ref := range refs {
 			b, err := readBug(repo, ref)
-
 			if err != nil {
 				out <- StreamedBug{Err: err}
 				return
@@ -255,7 +325,23 @@ func readAllBugs(repo repository.Repo, refPrefix string) <-chan StreamedBug {
 
 // ListLocalIds list all the available local bug ids
 func ListLocalIds(repo repository.Repo) ([]string, error) {
-	return repo.ListIds(bugsRefPattern)
+	return repo.ListIds(localRefPrefix(repo))
+}
+
+// ListLocalHeads return the last commit of each local bug, by bug id, to
+// tell which bugs changed since a previous listing
+func ListLocalHeads(repo repository.Repo) (map[string]util.Hash, error) {
+	heads := make(map[string]util.Hash)
+
+	err := repo.ForEachRef(localRefPrefix(repo), func(ref string, hash util.Hash) error {
+		heads[strings.TrimPrefix(ref, localRefPrefix(repo))] = hash
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return heads, nil
 }
 
 // IsValid check if the Bug data is valid
@@ -311,20 +397,103 @@ func (bug *Bug) HasPendingOp() bool {
 	return !bug.staging.IsEmpty()
 }
 
-// Commit write the staging area in Git and move the operations to the packs
+// CommitOptions tune how CommitWithOptions write the staging area
+type CommitOptions struct {
+	// SkipEmpty discard the pending operations instead of committing them
+	// when they net to no change of the bug, as adding then removing the same
+	// label. ErrEmptyCommit is returned then.
+	SkipEmpty bool
+}
+
+// Commit write the staging area in Git and move the operations to the packs.
+// The bug reference is only updated once everything else is written, so a
+// failure leave both the repository and the bug unchanged.
 func (bug *Bug) Commit(repo repository.Repo) error {
+	return bug.CommitWithOptions(repo, CommitOptions{})
+}
+
+// CommitWithOptions is Commit, with the given options
+func (bug *Bug) CommitWithOptions(repo repository.Repo, opts CommitOptions) error {
 	if bug.staging.IsEmpty() {
 		return fmt.Errorf("can't commit a bug with no pending operation")
 	}
 
+	// a new bug always need its first commit, to be created
+	if opts.SkipEmpty && bug.lastCommit != "" && bug.stagingIsNoOp() {
+		bug.staging = OperationPack{}
+		return ErrEmptyCommit
+	}
+
+	// The objects are all written before the ref is updated, so that an
+	// interruption leave at worst unreferenced objects. The interruption is
+	// deferred meanwhile, so that the commit finish once started.
+	return util.Critical(func() error {
+		tx := repo.Begin()
+
+		err := bug.commit(repo, tx, bug.lastCommit)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		return nil
+	})
+}
+
+// stagingIsNoOp tell if the bug compile the same with or without the staging
+// area
+func (bug *Bug) stagingIsNoOp() bool {
+	after := bug.Compile()
+
+	staging := bug.staging
+	bug.staging = OperationPack{}
+	before := bug.Compile()
+	bug.staging = staging
+
+	return before.sameState(after)
+}
+
+// expectedRef return the expected old value of the reference of a bug whose
+// last commit is the given one, see repository.RefUpdate
+func expectedRef(lastCommit util.Hash) util.Hash {
+	if lastCommit == "" {
+		return repository.NoRef
+	}
+	return lastCommit
+}
+
+// commit store the staged operations on top of the last commit of the bug,
+// the reference of the bug being expected to point to old
+func (bug *Bug) commit(repo repository.Repo, tx repository.Transaction, old util.Hash) error {
+	if err := checkOperationCount(len(bug.staging.Operations), limits); err != nil {
+		return err
+	}
+
+	for _, op := range bug.staging.Operations {
+		if err := op.Validate(); err != nil {
+			return fmt.Errorf("invalid operation: %s", err)
+		}
+	}
+
+	compress, err := readCompressConfig(repo)
+	if err != nil {
+		return err
+	}
+
+	// Store the large messages in their own blob, out of the OperationPack
+	if err := bug.staging.storeLargeMessages(repo); err != nil {
+		return err
+	}
+
 	// Write the Ops as a Git blob containing the serialized array
-	hash, err := bug.staging.Write(repo)
+	hash, err := bug.staging.Write(repo, compress)
 	if err != nil {
 		return err
 	}
 
-	if bug.rootPack == "" {
-		bug.rootPack = hash
+	rootPack := bug.rootPack
+	if rootPack == "" {
+		rootPack = hash
 	}
 
 	// Make a Git tree referencing this blob
@@ -332,7 +501,7 @@ func (bug *Bug) Commit(repo repository.Repo) error {
 		// the last pack of ops
 		{ObjectType: repository.Blob, Hash: hash, Name: opsEntryName},
 		// always the first pack of ops (might be the same)
-		{ObjectType: repository.Blob, Hash: bug.rootPack, Name: rootEntryName},
+		{ObjectType: repository.Blob, Hash: rootPack, Name: rootEntryName},
 	}
 
 	// Reference, if any, all the files required by the ops
@@ -351,6 +520,19 @@ func (bug *Bug) Commit(repo repository.Repo) error {
 		})
 	}
 
+	messageTree := makeMessageTree(bug.staging)
+	if len(messageTree) > 0 {
+		messageTreeHash, err := repo.StoreTree(messageTree)
+		if err != nil {
+			return err
+		}
+		tree = append(tree, repository.TreeEntry{
+			ObjectType: repository.Tree,
+			Hash:       messageTreeHash,
+			Name:       messagesEntryName,
+		})
+	}
+
 	// Store the logical clocks as well
 	// --> edit clock for each OperationPack/commits
 	// --> create clock only for the first OperationPack/commits
@@ -372,8 +554,9 @@ func (bug *Bug) Commit(repo repository.Repo) error {
 		Hash:       emptyBlobHash,
 		Name:       fmt.Sprintf(editClockEntryPattern, editTime),
 	})
+	var createTime util.LamportTime
 	if bug.lastCommit == "" {
-		createTime, err := repo.CreateTimeIncrement()
+		createTime, err = repo.CreateTimeIncrement()
 		if err != nil {
 			return err
 		}
@@ -402,23 +585,37 @@ func (bug *Bug) Commit(repo repository.Repo) error {
 		return err
 	}
 
-	bug.lastCommit = hash
-
-	// if it was the first commit, use the commit hash as bug id
-	if bug.id == "" {
-		bug.id = string(hash)
+	// if it was the first commit, derive the bug id from the creation
+	id := bug.id
+	if id == "" {
+		id = bug.contentId(createTime)
+	}
+	if id == "" {
+		// not a valid bug, but it can still be stored, as the ones
+		// read from a remote, and found by the verifications
+		id = string(hash)
 	}
 
 	// Create or update the Git reference for this bug
 	// When pushing later, the remote will ensure that this ref update
-	// is fast-forward, that is no data has been overwritten
-	ref := fmt.Sprintf("%s%s", bugsRefPattern, bug.id)
-	err = repo.UpdateRef(ref, hash)
+	// is fast-forward, that is no data has been overwritten. Locally, the
+	// update fails if the bug has been committed by someone else meanwhile.
+	ref := bug.refPrefix(repo) + id
+	err = tx.UpdateRefFrom(ref, hash, expectedRef(old))
+	if err != nil {
+		return err
+	}
 
+	err = tx.Commit()
 	if err != nil {
 		return err
 	}
 
+	bug.id = id
+	bug.rootPack = rootPack
+	bug.lastCommit = hash
+	bug.staging.commitHash = hash
+	bug.staging.editTime = editTime
 	bug.packs = append(bug.packs, bug.staging)
 	bug.staging = OperationPack{}
 
@@ -449,10 +646,16 @@ func makeMediaTree(pack OperationPack) []repository.TreeEntry {
 	return tree
 }
 
-// Merge a different version of the same bug by rebasing operations of this bug
-// that are not present in the other on top of the chain of operations of the
-// other version.
+// Merge a different version of the same bug, with the strategy configured in
+// the repository. It return true if the local version has been updated.
+// An ErrRootMismatch is returned if the other version doesn't share the
+// creation of the bug, despite its id.
 func (bug *Bug) Merge(repo repository.Repo, other *Bug) (bool, error) {
+	return bug.MergeWithOptions(repo, other, MergeOptions{})
+}
+
+// MergeWithOptions merge a different version of the same bug, like Merge
+func (bug *Bug) MergeWithOptions(repo repository.Repo, other *Bug, opts MergeOptions) (bool, error) {
 	// Note: a faster merge should be possible without actually reading and parsing
 	// all operations pack of our side.
 	// Reading the other side is still necessary to validate remote data, at least
@@ -470,19 +673,58 @@ func (bug *Bug) Merge(repo repository.Repo, other *Bug) (bool, error) {
 		return false, errors.New("can't merge a bug that has never been stored")
 	}
 
+	if err := bug.checkSameRoot(other); err != nil {
+		return false, err
+	}
+
+	// a redacted history doesn't share the rewritten commits with the
+	// original one, the operations are compared instead
+	redacted, err := bug.redactedVersion(repo, other)
+	if err != nil {
+		return false, err
+	}
+
+	switch redacted {
+	case other:
+		if !opts.ForceRedact {
+			return false, ErrRedactedRemotely
+		}
+		return bug.replay(repo, other, bug)
+	case bug:
+		return bug.replay(repo, bug, other)
+	}
+
+	strategy := opts.Strategy
+	if strategy == "" {
+		strategy, err = readMergeStrategy(repo)
+		if err != nil {
+			return false, err
+		}
+	}
+
 	ancestor, err := repo.FindCommonAncestor(bug.lastCommit, other.lastCommit)
 
 	if err != nil {
 		return false, err
 	}
 
+	// a history holding merge commits can't be rebased
+	if strategy == MergeStrategyMerge || bug.nonLinear || other.nonLinear {
+		return bug.mergeHistories(repo, other, ancestor)
+	}
+
+	return bug.rebase(repo, other, ancestor, strategy)
+}
+
+// rebase rewrite the extra packs of one version on top of the other one,
+// the local ones with MergeStrategyRebase or the other ones with
+// MergeStrategyRebaseRemote. The packs whose operations are already on the
+// other side, like after a rebase there, are dropped.
+func (bug *Bug) rebase(repo repository.Repo, other *Bug, ancestor util.Hash, strategy MergeStrategy) (bool, error) {
 	ancestorIndex := 0
-	newPacks := make([]OperationPack, 0, len(bug.packs))
 
 	// Find the root of the rebase
 	for i, pack := range bug.packs {
-		newPacks = append(newPacks, pack)
-
 		if pack.commitHash == ancestor {
 			ancestorIndex = i
 			break
@@ -494,18 +736,51 @@ func (bug *Bug) Merge(repo repository.Repo, other *Bug) (bool, error) {
 		return false, nil
 	}
 
-	// get other bug's extra packs
-	for i := ancestorIndex + 1; i < len(other.packs); i++ {
-		// clone is probably not necessary
-		newPack := other.packs[i].Clone()
+	var maxEditTime util.LamportTime
+
+	for _, pack := range other.packs[ancestorIndex+1:] {
+		if pack.editTime > maxEditTime {
+			maxEditTime = pack.editTime
+		}
+	}
+
+	// Make sure that the local clocks are ahead of the incoming operations,
+	// so that the next local edits are ordered after them
+	if err := repo.CreateWitness(other.createTime); err != nil {
+		return false, err
+	}
+	if err := repo.EditWitness(maxEditTime); err != nil {
+		return false, err
+	}
+
+	// the kept packs, and the ones rewritten on top of them. Without local
+	// packs, the other version is simply fast-forwarded.
+	kept, moved := other.packs, bug.packs
+	if strategy == MergeStrategyRebaseRemote && ancestor != bug.lastCommit {
+		kept, moved = bug.packs, other.packs
+	}
+
+	newPacks := make([]OperationPack, 0, len(bug.packs)+len(other.packs))
+	newPacks = append(newPacks, bug.packs[:ancestorIndex+1]...)
 
+	known := make(map[string]bool)
+
+	for _, pack := range kept[ancestorIndex+1:] {
+		// clone is probably not necessary
+		newPack := pack.Clone()
 		newPacks = append(newPacks, newPack)
-		bug.lastCommit = newPack.commitHash
+
+		if id, err := newPack.contentId(); err == nil {
+			known[id] = true
+		}
 	}
 
-	// rebase our extra packs
-	for i := ancestorIndex + 1; i < len(bug.packs); i++ {
-		pack := bug.packs[i]
+	lastCommit := newPacks[len(newPacks)-1].commitHash
+
+	for _, pack := range moved[ancestorIndex+1:] {
+		if id, err := pack.contentId(); err == nil && known[id] {
+			continue
+		}
 
 		// get the referenced git tree
 		treeHash, err := repo.GetTreeHash(pack.commitHash)
@@ -515,7 +790,7 @@ func (bug *Bug) Merge(repo repository.Repo, other *Bug) (bool, error) {
 		}
 
 		// create a new commit with the correct ancestor
-		hash, err := repo.StoreCommitWithParent(treeHash, bug.lastCommit)
+		hash, err := repo.StoreCommitWithParent(treeHash, lastCommit)
 
 		if err != nil {
 			return false, err
@@ -526,20 +801,69 @@ func (bug *Bug) Merge(repo repository.Repo, other *Bug) (bool, error) {
 		newPack.commitHash = hash
 		newPacks = append(newPacks, newPack)
 
-		// update the bug
-		bug.lastCommit = hash
+		lastCommit = hash
+	}
+
+	// the other packs were all known already
+	if lastCommit == bug.lastCommit {
+		return false, nil
 	}
 
 	// Update the git ref
-	err = repo.UpdateRef(bugsRefPattern+bug.id, bug.lastCommit)
+	tx := repo.Begin()
+
+	err := tx.UpdateRefFrom(localRefPrefix(repo)+bug.id, lastCommit, bug.lastCommit)
 	if err != nil {
+		tx.Rollback()
 		return false, err
 	}
 
+	err = tx.Commit()
+	if err != nil {
+		return false, err
+	}
+
+	// update the bug
+	bug.lastCommit = lastCommit
+	bug.packs = newPacks
+
 	return true, nil
 }
 
-// Id return the Bug identifier
+// contentId derive the id of a new bug from its creation: the author, the
+// title and the time of the create operation, along with the logical create
+// time. Unlike the hash of the first commit, used as id by the older bugs, it
+// doesn't depend on how the operations are packed in git, and is the same on
+// every clone sharing the create operation. Without a create operation, no
+// id is returned.
+func (bug *Bug) contentId(createTime util.LamportTime) string {
+	first := bug.FirstOp()
+	if first == nil || first.OpType() != CreateOp {
+		return ""
+	}
+
+	snap := first.Apply(Snapshot{})
+
+	data, err := json.Marshal(struct {
+		Author     Person
+		Title      string
+		UnixTime   int64
+		CreateTime util.LamportTime
+	}{
+		Author:     first.GetAuthor(),
+		Title:      snap.Title,
+		UnixTime:   first.Time().Unix(),
+		CreateTime: createTime,
+	})
+	if err != nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%x", sha1.Sum(data))
+}
+
+// Id return the Bug identifier. It panic if the bug is not stored yet, use
+// IdSafe when that can happen.
 func (bug *Bug) Id() string {
 	if bug.id == "" {
 		// simply panic as it would be a coding error
@@ -549,14 +873,114 @@ func (bug *Bug) Id() string {
 	return bug.id
 }
 
+// IdSafe return the Bug identifier, or ErrBugNotStored if the bug has never
+// been committed
+func (bug *Bug) IdSafe() (string, error) {
+	if bug.id == "" {
+		return "", ErrBugNotStored
+	}
+	return bug.id, nil
+}
+
+// FirstCommitHash return the hash of the first git commit of the bug, or an
+// empty hash if the bug has never been stored.
+func (bug *Bug) FirstCommitHash() util.Hash {
+	if len(bug.packs) == 0 {
+		return ""
+	}
+	return bug.packs[0].commitHash
+}
+
+// LastCommitHash return the hash of the last git commit of the bug, or an
+// empty hash if the bug has never been stored.
+func (bug *Bug) LastCommitHash() util.Hash {
+	return bug.lastCommit
+}
+
+// IsValidId tell if an id is well-formed, without checking if a bug actually
+// exist with this id
+func IsValidId(id string) bool {
+	return len(id) == idLength
+}
+
 // HumanId return the Bug identifier truncated for human consumption
 func (bug *Bug) HumanId() string {
-	return formatHumanId(bug.Id())
+	return FormatHumanId(bug.Id())
+}
+
+// AbbreviateId return the shortest abbreviation of a bug id unique among
+// the local bugs, which can be longer than the HumanId. As the ids are not
+// all git objects, git can't abbreviate them.
+func AbbreviateId(repo repository.Repo, id string) (string, error) {
+	ids, err := ListLocalIds(repo)
+	if err != nil {
+		return "", err
+	}
+
+	length := humanIdLength
+
+	for _, other := range ids {
+		if other == id {
+			continue
+		}
+
+		if common := commonPrefixLength(id, other); common+1 > length {
+			length = common + 1
+		}
+	}
+
+	return abbreviate(id, length), nil
+}
+
+// AbbreviateIds return the shortest abbreviation of each id unique among
+// them, like AbbreviateId. Once sorted, an id only need to be told apart from
+// its neighbours, so that they are all computed at once.
+func AbbreviateIds(ids []string) map[string]string {
+	sorted := append([]string{}, ids...)
+	sort.Strings(sorted)
+
+	result := make(map[string]string, len(sorted))
+
+	for i, id := range sorted {
+		length := humanIdLength
+
+		for _, j := range []int{i - 1, i + 1} {
+			if j < 0 || j >= len(sorted) || sorted[j] == id {
+				continue
+			}
+			if common := commonPrefixLength(id, sorted[j]); common+1 > length {
+				length = common + 1
+			}
+		}
+
+		result[id] = abbreviate(id, length)
+	}
+
+	return result
+}
+
+// LocalAbbreviations return the shortest unique abbreviation of the id of
+// every local bug, see AbbreviateIds
+func LocalAbbreviations(repo repository.Repo) (map[string]string, error) {
+	ids, err := ListLocalIds(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	return AbbreviateIds(ids), nil
+}
+
+func abbreviate(id string, length int) string {
+	if length > len(id) {
+		length = len(id)
+	}
+	return id[:length]
 }
 
-func formatHumanId(id string) string {
-	format := fmt.Sprintf("%%.%ds", humanIdLength)
-	return fmt.Sprintf(format, id)
+// FormatHumanId truncate a bug id for human consumption, like HumanId. It's
+// the shortest abbreviation, see AbbreviateId for one unique among the bugs.
+func FormatHumanId(id string) string {
+	return abbreviate(id, humanIdLength)
 }
 
 // Lookup for the very first operation of the bug.
@@ -595,11 +1019,76 @@ func (bug *Bug) LastOp() Operation {
 	return lastPack.Operations[len(lastPack.Operations)-1]
 }
 
+// Lookup for the only operation of the bug, or nil if there is none or
+// more than one
+func (bug *Bug) singleOp() Operation {
+	var single Operation
+
+	for _, pack := range bug.packs {
+		for _, op := range pack.Operations {
+			if single != nil {
+				return nil
+			}
+			single = op
+		}
+	}
+
+	for _, op := range bug.staging.Operations {
+		if single != nil {
+			return nil
+		}
+		single = op
+	}
+
+	return single
+}
+
+// OperationsByAuthor count the committed operations of each author, by
+// author id. As a Person has no id of its own, it's their normalized email,
+// or their name if they have none, so that the counts of several bugs can be
+// added up. The operations still in staging are not counted.
+func (bug *Bug) OperationsByAuthor() map[string]int {
+	result := make(map[string]int)
+
+	for _, pack := range bug.packs {
+		for _, op := range pack.Operations {
+			result[authorId(op.GetAuthor())]++
+		}
+	}
+
+	return result
+}
+
+func authorId(author Person) string {
+	if author.Email == "" {
+		return author.Name
+	}
+	return NormalizeEmail(author.Email)
+}
+
 // Compile a bug in a easily usable snapshot
 func (bug *Bug) Compile() Snapshot {
+	// Most bugs only have their CreateOp, no need for the iterator then
+	if op := bug.singleOp(); op != nil {
+		snap := op.Apply(Snapshot{
+			id:       bug.id,
+			Status:   OpenStatus,
+			Priority: DefaultPriority,
+		})
+		snap.Operations = []Operation{op}
+		snap.packIndexes = []int{0}
+		return snap
+	}
+
+	return bug.compileAll()
+}
+
+// compileAll is the general path of Compile, applying every operation in order
+func (bug *Bug) compileAll() Snapshot {
 	snap := Snapshot{
-		id:     bug.id,
-		Status: OpenStatus,
+		id:       bug.id,
+		Status:   OpenStatus,
+		Priority: DefaultPriority,
 	}
 
 	it := NewOperationIterator(bug)
@@ -608,7 +1097,49 @@ func (bug *Bug) Compile() Snapshot {
 		op := it.Value()
 		snap = op.Apply(snap)
 		snap.Operations = append(snap.Operations, op)
+		snap.packIndexes = append(snap.packIndexes, it.PackIndex())
 	}
 
 	return snap
 }
+
+// Title return the current title of the bug, only applying the operations
+// that define it instead of compiling the whole bug
+func (bug *Bug) Title() string {
+	op := bug.lastOpOfType(CreateOp, SetTitleOp)
+	if op == nil {
+		return ""
+	}
+
+	return op.Apply(Snapshot{}).Title
+}
+
+// Status return the current status of the bug, only applying the operations
+// that define it instead of compiling the whole bug
+func (bug *Bug) Status() Status {
+	op := bug.lastOpOfType(SetStatusOp)
+	if op == nil {
+		return OpenStatus
+	}
+
+	return op.Apply(Snapshot{}).Status
+}
+
+// lastOpOfType return the last operation of one of the given types, in the
+// order of Compile
+func (bug *Bug) lastOpOfType(types ...OperationType) Operation {
+	var last Operation
+
+	it := NewOperationIterator(bug)
+
+	for it.Next() {
+		op := it.Value()
+		for _, t := range types {
+			if op.OpType() == t {
+				last = op
+			}
+		}
+	}
+
+	return last
+}
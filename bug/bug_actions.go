@@ -3,29 +3,207 @@ package bug
 import (
 	"fmt"
 	"io"
+	"sort"
 	"strings"
 
 	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util"
 )
 
 const MsgMergeNew = "new"
 const MsgMergeInvalid = "invalid data"
 const MsgMergeUpdated = "updated"
 const MsgMergeNothing = "nothing to do"
+const MsgMergeRedacted = "redacted remotely, pull with --force-redact to accept the rewritten history"
+
+// MergeOptions tune the merge of the remote bugs
+type MergeOptions struct {
+	// Accept the remote histories rewritten by a redaction. The local
+	// operations are committed again on top of them. See Bug.Redact.
+	ForceRedact bool
+
+	// Update the reference of each bug as soon as it's merged, instead of
+	// all of them at once at the end. An interrupted merge then leaves some
+	// bugs merged, but a single transaction can be impractical for very
+	// large merges.
+	NoTransaction bool
+
+	// The strategy joining the versions that diverged, instead of the one
+	// configured in the repository, see MergeStrategyConfigKey
+	Strategy MergeStrategy
+}
 
+// Fetch retrieve the bugs, the registries, the templates and the identity
+// mapping of a remote in the remote refs, like refs/remotes/origin/bugs/,
+// without merging them. Pull fetch first.
 func Fetch(repo repository.Repo, remote string) (string, error) {
-	remoteRefSpec := fmt.Sprintf(bugsRemoteRefPattern, remote)
-	fetchRefSpec := fmt.Sprintf("%s*:%s*", bugsRefPattern, remoteRefSpec)
-
-	return repo.FetchRefs(remote, fetchRefSpec)
+	// forced, as a redaction rewrite the remote history. The local bugs are
+	// only updated by the merge.
+	remoteRefSpec := remoteRefPrefix(repo, remote)
+	fetchRefSpec := fmt.Sprintf("+%s*:%s*", localRefPrefix(repo), remoteRefSpec)
+
+	return repo.FetchRefs(remote, fetchRefSpec,
+		labelRegistryFetchRefSpec(remote), checklistRegistryFetchRefSpec(remote),
+		templatesFetchRefSpec(remote), identityMappingFetchRefSpec(remote))
 }
 
+// Push send the local bugs, the registries, the templates and the identity
+// mapping to a remote. The bugs that diverged only because the remote commits
+// were rewritten on top of the local ones, see MergeStrategyRebaseRemote,
+// replace their remote version as long as it didn't change since the last
+// fetch.
 func Push(repo repository.Repo, remote string) (string, error) {
-	return repo.PushRefs(remote, bugsRefPattern+"*")
+	_, rewritten, err := checkPushable(repo, remote)
+	if err != nil {
+		return "", err
+	}
+
+	leases := make(map[string]util.Hash, len(rewritten))
+	for id, remoteHash := range rewritten {
+		leases[localRefPrefix(repo)+id] = remoteHash
+	}
+
+	refSpecs := []string{
+		localRefPrefix(repo) + "*",
+		fmt.Sprintf(TemplatesRefPattern, "*"),
+	}
+
+	for _, ref := range []string{labelRegistryRef(repo), checklistRegistryRef(repo), IdentityMappingRef(repo)} {
+		exist, err := repo.RefExist(ref)
+		if err != nil {
+			return "", err
+		}
+		if exist {
+			refSpecs = append(refSpecs, ref)
+		}
+	}
+
+	stdout, err := repo.PushRefsWithLease(remote, leases, refSpecs...)
+	if err != nil || len(rewritten) == 0 {
+		return stdout, err
+	}
+
+	// the remote refs are only updated by a fetch, the next lease would be
+	// taken on the replaced version otherwise
+	tx := repo.Begin()
+
+	for id := range rewritten {
+		localHash, err := repo.ResolveRef(localRefPrefix(repo) + id)
+		if err == nil {
+			err = tx.UpdateRef(remoteRefPrefix(repo, remote)+id, localHash)
+		}
+		if err != nil {
+			tx.Rollback()
+			return stdout, err
+		}
+	}
+
+	return stdout, tx.Commit()
+}
+
+// CheckPushable return the ids of the local bugs that diverged from their
+// version on a remote, as of the last fetch: the remote one is not part of
+// the local history, so pushing them would be rejected. They need to be
+// pulled and merged first. The bugs not on the remote yet are pushable, as
+// well as the ones holding all the operations of the remote version, like
+// after a merge with MergeStrategyRebaseRemote.
+func CheckPushable(repo repository.Repo, remote string) ([]string, error) {
+	diverged, _, err := checkPushable(repo, remote)
+	return diverged, err
+}
+
+// checkPushable return the ids of the diverged bugs, and the remote hash of
+// the ones whose remote version can be replaced
+func checkPushable(repo repository.Repo, remote string) ([]string, map[string]util.Hash, error) {
+	localHashes, err := repo.ResolveRefs(localRefPrefix(repo))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	remoteHashes, err := repo.ResolveRefs(remoteRefPrefix(repo, remote))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var diverged []string
+	rewritten := make(map[string]util.Hash)
+
+	for localRef, localHash := range localHashes {
+		id := strings.TrimPrefix(localRef, localRefPrefix(repo))
+
+		remoteHash, ok := remoteHashes[remoteRefPrefix(repo, remote)+id]
+		if !ok || remoteHash == localHash {
+			continue
+		}
+
+		ancestor, err := repo.FindCommonAncestor(localHash, remoteHash)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if ancestor == remoteHash {
+			continue
+		}
+
+		contained, err := containsRemoteBug(repo, remote, id)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if contained {
+			rewritten[id] = remoteHash
+		} else {
+			diverged = append(diverged, id)
+		}
+	}
+
+	sort.Strings(diverged)
+
+	return diverged, rewritten, nil
+}
+
+// containsRemoteBug tell if every operation pack of the remote version of a
+// bug is also in the local version, whatever the commit holding it
+func containsRemoteBug(repo repository.Repo, remote string, id string) (bool, error) {
+	localBug, err := ReadLocalBug(repo, id)
+	if err != nil {
+		return false, err
+	}
+
+	remoteBug, err := ReadRemoteBug(repo, remote, id)
+	if err != nil {
+		return false, err
+	}
+
+	known := make(map[string]bool, len(localBug.packs))
+	for _, pack := range localBug.packs {
+		contentId, err := pack.contentId()
+		if err != nil {
+			return false, err
+		}
+		known[contentId] = true
+	}
+
+	for _, pack := range remoteBug.packs {
+		contentId, err := pack.contentId()
+		if err != nil {
+			return false, err
+		}
+		if !known[contentId] {
+			return false, nil
+		}
+	}
+
+	return true, nil
 }
 
 func Pull(repo repository.Repo, out io.Writer, remote string) error {
-	fmt.Fprintf(out, "Fetching remote ...\n")
+	return PullWithOptions(repo, out, remote, MergeOptions{})
+}
+
+// PullWithOptions fetch and merge the bugs of a remote, like Pull
+func PullWithOptions(repo repository.Repo, out io.Writer, remote string, opts MergeOptions) error {
+	fmt.Fprintf(out, "Fetching remote %s ...\n", remote)
 
 	stdout, err := Fetch(repo, remote)
 	if err != nil {
@@ -36,7 +214,7 @@ func Pull(repo repository.Repo, out io.Writer, remote string) error {
 
 	fmt.Fprintf(out, "Merging data ...\n")
 
-	for merge := range MergeAll(repo, remote) {
+	for merge := range MergeAllWithOptions(repo, remote, opts) {
 		if merge.Err != nil {
 			return merge.Err
 		}
@@ -60,7 +238,7 @@ type MergeResult struct {
 func newMergeError(id string, err error) MergeResult {
 	return MergeResult{
 		Id:      id,
-		HumanId: formatHumanId(id),
+		HumanId: FormatHumanId(id),
 		Status:  err.Error(),
 	}
 }
@@ -68,84 +246,220 @@ func newMergeError(id string, err error) MergeResult {
 func newMergeStatus(id string, status string) MergeResult {
 	return MergeResult{
 		Id:      id,
-		HumanId: formatHumanId(id),
+		HumanId: FormatHumanId(id),
 		Status:  status,
 	}
 }
 
+// MergeAll merge the bugs of a remote in the local ones. Only the remote
+// bugs that changed since the last merge are processed, see the sync state.
+// The registries, the templates and the identity mapping of the remote are
+// merged first.
+//
+// The references of the merged bugs are updated all at once when the merge
+// complete, so that an interrupted merge doesn't leave the repository with
+// only some of the bugs merged. See MergeOptions.NoTransaction.
 func MergeAll(repo repository.Repo, remote string) <-chan MergeResult {
+	return MergeAllWithOptions(repo, remote, MergeOptions{})
+}
+
+// MergeAllWithOptions merge the bugs of a remote in the local ones, like
+// MergeAll
+func MergeAllWithOptions(repo repository.Repo, remote string, opts MergeOptions) <-chan MergeResult {
 	out := make(chan MergeResult)
 
 	go func() {
 		defer close(out)
 
-		remoteRefSpec := fmt.Sprintf(bugsRemoteRefPattern, remote)
-		remoteRefs, err := repo.ListRefs(remoteRefSpec)
+		if err := mergeLabelRegistry(repo, remote); err != nil {
+			out <- MergeResult{Err: err}
+			return
+		}
 
-		if err != nil {
+		if err := mergeChecklistRegistry(repo, remote); err != nil {
 			out <- MergeResult{Err: err}
 			return
 		}
 
-		for _, remoteRef := range remoteRefs {
-			refSplitted := strings.Split(remoteRef, "/")
-			id := refSplitted[len(refSplitted)-1]
+		if err := mergeTemplates(repo, remote); err != nil {
+			out <- MergeResult{Err: err}
+			return
+		}
 
-			remoteBug, err := readBug(repo, remoteRef)
+		if err := mergeIdentityMapping(repo, remote); err != nil {
+			out <- MergeResult{Err: err}
+			return
+		}
 
-			if err != nil {
-				out <- newMergeError(id, err)
-				continue
-			}
+		if opts.NoTransaction {
+			mergeBugs(repo, remote, opts, out)
+			return
+		}
 
-			// Check for error in remote data
-			if !remoteBug.IsValid() {
-				out <- newMergeStatus(id, MsgMergeInvalid)
-				continue
-			}
+		// nothing is applied if the merge is interrupted
+		batch := newRefBatch(repo)
 
-			localRef := bugsRefPattern + remoteBug.Id()
-			localExist, err := repo.RefExist(localRef)
+		if err := mergeBugs(batch, remote, opts, out); err != nil {
+			return
+		}
 
-			if err != nil {
-				out <- newMergeError(id, err)
-				continue
-			}
+		if err := batch.Apply(); err != nil {
+			out <- MergeResult{Err: fmt.Errorf("updating the merged bugs: %v", err)}
+		}
+	}()
 
-			// the bug is not local yet, simply create the reference
-			if !localExist {
-				err := repo.CopyRef(remoteRef, localRef)
+	return out
+}
 
-				if err != nil {
-					out <- newMergeError(id, err)
-					return
-				}
+// mergeBugs merge each remote bug in the local one, and send the result of
+// each merge. An error stopping the merge is returned after being sent.
+func mergeBugs(repo repository.Repo, remote string, opts MergeOptions, out chan<- MergeResult) error {
+	// the local bugs are resolved at once, rather than one by one
+	localHashes, err := repo.ResolveRefs(localRefPrefix(repo))
 
-				out <- newMergeStatus(id, MsgMergeNew)
-				continue
-			}
+	if err != nil {
+		out <- MergeResult{Err: err}
+		return err
+	}
+
+	synced, err := readSyncState(repo, remote)
+
+	if err != nil {
+		out <- MergeResult{Err: err}
+		return err
+	}
+
+	// the state of the remote bugs processed by this merge
+	state := make(map[string]util.Hash)
+
+	defer func() {
+		if err := writeSyncState(repo, remote, synced, state); err != nil {
+			out <- MergeResult{Err: err}
+		}
+	}()
+
+	// the error stopping the merge, already sent
+	var stopErr error
+	stop := func(id string, err error) error {
+		out <- newMergeError(id, err)
+		stopErr = err
+		return err
+	}
+
+	// the remote bugs are streamed in the order of their refs
+	err = repo.ForEachRef(remoteRefPrefix(repo, remote), func(remoteRef string, hash util.Hash) error {
+		refSplitted := strings.Split(remoteRef, "/")
+		id := refSplitted[len(refSplitted)-1]
+
+		// unchanged since the last merge
+		if synced[id] == hash {
+			state[id] = hash
+			return nil
+		}
+
+		localRef := localRefPrefix(repo) + id
+		localHash, localExist := localHashes[localRef]
+
+		// nothing to read, let alone merge
+		if localExist && localHash == hash {
+			state[id] = hash
+			out <- newMergeStatus(id, MsgMergeNothing)
+			return nil
+		}
+
+		// both sides mostly share their history, their trees are only
+		// read once for this bug
+		cache := newTreeCache(repo)
 
-			localBug, err := readBug(repo, localRef)
+		remoteBug, err := readBug(cache, remoteRef)
+
+		if err != nil {
+			out <- newMergeError(id, err)
+			return nil
+		}
+
+		// Check for error in remote data
+		if !remoteBug.IsValid() {
+			state[id] = hash
+			out <- newMergeStatus(id, MsgMergeInvalid)
+			return nil
+		}
 
+		// the bug is not local yet, simply create the reference
+		if !localExist {
+			err := remoteBug.checkReceivedLimits(cache, nil)
+			if _, ok := err.(*ErrLimitExceeded); ok {
+				state[id] = hash
+				out <- newMergeStatus(id, MsgMergeInvalid)
+				return nil
+			}
 			if err != nil {
-				out <- newMergeError(id, err)
-				return
+				return stop(id, err)
 			}
 
-			updated, err := localBug.Merge(repo, remoteBug)
+			err = repo.CopyRef(remoteRef, localRef)
 
 			if err != nil {
-				out <- newMergeError(id, err)
-				return
+				return stop(id, err)
 			}
 
-			if updated {
-				out <- newMergeStatus(id, MsgMergeUpdated)
-			} else {
-				out <- newMergeStatus(id, MsgMergeNothing)
-			}
+			state[id] = hash
+			out <- newMergeStatus(id, MsgMergeNew)
+			return nil
 		}
-	}()
 
-	return out
+		localBug, err := readBug(cache, localRef)
+
+		if err != nil {
+			return stop(id, err)
+		}
+
+		// only the new packs, the stored ones are accepted whatever the
+		// limits in effect
+		err = remoteBug.checkReceivedLimits(cache, localBug)
+		if _, ok := err.(*ErrLimitExceeded); ok {
+			state[id] = hash
+			out <- newMergeStatus(id, MsgMergeInvalid)
+			return nil
+		}
+		if err != nil {
+			return stop(id, err)
+		}
+
+		updated, err := localBug.MergeWithOptions(cache, remoteBug, opts)
+
+		// not merged, it's not recorded in the sync state so that it's
+		// merged again when accepted
+		if err == ErrRedactedRemotely {
+			out <- newMergeStatus(id, MsgMergeRedacted)
+			return nil
+		}
+
+		// not the same bug despite the id, the remote data is invalid
+		if _, ok := err.(*ErrRootMismatch); ok {
+			state[id] = hash
+			out <- newMergeStatus(id, MsgMergeInvalid)
+			return nil
+		}
+
+		if err != nil {
+			return stop(id, err)
+		}
+
+		state[id] = hash
+
+		if updated {
+			out <- newMergeStatus(id, MsgMergeUpdated)
+		} else {
+			out <- newMergeStatus(id, MsgMergeNothing)
+		}
+
+		return nil
+	})
+
+	if err != nil && err != stopErr {
+		out <- MergeResult{Err: err}
+	}
+
+	return err
 }
@@ -0,0 +1,95 @@
+package bug
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type createTestOperation struct {
+	OpBase
+	Title   string
+	Message string
+}
+
+func (op createTestOperation) Apply(snapshot Snapshot) Snapshot {
+	snapshot.Title = op.Title
+	snapshot.Comments = []Comment{
+		{Message: op.Message, Author: op.Author, UnixTime: op.UnixTime},
+	}
+	snapshot.Author = op.Author
+	snapshot.CreatedAt = op.Time()
+	return snapshot
+}
+
+func (op createTestOperation) Summary() string {
+	return op.Title
+}
+
+func newCreateTestOp() createTestOperation {
+	return createTestOperation{
+		OpBase: OpBase{
+			OperationType: CreateOp,
+			Author:        Person{Name: "René Descartes", Email: "rene@descartes.fr"},
+			UnixTime:      time.Now().Unix(),
+		},
+		Title:   "title",
+		Message: "message",
+	}
+}
+
+func TestCompileSingleOp(t *testing.T) {
+	committed := &Bug{
+		id: "id",
+		packs: []OperationPack{
+			{Operations: []Operation{newCreateTestOp()}},
+		},
+	}
+
+	staged := NewBug()
+	staged.Append(newCreateTestOp())
+
+	for _, b := range []*Bug{committed, staged} {
+		if b.singleOp() == nil {
+			t.Fatal("the fast path should be taken")
+		}
+
+		fast := b.Compile()
+		general := b.compileAll()
+
+		if !reflect.DeepEqual(fast, general) {
+			t.Fatalf("%v different than %v", fast, general)
+		}
+	}
+
+	staged.Append(newTestOp("title"))
+
+	if staged.singleOp() != nil {
+		t.Fatal("the fast path should not be taken with several operations")
+	}
+	if len(staged.Compile().Operations) != 2 {
+		t.Fatal("all the operations should be compiled")
+	}
+}
+
+func BenchmarkCompileSingleOp(b *testing.B) {
+	bug := &Bug{
+		packs: []OperationPack{
+			{Operations: []Operation{newCreateTestOp()}},
+		},
+	}
+
+	b.Run("fast", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			bug.Compile()
+		}
+	})
+
+	b.Run("iterator", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			bug.compileAll()
+		}
+	})
+}
@@ -0,0 +1,155 @@
+package bug
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ChecklistState is the state of an item of a checklist
+type ChecklistState int
+
+const (
+	_ ChecklistState = iota
+	ChecklistPending
+	ChecklistDone
+	// the item doesn't apply to the bug
+	ChecklistNA
+)
+
+func (s ChecklistState) String() string {
+	switch s {
+	case ChecklistPending:
+		return "pending"
+	case ChecklistDone:
+		return "done"
+	case ChecklistNA:
+		return "na"
+	default:
+		return "unknown state"
+	}
+}
+
+// ParseChecklistState parse the name of a state, case insensitively
+func ParseChecklistState(value string) (ChecklistState, error) {
+	switch strings.ToLower(value) {
+	case "pending":
+		return ChecklistPending, nil
+	case "done":
+		return ChecklistDone, nil
+	case "na":
+		return ChecklistNA, nil
+	default:
+		return 0, fmt.Errorf("unknown state \"%s\", expected pending, done or na", value)
+	}
+}
+
+// ChecklistItem is an item of a checklist with its state
+type ChecklistItem struct {
+	Name  string
+	State ChecklistState
+}
+
+// Checklist is a named list of items to go through for a bug, like the steps
+// of a triage. The checklists available are defined in the
+// ChecklistRegistry.
+type Checklist struct {
+	Name  string
+	Items []ChecklistItem
+}
+
+// Completion return the number of items done, and the number of items
+// applying to the bug
+func (c Checklist) Completion() (done int, total int) {
+	for _, item := range c.Items {
+		switch item.State {
+		case ChecklistDone:
+			done++
+			total++
+		case ChecklistPending:
+			total++
+		}
+	}
+	return done, total
+}
+
+// IsComplete tell if every item applying to the bug is done
+func (c Checklist) IsComplete() bool {
+	done, total := c.Completion()
+	return done == total
+}
+
+// Item return the state of an item, if it's in the checklist
+func (c Checklist) Item(name string) (ChecklistState, bool) {
+	for _, item := range c.Items {
+		if item.Name == name {
+			return item.State, true
+		}
+	}
+	return 0, false
+}
+
+// Checklist return the checklist of the bug with the given name, if any
+func (snap Snapshot) Checklist(name string) (Checklist, bool) {
+	for _, checklist := range snap.Checklists {
+		if checklist.Name == name {
+			return checklist, true
+		}
+	}
+	return Checklist{}, false
+}
+
+// ChecklistCompletion return the number of items done and applying to the
+// bug, over all its checklists
+func (snap Snapshot) ChecklistCompletion() (done int, total int) {
+	for _, checklist := range snap.Checklists {
+		d, t := checklist.Completion()
+		done += d
+		total += t
+	}
+	return done, total
+}
+
+// SetChecklistItems set the state of some items of a checklist, adding the
+// checklist and the items missing. Only the given items are changed, so
+// that concurrent edits of different items are all kept.
+func (snap *Snapshot) SetChecklistItems(name string, items []ChecklistItem) {
+	// new slices, as the previous ones can be shared with other snapshots
+	checklists := make([]Checklist, 0, len(snap.Checklists)+1)
+	index := -1
+
+	for i, checklist := range snap.Checklists {
+		if checklist.Name == name {
+			index = i
+		}
+		checklists = append(checklists, checklist)
+	}
+
+	if index < 0 {
+		index = len(checklists)
+		checklists = append(checklists, Checklist{Name: name})
+	}
+
+	updated := append([]ChecklistItem(nil), checklists[index].Items...)
+
+	for _, item := range items {
+		found := false
+		for i := range updated {
+			if updated[i].Name == item.Name {
+				updated[i].State = item.State
+				found = true
+			}
+		}
+		if !found {
+			updated = append(updated, item)
+		}
+	}
+
+	checklists[index].Items = updated
+
+	sort.SliceStable(checklists, func(i, j int) bool {
+		return checklists[i].Name < checklists[j].Name
+	})
+
+	snap.Checklists = checklists
+}
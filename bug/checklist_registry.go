@@ -0,0 +1,226 @@
+package bug
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util"
+)
+
+// ChecklistRegistryRefPattern is the ref storing the checklists defined in a
+// namespace, like refs/git-bug/checklists/bugs. It's pushed and pulled along
+// with the bugs.
+const ChecklistRegistryRefPattern = "refs/git-bug/checklists/%s"
+
+const checklistRegistryEntryName = "checklists"
+
+const remoteChecklistRegistryRefPattern = "refs/remotes/%s/git-bug/checklists/%s"
+
+// The names of the checklists are used in the queries, like
+// checklist:triage:done
+var checklistNameRegexp = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9-]*$`)
+
+// ChecklistDefinition is the list of the items of a checklist
+type ChecklistDefinition struct {
+	Items []string `json:"items"`
+
+	// Time order the edits, like LabelInfo.Time
+	Time     uint64 `json:"time"`
+	UnixTime int64  `json:"unix_time"`
+}
+
+// ChecklistRegistry hold the checklists available for the bugs of the
+// repository.
+//
+// Like the LabelRegistry, it's stored in git as a JSON blob referenced by a
+// dedicated chain of commits. Concurrent edits from two clones are merged
+// checklist by checklist, the last edit winning.
+type ChecklistRegistry struct {
+	checklists map[string]ChecklistDefinition
+
+	// the commit the registry has been read from, if any
+	lastCommit util.Hash
+}
+
+func checklistRegistryRef(repo repository.Repo) string {
+	return fmt.Sprintf(ChecklistRegistryRefPattern, RepoNamespace(repo))
+}
+
+func remoteChecklistRegistryRef(repo repository.Repo, remote string) string {
+	return fmt.Sprintf(remoteChecklistRegistryRefPattern, remote, RepoNamespace(repo))
+}
+
+// checklistRegistryFetchRefSpec fetch the registries of every namespace, as
+// a pattern doesn't fail when the remote has none
+func checklistRegistryFetchRefSpec(remote string) string {
+	return fmt.Sprintf("+%s:%s",
+		fmt.Sprintf(ChecklistRegistryRefPattern, "*"),
+		fmt.Sprintf(remoteChecklistRegistryRefPattern, remote, "*"),
+	)
+}
+
+// ValidateChecklistName check that the name of a checklist can be used in a
+// query
+func ValidateChecklistName(name string) error {
+	if !checklistNameRegexp.MatchString(name) {
+		return fmt.Errorf("invalid checklist name \"%s\", expected letters, digits and dashes", name)
+	}
+	return nil
+}
+
+// ValidateChecklistItem check that the name of an item can be given as
+// item=state
+func ValidateChecklistItem(item string) error {
+	if strings.TrimSpace(item) != item || item == "" {
+		return fmt.Errorf("invalid checklist item \"%s\", expected a trimmed, non-empty name", item)
+	}
+
+	for _, r := range item {
+		if r == '=' || unicode.IsControl(r) {
+			return fmt.Errorf("invalid checklist item \"%s\", it can't contain '=' or control characters", item)
+		}
+	}
+
+	return nil
+}
+
+// ReadChecklistRegistry read the checklist registry stored in the
+// repository. If none has been stored yet, an empty registry is returned.
+func ReadChecklistRegistry(repo repository.Repo) (*ChecklistRegistry, error) {
+	return readChecklistRegistry(repo, checklistRegistryRef(repo))
+}
+
+func readChecklistRegistry(repo repository.Repo, ref string) (*ChecklistRegistry, error) {
+	registry := &ChecklistRegistry{
+		checklists: make(map[string]ChecklistDefinition),
+	}
+
+	var err error
+	registry.lastCommit, err = readConfigRef(repo, ref, checklistRegistryEntryName, "checklist registry", &registry.checklists)
+	if err != nil {
+		return nil, err
+	}
+
+	return registry, nil
+}
+
+// Lookup return the definition of a checklist, if any
+func (reg *ChecklistRegistry) Lookup(name string) (ChecklistDefinition, bool) {
+	definition, ok := reg.checklists[name]
+	if !ok || len(definition.Items) == 0 {
+		return ChecklistDefinition{}, false
+	}
+	return definition, true
+}
+
+// Names return the names of the checklists defined, sorted
+func (reg *ChecklistRegistry) Names() []string {
+	var names []string
+	for name := range reg.checklists {
+		if _, ok := reg.Lookup(name); ok {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// Define set the items of a checklist. Without items, the checklist is
+// removed, but kept in the registry so that the removal also win over the
+// older edits when merged. The bugs keep the items already set.
+func (reg *ChecklistRegistry) Define(name string, items []string) error {
+	if err := ValidateChecklistName(name); err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool, len(items))
+	for _, item := range items {
+		if err := ValidateChecklistItem(item); err != nil {
+			return err
+		}
+		if seen[item] {
+			return fmt.Errorf("duplicated checklist item \"%s\"", item)
+		}
+		seen[item] = true
+	}
+
+	var maxTime uint64
+	for _, definition := range reg.checklists {
+		if definition.Time > maxTime {
+			maxTime = definition.Time
+		}
+	}
+
+	reg.checklists[name] = ChecklistDefinition{
+		Items:    items,
+		Time:     maxTime + 1,
+		UnixTime: time.Now().Unix(),
+	}
+
+	return nil
+}
+
+// newer tell if an edit of a checklist win over another one
+func (definition ChecklistDefinition) newer(other ChecklistDefinition) bool {
+	if definition.Time != other.Time {
+		return definition.Time > other.Time
+	}
+	if definition.UnixTime != other.UnixTime {
+		return definition.UnixTime > other.UnixTime
+	}
+	// any order, as long as every clone pick the same one
+	return strings.Join(definition.Items, "\n") > strings.Join(other.Items, "\n")
+}
+
+// merge add the edits of another registry, keeping the last edit of each
+// checklist
+func (reg *ChecklistRegistry) merge(other *ChecklistRegistry) {
+	for name, definition := range other.checklists {
+		local, ok := reg.checklists[name]
+		if !ok || definition.newer(local) {
+			reg.checklists[name] = definition
+		}
+	}
+}
+
+// Write store the checklist registry in the repository
+func (reg *ChecklistRegistry) Write(repo repository.Repo) error {
+	commitHash, err := writeConfigRef(repo, checklistRegistryRef(repo), checklistRegistryEntryName, reg.lastCommit, reg.checklists)
+	if err != nil {
+		return err
+	}
+
+	reg.lastCommit = commitHash
+
+	return nil
+}
+
+// mergeChecklistRegistry merge the checklist registry fetched from a remote
+// in the local one, checklist by checklist
+func mergeChecklistRegistry(repo repository.Repo, remote string) error {
+	localRef := checklistRegistryRef(repo)
+	remoteRef := remoteChecklistRegistryRef(repo, remote)
+
+	return mergeConfigRef(repo, localRef, remoteRef, func() (util.Hash, error) {
+		local, err := readChecklistRegistry(repo, localRef)
+		if err != nil {
+			return "", err
+		}
+
+		other, err := readChecklistRegistry(repo, remoteRef)
+		if err != nil {
+			return "", err
+		}
+
+		local.merge(other)
+
+		return storeConfigTree(repo, checklistRegistryEntryName, local.checklists)
+	})
+}
@@ -7,14 +7,24 @@ import (
 // Witnesser will read all the available Bug to recreate the different logical
 // clocks
 func Witnesser(repo *repository.GitRepo) error {
+	var err error
+
+	// the stream is read to its end, to not leave its goroutine blocked
 	for b := range ReadAllLocalBugs(repo) {
+		if err != nil {
+			continue
+		}
+
 		if b.Err != nil {
-			return b.Err
+			err = b.Err
+			continue
 		}
 
-		repo.CreateWitness(b.Bug.createTime)
-		repo.EditWitness(b.Bug.editTime)
+		err = repo.CreateWitness(b.Bug.createTime)
+		if err == nil {
+			err = repo.EditWitness(b.Bug.editTime)
+		}
 	}
 
-	return nil
+	return err
 }
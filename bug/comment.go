@@ -1,20 +1,138 @@
 package bug
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/MichaelMure/git-bug/util"
 	"github.com/dustin/go-humanize"
-	"time"
 )
 
 // Comment represent a comment in a Bug
 type Comment struct {
-	Author  Person
+	Author Person
+	// The message, empty if it's stored in its own blob, see GetMessage
 	Message string
 	Files   []util.Hash
 
 	// Creation time of the comment.
 	// Should be used only for human display, never for ordering as we can't rely on it in a distributed system.
 	UnixTime int64
+
+	// All the successive versions of the message, oldest first. The last one
+	// is the current message.
+	History []CommentRevision
+
+	// Set if the comment has been deleted. The message is then replaced by a
+	// tombstone and the files are hidden, but the original message is still
+	// in the History.
+	Deletion *CommentDeletion
+
+	// the message stored in its own blob, only read when needed
+	blobMessage *BlobMessage
+}
+
+// CommentDeletion describe who deleted a comment and when
+type CommentDeletion struct {
+	Author   Person
+	UnixTime int64
+	// True if the comment was deleted by someone else than its author
+	Forced bool
+}
+
+// Tombstone return the text displayed instead of a deleted comment
+func (d CommentDeletion) Tombstone() string {
+	t := time.Unix(d.UnixTime, 0)
+	return fmt.Sprintf("deleted by %s at %s", d.Author.Name, t.Format("Jan 2 2006 15:04"))
+}
+
+// IsDeleted tell if the comment has been deleted
+func (c Comment) IsDeleted() bool {
+	return c.Deletion != nil
+}
+
+// GetMessage return the message of the comment, reading it from its blob
+// if needed
+func (c Comment) GetMessage() string {
+	if c.blobMessage != nil {
+		return c.blobMessage.String()
+	}
+	return c.Message
+}
+
+// SetMessage replace the message of the comment
+func (c *Comment) SetMessage(message string) {
+	c.Message = message
+	c.blobMessage = nil
+}
+
+// WithBlobMessage return a copy of a new comment whose message, and the one
+// of its first revision, are read from the given blob when needed. A nil
+// blob leave the comment unchanged.
+func (c Comment) WithBlobMessage(message *BlobMessage) Comment {
+	if message == nil {
+		return c
+	}
+
+	c.Message = ""
+	c.blobMessage = message
+
+	if len(c.History) > 0 {
+		c.History = append([]CommentRevision(nil), c.History...)
+		c.History[0].Message = ""
+		c.History[0].blobMessage = message
+	}
+
+	return c
+}
+
+// withLoadedMessage return a copy of the comment holding its messages, to be
+// serialized
+func (c Comment) withLoadedMessage() Comment {
+	c.SetMessage(c.GetMessage())
+
+	c.History = append([]CommentRevision(nil), c.History...)
+	for i, revision := range c.History {
+		c.History[i].Message = revision.GetMessage()
+		c.History[i].blobMessage = nil
+	}
+
+	return c
+}
+
+// OriginalMessage return the message of the comment, even if it has been
+// deleted
+func (c Comment) OriginalMessage() string {
+	if len(c.History) == 0 {
+		return c.GetMessage()
+	}
+
+	return c.History[len(c.History)-1].GetMessage()
+}
+
+// CommentRevision is a version of the message of a comment
+type CommentRevision struct {
+	Author Person
+	// The message, empty if it's stored in its own blob, see GetMessage
+	Message  string
+	UnixTime int64
+
+	blobMessage *BlobMessage
+}
+
+// GetMessage return the message of the revision, reading it from its blob
+// if needed
+func (r CommentRevision) GetMessage() string {
+	if r.blobMessage != nil {
+		return r.blobMessage.String()
+	}
+	return r.Message
+}
+
+// FormatTime format the UnixTime of the revision for human consumption
+func (r CommentRevision) FormatTime() string {
+	t := time.Unix(r.UnixTime, 0)
+	return humanize.Time(t)
 }
 
 // FormatTime format the UnixTime of the comment for human consumption
@@ -0,0 +1,145 @@
+package bug
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util"
+)
+
+// The configurations shared with the repository, like the label registry,
+// are stored as a JSON blob in a dedicated chain of commits, pushed and
+// pulled along with the bugs. Each commit hold a tree with a single entry.
+
+// readConfigRef decode the configuration stored in a ref, and return the
+// commit it has been read from. If the ref doesn't exist, v is left as is and
+// no commit is returned. The description name the configuration in the
+// errors.
+func readConfigRef(repo repository.Repo, ref string, entryName string, description string, v interface{}) (util.Hash, error) {
+	exist, err := repo.RefExist(ref)
+	if err != nil || !exist {
+		return "", err
+	}
+
+	commit, err := repo.ResolveRef(ref)
+	if err != nil {
+		return "", err
+	}
+
+	entries, err := repo.ListEntries(commit)
+	if err != nil {
+		return "", err
+	}
+
+	for _, entry := range entries {
+		if entry.Name != entryName {
+			continue
+		}
+
+		data, err := repo.ReadData(entry.Hash)
+		if err != nil {
+			return "", err
+		}
+
+		err = json.Unmarshal(data, v)
+		if err != nil {
+			return "", fmt.Errorf("invalid %s: %v", description, err)
+		}
+
+		return commit, nil
+	}
+
+	return "", fmt.Errorf("invalid %s: missing the %s entry", description, entryName)
+}
+
+// writeConfigRef store a configuration on top of the previous commit, if
+// any, and return the new commit
+func writeConfigRef(repo repository.Repo, ref string, entryName string, previous util.Hash, v interface{}) (util.Hash, error) {
+	treeHash, err := storeConfigTree(repo, entryName, v)
+	if err != nil {
+		return "", err
+	}
+
+	var commitHash util.Hash
+	if previous != "" {
+		commitHash, err = repo.StoreCommitWithParent(treeHash, previous)
+	} else {
+		commitHash, err = repo.StoreCommit(treeHash)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return commitHash, repo.UpdateRef(ref, commitHash)
+}
+
+func storeConfigTree(repo repository.Repo, entryName string, v interface{}) (util.Hash, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	blobHash, err := repo.StoreData(data)
+	if err != nil {
+		return "", err
+	}
+
+	return repo.StoreTree([]repository.TreeEntry{
+		{ObjectType: repository.Blob, Hash: blobHash, Name: entryName},
+	})
+}
+
+// mergeConfigRef merge a configuration fetched from a remote in the local
+// one. When both have been edited, they are joined by a merge commit holding
+// the tree returned by mergeTree, so that the local ref can still be pushed.
+func mergeConfigRef(repo repository.Repo, localRef string, remoteRef string, mergeTree func() (util.Hash, error)) error {
+	exist, err := repo.RefExist(remoteRef)
+	if err != nil || !exist {
+		return err
+	}
+
+	remoteHash, err := repo.ResolveRef(remoteRef)
+	if err != nil {
+		return err
+	}
+
+	localExist, err := repo.RefExist(localRef)
+	if err != nil {
+		return err
+	}
+
+	if !localExist {
+		return repo.UpdateRef(localRef, remoteHash)
+	}
+
+	localHash, err := repo.ResolveRef(localRef)
+	if err != nil {
+		return err
+	}
+
+	ancestor, err := repo.FindCommonAncestor(localHash, remoteHash)
+	if err != nil {
+		return err
+	}
+
+	switch ancestor {
+	case remoteHash:
+		// the remote edits are already merged
+		return nil
+	case localHash:
+		return repo.UpdateRef(localRef, remoteHash)
+	}
+
+	treeHash, err := mergeTree()
+	if err != nil {
+		return err
+	}
+
+	commitHash, err := repo.StoreMergeCommit(treeHash, localHash, remoteHash)
+	if err != nil {
+		return err
+	}
+
+	return repo.UpdateRef(localRef, commitHash)
+}
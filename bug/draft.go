@@ -0,0 +1,99 @@
+package bug
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+// ErrNotADraft is returned when publishing a bug which is not a draft
+var ErrNotADraft = errors.New("the bug is not a draft")
+
+// draftRefPrefix return the ref prefix of the drafts, like refs/drafts/bugs/.
+// These refs are out of the namespace, so the drafts are not listed with the
+// bugs, and never pushed nor fetched.
+func draftRefPrefix(repo repository.Repo) string {
+	return fmt.Sprintf("refs/drafts/%s/", RepoNamespace(repo))
+}
+
+// refPrefix return the ref prefix the bug is stored under
+func (bug *Bug) refPrefix(repo repository.Repo) string {
+	if bug.draft {
+		return draftRefPrefix(repo)
+	}
+	return localRefPrefix(repo)
+}
+
+// IsDraft tell if the bug is a local draft, not published yet
+func (bug *Bug) IsDraft() bool {
+	return bug.draft
+}
+
+// SaveDraft commit the pending operations of a new bug or of a draft as a
+// local draft, which stay out of the shared history until published. An
+// already published bug can't become a draft again.
+func (bug *Bug) SaveDraft(repo repository.Repo) error {
+	if !bug.draft && bug.lastCommit != "" {
+		return fmt.Errorf("the bug %s is already published", bug.HumanId())
+	}
+
+	bug.draft = true
+
+	err := bug.Commit(repo)
+	if err != nil && bug.lastCommit == "" {
+		bug.draft = false
+	}
+
+	return err
+}
+
+// Publish move a draft along with the other bugs, where it's listed and
+// pushed as any other bug. The pending operations, if any, are committed
+// first.
+func (bug *Bug) Publish(repo repository.Repo) error {
+	if !bug.draft {
+		return ErrNotADraft
+	}
+
+	if !bug.staging.IsEmpty() {
+		if err := bug.Commit(repo); err != nil {
+			return err
+		}
+	}
+
+	tx := repo.Begin()
+
+	if err := tx.UpdateRefFrom(localRefPrefix(repo)+bug.id, bug.lastCommit, repository.NoRef); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.DeleteRef(draftRefPrefix(repo) + bug.id); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	bug.draft = false
+
+	return nil
+}
+
+// ListDraftIds list the ids of the local drafts
+func ListDraftIds(repo repository.Repo) ([]string, error) {
+	return repo.ListIds(draftRefPrefix(repo))
+}
+
+// ReadDraft read a local draft from its id
+func ReadDraft(repo repository.Repo, id string) (*Bug, error) {
+	b, err := readBug(repo, draftRefPrefix(repo)+id)
+	if err != nil {
+		return nil, err
+	}
+
+	b.draft = true
+
+	return b, nil
+}
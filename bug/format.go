@@ -0,0 +1,201 @@
+package bug
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/dustin/go-humanize"
+)
+
+// FormatConfigPattern is the git config key of an output format defined by
+// the user, which take precedence over the built-in one of the same name
+const FormatConfigPattern = repository.PreferencePrefix + formatPreferencePattern
+
+const formatPreferencePattern = "format.%s"
+
+// BuiltinFormats are the output formats always available, by name
+var BuiltinFormats = map[string]string{
+	"oneline": `{{.Oneline}}`,
+	"full": `{{.Id}}
+title: {{.Title}}
+status: {{.Status}}
+priority: {{.Priority}}
+author: {{.Author.Name}} <{{.Author.Email}}>
+created: {{ago .CreatedAt}}
+edited: {{ago .LastEdit}}
+labels: {{join ", " .Labels}}
+comments: {{len .Comments}}
+`,
+	// stable and tab separated, meant for the scripts
+	"porcelain": `{{.Id}}	{{.Status}}	{{.Priority}}	{{.CreatedAt.Unix}}	{{.Author.Email}}	{{join "," .Labels}}	{{.Title}}`,
+}
+
+// the name given to the parsed templates, to recognize their errors
+const formatTemplateName = "format"
+
+// template: format:<line>[:<column>]: <message>
+var formatErrorRegexp = regexp.MustCompile(`^template: ` + formatTemplateName + `:(\d+)(?::(\d+))?: (?:executing "` + formatTemplateName + `" at )?(.*)$`)
+
+// FormatError is an error of an output format, at a position of its
+// template. The column is 0 when unknown, like for most of the syntax errors.
+type FormatError struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+func (e FormatError) Error() string {
+	if e.Column == 0 {
+		return fmt.Sprintf("template error at line %d: %s", e.Line, e.Message)
+	}
+	return fmt.Sprintf("template error at line %d, column %d: %s", e.Line, e.Column, e.Message)
+}
+
+// newFormatError locate an error of text/template in the template
+func newFormatError(err error) error {
+	match := formatErrorRegexp.FindStringSubmatch(err.Error())
+	if match == nil {
+		return err
+	}
+
+	line, _ := strconv.Atoi(match[1])
+	// text/template count the columns from 0
+	column := 0
+	if match[2] != "" {
+		column, _ = strconv.Atoi(match[2])
+		column++
+	}
+
+	return FormatError{Line: line, Column: column, Message: match[3]}
+}
+
+// OutputFormat render a Snapshot with a text/template, like
+// "{{.HumanId}} [{{.Status}}] {{.Title}}"
+type OutputFormat struct {
+	template *template.Template
+}
+
+// ParseOutputFormat parse the template of an output format
+func ParseOutputFormat(text string) (*OutputFormat, error) {
+	tmpl, err := template.New(formatTemplateName).Funcs(formatFuncs).Parse(text)
+	if err != nil {
+		return nil, newFormatError(err)
+	}
+
+	return &OutputFormat{template: tmpl}, nil
+}
+
+// LoadOutputFormat return the output format with the given name, defined in
+// the git config or built-in. A value that isn't a name is parsed as a
+// template.
+func LoadOutputFormat(repo repository.Repo, value string) (*OutputFormat, error) {
+	if !templateNameRegexp.MatchString(value) {
+		return ParseOutputFormat(value)
+	}
+
+	text, err := repository.GetUserPreference(repo, fmt.Sprintf(formatPreferencePattern, value))
+	if err == nil {
+		return ParseOutputFormat(text)
+	}
+	if err != repository.ErrNoConfigEntry {
+		return nil, err
+	}
+
+	if text, ok := BuiltinFormats[value]; ok {
+		return ParseOutputFormat(text)
+	}
+
+	return nil, fmt.Errorf("unknown format \"%s\", expected a template or one of %s",
+		value, strings.Join(BuiltinFormatNames(), ", "))
+}
+
+// BuiltinFormatNames return the names of the built-in output formats, sorted
+func BuiltinFormatNames() []string {
+	names := make([]string, 0, len(BuiltinFormats))
+	for name := range BuiltinFormats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Execute render the snapshot, followed by a line break if the template
+// doesn't end with one. Nothing is written if the rendering fails.
+func (f *OutputFormat) Execute(w io.Writer, snap *Snapshot) error {
+	var buf bytes.Buffer
+
+	if err := f.template.Execute(&buf, snap); err != nil {
+		return newFormatError(err)
+	}
+
+	if !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+		buf.WriteByte('\n')
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// the functions available in the templates, the value to transform given
+// last so that they can be used in a pipeline, like {{.Title | truncate 20}}
+var formatFuncs = template.FuncMap{
+	"ago":      formatAgo,
+	"truncate": formatTruncate,
+	"join":     formatJoin,
+}
+
+// formatAgo format a time relatively to now, like "3 days ago". A number is
+// a unix time, like the UnixTime of a comment.
+func formatAgo(value interface{}) (string, error) {
+	var t time.Time
+
+	switch v := value.(type) {
+	case time.Time:
+		t = v
+	case int64:
+		t = time.Unix(v, 0)
+	case int:
+		t = time.Unix(int64(v), 0)
+	default:
+		return "", fmt.Errorf("unexpected %T, expected a time", value)
+	}
+
+	return humanize.RelTime(t, now(), "ago", "from now"), nil
+}
+
+// formatTruncate shorten a text to the given number of characters, ending
+// with "..." when truncated
+func formatTruncate(length int, text string) string {
+	runes := []rune(text)
+	if len(runes) <= length {
+		return text
+	}
+	if length <= 3 {
+		return string(runes[:length])
+	}
+	return string(runes[:length-3]) + "..."
+}
+
+// formatJoin join the labels, or any list of texts, with a separator
+func formatJoin(sep string, value interface{}) (string, error) {
+	switch v := value.(type) {
+	case []Label:
+		texts := make([]string, len(v))
+		for i, label := range v {
+			texts[i] = string(label)
+		}
+		return strings.Join(texts, sep), nil
+	case []string:
+		return strings.Join(v, sep), nil
+	default:
+		return "", fmt.Errorf("unexpected %T, expected a list of texts", value)
+	}
+}
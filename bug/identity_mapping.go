@@ -0,0 +1,69 @@
+package bug
+
+import (
+	"fmt"
+
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util"
+)
+
+// IdentityMappingRefPattern is the ref storing the mapping of the users of
+// the other bug trackers to git-bug persons of a namespace, like
+// refs/git-bug/identity-mappings/bugs, see bridge.IdentityMapping. It's
+// pushed and pulled along with the bugs.
+const IdentityMappingRefPattern = "refs/git-bug/identity-mappings/%s"
+
+// IdentityMappingEntryName is the name of the entry holding the mapping, as
+// a JSON object of the Person of each login
+const IdentityMappingEntryName = "mapping"
+
+const remoteIdentityMappingRefPattern = "refs/remotes/%s/git-bug/identity-mappings/%s"
+
+// IdentityMappingRef return the ref storing the identity mapping of the
+// namespace of the repository
+func IdentityMappingRef(repo repository.Repo) string {
+	return fmt.Sprintf(IdentityMappingRefPattern, RepoNamespace(repo))
+}
+
+func remoteIdentityMappingRef(repo repository.Repo, remote string) string {
+	return fmt.Sprintf(remoteIdentityMappingRefPattern, remote, RepoNamespace(repo))
+}
+
+// identityMappingFetchRefSpec fetch the identity mappings of every
+// namespace, as a pattern doesn't fail when the remote has none
+func identityMappingFetchRefSpec(remote string) string {
+	return fmt.Sprintf("+%s:%s",
+		fmt.Sprintf(IdentityMappingRefPattern, "*"),
+		fmt.Sprintf(remoteIdentityMappingRefPattern, remote, "*"),
+	)
+}
+
+// mergeIdentityMapping merge the identity mapping fetched from a remote in
+// the local one. When both have been edited, the logins are joined, the
+// local mapping winning for the logins mapped on both sides.
+func mergeIdentityMapping(repo repository.Repo, remote string) error {
+	localRef := IdentityMappingRef(repo)
+	remoteRef := remoteIdentityMappingRef(repo, remote)
+
+	return mergeConfigRef(repo, localRef, remoteRef, func() (util.Hash, error) {
+		local := make(map[string]Person)
+		_, err := readConfigRef(repo, localRef, IdentityMappingEntryName, "identity mapping", &local)
+		if err != nil {
+			return "", err
+		}
+
+		other := make(map[string]Person)
+		_, err = readConfigRef(repo, remoteRef, IdentityMappingEntryName, "identity mapping", &other)
+		if err != nil {
+			return "", err
+		}
+
+		for login, person := range other {
+			if _, ok := local[login]; !ok {
+				local[login] = person
+			}
+		}
+
+		return storeConfigTree(repo, IdentityMappingEntryName, local)
+	})
+}
@@ -0,0 +1,93 @@
+package bug
+
+import (
+	"fmt"
+
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util"
+)
+
+// ErrIncompleteHistory is returned when a bug can't be read because its
+// history is incomplete in the repository: cut by a shallow clone, or with
+// objects left out of a partial clone. Unlike a corruption, fetching the
+// missing history fix it.
+type ErrIncompleteHistory struct {
+	// Id of the bug
+	Id string
+	// The commit at the shallow boundary, or the missing object
+	Missing util.Hash
+	// Shallow tell if the history is cut by a shallow clone
+	Shallow bool
+}
+
+func (e *ErrIncompleteHistory) Error() string {
+	if e.Shallow {
+		return fmt.Sprintf("bug %s: incomplete history, the shallow clone stop at commit %s", e.Id, e.Missing)
+	}
+	return fmt.Sprintf("bug %s: incomplete history, object %s is missing", e.Id, e.Missing)
+}
+
+// Hint suggest how to fetch the missing history
+func (e *ErrIncompleteHistory) Hint() string {
+	if e.Shallow {
+		return "run \"git fetch --unshallow\" to fetch the complete history"
+	}
+	return "fetch the missing objects, or adjust the filter of the partial clone"
+}
+
+// The partial clones record the remote that can provide the missing objects
+const partialCloneConfigKey = "extensions.partialclone"
+
+// isShallowBoundary tell if a commit is at the boundary of a shallow clone,
+// its parents being missing
+func isShallowBoundary(repo repository.Repo, hash util.Hash) (bool, error) {
+	shallowCommits, err := repo.ShallowCommits()
+	if err != nil {
+		return false, err
+	}
+
+	for _, shallow := range shallowCommits {
+		if shallow == hash {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// checkFirstCommit return an ErrIncompleteHistory if the first commit
+// readable of a bug is not its actual first commit, which hold the root pack,
+// because a shallow clone stop there
+func checkFirstCommit(repo repository.Repo, id string, hash util.Hash, opsPack util.Hash, rootPack util.Hash) error {
+	if opsPack == rootPack {
+		return nil
+	}
+
+	boundary, err := isShallowBoundary(repo, hash)
+	if err != nil {
+		return err
+	}
+
+	if boundary {
+		return &ErrIncompleteHistory{Id: id, Missing: hash, Shallow: true}
+	}
+
+	return nil
+}
+
+// readError explain the failure to read an object of a bug. In a shallow or
+// partial clone, the object is expected to be missing rather than corrupted.
+func readError(repo repository.Repo, id string, hash util.Hash, err error) error {
+	shallowCommits, shallowErr := repo.ShallowCommits()
+	if shallowErr != nil {
+		return err
+	}
+
+	_, partialErr := repo.GetConfig(partialCloneConfigKey)
+
+	if len(shallowCommits) == 0 && partialErr != nil {
+		return err
+	}
+
+	return &ErrIncompleteHistory{Id: id, Missing: hash, Shallow: len(shallowCommits) > 0}
+}
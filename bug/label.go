@@ -3,6 +3,11 @@ package bug
 import (
 	"fmt"
 	"io"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/MichaelMure/git-bug/util"
 )
 
 type Label string
@@ -11,6 +16,35 @@ func (l Label) String() string {
 	return string(l)
 }
 
+// NormalizeLabel trim the whitespaces around a label, and reject the empty
+// ones and the ones holding control characters, like line breaks
+func NormalizeLabel(label string) (Label, error) {
+	label = strings.TrimSpace(label)
+
+	if label == "" {
+		return "", fmt.Errorf("empty label")
+	}
+
+	for _, r := range label {
+		if unicode.IsControl(r) {
+			return "", fmt.Errorf("invalid label %q: control character", label)
+		}
+	}
+
+	return Label(label), nil
+}
+
+// ContainsLabel tell if the snapshot has exactly this label, case included
+func (snap Snapshot) ContainsLabel(label string) bool {
+	for _, l := range snap.Labels {
+		if string(l) == label {
+			return true
+		}
+	}
+
+	return false
+}
+
 // UnmarshalGQL implements the graphql.Unmarshaler interface
 func (l *Label) UnmarshalGQL(v interface{}) error {
 	_, ok := v.(string)
@@ -27,3 +61,84 @@ func (l *Label) UnmarshalGQL(v interface{}) error {
 func (l Label) MarshalGQL(w io.Writer) {
 	w.Write([]byte(`"` + l.String() + `"`))
 }
+
+// The labels of a snapshot are an observed-remove set: each addition of a
+// label is identified by the operation adding it, and a removal only cancel
+// the additions it observed. A concurrent addition is then kept, whatever
+// the order the operations are applied in, and every clone converge to the
+// same labels.
+
+// AddLabel add a label to the snapshot, the addition being identified by
+// the hash of the operation
+func (snap *Snapshot) AddLabel(label Label, addition util.Hash) {
+	if snap.labelRemoved[addition] {
+		return
+	}
+
+	if snap.labelAdds == nil {
+		snap.labelAdds = make(map[Label][]util.Hash)
+	}
+
+	for _, existing := range snap.labelAdds[label] {
+		if existing == addition {
+			return
+		}
+	}
+
+	snap.labelAdds[label] = append(snap.labelAdds[label], addition)
+	snap.updateLabels()
+}
+
+// RemoveLabel cancel the given additions of a label. Without any, as for
+// the operations written before the additions were tracked, all the
+// additions applied so far are canceled.
+func (snap *Snapshot) RemoveLabel(label Label, observed []util.Hash) {
+	if len(observed) == 0 {
+		observed = snap.labelAdds[label]
+	}
+
+	if snap.labelRemoved == nil {
+		snap.labelRemoved = make(map[util.Hash]bool)
+	}
+
+	for _, addition := range observed {
+		snap.labelRemoved[addition] = true
+	}
+
+	var kept []util.Hash
+	for _, addition := range snap.labelAdds[label] {
+		if !snap.labelRemoved[addition] {
+			kept = append(kept, addition)
+		}
+	}
+
+	if len(kept) > 0 {
+		snap.labelAdds[label] = kept
+	} else {
+		delete(snap.labelAdds, label)
+	}
+
+	snap.updateLabels()
+}
+
+// LabelAdditions return the additions of a label in effect in the snapshot,
+// to be observed by a removal
+func (snap Snapshot) LabelAdditions(label Label) []util.Hash {
+	return snap.labelAdds[label]
+}
+
+// updateLabels derive the sorted labels from the additions in effect
+func (snap *Snapshot) updateLabels() {
+	// a new slice, as the previous one can be shared with other snapshots
+	var labels []Label
+
+	for label := range snap.labelAdds {
+		labels = append(labels, label)
+	}
+
+	sort.Slice(labels, func(i, j int) bool {
+		return labels[i] < labels[j]
+	})
+
+	snap.Labels = labels
+}
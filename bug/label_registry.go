@@ -0,0 +1,223 @@
+package bug
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util"
+)
+
+// LabelRegistryRefPattern is the ref storing the label registry of a
+// namespace, like refs/git-bug/labels/bugs. It's pushed and pulled along
+// with the bugs.
+const LabelRegistryRefPattern = "refs/git-bug/labels/%s"
+
+const labelRegistryEntryName = "labels"
+
+const remoteLabelRegistryRefPattern = "refs/remotes/%s/git-bug/labels/%s"
+
+// A color is given in the #rrggbb format
+var labelColorRegexp = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// LabelInfo is the optional metadata of a label. An empty field is unset.
+type LabelInfo struct {
+	Color       string `json:"color,omitempty"`
+	Description string `json:"description,omitempty"`
+
+	// Time order the edits of a label: it's higher than the time of every
+	// label known when the edit is made, so that the last edit win when two
+	// versions of the registry are merged. UnixTime is only used to settle
+	// concurrent edits.
+	Time     uint64 `json:"time"`
+	UnixTime int64  `json:"unix_time"`
+}
+
+// LabelRegistry hold the metadata of the labels of the repository, like
+// their color. Labels are usable without being registered.
+//
+// The registry is stored in git as a JSON blob referenced by a dedicated
+// chain of commits. Concurrent edits from two clones are merged label by
+// label, the last edit winning.
+type LabelRegistry struct {
+	labels map[Label]LabelInfo
+
+	// the commit the registry has been read from, if any
+	lastCommit util.Hash
+}
+
+func labelRegistryRef(repo repository.Repo) string {
+	return fmt.Sprintf(LabelRegistryRefPattern, RepoNamespace(repo))
+}
+
+func remoteLabelRegistryRef(repo repository.Repo, remote string) string {
+	return fmt.Sprintf(remoteLabelRegistryRefPattern, remote, RepoNamespace(repo))
+}
+
+// labelRegistryFetchRefSpec fetch the registries of every namespace, as a
+// pattern doesn't fail when the remote has none
+func labelRegistryFetchRefSpec(remote string) string {
+	return fmt.Sprintf("+%s:%s",
+		fmt.Sprintf(LabelRegistryRefPattern, "*"),
+		fmt.Sprintf(remoteLabelRegistryRefPattern, remote, "*"),
+	)
+}
+
+// ValidateLabelColor check that a color is in the #rrggbb format, or empty
+func ValidateLabelColor(color string) error {
+	if color != "" && !labelColorRegexp.MatchString(color) {
+		return fmt.Errorf("invalid color \"%s\", expected #rrggbb", color)
+	}
+	return nil
+}
+
+// ReadLabelRegistry read the label registry stored in the repository. If
+// none has been stored yet, an empty registry is returned.
+func ReadLabelRegistry(repo repository.Repo) (*LabelRegistry, error) {
+	return readLabelRegistry(repo, labelRegistryRef(repo))
+}
+
+func readLabelRegistry(repo repository.Repo, ref string) (*LabelRegistry, error) {
+	registry := &LabelRegistry{
+		labels: make(map[Label]LabelInfo),
+	}
+
+	var err error
+	registry.lastCommit, err = readConfigRef(repo, ref, labelRegistryEntryName, "label registry", &registry.labels)
+	if err != nil {
+		return nil, err
+	}
+
+	// the registry may come from a remote: an edit with an invalid color is
+	// dropped
+	for label, info := range registry.labels {
+		if ValidateLabelColor(info.Color) != nil {
+			delete(registry.labels, label)
+		}
+	}
+
+	return registry, nil
+}
+
+// LastCommitHash return the commit the registry has been read from, or
+// nothing if none has been stored yet
+func (reg *LabelRegistry) LastCommitHash() util.Hash {
+	return reg.lastCommit
+}
+
+// Lookup return the metadata of a label, if any
+func (reg *LabelRegistry) Lookup(label Label) (LabelInfo, bool) {
+	info, ok := reg.labels[label]
+	if ok && info.Color == "" && info.Description == "" {
+		return LabelInfo{}, false
+	}
+	return info, ok
+}
+
+// Labels return the labels having some metadata, sorted
+func (reg *LabelRegistry) Labels() []Label {
+	var labels []Label
+	for label := range reg.labels {
+		if _, ok := reg.Lookup(label); ok {
+			labels = append(labels, label)
+		}
+	}
+
+	sort.Slice(labels, func(i, j int) bool {
+		return labels[i] < labels[j]
+	})
+
+	return labels
+}
+
+// Edit set the color and the description of a label. An empty value unset
+// them. The label is kept in the registry, so that the edit also win over
+// the older ones when merged.
+func (reg *LabelRegistry) Edit(label Label, color string, description string) error {
+	if err := ValidateLabelColor(color); err != nil {
+		return err
+	}
+
+	var maxTime uint64
+	for _, info := range reg.labels {
+		if info.Time > maxTime {
+			maxTime = info.Time
+		}
+	}
+
+	reg.labels[label] = LabelInfo{
+		Color:       strings.ToLower(color),
+		Description: strings.Join(strings.Fields(description), " "),
+		Time:        maxTime + 1,
+		UnixTime:    time.Now().Unix(),
+	}
+
+	return nil
+}
+
+// newer tell if an edit of a label win over another one
+func (info LabelInfo) newer(other LabelInfo) bool {
+	if info.Time != other.Time {
+		return info.Time > other.Time
+	}
+	if info.UnixTime != other.UnixTime {
+		return info.UnixTime > other.UnixTime
+	}
+	// any order, as long as every clone pick the same one
+	if info.Color != other.Color {
+		return info.Color > other.Color
+	}
+	return info.Description > other.Description
+}
+
+// merge add the edits of another registry, keeping the last edit of each
+// label. An edit with an invalid color is dropped.
+func (reg *LabelRegistry) merge(other *LabelRegistry) {
+	for label, info := range other.labels {
+		if ValidateLabelColor(info.Color) != nil {
+			continue
+		}
+		local, ok := reg.labels[label]
+		if !ok || info.newer(local) {
+			reg.labels[label] = info
+		}
+	}
+}
+
+// Write store the label registry in the repository
+func (reg *LabelRegistry) Write(repo repository.Repo) error {
+	commitHash, err := writeConfigRef(repo, labelRegistryRef(repo), labelRegistryEntryName, reg.lastCommit, reg.labels)
+	if err != nil {
+		return err
+	}
+
+	reg.lastCommit = commitHash
+
+	return nil
+}
+
+// mergeLabelRegistry merge the label registry fetched from a remote in the
+// local one, label by label
+func mergeLabelRegistry(repo repository.Repo, remote string) error {
+	localRef := labelRegistryRef(repo)
+	remoteRef := remoteLabelRegistryRef(repo, remote)
+
+	return mergeConfigRef(repo, localRef, remoteRef, func() (util.Hash, error) {
+		local, err := readLabelRegistry(repo, localRef)
+		if err != nil {
+			return "", err
+		}
+
+		other, err := readLabelRegistry(repo, remoteRef)
+		if err != nil {
+			return "", err
+		}
+
+		local.merge(other)
+
+		return storeConfigTree(repo, labelRegistryEntryName, local.labels)
+	})
+}
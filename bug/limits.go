@@ -0,0 +1,208 @@
+package bug
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util"
+)
+
+// Limits bound the size of the operations, so that the ones written by a
+// malicious or buggy client are rejected before exhausting the memory. A
+// zero limit disable the check.
+//
+// The limits in effect, which can be configured, are enforced when the
+// operations are written and when they are received from a remote. The
+// stored operations are only bound by HardLimits when read, so that lowering
+// a limit doesn't make the existing bugs unreadable, and the clones with
+// different limits read the same bugs.
+type Limits struct {
+	// The longest message of a comment, in bytes
+	MaxCommentLength int
+	// The most labels added and removed by an operation
+	MaxLabelsPerOperation int
+	// The most operations in an OperationPack
+	MaxOperationsPerPack int
+}
+
+// DefaultLimits are the limits applied unless configured otherwise, well
+// above what a legitimate client write
+var DefaultLimits = Limits{
+	MaxCommentLength:      1024 * 1024,
+	MaxLabelsPerOperation: 100,
+	MaxOperationsPerPack:  10000,
+}
+
+// HardLimits is the fixed ceiling applied when reading the stored operations,
+// whatever the configured limits
+var HardLimits = Limits{
+	MaxCommentLength:      64 * 1024 * 1024,
+	MaxLabelsPerOperation: 10000,
+	MaxOperationsPerPack:  1000000,
+}
+
+// The git config keys overriding the default limits
+const (
+	MaxCommentLengthConfigKey      = repository.PreferencePrefix + maxCommentLengthPreference
+	MaxLabelsPerOperationConfigKey = repository.PreferencePrefix + maxLabelsPerOperationPreference
+	MaxOperationsPerPackConfigKey  = repository.PreferencePrefix + maxOperationsPerPackPreference
+)
+
+const (
+	maxCommentLengthPreference      = "limits.comment-length"
+	maxLabelsPerOperationPreference = "limits.labels-per-operation"
+	maxOperationsPerPackPreference  = "limits.operations-per-pack"
+)
+
+// the limits in effect, see SetLimits
+var limits = DefaultLimits
+
+// SetLimits change the limits applied to the operations written and received
+func SetLimits(l Limits) {
+	limits = l
+}
+
+// CurrentLimits return the limits in effect
+func CurrentLimits() Limits {
+	return limits
+}
+
+// LoadLimits apply the limits configured in the repository, the default
+// ones otherwise
+func LoadLimits(repo repository.Repo) error {
+	l := DefaultLimits
+
+	values := []struct {
+		preference string
+		limit      *int
+	}{
+		{maxCommentLengthPreference, &l.MaxCommentLength},
+		{maxLabelsPerOperationPreference, &l.MaxLabelsPerOperation},
+		{maxOperationsPerPackPreference, &l.MaxOperationsPerPack},
+	}
+
+	for _, v := range values {
+		value, err := repository.GetUserPreference(repo, v.preference)
+		if err == repository.ErrNoConfigEntry {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 0 {
+			return fmt.Errorf("invalid value for %s%s: %s", repository.PreferencePrefix, v.preference, value)
+		}
+		*v.limit = limit
+	}
+
+	SetLimits(l)
+	return nil
+}
+
+// ErrLimitExceeded is returned for an operation or an OperationPack above
+// one of the Limits
+type ErrLimitExceeded struct {
+	// The name of the limit, like "comment-length"
+	Limit string
+	Max   int
+	Value int
+}
+
+func (e *ErrLimitExceeded) Error() string {
+	return fmt.Sprintf("%s limit exceeded: %d, at most %d", e.Limit, e.Value, e.Max)
+}
+
+func checkLimit(name string, max int, value int) error {
+	if max > 0 && value > max {
+		return &ErrLimitExceeded{Limit: name, Max: max, Value: value}
+	}
+	return nil
+}
+
+// LabelOperation is implemented by the operations changing the labels, so
+// that their number is bounded
+type LabelOperation interface {
+	Operation
+	// LabelCount return the number of labels added and removed
+	LabelCount() int
+}
+
+// CheckLimits make sure that an operation is within the limits in effect.
+// The message of an operation stored in its own blob is not read: the size
+// of the blob is checked instead when the pack is verified, and before the
+// message is loaded.
+func CheckLimits(op Operation) error {
+	return checkOperationLimits(op, limits)
+}
+
+func checkOperationLimits(op Operation, l Limits) error {
+	if messageOp, ok := op.(MessageBlobOperation); ok && messageOp.GetMessageBlob() == "" {
+		err := checkLimit("comment-length", l.MaxCommentLength, len(messageOp.GetMessage()))
+		if err != nil {
+			return err
+		}
+	}
+
+	if labelOp, ok := op.(LabelOperation); ok {
+		err := checkLimit("labels-per-operation", l.MaxLabelsPerOperation, labelOp.LabelCount())
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkOperationCount make sure that a pack doesn't hold too many operations
+func checkOperationCount(count int, l Limits) error {
+	return checkLimit("operations-per-pack", l.MaxOperationsPerPack, count)
+}
+
+// checkPackSize make sure that a pack doesn't hold too many operations nor
+// message blobs above the comment length limit, with the limits in effect
+func checkPackSize(repo repository.Repo, pack OperationPack) error {
+	if err := checkOperationCount(len(pack.Operations), limits); err != nil {
+		return err
+	}
+
+	for _, blob := range pack.messageBlobs() {
+		if err := checkMessageBlobSize(repo, blob, limits); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkReceivedLimits make sure that the packs of a version of the bug
+// received from a remote are within the limits in effect. The packs already
+// in the local version, if any, are accepted whatever the limits.
+func (bug *Bug) checkReceivedLimits(repo repository.Repo, local *Bug) error {
+	known := make(map[util.Hash]bool)
+	if local != nil {
+		for _, pack := range local.packs {
+			known[pack.commitHash] = true
+		}
+	}
+
+	for _, pack := range bug.packs {
+		if known[pack.commitHash] {
+			continue
+		}
+
+		if err := checkPackSize(repo, pack); err != nil {
+			return err
+		}
+
+		for _, op := range pack.Operations {
+			if err := CheckLimits(op); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+package bug
+
+import "github.com/MichaelMure/git-bug/util"
+
+// LogEntry is an operation of a bug, along with the commit it was stored in
+type LogEntry struct {
+	Operation Operation
+	Hash      util.Hash
+	// The Lamport edit time of the commit, 0 for a staged operation
+	EditTime util.LamportTime
+	// The commit holding the OperationPack of the operation, empty for a
+	// staged operation
+	Commit util.Hash
+}
+
+// Log return the operations of the bug in the order they are applied, with
+// the commit holding each of them
+func (bug *Bug) Log() ([]LogEntry, error) {
+	var entries []LogEntry
+
+	it := NewOperationIterator(bug)
+
+	for it.Next() {
+		op := it.Value()
+
+		hash, err := HashOperation(op)
+		if err != nil {
+			return nil, err
+		}
+
+		entry := LogEntry{
+			Operation: op,
+			Hash:      hash,
+		}
+
+		// the staging area come after the packs
+		if it.packIndex < len(bug.packs) {
+			pack := bug.packs[it.order[it.packIndex]]
+			entry.EditTime = pack.editTime
+			entry.Commit = pack.commitHash
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,184 @@
+package bug
+
+import (
+	"fmt"
+
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util"
+)
+
+// MergeStrategy tell how Merge join two versions of a bug that diverged.
+//
+// The strategies only differ by the history they write: whichever the
+// strategy, the operations of both sides are ordered by their Lamport time
+// when compiling the bug, see OperationIterator.
+type MergeStrategy string
+
+const (
+	// MergeStrategyRebase rewrite the local commits on top of the other
+	// version, keeping the history linear. It's the default, as older
+	// versions of git-bug can only read a linear history.
+	MergeStrategyRebase MergeStrategy = "rebase"
+
+	// MergeStrategyRebaseRemote rewrite the commits of the other version on
+	// top of the local ones instead, keeping the local commits. The result
+	// doesn't contain the remote commits, so Push replace the remote version
+	// with a lease, as long as it didn't change since the fetch. The
+	// operations already present on one side are not duplicated when
+	// rebasing.
+	MergeStrategyRebaseRemote MergeStrategy = "rebase-remote"
+
+	// MergeStrategyMerge keep both histories and join them with a merge
+	// commit, so that no commit is rewritten. The operations of both sides
+	// are interleaved by their Lamport time.
+	MergeStrategyMerge MergeStrategy = "merge"
+)
+
+// the other names of the strategies
+var mergeStrategyAliases = map[string]MergeStrategy{
+	"rebase-local": MergeStrategyRebase,
+	"interleave":   MergeStrategyMerge,
+}
+
+// MergeStrategyConfigKey is the git config key selecting the merge strategy
+// of the repository, "rebase", "rebase-remote" or "merge". "rebase-local" and
+// "interleave" are accepted as well, for "rebase" and "merge".
+const MergeStrategyConfigKey = repository.PreferencePrefix + mergeStrategyPreference
+
+const mergeStrategyPreference = "merge.strategy"
+
+// ParseMergeStrategy parse the name of a MergeStrategy, or one of its aliases
+func ParseMergeStrategy(value string) (MergeStrategy, error) {
+	if strategy, ok := mergeStrategyAliases[value]; ok {
+		return strategy, nil
+	}
+
+	switch strategy := MergeStrategy(value); strategy {
+	case MergeStrategyRebase, MergeStrategyRebaseRemote, MergeStrategyMerge:
+		return strategy, nil
+	default:
+		return "", fmt.Errorf("unknown merge strategy \"%s\", expected rebase, rebase-remote or merge", value)
+	}
+}
+
+func readMergeStrategy(repo repository.Repo) (MergeStrategy, error) {
+	value, err := repository.GetUserPreference(repo, mergeStrategyPreference)
+	if err == repository.ErrNoConfigEntry {
+		return MergeStrategyRebase, nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	strategy, err := ParseMergeStrategy(value)
+	if err != nil {
+		return "", fmt.Errorf("invalid value for %s: %s", MergeStrategyConfigKey, value)
+	}
+
+	return strategy, nil
+}
+
+// mergeHistories add the packs of the other version that are missing from
+// this one, and join both histories with a merge commit if they diverged
+func (bug *Bug) mergeHistories(repo repository.Repo, other *Bug, ancestor util.Hash) (bool, error) {
+	// the other version is already part of our history
+	if ancestor == other.lastCommit {
+		return false, nil
+	}
+
+	known := make(map[util.Hash]bool, len(bug.packs))
+	for _, pack := range bug.packs {
+		known[pack.commitHash] = true
+	}
+
+	var incoming []OperationPack
+	maxEditTime := other.editTime
+
+	for _, pack := range other.packs {
+		if known[pack.commitHash] {
+			continue
+		}
+
+		incoming = append(incoming, pack.Clone())
+
+		if pack.editTime > maxEditTime {
+			maxEditTime = pack.editTime
+		}
+	}
+
+	// Make sure that the local clocks are ahead of the incoming operations,
+	// so that the merge commit and the next local edits are ordered after them
+	if err := repo.CreateWitness(other.createTime); err != nil {
+		return false, err
+	}
+	if err := repo.EditWitness(maxEditTime); err != nil {
+		return false, err
+	}
+
+	lastCommit := other.lastCommit
+	editTime := other.editTime
+
+	// our history is not an ancestor of the other: both have diverged
+	diverged := ancestor != bug.lastCommit
+
+	if diverged {
+		var err error
+		lastCommit, editTime, err = bug.storeMergeCommit(repo, other.lastCommit)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	// Update the git ref
+	tx := repo.Begin()
+
+	err := tx.UpdateRefFrom(localRefPrefix(repo)+bug.id, lastCommit, bug.lastCommit)
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return false, err
+	}
+
+	// update the bug
+	bug.lastCommit = lastCommit
+	bug.editTime = editTime
+	bug.packs = append(bug.packs, incoming...)
+	bug.nonLinear = bug.nonLinear || other.nonLinear || diverged
+
+	return true, nil
+}
+
+// storeMergeCommit write a commit joining our history and the other one. Its
+// tree hold the root pack, a merge marker and the edit clock, but no
+// operation.
+func (bug *Bug) storeMergeCommit(repo repository.Repo, other util.Hash) (util.Hash, util.LamportTime, error) {
+	emptyBlobHash, err := repo.StoreData([]byte{})
+	if err != nil {
+		return "", 0, err
+	}
+
+	editTime, err := repo.EditTimeIncrement()
+	if err != nil {
+		return "", 0, err
+	}
+
+	tree, err := repo.StoreTree([]repository.TreeEntry{
+		{ObjectType: repository.Blob, Hash: bug.rootPack, Name: rootEntryName},
+		{ObjectType: repository.Blob, Hash: emptyBlobHash, Name: mergeEntryName},
+		{ObjectType: repository.Blob, Hash: emptyBlobHash, Name: fmt.Sprintf(editClockEntryPattern, editTime)},
+	})
+	if err != nil {
+		return "", 0, err
+	}
+
+	hash, err := repo.StoreMergeCommit(tree, bug.lastCommit, other)
+	if err != nil {
+		return "", 0, err
+	}
+
+	return hash, editTime, nil
+}
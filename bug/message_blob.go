@@ -0,0 +1,239 @@
+package bug
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util"
+)
+
+// MessageBlobThreshold is the size in bytes above which the message of an
+// operation is stored in its own git blob instead of the OperationPack, so
+// that reading a bug doesn't decode its large messages until they are needed
+const MessageBlobThreshold = 16 * 1024
+
+const messagesEntryName = "messages"
+const messageEntryPattern = "message%d"
+
+// MessageBlobOperation is implemented by the operations holding a message
+// that can be stored in its own git blob
+type MessageBlobOperation interface {
+	Operation
+	// GetMessage return the message, loading it from its blob if needed
+	GetMessage() string
+	// GetMessageBlob return the hash of the blob storing the message, or an
+	// empty hash if the message is held by the operation
+	GetMessageBlob() util.Hash
+	// WithMessageBlob return a copy of the operation with its message
+	// stored in the given blob
+	WithMessageBlob(blob util.Hash, message *BlobMessage) Operation
+}
+
+// BlobMessage is a message stored in a git blob, read on first use
+type BlobMessage struct {
+	once    sync.Once
+	load    func() (string, error)
+	message string
+}
+
+func newLoadedBlobMessage(message string) *BlobMessage {
+	m := &BlobMessage{message: message}
+	m.once.Do(func() {})
+	return m
+}
+
+func newLazyBlobMessage(repo repository.Repo, blob util.Hash) *BlobMessage {
+	return &BlobMessage{
+		load: func() (string, error) {
+			if err := checkMessageBlobSize(repo, blob, HardLimits); err != nil {
+				return "", err
+			}
+
+			data, err := repo.ReadData(blob)
+			if err != nil {
+				return "", err
+			}
+			return string(data), nil
+		},
+	}
+}
+
+// checkMessageBlobSize make sure that a message blob is within the comment
+// length limit, without reading it
+func checkMessageBlobSize(repo repository.Repo, blob util.Hash, l Limits) error {
+	size, err := repo.DataSize(blob)
+	if err != nil {
+		return err
+	}
+
+	return checkLimit("comment-length", l.MaxCommentLength, int(size))
+}
+
+// String return the message, or a placeholder if its blob can't be read
+func (m *BlobMessage) String() string {
+	if m == nil {
+		return ""
+	}
+
+	m.once.Do(func() {
+		message, err := m.load()
+		if err != nil {
+			message = fmt.Sprintf("[message unavailable: %s]", err)
+		}
+		m.message = message
+	})
+
+	return m.message
+}
+
+// storeLargeMessages move the messages above MessageBlobThreshold of the
+// operations of the pack in their own blob
+func (opp *OperationPack) storeLargeMessages(repo repository.Repo) error {
+	for i, op := range opp.Operations {
+		blobOp, ok := op.(MessageBlobOperation)
+		if !ok || blobOp.GetMessageBlob() != "" {
+			continue
+		}
+
+		message := blobOp.GetMessage()
+		if len(message) <= MessageBlobThreshold {
+			continue
+		}
+
+		hash, err := repo.StoreData([]byte(message))
+		if err != nil {
+			return err
+		}
+
+		opp.Operations[i] = blobOp.WithMessageBlob(hash, newLoadedBlobMessage(message))
+	}
+
+	return nil
+}
+
+// attachMessageBlobs make the operations of a freshly parsed pack read their
+// message from its blob, when it's actually needed
+func (opp *OperationPack) attachMessageBlobs(repo repository.Repo) {
+	for i, op := range opp.Operations {
+		blobOp, ok := op.(MessageBlobOperation)
+		if !ok || blobOp.GetMessageBlob() == "" {
+			continue
+		}
+
+		blob := blobOp.GetMessageBlob()
+		opp.Operations[i] = blobOp.WithMessageBlob(blob, newLazyBlobMessage(repo, blob))
+	}
+}
+
+// messageBlobs return the message blobs referenced by the operations of the
+// pack, in order and without duplicate
+func (opp *OperationPack) messageBlobs() []util.Hash {
+	var blobs []util.Hash
+	added := make(map[util.Hash]struct{})
+
+	for _, op := range opp.Operations {
+		blobOp, ok := op.(MessageBlobOperation)
+		if !ok || blobOp.GetMessageBlob() == "" {
+			continue
+		}
+
+		blob := blobOp.GetMessageBlob()
+		if _, has := added[blob]; !has {
+			blobs = append(blobs, blob)
+			added[blob] = struct{}{}
+		}
+	}
+
+	return blobs
+}
+
+// Like the media, the message blobs are referenced in the tree of the commit
+// so that git push and pull them along with the OperationPack
+func makeMessageTree(pack OperationPack) []repository.TreeEntry {
+	var tree []repository.TreeEntry
+
+	for i, blob := range pack.messageBlobs() {
+		tree = append(tree, repository.TreeEntry{
+			ObjectType: repository.Blob,
+			Hash:       blob,
+			Name:       fmt.Sprintf(messageEntryPattern, i),
+		})
+	}
+
+	return tree
+}
+
+// CheckMessageBlobs verify that the message blobs referenced by the committed
+// operations are in the tree of their commit and can be read, and that these
+// trees don't hold dangling message blobs referenced by no operation. It
+// return a description of each problem found.
+func (bug *Bug) CheckMessageBlobs(repo repository.Repo) ([]string, error) {
+	var problems []string
+
+	for _, pack := range bug.packs {
+		packProblems, err := checkPackMessageBlobs(repo, pack)
+		if err != nil {
+			return nil, err
+		}
+		problems = append(problems, packProblems...)
+	}
+
+	return problems, nil
+}
+
+// checkPackMessageBlobs check the message blobs of a single pack, read from
+// its commit
+func checkPackMessageBlobs(repo repository.Repo, pack OperationPack) ([]string, error) {
+	var problems []string
+
+	entries, err := repo.ListEntries(pack.commitHash)
+	if err != nil {
+		return nil, err
+	}
+
+	var inTree []repository.TreeEntry
+
+	for _, entry := range entries {
+		if entry.Name != messagesEntryName {
+			continue
+		}
+
+		inTree, err = repo.ListEntries(entry.Hash)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	referenced := make(map[util.Hash]bool)
+
+	for _, blob := range pack.messageBlobs() {
+		referenced[blob] = true
+
+		found := false
+		for _, entry := range inTree {
+			if entry.Hash == blob {
+				found = true
+				break
+			}
+		}
+		if !found {
+			problems = append(problems, fmt.Sprintf("commit %s: message blob %s is missing from the tree", pack.commitHash, blob))
+		}
+
+		err := checkMessageBlobSize(repo, blob, HardLimits)
+		if _, ok := err.(*ErrLimitExceeded); ok {
+			problems = append(problems, fmt.Sprintf("commit %s: message blob %s: %s", pack.commitHash, blob, err))
+		} else if err != nil {
+			problems = append(problems, fmt.Sprintf("commit %s: message blob %s can't be read: %s", pack.commitHash, blob, err))
+		}
+	}
+
+	for _, entry := range inTree {
+		if !referenced[entry.Hash] {
+			problems = append(problems, fmt.Sprintf("commit %s: dangling message blob %s", pack.commitHash, entry.Hash))
+		}
+	}
+
+	return problems, nil
+}
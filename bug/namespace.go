@@ -0,0 +1,142 @@
+package bug
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+// DefaultNamespace is the namespace used when none is configured, which
+// store the bugs under refs/bugs/
+const DefaultNamespace = "bugs"
+
+// NamespaceConfigKey is the git config key used to select the namespace
+const NamespaceConfigKey = repository.PreferencePrefix + namespacePreference
+
+const namespacePreference = "namespace"
+
+// namespaces that would collide with refs used by git itself or by git-bug
+// for other data
+var reservedNamespaces = map[string]bool{
+	"heads":   true,
+	"tags":    true,
+	"remotes": true,
+	"notes":   true,
+	"stash":   true,
+	"replace": true,
+	"git-bug": true,
+	"drafts":  true,
+}
+
+// A namespace end up as a single ref component. Slashes are rejected, as a
+// nested namespace would otherwise be listed along with its parent.
+var namespaceRegexp = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// the namespace currently in use, shared by every read and write of bugs
+var namespace = DefaultNamespace
+
+// Namespace return the namespace currently in use
+func Namespace() string {
+	return namespace
+}
+
+// ValidateNamespace check that a namespace can safely be used as ref prefix
+func ValidateNamespace(ns string) error {
+	if !namespaceRegexp.MatchString(ns) {
+		return fmt.Errorf("invalid namespace \"%s\"", ns)
+	}
+
+	if reservedNamespaces[ns] {
+		return fmt.Errorf("namespace \"%s\" is reserved", ns)
+	}
+
+	return nil
+}
+
+// SetNamespace change the namespace in use. Bugs in other namespaces are
+// invisible until the namespace is changed again.
+func SetNamespace(ns string) error {
+	if err := ValidateNamespace(ns); err != nil {
+		return err
+	}
+
+	namespace = ns
+	return nil
+}
+
+// LoadNamespace select the namespace configured in the repository, or the
+// default one if none is configured
+func LoadNamespace(repo repository.Repo) error {
+	ns, err := ConfiguredNamespace(repo)
+	if err != nil {
+		return err
+	}
+
+	return SetNamespace(ns)
+}
+
+// ConfiguredNamespace return the namespace configured in the repository, or
+// the default one if none is configured
+func ConfiguredNamespace(repo repository.Repo) (string, error) {
+	ns, err := repository.GetUserPreference(repo, namespacePreference)
+	if err == repository.ErrNoConfigEntry {
+		return DefaultNamespace, nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return ns, nil
+}
+
+// namespacer is implemented by the repositories with their own namespace
+type namespacer interface {
+	BugNamespace() string
+}
+
+// namespacedRepo is a repository with its own namespace, see WithNamespace
+type namespacedRepo struct {
+	repository.Repo
+
+	namespace string
+}
+
+func (r *namespacedRepo) BugNamespace() string {
+	return r.namespace
+}
+
+// WithNamespace return the repository reading and writing its bugs in the
+// given namespace, instead of the one in use. It let a process use several
+// repositories, each one with its own namespace.
+func WithNamespace(repo repository.Repo, ns string) (repository.Repo, error) {
+	if err := ValidateNamespace(ns); err != nil {
+		return nil, err
+	}
+
+	if r, ok := repo.(*namespacedRepo); ok {
+		repo = r.Repo
+	}
+
+	return &namespacedRepo{Repo: repo, namespace: ns}, nil
+}
+
+// RepoNamespace return the namespace of the bugs of the repository, the one
+// given with WithNamespace or else the one in use
+func RepoNamespace(repo repository.Repo) string {
+	if r, ok := repo.(namespacer); ok {
+		return r.BugNamespace()
+	}
+	return namespace
+}
+
+// localRefPrefix return the ref prefix of the local bugs, like refs/bugs/
+func localRefPrefix(repo repository.Repo) string {
+	return fmt.Sprintf("refs/%s/", RepoNamespace(repo))
+}
+
+// remoteRefPrefix return the ref prefix of the bugs of a remote, like
+// refs/remotes/origin/bugs/
+func remoteRefPrefix(repo repository.Repo, remote string) string {
+	return fmt.Sprintf("refs/remotes/%s/%s/", remote, RepoNamespace(repo))
+}
@@ -1,8 +1,12 @@
 package bug
 
 import (
-	"github.com/MichaelMure/git-bug/util"
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
 	"time"
+
+	"github.com/MichaelMure/git-bug/util"
 )
 
 // OperationType is an identifier
@@ -15,21 +19,86 @@ const (
 	AddCommentOp
 	SetStatusOp
 	LabelChangeOp
+	SetRelationOp
+	DeleteCommentOp
+	SubscribeOp
+	UnsubscribeOp
+	SetCustomFieldOp
+	AddTimeLogOp
+	SetPriorityOp
+	ArchiveCommentsOp
+	SetChecklistOp
+	RemapAuthorsOp
 )
 
+var operationTypeNames = map[OperationType]string{
+	CreateOp:          "create",
+	SetTitleOp:        "set-title",
+	AddCommentOp:      "add-comment",
+	SetStatusOp:       "set-status",
+	LabelChangeOp:     "label-change",
+	SetRelationOp:     "set-relation",
+	DeleteCommentOp:   "delete-comment",
+	SubscribeOp:       "subscribe",
+	UnsubscribeOp:     "unsubscribe",
+	SetCustomFieldOp:  "set-custom-field",
+	AddTimeLogOp:      "add-time-log",
+	SetPriorityOp:     "set-priority",
+	ArchiveCommentsOp: "archive-comments",
+	SetChecklistOp:    "set-checklist",
+	RemapAuthorsOp:    "remap-authors",
+}
+
+// String return the name of the operation type, like "set-title"
+func (t OperationType) String() string {
+	if name, ok := operationTypeNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("unknown-%d", int(t))
+}
+
 // Operation define the interface to fulfill for an edit operation of a Bug
 type Operation interface {
 	// OpType return the type of operation
 	OpType() OperationType
 	// Time return the time when the operation was added
 	Time() time.Time
+	// GetAuthor return the author of the operation
+	GetAuthor() Person
 	// Apply the operation to a Snapshot to create the final state
 	Apply(snapshot Snapshot) Snapshot
 	// Files return the files needed by this operation
 	Files() []util.Hash
+	// GetMetadata return the metadata of the operation for a key, if any
+	GetMetadata(key string) (string, bool)
+	// Validate check the data of the operation before it's committed
+	Validate() error
+	// Summary return a one-line description of the data of the operation
+	Summary() string
+}
+
+// HashOperation compute a hash of the content of an operation. It can be
+// used to identify and reference an operation. A redacted operation keep the
+// hash of the original one, so that the references to it stay valid. When a
+// bug is read, this claim is only kept for the operations listed in its
+// redactions, see verifyRedactionClaims.
+func HashOperation(op Operation) (util.Hash, error) {
+	if hash, ok := op.GetMetadata(RedactedMetadataKey); ok {
+		return util.Hash(hash), nil
+	}
+
+	return hashContent(op)
+}
+
+// hashContent compute the hash of the serialized operation, whatever its
+// metadata claim
+func hashContent(op Operation) (util.Hash, error) {
+	data, err := json.Marshal(op)
+	if err != nil {
+		return "", err
+	}
 
-	// TODO: data validation (ex: a title is a single line)
-	// Validate() bool
+	return util.Hash(fmt.Sprintf("%x", sha1.Sum(data))), nil
 }
 
 // OpBase implement the common code for all operations
@@ -37,6 +106,9 @@ type OpBase struct {
 	OperationType OperationType
 	Author        Person
 	UnixTime      int64
+	// Arbitrary data about the operation, like the id of the data it was
+	// imported from
+	Metadata map[string]string `json:",omitempty"`
 }
 
 // NewOpBase is the constructor for an OpBase
@@ -58,7 +130,43 @@ func (op OpBase) Time() time.Time {
 	return time.Unix(op.UnixTime, 0)
 }
 
+// GetAuthor return the author of the operation
+func (op OpBase) GetAuthor() Person {
+	return op.Author
+}
+
 // Files return the files needed by this operation
 func (op OpBase) Files() []util.Hash {
 	return nil
 }
+
+// Validate accept any operation, unless overridden by the operation
+func (op OpBase) Validate() error {
+	return nil
+}
+
+// GetMetadata return the metadata of the operation for a key, if any
+func (op OpBase) GetMetadata(key string) (string, bool) {
+	value, ok := op.Metadata[key]
+	return value, ok
+}
+
+// dropMetadata remove a metadata of the operation. The map is shared by the
+// copies of the operation, so that it's removed from the one held by a pack.
+func (op OpBase) dropMetadata(key string) {
+	delete(op.Metadata, key)
+}
+
+// metadataDropper is implemented by the operations embedding OpBase
+type metadataDropper interface {
+	dropMetadata(key string)
+}
+
+// SetMetadata define a metadata of the operation, before it's appended to
+// a bug
+func (op *OpBase) SetMetadata(key string, value string) {
+	if op.Metadata == nil {
+		op.Metadata = make(map[string]string)
+	}
+	op.Metadata[key] = value
+}
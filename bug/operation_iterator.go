@@ -1,7 +1,19 @@
 package bug
 
+import "sort"
+
+// OperationIterator walk the operations of a Bug in the order they should be
+// applied.
+//
+// Committed OperationPacks are ordered by their Lamport edit time. When two
+// packs share the same time (they were created concurrently), they are ordered
+// by the content id of their operations, so that every machine compile the
+// same snapshot regardless of the order of the chain of commits. The root pack
+// always comes first as it holds the CreateOp, and operations inside a pack
+// keep their relative order. The staging area, if any, comes last.
 type OperationIterator struct {
 	bug       *Bug
+	order     []int
 	packIndex int
 	opIndex   int
 }
@@ -9,11 +21,51 @@ type OperationIterator struct {
 func NewOperationIterator(bug *Bug) *OperationIterator {
 	return &OperationIterator{
 		bug:       bug,
+		order:     packsOrder(bug.packs),
 		packIndex: 0,
 		opIndex:   -1,
 	}
 }
 
+// packsOrder compute the order in which the packs should be iterated
+func packsOrder(packs []OperationPack) []int {
+	order := make([]int, len(packs))
+	for i := range packs {
+		order[i] = i
+	}
+
+	if len(order) < 2 {
+		return order
+	}
+
+	// the content ids are only needed to break a tie, so they are computed
+	// lazily and once per pack
+	ids := make([]*string, len(packs))
+	contentId := func(i int) string {
+		if ids[i] == nil {
+			// an error here would mean that the operations can't be
+			// serialized, in which case we just fallback on the chain order
+			id, _ := packs[i].contentId()
+			ids[i] = &id
+		}
+		return *ids[i]
+	}
+
+	rest := order[1:]
+
+	sort.SliceStable(rest, func(i, j int) bool {
+		pi, pj := packs[rest[i]], packs[rest[j]]
+
+		if pi.editTime != pj.editTime {
+			return pi.editTime < pj.editTime
+		}
+
+		return contentId(rest[i]) < contentId(rest[j])
+	})
+
+	return order
+}
+
 func (it *OperationIterator) Next() bool {
 	// Special case of the staging area
 	if it.packIndex == len(it.bug.packs) {
@@ -26,7 +78,7 @@ func (it *OperationIterator) Next() bool {
 		return false
 	}
 
-	pack := it.bug.packs[it.packIndex]
+	pack := it.bug.packs[it.order[it.packIndex]]
 
 	it.opIndex++
 
@@ -46,6 +98,12 @@ func (it *OperationIterator) Next() bool {
 	return it.packIndex < len(it.bug.packs)
 }
 
+// PackIndex return the position, in the iteration order, of the pack holding
+// the current operation. The staging area come last.
+func (it *OperationIterator) PackIndex() int {
+	return it.packIndex
+}
+
 func (it *OperationIterator) Value() Operation {
 	// Special case of the staging area
 	if it.packIndex == len(it.bug.packs) {
@@ -62,7 +120,7 @@ func (it *OperationIterator) Value() Operation {
 		panic("Iterator is not valid anymore")
 	}
 
-	pack := it.bug.packs[it.packIndex]
+	pack := it.bug.packs[it.order[it.packIndex]]
 
 	if it.opIndex >= len(pack.Operations) {
 		panic("Iterator is not valid anymore")
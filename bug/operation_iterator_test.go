@@ -0,0 +1,84 @@
+package bug
+
+import (
+	"reflect"
+	"testing"
+)
+
+type testOperation struct {
+	OpBase
+	Value string
+}
+
+func (op testOperation) Apply(snapshot Snapshot) Snapshot {
+	return snapshot
+}
+
+func (op testOperation) Summary() string {
+	return op.Value
+}
+
+func newTestOp(value string) testOperation {
+	return testOperation{
+		OpBase: OpBase{OperationType: SetTitleOp, UnixTime: 1},
+		Value:  value,
+	}
+}
+
+func iteratedValues(bug *Bug) []string {
+	var values []string
+	it := NewOperationIterator(bug)
+	for it.Next() {
+		values = append(values, it.Value().(testOperation).Value)
+	}
+	return values
+}
+
+func TestOpIteratorSameTimeOrdering(t *testing.T) {
+	root := OperationPack{
+		Operations: []Operation{newTestOp("root")},
+		editTime:   1,
+	}
+	packA := OperationPack{
+		Operations: []Operation{newTestOp("a1"), newTestOp("a2")},
+		editTime:   2,
+	}
+	packB := OperationPack{
+		Operations: []Operation{newTestOp("b1"), newTestOp("b2")},
+		editTime:   2,
+	}
+	last := OperationPack{
+		Operations: []Operation{newTestOp("last")},
+		editTime:   3,
+	}
+
+	bug1 := &Bug{packs: []OperationPack{root, packA, packB, last}}
+	bug2 := &Bug{packs: []OperationPack{root, packB, packA, last}}
+	bug3 := &Bug{packs: []OperationPack{root, last, packB, packA}}
+
+	values1 := iteratedValues(bug1)
+	values2 := iteratedValues(bug2)
+	values3 := iteratedValues(bug3)
+
+	if !reflect.DeepEqual(values1, values2) || !reflect.DeepEqual(values1, values3) {
+		t.Fatalf("order depend on the packs order: %v, %v, %v", values1, values2, values3)
+	}
+
+	if values1[0] != "root" || values1[len(values1)-1] != "last" {
+		t.Fatalf("unexpected order %v", values1)
+	}
+
+	idA, _ := packA.contentId()
+	idB, _ := packB.contentId()
+
+	var expected []string
+	if idA < idB {
+		expected = []string{"root", "a1", "a2", "b1", "b2", "last"}
+	} else {
+		expected = []string{"root", "b1", "b2", "a1", "a2", "last"}
+	}
+
+	if !reflect.DeepEqual(values1, expected) {
+		t.Fatalf("expected %v, got %v", expected, values1)
+	}
+}
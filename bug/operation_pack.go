@@ -2,7 +2,14 @@ package bug
 
 import (
 	"bytes"
+	"compress/gzip"
+	"crypto/sha1"
 	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
 
 	"github.com/MichaelMure/git-bug/repository"
 	"github.com/MichaelMure/git-bug/util"
@@ -19,22 +26,150 @@ type OperationPack struct {
 
 	// Private field so not serialized by gob
 	commitHash util.Hash
+	editTime   util.LamportTime
 }
 
-// ParseOperationPack will deserialize an OperationPack from raw bytes
-func ParseOperationPack(data []byte) (*OperationPack, error) {
+// CompressConfigKey is the git config key to store the new OperationPacks
+// compressed, with "true". As older versions of git-bug can't read them,
+// it's disabled by default.
+const CompressConfigKey = repository.PreferencePrefix + compressPreference
+
+const compressPreference = "compress"
+
+// A compressed OperationPack start with this marker, followed by the gzipped
+// serialized pack. The first byte of a gob stream is the non-zero length of
+// its first message, so the uncompressed packs are still recognized.
+var compressedPackMarker = []byte("\x00gz1")
+
+// The largest uncompressed OperationPack accepted, so that a small
+// malicious compressed pack can't exhaust the memory. The large messages are
+// stored in their own blob, so a legitimate pack stay well below.
+const maxPackSize = 64 * 1024 * 1024
+
+// Each encoded operation hold at least the name of its type, its type id, its
+// length and some content. A pack declaring more operations than its size
+// allows is rejected before decoding, instead of allocating for them.
+const minEncodedOperationSize = 8
+
+// ParseOperationPack will deserialize an OperationPack from raw bytes,
+// compressed or not. As the data can come from anyone through a pull, a
+// malformed pack is reported as an error, as well as a pack above
+// HardLimits.
+func ParseOperationPack(data []byte) (opp *OperationPack, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			opp = nil
+			err = fmt.Errorf("malformed operation pack: %v", r)
+		}
+	}()
+
+	if bytes.HasPrefix(data, compressedPackMarker) {
+		inflated, err := inflate(data[len(compressedPackMarker):])
+		if err != nil {
+			return nil, err
+		}
+		data = inflated
+	}
+
+	if count, ok := declaredOperationCount(data); ok {
+		if count > uint64(len(data)/minEncodedOperationSize) {
+			return nil, fmt.Errorf("malformed operation pack: %d operations declared in %d bytes", count, len(data))
+		}
+		// rejected before decoding the operations
+		if err := checkOperationCount(int(count), HardLimits); err != nil {
+			return nil, err
+		}
+	}
+
 	reader := bytes.NewReader(data)
 	decoder := gob.NewDecoder(reader)
 
-	var opp OperationPack
+	var parsed OperationPack
 
-	err := decoder.Decode(&opp)
+	err = decoder.Decode(&parsed)
 
 	if err != nil {
 		return nil, err
 	}
 
-	return &opp, nil
+	if !parsed.IsValid() {
+		return nil, fmt.Errorf("malformed operation pack: no operation")
+	}
+
+	if err := checkOperationCount(len(parsed.Operations), HardLimits); err != nil {
+		return nil, err
+	}
+
+	for i, op := range parsed.Operations {
+		if op == nil {
+			return nil, fmt.Errorf("malformed operation pack: operation %d is missing", i)
+		}
+		if err := checkOperationLimits(op, HardLimits); err != nil {
+			return nil, err
+		}
+	}
+
+	return &parsed, nil
+}
+
+// declaredOperationCount read the number of operations of a gob encoded pack
+// without decoding it. The type definitions come first, then the pack, whose
+// only field is the slice of operations. ok is false if the data can't be
+// read this way, the decoder then report the error.
+func declaredOperationCount(data []byte) (count uint64, ok bool) {
+	for len(data) > 0 {
+		length, n := gobUint(data)
+		if n == 0 || length > uint64(len(data)-n) {
+			return 0, false
+		}
+		message := data[n : n+int(length)]
+		data = data[n+int(length):]
+
+		typeId, n := gobUint(message)
+		if n == 0 {
+			return 0, false
+		}
+
+		// a negative type id is a type definition
+		if typeId&1 == 1 {
+			continue
+		}
+
+		// the field delta of the operations, absent for an empty pack
+		delta, m := gobUint(message[n:])
+		if m == 0 || delta != 1 {
+			return 0, true
+		}
+
+		count, m = gobUint(message[n+m:])
+		return count, m > 0
+	}
+
+	return 0, false
+}
+
+// gobUint read an unsigned integer as encoded by gob: a single byte below
+// 128, or else the negated byte count followed by the big-endian bytes. It
+// return the value and the number of bytes read, 0 if the data is truncated.
+func gobUint(data []byte) (uint64, int) {
+	if len(data) == 0 {
+		return 0, 0
+	}
+	if data[0] < 0x80 {
+		return uint64(data[0]), 1
+	}
+
+	size := -int(int8(data[0]))
+	if size > 8 || size >= len(data) {
+		return 0, 0
+	}
+
+	var value uint64
+	for _, b := range data[1 : size+1] {
+		value = value<<8 | uint64(b)
+	}
+
+	return value, size + 1
 }
 
 // Serialize will serialise an OperationPack into raw bytes
@@ -51,6 +186,48 @@ func (opp *OperationPack) Serialize() ([]byte, error) {
 	return data.Bytes(), nil
 }
 
+// SerializeCompressed will serialise an OperationPack into gzipped raw bytes,
+// prefixed by a marker
+func (opp *OperationPack) SerializeCompressed() ([]byte, error) {
+	data, err := opp.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	var compressed bytes.Buffer
+	compressed.Write(compressedPackMarker)
+
+	writer := gzip.NewWriter(&compressed)
+
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return compressed.Bytes(), nil
+}
+
+func inflate(data []byte) ([]byte, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	data, err = ioutil.ReadAll(io.LimitReader(reader, maxPackSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxPackSize {
+		return nil, fmt.Errorf("malformed operation pack: more than %d bytes once inflated", maxPackSize)
+	}
+
+	return data, nil
+}
+
 // Append a new operation to the pack
 func (opp *OperationPack) Append(op Operation) {
 	opp.Operations = append(opp.Operations, op)
@@ -66,10 +243,30 @@ func (opp *OperationPack) IsValid() bool {
 	return !opp.IsEmpty()
 }
 
-// Write will serialize and store the OperationPack as a git blob and return
-// its hash
-func (opp *OperationPack) Write(repo repository.Repo) (util.Hash, error) {
-	data, err := opp.Serialize()
+// contentId compute an identifier derived only from the operations of the
+// pack. Unlike the git hash of the serialized pack, it doesn't depend on the
+// encoder and is stable across machines.
+func (opp *OperationPack) contentId() (string, error) {
+	data, err := json.Marshal(opp.Operations)
+
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%x", sha1.Sum(data)), nil
+}
+
+// Write will serialize, optionally compressed, and store the OperationPack
+// as a git blob and return its hash
+func (opp *OperationPack) Write(repo repository.Repo, compress bool) (util.Hash, error) {
+	var data []byte
+	var err error
+
+	if compress {
+		data, err = opp.SerializeCompressed()
+	} else {
+		data, err = opp.Serialize()
+	}
 
 	if err != nil {
 		return "", err
@@ -84,12 +281,31 @@ func (opp *OperationPack) Write(repo repository.Repo) (util.Hash, error) {
 	return hash, nil
 }
 
+// readCompressConfig tell if the OperationPacks should be written compressed
+func readCompressConfig(repo repository.Repo) (bool, error) {
+	value, err := repository.GetUserPreference(repo, compressPreference)
+	if err == repository.ErrNoConfigEntry {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	compress, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid value for %s: %s", CompressConfigKey, value)
+	}
+
+	return compress, nil
+}
+
 // Make a deep copy
 func (opp *OperationPack) Clone() OperationPack {
 
 	clone := OperationPack{
 		Operations: make([]Operation, len(opp.Operations)),
 		commitHash: opp.commitHash,
+		editTime:   opp.editTime,
 	}
 
 	for i, op := range opp.Operations {
@@ -1,52 +1,112 @@
 package operations
 
 import (
+	"fmt"
+
 	"github.com/MichaelMure/git-bug/bug"
 	"github.com/MichaelMure/git-bug/util"
 )
 
 // AddCommentOperation will add a new comment in the bug
 
-var _ bug.Operation = AddCommentOperation{}
+var _ bug.MessageBlobOperation = AddCommentOperation{}
+var _ bug.Redactable = AddCommentOperation{}
 
 type AddCommentOperation struct {
 	bug.OpBase
 	Message string
+	// The blob storing the message when it's too large to be held by the
+	// operation, Message is then empty
+	MessageBlob util.Hash `json:",omitempty"`
+	// The files attached to the comment
 	// TODO: change for a map[string]util.hash to store the filename ?
-	files []util.Hash
+	Attachments []util.Hash `json:",omitempty"`
+	blobMessage *bug.BlobMessage
 }
 
 func (op AddCommentOperation) Apply(snapshot bug.Snapshot) bug.Snapshot {
+	// the message is only read from its blob when needed
 	comment := bug.Comment{
 		Message:  op.Message,
 		Author:   op.Author,
-		Files:    op.files,
+		Files:    op.Attachments,
 		UnixTime: op.UnixTime,
+		History: []bug.CommentRevision{
+			{Author: op.Author, Message: op.Message, UnixTime: op.UnixTime},
+		},
 	}
 
-	snapshot.Comments = append(snapshot.Comments, comment)
+	snapshot.Comments = append(snapshot.Comments, comment.WithBlobMessage(op.blobMessage))
 
 	return snapshot
 }
 
+// Summary return the first line of the message, and the number of files
+func (op AddCommentOperation) Summary() string {
+	summary := summarizeText(op.GetMessage())
+	if len(op.Attachments) > 0 {
+		summary = fmt.Sprintf("%s (%d files)", summary, len(op.Attachments))
+	}
+	return summary
+}
+
+// Redact replace the message, the attached files are kept
+func (op AddCommentOperation) Redact(hash util.Hash) bug.Operation {
+	op.OpBase = op.OpBase.RedactedBase(hash)
+	op.Message = bug.RedactionMarker
+	op.MessageBlob = ""
+	op.blobMessage = nil
+	return op
+}
+
 func (op AddCommentOperation) Files() []util.Hash {
-	return op.files
+	return op.Attachments
+}
+
+// Validate reject a message above the limit, see bug.Limits
+func (op AddCommentOperation) Validate() error {
+	return bug.CheckLimits(op)
+}
+
+func (op AddCommentOperation) GetMessage() string {
+	if op.MessageBlob == "" {
+		return op.Message
+	}
+	return op.blobMessage.String()
+}
+
+func (op AddCommentOperation) GetMessageBlob() util.Hash {
+	return op.MessageBlob
+}
+
+func (op AddCommentOperation) WithMessageBlob(blob util.Hash, message *bug.BlobMessage) bug.Operation {
+	op.Message = ""
+	op.MessageBlob = blob
+	op.blobMessage = message
+	return op
 }
 
 func NewAddCommentOp(author bug.Person, message string, files []util.Hash) AddCommentOperation {
 	return AddCommentOperation{
-		OpBase:  bug.NewOpBase(bug.AddCommentOp, author),
-		Message: message,
-		files:   files,
+		OpBase:      bug.NewOpBase(bug.AddCommentOp, author),
+		Message:     message,
+		Attachments: files,
 	}
 }
 
 // Convenience function to apply the operation
-func Comment(b *bug.Bug, author bug.Person, message string) {
-	CommentWithFiles(b, author, message, nil)
+func Comment(b *bug.Bug, author bug.Person, message string) error {
+	return CommentWithFiles(b, author, message, nil)
 }
 
-func CommentWithFiles(b *bug.Bug, author bug.Person, message string, files []util.Hash) {
+func CommentWithFiles(b *bug.Bug, author bug.Person, message string, files []util.Hash) error {
+	message, err := util.CleanupText(message)
+	if err != nil {
+		return err
+	}
+
 	addCommentOp := NewAddCommentOp(author, message, files)
 	b.Append(addCommentOp)
+
+	return nil
 }
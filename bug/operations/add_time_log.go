@@ -0,0 +1,76 @@
+package operations
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/util"
+)
+
+// AddTimeLogOperation will record some time spent on a bug. The logs are
+// only ever added, so the concurrent logs of several people simply sum up.
+
+var _ bug.Redactable = AddTimeLogOperation{}
+
+type AddTimeLogOperation struct {
+	bug.OpBase
+	Duration time.Duration
+	Note     string
+}
+
+func (op AddTimeLogOperation) Apply(snapshot bug.Snapshot) bug.Snapshot {
+	snapshot.TimeLogs = append(snapshot.TimeLogs, bug.TimeLog{
+		Author:   op.Author,
+		Duration: op.Duration,
+		Note:     op.Note,
+		UnixTime: op.UnixTime,
+	})
+
+	return snapshot
+}
+
+// Summary return the duration logged and its note
+func (op AddTimeLogOperation) Summary() string {
+	if op.Note == "" {
+		return op.Duration.String()
+	}
+	return fmt.Sprintf("%s: %s", op.Duration, summarizeText(op.Note))
+}
+
+// Redact replace the note
+func (op AddTimeLogOperation) Redact(hash util.Hash) bug.Operation {
+	op.OpBase = op.OpBase.RedactedBase(hash)
+	op.Note = bug.RedactionMarker
+	return op
+}
+
+func NewAddTimeLogOp(author bug.Person, duration time.Duration, note string) AddTimeLogOperation {
+	return AddTimeLogOperation{
+		OpBase:   bug.NewOpBase(bug.AddTimeLogOp, author),
+		Duration: duration,
+		Note:     note,
+	}
+}
+
+// Convenience function to apply the operation
+func AddTimeLog(b *bug.Bug, author bug.Person, duration time.Duration, note string) error {
+	if duration <= 0 {
+		return fmt.Errorf("invalid duration %s, it should be positive", duration)
+	}
+
+	note, err := util.CleanupText(note)
+	if err != nil {
+		return err
+	}
+
+	if strings.Contains(note, "\n") {
+		return fmt.Errorf("a time log note should be a single line")
+	}
+
+	addTimeLogOp := NewAddTimeLogOp(author, duration, strings.TrimSpace(note))
+	b.Append(addTimeLogOp)
+
+	return nil
+}
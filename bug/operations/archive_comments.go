@@ -0,0 +1,85 @@
+package operations
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util"
+)
+
+// ArchiveCommentsOperation will move the comments created before a cutoff out
+// of the snapshot, into a git blob. Only the comments stored in the blob are
+// archived: the ones merged later from a remote with an older time are kept,
+// until archived by the next operation.
+
+var _ bug.Operation = ArchiveCommentsOperation{}
+
+type ArchiveCommentsOperation struct {
+	bug.OpBase
+	// The comments created before this unix time were archived
+	Before int64
+	// The hashes of the archived comments, see Snapshot.CommentHashes
+	Comments []util.Hash
+	// The blob storing the archived comments
+	Archive util.Hash
+}
+
+func (op ArchiveCommentsOperation) Apply(snapshot bug.Snapshot) bug.Snapshot {
+	snapshot.ArchiveComments(op.Comments, op.Archive)
+
+	return snapshot
+}
+
+// Summary return the cutoff of the archived comments
+func (op ArchiveCommentsOperation) Summary() string {
+	return fmt.Sprintf("before %s", time.Unix(op.Before, 0).Format("2006-01-02 15:04:05"))
+}
+
+// Files reference the archive, so that it's pushed and pulled with the bug
+func (op ArchiveCommentsOperation) Files() []util.Hash {
+	return []util.Hash{op.Archive}
+}
+
+func (op ArchiveCommentsOperation) Validate() error {
+	if op.Archive == "" {
+		return errors.New("missing comment archive")
+	}
+	if len(op.Comments) == 0 {
+		return errors.New("no archived comment")
+	}
+	return nil
+}
+
+func NewArchiveCommentsOp(author bug.Person, before int64, comments []util.Hash, archive util.Hash) ArchiveCommentsOperation {
+	return ArchiveCommentsOperation{
+		OpBase:   bug.NewOpBase(bug.ArchiveCommentsOp, author),
+		Before:   before,
+		Comments: comments,
+		Archive:  archive,
+	}
+}
+
+// Convenience function to apply the operation. The comments to archive are
+// stored right away in a blob, and their number returned.
+func ArchiveComments(repo repository.Repo, b *bug.Bug, author bug.Person, before time.Time) (int, error) {
+	comments, hashes, err := b.Compile().ArchivedComments(before.Unix())
+	if err != nil {
+		return 0, err
+	}
+	if len(comments) == 0 {
+		return 0, errors.New("no comment to archive")
+	}
+
+	archive, err := bug.StoreArchive(repo, comments)
+	if err != nil {
+		return 0, err
+	}
+
+	archiveOp := NewArchiveCommentsOp(author, before.Unix(), hashes, archive)
+	b.Append(archiveOp)
+
+	return len(comments), nil
+}
@@ -0,0 +1,60 @@
+package operations
+
+import (
+	"fmt"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+// CreateAndCommit create a new bug and store it in git
+func CreateAndCommit(repo repository.Repo, author bug.Person, title, message string) (bug.Result, error) {
+	b, err := Create(author, title, message)
+	if err != nil {
+		return bug.Result{}, err
+	}
+
+	return commitWithResult(repo, b, "%s created")
+}
+
+// CommentAndCommit add a new comment to a bug and store it in git
+func CommentAndCommit(repo repository.Repo, b *bug.Bug, author bug.Person, message string) (bug.Result, error) {
+	err := Comment(b, author, message)
+	if err != nil {
+		return bug.Result{}, err
+	}
+
+	return commitWithResult(repo, b, "%s commented")
+}
+
+// OpenAndCommit mark a bug as open and store it in git
+func OpenAndCommit(repo repository.Repo, b *bug.Bug, author bug.Person) (bug.Result, error) {
+	Open(b, author)
+
+	return commitWithResult(repo, b, "%s opened")
+}
+
+// CloseAndCommit mark a bug as closed and store it in git
+func CloseAndCommit(repo repository.Repo, b *bug.Bug, author bug.Person) (bug.Result, error) {
+	Close(b, author)
+
+	return commitWithResult(repo, b, "%s closed")
+}
+
+func commitWithResult(repo repository.Repo, b *bug.Bug, format string) (bug.Result, error) {
+	err := b.Commit(repo)
+	if err != nil {
+		return bug.Result{}, err
+	}
+
+	id, err := b.IdSafe()
+	if err != nil {
+		return bug.Result{}, err
+	}
+
+	return bug.Result{
+		Id:      id,
+		Commit:  b.LastCommitHash(),
+		Message: fmt.Sprintf(format, b.HumanId()),
+	}, nil
+}
@@ -1,54 +1,123 @@
 package operations
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/MichaelMure/git-bug/bug"
 	"github.com/MichaelMure/git-bug/util"
 )
 
 // CreateOperation define the initial creation of a bug
 
-var _ bug.Operation = CreateOperation{}
+var _ bug.MessageBlobOperation = CreateOperation{}
+var _ bug.Redactable = CreateOperation{}
 
 type CreateOperation struct {
 	bug.OpBase
 	Title   string
 	Message string
-	files   []util.Hash
+	// The blob storing the message when it's too large to be held by the
+	// operation, Message is then empty
+	MessageBlob util.Hash `json:",omitempty"`
+	// The files attached to the comment
+	Attachments []util.Hash `json:",omitempty"`
+	blobMessage *bug.BlobMessage
 }
 
 func (op CreateOperation) Apply(snapshot bug.Snapshot) bug.Snapshot {
 	snapshot.Title = op.Title
-	snapshot.Comments = []bug.Comment{
-		{
-			Message:  op.Message,
-			Author:   op.Author,
-			UnixTime: op.UnixTime,
+
+	// the message is only read from its blob when needed
+	comment := bug.Comment{
+		Message:  op.Message,
+		Author:   op.Author,
+		Files:    op.Attachments,
+		UnixTime: op.UnixTime,
+		History: []bug.CommentRevision{
+			{Author: op.Author, Message: op.Message, UnixTime: op.UnixTime},
 		},
 	}
+
+	snapshot.Comments = []bug.Comment{comment.WithBlobMessage(op.blobMessage)}
 	snapshot.Author = op.Author
 	snapshot.CreatedAt = op.Time()
 	return snapshot
 }
 
+// Summary return the title and the first line of the message
+func (op CreateOperation) Summary() string {
+	return fmt.Sprintf("%q: %s", op.Title, summarizeText(op.GetMessage()))
+}
+
+// Redact replace the title and the message, the attached files are kept
+func (op CreateOperation) Redact(hash util.Hash) bug.Operation {
+	op.OpBase = op.OpBase.RedactedBase(hash)
+	op.Title = bug.RedactionMarker
+	op.Message = bug.RedactionMarker
+	op.MessageBlob = ""
+	op.blobMessage = nil
+	return op
+}
+
 func (op CreateOperation) Files() []util.Hash {
-	return op.files
+	return op.Attachments
+}
+
+// Validate reject a message above the limit, see bug.Limits
+func (op CreateOperation) Validate() error {
+	return bug.CheckLimits(op)
+}
+
+func (op CreateOperation) GetMessage() string {
+	if op.MessageBlob == "" {
+		return op.Message
+	}
+	return op.blobMessage.String()
+}
+
+func (op CreateOperation) GetMessageBlob() util.Hash {
+	return op.MessageBlob
+}
+
+func (op CreateOperation) WithMessageBlob(blob util.Hash, message *bug.BlobMessage) bug.Operation {
+	op.Message = ""
+	op.MessageBlob = blob
+	op.blobMessage = message
+	return op
 }
 
 func NewCreateOp(author bug.Person, title, message string, files []util.Hash) CreateOperation {
 	return CreateOperation{
-		OpBase:  bug.NewOpBase(bug.CreateOp, author),
-		Title:   title,
-		Message: message,
-		files:   files,
+		OpBase:      bug.NewOpBase(bug.CreateOp, author),
+		Title:       title,
+		Message:     message,
+		Attachments: files,
 	}
 }
 
+// ErrEmptyTitle is returned when a title is empty once normalized
+var ErrEmptyTitle = errors.New("empty title")
+
 // Convenience function to apply the operation
 func Create(author bug.Person, title, message string) (*bug.Bug, error) {
 	return CreateWithFiles(author, title, message, nil)
 }
 
 func CreateWithFiles(author bug.Person, title, message string, files []util.Hash) (*bug.Bug, error) {
+	title, err := util.CleanupText(title)
+	if err != nil {
+		return nil, err
+	}
+	if title == "" {
+		return nil, ErrEmptyTitle
+	}
+
+	message, err = util.CleanupText(message)
+	if err != nil {
+		return nil, err
+	}
+
 	newBug := bug.NewBug()
 	createOp := NewCreateOp(author, title, message, files)
 	newBug.Append(createOp)
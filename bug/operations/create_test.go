@@ -4,6 +4,7 @@ import (
 	"github.com/MichaelMure/git-bug/bug"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestCreate(t *testing.T) {
@@ -21,7 +22,14 @@ func TestCreate(t *testing.T) {
 	expected := bug.Snapshot{
 		Title: "title",
 		Comments: []bug.Comment{
-			{Author: rene, Message: "message", UnixTime: create.UnixTime},
+			{
+				Author:   rene,
+				Message:  "message",
+				UnixTime: create.UnixTime,
+				History: []bug.CommentRevision{
+					{Author: rene, Message: "message", UnixTime: create.UnixTime},
+				},
+			},
 		},
 		Author:    rene,
 		CreatedAt: create.Time(),
@@ -31,3 +39,87 @@ func TestCreate(t *testing.T) {
 		t.Fatalf("%v different than %v", snapshot, expected)
 	}
 }
+
+func TestCreateCleanupText(t *testing.T) {
+	var rene = bug.Person{
+		Name:  "René Descartes",
+		Email: "rene@descartes.fr",
+	}
+
+	b, err := Create(rene, "\ufefftitle  ", "message\r\nline2  \r\n\r\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	create := b.FirstOp().(CreateOperation)
+
+	if create.Title != "title" || create.Message != "message\nline2" {
+		t.Fatalf("text not normalized: %q %q", create.Title, create.Message)
+	}
+
+	_, err = Create(rene, "title", "Ren\xe9")
+	if err == nil {
+		t.Fatal("invalid UTF-8 should be rejected")
+	}
+
+	// the operation constructor is also used to build operations from raw data,
+	// and should not alter it
+	create = NewCreateOp(rene, "title  ", "message\r\n", nil)
+
+	if create.Title != "title  " || create.Message != "message\r\n" {
+		t.Fatalf("constructor should not normalize: %q %q", create.Title, create.Message)
+	}
+}
+
+func TestEmptyTitle(t *testing.T) {
+	var rene = bug.Person{
+		Name:  "René Descartes",
+		Email: "rene@descartes.fr",
+	}
+
+	_, err := Create(rene, "\ufeff  \r\n\r\n", "message")
+	if err != ErrEmptyTitle {
+		t.Fatalf("an empty title should be rejected, got %v", err)
+	}
+
+	b, err := Create(rene, "title", "message")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = SetTitle(b, rene, " \r\n")
+	if err != ErrEmptyTitle {
+		t.Fatalf("an empty title should be rejected, got %v", err)
+	}
+	if len(b.Compile().Operations) != 1 {
+		t.Fatal("no operation should be added")
+	}
+}
+
+func TestCleanupOtherTexts(t *testing.T) {
+	var rene = bug.Person{
+		Name:  "René Descartes",
+		Email: "rene@descartes.fr",
+	}
+
+	b, err := Create(rene, "title", "message")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := AddTimeLog(b, rene, time.Hour, "\ufeffreview\r\n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetCustomField(b, rene, "team", "core  \r\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	snap := b.Compile()
+	if snap.TimeLogs[0].Note != "review" || snap.CustomFields["team"] != "core" {
+		t.Fatalf("texts not normalized: %q %q", snap.TimeLogs[0].Note, snap.CustomFields["team"])
+	}
+
+	if AddTimeLog(b, rene, time.Hour, "Ren\xe9") == nil || SetCustomField(b, rene, "team", "Ren\xe9") == nil {
+		t.Fatal("invalid UTF-8 should be rejected")
+	}
+}
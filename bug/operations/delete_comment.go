@@ -0,0 +1,95 @@
+package operations
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/util"
+)
+
+// DeleteCommentOperation will hide the content of a comment. As the history
+// is immutable, the original content is still stored in the bug.
+
+var _ bug.Operation = DeleteCommentOperation{}
+
+type DeleteCommentOperation struct {
+	bug.OpBase
+	// The hash of the operation that created the comment
+	Target util.Hash
+	// True if the comment is deleted by someone else than its author
+	Forced bool
+}
+
+func (op DeleteCommentOperation) Apply(snapshot bug.Snapshot) bug.Snapshot {
+	hashes, err := snapshot.CommentHashes()
+	if err != nil {
+		return snapshot
+	}
+
+	for i, hash := range hashes {
+		if hash != op.Target {
+			continue
+		}
+
+		deletion := bug.CommentDeletion{
+			Author:   op.Author,
+			UnixTime: op.UnixTime,
+			Forced:   op.Forced,
+		}
+
+		snapshot.Comments[i].Deletion = &deletion
+		snapshot.Comments[i].SetMessage(deletion.Tombstone())
+		snapshot.Comments[i].Files = nil
+	}
+
+	return snapshot
+}
+
+// Summary return the hash of the operation that created the comment
+func (op DeleteCommentOperation) Summary() string {
+	if op.Forced {
+		return fmt.Sprintf("comment %s (forced)", op.Target)
+	}
+	return fmt.Sprintf("comment %s", op.Target)
+}
+
+func NewDeleteCommentOp(author bug.Person, target util.Hash, forced bool) DeleteCommentOperation {
+	return DeleteCommentOperation{
+		OpBase: bug.NewOpBase(bug.DeleteCommentOp, author),
+		Target: target,
+		Forced: forced,
+	}
+}
+
+// Convenience function to apply the operation. Only the author of a comment
+// can delete it, unless force is set.
+func DeleteComment(b *bug.Bug, author bug.Person, prefix string, force bool) error {
+	snap := b.Compile()
+
+	index, err := snap.SearchComment(prefix)
+	if err != nil {
+		return err
+	}
+
+	hashes, err := snap.CommentHashes()
+	if err != nil {
+		return err
+	}
+
+	comment := snap.Comments[index]
+
+	if comment.IsDeleted() {
+		return errors.New("this comment is already deleted")
+	}
+
+	forced := comment.Author != author
+	if forced && !force {
+		return errors.New("only the author of a comment can delete it, unless forced")
+	}
+
+	deleteCommentOp := NewDeleteCommentOp(author, hashes[index], forced)
+	b.Append(deleteCommentOp)
+
+	return nil
+}
@@ -1,62 +1,118 @@
 package operations
 
 import (
+	"crypto/rand"
 	"fmt"
 	"io"
 	"io/ioutil"
-	"sort"
+	"strings"
 
 	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/util"
 )
 
 var _ bug.Operation = LabelChangeOperation{}
+var _ bug.LabelOperation = LabelChangeOperation{}
 
 // LabelChangeOperation define a Bug operation to add or remove labels
 type LabelChangeOperation struct {
 	bug.OpBase
 	Added   []bug.Label
 	Removed []bug.Label
+	// The additions of the removed labels seen when removing them, so that
+	// a concurrent addition is kept
+	Observed map[bug.Label][]util.Hash `json:",omitempty"`
+	// Random data, so that adding again a label give a different addition
+	// than the removed one, even with the same author and time
+	Nonce []byte `json:",omitempty"`
 }
 
 // Apply apply the operation
 func (op LabelChangeOperation) Apply(snapshot bug.Snapshot) bug.Snapshot {
-	// Add in the set
-AddLoop:
-	for _, added := range op.Added {
-		for _, label := range snapshot.Labels {
-			if label == added {
-				// Already exist
-				continue AddLoop
-			}
-		}
+	// an error would mean that the operation can't be serialized, so it
+	// couldn't have been stored in the first place
+	addition, _ := bug.HashOperation(op)
 
-		snapshot.Labels = append(snapshot.Labels, added)
+	for _, added := range op.Added {
+		snapshot.AddLabel(added, addition)
 	}
 
-	// Remove in the set
 	for _, removed := range op.Removed {
-		for i, label := range snapshot.Labels {
-			if label == removed {
-				snapshot.Labels[i] = snapshot.Labels[len(snapshot.Labels)-1]
-				snapshot.Labels = snapshot.Labels[:len(snapshot.Labels)-1]
-			}
+		snapshot.RemoveLabel(removed, op.Observed[removed])
+	}
+
+	return snapshot
+}
+
+// Summary return the added labels prefixed with a +, then the removed ones
+// prefixed with a -
+func (op LabelChangeOperation) Summary() string {
+	var changes []string
+	for _, label := range op.Added {
+		changes = append(changes, "+"+string(label))
+	}
+	for _, label := range op.Removed {
+		changes = append(changes, "-"+string(label))
+	}
+	return strings.Join(changes, " ")
+}
+
+// Validate reject the operations with too many labels, see bug.Limits, and
+// the added labels that are not normalized, see bug.NormalizeLabel. The removed ones are not checked, so that a malformed
+// label can still be removed.
+func (op LabelChangeOperation) Validate() error {
+	if err := bug.CheckLimits(op); err != nil {
+		return err
+	}
+
+	for _, added := range op.Added {
+		label, err := bug.NormalizeLabel(string(added))
+		if err != nil {
+			return err
+		}
+		if label != added {
+			return fmt.Errorf("invalid label %q: surrounding whitespaces", added)
 		}
 	}
 
-	// Sort
-	sort.Slice(snapshot.Labels, func(i, j int) bool {
-		return string(snapshot.Labels[i]) < string(snapshot.Labels[j])
-	})
+	return nil
+}
 
-	return snapshot
+// LabelCount return the number of labels added and removed
+func (op LabelChangeOperation) LabelCount() int {
+	return len(op.Added) + len(op.Removed)
+}
+
+// Observe record the additions of the removed labels in effect in the
+// snapshot, so that the removal only cancel these ones
+func (op *LabelChangeOperation) Observe(snapshot bug.Snapshot) {
+	for _, removed := range op.Removed {
+		additions := snapshot.LabelAdditions(removed)
+		if len(additions) == 0 {
+			continue
+		}
+
+		if op.Observed == nil {
+			op.Observed = make(map[bug.Label][]util.Hash)
+		}
+		op.Observed[removed] = additions
+	}
 }
 
 func NewLabelChangeOperation(author bug.Person, added, removed []bug.Label) LabelChangeOperation {
-	return LabelChangeOperation{
+	op := LabelChangeOperation{
 		OpBase:  bug.NewOpBase(bug.LabelChangeOp, author),
 		Added:   added,
 		Removed: removed,
 	}
+
+	if len(added) > 0 {
+		op.Nonce = make([]byte, 16)
+		// never fail, see crypto/rand.Read
+		_, _ = rand.Read(op.Nonce)
+	}
+
+	return op
 }
 
 // ChangeLabels is a convenience function to apply the operation
@@ -70,17 +126,20 @@ func ChangeLabels(out io.Writer, b *bug.Bug, author bug.Person, add, remove []st
 	snap := b.Compile()
 
 	for _, str := range add {
-		label := bug.Label(str)
+		label, err := bug.NormalizeLabel(str)
+		if err != nil {
+			return err
+		}
 
 		// check for duplicate
 		if labelExist(added, label) {
-			fmt.Fprintf(out, "label \"%s\" is a duplicate\n", str)
+			fmt.Fprintf(out, "label \"%s\" is a duplicate\n", label)
 			continue
 		}
 
 		// check that the label doesn't already exist
-		if labelExist(snap.Labels, label) {
-			fmt.Fprintf(out, "label \"%s\" is already set on this bug\n", str)
+		if snap.ContainsLabel(string(label)) {
+			fmt.Fprintf(out, "label \"%s\" is already set on this bug\n", label)
 			continue
 		}
 
@@ -97,7 +156,7 @@ func ChangeLabels(out io.Writer, b *bug.Bug, author bug.Person, add, remove []st
 		}
 
 		// check that the label actually exist
-		if !labelExist(snap.Labels, label) {
+		if !snap.ContainsLabel(str) {
 			fmt.Fprintf(out, "label \"%s\" doesn't exist on this bug\n", str)
 			continue
 		}
@@ -110,6 +169,7 @@ func ChangeLabels(out io.Writer, b *bug.Bug, author bug.Person, add, remove []st
 	}
 
 	labelOp := NewLabelChangeOperation(author, added, removed)
+	labelOp.Observe(snap)
 
 	b.Append(labelOp)
 
@@ -1,6 +1,9 @@
 package operations
 
-import "encoding/gob"
+import (
+	"encoding/gob"
+	"strings"
+)
 
 // Package initialisation used to register operation's type for (de)serialization
 func init() {
@@ -9,4 +12,32 @@ func init() {
 	gob.Register(SetTitleOperation{})
 	gob.Register(SetStatusOperation{})
 	gob.Register(LabelChangeOperation{})
+	gob.Register(SetRelationOperation{})
+	gob.Register(DeleteCommentOperation{})
+	gob.Register(SubscribeOperation{})
+	gob.Register(UnsubscribeOperation{})
+	gob.Register(SetCustomFieldOperation{})
+	gob.Register(AddTimeLogOperation{})
+	gob.Register(SetPriorityOperation{})
+	gob.Register(ArchiveCommentsOperation{})
+	gob.Register(SetChecklistOperation{})
+	gob.Register(RemapAuthorsOperation{})
+}
+
+// the length of the texts in the summaries of the operations
+const summaryLength = 50
+
+// summarizeText return the first line of a text, shortened for the summary
+// of an operation
+func summarizeText(text string) string {
+	lines := strings.SplitN(strings.TrimSpace(text), "\n", 2)
+	line := []rune(lines[0])
+
+	if len(line) > summaryLength {
+		return string(line[:summaryLength-3]) + "..."
+	}
+	if len(lines) > 1 {
+		return string(line) + "..."
+	}
+	return string(line)
 }
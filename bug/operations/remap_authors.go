@@ -0,0 +1,74 @@
+package operations
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/MichaelMure/git-bug/bug"
+)
+
+// RemapAuthorsOperation will replace the persons imported from another bug
+// tracker before their login was mapped by the Person it's mapped to. The
+// imported operations are left untouched, the remap is applied on the
+// snapshot.
+
+var _ bug.Operation = RemapAuthorsOperation{}
+
+type RemapAuthorsOperation struct {
+	bug.OpBase
+	Remaps []bug.AuthorRemap
+}
+
+func (op RemapAuthorsOperation) Apply(snapshot bug.Snapshot) bug.Snapshot {
+	snapshot.RemapAuthors(op.Remaps)
+
+	return snapshot
+}
+
+// Summary return the remapped logins
+func (op RemapAuthorsOperation) Summary() string {
+	remaps := make([]string, len(op.Remaps))
+	for i, remap := range op.Remaps {
+		remaps[i] = fmt.Sprintf("%s=%s", remap.Login, remap.Person.Name)
+	}
+	return strings.Join(remaps, ", ")
+}
+
+func (op RemapAuthorsOperation) Validate() error {
+	if len(op.Remaps) == 0 {
+		return errors.New("no author to remap")
+	}
+
+	for _, remap := range op.Remaps {
+		if remap.Login == "" {
+			return errors.New("missing login to remap")
+		}
+		if remap.Person.Email == "" {
+			return fmt.Errorf("login %s remapped to a person without an email", remap.Login)
+		}
+	}
+
+	return nil
+}
+
+func NewRemapAuthorsOp(author bug.Person, remaps []bug.AuthorRemap) RemapAuthorsOperation {
+	return RemapAuthorsOperation{
+		OpBase: bug.NewOpBase(bug.RemapAuthorsOp, author),
+		Remaps: remaps,
+	}
+}
+
+// Convenience function to apply the operation. Only the remaps needed by the
+// bug are appended, and whether one was needed is returned.
+func RemapAuthors(b *bug.Bug, author bug.Person, remaps []bug.AuthorRemap) bool {
+	needed := b.Compile().UnmappedAuthors(remaps)
+	if len(needed) == 0 {
+		return false
+	}
+
+	remapAuthorsOp := NewRemapAuthorsOp(author, needed)
+	b.Append(remapAuthorsOp)
+
+	return true
+}
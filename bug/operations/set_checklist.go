@@ -0,0 +1,110 @@
+package operations
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/MichaelMure/git-bug/bug"
+)
+
+// SetChecklistOperation will change the state of some items of a checklist.
+// Only the items changed are carried, so that two clones updating different
+// items concurrently don't override each other.
+
+var _ bug.Operation = SetChecklistOperation{}
+
+type SetChecklistOperation struct {
+	bug.OpBase
+	Checklist string
+	Items     []bug.ChecklistItem
+}
+
+func (op SetChecklistOperation) Apply(snapshot bug.Snapshot) bug.Snapshot {
+	snapshot.SetChecklistItems(op.Checklist, op.Items)
+
+	return snapshot
+}
+
+// Summary return the checklist and the state of its items
+func (op SetChecklistOperation) Summary() string {
+	items := make([]string, len(op.Items))
+	for i, item := range op.Items {
+		items[i] = fmt.Sprintf("%s=%s", item.Name, item.State)
+	}
+	return fmt.Sprintf("%s: %s", op.Checklist, strings.Join(items, ", "))
+}
+
+func (op SetChecklistOperation) Validate() error {
+	if err := bug.ValidateChecklistName(op.Checklist); err != nil {
+		return err
+	}
+
+	if len(op.Items) == 0 {
+		return errors.New("no checklist item to set")
+	}
+
+	for _, item := range op.Items {
+		if err := bug.ValidateChecklistItem(item.Name); err != nil {
+			return err
+		}
+		if item.State < bug.ChecklistPending || item.State > bug.ChecklistNA {
+			return fmt.Errorf("invalid state %d for checklist item \"%s\"", item.State, item.Name)
+		}
+	}
+
+	return nil
+}
+
+func NewSetChecklistOp(author bug.Person, checklist string, items []bug.ChecklistItem) SetChecklistOperation {
+	return SetChecklistOperation{
+		OpBase:    bug.NewOpBase(bug.SetChecklistOp, author),
+		Checklist: checklist,
+		Items:     items,
+	}
+}
+
+// Convenience function to apply the operation. The first time the checklist
+// is set on the bug, the items of the definition not given are added as
+// pending. Nothing is appended if no item change.
+func SetChecklist(b *bug.Bug, author bug.Person, name string, definition bug.ChecklistDefinition, states map[string]bug.ChecklistState) error {
+	defined := make(map[string]bool, len(definition.Items))
+	for _, item := range definition.Items {
+		defined[item] = true
+	}
+
+	for item, state := range states {
+		if !defined[item] {
+			return fmt.Errorf("unknown item \"%s\" in checklist %s", item, name)
+		}
+		if state < bug.ChecklistPending || state > bug.ChecklistNA {
+			return fmt.Errorf("invalid state %d for checklist item \"%s\"", state, item)
+		}
+	}
+
+	current, exist := b.Compile().Checklist(name)
+
+	var items []bug.ChecklistItem
+
+	// in the order of the definition, for a stable display
+	for _, item := range definition.Items {
+		state, given := states[item]
+		previous, set := current.Item(item)
+
+		switch {
+		case given && (!set || previous != state):
+			items = append(items, bug.ChecklistItem{Name: item, State: state})
+		case !given && !exist:
+			items = append(items, bug.ChecklistItem{Name: item, State: bug.ChecklistPending})
+		}
+	}
+
+	if len(items) == 0 {
+		return nil
+	}
+
+	setChecklistOp := NewSetChecklistOp(author, name, items)
+	b.Append(setChecklistOp)
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package operations
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/util"
+)
+
+// SetCustomFieldOperation will define a free form field of a bug, or
+// remove it with an empty value
+
+var _ bug.Redactable = SetCustomFieldOperation{}
+
+type SetCustomFieldOperation struct {
+	bug.OpBase
+	Key   string
+	Value string
+}
+
+func (op SetCustomFieldOperation) Apply(snapshot bug.Snapshot) bug.Snapshot {
+	if op.Value == "" {
+		delete(snapshot.CustomFields, op.Key)
+		return snapshot
+	}
+
+	if snapshot.CustomFields == nil {
+		snapshot.CustomFields = make(map[string]string)
+	}
+
+	snapshot.CustomFields[op.Key] = op.Value
+
+	return snapshot
+}
+
+// Summary return the key and the new value of the field
+func (op SetCustomFieldOperation) Summary() string {
+	if op.Value == "" {
+		return fmt.Sprintf("%s removed", op.Key)
+	}
+	return fmt.Sprintf("%s=%s", op.Key, op.Value)
+}
+
+// Redact replace the value, a removal stay a removal
+func (op SetCustomFieldOperation) Redact(hash util.Hash) bug.Operation {
+	op.OpBase = op.OpBase.RedactedBase(hash)
+	if op.Value != "" {
+		op.Value = bug.RedactionMarker
+	}
+	return op
+}
+
+func NewSetCustomFieldOp(author bug.Person, key string, value string) SetCustomFieldOperation {
+	return SetCustomFieldOperation{
+		OpBase: bug.NewOpBase(bug.SetCustomFieldOp, author),
+		Key:    key,
+		Value:  value,
+	}
+}
+
+// Convenience function to apply the operation
+func SetCustomField(b *bug.Bug, author bug.Person, key string, value string) error {
+	// the key is used in queries as "field:key=value"
+	if key == "" || strings.ContainsAny(key, "=: \t\n") {
+		return fmt.Errorf("invalid field name: %q", key)
+	}
+
+	value, err := util.CleanupText(value)
+	if err != nil {
+		return err
+	}
+
+	if strings.Contains(value, "\n") {
+		return fmt.Errorf("a field value should be a single line")
+	}
+
+	setCustomFieldOp := NewSetCustomFieldOp(author, key, value)
+	b.Append(setCustomFieldOp)
+
+	return nil
+}
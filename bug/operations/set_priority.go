@@ -0,0 +1,46 @@
+package operations
+
+import (
+	"fmt"
+
+	"github.com/MichaelMure/git-bug/bug"
+)
+
+// SetPriorityOperation will change the priority of a bug
+
+var _ bug.Operation = SetPriorityOperation{}
+
+type SetPriorityOperation struct {
+	bug.OpBase
+	Priority bug.Priority
+}
+
+func (op SetPriorityOperation) Apply(snapshot bug.Snapshot) bug.Snapshot {
+	snapshot.Priority = op.Priority
+
+	return snapshot
+}
+
+// Summary return the new priority
+func (op SetPriorityOperation) Summary() string {
+	return op.Priority.String()
+}
+
+func NewSetPriorityOp(author bug.Person, priority bug.Priority) SetPriorityOperation {
+	return SetPriorityOperation{
+		OpBase:   bug.NewOpBase(bug.SetPriorityOp, author),
+		Priority: priority,
+	}
+}
+
+// Convenience function to apply the operation
+func SetPriority(b *bug.Bug, author bug.Person, priority bug.Priority) error {
+	if priority < bug.LowPriority || priority > bug.CriticalPriority {
+		return fmt.Errorf("invalid priority %d", priority)
+	}
+
+	setPriorityOp := NewSetPriorityOp(author, priority)
+	b.Append(setPriorityOp)
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package operations
+
+import (
+	"fmt"
+
+	"github.com/MichaelMure/git-bug/bug"
+)
+
+// SetRelationOperation will link the bug to another one
+
+var _ bug.Operation = SetRelationOperation{}
+
+type SetRelationOperation struct {
+	bug.OpBase
+	Kind   bug.RelationKind
+	Target string
+}
+
+func (op SetRelationOperation) Apply(snapshot bug.Snapshot) bug.Snapshot {
+	relation := bug.Relation{
+		Kind:   op.Kind,
+		Target: op.Target,
+	}
+
+	for _, r := range snapshot.Relations {
+		if r == relation {
+			// Already exist
+			return snapshot
+		}
+	}
+
+	snapshot.Relations = append(snapshot.Relations, relation)
+
+	return snapshot
+}
+
+// Summary return the kind of relation and the bug it target
+func (op SetRelationOperation) Summary() string {
+	return fmt.Sprintf("%s %s", op.Kind, bug.FormatHumanId(op.Target))
+}
+
+func NewSetRelationOp(author bug.Person, kind bug.RelationKind, target string) SetRelationOperation {
+	return SetRelationOperation{
+		OpBase: bug.NewOpBase(bug.SetRelationOp, author),
+		Kind:   kind,
+		Target: target,
+	}
+}
+
+// Convenience function to apply the operation
+func SetRelation(b *bug.Bug, author bug.Person, kind bug.RelationKind, target string) error {
+	if !bug.IsValidId(target) {
+		return fmt.Errorf("invalid bug id: %s", target)
+	}
+
+	switch kind {
+	case bug.DuplicateRelation, bug.BlocksRelation, bug.RelatedToRelation:
+	default:
+		return fmt.Errorf("unknown relation kind")
+	}
+
+	setRelationOp := NewSetRelationOp(author, kind, target)
+	b.Append(setRelationOp)
+
+	return nil
+}
@@ -1,12 +1,15 @@
 package operations
 
 import (
+	"fmt"
+
 	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/util"
 )
 
 // SetTitleOperation will change the title of a bug
 
-var _ bug.Operation = SetTitleOperation{}
+var _ bug.Redactable = SetTitleOperation{}
 
 type SetTitleOperation struct {
 	bug.OpBase
@@ -20,6 +23,20 @@ func (op SetTitleOperation) Apply(snapshot bug.Snapshot) bug.Snapshot {
 	return snapshot
 }
 
+// Summary return the previous and the new title
+func (op SetTitleOperation) Summary() string {
+	return fmt.Sprintf("%q -> %q", op.Was, op.Title)
+}
+
+// Redact replace the new title. The next change of title still hold it as
+// the previous one, and must be redacted as well.
+func (op SetTitleOperation) Redact(hash util.Hash) bug.Operation {
+	op.OpBase = op.OpBase.RedactedBase(hash)
+	op.Title = bug.RedactionMarker
+	op.Was = bug.RedactionMarker
+	return op
+}
+
 func NewSetTitleOp(author bug.Person, title string, was string) SetTitleOperation {
 	return SetTitleOperation{
 		OpBase: bug.NewOpBase(bug.SetTitleOp, author),
@@ -29,7 +46,15 @@ func NewSetTitleOp(author bug.Person, title string, was string) SetTitleOperatio
 }
 
 // Convenience function to apply the operation
-func SetTitle(b *bug.Bug, author bug.Person, title string) {
+func SetTitle(b *bug.Bug, author bug.Person, title string) error {
+	title, err := util.CleanupText(title)
+	if err != nil {
+		return err
+	}
+	if title == "" {
+		return ErrEmptyTitle
+	}
+
 	it := bug.NewOperationIterator(b)
 
 	var lastTitleOp bug.Operation
@@ -49,4 +74,6 @@ func SetTitle(b *bug.Bug, author bug.Person, title string) {
 
 	setTitleOp := NewSetTitleOp(author, title, was)
 	b.Append(setTitleOp)
+
+	return nil
 }
@@ -0,0 +1,85 @@
+package operations
+
+import (
+	"fmt"
+
+	"github.com/MichaelMure/git-bug/bug"
+)
+
+// SubscribeOperation will add a person to the subscribers of a bug
+
+var _ bug.Operation = SubscribeOperation{}
+
+type SubscribeOperation struct {
+	bug.OpBase
+	Subscriber bug.Person
+}
+
+func (op SubscribeOperation) Apply(snapshot bug.Snapshot) bug.Snapshot {
+	if snapshot.IsSubscribed(op.Subscriber) {
+		return snapshot
+	}
+
+	snapshot.Subscribers = append(snapshot.Subscribers, op.Subscriber)
+
+	return snapshot
+}
+
+// Summary return the subscribed person
+func (op SubscribeOperation) Summary() string {
+	return fmt.Sprintf("%s <%s>", op.Subscriber.Name, op.Subscriber.Email)
+}
+
+func NewSubscribeOp(author bug.Person, subscriber bug.Person) SubscribeOperation {
+	return SubscribeOperation{
+		OpBase:     bug.NewOpBase(bug.SubscribeOp, author),
+		Subscriber: subscriber,
+	}
+}
+
+// Convenience function to apply the operation
+func Subscribe(b *bug.Bug, author bug.Person, subscriber bug.Person) {
+	op := NewSubscribeOp(author, subscriber)
+	b.Append(op)
+}
+
+// UnsubscribeOperation will remove a person from the subscribers of a bug
+
+var _ bug.Operation = UnsubscribeOperation{}
+
+type UnsubscribeOperation struct {
+	bug.OpBase
+	Subscriber bug.Person
+}
+
+func (op UnsubscribeOperation) Apply(snapshot bug.Snapshot) bug.Snapshot {
+	var subscribers []bug.Person
+
+	for _, subscriber := range snapshot.Subscribers {
+		if subscriber != op.Subscriber {
+			subscribers = append(subscribers, subscriber)
+		}
+	}
+
+	snapshot.Subscribers = subscribers
+
+	return snapshot
+}
+
+// Summary return the unsubscribed person
+func (op UnsubscribeOperation) Summary() string {
+	return fmt.Sprintf("%s <%s>", op.Subscriber.Name, op.Subscriber.Email)
+}
+
+func NewUnsubscribeOp(author bug.Person, subscriber bug.Person) UnsubscribeOperation {
+	return UnsubscribeOperation{
+		OpBase:     bug.NewOpBase(bug.UnsubscribeOp, author),
+		Subscriber: subscriber,
+	}
+}
+
+// Convenience function to apply the operation
+func Unsubscribe(b *bug.Bug, author bug.Person, subscriber bug.Person) {
+	op := NewUnsubscribeOp(author, subscriber)
+	b.Append(op)
+}
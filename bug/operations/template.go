@@ -0,0 +1,29 @@
+package operations
+
+import (
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+// NewBugFromTemplate create a new bug from the named template, authored by
+// the identity configured in git. The placeholders of the template are
+// replaced by the given values, a missing required value being an error.
+// The bug is not committed.
+func NewBugFromTemplate(repo repository.Repo, templateName string, values map[string]string) (*bug.Bug, error) {
+	template, err := bug.LoadTemplate(repo, templateName)
+	if err != nil {
+		return nil, err
+	}
+
+	title, message, err := template.Fill(values)
+	if err != nil {
+		return nil, err
+	}
+
+	author, err := bug.DefaultIdentity(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	return Create(author, title, message)
+}
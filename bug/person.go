@@ -1,33 +1,113 @@
 package bug
 
 import (
-	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/MichaelMure/git-bug/repository"
 )
 
+// AlternateEmailsConfigKey is the git config key listing the other emails of
+// the user, separated by commas, to recognize the bugs and edits they made
+// with them
+const AlternateEmailsConfigKey = repository.PreferencePrefix + alternateEmailsPreference
+
+const alternateEmailsPreference = "user.alternate-emails"
+
 type Person struct {
 	Name  string
 	Email string
 }
 
-// GetUser will query the repository for user detail and build the corresponding Person
-func GetUser(repo repository.Repo) (Person, error) {
-	name, err := repo.GetUserName()
-	if err != nil {
-		return Person{}, err
+// NormalizeEmail return the canonical form of an email, to compare it with
+// another one: the case is ignored, as well as the plus-address tag, so that
+// "John.Doe+bugs@Example.com" is the same address as "john.doe@example.com"
+func NormalizeEmail(email string) string {
+	email = strings.ToLower(strings.TrimSpace(email))
+
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return email
+	}
+
+	local := email[:at]
+	if plus := strings.Index(local, "+"); plus > 0 {
+		local = local[:plus]
+	}
+
+	return local + email[at:]
+}
+
+// HasEmail tell if the email of the person is one of the given ones, once
+// normalized
+func (p Person) HasEmail(emails ...string) bool {
+	if p.Email == "" {
+		return false
 	}
-	if name == "" {
-		return Person{}, errors.New("User name is not configured in git yet. Please use `git config --global user.name \"John Doe\"`")
+
+	email := NormalizeEmail(p.Email)
+
+	for _, other := range emails {
+		if NormalizeEmail(other) == email {
+			return true
+		}
 	}
 
-	email, err := repo.GetUserEmail()
+	return false
+}
+
+// DefaultIdentity build the Person configured in git with user.name and
+// user.email. It's the author of the new bugs and edits, unless another
+// one is given.
+func DefaultIdentity(repo repository.Repo) (Person, error) {
+	name, err := readIdentityConfig(repo, "user.name", `"John Doe"`)
 	if err != nil {
 		return Person{}, err
 	}
-	if email == "" {
-		return Person{}, errors.New("User name is not configured in git yet. Please use `git config --global user.email johndoe@example.com`")
+
+	email, err := readIdentityConfig(repo, "user.email", "johndoe@example.com")
+	if err != nil {
+		return Person{}, err
 	}
 
 	return Person{Name: name, Email: email}, nil
 }
+
+// IdentityEmails return the emails of the given identity: its own, followed
+// by the alternate ones configured with git-bug.user.alternate-emails
+func IdentityEmails(repo repository.Repo, identity Person) ([]string, error) {
+	emails := []string{identity.Email}
+
+	value, err := repository.GetUserPreference(repo, alternateEmailsPreference)
+	if err != nil && err != repository.ErrNoConfigEntry {
+		return nil, err
+	}
+
+	for _, email := range strings.Split(value, ",") {
+		email = strings.TrimSpace(email)
+		if email != "" {
+			emails = append(emails, email)
+		}
+	}
+
+	return emails, nil
+}
+
+func readIdentityConfig(repo repository.Repo, key string, example string) (string, error) {
+	value, err := repo.GetConfig(key)
+	if err != nil && err != repository.ErrNoConfigEntry {
+		return "", err
+	}
+
+	if value == "" {
+		return "", fmt.Errorf("%s is not configured in git yet. Please use `git config --global %s %s`", key, key, example)
+	}
+
+	return value, nil
+}
+
+// GetUser will query the repository for user detail and build the corresponding Person.
+// It's kept for compatibility, see DefaultIdentity.
+func GetUser(repo repository.Repo) (Person, error) {
+	return DefaultIdentity(repo)
+}
@@ -0,0 +1,52 @@
+package bug
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Priority is the urgency of a bug. The values are ordered, from the least
+// to the most urgent.
+type Priority int
+
+const (
+	_ Priority = iota
+	LowPriority
+	NormalPriority
+	HighPriority
+	CriticalPriority
+)
+
+// DefaultPriority is the priority of a bug never given one
+const DefaultPriority = NormalPriority
+
+func (p Priority) String() string {
+	switch p {
+	case LowPriority:
+		return "low"
+	case NormalPriority:
+		return "normal"
+	case HighPriority:
+		return "high"
+	case CriticalPriority:
+		return "critical"
+	default:
+		return "unknown priority"
+	}
+}
+
+// ParsePriority parse the name of a priority, case insensitively
+func ParsePriority(value string) (Priority, error) {
+	switch strings.ToLower(value) {
+	case "low":
+		return LowPriority, nil
+	case "normal":
+		return NormalPriority, nil
+	case "high":
+		return HighPriority, nil
+	case "critical":
+		return CriticalPriority, nil
+	default:
+		return 0, fmt.Errorf("unknown priority \"%s\", expected low, normal, high or critical", value)
+	}
+}
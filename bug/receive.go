@@ -0,0 +1,237 @@
+package bug
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util"
+)
+
+// The verification of the bugs pushed to a server, from its pre-receive hook.
+// git run the hook with the pushed objects in a quarantine directory, which
+// the git commands of the hook can read, before updating any ref.
+
+// zeroHash is given by git as the old value of a created ref, and as the new
+// value of a deleted one
+const zeroHash util.Hash = "0000000000000000000000000000000000000000"
+
+// RefUpdate is the update of a ref, as given to a pre-receive hook
+type RefUpdate struct {
+	Old util.Hash
+	New util.Hash
+	Ref string
+}
+
+// IsCreation tell if the ref doesn't exist yet
+func (u RefUpdate) IsCreation() bool {
+	return u.Old == zeroHash
+}
+
+// IsDeletion tell if the ref is deleted
+func (u RefUpdate) IsDeletion() bool {
+	return u.New == zeroHash
+}
+
+// ParseRefUpdates parse the "<old> <new> <ref>" lines given to a pre-receive
+// hook on its standard input
+func ParseRefUpdates(r io.Reader) ([]RefUpdate, error) {
+	var updates []RefUpdate
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("invalid ref update \"%s\", expected <old> <new> <ref>", line)
+		}
+
+		updates = append(updates, RefUpdate{
+			Old: util.Hash(fields[0]),
+			New: util.Hash(fields[1]),
+			Ref: fields[2],
+		})
+	}
+
+	return updates, scanner.Err()
+}
+
+// RefVerification is the result of the verification of a pushed ref
+type RefVerification struct {
+	Update RefUpdate
+	// The problems found, empty if the update can be accepted
+	Problems []string
+}
+
+// VerifyRefUpdates check the pushed updates of the bug refs before git accept
+// them, returning a verification for each of them. The other refs and the
+// deletions are not checked.
+//
+// When a bug is updated by a fast-forward, only the commits new since its
+// previous state are checked: the trees, the operations and their message
+// blobs. A new or rewritten bug is checked entirely, like by VerifyAll.
+func VerifyRefUpdates(repo repository.Repo, updates []RefUpdate) ([]RefVerification, error) {
+	var result []RefVerification
+
+	prefix := localRefPrefix(repo)
+
+	for _, update := range updates {
+		if !strings.HasPrefix(update.Ref, prefix) || update.IsDeletion() {
+			continue
+		}
+
+		v := RefVerification{Update: update}
+
+		id := strings.TrimPrefix(update.Ref, prefix)
+		if !IsValidId(id) {
+			v.Problems = append(v.Problems, fmt.Sprintf("invalid bug id \"%s\"", id))
+			result = append(result, v)
+			continue
+		}
+
+		problems, err := verifyRefUpdate(repo, id, update)
+		if err != nil {
+			return nil, err
+		}
+
+		v.Problems = problems
+		result = append(result, v)
+	}
+
+	return result, nil
+}
+
+func verifyRefUpdate(repo repository.Repo, id string, update RefUpdate) ([]string, error) {
+	rootPack, incremental, err := incrementalRoot(repo, update)
+	if err != nil {
+		return nil, err
+	}
+
+	if !incremental {
+		v := verifyBug(repo, id, string(update.New), true)
+		if v.Incomplete != nil {
+			return append(v.Problems, v.Incomplete.Error()), nil
+		}
+		return v.Problems, nil
+	}
+
+	var problems []string
+
+	err = repo.WalkCommitsSince(string(update.New), update.Old, func(hash util.Hash) error {
+		problems = append(problems, verifyNewCommit(repo, hash, rootPack)...)
+		return nil
+	})
+
+	return problems, err
+}
+
+// incrementalRoot tell if only the new commits of an update need to be
+// checked, that is if it's a fast-forward of an existing bug, and return the
+// root pack of the bug
+func incrementalRoot(repo repository.Repo, update RefUpdate) (util.Hash, bool, error) {
+	if update.IsCreation() {
+		return "", false, nil
+	}
+
+	ancestor, err := repo.FindCommonAncestor(update.Old, update.New)
+	if err != nil {
+		// unrelated histories
+		return "", false, nil
+	}
+
+	if ancestor != update.Old {
+		return "", false, nil
+	}
+
+	entries, err := repo.ListEntries(update.Old)
+	if err != nil {
+		return "", false, err
+	}
+
+	for _, entry := range entries {
+		if entry.Name == rootEntryName {
+			return entry.Hash, true, nil
+		}
+	}
+
+	// the previous state was already invalid
+	return "", false, nil
+}
+
+// verifyNewCommit check a commit added on top of a bug with the given root
+// pack
+func verifyNewCommit(repo repository.Repo, hash util.Hash, rootPack util.Hash) []string {
+	problem := func(format string, a ...interface{}) []string {
+		return []string{fmt.Sprintf("commit %s: ", hash) + fmt.Sprintf(format, a...)}
+	}
+
+	entries, err := repo.ListEntries(hash)
+	if err != nil {
+		return problem("%s", err)
+	}
+
+	var opsEntry repository.TreeEntry
+	opsFound := false
+	rootFound := false
+	mergeFound := false
+
+	for _, entry := range entries {
+		switch entry.Name {
+		case opsEntryName:
+			opsEntry = entry
+			opsFound = true
+		case rootEntryName:
+			rootFound = true
+			if entry.Hash != rootPack {
+				return problem("the root entry of the bug changed")
+			}
+		case mergeEntryName:
+			mergeFound = true
+		}
+	}
+
+	if !rootFound {
+		return problem("invalid tree, missing the root entry")
+	}
+
+	// a merge commit only join two histories, it has no operation
+	if mergeFound {
+		return nil
+	}
+
+	if !opsFound {
+		return problem("invalid tree, missing the ops entry")
+	}
+
+	data, err := repo.ReadData(opsEntry.Hash)
+	if err != nil {
+		return problem("%s", err)
+	}
+
+	pack, err := ParseOperationPack(data)
+	if err != nil {
+		return problem("%s", err)
+	}
+
+	pack.attachMessageBlobs(repo)
+	pack.commitHash = hash
+
+	problems := validatePack(*pack)
+
+	if err := checkPackSize(repo, *pack); err != nil {
+		problems = append(problems, problem("%s", err)...)
+	}
+
+	blobProblems, err := checkPackMessageBlobs(repo, *pack)
+	if err != nil {
+		return append(problems, problem("%s", err)...)
+	}
+
+	return append(problems, blobProblems...)
+}
@@ -0,0 +1,452 @@
+package bug
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util"
+)
+
+// RedactionMarker replace the text of a redacted operation
+const RedactionMarker = "[redacted]"
+
+// RedactedMetadataKey is the key of the metadata marking a redacted
+// operation. It hold the hash of the original operation.
+const RedactedMetadataKey = "redacted"
+
+// The entry of the rewritten commits listing the hashes of the redacted
+// operations, one per line
+const redactionsEntryName = "redactions"
+
+// ErrRedactedRemotely is returned when merging a remote version of a bug
+// whose history has been rewritten by a redaction, unless the rewritten
+// history is explicitly accepted
+var ErrRedactedRemotely = errors.New("the history has been redacted remotely")
+
+// Redactable is implemented by the operations holding free text, which can
+// be redacted
+type Redactable interface {
+	Operation
+	// Redact return a copy of the operation with its text replaced by
+	// RedactionMarker, marked as the redaction of the operation with the
+	// given hash
+	Redact(hash util.Hash) Operation
+}
+
+// RedactedBase return a copy of the base of an operation, marked as the
+// redaction of the operation with the given hash
+func (op OpBase) RedactedBase(hash util.Hash) OpBase {
+	metadata := make(map[string]string, len(op.Metadata)+1)
+	for key, value := range op.Metadata {
+		metadata[key] = value
+	}
+	metadata[RedactedMetadataKey] = string(hash)
+
+	op.Metadata = metadata
+	return op
+}
+
+// RewrittenCommit map a commit of a rewritten history to its replacement
+type RewrittenCommit struct {
+	Old util.Hash
+	New util.Hash
+}
+
+// SearchOperation return the hash of the committed operation matching a
+// hash prefix
+func (bug *Bug) SearchOperation(prefix string) (util.Hash, error) {
+	var matching []util.Hash
+
+	for _, pack := range bug.packs {
+		for _, op := range pack.Operations {
+			hash, err := HashOperation(op)
+			if err != nil {
+				return "", err
+			}
+			if strings.HasPrefix(string(hash), prefix) {
+				matching = append(matching, hash)
+			}
+		}
+	}
+
+	switch len(matching) {
+	case 0:
+		return "", fmt.Errorf("no operation matching %s", prefix)
+	case 1:
+		return matching[0], nil
+	default:
+		return "", fmt.Errorf("multiple operations matching %s", prefix)
+	}
+}
+
+// Redactions return the hashes of the redacted operations of the bug
+func (bug *Bug) Redactions(repo repository.Repo) ([]util.Hash, error) {
+	if bug.redactions == "" {
+		return nil, nil
+	}
+
+	data, err := repo.ReadData(bug.redactions)
+	if err != nil {
+		return nil, err
+	}
+
+	var hashes []util.Hash
+	for _, line := range strings.Split(string(data), "\n") {
+		if line != "" {
+			hashes = append(hashes, util.Hash(line))
+		}
+	}
+
+	return hashes, nil
+}
+
+// verifyRedactionClaims drop the redaction marker of the operations which are
+// not listed in the redactions of the bug, or already claimed by another
+// operation. Their hash is then the one of their content, so that an
+// operation can't claim the hash of another one.
+func (bug *Bug) verifyRedactionClaims(repo repository.Repo) error {
+	redactions, err := bug.Redactions(repo)
+	if err != nil {
+		return err
+	}
+
+	listed := make(map[util.Hash]bool, len(redactions))
+	for _, hash := range redactions {
+		listed[hash] = true
+	}
+
+	for _, pack := range bug.packs {
+		for _, op := range pack.Operations {
+			claim, ok := op.GetMetadata(RedactedMetadataKey)
+			if !ok {
+				continue
+			}
+
+			if listed[util.Hash(claim)] {
+				// a single operation can be the redaction of another one
+				delete(listed, util.Hash(claim))
+				continue
+			}
+
+			if dropper, ok := op.(metadataDropper); ok {
+				dropper.dropMetadata(RedactedMetadataKey)
+			} else {
+				return fmt.Errorf("unverifiable redaction of the operation %s", claim)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Redact rewrite the history of the bug, replacing the text of an operation
+// by RedactionMarker. The commits from the one holding the operation are
+// rewritten, the root one included, while the id of the bug stay the same.
+// It return the rewritten commits, in order.
+//
+// The other clones still hold the original operation. They must accept the
+// rewritten history explicitly, see MergeOptions.
+func (bug *Bug) Redact(repo repository.Repo, target util.Hash) ([]RewrittenCommit, error) {
+	if bug.HasPendingOp() {
+		return nil, errors.New("the bug has pending operations")
+	}
+
+	if bug.nonLinear {
+		return nil, errors.New("redacting a history holding merge commits is not supported")
+	}
+
+	packIndex, opIndex := -1, -1
+
+	for i, pack := range bug.packs {
+		for j, op := range pack.Operations {
+			hash, err := HashOperation(op)
+			if err != nil {
+				return nil, err
+			}
+			if hash == target {
+				packIndex, opIndex = i, j
+			}
+		}
+	}
+
+	if packIndex < 0 {
+		return nil, fmt.Errorf("no operation %s in the bug", target)
+	}
+
+	op := bug.packs[packIndex].Operations[opIndex]
+
+	if _, ok := op.GetMetadata(RedactedMetadataKey); ok {
+		return nil, fmt.Errorf("the operation %s is already redacted", target)
+	}
+
+	redactable, ok := op.(Redactable)
+	if !ok {
+		return nil, fmt.Errorf("the operation %s hold no text to redact", target)
+	}
+
+	redactions, err := bug.Redactions(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	redactions = append(redactions, target)
+	sort.Slice(redactions, func(i, j int) bool { return redactions[i] < redactions[j] })
+
+	var list bytes.Buffer
+	for _, hash := range redactions {
+		list.WriteString(string(hash) + "\n")
+	}
+
+	redactionsHash, err := repo.StoreData(list.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	newPack := bug.packs[packIndex].Clone()
+	newPack.Operations[opIndex] = redactable.Redact(target)
+
+	packHash, err := bug.writeLike(repo, newPack, packIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	rootPack := bug.rootPack
+	if packIndex == 0 {
+		rootPack = packHash
+	}
+
+	var parent util.Hash
+	if packIndex > 0 {
+		parent = bug.packs[packIndex-1].commitHash
+	}
+
+	var rewritten []RewrittenCommit
+
+	for i := packIndex; i < len(bug.packs); i++ {
+		old := bug.packs[i].commitHash
+
+		entries, err := repo.ListEntries(old)
+		if err != nil {
+			return nil, err
+		}
+
+		tree := make([]repository.TreeEntry, 0, len(entries)+1)
+
+		for _, entry := range entries {
+			switch {
+			case entry.Name == opsEntryName && i == packIndex:
+				entry.Hash = packHash
+			case entry.Name == rootEntryName:
+				entry.Hash = rootPack
+			case entry.Name == messagesEntryName && i == packIndex:
+				// rebuilt without the redacted message
+				continue
+			case entry.Name == redactionsEntryName:
+				continue
+			}
+			tree = append(tree, entry)
+		}
+
+		if i == packIndex {
+			messageTree := makeMessageTree(newPack)
+			if len(messageTree) > 0 {
+				messageTreeHash, err := repo.StoreTree(messageTree)
+				if err != nil {
+					return nil, err
+				}
+				tree = append(tree, repository.TreeEntry{
+					ObjectType: repository.Tree,
+					Hash:       messageTreeHash,
+					Name:       messagesEntryName,
+				})
+			}
+		}
+
+		tree = append(tree, repository.TreeEntry{
+			ObjectType: repository.Blob,
+			Hash:       redactionsHash,
+			Name:       redactionsEntryName,
+		})
+
+		treeHash, err := repo.StoreTree(tree)
+		if err != nil {
+			return nil, err
+		}
+
+		var hash util.Hash
+		if parent == "" {
+			hash, err = repo.StoreCommit(treeHash)
+		} else {
+			hash, err = repo.StoreCommitWithParent(treeHash, parent)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		rewritten = append(rewritten, RewrittenCommit{Old: old, New: hash})
+		parent = hash
+	}
+
+	ref := bug.refPrefix(repo) + bug.id
+
+	tx := repo.Begin()
+	if err := tx.UpdateRefFrom(ref, parent, bug.lastCommit); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	read, err := readBug(repo, ref)
+	if err != nil {
+		return nil, err
+	}
+	read.draft = bug.draft
+	*bug = *read
+
+	return rewritten, nil
+}
+
+// writeLike write a pack replacing the one at the given index, compressed
+// if the original one was
+func (bug *Bug) writeLike(repo repository.Repo, pack OperationPack, index int) (util.Hash, error) {
+	entries, err := repo.ListEntries(bug.packs[index].commitHash)
+	if err != nil {
+		return "", err
+	}
+
+	compress := false
+	for _, entry := range entries {
+		if entry.Name != opsEntryName {
+			continue
+		}
+		data, err := repo.ReadData(entry.Hash)
+		if err != nil {
+			return "", err
+		}
+		compress = bytes.HasPrefix(data, compressedPackMarker)
+	}
+
+	return pack.Write(repo, compress)
+}
+
+// redactedVersion return the version of the bug holding redactions unknown
+// to the other, or nil if they have the same redactions
+func (bug *Bug) redactedVersion(repo repository.Repo, other *Bug) (*Bug, error) {
+	if bug.redactions == other.redactions {
+		return nil, nil
+	}
+
+	local, err := bug.Redactions(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	remote, err := other.Redactions(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	newLocal := missingHashes(local, remote)
+	newRemote := missingHashes(remote, local)
+
+	switch {
+	case newLocal && newRemote:
+		return nil, errors.New("the local and remote histories have been redacted differently, redact the same operations on both sides")
+	case newRemote:
+		return other, nil
+	case newLocal:
+		return bug, nil
+	default:
+		return nil, nil
+	}
+}
+
+// missingHashes tell if some hashes are not in the other list
+func missingHashes(hashes []util.Hash, other []util.Hash) bool {
+	set := make(map[util.Hash]bool, len(other))
+	for _, hash := range other {
+		set[hash] = true
+	}
+
+	for _, hash := range hashes {
+		if !set[hash] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// replay make the bug a copy of the base version, followed by the operations
+// of the other version unknown to the base, committed again. The operations
+// are compared by hash, so that a redacted operation is never brought back
+// by a version holding the original. It return true if the local version has
+// been updated.
+func (bug *Bug) replay(repo repository.Repo, base *Bug, other *Bug) (bool, error) {
+	known := make(map[util.Hash]bool)
+
+	for _, pack := range base.packs {
+		for _, op := range pack.Operations {
+			hash, err := HashOperation(op)
+			if err != nil {
+				return false, err
+			}
+			known[hash] = true
+		}
+	}
+
+	result := *base
+	result.packs = append([]OperationPack{}, base.packs...)
+	result.staging = OperationPack{}
+	result.draft = false
+
+	var maxEditTime util.LamportTime
+
+	for _, pack := range other.packs {
+		if pack.editTime > maxEditTime {
+			maxEditTime = pack.editTime
+		}
+
+		for _, op := range pack.Operations {
+			hash, err := HashOperation(op)
+			if err != nil {
+				return false, err
+			}
+			if !known[hash] {
+				result.Append(op)
+				known[hash] = true
+			}
+		}
+	}
+
+	// the replayed operations are ordered after both versions
+	if err := repo.EditWitness(maxEditTime); err != nil {
+		return false, err
+	}
+
+	tx := repo.Begin()
+
+	if result.staging.IsEmpty() {
+		if err := tx.UpdateRefFrom(localRefPrefix(repo)+bug.id, result.lastCommit, bug.lastCommit); err != nil {
+			tx.Rollback()
+			return false, err
+		}
+		if err := tx.Commit(); err != nil {
+			return false, err
+		}
+	} else if err := result.commit(repo, tx, bug.lastCommit); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	updated := result.lastCommit != bug.lastCommit
+	*bug = result
+
+	return updated, nil
+}
@@ -0,0 +1,180 @@
+package bug
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util"
+)
+
+// refBatch wrap a repository to hold back the updates of references, so that
+// they are applied all at once with Apply, or not at all. The references
+// updated are read back from the batch by RefExist and ResolveRef. The value
+// of each reference when first read is expected to be unchanged when the
+// batch is applied, so that a concurrent write, like a bug committed during a
+// pull, make Apply fail instead of being overwritten. It's not safe for
+// concurrent use.
+type refBatch struct {
+	repository.Repo
+
+	// the new hash of each reference, empty for a removal
+	updates map[string]util.Hash
+	// the references in the order of their first update
+	refs []string
+	// the value of each reference when first read, repository.NoRef if it
+	// didn't exist
+	olds map[string]util.Hash
+	// the prefixes listed with ResolveRefs, whose other references didn't
+	// exist
+	listed []string
+}
+
+func newRefBatch(repo repository.Repo) *refBatch {
+	return &refBatch{
+		Repo:    repo,
+		updates: make(map[string]util.Hash),
+		olds:    make(map[string]util.Hash),
+	}
+}
+
+// remember record the value of a reference when first read
+func (b *refBatch) remember(ref string, hash util.Hash) {
+	if _, ok := b.olds[ref]; !ok {
+		b.olds[ref] = hash
+	}
+}
+
+// old return the expected value of a reference before the batch
+func (b *refBatch) old(ref string) (util.Hash, error) {
+	if old, ok := b.olds[ref]; ok {
+		return old, nil
+	}
+
+	for _, prefix := range b.listed {
+		if strings.HasPrefix(ref, prefix) {
+			return repository.NoRef, nil
+		}
+	}
+
+	// never read, only its current value can be checked
+	exist, err := b.Repo.RefExist(ref)
+	if err != nil {
+		return "", err
+	}
+	if !exist {
+		return repository.NoRef, nil
+	}
+	return b.Repo.ResolveRef(ref)
+}
+
+// BugNamespace keep the namespace of the wrapped repository
+func (b *refBatch) BugNamespace() string {
+	return RepoNamespace(b.Repo)
+}
+
+func (b *refBatch) set(ref string, hash util.Hash) {
+	if _, ok := b.updates[ref]; !ok {
+		b.refs = append(b.refs, ref)
+	}
+	b.updates[ref] = hash
+}
+
+// UpdateRef schedule the creation or update of a Git reference
+func (b *refBatch) UpdateRef(ref string, hash util.Hash) error {
+	b.set(ref, hash)
+	return nil
+}
+
+// UpdateRefs schedule several updates of Git references
+func (b *refBatch) UpdateRefs(updates []repository.RefUpdate) error {
+	for _, update := range updates {
+		b.set(update.Ref, update.Hash)
+	}
+	return nil
+}
+
+// Begin start a Transaction whose updates are added to the batch when
+// committed
+func (b *refBatch) Begin() repository.Transaction {
+	return repository.NewTransaction(b.UpdateRefs)
+}
+
+// CopyRef schedule the creation of a reference with the same value as
+// another one
+func (b *refBatch) CopyRef(source string, dest string) error {
+	hash, err := b.ResolveRef(source)
+	if err != nil {
+		return err
+	}
+
+	b.set(dest, hash)
+	return nil
+}
+
+// RefExist will check if a reference exist, once the batch applied
+func (b *refBatch) RefExist(ref string) (bool, error) {
+	if hash, ok := b.updates[ref]; ok {
+		return hash != "", nil
+	}
+
+	exist, err := b.Repo.RefExist(ref)
+	if err == nil && !exist {
+		b.remember(ref, repository.NoRef)
+	}
+	return exist, err
+}
+
+// ResolveRef return the hash a reference point to, once the batch applied
+func (b *refBatch) ResolveRef(ref string) (util.Hash, error) {
+	if hash, ok := b.updates[ref]; ok {
+		if hash == "" {
+			return "", fmt.Errorf("reference %s not found", ref)
+		}
+		return hash, nil
+	}
+
+	hash, err := b.Repo.ResolveRef(ref)
+	if err == nil {
+		b.remember(ref, hash)
+	}
+	return hash, err
+}
+
+// ResolveRefs return the hash of the references matching the refspec, as
+// stored in the repository
+func (b *refBatch) ResolveRefs(refspec string) (map[string]util.Hash, error) {
+	hashes, err := b.Repo.ResolveRefs(refspec)
+	if err != nil {
+		return nil, err
+	}
+
+	for ref, hash := range hashes {
+		b.remember(ref, hash)
+	}
+	b.listed = append(b.listed, strings.TrimSuffix(refspec, "*"))
+
+	return hashes, nil
+}
+
+// Apply the updates of the batch to the repository, all of them or none
+func (b *refBatch) Apply() error {
+	updates := make([]repository.RefUpdate, len(b.refs))
+	for i, ref := range b.refs {
+		old, err := b.old(ref)
+		if err != nil {
+			return err
+		}
+		updates[i] = repository.RefUpdate{Ref: ref, Hash: b.updates[ref], Old: old}
+	}
+
+	if err := b.Repo.UpdateRefs(updates); err != nil {
+		return err
+	}
+
+	b.updates = make(map[string]util.Hash)
+	b.refs = nil
+	b.olds = make(map[string]util.Hash)
+	b.listed = nil
+	return nil
+}
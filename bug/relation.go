@@ -0,0 +1,30 @@
+package bug
+
+type RelationKind int
+
+const (
+	_ RelationKind = iota
+	DuplicateRelation
+	BlocksRelation
+	RelatedToRelation
+)
+
+func (k RelationKind) String() string {
+	switch k {
+	case DuplicateRelation:
+		return "duplicate"
+	case BlocksRelation:
+		return "blocks"
+	case RelatedToRelation:
+		return "related to"
+	default:
+		return "unknown relation"
+	}
+}
+
+// Relation represent a link from a bug to another one
+type Relation struct {
+	Kind RelationKind
+	// The id of the other bug. The bug might not be known locally.
+	Target string
+}
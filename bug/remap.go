@@ -0,0 +1,76 @@
+package bug
+
+// AuthorRemap map the login of a user of another bug tracker to a Person, see
+// the identity mapping of the bridges
+type AuthorRemap struct {
+	Login  string
+	Person Person
+}
+
+// persons return the persons referenced by the snapshot, to remap them
+func (snap *Snapshot) persons() []*Person {
+	persons := []*Person{&snap.Author}
+
+	for i := range snap.Comments {
+		comment := &snap.Comments[i]
+
+		persons = append(persons, &comment.Author)
+
+		for j := range comment.History {
+			persons = append(persons, &comment.History[j].Author)
+		}
+
+		if comment.Deletion != nil {
+			persons = append(persons, &comment.Deletion.Author)
+		}
+	}
+
+	for i := range snap.Subscribers {
+		persons = append(persons, &snap.Subscribers[i])
+	}
+
+	for i := range snap.TimeLogs {
+		persons = append(persons, &snap.TimeLogs[i].Author)
+	}
+
+	return persons
+}
+
+// RemapAuthors replace the persons imported from another bug tracker before
+// their login was mapped, that is named after their login and without an
+// email, by the Person their login is mapped to. The operations are kept as
+// they were committed.
+func (snap *Snapshot) RemapAuthors(remaps []AuthorRemap) {
+	for _, person := range snap.persons() {
+		if person.Email != "" {
+			continue
+		}
+
+		for _, remap := range remaps {
+			if remap.Login == person.Name {
+				*person = remap.Person
+				break
+			}
+		}
+	}
+}
+
+// UnmappedAuthors return the remaps still needed by the bug: the ones of the
+// logins naming a person without an email in the snapshot
+func (snap Snapshot) UnmappedAuthors(remaps []AuthorRemap) []AuthorRemap {
+	unmapped := make(map[string]bool)
+	for _, person := range snap.persons() {
+		if person.Email == "" {
+			unmapped[person.Name] = true
+		}
+	}
+
+	var result []AuthorRemap
+	for _, remap := range remaps {
+		if unmapped[remap.Login] {
+			result = append(result, remap)
+		}
+	}
+
+	return result
+}
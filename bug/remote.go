@@ -0,0 +1,91 @@
+package bug
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+// DefaultRemote is the remote used to sync the bugs when none is configured
+const DefaultRemote = "origin"
+
+// RemoteConfigKey is the git config key selecting the remote used to sync
+// the bugs, independently of the remote of the code
+const RemoteConfigKey = repository.PreferencePrefix + remotePreference
+
+const remotePreference = "remote"
+
+// a remote name end up in the refs of the remote bugs
+var remoteRegexp = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// ConfiguredRemote return the remote used to sync the bugs by default
+func ConfiguredRemote(repo repository.Repo) (string, error) {
+	remote, err := repository.GetUserPreference(repo, remotePreference)
+	if err == repository.ErrNoConfigEntry {
+		return DefaultRemote, nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return remote, nil
+}
+
+// CheckRemote make sure that a remote exist in the repository
+func CheckRemote(repo repository.Repo, remote string) error {
+	_, err := repo.GetConfig(remoteUrlKey(remote))
+	if err == repository.ErrNoConfigEntry {
+		return fmt.Errorf("unknown remote \"%s\", it can be added with \"git remote add\" or \"git bug remote add\"", remote)
+	}
+
+	return err
+}
+
+// RemoteUrl return the url of a remote
+func RemoteUrl(repo repository.Repo, remote string) (string, error) {
+	if err := CheckRemote(repo, remote); err != nil {
+		return "", err
+	}
+
+	return repo.GetConfig(remoteUrlKey(remote))
+}
+
+// AddRemote add a git remote that only fetch and push the bugs, for
+// example when the remote of the code is read-only
+func AddRemote(repo repository.Repo, remote string, url string) error {
+	if !remoteRegexp.MatchString(remote) {
+		return fmt.Errorf("invalid remote name \"%s\"", remote)
+	}
+
+	if err := CheckRemote(repo, remote); err == nil {
+		return fmt.Errorf("remote \"%s\" already exists", remote)
+	}
+
+	if err := repo.SetConfig(remoteUrlKey(remote), url); err != nil {
+		return err
+	}
+
+	// the same refspecs as Fetch and Push, so that a plain git fetch or push
+	// on this remote only transfer the bugs
+	fetch := fmt.Sprintf("+%s*:%s*", localRefPrefix(repo), remoteRefPrefix(repo, remote))
+	if err := repo.SetConfig(fmt.Sprintf("remote.%s.fetch", remote), fetch); err != nil {
+		return err
+	}
+
+	push := fmt.Sprintf("%s*:%s*", localRefPrefix(repo), localRefPrefix(repo))
+	return repo.SetConfig(fmt.Sprintf("remote.%s.push", remote), push)
+}
+
+// SetDefaultRemote store the remote used to sync the bugs by default
+func SetDefaultRemote(repo repository.Repo, remote string) error {
+	if err := CheckRemote(repo, remote); err != nil {
+		return err
+	}
+
+	return repository.SetUserPreference(repo, remotePreference, remote)
+}
+
+func remoteUrlKey(remote string) string {
+	return fmt.Sprintf("remote.%s.url", remote)
+}
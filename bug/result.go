@@ -0,0 +1,20 @@
+package bug
+
+import "github.com/MichaelMure/git-bug/util"
+
+// Result describe the outcome of a high-level action on a bug in a
+// machine-readable way, so that each frontend (CLI, termui, API) can present
+// it without having to reformat it.
+type Result struct {
+	// The id of the bug
+	Id string
+	// The hash of the git commit created by the action
+	Commit util.Hash
+	// A human readable description of the action
+	Message string
+}
+
+// HumanId return the Bug identifier truncated for human consumption
+func (r Result) HumanId() string {
+	return FormatHumanId(r.Id)
+}
@@ -0,0 +1,103 @@
+package bug
+
+import (
+	"fmt"
+
+	"github.com/MichaelMure/git-bug/util"
+)
+
+// ErrRootMismatch is returned when merging two versions of a bug sharing an
+// id but not their creation, like a forged remote bug. Their histories are
+// unrelated and are never spliced together.
+type ErrRootMismatch struct {
+	Id string
+	// the root packs of the local and the other version
+	Local  util.Hash
+	Remote util.Hash
+}
+
+func (e *ErrRootMismatch) Error() string {
+	return fmt.Sprintf("bug %s: the other version doesn't share the creation of the local one, their histories are unrelated", FormatHumanId(e.Id))
+}
+
+// checkSameRoot return an ErrRootMismatch if the other version of the bug
+// doesn't have the same root pack and first operation. A redaction rewrite
+// the root pack, which is only compared when both versions are redacted
+// alike. The first operations are compared by content, a redacted one being
+// the redaction of the other, so that a claimed redaction can't stand in for
+// an unrelated operation.
+func (bug *Bug) checkSameRoot(other *Bug) error {
+	mismatch := &ErrRootMismatch{
+		Id:     bug.id,
+		Local:  bug.rootPack,
+		Remote: other.rootPack,
+	}
+
+	if bug.redactions == other.redactions && bug.rootPack != other.rootPack {
+		return mismatch
+	}
+
+	localOp := bug.FirstOp()
+	otherOp := other.FirstOp()
+
+	if localOp == nil || otherOp == nil {
+		return mismatch
+	}
+
+	same, err := sameOperation(localOp, otherOp)
+	if err != nil {
+		return err
+	}
+
+	if !same {
+		return mismatch
+	}
+
+	return nil
+}
+
+// sameOperation tell if two operations have the same content, or if one is
+// the redaction of the other
+func sameOperation(op Operation, other Operation) (bool, error) {
+	claim, redacted := op.GetMetadata(RedactedMetadataKey)
+	otherClaim, otherRedacted := other.GetMetadata(RedactedMetadataKey)
+
+	switch {
+	case redacted == otherRedacted:
+		return sameContent(op, other)
+	case otherRedacted:
+		return isRedactionOf(other, op, util.Hash(otherClaim))
+	default:
+		return isRedactionOf(op, other, util.Hash(claim))
+	}
+}
+
+// isRedactionOf tell if an operation is the redaction of the original one,
+// as claimed
+func isRedactionOf(redacted Operation, original Operation, claim util.Hash) (bool, error) {
+	hash, err := hashContent(original)
+	if err != nil {
+		return false, err
+	}
+
+	redactable, ok := original.(Redactable)
+	if hash != claim || !ok {
+		return false, nil
+	}
+
+	return sameContent(redactable.Redact(hash), redacted)
+}
+
+func sameContent(op Operation, other Operation) (bool, error) {
+	hash, err := hashContent(op)
+	if err != nil {
+		return false, err
+	}
+
+	otherHash, err := hashContent(other)
+	if err != nil {
+		return false, err
+	}
+
+	return hash == otherHash, nil
+}
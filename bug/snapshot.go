@@ -2,21 +2,63 @@ package bug
 
 import (
 	"fmt"
+	"reflect"
+	"strings"
 	"time"
+
+	"github.com/MichaelMure/git-bug/util"
 )
 
+// the current time, replaced in the tests
+var now = time.Now
+
 // Snapshot is a compiled form of the Bug data structure used for storage and merge
 type Snapshot struct {
 	id string
+	// the abbreviation of the id unique among the bugs of the repository,
+	// when known, see SetHumanId
+	humanId string
 
-	Status    Status
-	Title     string
-	Comments  []Comment
-	Labels    []Label
+	Status Status
+	// NormalPriority unless set by an operation
+	Priority Priority
+	Title    string
+	Comments []Comment
+	// The blobs storing the archived comments, and their number, see
+	// LoadArchivedComments
+	Archives      []util.Hash
+	ArchivedCount int
+	Labels        []Label
+	Relations     []Relation
+	// The people notified of the changes of the bug
+	Subscribers []Person
+	// Free form fields defined by the users, like a severity or a component
+	CustomFields map[string]string
+	// The checklists of the bug, sorted by name
+	Checklists []Checklist
+	// The time spent on the bug
+	TimeLogs  []TimeLog
 	Author    Person
 	CreatedAt time.Time
 
 	Operations []Operation
+
+	// the additions of each label still in effect, and the removed ones, see
+	// AddLabel
+	labelAdds    map[Label][]util.Hash
+	labelRemoved map[util.Hash]bool
+
+	// the hashes of the archived comments, left out of CommentHashes
+	archived map[util.Hash]bool
+
+	// the pack of each operation, to tell apart the edit sessions
+	packIndexes []int
+}
+
+// NewSnapshot create an empty snapshot of the given bug, for the decoders of
+// the exported snapshots
+func NewSnapshot(id string) Snapshot {
+	return Snapshot{id: id, Priority: DefaultPriority}
 }
 
 // Return the Bug identifier
@@ -24,18 +66,198 @@ func (snap Snapshot) Id() string {
 	return snap.id
 }
 
-// Return the Bug identifier truncated for human consumption
+// Return the Bug identifier abbreviated for human consumption: the shortest
+// abbreviation unique among the bugs of the repository if it's been set, or
+// the identifier truncated like FormatHumanId
 func (snap Snapshot) HumanId() string {
-	return fmt.Sprintf("%.8s", snap.id)
+	if snap.humanId != "" {
+		return snap.humanId
+	}
+	return FormatHumanId(snap.id)
+}
+
+// SetHumanId define the abbreviation of the Bug identifier unique among the
+// bugs of the repository, see AbbreviateId
+func (snap *Snapshot) SetHumanId(humanId string) {
+	snap.humanId = humanId
+}
+
+// SameEdit tell if the operation at the given index was made in the same edit
+// session, that is stored in the same OperationPack, as the previous one
+func (snap Snapshot) SameEdit(index int) bool {
+	if index <= 0 || index >= len(snap.packIndexes) {
+		return false
+	}
+
+	return snap.packIndexes[index] == snap.packIndexes[index-1]
 }
 
 func (snap Snapshot) Summary() string {
 	return fmt.Sprintf("C:%d L:%d",
-		len(snap.Comments)-1,
+		len(snap.Comments)-1+snap.ArchivedCount,
 		len(snap.Labels),
 	)
 }
 
+// the width of the title in Oneline
+const onelineTitleWidth = 60
+
+// escape the separators of Oneline in the labels
+var onelineLabelEscaper = strings.NewReplacer("%", "%25", " ", "%20", ",", "%2C")
+
+// Oneline return a single line summary of the bug, for the scripts:
+// "<human id> <status> <labels> <title>". The status is O for open or C for
+// closed. The labels are comma separated, or "-" if there is none, with
+// their spaces and commas escaped as in URLs. The title is truncated.
+func (snap Snapshot) Oneline() string {
+	status := "?"
+	switch snap.Status {
+	case OpenStatus:
+		status = "O"
+	case ClosedStatus:
+		status = "C"
+	}
+
+	labels := "-"
+	if len(snap.Labels) > 0 {
+		escaped := make([]string, len(snap.Labels))
+		for i, label := range snap.Labels {
+			escaped[i] = onelineLabelEscaper.Replace(string(label))
+		}
+		labels = strings.Join(escaped, ",")
+	}
+
+	title := []rune(snap.Title)
+	if len(title) > onelineTitleWidth {
+		title = append(title[:onelineTitleWidth-3], []rune("...")...)
+	}
+
+	return fmt.Sprintf("%s %s %s %s", snap.HumanId(), status, labels, string(title))
+}
+
+// CommentHashes return the hash identifying each comment, in the same order
+// as the comments. A comment is identified by the operation that created it.
+func (snap Snapshot) CommentHashes() ([]util.Hash, error) {
+	var hashes []util.Hash
+
+	for _, op := range snap.Operations {
+		if op.OpType() != CreateOp && op.OpType() != AddCommentOp {
+			continue
+		}
+
+		hash, err := HashOperation(op)
+		if err != nil {
+			return nil, err
+		}
+
+		if snap.archived[hash] {
+			continue
+		}
+
+		hashes = append(hashes, hash)
+	}
+
+	return hashes, nil
+}
+
+// SearchComment find the index of the comment matching a hash prefix
+func (snap Snapshot) SearchComment(prefix string) (int, error) {
+	hashes, err := snap.CommentHashes()
+	if err != nil {
+		return 0, err
+	}
+
+	index := -1
+
+	for i, hash := range hashes {
+		if strings.HasPrefix(string(hash), prefix) {
+			if index >= 0 {
+				return 0, fmt.Errorf("multiple matching comment found")
+			}
+			index = i
+		}
+	}
+
+	if index < 0 || index >= len(snap.Comments) {
+		return 0, fmt.Errorf("no matching comment found")
+	}
+
+	return index, nil
+}
+
+// IsSubscribed tell if a person is notified of the changes of the bug
+func (snap Snapshot) IsSubscribed(person Person) bool {
+	for _, subscriber := range snap.Subscribers {
+		if subscriber == person {
+			return true
+		}
+	}
+
+	return false
+}
+
+// StatusHistory return the successive status of the bug with who changed it
+// and when, starting with its creation
+func (snap Snapshot) StatusHistory() []StatusChange {
+	var history []StatusChange
+
+	for _, op := range snap.Operations {
+		var status Status
+
+		switch op.OpType() {
+		case CreateOp:
+			status = OpenStatus
+		case SetStatusOp:
+			// the status is the only thing changed by the operation
+			status = op.Apply(Snapshot{}).Status
+		default:
+			continue
+		}
+
+		history = append(history, StatusChange{
+			Status:   status,
+			Author:   op.GetAuthor(),
+			UnixTime: op.Time().Unix(),
+		})
+	}
+
+	return history
+}
+
+// Age return the time elapsed since the creation of the bug
+func (snap Snapshot) Age() time.Duration {
+	return now().Sub(snap.CreatedAt)
+}
+
+// TimeToClose return the time elapsed between the creation of the bug and
+// its first closing, even if it has been reopened since. The boolean is
+// false if the bug has never been closed.
+func (snap Snapshot) TimeToClose() (time.Duration, bool) {
+	for _, change := range snap.StatusHistory() {
+		if change.Status == ClosedStatus {
+			return change.Time().Sub(snap.CreatedAt), true
+		}
+	}
+
+	return 0, false
+}
+
+// Reopened tell if the bug has been opened again after its first closing
+func (snap Snapshot) Reopened() bool {
+	closed := false
+
+	for _, change := range snap.StatusHistory() {
+		switch {
+		case change.Status == ClosedStatus:
+			closed = true
+		case closed && change.Status == OpenStatus:
+			return true
+		}
+	}
+
+	return false
+}
+
 // Return the last time a bug was modified
 func (snap Snapshot) LastEdit() time.Time {
 	if len(snap.Operations) == 0 {
@@ -44,3 +266,24 @@ func (snap Snapshot) LastEdit() time.Time {
 
 	return snap.Operations[len(snap.Operations)-1].Time()
 }
+
+// sameState tell if two snapshots describe the same bug, regardless of the
+// operations that led there
+func (snap Snapshot) sameState(other Snapshot) bool {
+	return reflect.DeepEqual(snap.state(), other.state())
+}
+
+// state return the snapshot without its operations and the bookkeeping of
+// their application
+func (snap Snapshot) state() Snapshot {
+	snap.Operations = nil
+	snap.packIndexes = nil
+	snap.labelAdds = nil
+	snap.labelRemoved = nil
+
+	if len(snap.CustomFields) == 0 {
+		snap.CustomFields = nil
+	}
+
+	return snap
+}
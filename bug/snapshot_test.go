@@ -0,0 +1,115 @@
+package bug
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+type statusTestOperation struct {
+	OpBase
+	Status Status
+}
+
+func (op statusTestOperation) Apply(snapshot Snapshot) Snapshot {
+	snapshot.Status = op.Status
+	return snapshot
+}
+
+func (op statusTestOperation) Summary() string {
+	return op.Status.String()
+}
+
+var clock = time.Date(2018, 9, 1, 12, 0, 0, 0, time.UTC)
+
+func statusOpAt(opType OperationType, status Status, days int) statusTestOperation {
+	return statusTestOperation{
+		OpBase: OpBase{
+			OperationType: opType,
+			UnixTime:      clock.Add(time.Duration(days) * 24 * time.Hour).Unix(),
+		},
+		Status: status,
+	}
+}
+
+func TestSnapshotAge(t *testing.T) {
+	defer func() { now = time.Now }()
+	now = func() time.Time { return clock.Add(72 * time.Hour) }
+
+	snap := Snapshot{CreatedAt: clock}
+
+	if snap.Age() != 72*time.Hour {
+		t.Fatalf("unexpected age %v", snap.Age())
+	}
+}
+
+func TestSnapshotTimeToClose(t *testing.T) {
+	create := statusOpAt(CreateOp, OpenStatus, 0)
+	snap := Snapshot{
+		CreatedAt:  create.Time(),
+		Operations: []Operation{create},
+	}
+
+	if _, ok := snap.TimeToClose(); ok || snap.Reopened() {
+		t.Fatal("an open bug has no time to close")
+	}
+
+	// closed, reopened and closed again
+	snap.Operations = append(snap.Operations,
+		statusOpAt(SetStatusOp, ClosedStatus, 2),
+		statusOpAt(SetStatusOp, OpenStatus, 3),
+		statusOpAt(SetStatusOp, ClosedStatus, 10),
+	)
+
+	duration, ok := snap.TimeToClose()
+	if !ok || duration != 48*time.Hour {
+		t.Fatalf("the first closing should be used, got %v", duration)
+	}
+	if !snap.Reopened() {
+		t.Fatal("the bug has been reopened")
+	}
+
+	// closed once
+	snap.Operations = snap.Operations[:2]
+
+	duration, ok = snap.TimeToClose()
+	if !ok || duration != 48*time.Hour || snap.Reopened() {
+		t.Fatal("unexpected time to close")
+	}
+}
+
+func TestSnapshotOneline(t *testing.T) {
+	id := "0123456789abcdef0123456789abcdef01234567"
+
+	cases := []struct {
+		snap     Snapshot
+		expected string
+	}{
+		{
+			Snapshot{id: id, Status: OpenStatus, Title: "crash on startup"},
+			"0123456 O - crash on startup",
+		},
+		{
+			Snapshot{id: id, Status: ClosedStatus, Title: "crash on startup"},
+			"0123456 C - crash on startup",
+		},
+		{
+			Snapshot{id: id, Status: OpenStatus, Title: "crash", Labels: []Label{"bug", "ui"}},
+			"0123456 O bug,ui crash",
+		},
+		{
+			Snapshot{id: id, Status: ClosedStatus, Title: "crash", Labels: []Label{"good first issue", "a,b", "100%"}},
+			"0123456 C good%20first%20issue,a%2Cb,100%25 crash",
+		},
+		{
+			Snapshot{id: id, Status: OpenStatus, Title: strings.Repeat("é", 70)},
+			"0123456 O - " + strings.Repeat("é", 57) + "...",
+		},
+	}
+
+	for _, c := range cases {
+		if oneline := c.snap.Oneline(); oneline != c.expected {
+			t.Fatalf("unexpected summary %q, expected %q", oneline, c.expected)
+		}
+	}
+}
@@ -0,0 +1,443 @@
+// Package snapshotpb encode the snapshots of the bugs in the protobuf format
+// described by snapshot.proto, a compact format that clients in any language
+// can decode with the code generated from the schema.
+//
+// The encoding is written by hand, so that git-bug doesn't depend on a
+// protobuf library. The operations of the snapshot are not encoded.
+package snapshotpb
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/util"
+)
+
+// MarshalProto encode a snapshot as a Snapshot protobuf message
+func MarshalProto(snap bug.Snapshot) ([]byte, error) {
+	var e encoder
+
+	e.string(1, snap.Id())
+	e.int(2, int64(snap.Status))
+	e.string(3, snap.Title)
+	for _, comment := range snap.Comments {
+		e.message(4, func(e *encoder) { encodeComment(e, comment) })
+	}
+	for _, label := range snap.Labels {
+		e.bytes(5, []byte(label))
+	}
+	for _, relation := range snap.Relations {
+		e.message(6, func(e *encoder) {
+			e.int(1, int64(relation.Kind))
+			e.string(2, relation.Target)
+		})
+	}
+	for _, subscriber := range snap.Subscribers {
+		e.message(7, func(e *encoder) { encodePerson(e, subscriber) })
+	}
+	// sorted, for the encoding to be deterministic
+	keys := make([]string, 0, len(snap.CustomFields))
+	for key := range snap.CustomFields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		e.message(8, func(e *encoder) {
+			e.string(1, key)
+			e.string(2, snap.CustomFields[key])
+		})
+	}
+	for _, log := range snap.TimeLogs {
+		e.message(9, func(e *encoder) {
+			e.message(1, func(e *encoder) { encodePerson(e, log.Author) })
+			e.int(2, int64(log.Duration))
+			e.string(3, log.Note)
+			e.int(4, log.UnixTime)
+		})
+	}
+	e.message(10, func(e *encoder) { encodePerson(e, snap.Author) })
+	if !snap.CreatedAt.IsZero() {
+		// google.protobuf.Timestamp
+		e.message(11, func(e *encoder) {
+			e.int(1, snap.CreatedAt.Unix())
+			e.int(2, int64(snap.CreatedAt.Nanosecond()))
+		})
+	}
+	e.int(12, int64(snap.Priority))
+	for _, archive := range snap.Archives {
+		e.bytes(13, []byte(archive))
+	}
+	e.int(14, int64(snap.ArchivedCount))
+	for _, checklist := range snap.Checklists {
+		e.message(15, func(e *encoder) { encodeChecklist(e, checklist) })
+	}
+
+	return e.buf, nil
+}
+
+func encodePerson(e *encoder, person bug.Person) {
+	e.string(1, person.Name)
+	e.string(2, person.Email)
+}
+
+func encodeComment(e *encoder, comment bug.Comment) {
+	e.message(1, func(e *encoder) { encodePerson(e, comment.Author) })
+	e.string(2, comment.GetMessage())
+	for _, file := range comment.Files {
+		e.bytes(3, []byte(file))
+	}
+	e.int(4, comment.UnixTime)
+	for _, revision := range comment.History {
+		e.message(5, func(e *encoder) {
+			e.message(1, func(e *encoder) { encodePerson(e, revision.Author) })
+			e.string(2, revision.GetMessage())
+			e.int(3, revision.UnixTime)
+		})
+	}
+	if comment.Deletion != nil {
+		e.message(6, func(e *encoder) {
+			e.message(1, func(e *encoder) { encodePerson(e, comment.Deletion.Author) })
+			e.int(2, comment.Deletion.UnixTime)
+			e.bool(3, comment.Deletion.Forced)
+		})
+	}
+}
+
+func encodeChecklist(e *encoder, checklist bug.Checklist) {
+	e.string(1, checklist.Name)
+	for _, item := range checklist.Items {
+		e.message(2, func(e *encoder) {
+			e.string(1, item.Name)
+			e.int(2, int64(item.State))
+		})
+	}
+}
+
+// UnmarshalSnapshotProto decode a Snapshot protobuf message. The unknown
+// fields are ignored.
+func UnmarshalSnapshotProto(data []byte) (*bug.Snapshot, error) {
+	var id string
+	var snap bug.Snapshot
+
+	err := decodeMessage(data, func(f field) (bool, error) {
+		var err error
+
+		switch f.number {
+		case 1:
+			id, err = f.string()
+		case 2:
+			var status int64
+			status, err = f.int()
+			snap.Status = bug.Status(status)
+		case 3:
+			snap.Title, err = f.string()
+		case 4:
+			var comment bug.Comment
+			comment, err = decodeComment(f)
+			snap.Comments = append(snap.Comments, comment)
+		case 5:
+			var label string
+			label, err = f.string()
+			snap.Labels = append(snap.Labels, bug.Label(label))
+		case 6:
+			var relation bug.Relation
+			relation, err = decodeRelation(f)
+			snap.Relations = append(snap.Relations, relation)
+		case 7:
+			var subscriber bug.Person
+			subscriber, err = decodePerson(f)
+			snap.Subscribers = append(snap.Subscribers, subscriber)
+		case 8:
+			var key, value string
+			key, value, err = decodeMapEntry(f)
+			if snap.CustomFields == nil {
+				snap.CustomFields = make(map[string]string)
+			}
+			snap.CustomFields[key] = value
+		case 9:
+			var log bug.TimeLog
+			log, err = decodeTimeLog(f)
+			snap.TimeLogs = append(snap.TimeLogs, log)
+		case 10:
+			snap.Author, err = decodePerson(f)
+		case 11:
+			snap.CreatedAt, err = decodeTimestamp(f)
+		case 12:
+			var priority int64
+			priority, err = f.int()
+			snap.Priority = bug.Priority(priority)
+		case 13:
+			var archive string
+			archive, err = f.string()
+			snap.Archives = append(snap.Archives, util.Hash(archive))
+		case 14:
+			var count int64
+			count, err = f.int()
+			snap.ArchivedCount = int(count)
+		case 15:
+			var checklist bug.Checklist
+			checklist, err = decodeChecklist(f)
+			snap.Checklists = append(snap.Checklists, checklist)
+		default:
+			return false, nil
+		}
+
+		return true, err
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid snapshot: %s", err)
+	}
+
+	// the id can only be given at creation
+	result := bug.NewSnapshot(id)
+	result.Status = snap.Status
+	result.Title = snap.Title
+	result.Comments = snap.Comments
+	result.Labels = snap.Labels
+	result.Relations = snap.Relations
+	result.Subscribers = snap.Subscribers
+	result.CustomFields = snap.CustomFields
+	result.TimeLogs = snap.TimeLogs
+	result.Author = snap.Author
+	result.CreatedAt = snap.CreatedAt
+	if snap.Priority != 0 {
+		result.Priority = snap.Priority
+	}
+	result.Archives = snap.Archives
+	result.ArchivedCount = snap.ArchivedCount
+	result.Checklists = snap.Checklists
+
+	return &result, nil
+}
+
+// decodeSub decode a field holding a nested message
+func decodeSub(f field, read func(f field) (bool, error)) error {
+	data, err := f.bytes()
+	if err != nil {
+		return err
+	}
+	return decodeMessage(data, read)
+}
+
+func decodePerson(f field) (bug.Person, error) {
+	var person bug.Person
+
+	err := decodeSub(f, func(f field) (bool, error) {
+		var err error
+		switch f.number {
+		case 1:
+			person.Name, err = f.string()
+		case 2:
+			person.Email, err = f.string()
+		default:
+			return false, nil
+		}
+		return true, err
+	})
+
+	return person, err
+}
+
+func decodeComment(f field) (bug.Comment, error) {
+	var comment bug.Comment
+
+	err := decodeSub(f, func(f field) (bool, error) {
+		var err error
+		switch f.number {
+		case 1:
+			comment.Author, err = decodePerson(f)
+		case 2:
+			comment.Message, err = f.string()
+		case 3:
+			var file string
+			file, err = f.string()
+			comment.Files = append(comment.Files, util.Hash(file))
+		case 4:
+			comment.UnixTime, err = f.int()
+		case 5:
+			var revision bug.CommentRevision
+			revision, err = decodeRevision(f)
+			comment.History = append(comment.History, revision)
+		case 6:
+			var deletion bug.CommentDeletion
+			deletion, err = decodeDeletion(f)
+			comment.Deletion = &deletion
+		default:
+			return false, nil
+		}
+		return true, err
+	})
+
+	return comment, err
+}
+
+func decodeRevision(f field) (bug.CommentRevision, error) {
+	var revision bug.CommentRevision
+
+	err := decodeSub(f, func(f field) (bool, error) {
+		var err error
+		switch f.number {
+		case 1:
+			revision.Author, err = decodePerson(f)
+		case 2:
+			revision.Message, err = f.string()
+		case 3:
+			revision.UnixTime, err = f.int()
+		default:
+			return false, nil
+		}
+		return true, err
+	})
+
+	return revision, err
+}
+
+func decodeDeletion(f field) (bug.CommentDeletion, error) {
+	var deletion bug.CommentDeletion
+
+	err := decodeSub(f, func(f field) (bool, error) {
+		var err error
+		switch f.number {
+		case 1:
+			deletion.Author, err = decodePerson(f)
+		case 2:
+			deletion.UnixTime, err = f.int()
+		case 3:
+			deletion.Forced, err = f.bool()
+		default:
+			return false, nil
+		}
+		return true, err
+	})
+
+	return deletion, err
+}
+
+func decodeRelation(f field) (bug.Relation, error) {
+	var relation bug.Relation
+
+	err := decodeSub(f, func(f field) (bool, error) {
+		var err error
+		switch f.number {
+		case 1:
+			var kind int64
+			kind, err = f.int()
+			relation.Kind = bug.RelationKind(kind)
+		case 2:
+			relation.Target, err = f.string()
+		default:
+			return false, nil
+		}
+		return true, err
+	})
+
+	return relation, err
+}
+
+func decodeChecklist(f field) (bug.Checklist, error) {
+	var checklist bug.Checklist
+
+	err := decodeSub(f, func(f field) (bool, error) {
+		var err error
+		switch f.number {
+		case 1:
+			checklist.Name, err = f.string()
+		case 2:
+			var item bug.ChecklistItem
+			item, err = decodeChecklistItem(f)
+			checklist.Items = append(checklist.Items, item)
+		default:
+			return false, nil
+		}
+		return true, err
+	})
+
+	return checklist, err
+}
+
+func decodeChecklistItem(f field) (bug.ChecklistItem, error) {
+	var item bug.ChecklistItem
+
+	err := decodeSub(f, func(f field) (bool, error) {
+		var err error
+		switch f.number {
+		case 1:
+			item.Name, err = f.string()
+		case 2:
+			var state int64
+			state, err = f.int()
+			item.State = bug.ChecklistState(state)
+		default:
+			return false, nil
+		}
+		return true, err
+	})
+
+	return item, err
+}
+
+func decodeMapEntry(f field) (string, string, error) {
+	var key, value string
+
+	err := decodeSub(f, func(f field) (bool, error) {
+		var err error
+		switch f.number {
+		case 1:
+			key, err = f.string()
+		case 2:
+			value, err = f.string()
+		default:
+			return false, nil
+		}
+		return true, err
+	})
+
+	return key, value, err
+}
+
+func decodeTimeLog(f field) (bug.TimeLog, error) {
+	var log bug.TimeLog
+
+	err := decodeSub(f, func(f field) (bool, error) {
+		var err error
+		switch f.number {
+		case 1:
+			log.Author, err = decodePerson(f)
+		case 2:
+			var duration int64
+			duration, err = f.int()
+			log.Duration = time.Duration(duration)
+		case 3:
+			log.Note, err = f.string()
+		case 4:
+			log.UnixTime, err = f.int()
+		default:
+			return false, nil
+		}
+		return true, err
+	})
+
+	return log, err
+}
+
+func decodeTimestamp(f field) (time.Time, error) {
+	var seconds, nanos int64
+
+	err := decodeSub(f, func(f field) (bool, error) {
+		var err error
+		switch f.number {
+		case 1:
+			seconds, err = f.int()
+		case 2:
+			nanos, err = f.int()
+		default:
+			return false, nil
+		}
+		return true, err
+	})
+
+	return time.Unix(seconds, nanos), err
+}
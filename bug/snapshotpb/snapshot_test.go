@@ -0,0 +1,202 @@
+package snapshotpb
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/bug/operations"
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util"
+)
+
+var rene = bug.Person{Name: "René Descartes", Email: "rene@descartes.fr"}
+var isaac = bug.Person{Name: "Isaac Newton", Email: "isaac@newton.uk"}
+
+func roundTrip(t *testing.T, snap bug.Snapshot) *bug.Snapshot {
+	data, err := MarshalProto(snap)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := UnmarshalSnapshotProto(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	again, err := MarshalProto(*decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, again) {
+		t.Fatal("The encoding should be deterministic")
+	}
+
+	return decoded
+}
+
+func TestRoundTrip(t *testing.T) {
+	snap := bug.NewSnapshot("0123456789abcdef0123456789abcdef01234567")
+	snap.Status = bug.ClosedStatus
+	snap.Priority = bug.HighPriority
+	snap.Title = "title"
+	snap.Comments = []bug.Comment{
+		{
+			Author:   rene,
+			Message:  "message",
+			Files:    []util.Hash{"c6f6ec0a9b2a8f1b1e5c0b7c7b4e1c8d9e2f3a4b"},
+			UnixTime: 1500000000,
+			History: []bug.CommentRevision{
+				{Author: rene, Message: "first", UnixTime: 1500000000},
+				{Author: rene, Message: "message", UnixTime: 1500000100},
+			},
+		},
+		{
+			Author:   isaac,
+			Message:  "deleted by René Descartes",
+			UnixTime: 1500000200,
+			Deletion: &bug.CommentDeletion{Author: rene, UnixTime: 1500000300, Forced: true},
+		},
+	}
+	snap.Archives = []util.Hash{"5e0c8a3b1e5c0b7c7b4e1c8d9e2f3a4bc6f6ec0a"}
+	snap.ArchivedCount = 3
+	snap.Labels = []bug.Label{"bug", ""}
+	snap.Relations = []bug.Relation{{Kind: bug.BlocksRelation, Target: "abcdef"}}
+	snap.Subscribers = []bug.Person{rene, isaac}
+	snap.CustomFields = map[string]string{"severity": "high", "component": "ui", "empty": ""}
+	snap.Checklists = []bug.Checklist{
+		{Name: "release", Items: []bug.ChecklistItem{
+			{Name: "changelog", State: bug.ChecklistDone},
+			{Name: "docs", State: bug.ChecklistNA},
+		}},
+		{Name: "triage", Items: []bug.ChecklistItem{
+			{Name: "reproduced", State: bug.ChecklistPending},
+		}},
+	}
+	snap.TimeLogs = []bug.TimeLog{
+		{Author: isaac, Duration: 90 * time.Minute, Note: "debugging", UnixTime: 1500000400},
+	}
+	snap.Author = rene
+	snap.CreatedAt = time.Unix(1500000000, 123)
+
+	decoded := roundTrip(t, snap)
+
+	if decoded.Id() != snap.Id() {
+		t.Fatalf("Unexpected id %s", decoded.Id())
+	}
+	if !reflect.DeepEqual(*decoded, snap) {
+		t.Fatalf("The snapshot should survive a round trip:\n%#v\n%#v", *decoded, snap)
+	}
+}
+
+func TestRoundTripCompiled(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+
+	b, err := operations.Create(rene, "title", "message")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := operations.Comment(b, isaac, "comment"); err != nil {
+		t.Fatal(err)
+	}
+	if err := operations.SetCustomField(b, rene, "severity", "high"); err != nil {
+		t.Fatal(err)
+	}
+	if err := operations.AddTimeLog(b, isaac, time.Hour, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := operations.SetPriority(b, rene, bug.CriticalPriority); err != nil {
+		t.Fatal(err)
+	}
+	triage := bug.ChecklistDefinition{Items: []string{"reproduced", "labeled"}}
+	states := map[string]bug.ChecklistState{"reproduced": bug.ChecklistDone}
+	if err := operations.SetChecklist(b, rene, "triage", triage, states); err != nil {
+		t.Fatal(err)
+	}
+	operations.Subscribe(b, rene, isaac)
+	operations.Close(b, rene)
+	if err := b.Commit(repo); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := operations.Comment(b, rene, "later"); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Commit(repo); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := operations.ArchiveComments(repo, b, rene, time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Commit(repo); err != nil {
+		t.Fatal(err)
+	}
+
+	snap := b.Compile()
+	decoded := roundTrip(t, snap)
+
+	// the operations are not encoded
+	snap.Operations = nil
+
+	if !reflect.DeepEqual(decoded.Comments, snap.Comments) ||
+		!reflect.DeepEqual(decoded.CustomFields, snap.CustomFields) ||
+		!reflect.DeepEqual(decoded.TimeLogs, snap.TimeLogs) ||
+		!reflect.DeepEqual(decoded.Subscribers, snap.Subscribers) ||
+		!reflect.DeepEqual(decoded.Archives, snap.Archives) ||
+		!reflect.DeepEqual(decoded.Checklists, snap.Checklists) || len(snap.Checklists) != 1 ||
+		decoded.ArchivedCount != snap.ArchivedCount || snap.ArchivedCount == 0 ||
+		decoded.Id() != snap.Id() || decoded.Title != snap.Title ||
+		decoded.Status != snap.Status || decoded.Priority != snap.Priority ||
+		decoded.Author != snap.Author ||
+		!decoded.CreatedAt.Equal(snap.CreatedAt) {
+		t.Fatalf("The snapshot should survive a round trip:\n%#v\n%#v", *decoded, snap)
+	}
+}
+
+func TestWireFormat(t *testing.T) {
+	snap := bug.NewSnapshot("")
+	snap.Title = "t"
+
+	data, err := MarshalProto(snap)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// title = 3, an empty author = 10, then the normal priority = 12
+	expected := []byte{0x1a, 0x01, 't', 0x52, 0x00, 0x60, 0x02}
+	if !bytes.Equal(data, expected) {
+		t.Fatalf("Unexpected encoding %x", data)
+	}
+
+	// an unknown field is skipped
+	withUnknown := append([]byte{0xf8, 0x01, 0x2a}, data...)
+	decoded, err := UnmarshalSnapshotProto(withUnknown)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Title != "t" {
+		t.Fatalf("Unexpected title %q", decoded.Title)
+	}
+
+	// the default priority without one
+	decoded, err = UnmarshalSnapshotProto(data[:3])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Priority != bug.DefaultPriority {
+		t.Fatalf("Unexpected priority %v", decoded.Priority)
+	}
+
+	for _, invalid := range [][]byte{
+		{0x1a, 0x05, 't'},
+		{0x1a},
+		{0x18, 0x01},
+		{0x0f},
+	} {
+		if _, err := UnmarshalSnapshotProto(invalid); err == nil {
+			t.Fatalf("Decoding %x should fail", invalid)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package snapshotpb
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// The protobuf wire types used by snapshot.proto
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+var errTruncated = errors.New("truncated protobuf data")
+
+// encoder write the fields of a message in the protobuf wire format. As in
+// proto3, the scalar fields with their default value are not written.
+type encoder struct {
+	buf []byte
+}
+
+func (e *encoder) tag(field int, wireType int) {
+	e.buf = binary.AppendUvarint(e.buf, uint64(field)<<3|uint64(wireType))
+}
+
+func (e *encoder) uint(field int, v uint64) {
+	if v == 0 {
+		return
+	}
+	e.tag(field, wireVarint)
+	e.buf = binary.AppendUvarint(e.buf, v)
+}
+
+func (e *encoder) int(field int, v int64) {
+	e.uint(field, uint64(v))
+}
+
+func (e *encoder) bool(field int, v bool) {
+	if v {
+		e.uint(field, 1)
+	}
+}
+
+func (e *encoder) string(field int, s string) {
+	if s != "" {
+		e.bytes(field, []byte(s))
+	}
+}
+
+// bytes write a length-delimited field, even if empty, as needed by the
+// repeated fields
+func (e *encoder) bytes(field int, b []byte) {
+	e.tag(field, wireBytes)
+	e.buf = binary.AppendUvarint(e.buf, uint64(len(b)))
+	e.buf = append(e.buf, b...)
+}
+
+func (e *encoder) message(field int, write func(e *encoder)) {
+	var sub encoder
+	write(&sub)
+	e.bytes(field, sub.buf)
+}
+
+// decoder read the fields of a message in the protobuf wire format
+type decoder struct {
+	data []byte
+}
+
+func (d *decoder) uvarint() (uint64, error) {
+	v, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		return 0, errTruncated
+	}
+	d.data = d.data[n:]
+	return v, nil
+}
+
+func (d *decoder) skip(wireType int) error {
+	switch wireType {
+	case wireVarint:
+		_, err := d.uvarint()
+		return err
+	case wireFixed64, wireFixed32:
+		size := 8
+		if wireType == wireFixed32 {
+			size = 4
+		}
+		if len(d.data) < size {
+			return errTruncated
+		}
+		d.data = d.data[size:]
+		return nil
+	case wireBytes:
+		_, err := d.lengthDelimited()
+		return err
+	default:
+		return fmt.Errorf("unsupported protobuf wire type %d", wireType)
+	}
+}
+
+func (d *decoder) lengthDelimited() ([]byte, error) {
+	length, err := d.uvarint()
+	if err != nil {
+		return nil, err
+	}
+	if length > uint64(len(d.data)) {
+		return nil, errTruncated
+	}
+	b := d.data[:length]
+	d.data = d.data[length:]
+	return b, nil
+}
+
+// field is a field being decoded. The accessors check its wire type.
+type field struct {
+	number   int
+	wireType int
+	d        *decoder
+}
+
+func (f field) check(wireType int) error {
+	if f.wireType != wireType {
+		return fmt.Errorf("field %d: unexpected wire type %d", f.number, f.wireType)
+	}
+	return nil
+}
+
+func (f field) uint() (uint64, error) {
+	if err := f.check(wireVarint); err != nil {
+		return 0, err
+	}
+	return f.d.uvarint()
+}
+
+func (f field) int() (int64, error) {
+	v, err := f.uint()
+	return int64(v), err
+}
+
+func (f field) bool() (bool, error) {
+	v, err := f.uint()
+	return v != 0, err
+}
+
+func (f field) bytes() ([]byte, error) {
+	if err := f.check(wireBytes); err != nil {
+		return nil, err
+	}
+	return f.d.lengthDelimited()
+}
+
+func (f field) string() (string, error) {
+	b, err := f.bytes()
+	return string(b), err
+}
+
+// decodeMessage call read for each field of the message. The fields that
+// read doesn't handle, returning false, are skipped, so that newer fields
+// are ignored.
+func decodeMessage(data []byte, read func(f field) (bool, error)) error {
+	d := &decoder{data: data}
+
+	for len(d.data) > 0 {
+		key, err := d.uvarint()
+		if err != nil {
+			return err
+		}
+
+		f := field{
+			number:   int(key >> 3),
+			wireType: int(key & 7),
+			d:        d,
+		}
+
+		handled, err := read(f)
+		if err != nil {
+			return err
+		}
+
+		if !handled {
+			if err := d.skip(f.wireType); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -1,5 +1,7 @@
 package bug
 
+import "time"
+
 type Status int
 
 const (
@@ -29,3 +31,16 @@ func (s Status) Action() string {
 		return "unknown status"
 	}
 }
+
+// StatusChange is a change of the status of a bug, including its opening
+// when created
+type StatusChange struct {
+	Status   Status
+	Author   Person
+	UnixTime int64
+}
+
+// Time return the time of the change
+func (c StatusChange) Time() time.Time {
+	return time.Unix(c.UnixTime, 0)
+}
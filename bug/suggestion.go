@@ -0,0 +1,175 @@
+package bug
+
+import (
+	"sort"
+	"strings"
+)
+
+// maximum number of suggestions returned for a prefix
+const maxSuggestions = 5
+
+// a bug sharing at least this number of first chars with the prefix is
+// always suggested
+const suggestionCommonPrefix = 3
+
+// suggestIds return the ids that are close to the given prefix, either
+// because they share the first few chars or because they are within a small
+// edit distance, closest first. It return nil when no id is close enough.
+func suggestIds(ids []string, prefix string) []string {
+	prefix = strings.ToLower(prefix)
+
+	// allow one typo in short prefixes, two in longer ones
+	maxDistance := 1
+	if len(prefix) > humanIdLength {
+		maxDistance = 2
+	}
+
+	type candidate struct {
+		id       string
+		distance int
+	}
+
+	var candidates []candidate
+
+	for _, id := range ids {
+		distance := prefixDistance(id, prefix)
+
+		if distance <= maxDistance || commonPrefixLength(id, prefix) >= suggestionCommonPrefix {
+			candidates = append(candidates, candidate{id: id, distance: distance})
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].distance != candidates[j].distance {
+			return candidates[i].distance < candidates[j].distance
+		}
+		return candidates[i].id < candidates[j].id
+	})
+
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	if len(candidates) > maxSuggestions {
+		candidates = candidates[:maxSuggestions]
+	}
+
+	result := make([]string, len(candidates))
+	for i, c := range candidates {
+		result[i] = c.id
+	}
+
+	return result
+}
+
+// prefixDistance return the smallest edit distance between the prefix and the
+// beginning of the id, allowing the prefix to have one char more or less than
+// the part of the id it's compared to
+func prefixDistance(id string, prefix string) int {
+	best := -1
+
+	for _, length := range []int{len(prefix) - 1, len(prefix), len(prefix) + 1} {
+		if length < 0 || length > len(id) {
+			continue
+		}
+
+		d := levenshtein(id[:length], prefix)
+		if best < 0 || d < best {
+			best = d
+		}
+	}
+
+	if best < 0 {
+		return levenshtein(id, prefix)
+	}
+
+	return best
+}
+
+func commonPrefixLength(a, b string) int {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+	return i
+}
+
+// levenshtein compute the edit distance between two strings
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			cur[j] = minInt(minInt(prev[j]+1, cur[j-1]+1), prev[j-1]+cost)
+		}
+
+		prev, cur = cur, prev
+	}
+
+	return prev[len(b)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// SimilarLabel return the known label that a new label is likely a typo of:
+// the same up to the case and the separators, like "UI" and "u i", or within
+// a small edit distance. On a tie, the first of the known labels is chosen,
+// so they should be given most used first.
+func SimilarLabel(known []Label, label Label) (Label, bool) {
+	normalized := normalizeLabel(label)
+
+	// allow one typo in short labels, two in longer ones
+	maxDistance := 1
+	if len(normalized) > 6 {
+		maxDistance = 2
+	}
+
+	var best Label
+	bestDistance := -1
+
+	for _, candidate := range known {
+		if candidate == label {
+			return "", false
+		}
+
+		distance := levenshtein(normalizeLabel(candidate), normalized)
+		if distance > maxDistance || distance >= len(normalized) {
+			continue
+		}
+
+		if bestDistance < 0 || distance < bestDistance {
+			best = candidate
+			bestDistance = distance
+		}
+	}
+
+	return best, bestDistance >= 0
+}
+
+// normalizeLabel lower the case and drop the separators of a label
+func normalizeLabel(label Label) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '_', '.', '/', ':':
+			return -1
+		}
+		return r
+	}, strings.ToLower(string(label)))
+}
@@ -0,0 +1,123 @@
+package bug
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util"
+)
+
+// SyncRefPattern is the local ref holding the state of the bugs of a remote
+// at the last merge, like refs/bugs-sync/origin. The next merge only process
+// the bugs whose ref changed since. It's never pushed.
+const SyncRefPattern = "refs/%s-sync/%s"
+
+const syncEntryName = "state"
+
+func syncRef(repo repository.Repo, remote string) string {
+	return fmt.Sprintf(SyncRefPattern, RepoNamespace(repo), remote)
+}
+
+// readSyncState return the hash of each remote bug at the last merge, by
+// bug id. A damaged state is ignored, every bug is then processed.
+func readSyncState(repo repository.Repo, remote string) (map[string]util.Hash, error) {
+	ref := syncRef(repo, remote)
+
+	exist, err := repo.RefExist(ref)
+	if err != nil || !exist {
+		return nil, err
+	}
+
+	commit, err := repo.ResolveRef(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := repo.ListEntries(commit)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		if entry.Name != syncEntryName {
+			continue
+		}
+
+		data, err := repo.ReadData(entry.Hash)
+		if err != nil {
+			return nil, err
+		}
+
+		return parseSyncState(data), nil
+	}
+
+	return nil, nil
+}
+
+func parseSyncState(data []byte) map[string]util.Hash {
+	state := make(map[string]util.Hash)
+
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		state[fields[0]] = util.Hash(fields[1])
+	}
+
+	return state
+}
+
+// writeSyncState store the state of the remote bugs, if it changed
+func writeSyncState(repo repository.Repo, remote string, previous map[string]util.Hash, state map[string]util.Hash) error {
+	if sameSyncState(previous, state) {
+		return nil
+	}
+
+	ids := make([]string, 0, len(state))
+	for id := range state {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var buffer bytes.Buffer
+	for _, id := range ids {
+		fmt.Fprintf(&buffer, "%s %s\n", id, state[id])
+	}
+
+	blob, err := repo.StoreData(buffer.Bytes())
+	if err != nil {
+		return err
+	}
+
+	tree, err := repo.StoreTree([]repository.TreeEntry{
+		{ObjectType: repository.Blob, Hash: blob, Name: syncEntryName},
+	})
+	if err != nil {
+		return err
+	}
+
+	commit, err := repo.StoreCommit(tree)
+	if err != nil {
+		return err
+	}
+
+	return repo.UpdateRef(syncRef(repo, remote), commit)
+}
+
+func sameSyncState(a map[string]util.Hash, b map[string]util.Hash) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for id, hash := range a {
+		if b[id] != hash {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,237 @@
+package bug
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util"
+)
+
+// TemplatesRefPattern is the ref storing a template shared with the
+// repository, as a chain of commits holding a single "template" file. It's
+// pushed and pulled along with the bugs, whatever the namespace.
+const TemplatesRefPattern = "refs/git-bug/templates/%s"
+
+const remoteTemplatesRefPattern = "refs/remotes/%s/git-bug/templates/%s"
+
+// TemplateConfigPattern is the git config key of a template local to the
+// repository, which take precedence over the shared one
+const TemplateConfigPattern = repository.PreferencePrefix + templatePreferencePattern
+
+const templatePreferencePattern = "template.%s"
+
+const templateEntryName = "template"
+
+// Names are used both as ref component and as git config key
+var templateNameRegexp = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9-]*$`)
+
+// A placeholder is {{name}}, or {{name?}} when it can be left empty
+var placeholderRegexp = regexp.MustCompile(`{{\s*([a-zA-Z0-9_-]+)(\?)?\s*}}`)
+
+// ErrTemplateNotFound is returned when a template is neither in the git
+// config nor in its ref
+var ErrTemplateNotFound = errors.New("template not found")
+
+// Template is a model of bug. Its first line is the title, and the rest is
+// the message of the bug. Both can hold placeholders, like {{version}},
+// which are replaced by the given values.
+type Template struct {
+	Name string
+	Text string
+}
+
+// ValidateTemplateName check that a template name can be stored as a ref
+// and a git config key
+func ValidateTemplateName(name string) error {
+	if !templateNameRegexp.MatchString(name) {
+		return fmt.Errorf("invalid template name \"%s\"", name)
+	}
+	return nil
+}
+
+// LoadTemplate read a template from the git config, or else from its ref
+func LoadTemplate(repo repository.Repo, name string) (*Template, error) {
+	if err := ValidateTemplateName(name); err != nil {
+		return nil, err
+	}
+
+	text, err := repository.GetUserPreference(repo, fmt.Sprintf(templatePreferencePattern, name))
+	if err == nil {
+		return &Template{Name: name, Text: text}, nil
+	}
+	if err != repository.ErrNoConfigEntry {
+		return nil, err
+	}
+
+	ref := fmt.Sprintf(TemplatesRefPattern, name)
+
+	exist, err := repo.RefExist(ref)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, ErrTemplateNotFound
+	}
+
+	commit, err := repo.ResolveRef(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := repo.ListEntries(commit)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		if entry.Name != templateEntryName {
+			continue
+		}
+
+		data, err := repo.ReadData(entry.Hash)
+		if err != nil {
+			return nil, err
+		}
+
+		return &Template{Name: name, Text: string(data)}, nil
+	}
+
+	return nil, fmt.Errorf("ref %s doesn't hold a %s file", ref, templateEntryName)
+}
+
+// templatesFetchRefSpec fetch the templates of a remote
+func templatesFetchRefSpec(remote string) string {
+	return fmt.Sprintf("+%s:%s",
+		fmt.Sprintf(TemplatesRefPattern, "*"),
+		fmt.Sprintf(remoteTemplatesRefPattern, remote, "*"),
+	)
+}
+
+// StoreTemplate write a template in its ref, on top of the previous version
+// if any, so that it can be pushed along with the bugs
+func StoreTemplate(repo repository.Repo, template Template) error {
+	if err := ValidateTemplateName(template.Name); err != nil {
+		return err
+	}
+
+	ref := fmt.Sprintf(TemplatesRefPattern, template.Name)
+
+	exist, err := repo.RefExist(ref)
+	if err != nil {
+		return err
+	}
+
+	var previous util.Hash
+	if exist {
+		previous, err = repo.ResolveRef(ref)
+		if err != nil {
+			return err
+		}
+	}
+
+	blob, err := repo.StoreData([]byte(template.Text))
+	if err != nil {
+		return err
+	}
+
+	tree, err := repo.StoreTree([]repository.TreeEntry{
+		{ObjectType: repository.Blob, Hash: blob, Name: templateEntryName},
+	})
+	if err != nil {
+		return err
+	}
+
+	var commit util.Hash
+	if previous != "" {
+		commit, err = repo.StoreCommitWithParent(tree, previous)
+	} else {
+		commit, err = repo.StoreCommit(tree)
+	}
+	if err != nil {
+		return err
+	}
+
+	return repo.UpdateRef(ref, commit)
+}
+
+// mergeTemplates merge the templates fetched from a remote in the local ones.
+// When a template has been edited on both sides, the local version is kept.
+func mergeTemplates(repo repository.Repo, remote string) error {
+	remotePrefix := fmt.Sprintf(remoteTemplatesRefPattern, remote, "")
+
+	remoteRefs, err := repo.ResolveRefs(remotePrefix)
+	if err != nil {
+		return err
+	}
+
+	for remoteRef := range remoteRefs {
+		name := strings.TrimPrefix(remoteRef, remotePrefix)
+		localRef := fmt.Sprintf(TemplatesRefPattern, name)
+
+		err := mergeConfigRef(repo, localRef, remoteRef, func() (util.Hash, error) {
+			localHash, err := repo.ResolveRef(localRef)
+			if err != nil {
+				return "", err
+			}
+			return repo.GetTreeHash(localHash)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Placeholders return the names of the placeholders of the template, and
+// whether each of them is required
+func (t Template) Placeholders() map[string]bool {
+	placeholders := make(map[string]bool)
+
+	for _, match := range placeholderRegexp.FindAllStringSubmatch(t.Text, -1) {
+		required := match[2] == ""
+		placeholders[match[1]] = placeholders[match[1]] || required
+	}
+
+	return placeholders
+}
+
+// Fill replace the placeholders with the given values, and return the
+// resulting title and message. A missing value for a required placeholder
+// is an error.
+func (t Template) Fill(values map[string]string) (string, string, error) {
+	var missing []string
+	for name, required := range t.Placeholders() {
+		if _, ok := values[name]; required && !ok {
+			missing = append(missing, name)
+		}
+	}
+
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return "", "", fmt.Errorf("template %s: missing value for %s", t.Name, strings.Join(missing, ", "))
+	}
+
+	text := placeholderRegexp.ReplaceAllStringFunc(t.Text, func(placeholder string) string {
+		name := placeholderRegexp.FindStringSubmatch(placeholder)[1]
+		return values[name]
+	})
+
+	lines := strings.SplitN(text, "\n", 2)
+
+	title := strings.TrimSpace(lines[0])
+	if title == "" {
+		return "", "", fmt.Errorf("template %s: empty title", t.Name)
+	}
+
+	message := ""
+	if len(lines) > 1 {
+		message = strings.TrimSpace(lines[1])
+	}
+
+	return title, message, nil
+}
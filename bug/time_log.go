@@ -0,0 +1,62 @@
+package bug
+
+import (
+	"sort"
+	"time"
+)
+
+// TimeLog is some time spent on a bug by a person
+type TimeLog struct {
+	Author   Person
+	Duration time.Duration
+	Note     string
+	UnixTime int64
+}
+
+// Time return the time when the log was added
+func (log TimeLog) Time() time.Time {
+	return time.Unix(log.UnixTime, 0)
+}
+
+// AuthorTime is the total time spent on a bug by a person
+type AuthorTime struct {
+	Author   Person
+	Duration time.Duration
+}
+
+// TimeSpent return the total time logged on the bug
+func (snap Snapshot) TimeSpent() time.Duration {
+	var total time.Duration
+
+	for _, log := range snap.TimeLogs {
+		total += log.Duration
+	}
+
+	return total
+}
+
+// TimeSpentByAuthor return the time logged on the bug by each person, the
+// most involved first
+func (snap Snapshot) TimeSpentByAuthor() []AuthorTime {
+	var result []AuthorTime
+	index := make(map[Person]int)
+
+	for _, log := range snap.TimeLogs {
+		i, ok := index[log.Author]
+		if !ok {
+			i = len(result)
+			index[log.Author] = i
+			result = append(result, AuthorTime{Author: log.Author})
+		}
+		result[i].Duration += log.Duration
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Duration != result[j].Duration {
+			return result[i].Duration > result[j].Duration
+		}
+		return result[i].Author.Name < result[j].Author.Name
+	})
+
+	return result
+}
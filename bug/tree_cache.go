@@ -0,0 +1,67 @@
+package bug
+
+import (
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util"
+)
+
+// treeCache wrap a repository to look up the tree of each commit only once,
+// when the same commits are read several times, like when a bug is read
+// from both sides and then merged. It's meant to be used for a single such
+// call and dropped after, so that it doesn't grow with every bug read. It's
+// not safe for concurrent use.
+type treeCache struct {
+	repository.Repo
+
+	entries map[util.Hash][]repository.TreeEntry
+	trees   map[util.Hash]util.Hash
+}
+
+func newTreeCache(repo repository.Repo) *treeCache {
+	if cache, ok := repo.(*treeCache); ok {
+		return cache
+	}
+
+	return &treeCache{
+		Repo:    repo,
+		entries: make(map[util.Hash][]repository.TreeEntry),
+		trees:   make(map[util.Hash]util.Hash),
+	}
+}
+
+// BugNamespace keep the namespace of the wrapped repository
+func (c *treeCache) BugNamespace() string {
+	return RepoNamespace(c.Repo)
+}
+
+// ListEntries will return the list of entries in a Git tree
+func (c *treeCache) ListEntries(hash util.Hash) ([]repository.TreeEntry, error) {
+	if entries, ok := c.entries[hash]; ok {
+		return entries, nil
+	}
+
+	entries, err := c.Repo.ListEntries(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	c.entries[hash] = entries
+
+	return entries, nil
+}
+
+// GetTreeHash return the git tree hash referenced in a commit
+func (c *treeCache) GetTreeHash(commit util.Hash) (util.Hash, error) {
+	if tree, ok := c.trees[commit]; ok {
+		return tree, nil
+	}
+
+	tree, err := c.Repo.GetTreeHash(commit)
+	if err != nil {
+		return "", err
+	}
+
+	c.trees[commit] = tree
+
+	return tree, nil
+}
@@ -0,0 +1,177 @@
+package bug
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+// BugVerification is the result of the verification of a single bug
+type BugVerification struct {
+	Id string
+	// The problems found, empty for a valid bug
+	Problems []string
+	// Set if the history of the bug is only partially fetched. It's not a
+	// corruption, but the bug can't be verified.
+	Incomplete *ErrIncompleteHistory
+}
+
+// IsValid tell if no problem was found with the bug
+func (v BugVerification) IsValid() bool {
+	return len(v.Problems) == 0 && v.Incomplete == nil
+}
+
+// VerifyReport is the result of the verification of all the local bugs
+type VerifyReport struct {
+	Bugs []BugVerification
+
+	// The lock files of the bug refs left by an interrupted git process,
+	// preventing the update of these refs
+	StaleLocks []string
+
+	Valid      int
+	Invalid    int
+	Incomplete int
+}
+
+// InvalidIds return the ids of the bugs with problems
+func (r VerifyReport) InvalidIds() []string {
+	var ids []string
+	for _, v := range r.Bugs {
+		if len(v.Problems) > 0 {
+			ids = append(ids, v.Id)
+		}
+	}
+	return ids
+}
+
+// Summary return a single line with the number of bugs in each state
+func (r VerifyReport) Summary() string {
+	return fmt.Sprintf("%d bugs verified: %d valid, %d invalid, %d with an incomplete history",
+		len(r.Bugs), r.Valid, r.Invalid, r.Incomplete)
+}
+
+// VerifyAll read and validate each local bug. A problem with a bug is
+// recorded in the report and doesn't stop the verification of the others,
+// an error is returned only if the bugs can't be listed.
+func VerifyAll(repo repository.Repo) (VerifyReport, error) {
+	var report VerifyReport
+
+	ids, err := ListLocalIds(repo)
+	if err != nil {
+		return report, err
+	}
+
+	for _, id := range ids {
+		v := verifyBug(repo, id, localRefPrefix(repo)+id, false)
+
+		switch {
+		case v.Incomplete != nil:
+			report.Incomplete++
+		case len(v.Problems) > 0:
+			report.Invalid++
+		default:
+			report.Valid++
+		}
+
+		report.Bugs = append(report.Bugs, v)
+	}
+
+	report.StaleLocks, err = staleRefLocks(repo)
+	if err != nil {
+		return report, err
+	}
+
+	return report, nil
+}
+
+// staleRefLocks return the lock files of the refs of the bugs and the drafts,
+// relative to the git directory. git remove them when it's interrupted, but
+// not when it's killed. Only the repositories on disk have them, in the
+// directory shared by the worktrees.
+func staleRefLocks(repo repository.Repo) ([]string, error) {
+	gitDir := repo.GetCommonDir()
+
+	var locks []string
+
+	for _, prefix := range []string{localRefPrefix(repo), draftRefPrefix(repo)} {
+		root := filepath.Join(gitDir, filepath.FromSlash(prefix))
+
+		err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if !info.IsDir() && strings.HasSuffix(path, ".lock") {
+				rel, err := filepath.Rel(gitDir, path)
+				if err != nil {
+					return err
+				}
+				locks = append(locks, filepath.ToSlash(rel))
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return locks, nil
+}
+
+// verifyBug read and validate the bug with the given id at a revision, a ref
+// or a commit hash. A bug received from a remote is also checked against the
+// size limits in effect.
+func verifyBug(repo repository.Repo, id string, revision string, received bool) BugVerification {
+	v := BugVerification{Id: id}
+
+	b, err := readBugRevision(repo, id, revision)
+	if e, ok := err.(*ErrIncompleteHistory); ok {
+		v.Incomplete = e
+		return v
+	}
+	if err != nil {
+		v.Problems = append(v.Problems, err.Error())
+		return v
+	}
+
+	if !b.IsValid() {
+		v.Problems = append(v.Problems, "invalid bug: it must start with a single create operation")
+	}
+
+	for _, pack := range b.packs {
+		v.Problems = append(v.Problems, validatePack(pack)...)
+
+		if received {
+			if err := checkPackSize(repo, pack); err != nil {
+				v.Problems = append(v.Problems, fmt.Sprintf("commit %s: %s", pack.commitHash, err))
+			}
+		}
+	}
+
+	problems, err := b.CheckMessageBlobs(repo)
+	if err != nil {
+		v.Problems = append(v.Problems, err.Error())
+	}
+	v.Problems = append(v.Problems, problems...)
+
+	return v
+}
+
+// validatePack check the data of the operations of a committed pack
+func validatePack(pack OperationPack) []string {
+	var problems []string
+
+	for _, op := range pack.Operations {
+		if err := op.Validate(); err != nil {
+			problems = append(problems, fmt.Sprintf("commit %s: invalid operation: %s", pack.commitHash, err))
+		}
+	}
+
+	return problems
+}
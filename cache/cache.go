@@ -1,9 +1,23 @@
+// Package cache is the public API to embed git-bug in another program.
+//
+// It allow to open a repository, list and search its bugs, create and edit
+// them, and exchange them with a git remote, without having to deal with the
+// way they are stored in git. The CLI, the termui and the webui are all built
+// on top of it.
+//
+// The interfaces of this package are meant to stay stable: a breaking change
+// is reflected in the version of git-bug.
 package cache
 
 import (
 	"fmt"
 	"io"
+	"runtime"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/MichaelMure/git-bug/bug"
 	"github.com/MichaelMure/git-bug/bug/operations"
@@ -11,97 +25,339 @@ import (
 	"github.com/MichaelMure/git-bug/util"
 )
 
+// AutoSubscribeConfigKey is the git config key to disable the automatic
+// subscription to the bugs one open or comment, with "false"
+const AutoSubscribeConfigKey = repository.PreferencePrefix + autoSubscribePreference
+
+const autoSubscribePreference = "autosubscribe"
+
+// WorkersConfigKey is the git config key to set the number of bugs read
+// concurrently when listing them. It default to GOMAXPROCS, to not overwhelm
+// git with processes.
+const WorkersConfigKey = repository.PreferencePrefix + workersPreference
+
+const workersPreference = "workers"
+
+// LabelCount is a label with the number of bugs having it
+type LabelCount struct {
+	Label bug.Label
+	Count int
+}
+
+// RebuildOptions tune RepoCacher.Rebuild
+type RebuildOptions struct {
+	// Jobs is the number of bugs read concurrently, the one configured with
+	// WorkersConfigKey if zero
+	Jobs int
+	// Progress, if set, is called after each bug read. The calls are never
+	// concurrent.
+	Progress func(progress RebuildProgress)
+}
+
+// RebuildProgress tell how far a rebuild went
+type RebuildProgress struct {
+	Done  int
+	Total int
+	// the time since the start of the rebuild
+	Elapsed time.Duration
+}
+
+// ETA estimate the time left, from the average time per bug so far
+func (p RebuildProgress) ETA() time.Duration {
+	if p.Done == 0 {
+		return 0
+	}
+	return p.Elapsed / time.Duration(p.Done) * time.Duration(p.Total-p.Done)
+}
+
+// RebuildResult summarize a rebuild
+type RebuildResult struct {
+	// the number of bugs read and cached
+	Loaded int
+	// the error of each bug that couldn't be read, by id. The bugs whose
+	// history is incomplete are reported by IncompleteBugs instead.
+	Failed map[string]error
+}
+
+// Cacher hold several repositories, identified by a reference
 type Cacher interface {
 	RegisterRepository(ref string, repo repository.Repo)
 	RegisterDefaultRepository(repo repository.Repo)
+	// RegisterLazyRepository register a repository opened on first access
+	RegisterLazyRepository(ref string, open func() (repository.Repo, error))
 
+	// Repositories return the references of the registered repositories,
+	// sorted, without opening them
+	Repositories() []string
 	ResolveRepo(ref string) (RepoCacher, error)
 	DefaultRepo() (RepoCacher, error)
 }
 
+// RepoCacher give access to the bugs of a repository
 type RepoCacher interface {
+	// Repository return the underlying git repository
 	Repository() repository.Repo
+
+	// SetAuthor define the identity used for the new bugs and edits,
+	// instead of the user configured in git
+	SetAuthor(author bug.Person)
+	// MyEmails return the emails of the identity used for the edits,
+	// including the alternate ones configured in git, see
+	// bug.IdentityEmails
+	MyEmails() ([]string, error)
+
+	// ResolveBug return the bug with the given id
 	ResolveBug(id string) (BugCacher, error)
+	// ResolveBugPrefix return the bug whose id start with the given prefix.
+	// If none match, the error list the bugs with a similar prefix.
 	ResolveBugPrefix(prefix string) (BugCacher, error)
+	// ResolveBugTitle return the only open bug whose title contain the
+	// query, ignoring the case. If none match, bug.ErrNoMatchingBug is
+	// returned.
+	ResolveBugTitle(query string) (BugCacher, error)
+	// AllBugIds return the ids of all the local bugs
 	AllBugIds() ([]string, error)
+	// HumanId return the shortest abbreviation of a bug id unique among the
+	// local bugs, see bug.AbbreviateIds. It's the HumanId of the snapshots.
+	HumanId(id string) string
+	// Search return the snapshot of the bugs accepted by the filter, or of
+	// all the bugs with a nil filter. The bugs whose history is incomplete
+	// in the repository are skipped, see IncompleteBugs.
+	Search(filter func(snap *bug.Snapshot) bool) ([]*bug.Snapshot, error)
+	// IncompleteBugs return the bugs skipped by the last Search, as their
+	// history is incomplete in a shallow or partial clone
+	IncompleteBugs() []*bug.ErrIncompleteHistory
+	// Labels return the labels set on the bugs, with the number of bugs
+	// having each, most used first
+	Labels() ([]LabelCount, error)
+	// ClearAllBugs drop the cached bugs, to be read again from git
 	ClearAllBugs()
+	// CachedBug return the bug with the given id only if it's already
+	// cached, without reading it from git
+	CachedBug(id string) (BugCacher, bool)
+	// ReadRefresh read from git the bugs whose ref moved since they were
+	// cached, and the ones not cached yet. The cache itself is only
+	// updated by Refresh.Apply, so that the reading can run in the
+	// background while the cache is used.
+	ReadRefresh() (*Refresh, error)
+	// Rebuild read again from git every bug, concurrently, and replace the
+	// cached ones once they are all read. A bug that can't be read is
+	// reported in the result instead of stopping the rebuild. The cached
+	// bugs with staged edits are kept as is.
+	Rebuild(opts RebuildOptions) (*RebuildResult, error)
 
 	// Mutations
+
+	// NewBug create and commit a new bug
 	NewBug(title string, message string) (BugCacher, error)
 	NewBugWithFiles(title string, message string, files []util.Hash) (BugCacher, error)
+	// CreateAndCommit is NewBug, describing the outcome in a bug.Result
+	CreateAndCommit(title string, message string) (bug.Result, error)
 	Fetch(remote string) (string, error)
 	MergeAll(remote string) <-chan bug.MergeResult
 	Pull(remote string, out io.Writer) error
+	PullWithOptions(remote string, out io.Writer, opts bug.MergeOptions) error
 	Push(remote string) (string, error)
 }
 
+// BugCacher give access to a single bug. Edits are staged until Commit is
+// called.
 type BugCacher interface {
+	// Snapshot return the current state of the bug, including the staged edits
 	Snapshot() *bug.Snapshot
 	ClearSnapshot()
 
 	// Mutations
 	AddComment(message string) error
 	AddCommentWithFiles(message string, files []util.Hash) error
-	ChangeLabels(added []string, removed []string) error
+	// DeleteComment hide the comment matching the hash prefix. Only the
+	// author of a comment can delete it, unless force is set.
+	DeleteComment(prefix string, force bool) error
+	// ArchiveComments move the comments created before the cutoff out of
+	// the snapshot, and return how many were archived
+	ArchiveComments(before time.Time) (int, error)
+	// ChangeLabels add and remove labels, explaining in out the labels
+	// that are ignored. out can be nil.
+	ChangeLabels(out io.Writer, added []string, removed []string) error
 	Open() error
 	Close() error
+	// CommentAndCommit, OpenAndCommit and CloseAndCommit commit the action
+	// along with the other staged edits, describing the outcome in a
+	// bug.Result
+	CommentAndCommit(message string) (bug.Result, error)
+	OpenAndCommit() (bug.Result, error)
+	CloseAndCommit() (bug.Result, error)
 	SetTitle(title string) error
-
+	// SetCustomField define a free form field of the bug, or remove it
+	// with an empty value
+	SetCustomField(key string, value string) error
+	// SetPriority change the priority of the bug
+	SetPriority(priority bug.Priority) error
+	// SetChecklist change the state of some items of a checklist defined in
+	// the ChecklistRegistry
+	SetChecklist(name string, states map[string]bug.ChecklistState) error
+	// AddTimeLog record some time spent on the bug, with an optional note
+	AddTimeLog(duration time.Duration, note string) error
+	// Subscribe and Unsubscribe add and remove the author to the people
+	// notified of the changes of the bug
+	Subscribe() error
+	Unsubscribe() error
+
+	// Commit store the staged edits in git
 	Commit() error
+	// CommitAsNeeded store the staged edits in git, if any
 	CommitAsNeeded() error
 }
 
+// OpenRepo open the git repository at the given path, using the bug
+// namespace configured in this repository rather than the one in use
+func OpenRepo(path string) (RepoCacher, error) {
+	repo, err := repository.NewGitRepo(path, bug.Witnesser)
+	if err != nil {
+		return nil, err
+	}
+
+	ns, err := bug.ConfiguredNamespace(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	namespaced, err := bug.WithNamespace(repo, ns)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewRepoCache(namespaced), nil
+}
+
 // Cacher ------------------------
 
 type RootCache struct {
+	// a pointer, as the RootCache is copied along with the graphql backend
+	mu    *sync.Mutex
 	repos map[string]RepoCacher
+	lazy  map[string]func() (repository.Repo, error)
 }
 
 func NewCache() RootCache {
 	return RootCache{
+		mu:    &sync.Mutex{},
 		repos: make(map[string]RepoCacher),
+		lazy:  make(map[string]func() (repository.Repo, error)),
 	}
 }
 
 func (c *RootCache) RegisterRepository(ref string, repo repository.Repo) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.lazy, ref)
 	c.repos[ref] = NewRepoCache(repo)
 }
 
 func (c *RootCache) RegisterDefaultRepository(repo repository.Repo) {
-	c.repos[""] = NewRepoCache(repo)
+	c.RegisterRepository("", repo)
+}
+
+func (c *RootCache) RegisterLazyRepository(ref string, open func() (repository.Repo, error)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.repos, ref)
+	c.lazy[ref] = open
+}
+
+func (c *RootCache) Repositories() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	refs := make([]string, 0, len(c.repos)+len(c.lazy))
+	for ref := range c.repos {
+		refs = append(refs, ref)
+	}
+	for ref := range c.lazy {
+		refs = append(refs, ref)
+	}
+
+	sort.Strings(refs)
+
+	return refs
 }
 
 func (c *RootCache) DefaultRepo() (RepoCacher, error) {
-	if len(c.repos) != 1 {
+	refs := c.Repositories()
+
+	if len(refs) != 1 {
 		return nil, fmt.Errorf("repository is not unique")
 	}
 
-	for _, r := range c.repos {
+	return c.ResolveRepo(refs[0])
+}
+
+func (c *RootCache) ResolveRepo(ref string) (RepoCacher, error) {
+	c.mu.Lock()
+
+	if r, ok := c.repos[ref]; ok {
+		c.mu.Unlock()
 		return r, nil
 	}
 
-	panic("unreachable")
-}
+	open, ok := c.lazy[ref]
+	c.mu.Unlock()
 
-func (c *RootCache) ResolveRepo(ref string) (RepoCacher, error) {
-	r, ok := c.repos[ref]
 	if !ok {
 		return nil, fmt.Errorf("unknown repo")
 	}
+
+	// opened without holding the lock, so that a slow repository doesn't
+	// block the access to the other ones
+	repo, err := open()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// opened concurrently, or registered again meanwhile
+	if r, ok := c.repos[ref]; ok {
+		return r, nil
+	}
+	if _, ok := c.lazy[ref]; !ok {
+		return nil, fmt.Errorf("unknown repo")
+	}
+
+	r := NewRepoCache(repo)
+	c.repos[ref] = r
+	delete(c.lazy, ref)
+
 	return r, nil
 }
 
 // Repo ------------------------
 
 type RepoCache struct {
-	repo repository.Repo
-	bugs map[string]BugCacher
+	repo       repository.Repo
+	bugs       map[string]BugCacher
+	incomplete []*bug.ErrIncompleteHistory
+	author     *bug.Person
+
+	// the last commit of each cached bug, when read or committed. Unlike
+	// the bugs, it's read from the background by ReadRefresh.
+	headsMu sync.Mutex
+	heads   map[string]util.Hash
+
+	// the abbreviation of the id of every local bug, computed again when
+	// an id is missing, like for a new bug
+	abbreviationsMu sync.Mutex
+	abbreviations   map[string]string
 }
 
 func NewRepoCache(r repository.Repo) RepoCacher {
 	return &RepoCache{
-		repo: r,
-		bugs: make(map[string]BugCacher),
+		repo:  r,
+		bugs:  make(map[string]BugCacher),
+		heads: make(map[string]util.Hash),
 	}
 }
 
@@ -109,6 +365,92 @@ func (c *RepoCache) Repository() repository.Repo {
 	return c.repo
 }
 
+func (c *RepoCache) SetAuthor(author bug.Person) {
+	c.author = &author
+}
+
+// getAuthor return the identity to use for new edits
+func (c *RepoCache) getAuthor() (bug.Person, error) {
+	if c.author != nil {
+		return *c.author, nil
+	}
+
+	return bug.DefaultIdentity(c.repo)
+}
+
+func (c *RepoCache) MyEmails() ([]string, error) {
+	author, err := c.getAuthor()
+	if err != nil {
+		return nil, err
+	}
+
+	return bug.IdentityEmails(c.repo, author)
+}
+
+// autoSubscribe tell if the author should be subscribed to the bugs they
+// open or comment
+func (c *RepoCache) autoSubscribe() (bool, error) {
+	value, err := repository.GetUserPreference(c.repo, autoSubscribePreference)
+	if err == repository.ErrNoConfigEntry {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid value for %s: %s", AutoSubscribeConfigKey, value)
+	}
+
+	return enabled, nil
+}
+
+// workers return the number of bugs to read concurrently
+func (c *RepoCache) workers() (int, error) {
+	value, err := repository.GetUserPreference(c.repo, workersPreference)
+	if err == repository.ErrNoConfigEntry {
+		return runtime.GOMAXPROCS(0), nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	workers, err := strconv.Atoi(value)
+	if err != nil || workers < 1 {
+		return 0, fmt.Errorf("invalid value for %s: %s", WorkersConfigKey, value)
+	}
+
+	return workers, nil
+}
+
+// subscribeAuthor subscribe the author to the bug if needed and enabled
+func (c *RepoCache) subscribeAuthor(b *bug.Bug, author bug.Person) error {
+	enabled, err := c.autoSubscribe()
+	if err != nil || !enabled {
+		return err
+	}
+
+	if !b.Compile().IsSubscribed(author) {
+		operations.Subscribe(b, author, author)
+	}
+
+	return nil
+}
+
+// cacheBug store a bug in the cache, recording its last commit
+func (c *RepoCache) cacheBug(id string, cached *BugCache) {
+	c.bugs[id] = cached
+	c.setHead(id, cached.bug.LastCommitHash())
+}
+
+func (c *RepoCache) setHead(id string, head util.Hash) {
+	c.headsMu.Lock()
+	defer c.headsMu.Unlock()
+
+	c.heads[id] = head
+}
+
 func (c *RepoCache) ResolveBug(id string) (BugCacher, error) {
 	cached, ok := c.bugs[id]
 	if ok {
@@ -120,10 +462,15 @@ func (c *RepoCache) ResolveBug(id string) (BugCacher, error) {
 		return nil, err
 	}
 
-	cached = NewBugCache(c.repo, b)
-	c.bugs[id] = cached
+	read := &BugCache{repoCache: c, bug: b}
+	c.cacheBug(id, read)
 
-	return cached, nil
+	return read, nil
+}
+
+func (c *RepoCache) CachedBug(id string) (BugCacher, bool) {
+	cached, ok := c.bugs[id]
+	return cached, ok
 }
 
 func (c *RepoCache) ResolveBugPrefix(prefix string) (BugCacher, error) {
@@ -147,24 +494,365 @@ func (c *RepoCache) ResolveBugPrefix(prefix string) (BugCacher, error) {
 		return b, nil
 	}
 
-	b, err := bug.FindLocalBug(c.repo, prefix)
+	b, suggestions, err := bug.FindBugWithSuggestions(c.repo, prefix)
+
+	if err == bug.ErrNoMatchingBug && len(suggestions) > 0 {
+		return nil, fmt.Errorf("%s Did you mean:\n%s", err, strings.Join(suggestions, "\n"))
+	}
+
+	if err != nil {
+		return nil, err
+	}
 
+	id, err := b.IdSafe()
 	if err != nil {
 		return nil, err
 	}
 
-	cached := NewBugCache(c.repo, b)
-	c.bugs[b.Id()] = cached
+	cached := &BugCache{repoCache: c, bug: b}
+	c.cacheBug(id, cached)
 
 	return cached, nil
 }
 
+func (c *RepoCache) ResolveBugTitle(query string) (BugCacher, error) {
+	query = strings.ToLower(query)
+
+	matching, err := c.Search(func(snap *bug.Snapshot) bool {
+		return snap.Status == bug.OpenStatus &&
+			strings.Contains(strings.ToLower(snap.Title), query)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(matching) == 0 {
+		return nil, bug.ErrNoMatchingBug
+	}
+
+	if len(matching) > 1 {
+		candidates := make([]string, len(matching))
+		for i, snap := range matching {
+			candidates[i] = fmt.Sprintf("%s %s", snap.HumanId(), snap.Title)
+		}
+		return nil, fmt.Errorf("Multiple open bugs have a matching title:\n%s", strings.Join(candidates, "\n"))
+	}
+
+	return c.bugs[matching[0].Id()], nil
+}
+
 func (c *RepoCache) AllBugIds() ([]string, error) {
 	return bug.ListLocalIds(c.repo)
 }
 
+func (c *RepoCache) HumanId(id string) string {
+	c.abbreviationsMu.Lock()
+	defer c.abbreviationsMu.Unlock()
+
+	if abbrev, ok := c.abbreviations[id]; ok {
+		return abbrev
+	}
+
+	abbreviations, err := bug.LocalAbbreviations(c.repo)
+	if err != nil {
+		return bug.FormatHumanId(id)
+	}
+
+	c.abbreviations = abbreviations
+
+	if abbrev, ok := abbreviations[id]; ok {
+		return abbrev
+	}
+	return bug.FormatHumanId(id)
+}
+
+func (c *RepoCache) Search(filter func(snap *bug.Snapshot) bool) ([]*bug.Snapshot, error) {
+	ids, err := c.AllBugIds()
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.loadBugs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*bug.Snapshot
+
+	for _, id := range ids {
+		cached, ok := c.bugs[id]
+		if !ok {
+			// incomplete history
+			continue
+		}
+
+		snap := cached.Snapshot()
+
+		if filter == nil || filter(snap) {
+			result = append(result, snap)
+		}
+	}
+
+	return result, nil
+}
+
+// loadBugs read and compile concurrently the bugs not cached yet. The cache
+// itself is only updated once they are all read, in the order of the ids, so
+// that the first error is the same as when reading them one by one.
+func (c *RepoCache) loadBugs(ids []string) error {
+	var missing []string
+	for _, id := range ids {
+		if _, ok := c.bugs[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+
+	loaded, errs, err := c.readBugs(missing)
+	if err != nil {
+		return err
+	}
+
+	c.incomplete = nil
+
+	for i, id := range missing {
+		if incomplete, ok := errs[i].(*bug.ErrIncompleteHistory); ok {
+			c.incomplete = append(c.incomplete, incomplete)
+			continue
+		}
+		if errs[i] != nil {
+			return errs[i]
+		}
+		c.cacheBug(id, loaded[i])
+	}
+
+	return nil
+}
+
+// readBugs read and compile concurrently the given bugs, without touching
+// the cache, returning them or the error of each
+func (c *RepoCache) readBugs(ids []string) ([]*BugCache, []error, error) {
+	workers, err := c.workers()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	loaded, errs := c.readBugsWith(ids, workers, nil)
+
+	return loaded, errs, nil
+}
+
+// readBugsWith is readBugs with the given number of workers, calling
+// progress, if set, with the number of bugs read after each one
+func (c *RepoCache) readBugsWith(ids []string, workers int, progress func(done int)) ([]*BugCache, []error) {
+	loaded := make([]*BugCache, len(ids))
+	errs := make([]error, len(ids))
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+
+	var progressMu sync.Mutex
+	done := 0
+	report := func() {
+		if progress == nil {
+			return
+		}
+
+		progressMu.Lock()
+		defer progressMu.Unlock()
+
+		done++
+		progress(done)
+	}
+
+	for w := 0; w < workers && w < len(ids); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := range indexes {
+				b, err := bug.ReadLocalBug(c.repo, ids[i])
+				if err != nil {
+					errs[i] = err
+					report()
+					continue
+				}
+
+				cached := &BugCache{repoCache: c, bug: b}
+				cached.Snapshot()
+				loaded[i] = cached
+				report()
+			}
+		}()
+	}
+
+	for i := range ids {
+		indexes <- i
+	}
+	close(indexes)
+
+	wg.Wait()
+
+	return loaded, errs
+}
+
+func (c *RepoCache) Rebuild(opts RebuildOptions) (*RebuildResult, error) {
+	jobs := opts.Jobs
+	if jobs < 0 {
+		return nil, fmt.Errorf("invalid number of jobs %d", jobs)
+	}
+	if jobs == 0 {
+		var err error
+		jobs, err = c.workers()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ids, err := bug.ListLocalIds(c.repo)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(ids)
+
+	start := time.Now()
+
+	var progress func(done int)
+	if opts.Progress != nil {
+		progress = func(done int) {
+			opts.Progress(RebuildProgress{Done: done, Total: len(ids), Elapsed: time.Since(start)})
+		}
+	}
+
+	loaded, errs := c.readBugsWith(ids, jobs, progress)
+
+	// the cache is replaced at once, so that it's never half rebuilt
+	previous := c.bugs
+	c.ClearAllBugs()
+	c.incomplete = nil
+
+	result := &RebuildResult{Failed: make(map[string]error)}
+
+	for i, id := range ids {
+		if existing, ok := previous[id].(*BugCache); ok && existing.bug.HasPendingOp() {
+			c.cacheBug(id, existing)
+			continue
+		}
+
+		if incomplete, ok := errs[i].(*bug.ErrIncompleteHistory); ok {
+			c.incomplete = append(c.incomplete, incomplete)
+			continue
+		}
+		if errs[i] != nil {
+			result.Failed[id] = errs[i]
+			continue
+		}
+
+		c.cacheBug(id, loaded[i])
+		result.Loaded++
+	}
+
+	return result, nil
+}
+
+func (c *RepoCache) IncompleteBugs() []*bug.ErrIncompleteHistory {
+	return c.incomplete
+}
+
+func (c *RepoCache) Labels() ([]LabelCount, error) {
+	snaps, err := c.Search(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[bug.Label]int)
+	for _, snap := range snaps {
+		for _, label := range snap.Labels {
+			counts[label]++
+		}
+	}
+
+	result := make([]LabelCount, 0, len(counts))
+	for label, count := range counts {
+		result = append(result, LabelCount{Label: label, Count: count})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Label < result[j].Label
+	})
+
+	return result, nil
+}
+
 func (c *RepoCache) ClearAllBugs() {
 	c.bugs = make(map[string]BugCacher)
+
+	c.abbreviationsMu.Lock()
+	c.abbreviations = nil
+	c.abbreviationsMu.Unlock()
+
+	c.headsMu.Lock()
+	defer c.headsMu.Unlock()
+
+	c.heads = make(map[string]util.Hash)
+}
+
+func (c *RepoCache) ReadRefresh() (*Refresh, error) {
+	heads, err := bug.ListLocalHeads(c.repo)
+	if err != nil {
+		return nil, err
+	}
+
+	c.headsMu.Lock()
+	cached := make(map[string]util.Hash, len(c.heads))
+	for id, head := range c.heads {
+		cached[id] = head
+	}
+	c.headsMu.Unlock()
+
+	r := &Refresh{
+		repoCache: c,
+		previous:  make(map[string]util.Hash),
+		bugs:      make(map[string]*BugCache),
+	}
+
+	var ids []string
+	for id, head := range heads {
+		if previous, ok := cached[id]; !ok || previous != head {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	loaded, errs, err := c.readBugs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, id := range ids {
+		if incomplete, ok := errs[i].(*bug.ErrIncompleteHistory); ok {
+			r.incomplete = append(r.incomplete, incomplete)
+			continue
+		}
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		if head, ok := cached[id]; ok {
+			r.previous[id] = head
+		}
+		r.bugs[id] = loaded[i]
+	}
+
+	for id, head := range cached {
+		if _, ok := heads[id]; !ok {
+			r.previous[id] = head
+			r.removed = append(r.removed, id)
+		}
+	}
+
+	return r, nil
 }
 
 func (c *RepoCache) NewBug(title string, message string) (BugCacher, error) {
@@ -172,7 +860,15 @@ func (c *RepoCache) NewBug(title string, message string) (BugCacher, error) {
 }
 
 func (c *RepoCache) NewBugWithFiles(title string, message string, files []util.Hash) (BugCacher, error) {
-	author, err := bug.GetUser(c.repo)
+	b, err := c.newBug(title, message, files)
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func (c *RepoCache) newBug(title string, message string, files []util.Hash) (*BugCache, error) {
+	author, err := c.getAuthor()
 	if err != nil {
 		return nil, err
 	}
@@ -182,17 +878,36 @@ func (c *RepoCache) NewBugWithFiles(title string, message string, files []util.H
 		return nil, err
 	}
 
+	err = c.subscribeAuthor(b, author)
+	if err != nil {
+		return nil, err
+	}
+
 	err = b.Commit(c.repo)
 	if err != nil {
 		return nil, err
 	}
 
-	cached := NewBugCache(c.repo, b)
-	c.bugs[b.Id()] = cached
+	id, err := b.IdSafe()
+	if err != nil {
+		return nil, err
+	}
+
+	cached := &BugCache{repoCache: c, bug: b}
+	c.cacheBug(id, cached)
 
 	return cached, nil
 }
 
+func (c *RepoCache) CreateAndCommit(title string, message string) (bug.Result, error) {
+	b, err := c.newBug(title, message, nil)
+	if err != nil {
+		return bug.Result{}, err
+	}
+
+	return b.result("%s created"), nil
+}
+
 func (c *RepoCache) Fetch(remote string) (string, error) {
 	return bug.Fetch(c.repo, remote)
 }
@@ -205,6 +920,10 @@ func (c *RepoCache) Pull(remote string, out io.Writer) error {
 	return bug.Pull(c.repo, out, remote)
 }
 
+func (c *RepoCache) PullWithOptions(remote string, out io.Writer, opts bug.MergeOptions) error {
+	return bug.PullWithOptions(c.repo, out, remote, opts)
+}
+
 func (c *RepoCache) Push(remote string) (string, error) {
 	return bug.Push(c.repo, remote)
 }
@@ -212,21 +931,24 @@ func (c *RepoCache) Push(remote string) (string, error) {
 // Bug ------------------------
 
 type BugCache struct {
-	repo repository.Repo
-	bug  *bug.Bug
-	snap *bug.Snapshot
+	repoCache *RepoCache
+	bug       *bug.Bug
+	snap      *bug.Snapshot
 }
 
-func NewBugCache(repo repository.Repo, b *bug.Bug) BugCacher {
+func NewBugCache(repoCache *RepoCache, b *bug.Bug) BugCacher {
 	return &BugCache{
-		repo: repo,
-		bug:  b,
+		repoCache: repoCache,
+		bug:       b,
 	}
 }
 
 func (c *BugCache) Snapshot() *bug.Snapshot {
 	if c.snap == nil {
 		snap := c.bug.Compile()
+		if id, err := c.bug.IdSafe(); err == nil {
+			snap.SetHumanId(c.repoCache.HumanId(id))
+		}
 		c.snap = &snap
 	}
 	return c.snap
@@ -241,12 +963,20 @@ func (c *BugCache) AddComment(message string) error {
 }
 
 func (c *BugCache) AddCommentWithFiles(message string, files []util.Hash) error {
-	author, err := bug.GetUser(c.repo)
+	author, err := c.repoCache.getAuthor()
 	if err != nil {
 		return err
 	}
 
-	operations.CommentWithFiles(c.bug, author, message, files)
+	err = operations.CommentWithFiles(c.bug, author, message, files)
+	if err != nil {
+		return err
+	}
+
+	err = c.repoCache.subscribeAuthor(c.bug, author)
+	if err != nil {
+		return err
+	}
 
 	// TODO: perf --> the snapshot could simply be updated with the new op
 	c.ClearSnapshot()
@@ -254,13 +984,47 @@ func (c *BugCache) AddCommentWithFiles(message string, files []util.Hash) error
 	return nil
 }
 
-func (c *BugCache) ChangeLabels(added []string, removed []string) error {
-	author, err := bug.GetUser(c.repo)
+func (c *BugCache) DeleteComment(prefix string, force bool) error {
+	author, err := c.repoCache.getAuthor()
 	if err != nil {
 		return err
 	}
 
-	err = operations.ChangeLabels(nil, c.bug, author, added, removed)
+	err = operations.DeleteComment(c.bug, author, prefix, force)
+	if err != nil {
+		return err
+	}
+
+	// TODO: perf --> the snapshot could simply be updated with the new op
+	c.ClearSnapshot()
+
+	return nil
+}
+
+func (c *BugCache) ArchiveComments(before time.Time) (int, error) {
+	author, err := c.repoCache.getAuthor()
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := operations.ArchiveComments(c.repoCache.repo, c.bug, author, before)
+	if err != nil {
+		return 0, err
+	}
+
+	// TODO: perf --> the snapshot could simply be updated with the new op
+	c.ClearSnapshot()
+
+	return count, nil
+}
+
+func (c *BugCache) ChangeLabels(out io.Writer, added []string, removed []string) error {
+	author, err := c.repoCache.getAuthor()
+	if err != nil {
+		return err
+	}
+
+	err = operations.ChangeLabels(out, c.bug, author, added, removed)
 	if err != nil {
 		return err
 	}
@@ -272,7 +1036,7 @@ func (c *BugCache) ChangeLabels(added []string, removed []string) error {
 }
 
 func (c *BugCache) Open() error {
-	author, err := bug.GetUser(c.repo)
+	author, err := c.repoCache.getAuthor()
 	if err != nil {
 		return err
 	}
@@ -286,7 +1050,7 @@ func (c *BugCache) Open() error {
 }
 
 func (c *BugCache) Close() error {
-	author, err := bug.GetUser(c.repo)
+	author, err := c.repoCache.getAuthor()
 	if err != nil {
 		return err
 	}
@@ -299,13 +1063,165 @@ func (c *BugCache) Close() error {
 	return nil
 }
 
+func (c *BugCache) CommentAndCommit(message string) (bug.Result, error) {
+	if err := c.AddComment(message); err != nil {
+		return bug.Result{}, err
+	}
+
+	return c.commitWithResult("%s commented")
+}
+
+func (c *BugCache) OpenAndCommit() (bug.Result, error) {
+	if err := c.Open(); err != nil {
+		return bug.Result{}, err
+	}
+
+	return c.commitWithResult("%s opened")
+}
+
+func (c *BugCache) CloseAndCommit() (bug.Result, error) {
+	if err := c.Close(); err != nil {
+		return bug.Result{}, err
+	}
+
+	return c.commitWithResult("%s closed")
+}
+
+func (c *BugCache) commitWithResult(format string) (bug.Result, error) {
+	if err := c.Commit(); err != nil {
+		return bug.Result{}, err
+	}
+
+	return c.result(format), nil
+}
+
+// result describe the last commit of a stored bug
+func (c *BugCache) result(format string) bug.Result {
+	id := c.bug.Id()
+
+	return bug.Result{
+		Id:      id,
+		Commit:  c.bug.LastCommitHash(),
+		Message: fmt.Sprintf(format, c.repoCache.HumanId(id)),
+	}
+}
+
 func (c *BugCache) SetTitle(title string) error {
-	author, err := bug.GetUser(c.repo)
+	author, err := c.repoCache.getAuthor()
+	if err != nil {
+		return err
+	}
+
+	err = operations.SetTitle(c.bug, author, title)
+	if err != nil {
+		return err
+	}
+
+	// TODO: perf --> the snapshot could simply be updated with the new op
+	c.ClearSnapshot()
+
+	return nil
+}
+
+func (c *BugCache) SetCustomField(key string, value string) error {
+	author, err := c.repoCache.getAuthor()
+	if err != nil {
+		return err
+	}
+
+	err = operations.SetCustomField(c.bug, author, key, value)
+	if err != nil {
+		return err
+	}
+
+	// TODO: perf --> the snapshot could simply be updated with the new op
+	c.ClearSnapshot()
+
+	return nil
+}
+
+func (c *BugCache) SetPriority(priority bug.Priority) error {
+	author, err := c.repoCache.getAuthor()
+	if err != nil {
+		return err
+	}
+
+	err = operations.SetPriority(c.bug, author, priority)
+	if err != nil {
+		return err
+	}
+
+	// TODO: perf --> the snapshot could simply be updated with the new op
+	c.ClearSnapshot()
+
+	return nil
+}
+
+func (c *BugCache) SetChecklist(name string, states map[string]bug.ChecklistState) error {
+	author, err := c.repoCache.getAuthor()
 	if err != nil {
 		return err
 	}
 
-	operations.SetTitle(c.bug, author, title)
+	registry, err := bug.ReadChecklistRegistry(c.repoCache.repo)
+	if err != nil {
+		return err
+	}
+
+	definition, ok := registry.Lookup(name)
+	if !ok {
+		return fmt.Errorf("unknown checklist %s", name)
+	}
+
+	err = operations.SetChecklist(c.bug, author, name, definition, states)
+	if err != nil {
+		return err
+	}
+
+	// TODO: perf --> the snapshot could simply be updated with the new op
+	c.ClearSnapshot()
+
+	return nil
+}
+
+func (c *BugCache) AddTimeLog(duration time.Duration, note string) error {
+	author, err := c.repoCache.getAuthor()
+	if err != nil {
+		return err
+	}
+
+	err = operations.AddTimeLog(c.bug, author, duration, note)
+	if err != nil {
+		return err
+	}
+
+	// TODO: perf --> the snapshot could simply be updated with the new op
+	c.ClearSnapshot()
+
+	return nil
+}
+
+func (c *BugCache) Subscribe() error {
+	author, err := c.repoCache.getAuthor()
+	if err != nil {
+		return err
+	}
+
+	operations.Subscribe(c.bug, author, author)
+
+	// TODO: perf --> the snapshot could simply be updated with the new op
+	c.ClearSnapshot()
+
+	return nil
+}
+
+func (c *BugCache) Unsubscribe() error {
+	author, err := c.repoCache.getAuthor()
+	if err != nil {
+		return err
+	}
+
+	operations.Unsubscribe(c.bug, author, author)
 
 	// TODO: perf --> the snapshot could simply be updated with the new op
 	c.ClearSnapshot()
@@ -314,12 +1230,34 @@ func (c *BugCache) SetTitle(title string) error {
 }
 
 func (c *BugCache) Commit() error {
-	return c.bug.Commit(c.repo)
+	return c.CommitWithOptions(bug.CommitOptions{})
+}
+
+// CommitWithOptions is Commit, with the given options. It returns
+// bug.ErrEmptyCommit if the pending operations were discarded.
+func (c *BugCache) CommitWithOptions(opts bug.CommitOptions) error {
+	// not interrupted between the commit and the update of the cache
+	return util.Critical(func() error {
+		err := c.bug.CommitWithOptions(c.repoCache.repo, opts)
+		if err == bug.ErrEmptyCommit {
+			// the snapshot included the discarded operations
+			c.ClearSnapshot()
+			return err
+		}
+		if err != nil {
+			return err
+		}
+
+		// only this bug is up to date, no need to read it again on refresh
+		c.repoCache.setHead(c.bug.Id(), c.bug.LastCommitHash())
+
+		return nil
+	})
 }
 
 func (c *BugCache) CommitAsNeeded() error {
 	if c.bug.HasPendingOp() {
-		return c.bug.Commit(c.repo)
+		return c.Commit()
 	}
 	return nil
 }
@@ -0,0 +1,109 @@
+package cache_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+// createRepo create an empty git repository for the examples
+func createRepo() string {
+	dir, err := ioutil.TempDir("", "git-bug-example")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	_, err = repository.InitGitRepo(dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return dir
+}
+
+func ExampleOpenRepo() {
+	dir := createRepo()
+	defer os.RemoveAll(dir)
+
+	repo, err := cache.OpenRepo(dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// By default, the user configured in git is used
+	repo.SetAuthor(bug.Person{Name: "René Descartes", Email: "rene@descartes.fr"})
+
+	b, err := repo.NewBug("Crash on startup", "The application crash when started offline")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = b.AddComment("Fixed in the next version")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = b.Close()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = b.Commit()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	snap := b.Snapshot()
+	fmt.Println(snap.Title, snap.Status, len(snap.Comments))
+
+	// Output: Crash on startup closed 2
+}
+
+func ExampleRepoCache_Search() {
+	dir := createRepo()
+	defer os.RemoveAll(dir)
+
+	repo, err := cache.OpenRepo(dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	repo.SetAuthor(bug.Person{Name: "René Descartes", Email: "rene@descartes.fr"})
+
+	for _, title := range []string{"first bug", "second bug"} {
+		b, err := repo.NewBug(title, "message")
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if title == "first bug" {
+			err = b.Close()
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			err = b.Commit()
+			if err != nil {
+				log.Fatal(err)
+			}
+		}
+	}
+
+	open, err := repo.Search(func(snap *bug.Snapshot) bool {
+		return snap.Status == bug.OpenStatus
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, snap := range open {
+		fmt.Println(snap.Title)
+	}
+
+	// Output: second bug
+}
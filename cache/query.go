@@ -0,0 +1,451 @@
+package cache
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/MichaelMure/git-bug/bug"
+)
+
+// Query select the bugs matching all of its criteria. It's written as
+// space separated "key:value" terms:
+//
+//	status:open          the status of the bug, open or closed
+//	priority:high        the priority of the bug, low, normal, high or critical
+//	author:me            the email of the author of the bug, or me
+//	participant:me       the email of someone who edited the bug, or me
+//	label:UI             a label of the bug
+//	field:severity=high  the value of a custom field
+//	checklist:qa:done    a checklist with every item done, or still pending
+//	sort:edit-desc       the order of the results, by priority, edit or creation,
+//	                     ascending (-asc) or descending (-desc)
+//	no:label             a field left empty, only the labels for now
+//
+// A term is negated with a "-" prefix or a "!" suffix on its key, like
+// -label:wontfix or label!:wontfix: the bugs matching it are excluded. The
+// negated terms are independent, a bug matching any of them is excluded.
+//
+// The emails are compared as with bug.Person.HasEmail. The "me" token stand
+// for the configured identity, see ResolveMe.
+type Query struct {
+	Status       []bug.Status
+	Priority     []bug.Priority
+	Authors      []string
+	Participants []string
+	Labels       []bug.Label
+	CustomFields map[string]string
+	Checklists   map[string]bug.ChecklistState
+	Mine         MineFilter
+	OrderBy      OrderBy
+	// the bugs without any label
+	NoLabel bool
+	// the negated terms, one query each: a bug matching any of them is
+	// excluded
+	Exclusions []*Query
+
+	// the emails of the user, for the "me" token and Mine
+	me []string
+}
+
+// meToken stand for the user in the author and participant terms
+const meToken = "me"
+
+// MineFilter select the bugs of the user, in addition to the other criteria
+type MineFilter int
+
+const (
+	// every bug
+	MineNone MineFilter = iota
+	// the bugs opened by the user
+	MineAuthor
+	// the bugs edited by the user after their creation
+	MineParticipating
+	// the bugs opened or edited by the user
+	MineAll
+)
+
+// ParseMineFilter parse the name of a MineFilter: author, participating or
+// all
+func ParseMineFilter(value string) (MineFilter, error) {
+	switch value {
+	case "author":
+		return MineAuthor, nil
+	case "participating":
+		return MineParticipating, nil
+	case "all":
+		return MineAll, nil
+	case "assigned":
+		return MineNone, fmt.Errorf("the bugs can't be assigned yet")
+	default:
+		return MineNone, fmt.Errorf("unknown filter \"%s\", expected author, participating or all", value)
+	}
+}
+
+// OrderBy is the order of the bugs selected by a query
+type OrderBy int
+
+const (
+	// the order the bugs are listed in
+	OrderByDefault OrderBy = iota
+	OrderByPriorityAsc
+	OrderByPriorityDesc
+	OrderByEditAsc
+	OrderByEditDesc
+	OrderByCreationAsc
+	OrderByCreationDesc
+)
+
+// ParseQuery parse a query string. An empty query match every bug.
+func ParseQuery(query string) (*Query, error) {
+	result := &Query{
+		CustomFields: make(map[string]string),
+		Checklists:   make(map[string]bug.ChecklistState),
+	}
+
+	for _, term := range strings.Fields(query) {
+		split := strings.SplitN(term, ":", 2)
+		if len(split) != 2 || split[1] == "" {
+			return nil, fmt.Errorf("invalid query term \"%s\", expected key:value", term)
+		}
+
+		key, value := split[0], split[1]
+
+		if negated, ok := negatedKey(key); ok {
+			if negated == "sort" {
+				return nil, fmt.Errorf("the sort can't be negated")
+			}
+			exclusion, err := ParseQuery(negated + ":" + value)
+			if err != nil {
+				return nil, err
+			}
+			result.Exclusions = append(result.Exclusions, exclusion)
+			continue
+		}
+
+		switch key {
+		case "status":
+			status, err := parseStatus(value)
+			if err != nil {
+				return nil, err
+			}
+			result.Status = append(result.Status, status)
+
+		case "priority":
+			priority, err := bug.ParsePriority(value)
+			if err != nil {
+				return nil, err
+			}
+			result.Priority = append(result.Priority, priority)
+
+		case "author":
+			result.Authors = append(result.Authors, value)
+
+		case "participant":
+			result.Participants = append(result.Participants, value)
+
+		case "label":
+			result.Labels = append(result.Labels, bug.Label(value))
+
+		case "field":
+			field := strings.SplitN(value, "=", 2)
+			if len(field) != 2 || field[0] == "" {
+				return nil, fmt.Errorf("invalid field query \"%s\", expected field:key=value", value)
+			}
+			result.CustomFields[field[0]] = field[1]
+
+		case "checklist":
+			checklist := strings.SplitN(value, ":", 2)
+			if len(checklist) != 2 || checklist[0] == "" {
+				return nil, fmt.Errorf("invalid checklist query \"%s\", expected checklist:name:state", value)
+			}
+			state, err := bug.ParseChecklistState(checklist[1])
+			if err != nil {
+				return nil, err
+			}
+			if state == bug.ChecklistNA {
+				return nil, fmt.Errorf("invalid checklist query \"%s\", expected a done or pending state", value)
+			}
+			result.Checklists[checklist[0]] = state
+
+		case "sort":
+			orderBy, err := parseOrderBy(value)
+			if err != nil {
+				return nil, err
+			}
+			result.OrderBy = orderBy
+
+		case "no":
+			switch value {
+			case "label":
+				result.NoLabel = true
+			case "assignee":
+				return nil, fmt.Errorf("the bugs can't be assigned yet")
+			case "milestone":
+				return nil, fmt.Errorf("the bugs can't have a milestone yet")
+			default:
+				return nil, fmt.Errorf("unknown field \"%s\", expected one of %s", value, strings.Join(EmptiableFields, ", "))
+			}
+
+		default:
+			return nil, fmt.Errorf("unknown query key \"%s\"", key)
+		}
+	}
+
+	return result, nil
+}
+
+// EmptiableFields are the fields a query can require to be empty, with no:
+var EmptiableFields = []string{"label"}
+
+// negatedKey return the key of a negated term, written -key or key!
+func negatedKey(key string) (string, bool) {
+	switch {
+	case len(key) > 1 && strings.HasPrefix(key, "-"):
+		return key[1:], true
+	case len(key) > 1 && strings.HasSuffix(key, "!"):
+		return key[:len(key)-1], true
+	default:
+		return key, false
+	}
+}
+
+func parseStatus(value string) (bug.Status, error) {
+	switch value {
+	case "open":
+		return bug.OpenStatus, nil
+	case "closed":
+		return bug.ClosedStatus, nil
+	default:
+		return 0, fmt.Errorf("unknown status \"%s\"", value)
+	}
+}
+
+// ResolveMe read the emails of the user when the query need them, that is
+// with the "me" token or Mine
+func (q *Query) ResolveMe(c RepoCacher) error {
+	if !q.needMe() {
+		return nil
+	}
+
+	emails, err := c.MyEmails()
+	if err != nil {
+		return err
+	}
+
+	q.setMe(emails)
+
+	return nil
+}
+
+func (q *Query) needMe() bool {
+	if q.Mine != MineNone || hasMe(q.Authors) || hasMe(q.Participants) {
+		return true
+	}
+
+	for _, exclusion := range q.Exclusions {
+		if exclusion.needMe() {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (q *Query) setMe(emails []string) {
+	q.me = emails
+
+	for _, exclusion := range q.Exclusions {
+		exclusion.setMe(emails)
+	}
+}
+
+func hasMe(values []string) bool {
+	for _, value := range values {
+		if value == meToken {
+			return true
+		}
+	}
+
+	return false
+}
+
+func parseOrderBy(value string) (OrderBy, error) {
+	switch value {
+	case "priority-asc":
+		return OrderByPriorityAsc, nil
+	case "priority-desc":
+		return OrderByPriorityDesc, nil
+	case "edit-asc":
+		return OrderByEditAsc, nil
+	case "edit-desc":
+		return OrderByEditDesc, nil
+	case "creation-asc":
+		return OrderByCreationAsc, nil
+	case "creation-desc":
+		return OrderByCreationDesc, nil
+	default:
+		return 0, fmt.Errorf("unknown sort \"%s\", expected priority, edit or creation, followed by -asc or -desc", value)
+	}
+}
+
+// Match tell if a bug fulfill the query. A bug match if it has one of the
+// queried status, one of the queried priorities, one of the queried authors,
+// all the queried participants, all the queried labels, all the queried
+// custom fields and all the queried checklists, without matching any of the
+// negated terms.
+func (q *Query) Match(snap *bug.Snapshot) bool {
+	if len(q.Status) > 0 && !q.matchStatus(snap.Status) {
+		return false
+	}
+
+	if len(q.Priority) > 0 && !q.matchPriority(snap.Priority) {
+		return false
+	}
+
+	if len(q.Authors) > 0 && !snap.Author.HasEmail(q.emails(q.Authors)...) {
+		return false
+	}
+
+	for _, participant := range q.Participants {
+		if !isParticipant(snap, q.emails([]string{participant})) {
+			return false
+		}
+	}
+
+	switch q.Mine {
+	case MineAuthor:
+		if !snap.Author.HasEmail(q.me...) {
+			return false
+		}
+	case MineParticipating:
+		if !isParticipant(snap, q.me) {
+			return false
+		}
+	case MineAll:
+		if !snap.Author.HasEmail(q.me...) && !isParticipant(snap, q.me) {
+			return false
+		}
+	}
+
+	for _, label := range q.Labels {
+		if !snap.ContainsLabel(string(label)) {
+			return false
+		}
+	}
+
+	for key, value := range q.CustomFields {
+		if snap.CustomFields[key] != value {
+			return false
+		}
+	}
+
+	for name, state := range q.Checklists {
+		checklist, ok := snap.Checklist(name)
+		if !ok || checklist.IsComplete() != (state == bug.ChecklistDone) {
+			return false
+		}
+	}
+
+	if q.NoLabel && len(snap.Labels) > 0 {
+		return false
+	}
+
+	for _, exclusion := range q.Exclusions {
+		if exclusion.Match(snap) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (q *Query) matchStatus(status bug.Status) bool {
+	for _, s := range q.Status {
+		if s == status {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (q *Query) matchPriority(priority bug.Priority) bool {
+	for _, p := range q.Priority {
+		if p == priority {
+			return true
+		}
+	}
+
+	return false
+}
+
+// emails replace the "me" token by the emails of the user
+func (q *Query) emails(values []string) []string {
+	var result []string
+	for _, value := range values {
+		if value == meToken {
+			result = append(result, q.me...)
+		} else {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+// isParticipant tell if someone with one of the emails edited the bug after
+// its creation. The edits made with the creation, like the subscription of
+// the author, don't count.
+func isParticipant(snap *bug.Snapshot, emails []string) bool {
+	creation := true
+	for i, op := range snap.Operations {
+		creation = creation && (i == 0 || snap.SameEdit(i))
+		if !creation && op.GetAuthor().HasEmail(emails...) {
+			return true
+		}
+	}
+
+	if len(snap.Operations) > 0 {
+		return false
+	}
+
+	// the decoded snapshots have no operations, only the comments are known
+	for i, comment := range snap.Comments {
+		if i > 0 && comment.Author.HasEmail(emails...) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Sort order the bugs as requested by the query. The sort is stable, so that
+// the bugs of the same priority or time stay in their original order.
+func (q *Query) Sort(snaps []*bug.Snapshot) {
+	switch q.OrderBy {
+	case OrderByPriorityAsc:
+		sort.SliceStable(snaps, func(i, j int) bool {
+			return snaps[i].Priority < snaps[j].Priority
+		})
+	case OrderByPriorityDesc:
+		sort.SliceStable(snaps, func(i, j int) bool {
+			return snaps[i].Priority > snaps[j].Priority
+		})
+	case OrderByEditAsc:
+		sort.SliceStable(snaps, func(i, j int) bool {
+			return snaps[i].LastEdit().Before(snaps[j].LastEdit())
+		})
+	case OrderByEditDesc:
+		sort.SliceStable(snaps, func(i, j int) bool {
+			return snaps[i].LastEdit().After(snaps[j].LastEdit())
+		})
+	case OrderByCreationAsc:
+		sort.SliceStable(snaps, func(i, j int) bool {
+			return snaps[i].CreatedAt.Before(snaps[j].CreatedAt)
+		})
+	case OrderByCreationDesc:
+		sort.SliceStable(snaps, func(i, j int) bool {
+			return snaps[i].CreatedAt.After(snaps[j].CreatedAt)
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package cache
+
+import (
+	"sort"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/util"
+)
+
+// Refresh hold the bugs read from git by RepoCacher.ReadRefresh, not applied
+// to the cache yet
+type Refresh struct {
+	repoCache *RepoCache
+	// the last commit of the bugs as cached when the refresh was read, to
+	// not override the ones committed since
+	previous   map[string]util.Hash
+	bugs       map[string]*BugCache
+	removed    []string
+	incomplete []*bug.ErrIncompleteHistory
+}
+
+// Apply update the cache with the bugs read, and return the ids of the bugs
+// whose cached state changed or that were removed, sorted. The bugs already
+// cached are updated in place, so that the BugCacher given before see the
+// changes, unless they have staged edits or were committed since the refresh
+// was read.
+//
+// Like the other methods of the cache, it must not be called concurrently
+// with them.
+func (r *Refresh) Apply() []string {
+	c := r.repoCache
+
+	var changed []string
+
+	for id, read := range r.bugs {
+		if !r.unchanged(id) {
+			continue
+		}
+
+		existing, ok := c.bugs[id].(*BugCache)
+		if !ok {
+			c.cacheBug(id, read)
+			continue
+		}
+
+		if existing.bug.HasPendingOp() {
+			continue
+		}
+
+		existing.bug = read.bug
+		existing.snap = read.snap
+		c.setHead(id, read.bug.LastCommitHash())
+		changed = append(changed, id)
+	}
+
+	for _, id := range r.removed {
+		if !r.unchanged(id) {
+			continue
+		}
+
+		delete(c.bugs, id)
+
+		c.headsMu.Lock()
+		delete(c.heads, id)
+		c.headsMu.Unlock()
+
+		changed = append(changed, id)
+	}
+
+	c.incomplete = r.incomplete
+
+	sort.Strings(changed)
+
+	return changed
+}
+
+// unchanged tell if the cached state of a bug is the same as when the
+// refresh was read
+func (r *Refresh) unchanged(id string) bool {
+	c := r.repoCache
+
+	c.headsMu.Lock()
+	defer c.headsMu.Unlock()
+
+	head, ok := c.heads[id]
+	previous, wasCached := r.previous[id]
+
+	return ok == wasCached && head == previous
+}
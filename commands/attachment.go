@@ -0,0 +1,17 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var attachmentCmd = &cobra.Command{
+	Use:   "attachment",
+	Short: "Extract the files attached to the comments of a bug",
+	Long: `Extract the files attached to the comments of a bug.
+
+The attachments are numbered from 1 across the comments of the bug, as listed by "git bug show".`,
+}
+
+func init() {
+	RootCmd.AddCommand(attachmentCmd)
+}
@@ -0,0 +1,121 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/util"
+	"github.com/spf13/cobra"
+)
+
+var (
+	attachmentGetOutput string
+	attachmentGetForce  bool
+)
+
+func runAttachmentGet(cmd *cobra.Command, args []string) error {
+	if len(args) != 2 {
+		return errors.New("You must provide a bug id and the number of an attachment")
+	}
+
+	backend := cache.NewRepoCache(repo)
+
+	b, err := resolveBug(backend, args[0])
+	if err != nil {
+		return err
+	}
+
+	attachments := b.Snapshot().Attachments()
+
+	number, err := strconv.Atoi(args[1])
+	if err != nil || number < 1 || number > len(attachments) {
+		return fmt.Errorf("invalid attachment \"%s\", the bug has %d attachments, see \"git bug show\"", args[1], len(attachments))
+	}
+
+	hash := attachments[number-1]
+
+	if attachmentGetOutput == "-" {
+		return writeAttachment(os.Stdout, hash)
+	}
+
+	path := attachmentGetOutput
+
+	// the file is named after the attachment by default
+	info, err := os.Stat(path)
+	if path == "" || (err == nil && info.IsDir()) {
+		attachment, err := bug.ReadAttachment(repo, hash)
+		if err != nil {
+			return err
+		}
+		path = filepath.Join(path, attachment.Filename())
+	}
+
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if !attachmentGetForce {
+		flags |= os.O_EXCL
+	}
+
+	file, err := os.OpenFile(path, flags, 0644)
+	if os.IsExist(err) {
+		return fmt.Errorf("%s already exists, use --force to overwrite it", path)
+	}
+	if err != nil {
+		return err
+	}
+
+	err = writeAttachment(file, hash)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		// no truncated file left behind
+		os.Remove(path)
+		return err
+	}
+
+	fmt.Println(path)
+
+	return nil
+}
+
+// writeAttachment stream the data of an attachment, without holding it in
+// memory as the attached logs can be large
+func writeAttachment(w io.Writer, hash util.Hash) error {
+	reader, err := repo.OpenData(hash)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	_, err = io.Copy(w, reader)
+
+	return err
+}
+
+var attachmentGetCmd = &cobra.Command{
+	Use:   "get [<option>...] <id> <number>",
+	Short: "Extract a file attached to a bug, by its number",
+	Long: `Extract a file attached to a bug, by its number as listed by "git bug show".
+
+The file is written in the current directory, named after its hash and its detected content type, unless another path is given with --output. An existing file is not overwritten without --force.`,
+	Example: `git bug attachment get 3c2e1 2 -o crash.log
+git bug attachment get 3c2e1 1 -o - | less`,
+	RunE: runAttachmentGet,
+}
+
+func init() {
+	attachmentCmd.AddCommand(attachmentGetCmd)
+
+	attachmentGetCmd.Flags().StringVarP(&attachmentGetOutput, "output", "o", "",
+		"Write the file to the given path or directory. Use - to write it to the standard output",
+	)
+	attachmentGetCmd.Flags().BoolVarP(&attachmentGetForce, "force", "f", false,
+		"Overwrite the file if it already exists",
+	)
+}
@@ -0,0 +1,32 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/MichaelMure/git-bug/bridge"
+	"github.com/spf13/cobra"
+)
+
+func runBridge(cmd *cobra.Command, args []string) error {
+	mapping, err := bridge.ReadIdentityMapping(repo)
+	if err != nil {
+		return err
+	}
+
+	for _, login := range mapping.Logins() {
+		person, _ := mapping.Lookup(login)
+		fmt.Printf("%s --> %s <%s>\n", login, person.Name, person.Email)
+	}
+
+	return nil
+}
+
+var bridgeCmd = &cobra.Command{
+	Use:   "bridge",
+	Short: "Display the identity mapping used by the bridges to other bug trackers",
+	RunE:  runBridge,
+}
+
+func init() {
+	RootCmd.AddCommand(bridgeCmd)
+}
@@ -0,0 +1,105 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/MichaelMure/git-bug/bridge"
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/spf13/cobra"
+)
+
+var (
+	bridgeMapUserRemove bool
+	bridgeMapUserRemap  bool
+)
+
+func runBridgeMapUser(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 && !bridgeMapUserRemap {
+		return errors.New("You must provide an upstream login")
+	}
+
+	mapping, err := bridge.ReadIdentityMapping(repo)
+	if err != nil {
+		return err
+	}
+
+	if len(args) > 0 {
+		err = mapUser(mapping, args)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !bridgeMapUserRemap {
+		return nil
+	}
+
+	author, err := bug.DefaultIdentity(repo)
+	if err != nil {
+		return err
+	}
+
+	remapped, err := mapping.Remap(repo, author)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%d bug(s) remapped\n", remapped)
+
+	return nil
+}
+
+// mapUser edit the mapping of a login and store it
+func mapUser(mapping *bridge.IdentityMapping, args []string) error {
+	login := args[0]
+
+	if bridgeMapUserRemove {
+		if len(args) > 1 {
+			return errors.New("Only the login is expected when removing a mapping")
+		}
+
+		if _, ok := mapping.Lookup(login); !ok {
+			return errors.New("This login is not mapped")
+		}
+
+		mapping.Unmap(login)
+
+		return mapping.Write(repo)
+	}
+
+	if len(args) != 3 {
+		return errors.New("You must provide an upstream login, a name and an email")
+	}
+
+	mapping.Map(login, bug.Person{
+		Name:  args[1],
+		Email: args[2],
+	})
+
+	return mapping.Write(repo)
+}
+
+var bridgeMapUserCmd = &cobra.Command{
+	Use:   "map-user [<option>...] [<login> [<name> <email>]]",
+	Short: "Map a user of another bug tracker to a git-bug person",
+	Long: `Map a user of another bug tracker to a git-bug person.
+
+The mapping is used by the importers for the authors of the new operations.
+The bugs imported before a login was mapped keep showing the login, unless
+--remap is given: the authors of the imported bugs are then remapped with an
+operation appended to the bugs, their history being left untouched. Without a
+login, only the remap is done.`,
+	RunE: runBridgeMapUser,
+}
+
+func init() {
+	bridgeCmd.AddCommand(bridgeMapUserCmd)
+
+	bridgeMapUserCmd.Flags().BoolVarP(&bridgeMapUserRemove, "remove", "r", false,
+		"Remove the mapping of a login",
+	)
+	bridgeMapUserCmd.Flags().BoolVar(&bridgeMapUserRemap, "remap", false,
+		"Remap the authors of the bugs already imported",
+	)
+}
@@ -0,0 +1,82 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/MichaelMure/git-bug/bridge/phabricator"
+	"github.com/spf13/cobra"
+)
+
+const phabricatorTokenEnvVar = "GIT_BUG_PHABRICATOR_TOKEN"
+
+var (
+	bridgePhabricatorToken string
+	bridgePhabricatorFull  bool
+)
+
+func runBridgePhabricator(cmd *cobra.Command, args []string) error {
+	if len(args) > 1 {
+		return errors.New("Only one Phabricator instance at a time is supported")
+	}
+
+	var instanceUrl string
+	if len(args) == 1 {
+		instanceUrl = args[0]
+	} else {
+		var err error
+		instanceUrl, err = phabricator.ConfiguredUrl(repo)
+		if err != nil {
+			return err
+		}
+	}
+	if instanceUrl == "" {
+		return fmt.Errorf("You must provide the url of the Phabricator instance, or configure it with %s", phabricator.UrlConfigKey)
+	}
+
+	token := bridgePhabricatorToken
+	if token == "" {
+		token = os.Getenv(phabricatorTokenEnvVar)
+	}
+	if token == "" {
+		return fmt.Errorf("You must provide a Conduit API token, with --token or %s", phabricatorTokenEnvVar)
+	}
+
+	client := phabricator.NewClient(instanceUrl, token)
+
+	result, err := phabricator.Import(repo, client, bridgePhabricatorFull)
+
+	if result != nil {
+		fmt.Printf("%d created, %d updated, %d unchanged\n",
+			result.Created, result.Updated, result.Unchanged)
+	}
+
+	return err
+}
+
+var bridgePhabricatorCmd = &cobra.Command{
+	Use:   "phabricator [<option>...] [<url>]",
+	Short: "Import the tasks of a Phabricator instance",
+	Long: `Import the tasks of Maniphest, the bug tracker of a Phabricator instance.
+
+Each task become a bug, with its comments. The projects become labels, and
+the authors are translated with the identity mapping of the bridges, from
+their username. Importing again add the new comments and update the title,
+status, priority and labels. Only the tasks modified since the previous
+import are asked for, unless --full is given.
+
+Without url, the instance configured with git-bug.phabricator.url is imported.`,
+	RunE: runBridgePhabricator,
+}
+
+func init() {
+	bridgeCmd.AddCommand(bridgePhabricatorCmd)
+
+	bridgePhabricatorCmd.Flags().StringVarP(&bridgePhabricatorToken, "token", "t", "",
+		"The Conduit API token, "+phabricatorTokenEnvVar+" if not given",
+	)
+	bridgePhabricatorCmd.Flags().BoolVar(&bridgePhabricatorFull, "full", false,
+		"Import every task, not only the ones modified since the previous import",
+	)
+}
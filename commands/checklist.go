@@ -0,0 +1,172 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/input"
+	"github.com/spf13/cobra"
+)
+
+var (
+	checklistSet []string
+)
+
+func runChecklist(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return errors.New("You must provide a bug id")
+	}
+
+	if len(args) > 2 {
+		return errors.New("Only one checklist can be given")
+	}
+
+	backend := cache.NewRepoCache(repo)
+
+	b, err := resolveBug(backend, args[0])
+	if err != nil {
+		return err
+	}
+
+	snap := b.Snapshot()
+
+	// no checklist display the current ones
+	if len(args) == 1 {
+		if len(checklistSet) > 0 {
+			return errors.New("You must provide the checklist to set")
+		}
+
+		for _, checklist := range snap.Checklists {
+			printChecklist(checklist)
+		}
+		return nil
+	}
+
+	name := args[1]
+
+	registry, err := bug.ReadChecklistRegistry(repo)
+	if err != nil {
+		return err
+	}
+
+	definition, ok := registry.Lookup(name)
+	if !ok {
+		return fmt.Errorf("unknown checklist %s, see \"git bug checklist ls\"", name)
+	}
+
+	var states map[string]bug.ChecklistState
+
+	if len(checklistSet) > 0 {
+		states, err = parseChecklistSet(checklistSet)
+	} else {
+		if !input.IsInteractive() {
+			return errors.New("You must provide the items to set with --set, without a terminal")
+		}
+		current, _ := snap.Checklist(name)
+		states, err = promptChecklist(current, definition)
+	}
+	if err != nil {
+		return err
+	}
+
+	err = b.SetChecklist(name, states)
+	if err != nil {
+		return err
+	}
+
+	return b.CommitAsNeeded()
+}
+
+// parseChecklistSet parse the item=state values given with --set
+func parseChecklistSet(values []string) (map[string]bug.ChecklistState, error) {
+	states := make(map[string]bug.ChecklistState, len(values))
+
+	for _, value := range values {
+		split := strings.SplitN(value, "=", 2)
+		if len(split) != 2 {
+			return nil, fmt.Errorf("invalid item \"%s\", expected item=state", value)
+		}
+
+		state, err := bug.ParseChecklistState(split[1])
+		if err != nil {
+			return nil, err
+		}
+
+		states[split[0]] = state
+	}
+
+	return states, nil
+}
+
+// promptChecklist ask the state of each item of a checklist on the terminal
+func promptChecklist(current bug.Checklist, definition bug.ChecklistDefinition) (map[string]bug.ChecklistState, error) {
+	answers := []string{"pending", "done", "na", "keep"}
+	states := make(map[string]bug.ChecklistState, len(definition.Items))
+
+	for _, item := range definition.Items {
+		state, ok := current.Item(item)
+		if !ok {
+			state = bug.ChecklistPending
+		}
+
+		question := fmt.Sprintf("%s (%s)", item, state)
+		choice, err := input.Choose(question, answers)
+		if err != nil {
+			return nil, err
+		}
+
+		switch choice {
+		case 0:
+			states[item] = bug.ChecklistPending
+		case 1:
+			states[item] = bug.ChecklistDone
+		case 2:
+			states[item] = bug.ChecklistNA
+		}
+	}
+
+	return states, nil
+}
+
+func printChecklist(checklist bug.Checklist) {
+	done, total := checklist.Completion()
+	fmt.Printf("%s %d/%d\n", checklist.Name, done, total)
+
+	for _, item := range checklist.Items {
+		fmt.Printf("  [%s] %s\n", checklistMark(item.State), item.Name)
+	}
+}
+
+func checklistMark(state bug.ChecklistState) string {
+	switch state {
+	case bug.ChecklistDone:
+		return "x"
+	case bug.ChecklistNA:
+		return "-"
+	default:
+		return " "
+	}
+}
+
+var checklistCmd = &cobra.Command{
+	Use:   "checklist [<option>...] <id> [<checklist>]",
+	Short: "Display or update the checklists of a bug",
+	Long: `Display or update the checklists of a bug.
+
+Without a checklist, the checklists of the bug are displayed. With one, the state of each item is asked on the terminal, unless the items are given with --set. An item is either pending, done or na when it doesn't apply to the bug.
+
+The checklists available are defined with "git bug checklist define". Only the items changed are recorded, so that two clones updating different items of the same checklist concurrently keep both updates.`,
+	Example: `git bug checklist 3c2e1 triage --set reproduced=done --set regression=na`,
+	RunE:    runChecklist,
+}
+
+func init() {
+	RootCmd.AddCommand(checklistCmd)
+
+	checklistCmd.Flags().StringArrayVarP(&checklistSet, "set", "s", nil,
+		"Set the state of an item, as item=state, without asking",
+	)
+}
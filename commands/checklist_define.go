@@ -0,0 +1,40 @@
+package commands
+
+import (
+	"errors"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/spf13/cobra"
+)
+
+func runChecklistDefine(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return errors.New("You must provide the name of the checklist")
+	}
+
+	registry, err := bug.ReadChecklistRegistry(repo)
+	if err != nil {
+		return err
+	}
+
+	err = registry.Define(args[0], args[1:])
+	if err != nil {
+		return err
+	}
+
+	return registry.Write(repo)
+}
+
+var checklistDefineCmd = &cobra.Command{
+	Use:   "define <checklist> [<item>...]",
+	Short: "Define the items of a checklist",
+	Long: `Define the items of a checklist, shared with the repository.
+
+The definitions are stored in a dedicated ref, pushed and pulled along with the bugs. When two clones define the same checklist concurrently, the last definition win. Without any item, the checklist is removed; the bugs keep the items already set.`,
+	Example: `git bug checklist define triage reproduced regression "has test"`,
+	RunE:    runChecklistDefine,
+}
+
+func init() {
+	checklistCmd.AddCommand(checklistDefineCmd)
+}
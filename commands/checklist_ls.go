@@ -0,0 +1,36 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/spf13/cobra"
+)
+
+func runChecklistLs(cmd *cobra.Command, args []string) error {
+	registry, err := bug.ReadChecklistRegistry(repo)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range registry.Names() {
+		definition, _ := registry.Lookup(name)
+		fmt.Printf("%s\t%s\n", name, strings.Join(definition.Items, ", "))
+	}
+
+	return nil
+}
+
+var checklistLsCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "List the checklists defined in the repository",
+	Long: `List the checklists defined in the repository.
+
+The name of the checklist and its items are separated by a tab.`,
+	RunE: runChecklistLs,
+}
+
+func init() {
+	checklistCmd.AddCommand(checklistLsCmd)
+}
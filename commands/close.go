@@ -3,11 +3,12 @@ package commands
 import (
 	"errors"
 
-	"github.com/MichaelMure/git-bug/bug"
-	"github.com/MichaelMure/git-bug/bug/operations"
+	"github.com/MichaelMure/git-bug/cache"
 	"github.com/spf13/cobra"
 )
 
+var closeMessage string
+
 func runCloseBug(cmd *cobra.Command, args []string) error {
 	if len(args) > 1 {
 		return errors.New("Only closing one bug at a time is supported")
@@ -19,27 +20,35 @@ func runCloseBug(cmd *cobra.Command, args []string) error {
 
 	prefix := args[0]
 
-	b, err := bug.FindLocalBug(repo, prefix)
-	if err != nil {
-		return err
-	}
+	backend := cache.NewRepoCache(repo)
 
-	author, err := bug.GetUser(repo)
+	b, err := resolveBug(backend, prefix)
 	if err != nil {
 		return err
 	}
 
-	operations.Close(b, author)
+	// the comment and the change are committed together
+	if closeMessage != "" {
+		err = b.AddComment(closeMessage)
+		if err != nil {
+			return err
+		}
+	}
 
-	return b.Commit(repo)
+	_, err = b.CloseAndCommit()
+	return err
 }
 
 var closeCmd = &cobra.Command{
-	Use:   "close <id>",
+	Use:   "close [<option>...] <id>",
 	Short: "Mark the bug as closed",
 	RunE:  runCloseBug,
 }
 
 func init() {
 	RootCmd.AddCommand(closeCmd)
+
+	closeCmd.Flags().StringVarP(&closeMessage, "message", "m", "",
+		"Add a comment along with the closing, in the same commit",
+	)
 }
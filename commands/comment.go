@@ -3,9 +3,9 @@ package commands
 import (
 	"errors"
 	"fmt"
+	"os"
 
-	"github.com/MichaelMure/git-bug/bug"
-	"github.com/MichaelMure/git-bug/bug/operations"
+	"github.com/MichaelMure/git-bug/cache"
 	"github.com/MichaelMure/git-bug/input"
 	"github.com/spf13/cobra"
 )
@@ -28,6 +28,13 @@ func runComment(cmd *cobra.Command, args []string) error {
 
 	prefix := args[0]
 
+	backend := cache.NewRepoCache(repo)
+
+	b, err := resolveBug(backend, prefix)
+	if err != nil {
+		return err
+	}
+
 	if commentMessageFile != "" && commentMessage == "" {
 		commentMessage, err = input.FromFile(commentMessageFile)
 		if err != nil {
@@ -35,8 +42,19 @@ func runComment(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// written in the editor, the message is kept as a draft until committed
+	var draft *input.CommentDraft
+
 	if commentMessage == "" {
-		commentMessage, err = input.BugCommentEditorInput(repo)
+		id := b.Snapshot().Id()
+
+		resume, err := input.ResumeCommentDraft(repo, id)
+		if err != nil {
+			return err
+		}
+
+		var edited input.CommentDraft
+		commentMessage, edited, err = input.BugCommentDraftInput(repo, id, resume)
 		if err == input.ErrEmptyMessage {
 			fmt.Println("Empty message, aborting.")
 			return nil
@@ -44,27 +62,32 @@ func runComment(cmd *cobra.Command, args []string) error {
 		if err != nil {
 			return err
 		}
-	}
 
-	author, err := bug.GetUser(repo)
-	if err != nil {
-		return err
+		draft = &edited
 	}
 
-	b, err := bug.FindLocalBug(repo, prefix)
+	_, err = b.CommentAndCommit(commentMessage)
 	if err != nil {
+		if draft != nil {
+			fmt.Fprintf(os.Stderr, "The comment is kept as a draft, see \"git bug drafts\".\n")
+		}
 		return err
 	}
 
-	operations.Comment(b, author, commentMessage)
+	if draft != nil {
+		return draft.Remove()
+	}
 
-	return b.Commit(repo)
+	return nil
 }
 
 var commentCmd = &cobra.Command{
 	Use:   "comment <id> [<options>...]",
 	Short: "Add a new comment to a bug",
-	RunE:  runComment,
+	Long: `Add a new comment to a bug.
+
+Without a message, it's written in the editor. The message is kept as a draft until the comment is committed, so that it's not lost if interrupted. The next comment on the bug offers to resume it, and the drafts can be listed or deleted with "git bug drafts".`,
+	RunE: runComment,
 }
 
 func init() {
@@ -0,0 +1,59 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/spf13/cobra"
+)
+
+func runCommentArchive(cmd *cobra.Command, args []string) error {
+	if len(args) < 2 {
+		return errors.New("You must provide a bug id and a date")
+	}
+
+	if len(args) > 2 {
+		return errors.New("Only archiving the comments of one bug at a time is supported")
+	}
+
+	before, err := time.ParseInLocation(statsDateLayout, args[1], time.Local)
+	if err != nil {
+		return fmt.Errorf("invalid date, expected YYYY-MM-DD: %s", args[1])
+	}
+
+	backend := cache.NewRepoCache(repo)
+
+	b, err := resolveBug(backend, args[0])
+	if err != nil {
+		return err
+	}
+
+	count, err := b.ArchiveComments(before)
+	if err != nil {
+		return err
+	}
+
+	err = b.Commit()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%d comments archived\n", count)
+
+	return nil
+}
+
+var commentArchiveCmd = &cobra.Command{
+	Use:   "archive <id> <date>",
+	Short: "Archive the comments of a bug created before a date",
+	Long: `Archive the comments of a bug created before a date, given as YYYY-MM-DD.
+
+For the bugs with thousands of comments, like the ones fed by bots. The archived comments are stored together in a git blob and left out of the bug as displayed, except its description. They can still be displayed with "show --archived".`,
+	RunE: runCommentArchive,
+}
+
+func init() {
+	commentCmd.AddCommand(commentArchiveCmd)
+}
@@ -0,0 +1,51 @@
+package commands
+
+import (
+	"errors"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/spf13/cobra"
+)
+
+var commentRmForce bool
+
+func runCommentRm(cmd *cobra.Command, args []string) error {
+	if len(args) < 2 {
+		return errors.New("You must provide a bug id and a comment hash")
+	}
+
+	if len(args) > 2 {
+		return errors.New("Only deleting one comment at a time is supported")
+	}
+
+	backend := cache.NewRepoCache(repo)
+
+	b, err := resolveBug(backend, args[0])
+	if err != nil {
+		return err
+	}
+
+	err = b.DeleteComment(args[1], commentRmForce)
+	if err != nil {
+		return err
+	}
+
+	return b.Commit()
+}
+
+var commentRmCmd = &cobra.Command{
+	Use:   "rm <id> <comment>",
+	Short: "Delete a comment of a bug",
+	Long: `Delete a comment of a bug, identified by the hash prefix displayed by "show".
+
+The comment is replaced by a tombstone for everyone. The original content is still stored and can be displayed with "show --include-deleted". To remove it from the history, like a leaked secret, use "redact".`,
+	RunE: runCommentRm,
+}
+
+func init() {
+	commentCmd.AddCommand(commentRmCmd)
+
+	commentRmCmd.Flags().BoolVarP(&commentRmForce, "force", "f", false,
+		"Delete the comment even if you are not its author. The override is recorded.",
+	)
+}
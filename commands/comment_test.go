@@ -0,0 +1,122 @@
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/bug/operations"
+	"github.com/MichaelMure/git-bug/input"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+// setupCommentRepo create a repository with a bug, with the editor writing
+// the given message
+func setupCommentRepo(t *testing.T, message string) (*repository.GitRepo, string) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gitRepo, err := repository.InitGitRepo(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	author := bug.Person{Name: "René Descartes", Email: "rene@descartes.fr"}
+	if err := gitRepo.SetConfig("user.name", author.Name); err != nil {
+		t.Fatal(err)
+	}
+	if err := gitRepo.SetConfig("user.email", author.Email); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := operations.Create(author, "title", "message")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Commit(gitRepo); err != nil {
+		t.Fatal(err)
+	}
+
+	editor := filepath.Join(dir, "editor.sh")
+	script := "#!/bin/sh\nprintf '" + message + "' > \"$1\"\n"
+	if err := ioutil.WriteFile(editor, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Setenv("GIT_EDITOR", editor); err != nil {
+		t.Fatal(err)
+	}
+
+	repo = gitRepo
+	commentMessage = ""
+	commentMessageFile = ""
+
+	return gitRepo, b.Id()
+}
+
+func cleanupCommentRepo(gitRepo *repository.GitRepo) {
+	os.Unsetenv("GIT_EDITOR")
+	os.RemoveAll(gitRepo.GetPath())
+	repo = nil
+}
+
+func TestCommentRemoveDraft(t *testing.T) {
+	gitRepo, id := setupCommentRepo(t, "a new comment")
+	defer cleanupCommentRepo(gitRepo)
+
+	if err := runComment(commentCmd, []string{id}); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := bug.ReadLocalBug(gitRepo, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	comments := b.Compile().Comments
+	if len(comments) != 2 || comments[1].GetMessage() != "a new comment" {
+		t.Fatal("The comment should be committed")
+	}
+
+	drafts, err := input.ListCommentDrafts(gitRepo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(drafts) != 0 {
+		t.Fatalf("The draft should be removed once committed, got %+v", drafts)
+	}
+}
+
+func TestCommentKeepDraftOnFailure(t *testing.T) {
+	gitRepo, id := setupCommentRepo(t, "a comment too long")
+	defer cleanupCommentRepo(gitRepo)
+
+	// make the commit fail
+	limits := bug.CurrentLimits()
+	defer bug.SetLimits(limits)
+	failing := limits
+	failing.MaxCommentLength = 5
+	bug.SetLimits(failing)
+
+	if err := runComment(commentCmd, []string{id}); err == nil {
+		t.Fatal("The commit should fail")
+	}
+
+	drafts, err := input.ListCommentDrafts(gitRepo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(drafts) != 1 {
+		t.Fatalf("The draft should be kept, got %+v", drafts)
+	}
+
+	message, err := drafts[0].Message()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if message != "a comment too long" || drafts[0].BugId != id {
+		t.Fatalf("Unexpected draft %+v with message %s", drafts[0], message)
+	}
+}
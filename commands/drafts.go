@@ -0,0 +1,43 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/MichaelMure/git-bug/input"
+	"github.com/spf13/cobra"
+)
+
+func runDrafts(cmd *cobra.Command, args []string) error {
+	drafts, err := input.ListCommentDrafts(repo)
+	if err != nil {
+		return err
+	}
+
+	for _, draft := range drafts {
+		message, err := draft.Message()
+		if err != nil {
+			return err
+		}
+
+		firstLine := strings.SplitN(message, "\n", 2)[0]
+		fmt.Printf("%s\t%s\t%s\n", draft.Name, draft.ModTime.Format("Jan 2 15:04"), firstLine)
+	}
+
+	return nil
+}
+
+var draftsCmd = &cobra.Command{
+	Use:   "drafts",
+	Short: "List the comment drafts not committed yet",
+	Long: `List the comment drafts not committed yet.
+
+Only the comments have drafts: the title and message of a new bug written in the editor are not kept. A comment written in the editor is kept as a draft until it's committed, so that it's not lost if interrupted. The next comment on the same bug offers to resume it. The drafts are local files, never committed nor pushed.
+
+The name of the draft, which is the id of the bug, the time it was last saved and its first line are separated by a tab. A bug with several drafts, like from two terminals, has the name of the others suffixed with a number.`,
+	RunE: runDrafts,
+}
+
+func init() {
+	RootCmd.AddCommand(draftsCmd)
+}
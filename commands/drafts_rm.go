@@ -0,0 +1,94 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/MichaelMure/git-bug/input"
+	"github.com/spf13/cobra"
+)
+
+var draftsRmAll bool
+
+func runDraftsRm(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 && !draftsRmAll {
+		return errors.New("You must provide a draft, or --all")
+	}
+
+	drafts, err := input.ListCommentDrafts(repo)
+	if err != nil {
+		return err
+	}
+
+	if draftsRmAll {
+		for _, draft := range drafts {
+			if err := draft.Remove(); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// resolved first, so that nothing is deleted if one doesn't match
+	var matching []input.CommentDraft
+	for _, prefix := range args {
+		draft, err := resolveDraft(drafts, prefix)
+		if err != nil {
+			return err
+		}
+		matching = append(matching, draft)
+	}
+
+	for _, draft := range matching {
+		if err := draft.Remove(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resolveDraft find the draft with the given name, or the only one whose
+// name start with the given prefix
+func resolveDraft(drafts []input.CommentDraft, prefix string) (input.CommentDraft, error) {
+	var matching []input.CommentDraft
+
+	for _, draft := range drafts {
+		if draft.Name == prefix {
+			return draft, nil
+		}
+		if strings.HasPrefix(draft.Name, prefix) {
+			matching = append(matching, draft)
+		}
+	}
+
+	switch len(matching) {
+	case 0:
+		return input.CommentDraft{}, fmt.Errorf("no draft matching %s", prefix)
+	case 1:
+		return matching[0], nil
+	}
+
+	names := make([]string, len(matching))
+	for i, draft := range matching {
+		names[i] = draft.Name
+	}
+
+	return input.CommentDraft{}, fmt.Errorf("Multiple matching drafts found:\n%s", strings.Join(names, "\n"))
+}
+
+var draftsRmCmd = &cobra.Command{
+	Use:   "rm [<option>...] [<draft>...]",
+	Short: "Delete comment drafts",
+	Long:  `Delete comment drafts, given by their name as listed by "drafts", or a prefix of it.`,
+	RunE:  runDraftsRm,
+}
+
+func init() {
+	draftsCmd.AddCommand(draftsRmCmd)
+
+	draftsRmCmd.Flags().BoolVarP(&draftsRmAll, "all", "a", false,
+		"Delete all the drafts",
+	)
+}
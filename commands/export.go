@@ -0,0 +1,36 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/MichaelMure/git-bug/bridge/jsonl"
+	"github.com/spf13/cobra"
+)
+
+var exportFormat string
+
+func runExport(cmd *cobra.Command, args []string) error {
+	if exportFormat != "jsonl" {
+		return fmt.Errorf("unknown format %s, expected jsonl", exportFormat)
+	}
+
+	return jsonl.Export(repo, os.Stdout)
+}
+
+var exportCmd = &cobra.Command{
+	Use:   "export [<option>...]",
+	Short: "Export the bugs as a JSON Lines stream on stdout",
+	Long: `Export the bugs as a JSON Lines stream on stdout, one bug per line, in the format read by the import command.
+
+The bugs are written one at a time, so that a large repository can be piped to tools like jq. Importing the stream back in the same repository change nothing. The deleted and archived comments are left out.`,
+	RunE: runExport,
+}
+
+func init() {
+	RootCmd.AddCommand(exportCmd)
+
+	exportCmd.Flags().StringVarP(&exportFormat, "format", "f", "jsonl",
+		"Format of the stream, only jsonl is supported",
+	)
+}
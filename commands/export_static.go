@@ -0,0 +1,47 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/MichaelMure/git-bug/static"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportStaticDir  string
+	exportStaticFull bool
+)
+
+func runExportStatic(cmd *cobra.Command, args []string) error {
+	result, err := static.Export(repo, exportStaticDir, exportStaticFull)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%d exported, %d unchanged, %d removed\n",
+		result.Exported, result.Unchanged, result.Removed)
+
+	return nil
+}
+
+var exportStaticCmd = &cobra.Command{
+	Use:   "export-static [<option>...]",
+	Short: "Export the bugs as a static web site",
+	Long: `Export the bugs as a static web site, to publish them read-only with any web server, like GitHub Pages.
+
+The directory receive an index.json of the bug summaries and a bug/<id>.json per bug, along with the HTML pages showing them. The attached files are copied under files/, named after their hash.
+
+Only the bugs that changed since the previous export are written again, as recorded in the ` + static.StateFileName + ` file of the directory. The whole site is written again when the labels are edited, or with --full.`,
+	RunE: runExportStatic,
+}
+
+func init() {
+	RootCmd.AddCommand(exportStaticCmd)
+
+	exportStaticCmd.Flags().StringVar(&exportStaticDir, "dir", "public",
+		"The directory to write the site in",
+	)
+	exportStaticCmd.Flags().BoolVar(&exportStaticFull, "full", false,
+		"Write every bug, even the unchanged ones",
+	)
+}
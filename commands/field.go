@@ -0,0 +1,55 @@
+package commands
+
+import (
+	"errors"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/spf13/cobra"
+)
+
+func runField(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return errors.New("You must provide a bug id")
+	}
+
+	if len(args) == 1 {
+		return errors.New("You must provide a field name")
+	}
+
+	if len(args) > 3 {
+		return errors.New("Only one field can be set at a time")
+	}
+
+	prefix := args[0]
+	key := args[1]
+
+	// no value remove the field
+	var value string
+	if len(args) == 3 {
+		value = args[2]
+	}
+
+	backend := cache.NewRepoCache(repo)
+
+	b, err := resolveBug(backend, prefix)
+	if err != nil {
+		return err
+	}
+
+	err = b.SetCustomField(key, value)
+	if err != nil {
+		return err
+	}
+
+	return b.Commit()
+}
+
+var fieldCmd = &cobra.Command{
+	Use:   "field <id> <name> [<value>]",
+	Short: "Set a custom field of a bug, or remove it without a value",
+	RunE:  runField,
+}
+
+func init() {
+	RootCmd.AddCommand(fieldCmd)
+}
@@ -0,0 +1,24 @@
+package commands
+
+import (
+	"strings"
+
+	"github.com/MichaelMure/git-bug/bug"
+)
+
+// the documentation of --template, shared by the commands displaying bugs
+const templateHelp = `A template is a Go text/template executed with the bug, like
+"{{.HumanId}} [{{.Status}}] {{.Title}} ({{len .Comments}})". Besides the
+fields and methods of the bug, it can call:
+  ago <time>              the time relatively to now, like "3 days ago"
+  truncate <n> <text>     the text shortened to n characters
+  join <sep> <labels>     the labels separated by sep
+
+The built-in formats oneline, full and porcelain can be given by name, and
+other formats can be defined in the git config, like
+git-bug.format.short "{{.HumanId}} {{.Title}}".`
+
+// formatNames list the names of the built-in output formats
+func formatNames() string {
+	return strings.Join(bug.BuiltinFormatNames(), ", ")
+}
@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/util"
+	"github.com/spf13/cobra"
+)
+
+func runFsck(cmd *cobra.Command, args []string) error {
+	report, err := bug.VerifyAll(repo)
+	if err != nil {
+		return err
+	}
+
+	count := 0
+	var incomplete []*bug.ErrIncompleteHistory
+
+	for _, v := range report.Bugs {
+		if v.Incomplete != nil {
+			incomplete = append(incomplete, v.Incomplete)
+			continue
+		}
+
+		for _, problem := range v.Problems {
+			fmt.Printf("%s: %s\n", v.Id, problem)
+		}
+		count += len(v.Problems)
+	}
+
+	// not a corruption, the history is only partially fetched
+	for _, e := range incomplete {
+		fmt.Printf("%s: %s\n", e.Id, util.Yellow(e.Error()))
+	}
+	if len(incomplete) > 0 {
+		fmt.Printf("%d bugs with an incomplete history can't be checked: %s\n", len(incomplete), incomplete[0].Hint())
+	}
+
+	for _, lock := range report.StaleLocks {
+		fmt.Printf("%s: stale lock of an interrupted git command, remove it if no other git command is running\n", lock)
+	}
+	count += len(report.StaleLocks)
+
+	fmt.Println(report.Summary())
+
+	if count > 0 {
+		return fmt.Errorf("%d problems found", count)
+	}
+
+	return nil
+}
+
+var fsckCmd = &cobra.Command{
+	Use:   "fsck",
+	Short: "Check the integrity of the bugs",
+	Long: `Check the integrity of the bugs.
+
+Check that each local bug can be read and is valid, and that the messages stored in their
+own blob are referenced by the commits of the bug and present in the
+repository, without dangling message blobs. The lock files of the bug refs
+left by a killed git command are reported as well, as they prevent the update
+of these bugs.
+
+In a shallow or partial clone, the bugs whose history is incomplete are
+reported apart, as they are not corrupted. A problem with a bug doesn't stop
+the verification of the others.`,
+	RunE: runFsck,
+}
+
+func init() {
+	RootCmd.AddCommand(fsckCmd)
+}
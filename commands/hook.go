@@ -0,0 +1,14 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var hookCmd = &cobra.Command{
+	Use:   "hook",
+	Short: "Run as a git hook, to check the bugs pushed to a server",
+}
+
+func init() {
+	RootCmd.AddCommand(hookCmd)
+}
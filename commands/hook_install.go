@@ -0,0 +1,82 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/spf13/cobra"
+)
+
+var (
+	hookInstallForce bool
+)
+
+// the script of the pre-receive hook, running "git bug hook pre-receive"
+const preReceiveHookScript = "#!/bin/sh\nexec git bug hook pre-receive\n"
+
+func runHookInstall(cmd *cobra.Command, args []string) error {
+	if len(args) > 0 {
+		return errors.New("Only the pre-receive hook can be installed")
+	}
+
+	dir, err := repository.HooksDir(repo)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	path := filepath.Join(dir, "pre-receive")
+
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if !hookInstallForce {
+		flags |= os.O_EXCL
+	}
+
+	file, err := os.OpenFile(path, flags, 0755)
+	if os.IsExist(err) {
+		return fmt.Errorf("%s already exists, use --force to replace it", path)
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = file.WriteString(preReceiveHookScript)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	// an existing file keep its mode
+	if err := os.Chmod(path, 0755); err != nil {
+		return err
+	}
+
+	fmt.Printf("pre-receive hook installed in %s\n", path)
+
+	return nil
+}
+
+var hookInstallCmd = &cobra.Command{
+	Use:   "install [<option>...]",
+	Short: "Install the pre-receive hook in the repository",
+	Long: `Install the pre-receive hook in the repository, to reject the pushes of invalid bugs. See "git bug hook pre-receive".
+
+The hook is written in the directory configured with core.hooksPath, or else in the hooks directory of the repository, shared by its worktrees. An existing hook is not replaced without --force.`,
+	RunE: runHookInstall,
+}
+
+func init() {
+	hookCmd.AddCommand(hookInstallCmd)
+
+	hookInstallCmd.Flags().BoolVarP(&hookInstallForce, "force", "f", false,
+		"Replace the hook if it already exists",
+	)
+}
@@ -0,0 +1,63 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/spf13/cobra"
+)
+
+func runHookPreReceive(cmd *cobra.Command, args []string) error {
+	updates, err := bug.ParseRefUpdates(os.Stdin)
+	if err != nil {
+		return err
+	}
+
+	verifications, err := bug.VerifyRefUpdates(repo, updates)
+	if err != nil {
+		return err
+	}
+
+	rejected := 0
+
+	for _, v := range verifications {
+		if len(v.Problems) == 0 {
+			continue
+		}
+
+		rejected++
+		for _, problem := range v.Problems {
+			fmt.Printf("%s: %s\n", v.Update.Ref, problem)
+		}
+	}
+
+	if rejected > 0 {
+		return fmt.Errorf("%d bugs rejected", rejected)
+	}
+
+	return nil
+}
+
+var hookPreReceiveCmd = &cobra.Command{
+	Use:   "pre-receive",
+	Short: "Reject the pushes of invalid bugs, as a pre-receive hook",
+	Long: `Reject the pushes of invalid bugs, as a pre-receive hook.
+
+Read the "<old> <new> <ref>" lines given by git on the standard input, and
+check each pushed bug before git accept it. The bugs updated by a
+fast-forward only have their new commits checked, the new or rewritten ones
+are checked entirely like by fsck. The deletions and the other refs are
+accepted.
+
+Each problem is reported with the ref of the bug, and the exit code is 1 if
+any bug is rejected, so that git refuse the whole push. It's installed in
+the repository receiving the pushes with "git bug hook install".`,
+	RunE: runHookPreReceive,
+	// the output is relayed by git to the pusher, the problems are enough
+	SilenceUsage: true,
+}
+
+func init() {
+	hookCmd.AddCommand(hookPreReceiveCmd)
+}
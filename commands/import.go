@@ -0,0 +1,69 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/MichaelMure/git-bug/bridge/jsonl"
+	"github.com/spf13/cobra"
+)
+
+var (
+	importFormat string
+	importStrict bool
+)
+
+func runImport(cmd *cobra.Command, args []string) error {
+	if importFormat != "jsonl" {
+		return fmt.Errorf("unknown format %s, expected jsonl", importFormat)
+	}
+
+	result, err := jsonl.Import(repo, os.Stdin, importStrict)
+
+	if result != nil {
+		for _, skipped := range result.Skipped {
+			fmt.Fprintf(os.Stderr, "%s, skipped\n", skipped)
+		}
+
+		fmt.Printf("%d created, %d updated, %d unchanged, %d skipped\n",
+			result.Created, result.Updated, result.Unchanged, len(result.Skipped))
+	}
+
+	return err
+}
+
+var importCmd = &cobra.Command{
+	Use:   "import [<option>...]",
+	Short: "Import bugs from a JSON Lines stream on stdin",
+	Long: `Import bugs from a JSON Lines stream on stdin.
+
+Each line is a JSON object describing a bug:
+  {
+    "external_id": "JIRA-42",
+    "title": "Crash on startup",
+    "body": "It crash.",
+    "author": {"name": "John Doe", "email": "john@example.com", "login": "jdoe"},
+    "created_at": "2018-09-01T12:00:00Z",
+    "comments": [{"external_id": "1", "author": {...}, "body": "...", "created_at": "..."}],
+    "labels": ["bug"],
+    "closed": false
+  }
+
+Only external_id and title are required. Importing the same bug again add
+its new comments and update its title, labels and status. The malformed
+lines are reported with their number and skipped, unless --strict is given.
+An author whose login is mapped with "git bug bridge map-user" is replaced by
+the mapped person.`,
+	RunE: runImport,
+}
+
+func init() {
+	RootCmd.AddCommand(importCmd)
+
+	importCmd.Flags().StringVarP(&importFormat, "format", "f", "jsonl",
+		"Format of the stream, only jsonl is supported",
+	)
+	importCmd.Flags().BoolVar(&importStrict, "strict", false,
+		"Stop at the first malformed line instead of skipping it",
+	)
+}
@@ -2,14 +2,20 @@ package commands
 
 import (
 	"errors"
+	"fmt"
 	"os"
 
 	"github.com/MichaelMure/git-bug/bug"
-	"github.com/MichaelMure/git-bug/bug/operations"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/input"
 	"github.com/spf13/cobra"
 )
 
-var labelRemove bool
+var (
+	labelRemove   bool
+	labelMessage  string
+	labelForceNew bool
+)
 
 func runLabel(cmd *cobra.Command, args []string) error {
 	if len(args) == 0 {
@@ -30,23 +36,82 @@ func runLabel(cmd *cobra.Command, args []string) error {
 		add = args[1:]
 	}
 
-	b, err := bug.FindLocalBug(repo, prefix)
+	backend := cache.NewRepoCache(repo)
+
+	b, err := resolveBug(backend, prefix)
 	if err != nil {
 		return err
 	}
 
-	author, err := bug.GetUser(repo)
+	if !labelForceNew {
+		add, err = checkNewLabels(backend, add)
+		if err != nil {
+			return err
+		}
+	}
+
+	// the comment and the change are committed together
+	if labelMessage != "" {
+		err = b.AddComment(labelMessage)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = b.ChangeLabels(os.Stdout, add, remove)
 	if err != nil {
 		return err
 	}
 
-	err = operations.ChangeLabels(os.Stdout, b, author, add, remove)
+	return b.Commit()
+}
+
+// checkNewLabels catch the typos creating near-duplicate labels: a label not
+// used yet but similar to an existing one is confirmed first. Without a
+// terminal to ask, the labels are accepted as they are.
+func checkNewLabels(backend cache.RepoCacher, labels []string) ([]string, error) {
+	if len(labels) == 0 || !input.IsInteractive() {
+		return labels, nil
+	}
 
+	counts, err := backend.Labels()
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	known := make([]bug.Label, len(counts))
+	used := make(map[string]bool, len(counts))
+	for i, count := range counts {
+		known[i] = count.Label
+		used[string(count.Label)] = true
 	}
 
-	return b.Commit(repo)
+	result := make([]string, 0, len(labels))
+
+	for _, label := range labels {
+		similar, ok := bug.SimilarLabel(known, bug.Label(label))
+		if used[label] || !ok {
+			result = append(result, label)
+			continue
+		}
+
+		question := fmt.Sprintf("label '%s' is new — did you mean '%s'?", label, similar)
+		choice, err := input.Choose(question, []string{"use existing", "create new", "abort"})
+		if err != nil {
+			return nil, err
+		}
+
+		switch choice {
+		case 0:
+			result = append(result, string(similar))
+		case 1:
+			result = append(result, label)
+		default:
+			return nil, errors.New("aborted")
+		}
+	}
+
+	return result, nil
 }
 
 var labelCmd = &cobra.Command{
@@ -61,4 +126,10 @@ func init() {
 	labelCmd.Flags().BoolVarP(&labelRemove, "remove", "r", false,
 		"Remove a label",
 	)
+	labelCmd.Flags().StringVarP(&labelMessage, "message", "m", "",
+		"Add a comment along with the label change, in the same commit",
+	)
+	labelCmd.Flags().BoolVar(&labelForceNew, "force-new", false,
+		"Add the labels as given, without asking about the new ones similar to an existing label",
+	)
 }
@@ -0,0 +1,71 @@
+package commands
+
+import (
+	"errors"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/spf13/cobra"
+)
+
+var (
+	labelEditColor       string
+	labelEditDescription string
+)
+
+func runLabelEdit(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return errors.New("You must provide a single label")
+	}
+
+	colorSet := cmd.Flags().Changed("color")
+	descriptionSet := cmd.Flags().Changed("description")
+
+	if !colorSet && !descriptionSet {
+		return errors.New("You must provide a color or a description")
+	}
+
+	label := bug.Label(args[0])
+
+	registry, err := bug.ReadLabelRegistry(repo)
+	if err != nil {
+		return err
+	}
+
+	// the value not given is kept
+	info, _ := registry.Lookup(label)
+	if colorSet {
+		info.Color = labelEditColor
+	}
+	if descriptionSet {
+		info.Description = labelEditDescription
+	}
+
+	err = registry.Edit(label, info.Color, info.Description)
+	if err != nil {
+		return err
+	}
+
+	return registry.Write(repo)
+}
+
+var labelEditCmd = &cobra.Command{
+	Use:   "edit <label>",
+	Short: "Set the color and the description of a label",
+	Long: `Set the color and the description of a label, shared with the repository.
+
+They are stored in a dedicated ref, pushed and pulled along with the bugs. When two clones edit the same label concurrently, the last edit win. An empty value unset the color or the description.
+
+The web UI and the termui use the color when set, instead of the one derived from the name of the label.`,
+	RunE: runLabelEdit,
+}
+
+func init() {
+	labelCmd.AddCommand(labelEditCmd)
+
+	labelEditCmd.Flags().StringVar(&labelEditColor, "color", "",
+		"The color of the label, as #rrggbb",
+	)
+	labelEditCmd.Flags().StringVar(&labelEditDescription, "description", "",
+		"A description of the label",
+	)
+}
@@ -0,0 +1,59 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/spf13/cobra"
+)
+
+func runLabelLs(cmd *cobra.Command, args []string) error {
+	backend := cache.NewRepoCache(repo)
+
+	labels, err := backend.Labels()
+	if err != nil {
+		return err
+	}
+
+	registry, err := bug.ReadLabelRegistry(repo)
+	if err != nil {
+		return err
+	}
+
+	used := make(map[bug.Label]bool, len(labels))
+	for _, label := range labels {
+		used[label.Label] = true
+	}
+
+	// the labels described but not used yet come last
+	for _, label := range registry.Labels() {
+		if !used[label] {
+			labels = append(labels, cache.LabelCount{Label: label})
+		}
+	}
+
+	for _, label := range labels {
+		info, ok := registry.Lookup(label.Label)
+		if ok && info.Description != "" {
+			fmt.Printf("%s\t%d\t%s\n", label.Label, label.Count, info.Description)
+		} else {
+			fmt.Printf("%s\t%d\n", label.Label, label.Count)
+		}
+	}
+
+	return nil
+}
+
+var labelLsCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "List the labels used in the repository",
+	Long: `List the labels set on the bugs, with the number of bugs having each, most used first. The labels given a description with "label edit" but not used yet are listed last.
+
+The label, its count and its description, if any, are separated by a tab.`,
+	RunE: runLabelLs,
+}
+
+func init() {
+	labelCmd.AddCommand(labelLsCmd)
+}
@@ -0,0 +1,152 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/util"
+	"github.com/spf13/cobra"
+)
+
+var (
+	logPatch  bool
+	logFormat string
+)
+
+// the length of the hashes in the text output, as git log --oneline
+const logHashLength = 7
+
+type logJSONEntry struct {
+	Hash      string        `json:"hash"`
+	Lamport   uint64        `json:"lamport"`
+	Time      time.Time     `json:"time"`
+	Author    logJSONAuthor `json:"author"`
+	Type      string        `json:"type"`
+	Summary   string        `json:"summary"`
+	Commit    string        `json:"commit"`
+	Operation bug.Operation `json:"operation,omitempty"`
+}
+
+type logJSONAuthor struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+func runLog(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return errors.New("You must provide a single bug id")
+	}
+
+	if logFormat != "text" && logFormat != "json" {
+		return fmt.Errorf("unknown format %s, expected text or json", logFormat)
+	}
+
+	backend := cache.NewRepoCache(repo)
+
+	b, err := resolveBug(backend, args[0])
+	if err != nil {
+		return err
+	}
+
+	read, err := bug.ReadLocalBug(repo, b.Snapshot().Id())
+	if err != nil {
+		return err
+	}
+
+	entries, err := read.Log()
+	if err != nil {
+		return err
+	}
+
+	if logFormat == "json" {
+		return printLogJSON(entries)
+	}
+
+	for _, entry := range entries {
+		op := entry.Operation
+
+		fmt.Printf("%s %s %s %s %s %s %s\n",
+			util.Cyan(shortHash(entry.Hash)),
+			util.Yellow(fmt.Sprintf("%-16s", op.OpType())),
+			fmt.Sprintf("@%-4d", entry.EditTime),
+			op.Time().Format("2006-01-02 15:04:05"),
+			util.Magenta(fmt.Sprintf("%-20.20s", op.GetAuthor().Name)),
+			fmt.Sprintf("(%s)", shortHash(entry.Commit)),
+			op.Summary(),
+		)
+
+		if logPatch {
+			payload, err := json.MarshalIndent(op, "    ", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Printf("    %s\n\n", payload)
+		}
+	}
+
+	return nil
+}
+
+func printLogJSON(entries []bug.LogEntry) error {
+	result := make([]logJSONEntry, len(entries))
+
+	for i, entry := range entries {
+		op := entry.Operation
+
+		result[i] = logJSONEntry{
+			Hash:    entry.Hash.String(),
+			Lamport: uint64(entry.EditTime),
+			Time:    op.Time(),
+			Author:  logJSONAuthor{Name: op.GetAuthor().Name, Email: op.GetAuthor().Email},
+			Type:    op.OpType().String(),
+			Summary: op.Summary(),
+			Commit:  entry.Commit.String(),
+		}
+
+		if logPatch {
+			result[i].Operation = op
+		}
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(result)
+}
+
+// shortHash abbreviate a hash for the text output, a staged operation having
+// no commit yet
+func shortHash(hash util.Hash) string {
+	if hash == "" {
+		return strings.Repeat("-", logHashLength)
+	}
+	if len(hash) > logHashLength {
+		return string(hash[:logHashLength])
+	}
+	return string(hash)
+}
+
+var logCmd = &cobra.Command{
+	Use:   "log [<option>...] <id>",
+	Short: "Display the operations of a bug",
+	Long: `Display the operations of a bug in the order they are applied, one per line: the hash of the operation, its type, the Lamport time and the commit of the operation pack holding it, its time, its author and a summary of its data.
+
+The commit of each operation helps to understand a history merged from several clones.`,
+	RunE: runLog,
+}
+
+func init() {
+	RootCmd.AddCommand(logCmd)
+
+	logCmd.Flags().BoolVarP(&logPatch, "patch", "p", false,
+		"Display the full data of each operation",
+	)
+	logCmd.Flags().StringVarP(&logFormat, "format", "f", "text",
+		"Output format, text or json",
+	)
+}
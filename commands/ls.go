@@ -1,22 +1,81 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/cache"
 	"github.com/MichaelMure/git-bug/util"
 	"github.com/spf13/cobra"
 )
 
+var (
+	lsOneline  bool
+	lsMine     string
+	lsTemplate string
+)
+
 func runLsBug(cmd *cobra.Command, args []string) error {
-	bugs := bug.ReadAllLocalBugs(repo)
+	if lsOneline && lsTemplate != "" {
+		return errors.New("--oneline and --template can't be used together")
+	}
+
+	var format *bug.OutputFormat
+	if lsTemplate != "" {
+		var err error
+		format, err = bug.LoadOutputFormat(repo, lsTemplate)
+		if err != nil {
+			return err
+		}
+	}
+
+	query, err := cache.ParseQuery(strings.Join(args, " "))
+	if err != nil {
+		return err
+	}
+
+	if lsMine != "" {
+		query.Mine, err = cache.ParseMineFilter(lsMine)
+		if err != nil {
+			return err
+		}
+	}
+
+	backend := cache.NewRepoCache(repo)
+
+	err = query.ResolveMe(backend)
+	if err != nil {
+		return err
+	}
+
+	snapshots, err := backend.Search(query.Match)
+	if err != nil {
+		return err
+	}
+
+	query.Sort(snapshots)
 
-	for b := range bugs {
-		if b.Err != nil {
-			return b.Err
+	// computed once for all the bugs listed
+	abbreviations, err := bug.LocalAbbreviations(repo)
+	if err != nil {
+		return err
+	}
+
+	for _, snapshot := range snapshots {
+		if format != nil {
+			if err := format.Execute(os.Stdout, snapshot); err != nil {
+				return err
+			}
+			continue
 		}
 
-		snapshot := b.Bug.Compile()
+		if lsOneline {
+			fmt.Println(snapshot.Oneline())
+			continue
+		}
 
 		var author bug.Person
 
@@ -25,12 +84,14 @@ func runLsBug(cmd *cobra.Command, args []string) error {
 			author = create.Author
 		}
 
+		id := abbreviatedId(abbreviations, snapshot.Id())
+
 		// truncate + pad if needed
 		titleFmt := fmt.Sprintf("%-50.50s", snapshot.Title)
 		authorFmt := fmt.Sprintf("%-15.15s", author.Name)
 
 		fmt.Printf("%s %s\t%s\t%s\t%s\n",
-			util.Cyan(b.Bug.HumanId()),
+			util.Cyan(id),
 			util.Yellow(snapshot.Status),
 			titleFmt,
 			util.Magenta(authorFmt),
@@ -38,15 +99,80 @@ func runLsBug(cmd *cobra.Command, args []string) error {
 		)
 	}
 
+	// the history of these bugs can't be replayed, but they exist
+	incomplete := backend.IncompleteBugs()
+
+	// keep the output parseable
+	if lsOneline || format != nil {
+		if len(incomplete) > 0 {
+			fmt.Fprintf(os.Stderr, "%d bugs with an incomplete history: %s\n", len(incomplete), incomplete[0].Hint())
+		}
+		return nil
+	}
+
+	for _, e := range incomplete {
+		id := abbreviatedId(abbreviations, e.Id)
+
+		fmt.Printf("%s %s\n", util.Cyan(id), util.Red("incomplete history"))
+	}
+
+	if len(incomplete) > 0 {
+		fmt.Printf("\n%d bugs with an incomplete history: %s\n", len(incomplete), incomplete[0].Hint())
+	}
+
 	return nil
 }
 
+// abbreviatedId return the abbreviation of an id computed beforehand, or its
+// HumanId
+func abbreviatedId(abbreviations map[string]string, id string) string {
+	if abbrev, ok := abbreviations[id]; ok {
+		return abbrev
+	}
+	return bug.FormatHumanId(id)
+}
+
 var lsCmd = &cobra.Command{
-	Use:   "ls",
-	Short: "Display a summary of all bugs",
-	RunE:  runLsBug,
+	Use:   "ls [<query>]",
+	Short: "Display a summary of all bugs, or of the bugs matching the query",
+	Long: `Display a summary of all bugs, or of the bugs matching the query.
+
+A query is made of space separated key:value terms, all of them must match:
+  status:open          the status of the bug, open or closed
+  priority:high        the priority of the bug, low, normal, high or critical
+  author:me            the email of the author of the bug, or me
+  participant:me       the email of someone who edited the bug, or me
+  label:UI             a label of the bug
+  field:severity=high  the value of a custom field
+  checklist:qa:done    a checklist with every item done, or still pending
+  no:label             a field left empty, only the labels for now
+
+A term is negated with a "-" prefix or a "!" suffix on its key, like
+-label:wontfix or label!:wontfix, to exclude the bugs matching it. A bug
+matching any of the negated terms is excluded.
+
+The bugs can be ordered with a sort term, by priority, edit or creation time,
+ascending or descending: sort:priority-desc, sort:edit-asc, sort:creation-desc...
+
+"me" is the identity configured in git with user.email, and the other emails
+listed in git-bug.user.alternate-emails, separated by commas. The emails are
+compared ignoring the case and the "+tag" of the plus-addresses.
+
+` + templateHelp,
+	RunE: runLsBug,
 }
 
 func init() {
 	RootCmd.AddCommand(lsCmd)
+
+	lsCmd.Flags().BoolVar(&lsOneline, "oneline", false,
+		"Display each bug on a single line meant for the scripts: \"<id> <O|C> <labels|-> <title>\"",
+	)
+	lsCmd.Flags().StringVarP(&lsTemplate, "template", "t", "",
+		"Display each bug with a Go template, or a named format: "+formatNames(),
+	)
+	lsCmd.Flags().StringVar(&lsMine, "mine", "",
+		"Only display my bugs: the ones I opened (author), edited (participating) or both (all)",
+	)
+	lsCmd.Flags().Lookup("mine").NoOptDefVal = "all"
 }
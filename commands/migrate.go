@@ -0,0 +1,32 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/MichaelMure/git-bug/migration"
+	"github.com/spf13/cobra"
+)
+
+func runMigrate(cmd *cobra.Command, args []string) error {
+	pending, err := migration.Pending(repo)
+	if err != nil {
+		return err
+	}
+
+	if len(pending) == 0 {
+		fmt.Printf("The repository is already at the format version %d\n", migration.CurrentVersion())
+	}
+
+	return migration.Run(repo, os.Stdout)
+}
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Migrate the repository to the current data format",
+	RunE:  runMigrate,
+}
+
+func init() {
+	RootCmd.AddCommand(migrateCmd)
+}
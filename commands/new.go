@@ -2,9 +2,10 @@ package commands
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/MichaelMure/git-bug/bug"
-	"github.com/MichaelMure/git-bug/bug/operations"
+	"github.com/MichaelMure/git-bug/cache"
 	"github.com/MichaelMure/git-bug/input"
 	"github.com/spf13/cobra"
 )
@@ -13,11 +14,20 @@ var (
 	newTitle       string
 	newMessage     string
 	newMessageFile string
+	newTemplate    string
+	newValues      []string
 )
 
 func runNewBug(cmd *cobra.Command, args []string) error {
 	var err error
 
+	if newTemplate != "" {
+		newTitle, newMessage, err = fillTemplate(newTemplate, newValues)
+		if err != nil {
+			return err
+		}
+	}
+
 	if newMessageFile != "" && newMessage == "" {
 		newMessage, err = input.FromFile(newMessageFile)
 		if err != nil {
@@ -25,8 +35,16 @@ func runNewBug(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	if newMessage == "" || newTitle == "" {
-		newTitle, newMessage, err = input.BugCreateEditorInput(repo, newTitle, newMessage)
+	return createBug(newTitle, newMessage)
+}
+
+// createBug create a bug, the title and message being completed in the
+// editor if not both given
+func createBug(title string, message string) error {
+	var err error
+
+	if message == "" || title == "" {
+		title, message, err = input.BugCreateEditorInput(repo, title, message)
 
 		if err == input.ErrEmptyTitle {
 			fmt.Println("Empty title, aborting.")
@@ -37,25 +55,35 @@ func runNewBug(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	author, err := bug.GetUser(repo)
-	if err != nil {
-		return err
-	}
+	backend := cache.NewRepoCache(repo)
 
-	newBug, err := operations.Create(author, newTitle, newMessage)
+	result, err := backend.CreateAndCommit(title, message)
 	if err != nil {
 		return err
 	}
 
-	err = newBug.Commit(repo)
+	fmt.Println(result.Message)
 
+	return nil
+}
+
+// fillTemplate fill the named template with values given as key=value
+func fillTemplate(name string, rawValues []string) (string, string, error) {
+	template, err := bug.LoadTemplate(repo, name)
 	if err != nil {
-		return err
+		return "", "", err
 	}
 
-	fmt.Printf("%s created\n", newBug.HumanId())
+	values := make(map[string]string)
+	for _, raw := range rawValues {
+		split := strings.SplitN(raw, "=", 2)
+		if len(split) != 2 {
+			return "", "", fmt.Errorf("invalid value \"%s\", expected key=value", raw)
+		}
+		values[split[0]] = split[1]
+	}
 
-	return nil
+	return template.Fill(values)
 }
 
 var newCmd = &cobra.Command{
@@ -76,4 +104,10 @@ func init() {
 	newCmd.Flags().StringVarP(&newMessageFile, "file", "F", "",
 		"Take the message from the given file. Use - to read the message from the standard input",
 	)
+	newCmd.Flags().StringVarP(&newTemplate, "template", "T", "",
+		"Create the bug from the given template, stored in the git config as git-bug.template.<name> or in refs/git-bug/templates/<name>",
+	)
+	newCmd.Flags().StringArrayVar(&newValues, "set", nil,
+		"Set the value of a placeholder of the template, as key=value",
+	)
 }
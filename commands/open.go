@@ -3,11 +3,12 @@ package commands
 import (
 	"errors"
 
-	"github.com/MichaelMure/git-bug/bug"
-	"github.com/MichaelMure/git-bug/bug/operations"
+	"github.com/MichaelMure/git-bug/cache"
 	"github.com/spf13/cobra"
 )
 
+var openMessage string
+
 func runOpenBug(cmd *cobra.Command, args []string) error {
 	if len(args) > 1 {
 		return errors.New("Only opening one bug at a time is supported")
@@ -19,27 +20,35 @@ func runOpenBug(cmd *cobra.Command, args []string) error {
 
 	prefix := args[0]
 
-	b, err := bug.FindLocalBug(repo, prefix)
-	if err != nil {
-		return err
-	}
+	backend := cache.NewRepoCache(repo)
 
-	author, err := bug.GetUser(repo)
+	b, err := resolveBug(backend, prefix)
 	if err != nil {
 		return err
 	}
 
-	operations.Open(b, author)
+	// the comment and the change are committed together
+	if openMessage != "" {
+		err = b.AddComment(openMessage)
+		if err != nil {
+			return err
+		}
+	}
 
-	return b.Commit(repo)
+	_, err = b.OpenAndCommit()
+	return err
 }
 
 var openCmd = &cobra.Command{
-	Use:   "open <id>",
+	Use:   "open [<option>...] <id>",
 	Short: "Mark the bug as open",
 	RunE:  runOpenBug,
 }
 
 func init() {
 	RootCmd.AddCommand(openCmd)
+
+	openCmd.Flags().StringVarP(&openMessage, "message", "m", "",
+		"Add a comment along with the reopening, in the same commit",
+	)
 }
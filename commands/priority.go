@@ -0,0 +1,59 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/spf13/cobra"
+)
+
+func runPriority(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return errors.New("You must provide a bug id")
+	}
+
+	if len(args) > 2 {
+		return errors.New("Only one priority can be given")
+	}
+
+	backend := cache.NewRepoCache(repo)
+
+	b, err := resolveBug(backend, args[0])
+	if err != nil {
+		return err
+	}
+
+	// no priority display the current one
+	if len(args) == 1 {
+		fmt.Println(b.Snapshot().Priority)
+		return nil
+	}
+
+	priority, err := bug.ParsePriority(args[1])
+	if err != nil {
+		return err
+	}
+
+	err = b.SetPriority(priority)
+	if err != nil {
+		return err
+	}
+
+	return b.Commit()
+}
+
+var priorityCmd = &cobra.Command{
+	Use:   "priority <id> [<priority>]",
+	Short: "Display or change the priority of a bug",
+	Long: `Display or change the priority of a bug.
+
+The priority is one of low, normal, high or critical. A bug never given a
+priority is of normal priority.`,
+	RunE: runPriority,
+}
+
+func init() {
+	RootCmd.AddCommand(priorityCmd)
+}
@@ -1,33 +1,88 @@
 package commands
 
 import (
-	"errors"
 	"os"
 
 	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/cache"
 	"github.com/spf13/cobra"
 )
 
+var (
+	pullRemote        string
+	pullForceRedact   bool
+	pullNoTransaction bool
+	pullStrategy      string
+)
+
 func runPull(cmd *cobra.Command, args []string) error {
-	if len(args) > 1 {
-		return errors.New("Only pulling from one remote at a time is supported")
+	remote, err := resolveRemote(args, pullRemote)
+	if err != nil {
+		return err
 	}
 
-	remote := "origin"
-	if len(args) == 1 {
-		remote = args[0]
+	var strategy bug.MergeStrategy
+	if pullStrategy != "" {
+		strategy, err = bug.ParseMergeStrategy(pullStrategy)
+		if err != nil {
+			return err
+		}
 	}
 
-	return bug.Pull(repo, os.Stdout, remote)
+	backend := cache.NewRepoCache(repo)
+
+	return backend.PullWithOptions(remote, os.Stdout, bug.MergeOptions{
+		ForceRedact:   pullForceRedact,
+		NoTransaction: pullNoTransaction,
+		Strategy:      strategy,
+	})
 }
 
 // showCmd defines the "push" subcommand.
 var pullCmd = &cobra.Command{
 	Use:   "pull [<remote>]",
 	Short: "Pull bugs update from a git remote",
-	RunE:  runPull,
+	Long: `Pull bugs update from a git remote.
+
+Without remote, the one configured with git-bug.remote is used, or else origin.
+
+A bug whose history has been rewritten remotely by "redact" is not merged,
+unless --force-redact is given. The local history is then replaced by the
+redacted one, the local edits being committed again on top of it.
+
+The merged bugs are updated all at once at the end, so that an interrupted
+pull leaves the bugs as they were. With --no-transaction, each bug is updated
+as soon as it's merged instead, for the pulls too large for a single
+transaction.
+
+The bugs that diverged are joined with the strategy configured with
+git-bug.merge.strategy, or given with --strategy:
+  rebase         the local commits are rewritten on top of the remote ones,
+                 the default, also named rebase-local
+  rebase-remote  the remote commits are rewritten on top of the local ones,
+                 the push then replace the remote version if it didn't
+                 change since the pull
+  merge          both histories are kept and joined by a merge commit, also
+                 named interleave
+
+Whichever the strategy, the operations of both sides are ordered by their
+logical time.`,
+	RunE: runPull,
 }
 
 func init() {
 	RootCmd.AddCommand(pullCmd)
+
+	pullCmd.Flags().StringVar(&pullRemote, "remote", "",
+		"The remote to pull from",
+	)
+	pullCmd.Flags().BoolVar(&pullForceRedact, "force-redact", false,
+		"Accept the histories rewritten by a redaction",
+	)
+	pullCmd.Flags().BoolVar(&pullNoTransaction, "no-transaction", false,
+		"Update each bug as soon as it's merged instead of all at once",
+	)
+	pullCmd.Flags().StringVar(&pullStrategy, "strategy", "",
+		"Join the diverged bugs with the given strategy: rebase, rebase-remote or merge",
+	)
 }
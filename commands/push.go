@@ -1,24 +1,38 @@
 package commands
 
 import (
-	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/cache"
 	"github.com/spf13/cobra"
 )
 
+var pushRemote string
+
 func runPush(cmd *cobra.Command, args []string) error {
-	if len(args) > 1 {
-		return errors.New("Only pushing to one remote at a time is supported")
+	remote, err := resolveRemote(args, pushRemote)
+	if err != nil {
+		return err
+	}
+
+	// the remote would reject them, better tell which ones before pushing
+	diverged, err := bug.CheckPushable(repo, remote)
+	if err != nil {
+		return err
 	}
 
-	remote := "origin"
-	if len(args) == 1 {
-		remote = args[0]
+	if len(diverged) > 0 {
+		return fmt.Errorf("These bugs diverged from the remote %s, pull them first:\n%s",
+			remote, strings.Join(diverged, "\n"))
 	}
 
-	stdout, err := bug.Push(repo, remote)
+	backend := cache.NewRepoCache(repo)
+
+	fmt.Printf("Pushing to remote %s ...\n", remote)
+
+	stdout, err := backend.Push(remote)
 	if err != nil {
 		return err
 	}
@@ -32,9 +46,20 @@ func runPush(cmd *cobra.Command, args []string) error {
 var pushCmd = &cobra.Command{
 	Use:   "push [<remote>]",
 	Short: "Push bugs update to a git remote",
-	RunE:  runPush,
+	Long: `Push bugs update to a git remote.
+
+Without remote, the one configured with git-bug.remote is used, or else origin.
+
+Nothing is pushed if a bug diverged from its version on the remote, as of the last pull. It need to be pulled first.
+
+The bugs pulled with the rebase-remote strategy replace their remote version, unless it changed since the pull.`,
+	RunE: runPush,
 }
 
 func init() {
 	RootCmd.AddCommand(pushCmd)
+
+	pushCmd.Flags().StringVar(&pushRemote, "remote", "",
+		"The remote to push to",
+	)
 }
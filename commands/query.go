@@ -0,0 +1,203 @@
+package commands
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/util"
+	"github.com/spf13/cobra"
+)
+
+var (
+	queryPorcelain bool
+	queryLimit     int
+	queryFields    string
+)
+
+// queryField extract a field of a bug, as a single line
+type queryField func(snap *bug.Snapshot) string
+
+// The fields available in the query output. Their names and content are
+// stable, as they are parsed by scripts and editor plugins.
+var queryFieldsByName = map[string]queryField{
+	"id": func(snap *bug.Snapshot) string {
+		return snap.Id()
+	},
+	"humanid": func(snap *bug.Snapshot) string {
+		return snap.HumanId()
+	},
+	"status": func(snap *bug.Snapshot) string {
+		return snap.Status.String()
+	},
+	"priority": func(snap *bug.Snapshot) string {
+		return snap.Priority.String()
+	},
+	"title": func(snap *bug.Snapshot) string {
+		return snap.Title
+	},
+	"author": func(snap *bug.Snapshot) string {
+		return snap.Author.Name
+	},
+	"email": func(snap *bug.Snapshot) string {
+		return snap.Author.Email
+	},
+	"created": func(snap *bug.Snapshot) string {
+		return strconv.FormatInt(snap.CreatedAt.Unix(), 10)
+	},
+	"edited": func(snap *bug.Snapshot) string {
+		return strconv.FormatInt(snap.LastEdit().Unix(), 10)
+	},
+	"labels": func(snap *bug.Snapshot) string {
+		labels := make([]string, len(snap.Labels))
+		for i, label := range snap.Labels {
+			labels[i] = string(label)
+		}
+		return strings.Join(labels, ",")
+	},
+	"comments": func(snap *bug.Snapshot) string {
+		return strconv.Itoa(len(snap.Comments) - 1)
+	},
+}
+
+var queryFieldColors = map[string]func(a ...interface{}) string{
+	"id":      util.Cyan,
+	"humanid": util.Cyan,
+	"status":  util.Yellow,
+	"author":  util.Magenta,
+}
+
+// a tab or a line break in a value would break the format
+var queryFieldReplacer = strings.NewReplacer("\t", " ", "\r", " ", "\n", " ")
+
+func runQuery(cmd *cobra.Command, args []string) error {
+	query, err := cache.ParseQuery(strings.Join(args, " "))
+	if err != nil {
+		return err
+	}
+
+	if queryLimit < 0 {
+		return fmt.Errorf("invalid limit: %d", queryLimit)
+	}
+
+	names := strings.Split(queryFields, ",")
+	fields := make([]queryField, len(names))
+
+	for i, name := range names {
+		names[i] = strings.TrimSpace(name)
+
+		field, ok := queryFieldsByName[names[i]]
+		if !ok {
+			return fmt.Errorf("unknown field \"%s\"", names[i])
+		}
+		fields[i] = field
+	}
+
+	backend := cache.NewRepoCache(repo)
+
+	err = query.ResolveMe(backend)
+	if err != nil {
+		return err
+	}
+
+	ids, err := backend.AllBugIds()
+	if err != nil {
+		return err
+	}
+
+	var snaps []*bug.Snapshot
+
+	// the bugs are read one by one to stop as soon as the limit is reached,
+	// unless they have to be sorted first
+	for _, id := range ids {
+		if queryLimit > 0 && len(snaps) >= queryLimit && query.OrderBy == cache.OrderByDefault {
+			break
+		}
+
+		b, err := backend.ResolveBug(id)
+		if err != nil {
+			return err
+		}
+
+		snap := b.Snapshot()
+
+		if query.Match(snap) {
+			snaps = append(snaps, snap)
+		}
+	}
+
+	query.Sort(snaps)
+
+	if queryLimit > 0 && len(snaps) > queryLimit {
+		snaps = snaps[:queryLimit]
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+
+	for _, snap := range snaps {
+		values := make([]string, len(fields))
+		for i, field := range fields {
+			values[i] = queryFieldReplacer.Replace(field(snap))
+
+			if color, ok := queryFieldColors[names[i]]; ok && !queryPorcelain {
+				values[i] = color(values[i])
+			}
+		}
+
+		fmt.Fprintln(out, strings.Join(values, "\t"))
+	}
+
+	if err := out.Flush(); err != nil {
+		return err
+	}
+
+	// editor plugins rely on the exit code to know if a bug matched, without
+	// any error message
+	if len(snaps) == 0 {
+		closeTrace()
+		os.Exit(1)
+	}
+
+	return nil
+}
+
+var queryCmd = &cobra.Command{
+	Use:   "query [<option>...] [<query>]",
+	Short: "Output the bugs matching a query, one per line, for scripts and editors",
+	Long: `Output the bugs matching a query, one per line, for scripts and editors.
+
+The query is the same as for ls. Each line hold the selected fields separated
+by tabs, in the given order, without header. The available fields are:
+  id        the full id of the bug
+  humanid   the truncated id of the bug
+  status    open or closed
+  priority  low, normal, high or critical
+  title     the title of the bug
+  author    the name of the author of the bug
+  email     the email of the author of the bug
+  created   the creation time, as a unix timestamp
+  edited    the time of the last edition, as a unix timestamp
+  labels    the labels of the bug, separated by commas
+  comments  the number of comments, not counting the description
+
+The exit code is 1 if no bug match. It never prompt for anything.`,
+	RunE: runQuery,
+}
+
+func init() {
+	RootCmd.AddCommand(queryCmd)
+
+	queryCmd.Flags().BoolVarP(&queryPorcelain, "porcelain", "p", false,
+		"Output without colors, in a format stable across versions",
+	)
+	queryCmd.Flags().IntVarP(&queryLimit, "limit", "n", 0,
+		"Output at most this number of bugs, 0 for no limit",
+	)
+	queryCmd.Flags().StringVarP(&queryFields, "fields", "f", "id,status,title",
+		"The comma separated fields to output",
+	)
+}
@@ -0,0 +1,72 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/spf13/cobra"
+)
+
+func runRedact(cmd *cobra.Command, args []string) error {
+	if len(args) != 2 {
+		return errors.New("You must provide a bug id and an operation hash")
+	}
+
+	// rewriting the history, only an id is accepted
+	b, err := bug.FindLocalBug(repo, args[0])
+	if err != nil {
+		return err
+	}
+
+	hash, err := b.SearchOperation(args[1])
+	if err != nil {
+		return err
+	}
+
+	rewritten, err := b.Redact(repo, hash)
+	if err != nil {
+		return err
+	}
+
+	id, err := bug.AbbreviateId(repo, b.Id())
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Operation %s of bug %s redacted, rewritten commits:\n", hash, id)
+	for _, commit := range rewritten {
+		fmt.Printf("%s → %s\n", commit.Old, commit.New)
+	}
+
+	ref := fmt.Sprintf("refs/%s/%s", bug.RepoNamespace(repo), b.Id())
+
+	fmt.Println()
+	fmt.Println("Warning: the original operation is still stored in the other clones.")
+	fmt.Printf("Push the rewritten history with \"git push --force <remote> %s\",\n", ref)
+	fmt.Printf("then every other clone must pull it with \"%s pull --force-redact\".\n", rootCommandName)
+	fmt.Println("Locally, the original commits stay reachable from the remote-tracking refs")
+	fmt.Println("and the reflog until they are updated and garbage collected.")
+
+	return nil
+}
+
+var redactCmd = &cobra.Command{
+	Use:   "redact <id> <operation>",
+	Short: "Rewrite the history of a bug to remove the text of an operation",
+	Long: `Rewrite the history of a bug to remove the text of an operation, like a
+secret pasted in a comment.
+
+The operation is identified by a prefix of its hash, as the comment hashes
+displayed by "show". Its text is replaced by a redaction marker, and the
+commits of the bug from the one holding it are rewritten. The attached files
+are kept.
+
+Unlike "comment rm", this rewrite the shared history: the other clones must
+accept it explicitly with "pull --force-redact".`,
+	RunE: runRedact,
+}
+
+func init() {
+	RootCmd.AddCommand(redactCmd)
+}
@@ -0,0 +1,33 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/spf13/cobra"
+)
+
+func runRemote(cmd *cobra.Command, args []string) error {
+	remote, err := bug.ConfiguredRemote(repo)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(remote)
+
+	return bug.CheckRemote(repo, remote)
+}
+
+var remoteCmd = &cobra.Command{
+	Use:   "remote",
+	Short: "Display the remote used to sync the bugs by default",
+	Long: `Display the remote used to sync the bugs by default.
+
+It's origin, unless another remote is configured with git-bug.remote:
+  git config git-bug.remote bugs`,
+	RunE: runRemote,
+}
+
+func init() {
+	RootCmd.AddCommand(remoteCmd)
+}
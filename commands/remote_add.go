@@ -0,0 +1,46 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/spf13/cobra"
+)
+
+var remoteAddDefault bool
+
+func runRemoteAdd(cmd *cobra.Command, args []string) error {
+	if len(args) != 2 {
+		return errors.New("You must provide a name and an url")
+	}
+
+	name, url := args[0], args[1]
+
+	err := bug.AddRemote(repo, name, url)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Added remote %s, restricted to the bugs\n", name)
+
+	if !remoteAddDefault {
+		return nil
+	}
+
+	return bug.SetDefaultRemote(repo, name)
+}
+
+var remoteAddCmd = &cobra.Command{
+	Use:   "add [<option>...] <name> <url>",
+	Short: "Add a git remote only used to sync the bugs",
+	RunE:  runRemoteAdd,
+}
+
+func init() {
+	remoteCmd.AddCommand(remoteAddCmd)
+
+	remoteAddCmd.Flags().BoolVarP(&remoteAddDefault, "default", "d", false,
+		"Use this remote to sync the bugs by default",
+	)
+}
@@ -1,10 +1,16 @@
 package commands
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/input"
+	"github.com/MichaelMure/git-bug/migration"
 	"github.com/MichaelMure/git-bug/repository"
 	"github.com/spf13/cobra"
 )
@@ -12,12 +18,48 @@ import (
 // Will display "git bug"
 // \u00A0 is a non-breaking space
 // It's used to avoid cobra to split the Use string at the first space to get the root command name
-//const rootCommandName = "git\u00A0bug"
+// const rootCommandName = "git\u00A0bug"
 const rootCommandName = "git-bug"
 
 // package scoped var to hold the repo after the PreRun execution
 var repo repository.Repo
 
+// the namespace given on the command line, overriding the git config
+var rootNamespace string
+
+// migrate the repository if needed without asking first
+var rootAutoMigrate bool
+
+// only accept bug ids, never titles
+var rootIdOnly bool
+
+// trace the git calls, see traceRepo
+var rootVerbose bool
+
+// never run the first-run wizard
+var rootNoWizard bool
+
+// commands that never write in the repository and can run before it's
+// migrated. migrate handle the migration by itself.
+var readOnlyCommands = map[string]bool{
+	"bridge":        true,
+	"commands":      true,
+	"export":        true,
+	"export-static": true,
+	"fsck":          true,
+	"hook":          true,
+	"log":           true,
+	"ls":            true,
+	"migrate":       true,
+	"pre-receive":   true,
+	"push":          true,
+	"query":         true,
+	"remote":        true,
+	"show":          true,
+	"status":        true,
+	"title":         true,
+}
+
 // RootCmd represents the base command when called without any subcommands
 var RootCmd = &cobra.Command{
 	Version: "0.2.0",
@@ -28,10 +70,9 @@ var RootCmd = &cobra.Command{
 
 It use the same internal storage so it doesn't pollute your project. As you would do with commits and branches, you can push your bugs to the same git remote your are already using to collaborate with other peoples.`,
 
-	// Force the execution of the PreRun while still displaying the help
-	Run: func(cmd *cobra.Command, args []string) {
-		cmd.Help()
-	},
+	// Force the execution of the PreRun while still displaying the help, or
+	// the wizard in a new repository
+	RunE: runRoot,
 
 	// Load the repo before any command execution
 	// Note, this concern only commands that actually have a Run function
@@ -43,12 +84,81 @@ It use the same internal storage so it doesn't pollute your project. As you woul
 _git_bug() {
     __start_git-bug "$@"
 }
+
+__git-bug_complete_labels() {
+    local IFS=$'\n'
+    COMPREPLY=( $(compgen -W "$(git bug label ls 2>/dev/null | cut -f1)" -- "$cur") )
+}
+
+# the values of the query terms asking for an empty field or a label
+__git-bug_complete_query() {
+    local IFS=$'\n'
+    case "$cur" in
+        no:*)
+            COMPREPLY=( $(compgen -P "no:" -W "` + strings.Join(cache.EmptiableFields, "\n") + `" -- "${cur#no:}") )
+            ;;
+        label:*|-label:*|label!:*)
+            COMPREPLY=( $(compgen -P "${cur%%:*}:" -W "$(git bug label ls 2>/dev/null | cut -f1)" -- "${cur#*:}") )
+            ;;
+    esac
+}
+
+__custom_func() {
+    case ${last_command} in
+        git-bug_ls | git-bug_query)
+            __git-bug_complete_query
+            ;;
+        git-bug_label)
+            # the labels come after the bug id
+            if [[ ${#nouns[@]} -ge 1 ]]; then
+                __git-bug_complete_labels
+            fi
+            ;;
+    esac
+}
 `,
 }
 
+func init() {
+	RootCmd.PersistentFlags().StringVar(&rootNamespace, "namespace", "",
+		"Use the bugs of the given namespace instead of the configured one (default \"bugs\")",
+	)
+	RootCmd.PersistentFlags().BoolVar(&rootAutoMigrate, "auto-migrate", false,
+		"Migrate the repository to the current format if needed, without asking",
+	)
+	RootCmd.PersistentFlags().BoolVar(&rootIdOnly, "id-only", false,
+		"Only accept bug ids, not titles, to select a bug",
+	)
+	RootCmd.PersistentFlags().BoolVar(&rootVerbose, "verbose", false,
+		"Log every git call and its duration to stderr, like GIT_BUG_TRACE=1",
+	)
+	RootCmd.Flags().BoolVar(&rootNoWizard, "no-wizard", false,
+		"Display the help instead of the first-run wizard",
+	)
+}
+
+func runRoot(cmd *cobra.Command, args []string) error {
+	run, err := shouldRunWizard()
+	if err != nil {
+		return err
+	}
+
+	if run {
+		return runWizard()
+	}
+
+	return cmd.Help()
+}
+
 func Execute() {
-	if err := RootCmd.Execute(); err != nil {
+	err := RootCmd.Execute()
+	closeTrace()
+
+	if err != nil {
 		fmt.Println(err)
+		if incomplete, ok := err.(*bug.ErrIncompleteHistory); ok {
+			fmt.Println(incomplete.Hint())
+		}
 		os.Exit(1)
 	}
 }
@@ -68,5 +178,166 @@ func loadRepo(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	return nil
+	repo, err = traceRepo(repo)
+	if err != nil {
+		return err
+	}
+
+	if rootNamespace != "" {
+		err = bug.SetNamespace(rootNamespace)
+	} else {
+		err = bug.LoadNamespace(repo)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if err := bug.LoadLimits(repo); err != nil {
+		return err
+	}
+
+	return checkMigration(cmd)
+}
+
+// checkMigration make sure that the repository is in the current format
+// before running a command that could write in it
+func checkMigration(cmd *cobra.Command) error {
+	pending, err := migration.Pending(repo)
+	if err != nil {
+		return err
+	}
+
+	// the root command only display the help
+	if !cmd.HasParent() || readOnlyCommands[cmd.Name()] {
+		return nil
+	}
+
+	if len(pending) > 0 && !rootAutoMigrate {
+		question := fmt.Sprintf("The repository need to be migrated to the format version %d. Migrate now?",
+			migration.CurrentVersion())
+
+		ok, err := input.Confirm(question)
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			return fmt.Errorf("The repository must be migrated first, run \"%s migrate\"", rootCommandName)
+		}
+	}
+
+	return migration.Run(repo, os.Stdout)
+}
+
+// resolveBug find the bug designated by a command argument: a full or
+// partial id, or else a part of the title of a single open bug. Every
+// command taking a bug should use it to behave consistently.
+func resolveBug(backend cache.RepoCacher, arg string) (cache.BugCacher, error) {
+	if rootIdOnly {
+		return backend.ResolveBugPrefix(arg)
+	}
+
+	ids, err := backend.AllBugIds()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range ids {
+		if strings.HasPrefix(id, arg) {
+			return backend.ResolveBugPrefix(arg)
+		}
+	}
+
+	b, err := backend.ResolveBugTitle(arg)
+	if err != bug.ErrNoMatchingBug {
+		return b, err
+	}
+
+	// no title match either, report the id suggestions
+	return backend.ResolveBugPrefix(arg)
+}
+
+// The exit codes of the plumbing commands when the bug can't be resolved
+const (
+	exitNoMatchingBug = 1
+	exitAmbiguousBug  = 2
+)
+
+// resolveBugExit read the only bug matching an id prefix, for the plumbing
+// commands. It exit with a dedicated code when no or several bugs match, so
+// that scripts can tell them apart.
+func resolveBugExit(prefix string) (*bug.Bug, error) {
+	ids, err := bug.ListLocalIds(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	var matching []string
+	for _, id := range ids {
+		if strings.HasPrefix(id, prefix) {
+			matching = append(matching, id)
+		}
+	}
+
+	if len(matching) == 0 {
+		fmt.Fprintln(os.Stderr, bug.ErrNoMatchingBug)
+		os.Exit(exitNoMatchingBug)
+	}
+
+	if len(matching) > 1 {
+		fmt.Fprintf(os.Stderr, "Multiple matching bug found:\n%s\n", strings.Join(matching, "\n"))
+		os.Exit(exitAmbiguousBug)
+	}
+
+	return bug.ReadLocalBug(repo, matching[0])
+}
+
+// printBugField output a single field of a bug for the plumbing commands,
+// optionally along with the full id of the bug
+func printBugField(format string, withId bool, id string, name string, value string) error {
+	switch format {
+	case "text":
+		if withId {
+			fmt.Printf("%s\t%s\n", id, value)
+		} else {
+			fmt.Println(value)
+		}
+		return nil
+	case "json":
+		fields := map[string]string{name: value}
+		if withId {
+			fields["id"] = id
+		}
+		return json.NewEncoder(os.Stdout).Encode(fields)
+	default:
+		return fmt.Errorf("unknown format %s, expected text or json", format)
+	}
+}
+
+// resolveRemote select the remote to sync the bugs with: the one given as
+// argument or with --remote, or else the configured one. It must exist.
+func resolveRemote(args []string, flag string) (string, error) {
+	if len(args) > 1 {
+		return "", errors.New("Only one remote at a time is supported")
+	}
+
+	var remote string
+
+	switch {
+	case len(args) == 1 && flag != "" && args[0] != flag:
+		return "", errors.New("The remote is given both as argument and with --remote")
+	case len(args) == 1:
+		remote = args[0]
+	case flag != "":
+		remote = flag
+	default:
+		var err error
+		remote, err = bug.ConfiguredRemote(repo)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return remote, bug.CheckRemote(repo, remote)
 }
@@ -3,13 +3,25 @@ package commands
 import (
 	"errors"
 	"fmt"
+	"os"
+	"sort"
 	"strings"
 
 	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/cache"
 	"github.com/MichaelMure/git-bug/util"
 	"github.com/spf13/cobra"
 )
 
+var (
+	showComment        string
+	showHistory        bool
+	showIncludeDeleted bool
+	showRaw            bool
+	showArchived       bool
+	showTemplate       string
+)
+
 func runShowBug(cmd *cobra.Command, args []string) error {
 	if len(args) > 1 {
 		return errors.New("Only showing one bug at a time is supported")
@@ -21,23 +33,54 @@ func runShowBug(cmd *cobra.Command, args []string) error {
 
 	prefix := args[0]
 
-	b, err := bug.FindLocalBug(repo, prefix)
+	backend := cache.NewRepoCache(repo)
+
+	b, err := resolveBug(backend, prefix)
 	if err != nil {
 		return err
 	}
 
-	snapshot := b.Compile()
+	snapshot := b.Snapshot()
 
 	if len(snapshot.Comments) == 0 {
 		return errors.New("Invalid bug: no comment")
 	}
 
+	if showHistory && showComment == "" {
+		return errors.New("--history require a comment to be selected with --comment")
+	}
+
+	if showComment != "" {
+		if showTemplate != "" {
+			return errors.New("--comment and --template can't be used together")
+		}
+		return showSingleComment(snapshot, showComment, showHistory)
+	}
+
+	if showTemplate != "" {
+		format, err := bug.LoadOutputFormat(repo, showTemplate)
+		if err != nil {
+			return err
+		}
+		return format.Execute(os.Stdout, snapshot)
+	}
+
+	hashes, err := snapshot.CommentHashes()
+	if err != nil {
+		return err
+	}
+
 	firstComment := snapshot.Comments[0]
 
+	id, err := bug.AbbreviateId(repo, snapshot.Id())
+	if err != nil {
+		return err
+	}
+
 	// Header
 	fmt.Printf("[%s] %s %s\n\n",
 		util.Yellow(snapshot.Status),
-		util.Cyan(snapshot.HumanId()),
+		util.Cyan(id),
 		snapshot.Title,
 	)
 
@@ -51,36 +94,225 @@ func runShowBug(cmd *cobra.Command, args []string) error {
 		labels[i] = string(snapshot.Labels[i])
 	}
 
+	fmt.Printf("priority: %s\n", snapshot.Priority)
 	fmt.Printf("labels: %s\n\n",
 		strings.Join(labels, ", "),
 	)
 
+	if len(snapshot.CustomFields) > 0 {
+		var keys []string
+		for key := range snapshot.CustomFields {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			fmt.Printf("%s: %s\n", key, snapshot.CustomFields[key])
+		}
+		fmt.Println()
+	}
+
+	for _, checklist := range snapshot.Checklists {
+		done, total := checklist.Completion()
+		fmt.Printf("checklist %s: %d/%d\n", checklist.Name, done, total)
+	}
+	if len(snapshot.Checklists) > 0 {
+		fmt.Println()
+	}
+
+	if len(snapshot.TimeLogs) > 0 {
+		fmt.Printf("time spent: %s\n\n", formatTimeSpent(snapshot.TimeSpent()))
+	}
+
 	// Comments
 	indent := "  "
+	// the attachments are numbered across the comments, see "git bug attachment get"
+	attachments := 0
 
 	for i, comment := range snapshot.Comments {
-		fmt.Printf("%s#%d %s <%s>\n\n",
+		fmt.Printf("%s#%d %s %s <%s>\n\n",
 			indent,
 			i,
+			util.Cyan(fmt.Sprintf("%.7s", hashes[i])),
 			comment.Author.Name,
 			comment.Author.Email,
 		)
 
+		fmt.Printf("%s\n\n\n", wrapMessage(displayedMessage(comment), indent))
+
+		if err := showAttachments(comment, indent, &attachments); err != nil {
+			return err
+		}
+
+		// the archived comments are older than the others
+		if i == 0 && snapshot.ArchivedCount > 0 {
+			if err := showArchivedComments(snapshot, indent); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// showAttachments list the files attached to a comment, numbered after the
+// ones of the previous comments
+func showAttachments(comment bug.Comment, indent string, number *int) error {
+	for _, hash := range comment.Files {
+		attachment, err := bug.ReadAttachment(repo, hash)
+		if err != nil {
+			return err
+		}
+
+		*number++
+
+		fmt.Printf("%s%s %s (%s, %s)\n",
+			indent,
+			util.Yellow(fmt.Sprintf("attachment %d:", *number)),
+			attachment.Filename(),
+			attachment.HumanSize(),
+			attachment.ContentType,
+		)
+	}
+
+	if len(comment.Files) > 0 {
+		fmt.Print("\n\n")
+	}
+
+	return nil
+}
+
+func showArchivedComments(snapshot *bug.Snapshot, indent string) error {
+	if !showArchived {
 		fmt.Printf("%s%s\n\n\n",
 			indent,
-			comment.Message,
+			util.Yellow(fmt.Sprintf("%d older comments archived, use --archived to display them", snapshot.ArchivedCount)),
 		)
+		return nil
+	}
+
+	archived, err := snapshot.LoadArchivedComments(repo)
+	if err != nil {
+		return err
+	}
+
+	for _, comment := range archived {
+		fmt.Printf("%s%s %s <%s>\n\n",
+			indent,
+			util.Yellow("archived"),
+			comment.Author.Name,
+			comment.Author.Email,
+		)
+
+		fmt.Printf("%s\n\n\n", wrapMessage(displayedMessage(comment), indent))
 	}
 
 	return nil
 }
 
+func showSingleComment(snapshot *bug.Snapshot, prefix string, history bool) error {
+	index, err := snapshot.SearchComment(prefix)
+	if err != nil {
+		return err
+	}
+
+	comment := snapshot.Comments[index]
+
+	if !history {
+		fmt.Printf("%s <%s> %s\n\n%s\n",
+			util.Magenta(comment.Author.Name),
+			comment.Author.Email,
+			comment.FormatTime(),
+			wrapMessage(displayedMessage(comment), ""),
+		)
+		return nil
+	}
+
+	if comment.IsDeleted() && !showIncludeDeleted {
+		return errors.New("This comment has been deleted, use --include-deleted to show its history")
+	}
+
+	for i, revision := range comment.History {
+		fmt.Printf("revision %d by %s <%s> %s\n\n",
+			i,
+			util.Magenta(revision.Author.Name),
+			revision.Author.Email,
+			revision.FormatTime(),
+		)
+
+		if i == 0 {
+			fmt.Printf("%s\n\n", revision.GetMessage())
+			continue
+		}
+
+		diff := util.WordDiff(comment.History[i-1].GetMessage(), revision.GetMessage())
+		fmt.Printf("%s\n\n", util.FormatWordDiff(diff))
+	}
+
+	return nil
+}
+
+// displayedMessage return the message to display for a comment, revealing the
+// deleted ones only if asked to
+func displayedMessage(comment bug.Comment) string {
+	if comment.IsDeleted() && showIncludeDeleted {
+		return fmt.Sprintf("%s\n%s", util.Red("["+comment.GetMessage()+"]"), renderMessage(comment.OriginalMessage()))
+	}
+
+	if comment.IsDeleted() {
+		return comment.GetMessage()
+	}
+
+	return renderMessage(comment.GetMessage())
+}
+
+// wrapMessage wrap a message to the width of the terminal, indenting each
+// of its lines
+func wrapMessage(message string, indent string) string {
+	lines := util.WrapText(message, util.TerminalWidth(os.Stdout)-len(indent))
+	for i, line := range lines {
+		lines[i] = indent + line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderMessage render the markdown of a message, unless disabled
+func renderMessage(message string) string {
+	if showRaw {
+		return message
+	}
+
+	return util.RenderMarkdown(message, 0, util.AnsiMarkdownStyle)
+}
+
 var showCmd = &cobra.Command{
-	Use:   "show <id>",
+	Use:   "show [<option>...] <id>",
 	Short: "Display the details of a bug",
-	RunE:  runShowBug,
+	Long: `Display the details of a bug, with its comments.
+
+` + templateHelp,
+	RunE: runShowBug,
 }
 
 func init() {
 	RootCmd.AddCommand(showCmd)
+
+	showCmd.Flags().StringVarP(&showComment, "comment", "c", "",
+		"Only show the comment matching the given hash prefix",
+	)
+	showCmd.Flags().BoolVar(&showHistory, "history", false,
+		"Show the revisions of the selected comment, with the difference between each of them",
+	)
+	showCmd.Flags().BoolVar(&showIncludeDeleted, "include-deleted", false,
+		"Show the original content of the deleted comments",
+	)
+	showCmd.Flags().BoolVar(&showArchived, "archived", false,
+		"Also display the archived comments, read from their blob",
+	)
+	showCmd.Flags().BoolVar(&showRaw, "raw", false,
+		"Show the messages as written, without rendering their markdown",
+	)
+	showCmd.Flags().StringVarP(&showTemplate, "template", "t", "",
+		"Display the bug with a Go template, or a named format: "+formatNames(),
+	)
 }
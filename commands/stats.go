@@ -0,0 +1,164 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/stats"
+	"github.com/MichaelMure/git-bug/util"
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+)
+
+const statsDateLayout = "2006-01-02"
+
+var (
+	statsWeeks    int
+	statsFormat   string
+	statsSince    string
+	statsUntil    string
+	statsByAuthor bool
+)
+
+func runStats(cmd *cobra.Command, args []string) error {
+	opts := stats.Options{
+		Weeks:    statsWeeks,
+		ByAuthor: statsByAuthor,
+		Now:      time.Now(),
+	}
+
+	if statsWeeks < 0 {
+		return fmt.Errorf("invalid number of weeks: %d", statsWeeks)
+	}
+
+	var err error
+
+	if statsSince != "" {
+		opts.Since, err = time.ParseInLocation(statsDateLayout, statsSince, time.Local)
+		if err != nil {
+			return fmt.Errorf("invalid date for --since, expected YYYY-MM-DD: %s", statsSince)
+		}
+	}
+
+	if statsUntil != "" {
+		opts.Until, err = time.ParseInLocation(statsDateLayout, statsUntil, time.Local)
+		if err != nil {
+			return fmt.Errorf("invalid date for --until, expected YYYY-MM-DD: %s", statsUntil)
+		}
+	}
+
+	backend := cache.NewRepoCache(repo)
+
+	snapshots, err := backend.Search(nil)
+	if err != nil {
+		return err
+	}
+
+	result := stats.Compute(snapshots, opts)
+
+	switch statsFormat {
+	case "text":
+		printStats(result)
+		return nil
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result)
+	default:
+		return fmt.Errorf("unknown format %s, expected text or json", statsFormat)
+	}
+}
+
+func printStats(s *stats.Stats) {
+	fmt.Printf("bugs: %d (open: %d, closed: %d)\n\n", s.Total, s.Open, s.Closed)
+
+	if len(s.Weeks) > 0 {
+		opened := make([]int, len(s.Weeks))
+		closed := make([]int, len(s.Weeks))
+		var totalOpened, totalClosed int
+
+		for i, w := range s.Weeks {
+			opened[i] = w.Opened
+			closed[i] = w.Closed
+			totalOpened += w.Opened
+			totalClosed += w.Closed
+		}
+
+		fmt.Printf("activity of the last %d weeks:\n", len(s.Weeks))
+		fmt.Printf("  opened %s %d\n", util.Green(stats.Sparkline(opened)), totalOpened)
+		fmt.Printf("  closed %s %d\n\n", util.Red(stats.Sparkline(closed)), totalClosed)
+	}
+
+	if s.ClosedWithTime > 0 {
+		fmt.Printf("median time to close: %s (over %d bugs)\n\n",
+			formatDuration(s.MedianTimeToClose), s.ClosedWithTime)
+	}
+
+	if s.TimeSpent > 0 {
+		fmt.Printf("time tracked: %s\n\n", formatTimeSpent(s.TimeSpent))
+	}
+
+	if len(s.TopCommenters) > 0 {
+		fmt.Println("top commenters:")
+		for _, c := range s.TopCommenters {
+			fmt.Printf("  %-25.25s %d\n", c.Name, c.Count)
+		}
+		fmt.Println()
+	}
+
+	if len(s.Labels) > 0 {
+		fmt.Println("labels:")
+		for _, l := range s.Labels {
+			fmt.Printf("  %-25.25s %d\n", l.Label, l.Count)
+		}
+		fmt.Println()
+	}
+
+	if len(s.Authors) > 0 {
+		fmt.Printf("  %-25s %6s %6s %8s\n", "author", "opened", "closed", "comments")
+		for _, a := range s.Authors {
+			fmt.Printf("  %-25.25s %6d %6d %8d\n", a.Name, a.Opened, a.Closed, a.Comments)
+		}
+		fmt.Println()
+	}
+
+	if s.Untimed > 0 {
+		fmt.Printf("note: %d bugs created without timestamp are excluded from the time-based metrics\n", s.Untimed)
+	}
+}
+
+// formatDuration format a duration for human consumption, like "3 days"
+func formatDuration(d time.Duration) string {
+	now := time.Now()
+	return strings.TrimSpace(humanize.RelTime(now, now.Add(d), "", ""))
+}
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Display statistics about the bugs of the repository",
+	RunE:  runStats,
+}
+
+func init() {
+	RootCmd.AddCommand(statsCmd)
+
+	statsCmd.Flags().IntVarP(&statsWeeks, "weeks", "w", 12,
+		"Number of weeks of activity to display",
+	)
+	statsCmd.Flags().StringVarP(&statsFormat, "format", "f", "text",
+		"Output format, text or json",
+	)
+	statsCmd.Flags().StringVar(&statsSince, "since", "",
+		"Only account for the events since this date (YYYY-MM-DD)",
+	)
+	statsCmd.Flags().StringVar(&statsUntil, "until", "",
+		"Only account for the events before this date (YYYY-MM-DD)",
+	)
+	statsCmd.Flags().BoolVar(&statsByAuthor, "author", false,
+		"Display the activity of each author",
+	)
+}
@@ -0,0 +1,46 @@
+package commands
+
+import (
+	"errors"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	statusId     bool
+	statusFormat string
+)
+
+func runStatus(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return errors.New("You must provide a single bug id")
+	}
+
+	b, err := resolveBugExit(args[0])
+	if err != nil {
+		return err
+	}
+
+	return printBugField(statusFormat, statusId, b.Id(), "status", b.Status().String())
+}
+
+var statusCmd = &cobra.Command{
+	Use:   "status [<option>...] <id>",
+	Short: "Display the status of a bug, open or closed, for scripts",
+	Long: `Display the status of a bug, open or closed, for scripts.
+
+The bug is only resolved by its id or a prefix of it. The exit code is 1 if
+no bug match and 2 if several bugs match.`,
+	RunE: runStatus,
+}
+
+func init() {
+	RootCmd.AddCommand(statusCmd)
+
+	statusCmd.Flags().BoolVar(&statusId, "id", false,
+		"Display the full id of the bug before the status, separated by a tab",
+	)
+	statusCmd.Flags().StringVarP(&statusFormat, "format", "f", "text",
+		"Output format, text or json",
+	)
+}
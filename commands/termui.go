@@ -1,12 +1,26 @@
 package commands
 
 import (
+	"os"
+
+	"github.com/MichaelMure/git-bug/cache"
 	"github.com/MichaelMure/git-bug/termui"
 	"github.com/spf13/cobra"
 )
 
+var (
+	termUIRaw      bool
+	termUIShowKeys bool
+)
+
 func runTermUI(cmd *cobra.Command, args []string) error {
-	return termui.Run(repo)
+	if termUIShowKeys {
+		return termui.ShowKeys(cache.NewRepoCache(repo), os.Stdout)
+	}
+
+	return termui.Run(cache.NewRepoCache(repo), termui.Options{
+		RawMarkdown: termUIRaw,
+	})
 }
 
 var termUICmd = &cobra.Command{
@@ -17,4 +31,11 @@ var termUICmd = &cobra.Command{
 
 func init() {
 	RootCmd.AddCommand(termUICmd)
+
+	termUICmd.Flags().BoolVar(&termUIRaw, "raw", false,
+		"Show the comments as written, without rendering their markdown",
+	)
+	termUICmd.Flags().BoolVar(&termUIShowKeys, "show-keys", false,
+		"Print the keybindings of each view, as configured with git-bug.tui.key.<action>, instead of launching the UI",
+	)
 }
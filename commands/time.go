@@ -0,0 +1,35 @@
+package commands
+
+import (
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// formatTimeSpent format a duration of work, like "1h30m"
+func formatTimeSpent(d time.Duration) string {
+	if d < time.Minute {
+		return d.String()
+	}
+
+	// "2h0m0s" --> "2h", "1h30m0s" --> "1h30m"
+	s := d.Round(time.Minute).String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+
+	return s
+}
+
+var timeCmd = &cobra.Command{
+	Use:   "time",
+	Short: "Record and display the time spent on the bugs",
+}
+
+func init() {
+	RootCmd.AddCommand(timeCmd)
+}
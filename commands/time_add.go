@@ -0,0 +1,59 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/spf13/cobra"
+)
+
+var timeAddMessage string
+
+func runTimeAdd(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return errors.New("You must provide a bug id")
+	}
+
+	if len(args) == 1 {
+		return errors.New("You must provide a duration, like 1h30m")
+	}
+
+	if len(args) > 2 {
+		return errors.New("Only one duration can be added at a time")
+	}
+
+	duration, err := time.ParseDuration(args[1])
+	if err != nil {
+		return fmt.Errorf("invalid duration %s, expected something like 1h30m", args[1])
+	}
+
+	backend := cache.NewRepoCache(repo)
+
+	b, err := resolveBug(backend, args[0])
+	if err != nil {
+		return err
+	}
+
+	err = b.AddTimeLog(duration, timeAddMessage)
+	if err != nil {
+		return err
+	}
+
+	return b.Commit()
+}
+
+var timeAddCmd = &cobra.Command{
+	Use:   "add [<option>...] <id> <duration>",
+	Short: "Record some time spent on a bug, like 1h30m",
+	RunE:  runTimeAdd,
+}
+
+func init() {
+	timeCmd.AddCommand(timeAddCmd)
+
+	timeAddCmd.Flags().StringVarP(&timeAddMessage, "message", "m", "",
+		"A note about the work done",
+	)
+}
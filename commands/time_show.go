@@ -0,0 +1,62 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/util"
+	"github.com/spf13/cobra"
+)
+
+func runTimeShow(cmd *cobra.Command, args []string) error {
+	if len(args) > 1 {
+		return errors.New("Only showing one bug at a time is supported")
+	}
+
+	if len(args) == 0 {
+		return errors.New("You must provide a bug id")
+	}
+
+	backend := cache.NewRepoCache(repo)
+
+	b, err := resolveBug(backend, args[0])
+	if err != nil {
+		return err
+	}
+
+	snap := b.Snapshot()
+
+	fmt.Printf("total: %s\n", util.Bold(formatTimeSpent(snap.TimeSpent())))
+
+	if len(snap.TimeLogs) == 0 {
+		return nil
+	}
+
+	fmt.Println()
+	for _, spent := range snap.TimeSpentByAuthor() {
+		fmt.Printf("  %-25.25s %s\n", spent.Author.Name, formatTimeSpent(spent.Duration))
+	}
+
+	fmt.Println()
+	for _, log := range snap.TimeLogs {
+		fmt.Printf("  %s %s %-8s %s\n",
+			log.Time().Format("2006-01-02"),
+			util.Magenta(fmt.Sprintf("%-20.20s", log.Author.Name)),
+			formatTimeSpent(log.Duration),
+			log.Note,
+		)
+	}
+
+	return nil
+}
+
+var timeShowCmd = &cobra.Command{
+	Use:   "show <id>",
+	Short: "Display the time spent on a bug, by author",
+	RunE:  runTimeShow,
+}
+
+func init() {
+	timeCmd.AddCommand(timeShowCmd)
+}
@@ -0,0 +1,46 @@
+package commands
+
+import (
+	"errors"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	titleId     bool
+	titleFormat string
+)
+
+func runTitle(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return errors.New("You must provide a single bug id")
+	}
+
+	b, err := resolveBugExit(args[0])
+	if err != nil {
+		return err
+	}
+
+	return printBugField(titleFormat, titleId, b.Id(), "title", b.Title())
+}
+
+var titleCmd = &cobra.Command{
+	Use:   "title [<option>...] <id>",
+	Short: "Display the title of a bug, for scripts",
+	Long: `Display the title of a bug, for scripts.
+
+The bug is only resolved by its id or a prefix of it. The exit code is 1 if
+no bug match and 2 if several bugs match.`,
+	RunE: runTitle,
+}
+
+func init() {
+	RootCmd.AddCommand(titleCmd)
+
+	titleCmd.Flags().BoolVar(&titleId, "id", false,
+		"Display the full id of the bug before the title, separated by a tab",
+	)
+	titleCmd.Flags().StringVarP(&titleFormat, "format", "f", "text",
+		"Output format, text or json",
+	)
+}
@@ -0,0 +1,57 @@
+package commands
+
+import (
+	"io"
+	"os"
+
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+// traceEnvVar enable the tracing of the git calls when set to 1, like
+// --verbose
+const traceEnvVar = "GIT_BUG_TRACE"
+
+// traceFileEnvVar log the traced git calls to a file instead of stderr
+const traceFileEnvVar = "GIT_BUG_TRACE_FILE"
+
+// the repository tracing its calls, if enabled
+var rootTrace *repository.TracedRepo
+
+// the file the calls are traced to, if any
+var rootTraceFile *os.File
+
+// traceRepo wrap the repository to trace its calls if enabled. The repository
+// is returned as is otherwise.
+func traceRepo(r repository.Repo) (repository.Repo, error) {
+	if !rootVerbose && os.Getenv(traceEnvVar) != "1" {
+		return r, nil
+	}
+
+	var out io.Writer = os.Stderr
+
+	if path := os.Getenv(traceFileEnvVar); path != "" {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, err
+		}
+		rootTraceFile = file
+		out = file
+	}
+
+	rootTrace = repository.NewTracedRepo(r, out)
+
+	return rootTrace, nil
+}
+
+// closeTrace log the summary of the traced calls, if any
+func closeTrace() {
+	if rootTrace == nil {
+		return
+	}
+
+	rootTrace.WriteSummary()
+
+	if rootTraceFile != nil {
+		rootTraceFile.Close()
+	}
+}
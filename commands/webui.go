@@ -5,22 +5,177 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
-	"log"
 	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/cache"
 	"github.com/MichaelMure/git-bug/graphql"
+	"github.com/MichaelMure/git-bug/migration"
 	"github.com/MichaelMure/git-bug/repository"
 	"github.com/MichaelMure/git-bug/util"
 	"github.com/MichaelMure/git-bug/webui"
 	"github.com/gorilla/mux"
+	"github.com/mattn/go-isatty"
 	"github.com/phayes/freeport"
 	"github.com/skratchdot/open-golang/open"
 	"github.com/spf13/cobra"
 	"github.com/vektah/gqlgen/handler"
 )
 
-var port int
+var (
+	port          int
+	webUIRepos    []string
+	webUIReposDir string
+	webUIJobs     int
+)
+
+// the minimum delay between two updates of the progress of the loading
+const progressInterval = 100 * time.Millisecond
+
+// multiRepo tell if the web UI serve the given repositories instead of the
+// current one
+func multiRepo() bool {
+	return len(webUIRepos) > 0 || webUIReposDir != ""
+}
+
+// loadWebUIRepo load the current repository, unless other ones are given.
+// They don't need to be run from a git repository.
+func loadWebUIRepo(cmd *cobra.Command, args []string) error {
+	if !multiRepo() {
+		return loadRepo(cmd, args)
+	}
+
+	// each repository use the namespace configured in it otherwise, see
+	// openWebUIRepo
+	if rootNamespace != "" {
+		return bug.ValidateNamespace(rootNamespace)
+	}
+
+	return nil
+}
+
+// webUICache register the repositories to serve, the given ones being
+// opened only on first access
+func webUICache() (cache.RootCache, error) {
+	c := cache.NewCache()
+
+	if !multiRepo() {
+		c.RegisterDefaultRepository(repo)
+		return c, nil
+	}
+
+	paths, err := webUIPaths()
+	if err != nil {
+		return c, err
+	}
+
+	for _, path := range paths {
+		ref := repoRef(path)
+
+		for _, existing := range c.Repositories() {
+			if existing == ref {
+				return c, fmt.Errorf("several repositories are named %s", ref)
+			}
+		}
+
+		c.RegisterLazyRepository(ref, openWebUIRepo(path))
+	}
+
+	return c, nil
+}
+
+// webUIPaths return the paths of the repositories given to serve
+func webUIPaths() ([]string, error) {
+	paths := append([]string{}, webUIRepos...)
+
+	if webUIReposDir != "" {
+		found, err := findRepos(webUIReposDir)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, found...)
+	}
+
+	return paths, nil
+}
+
+// repoRef name a repository after its directory, like git clone do
+func repoRef(path string) string {
+	return strings.TrimSuffix(filepath.Base(filepath.Clean(path)), ".git")
+}
+
+// findRepos list the git repositories, bare or not, directly in a directory
+func findRepos(dir string) ([]string, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var repos []string
+
+	for _, info := range infos {
+		if !info.IsDir() {
+			continue
+		}
+
+		path := filepath.Join(dir, info.Name())
+
+		isRepo := fileExists(filepath.Join(path, ".git")) ||
+			fileExists(filepath.Join(path, "HEAD")) && fileExists(filepath.Join(path, "objects"))
+
+		if isRepo {
+			repos = append(repos, path)
+		}
+	}
+
+	return repos, nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// openWebUIRepo return the function opening a served repository, with the
+// namespace given on the command line or else the one configured in it
+func openWebUIRepo(path string) func() (repository.Repo, error) {
+	return func() (repository.Repo, error) {
+		r, err := repository.NewGitRepo(path, bug.Witnesser)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", path, err)
+		}
+
+		pending, err := migration.Pending(r)
+		if err != nil {
+			return nil, err
+		}
+		if len(pending) > 0 {
+			return nil, fmt.Errorf("%s must be migrated first, run \"%s migrate\" in it", path, rootCommandName)
+		}
+
+		ns := rootNamespace
+		if ns == "" {
+			ns, err = bug.ConfiguredNamespace(r)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		namespaced, err := bug.WithNamespace(r, ns)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", path, err)
+		}
+
+		return namespaced, nil
+	}
+}
 
 func runWebUI(cmd *cobra.Command, args []string) error {
 	if port == 0 {
@@ -31,6 +186,17 @@ func runWebUI(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	repos, err := webUICache()
+	if err != nil {
+		return err
+	}
+
+	if !multiRepo() {
+		if err := loadWebUIBugs(&repos); err != nil {
+			return err
+		}
+	}
+
 	addr := fmt.Sprintf("127.0.0.1:%d", port)
 	webUiAddr := fmt.Sprintf("http://%s", addr)
 
@@ -42,25 +208,182 @@ func runWebUI(cmd *cobra.Command, args []string) error {
 
 	// Routes
 	router.Path("/playground").Handler(handler.Playground("git-bug", "/graphql"))
-	router.Path("/graphql").Handler(graphql.NewHandler(repo))
-	router.Path("/gitfile/{hash}").Handler(newGitFileHandler(repo))
-	router.Path("/upload").Methods("POST").Handler(newGitUploadFileHandler(repo))
-	router.PathPrefix("/").Handler(http.FileServer(webui.WebUIAssets))
+	router.Path("/graphql").Handler(graphql.NewCacheHandler(repos))
+	router.Path("/gitfile/{hash}").Handler(newGitFileHandler(&repos))
+	router.Path("/upload").Methods("POST").Handler(newGitUploadFileHandler(&repos))
+	router.PathPrefix("/").Handler(newSpaHandler(webui.WebUIAssets))
+
+	// let the other tools link to this web UI, like the termui
+	served, err := servedRepos()
+	if err != nil {
+		return err
+	}
+
+	removePorts := func() {
+		for _, r := range served {
+			webui.RemovePort(r)
+		}
+	}
+
+	for ref, r := range served {
+		if err := webui.WritePort(r, port, ref); err != nil {
+			removePorts()
+			return err
+		}
+	}
+	defer removePorts()
+
+	// once the mutations in progress are committed
+	util.OnInterrupt(func() {
+		removePorts()
+		closeTrace()
+	})
 
 	open.Run(webUiAddr)
 
-	log.Fatal(http.ListenAndServe(addr, router))
+	return http.ListenAndServe(addr, router)
+}
+
+// servedRepos return the repositories served by the web UI, by the name of
+// the repository in the URLs, empty for the current one. The ones that can't
+// be opened are left out, the error being reported on access.
+func servedRepos() (map[string]repository.Repo, error) {
+	if !multiRepo() {
+		return map[string]repository.Repo{"": repo}, nil
+	}
+
+	paths, err := webUIPaths()
+	if err != nil {
+		return nil, err
+	}
+
+	served := make(map[string]repository.Repo, len(paths))
+
+	for _, path := range paths {
+		r, err := repository.NewGitRepo(path, bug.Witnesser)
+		if err == nil {
+			served[repoRef(path)] = r
+		}
+	}
+
+	return served, nil
+}
+
+// spaHandler serve the assets of the web UI, and its index for the other
+// paths, like /bug/<id>, as they are routed by the web UI itself
+type spaHandler struct {
+	assets http.FileSystem
+	files  http.Handler
+}
+
+func newSpaHandler(assets http.FileSystem) http.Handler {
+	return &spaHandler{
+		assets: assets,
+		files:  http.FileServer(assets),
+	}
+}
+
+func (sh *spaHandler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	name := path.Clean("/" + r.URL.Path)
+
+	file, err := sh.assets.Open(name)
+	if err == nil {
+		file.Close()
+	}
+
+	// a missing asset is still not found
+	if !os.IsNotExist(err) || path.Ext(name) != "" {
+		sh.files.ServeHTTP(rw, r)
+		return
+	}
+
+	index := r.WithContext(r.Context())
+	index.URL = &url.URL{Path: "/"}
+	sh.files.ServeHTTP(rw, index)
+}
+
+// loadWebUIBugs read every bug of the repository before serving it, so that
+// the first page doesn't wait for them. The bugs that can't be read are
+// reported and left out.
+func loadWebUIBugs(repos cache.Cacher) error {
+	backend, err := repos.DefaultRepo()
+	if err != nil {
+		return err
+	}
+
+	result, err := backend.Rebuild(cache.RebuildOptions{
+		Jobs:     webUIJobs,
+		Progress: printProgress(os.Stderr),
+	})
+	if err != nil {
+		return err
+	}
+
+	ids := make([]string, 0, len(result.Failed))
+	for id := range result.Failed {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", id, result.Failed[id])
+	}
+
+	fmt.Printf("%d bugs loaded\n", result.Loaded)
 
 	return nil
 }
 
+// printProgress return a function printing the progress of the loading of
+// the bugs on a single line of a terminal, at most every progressInterval
+func printProgress(out *os.File) func(cache.RebuildProgress) {
+	if !isatty.IsTerminal(out.Fd()) {
+		return nil
+	}
+
+	var last time.Time
+
+	return func(p cache.RebuildProgress) {
+		finished := p.Done == p.Total
+		if !finished && time.Since(last) < progressInterval {
+			return
+		}
+		last = time.Now()
+
+		fmt.Fprintf(out, "\rLoading the bugs: %d/%d, %s left ", p.Done, p.Total, p.ETA().Round(time.Second))
+
+		if finished {
+			fmt.Fprint(out, "\r\033[K")
+		}
+	}
+}
+
+// requestRepo return the repository selected by the repo parameter of the
+// request, or the only one served
+func requestRepo(repos cache.Cacher, r *http.Request) (repository.Repo, error) {
+	var repo cache.RepoCacher
+	var err error
+
+	if ref, ok := r.URL.Query()["repo"]; ok {
+		repo, err = repos.ResolveRepo(ref[0])
+	} else {
+		repo, err = repos.DefaultRepo()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return repo.Repository(), nil
+}
+
 type gitFileHandler struct {
-	repo repository.Repo
+	repos cache.Cacher
 }
 
-func newGitFileHandler(repo repository.Repo) http.Handler {
+func newGitFileHandler(repos cache.Cacher) http.Handler {
 	return &gitFileHandler{
-		repo: repo,
+		repos: repos,
 	}
 }
 
@@ -76,7 +399,13 @@ func (gfh *gitFileHandler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
 	// This can be a problem for big files. There might be a way around
 	// that by implementing a io.ReadSeeker that would read and discard
 	// data when a seek is called.
-	data, err := gfh.repo.ReadData(util.Hash(hash))
+	repo, err := requestRepo(gfh.repos, r)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	data, err := repo.ReadData(util.Hash(hash))
 	if err != nil {
 		http.Error(rw, err.Error(), http.StatusInternalServerError)
 		return
@@ -86,16 +415,22 @@ func (gfh *gitFileHandler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
 }
 
 type gitUploadFileHandler struct {
-	repo repository.Repo
+	repos cache.Cacher
 }
 
-func newGitUploadFileHandler(repo repository.Repo) http.Handler {
+func newGitUploadFileHandler(repos cache.Cacher) http.Handler {
 	return &gitUploadFileHandler{
-		repo: repo,
+		repos: repos,
 	}
 }
 
 func (gufh *gitUploadFileHandler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	repo, err := requestRepo(gufh.repos, r)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	// 100MB (github limit)
 	var maxUploadSize int64 = 100 * 1000 * 1000
 	r.Body = http.MaxBytesReader(rw, r.Body, maxUploadSize)
@@ -123,7 +458,7 @@ func (gufh *gitUploadFileHandler) ServeHTTP(rw http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	hash, err := gufh.repo.StoreData(fileBytes)
+	hash, err := repo.StoreData(fileBytes)
 	if err != nil {
 		http.Error(rw, err.Error(), http.StatusInternalServerError)
 		return
@@ -148,10 +483,30 @@ func (gufh *gitUploadFileHandler) ServeHTTP(rw http.ResponseWriter, r *http.Requ
 var webUICmd = &cobra.Command{
 	Use:   "webui",
 	Short: "Launch the web UI",
-	RunE:  runWebUI,
+	Long: `Launch the web UI.
+
+By default, the bugs of the current repository are served. With --repo or
+--repos-dir, several repositories are served instead, each one opened on
+first access. They are named after their directory, without the .git
+suffix, and selected in the web UI or with the repository query of the
+GraphQL API.
+
+The bugs of the current repository are all read before serving them, --jobs
+at a time. A bug that can't be read is reported and left out.`,
+	PersistentPreRunE: loadWebUIRepo,
+	RunE:              runWebUI,
 }
 
 func init() {
 	RootCmd.AddCommand(webUICmd)
 	webUICmd.Flags().IntVarP(&port, "port", "p", 0, "Port to listen to")
+	webUICmd.Flags().StringArrayVar(&webUIRepos, "repo", nil,
+		"Serve the git repository at the given path, bare or not. Can be repeated",
+	)
+	webUICmd.Flags().StringVar(&webUIReposDir, "repos-dir", "",
+		"Serve every git repository found directly in the given directory",
+	)
+	webUICmd.Flags().IntVarP(&webUIJobs, "jobs", "j", 0,
+		"Number of bugs read concurrently when loading them, git-bug.workers or the number of CPUs by default",
+	)
 }
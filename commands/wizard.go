@@ -0,0 +1,159 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/MichaelMure/git-bug/bridge/phabricator"
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/input"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+// the preference marking that the first-run wizard was offered already
+const wizardDonePreference = "setup-done"
+
+const wizardIntro = `Welcome to git-bug! This repository has no bug yet, let's set it up.
+This is only asked once, run "git bug --help" to see every command.
+
+`
+
+const wizardCommands = `
+The basic commands:
+  git bug new            create a bug
+  git bug ls             list the bugs, "git bug ls status:open label:ui"
+  git bug show <id>      display a bug, by id or title
+  git bug comment <id>   add a comment to a bug
+  git bug close <id>     close a bug
+  git bug push / pull    share the bugs with a git remote
+  git bug termui         browse the bugs in the terminal
+
+`
+
+// shouldRunWizard tell if the first-run wizard should be run instead of the
+// help: only in a repository without bug, on a terminal, and only once
+func shouldRunWizard() (bool, error) {
+	if rootNoWizard || !input.IsInteractive() {
+		return false, nil
+	}
+
+	_, err := repository.GetUserPreference(repo, wizardDonePreference)
+	if err == nil {
+		return false, nil
+	}
+	if err != repository.ErrNoConfigEntry {
+		return false, err
+	}
+
+	ids, err := bug.ListLocalIds(repo)
+	if err != nil {
+		return false, err
+	}
+
+	return len(ids) == 0, nil
+}
+
+// runWizard guide a newcomer through the setup of the repository: the
+// identity, a bridge for the remote, and a first bug
+func runWizard() error {
+	// marked first, so that an interrupted wizard is not run again
+	if err := repository.SetUserPreference(repo, wizardDonePreference, "true"); err != nil {
+		return err
+	}
+
+	fmt.Print(wizardIntro)
+
+	if err := wizardIdentity(); err != nil {
+		return err
+	}
+
+	if err := wizardBridge(); err != nil {
+		return err
+	}
+
+	fmt.Print(wizardCommands)
+
+	ok, err := input.Confirm("Create a first bug now?")
+	if err != nil || !ok {
+		return err
+	}
+
+	title, err := input.Prompt("Title:", "")
+	if err != nil {
+		return err
+	}
+
+	return createBug(title, "")
+}
+
+// wizardIdentity confirm the identity the bugs are authored with, or collect
+// one stored in the git config of the repository
+func wizardIdentity() error {
+	identity, err := bug.DefaultIdentity(repo)
+	if err == nil {
+		question := fmt.Sprintf("Your bugs and comments will be authored by %s <%s>. Is that right?",
+			identity.Name, identity.Email)
+
+		ok, err := input.Confirm(question)
+		if err != nil || ok {
+			return err
+		}
+	}
+
+	name, err := input.Prompt("Your name:", identity.Name)
+	if err != nil {
+		return err
+	}
+
+	email, err := input.Prompt("Your email:", identity.Email)
+	if err != nil {
+		return err
+	}
+
+	if name == "" || email == "" {
+		return errors.New("A name and an email are required to author the bugs")
+	}
+
+	if err := repo.SetConfig("user.name", name); err != nil {
+		return err
+	}
+
+	return repo.SetConfig("user.email", email)
+}
+
+// wizardBridge offer to configure the bridge matching the remote of the
+// bugs, if any
+func wizardBridge() error {
+	remote, err := bug.ConfiguredRemote(repo)
+	if err != nil {
+		return err
+	}
+
+	remoteUrl, err := bug.RemoteUrl(repo, remote)
+	if err != nil {
+		// no remote yet
+		return nil
+	}
+
+	instance, ok := phabricator.DetectInstance(remoteUrl)
+	if !ok {
+		return nil
+	}
+
+	question := fmt.Sprintf("The remote %s is hosted by the Phabricator instance %s. Configure the import of its tasks?",
+		remote, instance)
+
+	ok, err = input.Confirm(question)
+	if err != nil || !ok {
+		return err
+	}
+
+	if err := phabricator.SetUrl(repo, instance); err != nil {
+		return err
+	}
+
+	fmt.Printf("Import the tasks with \"git bug bridge phabricator\", with a Conduit API token in %s.\n",
+		phabricatorTokenEnvVar)
+
+	return nil
+}
@@ -31,14 +31,37 @@ func NewExecutableSchema(resolvers ResolverRoot) graphql.ExecutableSchema {
 
 type Resolvers interface {
 	AddCommentOperation_date(ctx context.Context, obj *operations.AddCommentOperation) (time.Time, error)
+	AddCommentOperation_message(ctx context.Context, obj *operations.AddCommentOperation) (string, error)
+	AddCommentOperation_messageHtml(ctx context.Context, obj *operations.AddCommentOperation) (string, error)
+
+	AddTimeLogOperation_date(ctx context.Context, obj *operations.AddTimeLogOperation) (time.Time, error)
+	AddTimeLogOperation_duration(ctx context.Context, obj *operations.AddTimeLogOperation) (int, error)
+
+	ArchiveCommentsOperation_date(ctx context.Context, obj *operations.ArchiveCommentsOperation) (time.Time, error)
+	ArchiveCommentsOperation_before(ctx context.Context, obj *operations.ArchiveCommentsOperation) (time.Time, error)
 
 	Bug_status(ctx context.Context, obj *bug.Snapshot) (models.Status, error)
+	Bug_priority(ctx context.Context, obj *bug.Snapshot) (models.Priority, error)
+
+	Bug_customFields(ctx context.Context, obj *bug.Snapshot) ([]models.CustomField, error)
+
+	Bug_timeSpent(ctx context.Context, obj *bug.Snapshot) (int, error)
 
 	Bug_comments(ctx context.Context, obj *bug.Snapshot, after *string, before *string, first *int, last *int) (models.CommentConnection, error)
 	Bug_operations(ctx context.Context, obj *bug.Snapshot, after *string, before *string, first *int, last *int) (models.OperationConnection, error)
 
+	ChecklistItem_state(ctx context.Context, obj *bug.ChecklistItem) (models.ChecklistState, error)
+
+	Comment_message(ctx context.Context, obj *bug.Comment) (string, error)
+	Comment_messageHtml(ctx context.Context, obj *bug.Comment) (string, error)
+
 	CreateOperation_date(ctx context.Context, obj *operations.CreateOperation) (time.Time, error)
 
+	CreateOperation_message(ctx context.Context, obj *operations.CreateOperation) (string, error)
+	CreateOperation_messageHtml(ctx context.Context, obj *operations.CreateOperation) (string, error)
+
+	DeleteCommentOperation_date(ctx context.Context, obj *operations.DeleteCommentOperation) (time.Time, error)
+
 	LabelChangeOperation_date(ctx context.Context, obj *operations.LabelChangeOperation) (time.Time, error)
 
 	Mutation_newBug(ctx context.Context, repoRef *string, title string, message string, files []util.Hash) (bug.Snapshot, error)
@@ -51,38 +74,99 @@ type Resolvers interface {
 
 	Query_defaultRepository(ctx context.Context) (*models.Repository, error)
 	Query_repository(ctx context.Context, id string) (*models.Repository, error)
+	Query_repositories(ctx context.Context) ([]models.Repository, error)
 
-	Repository_allBugs(ctx context.Context, obj *models.Repository, after *string, before *string, first *int, last *int) (models.BugConnection, error)
+	Relation_kind(ctx context.Context, obj *bug.Relation) (models.RelationKind, error)
+
+	RemapAuthorsOperation_date(ctx context.Context, obj *operations.RemapAuthorsOperation) (time.Time, error)
+
+	Repository_allBugs(ctx context.Context, obj *models.Repository, query *string, after *string, before *string, first *int, last *int) (models.BugConnection, error)
 	Repository_bug(ctx context.Context, obj *models.Repository, prefix string) (*bug.Snapshot, error)
+	Repository_labels(ctx context.Context, obj *models.Repository) ([]models.LabelInfo, error)
+
+	SetChecklistOperation_date(ctx context.Context, obj *operations.SetChecklistOperation) (time.Time, error)
+
+	SetCustomFieldOperation_date(ctx context.Context, obj *operations.SetCustomFieldOperation) (time.Time, error)
+
+	SetPriorityOperation_date(ctx context.Context, obj *operations.SetPriorityOperation) (time.Time, error)
+	SetPriorityOperation_priority(ctx context.Context, obj *operations.SetPriorityOperation) (models.Priority, error)
+
+	SetRelationOperation_date(ctx context.Context, obj *operations.SetRelationOperation) (time.Time, error)
+	SetRelationOperation_kind(ctx context.Context, obj *operations.SetRelationOperation) (models.RelationKind, error)
 
 	SetStatusOperation_date(ctx context.Context, obj *operations.SetStatusOperation) (time.Time, error)
 	SetStatusOperation_status(ctx context.Context, obj *operations.SetStatusOperation) (models.Status, error)
 
 	SetTitleOperation_date(ctx context.Context, obj *operations.SetTitleOperation) (time.Time, error)
+
+	SubscribeOperation_date(ctx context.Context, obj *operations.SubscribeOperation) (time.Time, error)
+
+	UnsubscribeOperation_date(ctx context.Context, obj *operations.UnsubscribeOperation) (time.Time, error)
 }
 
 type ResolverRoot interface {
 	AddCommentOperation() AddCommentOperationResolver
+	AddTimeLogOperation() AddTimeLogOperationResolver
+	ArchiveCommentsOperation() ArchiveCommentsOperationResolver
 	Bug() BugResolver
+	ChecklistItem() ChecklistItemResolver
+	Comment() CommentResolver
 	CreateOperation() CreateOperationResolver
+	DeleteCommentOperation() DeleteCommentOperationResolver
 	LabelChangeOperation() LabelChangeOperationResolver
 	Mutation() MutationResolver
 	Query() QueryResolver
+	Relation() RelationResolver
+	RemapAuthorsOperation() RemapAuthorsOperationResolver
 	Repository() RepositoryResolver
+	SetChecklistOperation() SetChecklistOperationResolver
+	SetCustomFieldOperation() SetCustomFieldOperationResolver
+	SetPriorityOperation() SetPriorityOperationResolver
+	SetRelationOperation() SetRelationOperationResolver
 	SetStatusOperation() SetStatusOperationResolver
 	SetTitleOperation() SetTitleOperationResolver
+	SubscribeOperation() SubscribeOperationResolver
+	UnsubscribeOperation() UnsubscribeOperationResolver
 }
 type AddCommentOperationResolver interface {
 	Date(ctx context.Context, obj *operations.AddCommentOperation) (time.Time, error)
+	Message(ctx context.Context, obj *operations.AddCommentOperation) (string, error)
+	MessageHtml(ctx context.Context, obj *operations.AddCommentOperation) (string, error)
+}
+type AddTimeLogOperationResolver interface {
+	Date(ctx context.Context, obj *operations.AddTimeLogOperation) (time.Time, error)
+	Duration(ctx context.Context, obj *operations.AddTimeLogOperation) (int, error)
+}
+type ArchiveCommentsOperationResolver interface {
+	Date(ctx context.Context, obj *operations.ArchiveCommentsOperation) (time.Time, error)
+	Before(ctx context.Context, obj *operations.ArchiveCommentsOperation) (time.Time, error)
 }
 type BugResolver interface {
 	Status(ctx context.Context, obj *bug.Snapshot) (models.Status, error)
+	Priority(ctx context.Context, obj *bug.Snapshot) (models.Priority, error)
+
+	CustomFields(ctx context.Context, obj *bug.Snapshot) ([]models.CustomField, error)
+
+	TimeSpent(ctx context.Context, obj *bug.Snapshot) (int, error)
 
 	Comments(ctx context.Context, obj *bug.Snapshot, after *string, before *string, first *int, last *int) (models.CommentConnection, error)
 	Operations(ctx context.Context, obj *bug.Snapshot, after *string, before *string, first *int, last *int) (models.OperationConnection, error)
 }
+type ChecklistItemResolver interface {
+	State(ctx context.Context, obj *bug.ChecklistItem) (models.ChecklistState, error)
+}
+type CommentResolver interface {
+	Message(ctx context.Context, obj *bug.Comment) (string, error)
+	MessageHtml(ctx context.Context, obj *bug.Comment) (string, error)
+}
 type CreateOperationResolver interface {
 	Date(ctx context.Context, obj *operations.CreateOperation) (time.Time, error)
+
+	Message(ctx context.Context, obj *operations.CreateOperation) (string, error)
+	MessageHtml(ctx context.Context, obj *operations.CreateOperation) (string, error)
+}
+type DeleteCommentOperationResolver interface {
+	Date(ctx context.Context, obj *operations.DeleteCommentOperation) (time.Time, error)
 }
 type LabelChangeOperationResolver interface {
 	Date(ctx context.Context, obj *operations.LabelChangeOperation) (time.Time, error)
@@ -99,10 +183,32 @@ type MutationResolver interface {
 type QueryResolver interface {
 	DefaultRepository(ctx context.Context) (*models.Repository, error)
 	Repository(ctx context.Context, id string) (*models.Repository, error)
+	Repositories(ctx context.Context) ([]models.Repository, error)
+}
+type RelationResolver interface {
+	Kind(ctx context.Context, obj *bug.Relation) (models.RelationKind, error)
+}
+type RemapAuthorsOperationResolver interface {
+	Date(ctx context.Context, obj *operations.RemapAuthorsOperation) (time.Time, error)
 }
 type RepositoryResolver interface {
-	AllBugs(ctx context.Context, obj *models.Repository, after *string, before *string, first *int, last *int) (models.BugConnection, error)
+	AllBugs(ctx context.Context, obj *models.Repository, query *string, after *string, before *string, first *int, last *int) (models.BugConnection, error)
 	Bug(ctx context.Context, obj *models.Repository, prefix string) (*bug.Snapshot, error)
+	Labels(ctx context.Context, obj *models.Repository) ([]models.LabelInfo, error)
+}
+type SetChecklistOperationResolver interface {
+	Date(ctx context.Context, obj *operations.SetChecklistOperation) (time.Time, error)
+}
+type SetCustomFieldOperationResolver interface {
+	Date(ctx context.Context, obj *operations.SetCustomFieldOperation) (time.Time, error)
+}
+type SetPriorityOperationResolver interface {
+	Date(ctx context.Context, obj *operations.SetPriorityOperation) (time.Time, error)
+	Priority(ctx context.Context, obj *operations.SetPriorityOperation) (models.Priority, error)
+}
+type SetRelationOperationResolver interface {
+	Date(ctx context.Context, obj *operations.SetRelationOperation) (time.Time, error)
+	Kind(ctx context.Context, obj *operations.SetRelationOperation) (models.RelationKind, error)
 }
 type SetStatusOperationResolver interface {
 	Date(ctx context.Context, obj *operations.SetStatusOperation) (time.Time, error)
@@ -111,6 +217,12 @@ type SetStatusOperationResolver interface {
 type SetTitleOperationResolver interface {
 	Date(ctx context.Context, obj *operations.SetTitleOperation) (time.Time, error)
 }
+type SubscribeOperationResolver interface {
+	Date(ctx context.Context, obj *operations.SubscribeOperation) (time.Time, error)
+}
+type UnsubscribeOperationResolver interface {
+	Date(ctx context.Context, obj *operations.UnsubscribeOperation) (time.Time, error)
+}
 
 type shortMapper struct {
 	r ResolverRoot
@@ -120,10 +232,46 @@ func (s shortMapper) AddCommentOperation_date(ctx context.Context, obj *operatio
 	return s.r.AddCommentOperation().Date(ctx, obj)
 }
 
+func (s shortMapper) AddCommentOperation_message(ctx context.Context, obj *operations.AddCommentOperation) (string, error) {
+	return s.r.AddCommentOperation().Message(ctx, obj)
+}
+
+func (s shortMapper) AddCommentOperation_messageHtml(ctx context.Context, obj *operations.AddCommentOperation) (string, error) {
+	return s.r.AddCommentOperation().MessageHtml(ctx, obj)
+}
+
+func (s shortMapper) AddTimeLogOperation_date(ctx context.Context, obj *operations.AddTimeLogOperation) (time.Time, error) {
+	return s.r.AddTimeLogOperation().Date(ctx, obj)
+}
+
+func (s shortMapper) AddTimeLogOperation_duration(ctx context.Context, obj *operations.AddTimeLogOperation) (int, error) {
+	return s.r.AddTimeLogOperation().Duration(ctx, obj)
+}
+
+func (s shortMapper) ArchiveCommentsOperation_date(ctx context.Context, obj *operations.ArchiveCommentsOperation) (time.Time, error) {
+	return s.r.ArchiveCommentsOperation().Date(ctx, obj)
+}
+
+func (s shortMapper) ArchiveCommentsOperation_before(ctx context.Context, obj *operations.ArchiveCommentsOperation) (time.Time, error) {
+	return s.r.ArchiveCommentsOperation().Before(ctx, obj)
+}
+
 func (s shortMapper) Bug_status(ctx context.Context, obj *bug.Snapshot) (models.Status, error) {
 	return s.r.Bug().Status(ctx, obj)
 }
 
+func (s shortMapper) Bug_priority(ctx context.Context, obj *bug.Snapshot) (models.Priority, error) {
+	return s.r.Bug().Priority(ctx, obj)
+}
+
+func (s shortMapper) Bug_customFields(ctx context.Context, obj *bug.Snapshot) ([]models.CustomField, error) {
+	return s.r.Bug().CustomFields(ctx, obj)
+}
+
+func (s shortMapper) Bug_timeSpent(ctx context.Context, obj *bug.Snapshot) (int, error) {
+	return s.r.Bug().TimeSpent(ctx, obj)
+}
+
 func (s shortMapper) Bug_comments(ctx context.Context, obj *bug.Snapshot, after *string, before *string, first *int, last *int) (models.CommentConnection, error) {
 	return s.r.Bug().Comments(ctx, obj, after, before, first, last)
 }
@@ -132,10 +280,34 @@ func (s shortMapper) Bug_operations(ctx context.Context, obj *bug.Snapshot, afte
 	return s.r.Bug().Operations(ctx, obj, after, before, first, last)
 }
 
+func (s shortMapper) ChecklistItem_state(ctx context.Context, obj *bug.ChecklistItem) (models.ChecklistState, error) {
+	return s.r.ChecklistItem().State(ctx, obj)
+}
+
+func (s shortMapper) Comment_message(ctx context.Context, obj *bug.Comment) (string, error) {
+	return s.r.Comment().Message(ctx, obj)
+}
+
+func (s shortMapper) Comment_messageHtml(ctx context.Context, obj *bug.Comment) (string, error) {
+	return s.r.Comment().MessageHtml(ctx, obj)
+}
+
 func (s shortMapper) CreateOperation_date(ctx context.Context, obj *operations.CreateOperation) (time.Time, error) {
 	return s.r.CreateOperation().Date(ctx, obj)
 }
 
+func (s shortMapper) CreateOperation_message(ctx context.Context, obj *operations.CreateOperation) (string, error) {
+	return s.r.CreateOperation().Message(ctx, obj)
+}
+
+func (s shortMapper) CreateOperation_messageHtml(ctx context.Context, obj *operations.CreateOperation) (string, error) {
+	return s.r.CreateOperation().MessageHtml(ctx, obj)
+}
+
+func (s shortMapper) DeleteCommentOperation_date(ctx context.Context, obj *operations.DeleteCommentOperation) (time.Time, error) {
+	return s.r.DeleteCommentOperation().Date(ctx, obj)
+}
+
 func (s shortMapper) LabelChangeOperation_date(ctx context.Context, obj *operations.LabelChangeOperation) (time.Time, error) {
 	return s.r.LabelChangeOperation().Date(ctx, obj)
 }
@@ -176,14 +348,54 @@ func (s shortMapper) Query_repository(ctx context.Context, id string) (*models.R
 	return s.r.Query().Repository(ctx, id)
 }
 
-func (s shortMapper) Repository_allBugs(ctx context.Context, obj *models.Repository, after *string, before *string, first *int, last *int) (models.BugConnection, error) {
-	return s.r.Repository().AllBugs(ctx, obj, after, before, first, last)
+func (s shortMapper) Query_repositories(ctx context.Context) ([]models.Repository, error) {
+	return s.r.Query().Repositories(ctx)
+}
+
+func (s shortMapper) Relation_kind(ctx context.Context, obj *bug.Relation) (models.RelationKind, error) {
+	return s.r.Relation().Kind(ctx, obj)
+}
+
+func (s shortMapper) RemapAuthorsOperation_date(ctx context.Context, obj *operations.RemapAuthorsOperation) (time.Time, error) {
+	return s.r.RemapAuthorsOperation().Date(ctx, obj)
+}
+
+func (s shortMapper) Repository_allBugs(ctx context.Context, obj *models.Repository, query *string, after *string, before *string, first *int, last *int) (models.BugConnection, error) {
+	return s.r.Repository().AllBugs(ctx, obj, query, after, before, first, last)
 }
 
 func (s shortMapper) Repository_bug(ctx context.Context, obj *models.Repository, prefix string) (*bug.Snapshot, error) {
 	return s.r.Repository().Bug(ctx, obj, prefix)
 }
 
+func (s shortMapper) Repository_labels(ctx context.Context, obj *models.Repository) ([]models.LabelInfo, error) {
+	return s.r.Repository().Labels(ctx, obj)
+}
+
+func (s shortMapper) SetChecklistOperation_date(ctx context.Context, obj *operations.SetChecklistOperation) (time.Time, error) {
+	return s.r.SetChecklistOperation().Date(ctx, obj)
+}
+
+func (s shortMapper) SetCustomFieldOperation_date(ctx context.Context, obj *operations.SetCustomFieldOperation) (time.Time, error) {
+	return s.r.SetCustomFieldOperation().Date(ctx, obj)
+}
+
+func (s shortMapper) SetPriorityOperation_date(ctx context.Context, obj *operations.SetPriorityOperation) (time.Time, error) {
+	return s.r.SetPriorityOperation().Date(ctx, obj)
+}
+
+func (s shortMapper) SetPriorityOperation_priority(ctx context.Context, obj *operations.SetPriorityOperation) (models.Priority, error) {
+	return s.r.SetPriorityOperation().Priority(ctx, obj)
+}
+
+func (s shortMapper) SetRelationOperation_date(ctx context.Context, obj *operations.SetRelationOperation) (time.Time, error) {
+	return s.r.SetRelationOperation().Date(ctx, obj)
+}
+
+func (s shortMapper) SetRelationOperation_kind(ctx context.Context, obj *operations.SetRelationOperation) (models.RelationKind, error) {
+	return s.r.SetRelationOperation().Kind(ctx, obj)
+}
+
 func (s shortMapper) SetStatusOperation_date(ctx context.Context, obj *operations.SetStatusOperation) (time.Time, error) {
 	return s.r.SetStatusOperation().Date(ctx, obj)
 }
@@ -196,6 +408,14 @@ func (s shortMapper) SetTitleOperation_date(ctx context.Context, obj *operations
 	return s.r.SetTitleOperation().Date(ctx, obj)
 }
 
+func (s shortMapper) SubscribeOperation_date(ctx context.Context, obj *operations.SubscribeOperation) (time.Time, error) {
+	return s.r.SubscribeOperation().Date(ctx, obj)
+}
+
+func (s shortMapper) UnsubscribeOperation_date(ctx context.Context, obj *operations.UnsubscribeOperation) (time.Time, error) {
+	return s.r.UnsubscribeOperation().Date(ctx, obj)
+}
+
 type executableSchema struct {
 	resolvers Resolvers
 }
@@ -265,6 +485,8 @@ func (ec *executionContext) _AddCommentOperation(ctx context.Context, sel []quer
 			out.Values[i] = ec._AddCommentOperation_date(ctx, field, obj)
 		case "message":
 			out.Values[i] = ec._AddCommentOperation_message(ctx, field, obj)
+		case "messageHtml":
+			out.Values[i] = ec._AddCommentOperation_messageHtml(ctx, field, obj)
 		case "files":
 			out.Values[i] = ec._AddCommentOperation_files(ctx, field, obj)
 		default:
@@ -317,14 +539,63 @@ func (ec *executionContext) _AddCommentOperation_date(ctx context.Context, field
 }
 
 func (ec *executionContext) _AddCommentOperation_message(ctx context.Context, field graphql.CollectedField, obj *operations.AddCommentOperation) graphql.Marshaler {
-	rctx := graphql.GetResolverContext(ctx)
-	rctx.Object = "AddCommentOperation"
-	rctx.Args = nil
-	rctx.Field = field
-	rctx.PushField(field.Alias)
-	defer rctx.Pop()
-	res := obj.Message
-	return graphql.MarshalString(res)
+	ctx = graphql.WithResolverContext(ctx, &graphql.ResolverContext{
+		Object: "AddCommentOperation",
+		Args:   nil,
+		Field:  field,
+	})
+	return graphql.Defer(func() (ret graphql.Marshaler) {
+		defer func() {
+			if r := recover(); r != nil {
+				userErr := ec.Recover(ctx, r)
+				ec.Error(ctx, userErr)
+				ret = graphql.Null
+			}
+		}()
+
+		resTmp, err := ec.ResolverMiddleware(ctx, func(ctx context.Context) (interface{}, error) {
+			return ec.resolvers.AddCommentOperation_message(ctx, obj)
+		})
+		if err != nil {
+			ec.Error(ctx, err)
+			return graphql.Null
+		}
+		if resTmp == nil {
+			return graphql.Null
+		}
+		res := resTmp.(string)
+		return graphql.MarshalString(res)
+	})
+}
+
+func (ec *executionContext) _AddCommentOperation_messageHtml(ctx context.Context, field graphql.CollectedField, obj *operations.AddCommentOperation) graphql.Marshaler {
+	ctx = graphql.WithResolverContext(ctx, &graphql.ResolverContext{
+		Object: "AddCommentOperation",
+		Args:   nil,
+		Field:  field,
+	})
+	return graphql.Defer(func() (ret graphql.Marshaler) {
+		defer func() {
+			if r := recover(); r != nil {
+				userErr := ec.Recover(ctx, r)
+				ec.Error(ctx, userErr)
+				ret = graphql.Null
+			}
+		}()
+
+		resTmp, err := ec.ResolverMiddleware(ctx, func(ctx context.Context) (interface{}, error) {
+			return ec.resolvers.AddCommentOperation_messageHtml(ctx, obj)
+		})
+		if err != nil {
+			ec.Error(ctx, err)
+			return graphql.Null
+		}
+		if resTmp == nil {
+			return graphql.Null
+		}
+		res := resTmp.(string)
+		return graphql.MarshalString(res)
+	})
 }
 
 func (ec *executionContext) _AddCommentOperation_files(ctx context.Context, field graphql.CollectedField, obj *operations.AddCommentOperation) graphql.Marshaler {
@@ -347,11 +618,11 @@ func (ec *executionContext) _AddCommentOperation_files(ctx context.Context, fiel
 	return arr1
 }
 
-var bugImplementors = []string{"Bug"}
+var addTimeLogOperationImplementors = []string{"AddTimeLogOperation", "Operation", "Authored"}
 
 // nolint: gocyclo, errcheck, gas, goconst
-func (ec *executionContext) _Bug(ctx context.Context, sel []query.Selection, obj *bug.Snapshot) graphql.Marshaler {
-	fields := graphql.CollectFields(ec.Doc, sel, bugImplementors, ec.Variables)
+func (ec *executionContext) _AddTimeLogOperation(ctx context.Context, sel []query.Selection, obj *operations.AddTimeLogOperation) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.Doc, sel, addTimeLogOperationImplementors, ec.Variables)
 
 	out := graphql.NewOrderedMap(len(fields))
 	for i, field := range fields {
@@ -359,27 +630,15 @@ func (ec *executionContext) _Bug(ctx context.Context, sel []query.Selection, obj
 
 		switch field.Name {
 		case "__typename":
-			out.Values[i] = graphql.MarshalString("Bug")
-		case "id":
-			out.Values[i] = ec._Bug_id(ctx, field, obj)
-		case "humanId":
-			out.Values[i] = ec._Bug_humanId(ctx, field, obj)
-		case "status":
-			out.Values[i] = ec._Bug_status(ctx, field, obj)
-		case "title":
-			out.Values[i] = ec._Bug_title(ctx, field, obj)
-		case "labels":
-			out.Values[i] = ec._Bug_labels(ctx, field, obj)
+			out.Values[i] = graphql.MarshalString("AddTimeLogOperation")
 		case "author":
-			out.Values[i] = ec._Bug_author(ctx, field, obj)
-		case "createdAt":
-			out.Values[i] = ec._Bug_createdAt(ctx, field, obj)
-		case "lastEdit":
-			out.Values[i] = ec._Bug_lastEdit(ctx, field, obj)
-		case "comments":
-			out.Values[i] = ec._Bug_comments(ctx, field, obj)
-		case "operations":
-			out.Values[i] = ec._Bug_operations(ctx, field, obj)
+			out.Values[i] = ec._AddTimeLogOperation_author(ctx, field, obj)
+		case "date":
+			out.Values[i] = ec._AddTimeLogOperation_date(ctx, field, obj)
+		case "duration":
+			out.Values[i] = ec._AddTimeLogOperation_duration(ctx, field, obj)
+		case "note":
+			out.Values[i] = ec._AddTimeLogOperation_note(ctx, field, obj)
 		default:
 			panic("unknown field " + strconv.Quote(field.Name))
 		}
@@ -388,31 +647,50 @@ func (ec *executionContext) _Bug(ctx context.Context, sel []query.Selection, obj
 	return out
 }
 
-func (ec *executionContext) _Bug_id(ctx context.Context, field graphql.CollectedField, obj *bug.Snapshot) graphql.Marshaler {
+func (ec *executionContext) _AddTimeLogOperation_author(ctx context.Context, field graphql.CollectedField, obj *operations.AddTimeLogOperation) graphql.Marshaler {
 	rctx := graphql.GetResolverContext(ctx)
-	rctx.Object = "Bug"
+	rctx.Object = "AddTimeLogOperation"
 	rctx.Args = nil
 	rctx.Field = field
 	rctx.PushField(field.Alias)
 	defer rctx.Pop()
-	res := obj.Id()
-	return graphql.MarshalString(res)
+	res := obj.Author
+	return ec._Person(ctx, field.Selections, &res)
 }
 
-func (ec *executionContext) _Bug_humanId(ctx context.Context, field graphql.CollectedField, obj *bug.Snapshot) graphql.Marshaler {
-	rctx := graphql.GetResolverContext(ctx)
-	rctx.Object = "Bug"
-	rctx.Args = nil
-	rctx.Field = field
-	rctx.PushField(field.Alias)
-	defer rctx.Pop()
-	res := obj.HumanId()
-	return graphql.MarshalString(res)
+func (ec *executionContext) _AddTimeLogOperation_date(ctx context.Context, field graphql.CollectedField, obj *operations.AddTimeLogOperation) graphql.Marshaler {
+	ctx = graphql.WithResolverContext(ctx, &graphql.ResolverContext{
+		Object: "AddTimeLogOperation",
+		Args:   nil,
+		Field:  field,
+	})
+	return graphql.Defer(func() (ret graphql.Marshaler) {
+		defer func() {
+			if r := recover(); r != nil {
+				userErr := ec.Recover(ctx, r)
+				ec.Error(ctx, userErr)
+				ret = graphql.Null
+			}
+		}()
+
+		resTmp, err := ec.ResolverMiddleware(ctx, func(ctx context.Context) (interface{}, error) {
+			return ec.resolvers.AddTimeLogOperation_date(ctx, obj)
+		})
+		if err != nil {
+			ec.Error(ctx, err)
+			return graphql.Null
+		}
+		if resTmp == nil {
+			return graphql.Null
+		}
+		res := resTmp.(time.Time)
+		return graphql.MarshalTime(res)
+	})
 }
 
-func (ec *executionContext) _Bug_status(ctx context.Context, field graphql.CollectedField, obj *bug.Snapshot) graphql.Marshaler {
+func (ec *executionContext) _AddTimeLogOperation_duration(ctx context.Context, field graphql.CollectedField, obj *operations.AddTimeLogOperation) graphql.Marshaler {
 	ctx = graphql.WithResolverContext(ctx, &graphql.ResolverContext{
-		Object: "Bug",
+		Object: "AddTimeLogOperation",
 		Args:   nil,
 		Field:  field,
 	})
@@ -426,7 +704,7 @@ func (ec *executionContext) _Bug_status(ctx context.Context, field graphql.Colle
 		}()
 
 		resTmp, err := ec.ResolverMiddleware(ctx, func(ctx context.Context) (interface{}, error) {
-			return ec.resolvers.Bug_status(ctx, obj)
+			return ec.resolvers.AddTimeLogOperation_duration(ctx, obj)
 		})
 		if err != nil {
 			ec.Error(ctx, err)
@@ -435,45 +713,54 @@ func (ec *executionContext) _Bug_status(ctx context.Context, field graphql.Colle
 		if resTmp == nil {
 			return graphql.Null
 		}
-		res := resTmp.(models.Status)
-		return res
+		res := resTmp.(int)
+		return graphql.MarshalInt(res)
 	})
 }
 
-func (ec *executionContext) _Bug_title(ctx context.Context, field graphql.CollectedField, obj *bug.Snapshot) graphql.Marshaler {
+func (ec *executionContext) _AddTimeLogOperation_note(ctx context.Context, field graphql.CollectedField, obj *operations.AddTimeLogOperation) graphql.Marshaler {
 	rctx := graphql.GetResolverContext(ctx)
-	rctx.Object = "Bug"
+	rctx.Object = "AddTimeLogOperation"
 	rctx.Args = nil
 	rctx.Field = field
 	rctx.PushField(field.Alias)
 	defer rctx.Pop()
-	res := obj.Title
+	res := obj.Note
 	return graphql.MarshalString(res)
 }
 
-func (ec *executionContext) _Bug_labels(ctx context.Context, field graphql.CollectedField, obj *bug.Snapshot) graphql.Marshaler {
-	rctx := graphql.GetResolverContext(ctx)
-	rctx.Object = "Bug"
-	rctx.Args = nil
-	rctx.Field = field
-	rctx.PushField(field.Alias)
-	defer rctx.Pop()
-	res := obj.Labels
-	arr1 := graphql.Array{}
-	for idx1 := range res {
-		arr1 = append(arr1, func() graphql.Marshaler {
-			rctx := graphql.GetResolverContext(ctx)
-			rctx.PushIndex(idx1)
-			defer rctx.Pop()
-			return res[idx1]
-		}())
+var archiveCommentsOperationImplementors = []string{"ArchiveCommentsOperation", "Operation", "Authored"}
+
+// nolint: gocyclo, errcheck, gas, goconst
+func (ec *executionContext) _ArchiveCommentsOperation(ctx context.Context, sel []query.Selection, obj *operations.ArchiveCommentsOperation) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.Doc, sel, archiveCommentsOperationImplementors, ec.Variables)
+
+	out := graphql.NewOrderedMap(len(fields))
+	for i, field := range fields {
+		out.Keys[i] = field.Alias
+
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("ArchiveCommentsOperation")
+		case "author":
+			out.Values[i] = ec._ArchiveCommentsOperation_author(ctx, field, obj)
+		case "date":
+			out.Values[i] = ec._ArchiveCommentsOperation_date(ctx, field, obj)
+		case "before":
+			out.Values[i] = ec._ArchiveCommentsOperation_before(ctx, field, obj)
+		case "archive":
+			out.Values[i] = ec._ArchiveCommentsOperation_archive(ctx, field, obj)
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
 	}
-	return arr1
+
+	return out
 }
 
-func (ec *executionContext) _Bug_author(ctx context.Context, field graphql.CollectedField, obj *bug.Snapshot) graphql.Marshaler {
+func (ec *executionContext) _ArchiveCommentsOperation_author(ctx context.Context, field graphql.CollectedField, obj *operations.ArchiveCommentsOperation) graphql.Marshaler {
 	rctx := graphql.GetResolverContext(ctx)
-	rctx.Object = "Bug"
+	rctx.Object = "ArchiveCommentsOperation"
 	rctx.Args = nil
 	rctx.Field = field
 	rctx.PushField(field.Alias)
@@ -482,93 +769,10 @@ func (ec *executionContext) _Bug_author(ctx context.Context, field graphql.Colle
 	return ec._Person(ctx, field.Selections, &res)
 }
 
-func (ec *executionContext) _Bug_createdAt(ctx context.Context, field graphql.CollectedField, obj *bug.Snapshot) graphql.Marshaler {
-	rctx := graphql.GetResolverContext(ctx)
-	rctx.Object = "Bug"
-	rctx.Args = nil
-	rctx.Field = field
-	rctx.PushField(field.Alias)
-	defer rctx.Pop()
-	res := obj.CreatedAt
-	return graphql.MarshalTime(res)
-}
-
-func (ec *executionContext) _Bug_lastEdit(ctx context.Context, field graphql.CollectedField, obj *bug.Snapshot) graphql.Marshaler {
-	rctx := graphql.GetResolverContext(ctx)
-	rctx.Object = "Bug"
-	rctx.Args = nil
-	rctx.Field = field
-	rctx.PushField(field.Alias)
-	defer rctx.Pop()
-	res := obj.LastEdit()
-	return graphql.MarshalTime(res)
-}
-
-func (ec *executionContext) _Bug_comments(ctx context.Context, field graphql.CollectedField, obj *bug.Snapshot) graphql.Marshaler {
-	args := map[string]interface{}{}
-	var arg0 *string
-	if tmp, ok := field.Args["after"]; ok {
-		var err error
-		var ptr1 string
-		if tmp != nil {
-			ptr1, err = graphql.UnmarshalString(tmp)
-			arg0 = &ptr1
-		}
-
-		if err != nil {
-			ec.Error(ctx, err)
-			return graphql.Null
-		}
-	}
-	args["after"] = arg0
-	var arg1 *string
-	if tmp, ok := field.Args["before"]; ok {
-		var err error
-		var ptr1 string
-		if tmp != nil {
-			ptr1, err = graphql.UnmarshalString(tmp)
-			arg1 = &ptr1
-		}
-
-		if err != nil {
-			ec.Error(ctx, err)
-			return graphql.Null
-		}
-	}
-	args["before"] = arg1
-	var arg2 *int
-	if tmp, ok := field.Args["first"]; ok {
-		var err error
-		var ptr1 int
-		if tmp != nil {
-			ptr1, err = graphql.UnmarshalInt(tmp)
-			arg2 = &ptr1
-		}
-
-		if err != nil {
-			ec.Error(ctx, err)
-			return graphql.Null
-		}
-	}
-	args["first"] = arg2
-	var arg3 *int
-	if tmp, ok := field.Args["last"]; ok {
-		var err error
-		var ptr1 int
-		if tmp != nil {
-			ptr1, err = graphql.UnmarshalInt(tmp)
-			arg3 = &ptr1
-		}
-
-		if err != nil {
-			ec.Error(ctx, err)
-			return graphql.Null
-		}
-	}
-	args["last"] = arg3
+func (ec *executionContext) _ArchiveCommentsOperation_date(ctx context.Context, field graphql.CollectedField, obj *operations.ArchiveCommentsOperation) graphql.Marshaler {
 	ctx = graphql.WithResolverContext(ctx, &graphql.ResolverContext{
-		Object: "Bug",
-		Args:   args,
+		Object: "ArchiveCommentsOperation",
+		Args:   nil,
 		Field:  field,
 	})
 	return graphql.Defer(func() (ret graphql.Marshaler) {
@@ -581,7 +785,7 @@ func (ec *executionContext) _Bug_comments(ctx context.Context, field graphql.Col
 		}()
 
 		resTmp, err := ec.ResolverMiddleware(ctx, func(ctx context.Context) (interface{}, error) {
-			return ec.resolvers.Bug_comments(ctx, obj, args["after"].(*string), args["before"].(*string), args["first"].(*int), args["last"].(*int))
+			return ec.resolvers.ArchiveCommentsOperation_date(ctx, obj)
 		})
 		if err != nil {
 			ec.Error(ctx, err)
@@ -590,76 +794,15 @@ func (ec *executionContext) _Bug_comments(ctx context.Context, field graphql.Col
 		if resTmp == nil {
 			return graphql.Null
 		}
-		res := resTmp.(models.CommentConnection)
-		return ec._CommentConnection(ctx, field.Selections, &res)
+		res := resTmp.(time.Time)
+		return graphql.MarshalTime(res)
 	})
 }
 
-func (ec *executionContext) _Bug_operations(ctx context.Context, field graphql.CollectedField, obj *bug.Snapshot) graphql.Marshaler {
-	args := map[string]interface{}{}
-	var arg0 *string
-	if tmp, ok := field.Args["after"]; ok {
-		var err error
-		var ptr1 string
-		if tmp != nil {
-			ptr1, err = graphql.UnmarshalString(tmp)
-			arg0 = &ptr1
-		}
-
-		if err != nil {
-			ec.Error(ctx, err)
-			return graphql.Null
-		}
-	}
-	args["after"] = arg0
-	var arg1 *string
-	if tmp, ok := field.Args["before"]; ok {
-		var err error
-		var ptr1 string
-		if tmp != nil {
-			ptr1, err = graphql.UnmarshalString(tmp)
-			arg1 = &ptr1
-		}
-
-		if err != nil {
-			ec.Error(ctx, err)
-			return graphql.Null
-		}
-	}
-	args["before"] = arg1
-	var arg2 *int
-	if tmp, ok := field.Args["first"]; ok {
-		var err error
-		var ptr1 int
-		if tmp != nil {
-			ptr1, err = graphql.UnmarshalInt(tmp)
-			arg2 = &ptr1
-		}
-
-		if err != nil {
-			ec.Error(ctx, err)
-			return graphql.Null
-		}
-	}
-	args["first"] = arg2
-	var arg3 *int
-	if tmp, ok := field.Args["last"]; ok {
-		var err error
-		var ptr1 int
-		if tmp != nil {
-			ptr1, err = graphql.UnmarshalInt(tmp)
-			arg3 = &ptr1
-		}
-
-		if err != nil {
-			ec.Error(ctx, err)
-			return graphql.Null
-		}
-	}
-	args["last"] = arg3
+func (ec *executionContext) _ArchiveCommentsOperation_before(ctx context.Context, field graphql.CollectedField, obj *operations.ArchiveCommentsOperation) graphql.Marshaler {
 	ctx = graphql.WithResolverContext(ctx, &graphql.ResolverContext{
-		Object: "Bug",
-		Args:   args,
+		Object: "ArchiveCommentsOperation",
+		Args:   nil,
 		Field:  field,
 	})
 	return graphql.Defer(func() (ret graphql.Marshaler) {
@@ -672,7 +815,7 @@ func (ec *executionContext) _Bug_operations(ctx context.Context, field graphql.C
 		}()
 
 		resTmp, err := ec.ResolverMiddleware(ctx, func(ctx context.Context) (interface{}, error) {
-			return ec.resolvers.Bug_operations(ctx, obj, args["after"].(*string), args["before"].(*string), args["first"].(*int), args["last"].(*int))
+			return ec.resolvers.ArchiveCommentsOperation_before(ctx, obj)
 		})
 		if err != nil {
 			ec.Error(ctx, err)
@@ -681,16 +824,27 @@ func (ec *executionContext) _Bug_operations(ctx context.Context, field graphql.C
 		if resTmp == nil {
 			return graphql.Null
 		}
-		res := resTmp.(models.OperationConnection)
-		return ec._OperationConnection(ctx, field.Selections, &res)
+		res := resTmp.(time.Time)
+		return graphql.MarshalTime(res)
 	})
 }
 
-var bugConnectionImplementors = []string{"BugConnection"}
+func (ec *executionContext) _ArchiveCommentsOperation_archive(ctx context.Context, field graphql.CollectedField, obj *operations.ArchiveCommentsOperation) graphql.Marshaler {
+	rctx := graphql.GetResolverContext(ctx)
+	rctx.Object = "ArchiveCommentsOperation"
+	rctx.Args = nil
+	rctx.Field = field
+	rctx.PushField(field.Alias)
+	defer rctx.Pop()
+	res := obj.Archive
+	return res
+}
+
+var authorRemapImplementors = []string{"AuthorRemap"}
 
 // nolint: gocyclo, errcheck, gas, goconst
-func (ec *executionContext) _BugConnection(ctx context.Context, sel []query.Selection, obj *models.BugConnection) graphql.Marshaler {
-	fields := graphql.CollectFields(ec.Doc, sel, bugConnectionImplementors, ec.Variables)
+func (ec *executionContext) _AuthorRemap(ctx context.Context, sel []query.Selection, obj *bug.AuthorRemap) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.Doc, sel, authorRemapImplementors, ec.Variables)
 
 	out := graphql.NewOrderedMap(len(fields))
 	for i, field := range fields {
@@ -698,15 +852,11 @@ func (ec *executionContext) _BugConnection(ctx context.Context, sel []query.Sele
 
 		switch field.Name {
 		case "__typename":
-			out.Values[i] = graphql.MarshalString("BugConnection")
-		case "edges":
-			out.Values[i] = ec._BugConnection_edges(ctx, field, obj)
-		case "nodes":
-			out.Values[i] = ec._BugConnection_nodes(ctx, field, obj)
-		case "pageInfo":
-			out.Values[i] = ec._BugConnection_pageInfo(ctx, field, obj)
-		case "totalCount":
-			out.Values[i] = ec._BugConnection_totalCount(ctx, field, obj)
+			out.Values[i] = graphql.MarshalString("AuthorRemap")
+		case "login":
+			out.Values[i] = ec._AuthorRemap_login(ctx, field, obj)
+		case "person":
+			out.Values[i] = ec._AuthorRemap_person(ctx, field, obj)
 		default:
 			panic("unknown field " + strconv.Quote(field.Name))
 		}
@@ -715,480 +865,434 @@ func (ec *executionContext) _BugConnection(ctx context.Context, sel []query.Sele
 	return out
 }
 
-func (ec *executionContext) _BugConnection_edges(ctx context.Context, field graphql.CollectedField, obj *models.BugConnection) graphql.Marshaler {
+func (ec *executionContext) _AuthorRemap_login(ctx context.Context, field graphql.CollectedField, obj *bug.AuthorRemap) graphql.Marshaler {
 	rctx := graphql.GetResolverContext(ctx)
-	rctx.Object = "BugConnection"
+	rctx.Object = "AuthorRemap"
 	rctx.Args = nil
 	rctx.Field = field
 	rctx.PushField(field.Alias)
 	defer rctx.Pop()
-	res := obj.Edges
-	arr1 := graphql.Array{}
-	for idx1 := range res {
-		arr1 = append(arr1, func() graphql.Marshaler {
-			rctx := graphql.GetResolverContext(ctx)
-			rctx.PushIndex(idx1)
-			defer rctx.Pop()
-			return ec._BugEdge(ctx, field.Selections, &res[idx1])
-		}())
-	}
-	return arr1
+	res := obj.Login
+	return graphql.MarshalString(res)
 }
 
-func (ec *executionContext) _BugConnection_nodes(ctx context.Context, field graphql.CollectedField, obj *models.BugConnection) graphql.Marshaler {
+func (ec *executionContext) _AuthorRemap_person(ctx context.Context, field graphql.CollectedField, obj *bug.AuthorRemap) graphql.Marshaler {
 	rctx := graphql.GetResolverContext(ctx)
-	rctx.Object = "BugConnection"
+	rctx.Object = "AuthorRemap"
 	rctx.Args = nil
 	rctx.Field = field
 	rctx.PushField(field.Alias)
 	defer rctx.Pop()
-	res := obj.Nodes
-	arr1 := graphql.Array{}
-	for idx1 := range res {
-		arr1 = append(arr1, func() graphql.Marshaler {
-			rctx := graphql.GetResolverContext(ctx)
-			rctx.PushIndex(idx1)
-			defer rctx.Pop()
-			return ec._Bug(ctx, field.Selections, &res[idx1])
-		}())
+	res := obj.Person
+	return ec._Person(ctx, field.Selections, &res)
+}
+
+var bugImplementors = []string{"Bug"}
+
+// nolint: gocyclo, errcheck, gas, goconst
+func (ec *executionContext) _Bug(ctx context.Context, sel []query.Selection, obj *bug.Snapshot) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.Doc, sel, bugImplementors, ec.Variables)
+
+	out := graphql.NewOrderedMap(len(fields))
+	for i, field := range fields {
+		out.Keys[i] = field.Alias
+
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("Bug")
+		case "id":
+			out.Values[i] = ec._Bug_id(ctx, field, obj)
+		case "humanId":
+			out.Values[i] = ec._Bug_humanId(ctx, field, obj)
+		case "status":
+			out.Values[i] = ec._Bug_status(ctx, field, obj)
+		case "priority":
+			out.Values[i] = ec._Bug_priority(ctx, field, obj)
+		case "title":
+			out.Values[i] = ec._Bug_title(ctx, field, obj)
+		case "labels":
+			out.Values[i] = ec._Bug_labels(ctx, field, obj)
+		case "relations":
+			out.Values[i] = ec._Bug_relations(ctx, field, obj)
+		case "subscribers":
+			out.Values[i] = ec._Bug_subscribers(ctx, field, obj)
+		case "customFields":
+			out.Values[i] = ec._Bug_customFields(ctx, field, obj)
+		case "checklists":
+			out.Values[i] = ec._Bug_checklists(ctx, field, obj)
+		case "timeSpent":
+			out.Values[i] = ec._Bug_timeSpent(ctx, field, obj)
+		case "author":
+			out.Values[i] = ec._Bug_author(ctx, field, obj)
+		case "createdAt":
+			out.Values[i] = ec._Bug_createdAt(ctx, field, obj)
+		case "lastEdit":
+			out.Values[i] = ec._Bug_lastEdit(ctx, field, obj)
+		case "archivedCount":
+			out.Values[i] = ec._Bug_archivedCount(ctx, field, obj)
+		case "comments":
+			out.Values[i] = ec._Bug_comments(ctx, field, obj)
+		case "operations":
+			out.Values[i] = ec._Bug_operations(ctx, field, obj)
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
 	}
-	return arr1
+
+	return out
 }
 
-func (ec *executionContext) _BugConnection_pageInfo(ctx context.Context, field graphql.CollectedField, obj *models.BugConnection) graphql.Marshaler {
+func (ec *executionContext) _Bug_id(ctx context.Context, field graphql.CollectedField, obj *bug.Snapshot) graphql.Marshaler {
 	rctx := graphql.GetResolverContext(ctx)
-	rctx.Object = "BugConnection"
+	rctx.Object = "Bug"
 	rctx.Args = nil
 	rctx.Field = field
 	rctx.PushField(field.Alias)
 	defer rctx.Pop()
-	res := obj.PageInfo
-	return ec._PageInfo(ctx, field.Selections, &res)
+	res := obj.Id()
+	return graphql.MarshalString(res)
 }
 
-func (ec *executionContext) _BugConnection_totalCount(ctx context.Context, field graphql.CollectedField, obj *models.BugConnection) graphql.Marshaler {
+func (ec *executionContext) _Bug_humanId(ctx context.Context, field graphql.CollectedField, obj *bug.Snapshot) graphql.Marshaler {
 	rctx := graphql.GetResolverContext(ctx)
-	rctx.Object = "BugConnection"
+	rctx.Object = "Bug"
 	rctx.Args = nil
 	rctx.Field = field
 	rctx.PushField(field.Alias)
 	defer rctx.Pop()
-	res := obj.TotalCount
-	return graphql.MarshalInt(res)
+	res := obj.HumanId()
+	return graphql.MarshalString(res)
 }
 
-var bugEdgeImplementors = []string{"BugEdge"}
-
-// nolint: gocyclo, errcheck, gas, goconst
-func (ec *executionContext) _BugEdge(ctx context.Context, sel []query.Selection, obj *models.BugEdge) graphql.Marshaler {
-	fields := graphql.CollectFields(ec.Doc, sel, bugEdgeImplementors, ec.Variables)
-
-	out := graphql.NewOrderedMap(len(fields))
-	for i, field := range fields {
-		out.Keys[i] = field.Alias
+func (ec *executionContext) _Bug_status(ctx context.Context, field graphql.CollectedField, obj *bug.Snapshot) graphql.Marshaler {
+	ctx = graphql.WithResolverContext(ctx, &graphql.ResolverContext{
+		Object: "Bug",
+		Args:   nil,
+		Field:  field,
+	})
+	return graphql.Defer(func() (ret graphql.Marshaler) {
+		defer func() {
+			if r := recover(); r != nil {
+				userErr := ec.Recover(ctx, r)
+				ec.Error(ctx, userErr)
+				ret = graphql.Null
+			}
+		}()
 
-		switch field.Name {
-		case "__typename":
-			out.Values[i] = graphql.MarshalString("BugEdge")
-		case "cursor":
-			out.Values[i] = ec._BugEdge_cursor(ctx, field, obj)
-		case "node":
-			out.Values[i] = ec._BugEdge_node(ctx, field, obj)
-		default:
-			panic("unknown field " + strconv.Quote(field.Name))
+		resTmp, err := ec.ResolverMiddleware(ctx, func(ctx context.Context) (interface{}, error) {
+			return ec.resolvers.Bug_status(ctx, obj)
+		})
+		if err != nil {
+			ec.Error(ctx, err)
+			return graphql.Null
 		}
-	}
-
-	return out
-}
-
-func (ec *executionContext) _BugEdge_cursor(ctx context.Context, field graphql.CollectedField, obj *models.BugEdge) graphql.Marshaler {
-	rctx := graphql.GetResolverContext(ctx)
-	rctx.Object = "BugEdge"
-	rctx.Args = nil
-	rctx.Field = field
-	rctx.PushField(field.Alias)
-	defer rctx.Pop()
-	res := obj.Cursor
-	return graphql.MarshalString(res)
-}
-
-func (ec *executionContext) _BugEdge_node(ctx context.Context, field graphql.CollectedField, obj *models.BugEdge) graphql.Marshaler {
-	rctx := graphql.GetResolverContext(ctx)
-	rctx.Object = "BugEdge"
-	rctx.Args = nil
-	rctx.Field = field
-	rctx.PushField(field.Alias)
-	defer rctx.Pop()
-	res := obj.Node
-	return ec._Bug(ctx, field.Selections, &res)
+		if resTmp == nil {
+			return graphql.Null
+		}
+		res := resTmp.(models.Status)
+		return res
+	})
 }
 
-var commentImplementors = []string{"Comment", "Authored"}
-
-// nolint: gocyclo, errcheck, gas, goconst
-func (ec *executionContext) _Comment(ctx context.Context, sel []query.Selection, obj *bug.Comment) graphql.Marshaler {
-	fields := graphql.CollectFields(ec.Doc, sel, commentImplementors, ec.Variables)
-
-	out := graphql.NewOrderedMap(len(fields))
-	for i, field := range fields {
-		out.Keys[i] = field.Alias
+func (ec *executionContext) _Bug_priority(ctx context.Context, field graphql.CollectedField, obj *bug.Snapshot) graphql.Marshaler {
+	ctx = graphql.WithResolverContext(ctx, &graphql.ResolverContext{
+		Object: "Bug",
+		Args:   nil,
+		Field:  field,
+	})
+	return graphql.Defer(func() (ret graphql.Marshaler) {
+		defer func() {
+			if r := recover(); r != nil {
+				userErr := ec.Recover(ctx, r)
+				ec.Error(ctx, userErr)
+				ret = graphql.Null
+			}
+		}()
 
-		switch field.Name {
-		case "__typename":
-			out.Values[i] = graphql.MarshalString("Comment")
-		case "author":
-			out.Values[i] = ec._Comment_author(ctx, field, obj)
-		case "message":
-			out.Values[i] = ec._Comment_message(ctx, field, obj)
-		case "files":
-			out.Values[i] = ec._Comment_files(ctx, field, obj)
-		default:
-			panic("unknown field " + strconv.Quote(field.Name))
+		resTmp, err := ec.ResolverMiddleware(ctx, func(ctx context.Context) (interface{}, error) {
+			return ec.resolvers.Bug_priority(ctx, obj)
+		})
+		if err != nil {
+			ec.Error(ctx, err)
+			return graphql.Null
 		}
-	}
-
-	return out
+		if resTmp == nil {
+			return graphql.Null
+		}
+		res := resTmp.(models.Priority)
+		return res
+	})
 }
 
-func (ec *executionContext) _Comment_author(ctx context.Context, field graphql.CollectedField, obj *bug.Comment) graphql.Marshaler {
+func (ec *executionContext) _Bug_title(ctx context.Context, field graphql.CollectedField, obj *bug.Snapshot) graphql.Marshaler {
 	rctx := graphql.GetResolverContext(ctx)
-	rctx.Object = "Comment"
+	rctx.Object = "Bug"
 	rctx.Args = nil
 	rctx.Field = field
 	rctx.PushField(field.Alias)
 	defer rctx.Pop()
-	res := obj.Author
-	return ec._Person(ctx, field.Selections, &res)
+	res := obj.Title
+	return graphql.MarshalString(res)
 }
 
-func (ec *executionContext) _Comment_message(ctx context.Context, field graphql.CollectedField, obj *bug.Comment) graphql.Marshaler {
+func (ec *executionContext) _Bug_labels(ctx context.Context, field graphql.CollectedField, obj *bug.Snapshot) graphql.Marshaler {
 	rctx := graphql.GetResolverContext(ctx)
-	rctx.Object = "Comment"
+	rctx.Object = "Bug"
 	rctx.Args = nil
 	rctx.Field = field
 	rctx.PushField(field.Alias)
 	defer rctx.Pop()
-	res := obj.Message
-	return graphql.MarshalString(res)
+	res := obj.Labels
+	arr1 := graphql.Array{}
+	for idx1 := range res {
+		arr1 = append(arr1, func() graphql.Marshaler {
+			rctx := graphql.GetResolverContext(ctx)
+			rctx.PushIndex(idx1)
+			defer rctx.Pop()
+			return res[idx1]
+		}())
+	}
+	return arr1
 }
 
-func (ec *executionContext) _Comment_files(ctx context.Context, field graphql.CollectedField, obj *bug.Comment) graphql.Marshaler {
+func (ec *executionContext) _Bug_relations(ctx context.Context, field graphql.CollectedField, obj *bug.Snapshot) graphql.Marshaler {
 	rctx := graphql.GetResolverContext(ctx)
-	rctx.Object = "Comment"
+	rctx.Object = "Bug"
 	rctx.Args = nil
 	rctx.Field = field
 	rctx.PushField(field.Alias)
 	defer rctx.Pop()
-	res := obj.Files
+	res := obj.Relations
 	arr1 := graphql.Array{}
 	for idx1 := range res {
 		arr1 = append(arr1, func() graphql.Marshaler {
 			rctx := graphql.GetResolverContext(ctx)
 			rctx.PushIndex(idx1)
 			defer rctx.Pop()
-			return res[idx1]
+			return ec._Relation(ctx, field.Selections, &res[idx1])
 		}())
 	}
 	return arr1
 }
 
-var commentConnectionImplementors = []string{"CommentConnection"}
-
-// nolint: gocyclo, errcheck, gas, goconst
-func (ec *executionContext) _CommentConnection(ctx context.Context, sel []query.Selection, obj *models.CommentConnection) graphql.Marshaler {
-	fields := graphql.CollectFields(ec.Doc, sel, commentConnectionImplementors, ec.Variables)
-
-	out := graphql.NewOrderedMap(len(fields))
-	for i, field := range fields {
-		out.Keys[i] = field.Alias
-
-		switch field.Name {
-		case "__typename":
-			out.Values[i] = graphql.MarshalString("CommentConnection")
-		case "edges":
-			out.Values[i] = ec._CommentConnection_edges(ctx, field, obj)
-		case "nodes":
-			out.Values[i] = ec._CommentConnection_nodes(ctx, field, obj)
-		case "pageInfo":
-			out.Values[i] = ec._CommentConnection_pageInfo(ctx, field, obj)
-		case "totalCount":
-			out.Values[i] = ec._CommentConnection_totalCount(ctx, field, obj)
-		default:
-			panic("unknown field " + strconv.Quote(field.Name))
-		}
-	}
-
-	return out
-}
-
-func (ec *executionContext) _CommentConnection_edges(ctx context.Context, field graphql.CollectedField, obj *models.CommentConnection) graphql.Marshaler {
+func (ec *executionContext) _Bug_subscribers(ctx context.Context, field graphql.CollectedField, obj *bug.Snapshot) graphql.Marshaler {
 	rctx := graphql.GetResolverContext(ctx)
-	rctx.Object = "CommentConnection"
+	rctx.Object = "Bug"
 	rctx.Args = nil
 	rctx.Field = field
 	rctx.PushField(field.Alias)
 	defer rctx.Pop()
-	res := obj.Edges
+	res := obj.Subscribers
 	arr1 := graphql.Array{}
 	for idx1 := range res {
 		arr1 = append(arr1, func() graphql.Marshaler {
 			rctx := graphql.GetResolverContext(ctx)
 			rctx.PushIndex(idx1)
 			defer rctx.Pop()
-			return ec._CommentEdge(ctx, field.Selections, &res[idx1])
+			return ec._Person(ctx, field.Selections, &res[idx1])
 		}())
 	}
 	return arr1
 }
 
-func (ec *executionContext) _CommentConnection_nodes(ctx context.Context, field graphql.CollectedField, obj *models.CommentConnection) graphql.Marshaler {
+func (ec *executionContext) _Bug_customFields(ctx context.Context, field graphql.CollectedField, obj *bug.Snapshot) graphql.Marshaler {
+	ctx = graphql.WithResolverContext(ctx, &graphql.ResolverContext{
+		Object: "Bug",
+		Args:   nil,
+		Field:  field,
+	})
+	return graphql.Defer(func() (ret graphql.Marshaler) {
+		defer func() {
+			if r := recover(); r != nil {
+				userErr := ec.Recover(ctx, r)
+				ec.Error(ctx, userErr)
+				ret = graphql.Null
+			}
+		}()
+
+		resTmp, err := ec.ResolverMiddleware(ctx, func(ctx context.Context) (interface{}, error) {
+			return ec.resolvers.Bug_customFields(ctx, obj)
+		})
+		if err != nil {
+			ec.Error(ctx, err)
+			return graphql.Null
+		}
+		if resTmp == nil {
+			return graphql.Null
+		}
+		res := resTmp.([]models.CustomField)
+		arr1 := graphql.Array{}
+		for idx1 := range res {
+			arr1 = append(arr1, func() graphql.Marshaler {
+				rctx := graphql.GetResolverContext(ctx)
+				rctx.PushIndex(idx1)
+				defer rctx.Pop()
+				return ec._CustomField(ctx, field.Selections, &res[idx1])
+			}())
+		}
+		return arr1
+	})
+}
+
+func (ec *executionContext) _Bug_checklists(ctx context.Context, field graphql.CollectedField, obj *bug.Snapshot) graphql.Marshaler {
 	rctx := graphql.GetResolverContext(ctx)
-	rctx.Object = "CommentConnection"
+	rctx.Object = "Bug"
 	rctx.Args = nil
 	rctx.Field = field
 	rctx.PushField(field.Alias)
 	defer rctx.Pop()
-	res := obj.Nodes
+	res := obj.Checklists
 	arr1 := graphql.Array{}
 	for idx1 := range res {
 		arr1 = append(arr1, func() graphql.Marshaler {
 			rctx := graphql.GetResolverContext(ctx)
 			rctx.PushIndex(idx1)
 			defer rctx.Pop()
-			return ec._Comment(ctx, field.Selections, &res[idx1])
+			return ec._Checklist(ctx, field.Selections, &res[idx1])
 		}())
 	}
 	return arr1
 }
 
-func (ec *executionContext) _CommentConnection_pageInfo(ctx context.Context, field graphql.CollectedField, obj *models.CommentConnection) graphql.Marshaler {
+func (ec *executionContext) _Bug_timeSpent(ctx context.Context, field graphql.CollectedField, obj *bug.Snapshot) graphql.Marshaler {
+	ctx = graphql.WithResolverContext(ctx, &graphql.ResolverContext{
+		Object: "Bug",
+		Args:   nil,
+		Field:  field,
+	})
+	return graphql.Defer(func() (ret graphql.Marshaler) {
+		defer func() {
+			if r := recover(); r != nil {
+				userErr := ec.Recover(ctx, r)
+				ec.Error(ctx, userErr)
+				ret = graphql.Null
+			}
+		}()
+
+		resTmp, err := ec.ResolverMiddleware(ctx, func(ctx context.Context) (interface{}, error) {
+			return ec.resolvers.Bug_timeSpent(ctx, obj)
+		})
+		if err != nil {
+			ec.Error(ctx, err)
+			return graphql.Null
+		}
+		if resTmp == nil {
+			return graphql.Null
+		}
+		res := resTmp.(int)
+		return graphql.MarshalInt(res)
+	})
+}
+
+func (ec *executionContext) _Bug_author(ctx context.Context, field graphql.CollectedField, obj *bug.Snapshot) graphql.Marshaler {
 	rctx := graphql.GetResolverContext(ctx)
-	rctx.Object = "CommentConnection"
+	rctx.Object = "Bug"
 	rctx.Args = nil
 	rctx.Field = field
 	rctx.PushField(field.Alias)
 	defer rctx.Pop()
-	res := obj.PageInfo
-	return ec._PageInfo(ctx, field.Selections, &res)
+	res := obj.Author
+	return ec._Person(ctx, field.Selections, &res)
 }
 
-func (ec *executionContext) _CommentConnection_totalCount(ctx context.Context, field graphql.CollectedField, obj *models.CommentConnection) graphql.Marshaler {
+func (ec *executionContext) _Bug_createdAt(ctx context.Context, field graphql.CollectedField, obj *bug.Snapshot) graphql.Marshaler {
 	rctx := graphql.GetResolverContext(ctx)
-	rctx.Object = "CommentConnection"
+	rctx.Object = "Bug"
 	rctx.Args = nil
 	rctx.Field = field
 	rctx.PushField(field.Alias)
 	defer rctx.Pop()
-	res := obj.TotalCount
-	return graphql.MarshalInt(res)
+	res := obj.CreatedAt
+	return graphql.MarshalTime(res)
 }
 
-var commentEdgeImplementors = []string{"CommentEdge"}
-
-// nolint: gocyclo, errcheck, gas, goconst
-func (ec *executionContext) _CommentEdge(ctx context.Context, sel []query.Selection, obj *models.CommentEdge) graphql.Marshaler {
-	fields := graphql.CollectFields(ec.Doc, sel, commentEdgeImplementors, ec.Variables)
-
-	out := graphql.NewOrderedMap(len(fields))
-	for i, field := range fields {
-		out.Keys[i] = field.Alias
-
-		switch field.Name {
-		case "__typename":
-			out.Values[i] = graphql.MarshalString("CommentEdge")
-		case "cursor":
-			out.Values[i] = ec._CommentEdge_cursor(ctx, field, obj)
-		case "node":
-			out.Values[i] = ec._CommentEdge_node(ctx, field, obj)
-		default:
-			panic("unknown field " + strconv.Quote(field.Name))
-		}
-	}
-
-	return out
-}
-
-func (ec *executionContext) _CommentEdge_cursor(ctx context.Context, field graphql.CollectedField, obj *models.CommentEdge) graphql.Marshaler {
+func (ec *executionContext) _Bug_lastEdit(ctx context.Context, field graphql.CollectedField, obj *bug.Snapshot) graphql.Marshaler {
 	rctx := graphql.GetResolverContext(ctx)
-	rctx.Object = "CommentEdge"
+	rctx.Object = "Bug"
 	rctx.Args = nil
 	rctx.Field = field
 	rctx.PushField(field.Alias)
 	defer rctx.Pop()
-	res := obj.Cursor
-	return graphql.MarshalString(res)
+	res := obj.LastEdit()
+	return graphql.MarshalTime(res)
 }
 
-func (ec *executionContext) _CommentEdge_node(ctx context.Context, field graphql.CollectedField, obj *models.CommentEdge) graphql.Marshaler {
+func (ec *executionContext) _Bug_archivedCount(ctx context.Context, field graphql.CollectedField, obj *bug.Snapshot) graphql.Marshaler {
 	rctx := graphql.GetResolverContext(ctx)
-	rctx.Object = "CommentEdge"
+	rctx.Object = "Bug"
 	rctx.Args = nil
 	rctx.Field = field
 	rctx.PushField(field.Alias)
 	defer rctx.Pop()
-	res := obj.Node
-	return ec._Comment(ctx, field.Selections, &res)
+	res := obj.ArchivedCount
+	return graphql.MarshalInt(res)
 }
 
-var createOperationImplementors = []string{"CreateOperation", "Operation", "Authored"}
-
-// nolint: gocyclo, errcheck, gas, goconst
-func (ec *executionContext) _CreateOperation(ctx context.Context, sel []query.Selection, obj *operations.CreateOperation) graphql.Marshaler {
-	fields := graphql.CollectFields(ec.Doc, sel, createOperationImplementors, ec.Variables)
-
-	out := graphql.NewOrderedMap(len(fields))
-	for i, field := range fields {
-		out.Keys[i] = field.Alias
+func (ec *executionContext) _Bug_comments(ctx context.Context, field graphql.CollectedField, obj *bug.Snapshot) graphql.Marshaler {
+	args := map[string]interface{}{}
+	var arg0 *string
+	if tmp, ok := field.Args["after"]; ok {
+		var err error
+		var ptr1 string
+		if tmp != nil {
+			ptr1, err = graphql.UnmarshalString(tmp)
+			arg0 = &ptr1
+		}
 
-		switch field.Name {
-		case "__typename":
-			out.Values[i] = graphql.MarshalString("CreateOperation")
-		case "author":
-			out.Values[i] = ec._CreateOperation_author(ctx, field, obj)
-		case "date":
-			out.Values[i] = ec._CreateOperation_date(ctx, field, obj)
-		case "title":
-			out.Values[i] = ec._CreateOperation_title(ctx, field, obj)
-		case "message":
-			out.Values[i] = ec._CreateOperation_message(ctx, field, obj)
-		case "files":
-			out.Values[i] = ec._CreateOperation_files(ctx, field, obj)
-		default:
-			panic("unknown field " + strconv.Quote(field.Name))
+		if err != nil {
+			ec.Error(ctx, err)
+			return graphql.Null
 		}
 	}
+	args["after"] = arg0
+	var arg1 *string
+	if tmp, ok := field.Args["before"]; ok {
+		var err error
+		var ptr1 string
+		if tmp != nil {
+			ptr1, err = graphql.UnmarshalString(tmp)
+			arg1 = &ptr1
+		}
 
-	return out
-}
-
-func (ec *executionContext) _CreateOperation_author(ctx context.Context, field graphql.CollectedField, obj *operations.CreateOperation) graphql.Marshaler {
-	rctx := graphql.GetResolverContext(ctx)
-	rctx.Object = "CreateOperation"
-	rctx.Args = nil
-	rctx.Field = field
-	rctx.PushField(field.Alias)
-	defer rctx.Pop()
-	res := obj.Author
-	return ec._Person(ctx, field.Selections, &res)
-}
-
-func (ec *executionContext) _CreateOperation_date(ctx context.Context, field graphql.CollectedField, obj *operations.CreateOperation) graphql.Marshaler {
-	ctx = graphql.WithResolverContext(ctx, &graphql.ResolverContext{
-		Object: "CreateOperation",
-		Args:   nil,
-		Field:  field,
-	})
-	return graphql.Defer(func() (ret graphql.Marshaler) {
-		defer func() {
-			if r := recover(); r != nil {
-				userErr := ec.Recover(ctx, r)
-				ec.Error(ctx, userErr)
-				ret = graphql.Null
-			}
-		}()
-
-		resTmp, err := ec.ResolverMiddleware(ctx, func(ctx context.Context) (interface{}, error) {
-			return ec.resolvers.CreateOperation_date(ctx, obj)
-		})
 		if err != nil {
 			ec.Error(ctx, err)
 			return graphql.Null
 		}
-		if resTmp == nil {
-			return graphql.Null
+	}
+	args["before"] = arg1
+	var arg2 *int
+	if tmp, ok := field.Args["first"]; ok {
+		var err error
+		var ptr1 int
+		if tmp != nil {
+			ptr1, err = graphql.UnmarshalInt(tmp)
+			arg2 = &ptr1
 		}
-		res := resTmp.(time.Time)
-		return graphql.MarshalTime(res)
-	})
-}
-
-func (ec *executionContext) _CreateOperation_title(ctx context.Context, field graphql.CollectedField, obj *operations.CreateOperation) graphql.Marshaler {
-	rctx := graphql.GetResolverContext(ctx)
-	rctx.Object = "CreateOperation"
-	rctx.Args = nil
-	rctx.Field = field
-	rctx.PushField(field.Alias)
-	defer rctx.Pop()
-	res := obj.Title
-	return graphql.MarshalString(res)
-}
-
-func (ec *executionContext) _CreateOperation_message(ctx context.Context, field graphql.CollectedField, obj *operations.CreateOperation) graphql.Marshaler {
-	rctx := graphql.GetResolverContext(ctx)
-	rctx.Object = "CreateOperation"
-	rctx.Args = nil
-	rctx.Field = field
-	rctx.PushField(field.Alias)
-	defer rctx.Pop()
-	res := obj.Message
-	return graphql.MarshalString(res)
-}
 
-func (ec *executionContext) _CreateOperation_files(ctx context.Context, field graphql.CollectedField, obj *operations.CreateOperation) graphql.Marshaler {
-	rctx := graphql.GetResolverContext(ctx)
-	rctx.Object = "CreateOperation"
-	rctx.Args = nil
-	rctx.Field = field
-	rctx.PushField(field.Alias)
-	defer rctx.Pop()
-	res := obj.Files()
-	arr1 := graphql.Array{}
-	for idx1 := range res {
-		arr1 = append(arr1, func() graphql.Marshaler {
-			rctx := graphql.GetResolverContext(ctx)
-			rctx.PushIndex(idx1)
-			defer rctx.Pop()
-			return res[idx1]
-		}())
+		if err != nil {
+			ec.Error(ctx, err)
+			return graphql.Null
+		}
 	}
-	return arr1
-}
-
-var labelChangeOperationImplementors = []string{"LabelChangeOperation", "Operation", "Authored"}
-
-// nolint: gocyclo, errcheck, gas, goconst
-func (ec *executionContext) _LabelChangeOperation(ctx context.Context, sel []query.Selection, obj *operations.LabelChangeOperation) graphql.Marshaler {
-	fields := graphql.CollectFields(ec.Doc, sel, labelChangeOperationImplementors, ec.Variables)
-
-	out := graphql.NewOrderedMap(len(fields))
-	for i, field := range fields {
-		out.Keys[i] = field.Alias
+	args["first"] = arg2
+	var arg3 *int
+	if tmp, ok := field.Args["last"]; ok {
+		var err error
+		var ptr1 int
+		if tmp != nil {
+			ptr1, err = graphql.UnmarshalInt(tmp)
+			arg3 = &ptr1
+		}
 
-		switch field.Name {
-		case "__typename":
-			out.Values[i] = graphql.MarshalString("LabelChangeOperation")
-		case "author":
-			out.Values[i] = ec._LabelChangeOperation_author(ctx, field, obj)
-		case "date":
-			out.Values[i] = ec._LabelChangeOperation_date(ctx, field, obj)
-		case "added":
-			out.Values[i] = ec._LabelChangeOperation_added(ctx, field, obj)
-		case "removed":
-			out.Values[i] = ec._LabelChangeOperation_removed(ctx, field, obj)
-		default:
-			panic("unknown field " + strconv.Quote(field.Name))
+		if err != nil {
+			ec.Error(ctx, err)
+			return graphql.Null
 		}
 	}
-
-	return out
-}
-
-func (ec *executionContext) _LabelChangeOperation_author(ctx context.Context, field graphql.CollectedField, obj *operations.LabelChangeOperation) graphql.Marshaler {
-	rctx := graphql.GetResolverContext(ctx)
-	rctx.Object = "LabelChangeOperation"
-	rctx.Args = nil
-	rctx.Field = field
-	rctx.PushField(field.Alias)
-	defer rctx.Pop()
-	res := obj.Author
-	return ec._Person(ctx, field.Selections, &res)
-}
-
-func (ec *executionContext) _LabelChangeOperation_date(ctx context.Context, field graphql.CollectedField, obj *operations.LabelChangeOperation) graphql.Marshaler {
+	args["last"] = arg3
 	ctx = graphql.WithResolverContext(ctx, &graphql.ResolverContext{
-		Object: "LabelChangeOperation",
-		Args:   nil,
+		Object: "Bug",
+		Args:   args,
 		Field:  field,
 	})
 	return graphql.Defer(func() (ret graphql.Marshaler) {
@@ -1201,7 +1305,7 @@ func (ec *executionContext) _LabelChangeOperation_date(ctx context.Context, fiel
 		}()
 
 		resTmp, err := ec.ResolverMiddleware(ctx, func(ctx context.Context) (interface{}, error) {
-			return ec.resolvers.LabelChangeOperation_date(ctx, obj)
+			return ec.resolvers.Bug_comments(ctx, obj, args["after"].(*string), args["before"].(*string), args["first"].(*int), args["last"].(*int))
 		})
 		if err != nil {
 			ec.Error(ctx, err)
@@ -1210,94 +1314,15 @@ func (ec *executionContext) _LabelChangeOperation_date(ctx context.Context, fiel
 		if resTmp == nil {
 			return graphql.Null
 		}
-		res := resTmp.(time.Time)
-		return graphql.MarshalTime(res)
+		res := resTmp.(models.CommentConnection)
+		return ec._CommentConnection(ctx, field.Selections, &res)
 	})
 }
 
-func (ec *executionContext) _LabelChangeOperation_added(ctx context.Context, field graphql.CollectedField, obj *operations.LabelChangeOperation) graphql.Marshaler {
-	rctx := graphql.GetResolverContext(ctx)
-	rctx.Object = "LabelChangeOperation"
-	rctx.Args = nil
-	rctx.Field = field
-	rctx.PushField(field.Alias)
-	defer rctx.Pop()
-	res := obj.Added
-	arr1 := graphql.Array{}
-	for idx1 := range res {
-		arr1 = append(arr1, func() graphql.Marshaler {
-			rctx := graphql.GetResolverContext(ctx)
-			rctx.PushIndex(idx1)
-			defer rctx.Pop()
-			return res[idx1]
-		}())
-	}
-	return arr1
-}
-
-func (ec *executionContext) _LabelChangeOperation_removed(ctx context.Context, field graphql.CollectedField, obj *operations.LabelChangeOperation) graphql.Marshaler {
-	rctx := graphql.GetResolverContext(ctx)
-	rctx.Object = "LabelChangeOperation"
-	rctx.Args = nil
-	rctx.Field = field
-	rctx.PushField(field.Alias)
-	defer rctx.Pop()
-	res := obj.Removed
-	arr1 := graphql.Array{}
-	for idx1 := range res {
-		arr1 = append(arr1, func() graphql.Marshaler {
-			rctx := graphql.GetResolverContext(ctx)
-			rctx.PushIndex(idx1)
-			defer rctx.Pop()
-			return res[idx1]
-		}())
-	}
-	return arr1
-}
-
-var mutationImplementors = []string{"Mutation"}
-
-// nolint: gocyclo, errcheck, gas, goconst
-func (ec *executionContext) _Mutation(ctx context.Context, sel []query.Selection) graphql.Marshaler {
-	fields := graphql.CollectFields(ec.Doc, sel, mutationImplementors, ec.Variables)
-
-	ctx = graphql.WithResolverContext(ctx, &graphql.ResolverContext{
-		Object: "Mutation",
-	})
-
-	out := graphql.NewOrderedMap(len(fields))
-	for i, field := range fields {
-		out.Keys[i] = field.Alias
-
-		switch field.Name {
-		case "__typename":
-			out.Values[i] = graphql.MarshalString("Mutation")
-		case "newBug":
-			out.Values[i] = ec._Mutation_newBug(ctx, field)
-		case "addComment":
-			out.Values[i] = ec._Mutation_addComment(ctx, field)
-		case "changeLabels":
-			out.Values[i] = ec._Mutation_changeLabels(ctx, field)
-		case "open":
-			out.Values[i] = ec._Mutation_open(ctx, field)
-		case "close":
-			out.Values[i] = ec._Mutation_close(ctx, field)
-		case "setTitle":
-			out.Values[i] = ec._Mutation_setTitle(ctx, field)
-		case "commit":
-			out.Values[i] = ec._Mutation_commit(ctx, field)
-		default:
-			panic("unknown field " + strconv.Quote(field.Name))
-		}
-	}
-
-	return out
-}
-
-func (ec *executionContext) _Mutation_newBug(ctx context.Context, field graphql.CollectedField) graphql.Marshaler {
+func (ec *executionContext) _Bug_operations(ctx context.Context, field graphql.CollectedField, obj *bug.Snapshot) graphql.Marshaler {
 	args := map[string]interface{}{}
 	var arg0 *string
-	if tmp, ok := field.Args["repoRef"]; ok {
+	if tmp, ok := field.Args["after"]; ok {
 		var err error
 		var ptr1 string
 		if tmp != nil {
@@ -1310,75 +1335,44 @@ func (ec *executionContext) _Mutation_newBug(ctx context.Context, field graphql.
 			return graphql.Null
 		}
 	}
-	args["repoRef"] = arg0
-	var arg1 string
-	if tmp, ok := field.Args["title"]; ok {
+	args["after"] = arg0
+	var arg1 *string
+	if tmp, ok := field.Args["before"]; ok {
 		var err error
-		arg1, err = graphql.UnmarshalString(tmp)
-		if err != nil {
-			ec.Error(ctx, err)
-			return graphql.Null
+		var ptr1 string
+		if tmp != nil {
+			ptr1, err = graphql.UnmarshalString(tmp)
+			arg1 = &ptr1
 		}
-	}
-	args["title"] = arg1
-	var arg2 string
-	if tmp, ok := field.Args["message"]; ok {
-		var err error
-		arg2, err = graphql.UnmarshalString(tmp)
+
 		if err != nil {
 			ec.Error(ctx, err)
 			return graphql.Null
 		}
 	}
-	args["message"] = arg2
-	var arg3 []util.Hash
-	if tmp, ok := field.Args["files"]; ok {
+	args["before"] = arg1
+	var arg2 *int
+	if tmp, ok := field.Args["first"]; ok {
 		var err error
-		var rawIf1 []interface{}
+		var ptr1 int
 		if tmp != nil {
-			if tmp1, ok := tmp.([]interface{}); ok {
-				rawIf1 = tmp1
-			}
-		}
-		arg3 = make([]util.Hash, len(rawIf1))
-		for idx1 := range rawIf1 {
-			err = (&arg3[idx1]).UnmarshalGQL(rawIf1[idx1])
+			ptr1, err = graphql.UnmarshalInt(tmp)
+			arg2 = &ptr1
 		}
+
 		if err != nil {
 			ec.Error(ctx, err)
 			return graphql.Null
 		}
 	}
-	args["files"] = arg3
-	rctx := graphql.GetResolverContext(ctx)
-	rctx.Object = "Mutation"
-	rctx.Args = args
-	rctx.Field = field
-	rctx.PushField(field.Alias)
-	defer rctx.Pop()
-	resTmp, err := ec.ResolverMiddleware(ctx, func(ctx context.Context) (interface{}, error) {
-		return ec.resolvers.Mutation_newBug(ctx, args["repoRef"].(*string), args["title"].(string), args["message"].(string), args["files"].([]util.Hash))
-	})
-	if err != nil {
-		ec.Error(ctx, err)
-		return graphql.Null
-	}
-	if resTmp == nil {
-		return graphql.Null
-	}
-	res := resTmp.(bug.Snapshot)
-	return ec._Bug(ctx, field.Selections, &res)
-}
-
-func (ec *executionContext) _Mutation_addComment(ctx context.Context, field graphql.CollectedField) graphql.Marshaler {
-	args := map[string]interface{}{}
-	var arg0 *string
-	if tmp, ok := field.Args["repoRef"]; ok {
+	args["first"] = arg2
+	var arg3 *int
+	if tmp, ok := field.Args["last"]; ok {
 		var err error
-		var ptr1 string
+		var ptr1 int
 		if tmp != nil {
-			ptr1, err = graphql.UnmarshalString(tmp)
-			arg0 = &ptr1
+			ptr1, err = graphql.UnmarshalInt(tmp)
+			arg3 = &ptr1
 		}
 
 		if err != nil {
@@ -1386,78 +1380,1416 @@ func (ec *executionContext) _Mutation_addComment(ctx context.Context, field grap
 			return graphql.Null
 		}
 	}
-	args["repoRef"] = arg0
-	var arg1 string
-	if tmp, ok := field.Args["prefix"]; ok {
-		var err error
-		arg1, err = graphql.UnmarshalString(tmp)
+	args["last"] = arg3
+	ctx = graphql.WithResolverContext(ctx, &graphql.ResolverContext{
+		Object: "Bug",
+		Args:   args,
+		Field:  field,
+	})
+	return graphql.Defer(func() (ret graphql.Marshaler) {
+		defer func() {
+			if r := recover(); r != nil {
+				userErr := ec.Recover(ctx, r)
+				ec.Error(ctx, userErr)
+				ret = graphql.Null
+			}
+		}()
+
+		resTmp, err := ec.ResolverMiddleware(ctx, func(ctx context.Context) (interface{}, error) {
+			return ec.resolvers.Bug_operations(ctx, obj, args["after"].(*string), args["before"].(*string), args["first"].(*int), args["last"].(*int))
+		})
 		if err != nil {
 			ec.Error(ctx, err)
 			return graphql.Null
 		}
-	}
-	args["prefix"] = arg1
-	var arg2 string
-	if tmp, ok := field.Args["message"]; ok {
-		var err error
-		arg2, err = graphql.UnmarshalString(tmp)
-		if err != nil {
-			ec.Error(ctx, err)
+		if resTmp == nil {
 			return graphql.Null
 		}
-	}
-	args["message"] = arg2
-	var arg3 []util.Hash
-	if tmp, ok := field.Args["files"]; ok {
-		var err error
-		var rawIf1 []interface{}
-		if tmp != nil {
-			if tmp1, ok := tmp.([]interface{}); ok {
-				rawIf1 = tmp1
-			}
-		}
-		arg3 = make([]util.Hash, len(rawIf1))
-		for idx1 := range rawIf1 {
-			err = (&arg3[idx1]).UnmarshalGQL(rawIf1[idx1])
-		}
-		if err != nil {
-			ec.Error(ctx, err)
-			return graphql.Null
+		res := resTmp.(models.OperationConnection)
+		return ec._OperationConnection(ctx, field.Selections, &res)
+	})
+}
+
+var bugConnectionImplementors = []string{"BugConnection"}
+
+// nolint: gocyclo, errcheck, gas, goconst
+func (ec *executionContext) _BugConnection(ctx context.Context, sel []query.Selection, obj *models.BugConnection) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.Doc, sel, bugConnectionImplementors, ec.Variables)
+
+	out := graphql.NewOrderedMap(len(fields))
+	for i, field := range fields {
+		out.Keys[i] = field.Alias
+
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("BugConnection")
+		case "edges":
+			out.Values[i] = ec._BugConnection_edges(ctx, field, obj)
+		case "nodes":
+			out.Values[i] = ec._BugConnection_nodes(ctx, field, obj)
+		case "pageInfo":
+			out.Values[i] = ec._BugConnection_pageInfo(ctx, field, obj)
+		case "totalCount":
+			out.Values[i] = ec._BugConnection_totalCount(ctx, field, obj)
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
 		}
 	}
-	args["files"] = arg3
+
+	return out
+}
+
+func (ec *executionContext) _BugConnection_edges(ctx context.Context, field graphql.CollectedField, obj *models.BugConnection) graphql.Marshaler {
 	rctx := graphql.GetResolverContext(ctx)
-	rctx.Object = "Mutation"
-	rctx.Args = args
+	rctx.Object = "BugConnection"
+	rctx.Args = nil
 	rctx.Field = field
 	rctx.PushField(field.Alias)
 	defer rctx.Pop()
-	resTmp, err := ec.ResolverMiddleware(ctx, func(ctx context.Context) (interface{}, error) {
-		return ec.resolvers.Mutation_addComment(ctx, args["repoRef"].(*string), args["prefix"].(string), args["message"].(string), args["files"].([]util.Hash))
-	})
-	if err != nil {
-		ec.Error(ctx, err)
-		return graphql.Null
-	}
-	if resTmp == nil {
-		return graphql.Null
+	res := obj.Edges
+	arr1 := graphql.Array{}
+	for idx1 := range res {
+		arr1 = append(arr1, func() graphql.Marshaler {
+			rctx := graphql.GetResolverContext(ctx)
+			rctx.PushIndex(idx1)
+			defer rctx.Pop()
+			return ec._BugEdge(ctx, field.Selections, &res[idx1])
+		}())
 	}
-	res := resTmp.(bug.Snapshot)
-	return ec._Bug(ctx, field.Selections, &res)
+	return arr1
 }
 
-func (ec *executionContext) _Mutation_changeLabels(ctx context.Context, field graphql.CollectedField) graphql.Marshaler {
-	args := map[string]interface{}{}
-	var arg0 *string
-	if tmp, ok := field.Args["repoRef"]; ok {
-		var err error
-		var ptr1 string
-		if tmp != nil {
-			ptr1, err = graphql.UnmarshalString(tmp)
-			arg0 = &ptr1
-		}
-
-		if err != nil {
+func (ec *executionContext) _BugConnection_nodes(ctx context.Context, field graphql.CollectedField, obj *models.BugConnection) graphql.Marshaler {
+	rctx := graphql.GetResolverContext(ctx)
+	rctx.Object = "BugConnection"
+	rctx.Args = nil
+	rctx.Field = field
+	rctx.PushField(field.Alias)
+	defer rctx.Pop()
+	res := obj.Nodes
+	arr1 := graphql.Array{}
+	for idx1 := range res {
+		arr1 = append(arr1, func() graphql.Marshaler {
+			rctx := graphql.GetResolverContext(ctx)
+			rctx.PushIndex(idx1)
+			defer rctx.Pop()
+			return ec._Bug(ctx, field.Selections, &res[idx1])
+		}())
+	}
+	return arr1
+}
+
+func (ec *executionContext) _BugConnection_pageInfo(ctx context.Context, field graphql.CollectedField, obj *models.BugConnection) graphql.Marshaler {
+	rctx := graphql.GetResolverContext(ctx)
+	rctx.Object = "BugConnection"
+	rctx.Args = nil
+	rctx.Field = field
+	rctx.PushField(field.Alias)
+	defer rctx.Pop()
+	res := obj.PageInfo
+	return ec._PageInfo(ctx, field.Selections, &res)
+}
+
+func (ec *executionContext) _BugConnection_totalCount(ctx context.Context, field graphql.CollectedField, obj *models.BugConnection) graphql.Marshaler {
+	rctx := graphql.GetResolverContext(ctx)
+	rctx.Object = "BugConnection"
+	rctx.Args = nil
+	rctx.Field = field
+	rctx.PushField(field.Alias)
+	defer rctx.Pop()
+	res := obj.TotalCount
+	return graphql.MarshalInt(res)
+}
+
+var bugEdgeImplementors = []string{"BugEdge"}
+
+// nolint: gocyclo, errcheck, gas, goconst
+func (ec *executionContext) _BugEdge(ctx context.Context, sel []query.Selection, obj *models.BugEdge) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.Doc, sel, bugEdgeImplementors, ec.Variables)
+
+	out := graphql.NewOrderedMap(len(fields))
+	for i, field := range fields {
+		out.Keys[i] = field.Alias
+
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("BugEdge")
+		case "cursor":
+			out.Values[i] = ec._BugEdge_cursor(ctx, field, obj)
+		case "node":
+			out.Values[i] = ec._BugEdge_node(ctx, field, obj)
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+
+	return out
+}
+
+func (ec *executionContext) _BugEdge_cursor(ctx context.Context, field graphql.CollectedField, obj *models.BugEdge) graphql.Marshaler {
+	rctx := graphql.GetResolverContext(ctx)
+	rctx.Object = "BugEdge"
+	rctx.Args = nil
+	rctx.Field = field
+	rctx.PushField(field.Alias)
+	defer rctx.Pop()
+	res := obj.Cursor
+	return graphql.MarshalString(res)
+}
+
+func (ec *executionContext) _BugEdge_node(ctx context.Context, field graphql.CollectedField, obj *models.BugEdge) graphql.Marshaler {
+	rctx := graphql.GetResolverContext(ctx)
+	rctx.Object = "BugEdge"
+	rctx.Args = nil
+	rctx.Field = field
+	rctx.PushField(field.Alias)
+	defer rctx.Pop()
+	res := obj.Node
+	return ec._Bug(ctx, field.Selections, &res)
+}
+
+var checklistImplementors = []string{"Checklist"}
+
+// nolint: gocyclo, errcheck, gas, goconst
+func (ec *executionContext) _Checklist(ctx context.Context, sel []query.Selection, obj *bug.Checklist) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.Doc, sel, checklistImplementors, ec.Variables)
+
+	out := graphql.NewOrderedMap(len(fields))
+	for i, field := range fields {
+		out.Keys[i] = field.Alias
+
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("Checklist")
+		case "name":
+			out.Values[i] = ec._Checklist_name(ctx, field, obj)
+		case "items":
+			out.Values[i] = ec._Checklist_items(ctx, field, obj)
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+
+	return out
+}
+
+func (ec *executionContext) _Checklist_name(ctx context.Context, field graphql.CollectedField, obj *bug.Checklist) graphql.Marshaler {
+	rctx := graphql.GetResolverContext(ctx)
+	rctx.Object = "Checklist"
+	rctx.Args = nil
+	rctx.Field = field
+	rctx.PushField(field.Alias)
+	defer rctx.Pop()
+	res := obj.Name
+	return graphql.MarshalString(res)
+}
+
+func (ec *executionContext) _Checklist_items(ctx context.Context, field graphql.CollectedField, obj *bug.Checklist) graphql.Marshaler {
+	rctx := graphql.GetResolverContext(ctx)
+	rctx.Object = "Checklist"
+	rctx.Args = nil
+	rctx.Field = field
+	rctx.PushField(field.Alias)
+	defer rctx.Pop()
+	res := obj.Items
+	arr1 := graphql.Array{}
+	for idx1 := range res {
+		arr1 = append(arr1, func() graphql.Marshaler {
+			rctx := graphql.GetResolverContext(ctx)
+			rctx.PushIndex(idx1)
+			defer rctx.Pop()
+			return ec._ChecklistItem(ctx, field.Selections, &res[idx1])
+		}())
+	}
+	return arr1
+}
+
+var checklistItemImplementors = []string{"ChecklistItem"}
+
+// nolint: gocyclo, errcheck, gas, goconst
+func (ec *executionContext) _ChecklistItem(ctx context.Context, sel []query.Selection, obj *bug.ChecklistItem) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.Doc, sel, checklistItemImplementors, ec.Variables)
+
+	out := graphql.NewOrderedMap(len(fields))
+	for i, field := range fields {
+		out.Keys[i] = field.Alias
+
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("ChecklistItem")
+		case "name":
+			out.Values[i] = ec._ChecklistItem_name(ctx, field, obj)
+		case "state":
+			out.Values[i] = ec._ChecklistItem_state(ctx, field, obj)
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+
+	return out
+}
+
+func (ec *executionContext) _ChecklistItem_name(ctx context.Context, field graphql.CollectedField, obj *bug.ChecklistItem) graphql.Marshaler {
+	rctx := graphql.GetResolverContext(ctx)
+	rctx.Object = "ChecklistItem"
+	rctx.Args = nil
+	rctx.Field = field
+	rctx.PushField(field.Alias)
+	defer rctx.Pop()
+	res := obj.Name
+	return graphql.MarshalString(res)
+}
+
+func (ec *executionContext) _ChecklistItem_state(ctx context.Context, field graphql.CollectedField, obj *bug.ChecklistItem) graphql.Marshaler {
+	ctx = graphql.WithResolverContext(ctx, &graphql.ResolverContext{
+		Object: "ChecklistItem",
+		Args:   nil,
+		Field:  field,
+	})
+	return graphql.Defer(func() (ret graphql.Marshaler) {
+		defer func() {
+			if r := recover(); r != nil {
+				userErr := ec.Recover(ctx, r)
+				ec.Error(ctx, userErr)
+				ret = graphql.Null
+			}
+		}()
+
+		resTmp, err := ec.ResolverMiddleware(ctx, func(ctx context.Context) (interface{}, error) {
+			return ec.resolvers.ChecklistItem_state(ctx, obj)
+		})
+		if err != nil {
+			ec.Error(ctx, err)
+			return graphql.Null
+		}
+		if resTmp == nil {
+			return graphql.Null
+		}
+		res := resTmp.(models.ChecklistState)
+		return res
+	})
+}
+
+var commentImplementors = []string{"Comment", "Authored"}
+
+// nolint: gocyclo, errcheck, gas, goconst
+func (ec *executionContext) _Comment(ctx context.Context, sel []query.Selection, obj *bug.Comment) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.Doc, sel, commentImplementors, ec.Variables)
+
+	out := graphql.NewOrderedMap(len(fields))
+	for i, field := range fields {
+		out.Keys[i] = field.Alias
+
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("Comment")
+		case "author":
+			out.Values[i] = ec._Comment_author(ctx, field, obj)
+		case "message":
+			out.Values[i] = ec._Comment_message(ctx, field, obj)
+		case "messageHtml":
+			out.Values[i] = ec._Comment_messageHtml(ctx, field, obj)
+		case "files":
+			out.Values[i] = ec._Comment_files(ctx, field, obj)
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+
+	return out
+}
+
+func (ec *executionContext) _Comment_author(ctx context.Context, field graphql.CollectedField, obj *bug.Comment) graphql.Marshaler {
+	rctx := graphql.GetResolverContext(ctx)
+	rctx.Object = "Comment"
+	rctx.Args = nil
+	rctx.Field = field
+	rctx.PushField(field.Alias)
+	defer rctx.Pop()
+	res := obj.Author
+	return ec._Person(ctx, field.Selections, &res)
+}
+
+func (ec *executionContext) _Comment_message(ctx context.Context, field graphql.CollectedField, obj *bug.Comment) graphql.Marshaler {
+	ctx = graphql.WithResolverContext(ctx, &graphql.ResolverContext{
+		Object: "Comment",
+		Args:   nil,
+		Field:  field,
+	})
+	return graphql.Defer(func() (ret graphql.Marshaler) {
+		defer func() {
+			if r := recover(); r != nil {
+				userErr := ec.Recover(ctx, r)
+				ec.Error(ctx, userErr)
+				ret = graphql.Null
+			}
+		}()
+
+		resTmp, err := ec.ResolverMiddleware(ctx, func(ctx context.Context) (interface{}, error) {
+			return ec.resolvers.Comment_message(ctx, obj)
+		})
+		if err != nil {
+			ec.Error(ctx, err)
+			return graphql.Null
+		}
+		if resTmp == nil {
+			return graphql.Null
+		}
+		res := resTmp.(string)
+		return graphql.MarshalString(res)
+	})
+}
+
+func (ec *executionContext) _Comment_messageHtml(ctx context.Context, field graphql.CollectedField, obj *bug.Comment) graphql.Marshaler {
+	ctx = graphql.WithResolverContext(ctx, &graphql.ResolverContext{
+		Object: "Comment",
+		Args:   nil,
+		Field:  field,
+	})
+	return graphql.Defer(func() (ret graphql.Marshaler) {
+		defer func() {
+			if r := recover(); r != nil {
+				userErr := ec.Recover(ctx, r)
+				ec.Error(ctx, userErr)
+				ret = graphql.Null
+			}
+		}()
+
+		resTmp, err := ec.ResolverMiddleware(ctx, func(ctx context.Context) (interface{}, error) {
+			return ec.resolvers.Comment_messageHtml(ctx, obj)
+		})
+		if err != nil {
+			ec.Error(ctx, err)
+			return graphql.Null
+		}
+		if resTmp == nil {
+			return graphql.Null
+		}
+		res := resTmp.(string)
+		return graphql.MarshalString(res)
+	})
+}
+
+func (ec *executionContext) _Comment_files(ctx context.Context, field graphql.CollectedField, obj *bug.Comment) graphql.Marshaler {
+	rctx := graphql.GetResolverContext(ctx)
+	rctx.Object = "Comment"
+	rctx.Args = nil
+	rctx.Field = field
+	rctx.PushField(field.Alias)
+	defer rctx.Pop()
+	res := obj.Files
+	arr1 := graphql.Array{}
+	for idx1 := range res {
+		arr1 = append(arr1, func() graphql.Marshaler {
+			rctx := graphql.GetResolverContext(ctx)
+			rctx.PushIndex(idx1)
+			defer rctx.Pop()
+			return res[idx1]
+		}())
+	}
+	return arr1
+}
+
+var commentConnectionImplementors = []string{"CommentConnection"}
+
+// nolint: gocyclo, errcheck, gas, goconst
+func (ec *executionContext) _CommentConnection(ctx context.Context, sel []query.Selection, obj *models.CommentConnection) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.Doc, sel, commentConnectionImplementors, ec.Variables)
+
+	out := graphql.NewOrderedMap(len(fields))
+	for i, field := range fields {
+		out.Keys[i] = field.Alias
+
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("CommentConnection")
+		case "edges":
+			out.Values[i] = ec._CommentConnection_edges(ctx, field, obj)
+		case "nodes":
+			out.Values[i] = ec._CommentConnection_nodes(ctx, field, obj)
+		case "pageInfo":
+			out.Values[i] = ec._CommentConnection_pageInfo(ctx, field, obj)
+		case "totalCount":
+			out.Values[i] = ec._CommentConnection_totalCount(ctx, field, obj)
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+
+	return out
+}
+
+func (ec *executionContext) _CommentConnection_edges(ctx context.Context, field graphql.CollectedField, obj *models.CommentConnection) graphql.Marshaler {
+	rctx := graphql.GetResolverContext(ctx)
+	rctx.Object = "CommentConnection"
+	rctx.Args = nil
+	rctx.Field = field
+	rctx.PushField(field.Alias)
+	defer rctx.Pop()
+	res := obj.Edges
+	arr1 := graphql.Array{}
+	for idx1 := range res {
+		arr1 = append(arr1, func() graphql.Marshaler {
+			rctx := graphql.GetResolverContext(ctx)
+			rctx.PushIndex(idx1)
+			defer rctx.Pop()
+			return ec._CommentEdge(ctx, field.Selections, &res[idx1])
+		}())
+	}
+	return arr1
+}
+
+func (ec *executionContext) _CommentConnection_nodes(ctx context.Context, field graphql.CollectedField, obj *models.CommentConnection) graphql.Marshaler {
+	rctx := graphql.GetResolverContext(ctx)
+	rctx.Object = "CommentConnection"
+	rctx.Args = nil
+	rctx.Field = field
+	rctx.PushField(field.Alias)
+	defer rctx.Pop()
+	res := obj.Nodes
+	arr1 := graphql.Array{}
+	for idx1 := range res {
+		arr1 = append(arr1, func() graphql.Marshaler {
+			rctx := graphql.GetResolverContext(ctx)
+			rctx.PushIndex(idx1)
+			defer rctx.Pop()
+			return ec._Comment(ctx, field.Selections, &res[idx1])
+		}())
+	}
+	return arr1
+}
+
+func (ec *executionContext) _CommentConnection_pageInfo(ctx context.Context, field graphql.CollectedField, obj *models.CommentConnection) graphql.Marshaler {
+	rctx := graphql.GetResolverContext(ctx)
+	rctx.Object = "CommentConnection"
+	rctx.Args = nil
+	rctx.Field = field
+	rctx.PushField(field.Alias)
+	defer rctx.Pop()
+	res := obj.PageInfo
+	return ec._PageInfo(ctx, field.Selections, &res)
+}
+
+func (ec *executionContext) _CommentConnection_totalCount(ctx context.Context, field graphql.CollectedField, obj *models.CommentConnection) graphql.Marshaler {
+	rctx := graphql.GetResolverContext(ctx)
+	rctx.Object = "CommentConnection"
+	rctx.Args = nil
+	rctx.Field = field
+	rctx.PushField(field.Alias)
+	defer rctx.Pop()
+	res := obj.TotalCount
+	return graphql.MarshalInt(res)
+}
+
+var commentEdgeImplementors = []string{"CommentEdge"}
+
+// nolint: gocyclo, errcheck, gas, goconst
+func (ec *executionContext) _CommentEdge(ctx context.Context, sel []query.Selection, obj *models.CommentEdge) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.Doc, sel, commentEdgeImplementors, ec.Variables)
+
+	out := graphql.NewOrderedMap(len(fields))
+	for i, field := range fields {
+		out.Keys[i] = field.Alias
+
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("CommentEdge")
+		case "cursor":
+			out.Values[i] = ec._CommentEdge_cursor(ctx, field, obj)
+		case "node":
+			out.Values[i] = ec._CommentEdge_node(ctx, field, obj)
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+
+	return out
+}
+
+func (ec *executionContext) _CommentEdge_cursor(ctx context.Context, field graphql.CollectedField, obj *models.CommentEdge) graphql.Marshaler {
+	rctx := graphql.GetResolverContext(ctx)
+	rctx.Object = "CommentEdge"
+	rctx.Args = nil
+	rctx.Field = field
+	rctx.PushField(field.Alias)
+	defer rctx.Pop()
+	res := obj.Cursor
+	return graphql.MarshalString(res)
+}
+
+func (ec *executionContext) _CommentEdge_node(ctx context.Context, field graphql.CollectedField, obj *models.CommentEdge) graphql.Marshaler {
+	rctx := graphql.GetResolverContext(ctx)
+	rctx.Object = "CommentEdge"
+	rctx.Args = nil
+	rctx.Field = field
+	rctx.PushField(field.Alias)
+	defer rctx.Pop()
+	res := obj.Node
+	return ec._Comment(ctx, field.Selections, &res)
+}
+
+var createOperationImplementors = []string{"CreateOperation", "Operation", "Authored"}
+
+// nolint: gocyclo, errcheck, gas, goconst
+func (ec *executionContext) _CreateOperation(ctx context.Context, sel []query.Selection, obj *operations.CreateOperation) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.Doc, sel, createOperationImplementors, ec.Variables)
+
+	out := graphql.NewOrderedMap(len(fields))
+	for i, field := range fields {
+		out.Keys[i] = field.Alias
+
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("CreateOperation")
+		case "author":
+			out.Values[i] = ec._CreateOperation_author(ctx, field, obj)
+		case "date":
+			out.Values[i] = ec._CreateOperation_date(ctx, field, obj)
+		case "title":
+			out.Values[i] = ec._CreateOperation_title(ctx, field, obj)
+		case "message":
+			out.Values[i] = ec._CreateOperation_message(ctx, field, obj)
+		case "messageHtml":
+			out.Values[i] = ec._CreateOperation_messageHtml(ctx, field, obj)
+		case "files":
+			out.Values[i] = ec._CreateOperation_files(ctx, field, obj)
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+
+	return out
+}
+
+func (ec *executionContext) _CreateOperation_author(ctx context.Context, field graphql.CollectedField, obj *operations.CreateOperation) graphql.Marshaler {
+	rctx := graphql.GetResolverContext(ctx)
+	rctx.Object = "CreateOperation"
+	rctx.Args = nil
+	rctx.Field = field
+	rctx.PushField(field.Alias)
+	defer rctx.Pop()
+	res := obj.Author
+	return ec._Person(ctx, field.Selections, &res)
+}
+
+func (ec *executionContext) _CreateOperation_date(ctx context.Context, field graphql.CollectedField, obj *operations.CreateOperation) graphql.Marshaler {
+	ctx = graphql.WithResolverContext(ctx, &graphql.ResolverContext{
+		Object: "CreateOperation",
+		Args:   nil,
+		Field:  field,
+	})
+	return graphql.Defer(func() (ret graphql.Marshaler) {
+		defer func() {
+			if r := recover(); r != nil {
+				userErr := ec.Recover(ctx, r)
+				ec.Error(ctx, userErr)
+				ret = graphql.Null
+			}
+		}()
+
+		resTmp, err := ec.ResolverMiddleware(ctx, func(ctx context.Context) (interface{}, error) {
+			return ec.resolvers.CreateOperation_date(ctx, obj)
+		})
+		if err != nil {
+			ec.Error(ctx, err)
+			return graphql.Null
+		}
+		if resTmp == nil {
+			return graphql.Null
+		}
+		res := resTmp.(time.Time)
+		return graphql.MarshalTime(res)
+	})
+}
+
+func (ec *executionContext) _CreateOperation_title(ctx context.Context, field graphql.CollectedField, obj *operations.CreateOperation) graphql.Marshaler {
+	rctx := graphql.GetResolverContext(ctx)
+	rctx.Object = "CreateOperation"
+	rctx.Args = nil
+	rctx.Field = field
+	rctx.PushField(field.Alias)
+	defer rctx.Pop()
+	res := obj.Title
+	return graphql.MarshalString(res)
+}
+
+func (ec *executionContext) _CreateOperation_message(ctx context.Context, field graphql.CollectedField, obj *operations.CreateOperation) graphql.Marshaler {
+	ctx = graphql.WithResolverContext(ctx, &graphql.ResolverContext{
+		Object: "CreateOperation",
+		Args:   nil,
+		Field:  field,
+	})
+	return graphql.Defer(func() (ret graphql.Marshaler) {
+		defer func() {
+			if r := recover(); r != nil {
+				userErr := ec.Recover(ctx, r)
+				ec.Error(ctx, userErr)
+				ret = graphql.Null
+			}
+		}()
+
+		resTmp, err := ec.ResolverMiddleware(ctx, func(ctx context.Context) (interface{}, error) {
+			return ec.resolvers.CreateOperation_message(ctx, obj)
+		})
+		if err != nil {
+			ec.Error(ctx, err)
+			return graphql.Null
+		}
+		if resTmp == nil {
+			return graphql.Null
+		}
+		res := resTmp.(string)
+		return graphql.MarshalString(res)
+	})
+}
+
+func (ec *executionContext) _CreateOperation_messageHtml(ctx context.Context, field graphql.CollectedField, obj *operations.CreateOperation) graphql.Marshaler {
+	ctx = graphql.WithResolverContext(ctx, &graphql.ResolverContext{
+		Object: "CreateOperation",
+		Args:   nil,
+		Field:  field,
+	})
+	return graphql.Defer(func() (ret graphql.Marshaler) {
+		defer func() {
+			if r := recover(); r != nil {
+				userErr := ec.Recover(ctx, r)
+				ec.Error(ctx, userErr)
+				ret = graphql.Null
+			}
+		}()
+
+		resTmp, err := ec.ResolverMiddleware(ctx, func(ctx context.Context) (interface{}, error) {
+			return ec.resolvers.CreateOperation_messageHtml(ctx, obj)
+		})
+		if err != nil {
+			ec.Error(ctx, err)
+			return graphql.Null
+		}
+		if resTmp == nil {
+			return graphql.Null
+		}
+		res := resTmp.(string)
+		return graphql.MarshalString(res)
+	})
+}
+
+func (ec *executionContext) _CreateOperation_files(ctx context.Context, field graphql.CollectedField, obj *operations.CreateOperation) graphql.Marshaler {
+	rctx := graphql.GetResolverContext(ctx)
+	rctx.Object = "CreateOperation"
+	rctx.Args = nil
+	rctx.Field = field
+	rctx.PushField(field.Alias)
+	defer rctx.Pop()
+	res := obj.Files()
+	arr1 := graphql.Array{}
+	for idx1 := range res {
+		arr1 = append(arr1, func() graphql.Marshaler {
+			rctx := graphql.GetResolverContext(ctx)
+			rctx.PushIndex(idx1)
+			defer rctx.Pop()
+			return res[idx1]
+		}())
+	}
+	return arr1
+}
+
+var customFieldImplementors = []string{"CustomField"}
+
+// nolint: gocyclo, errcheck, gas, goconst
+func (ec *executionContext) _CustomField(ctx context.Context, sel []query.Selection, obj *models.CustomField) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.Doc, sel, customFieldImplementors, ec.Variables)
+
+	out := graphql.NewOrderedMap(len(fields))
+	for i, field := range fields {
+		out.Keys[i] = field.Alias
+
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("CustomField")
+		case "key":
+			out.Values[i] = ec._CustomField_key(ctx, field, obj)
+		case "value":
+			out.Values[i] = ec._CustomField_value(ctx, field, obj)
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+
+	return out
+}
+
+func (ec *executionContext) _CustomField_key(ctx context.Context, field graphql.CollectedField, obj *models.CustomField) graphql.Marshaler {
+	rctx := graphql.GetResolverContext(ctx)
+	rctx.Object = "CustomField"
+	rctx.Args = nil
+	rctx.Field = field
+	rctx.PushField(field.Alias)
+	defer rctx.Pop()
+	res := obj.Key
+	return graphql.MarshalString(res)
+}
+
+func (ec *executionContext) _CustomField_value(ctx context.Context, field graphql.CollectedField, obj *models.CustomField) graphql.Marshaler {
+	rctx := graphql.GetResolverContext(ctx)
+	rctx.Object = "CustomField"
+	rctx.Args = nil
+	rctx.Field = field
+	rctx.PushField(field.Alias)
+	defer rctx.Pop()
+	res := obj.Value
+	return graphql.MarshalString(res)
+}
+
+var deleteCommentOperationImplementors = []string{"DeleteCommentOperation", "Operation", "Authored"}
+
+// nolint: gocyclo, errcheck, gas, goconst
+func (ec *executionContext) _DeleteCommentOperation(ctx context.Context, sel []query.Selection, obj *operations.DeleteCommentOperation) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.Doc, sel, deleteCommentOperationImplementors, ec.Variables)
+
+	out := graphql.NewOrderedMap(len(fields))
+	for i, field := range fields {
+		out.Keys[i] = field.Alias
+
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("DeleteCommentOperation")
+		case "author":
+			out.Values[i] = ec._DeleteCommentOperation_author(ctx, field, obj)
+		case "date":
+			out.Values[i] = ec._DeleteCommentOperation_date(ctx, field, obj)
+		case "target":
+			out.Values[i] = ec._DeleteCommentOperation_target(ctx, field, obj)
+		case "forced":
+			out.Values[i] = ec._DeleteCommentOperation_forced(ctx, field, obj)
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+
+	return out
+}
+
+func (ec *executionContext) _DeleteCommentOperation_author(ctx context.Context, field graphql.CollectedField, obj *operations.DeleteCommentOperation) graphql.Marshaler {
+	rctx := graphql.GetResolverContext(ctx)
+	rctx.Object = "DeleteCommentOperation"
+	rctx.Args = nil
+	rctx.Field = field
+	rctx.PushField(field.Alias)
+	defer rctx.Pop()
+	res := obj.Author
+	return ec._Person(ctx, field.Selections, &res)
+}
+
+func (ec *executionContext) _DeleteCommentOperation_date(ctx context.Context, field graphql.CollectedField, obj *operations.DeleteCommentOperation) graphql.Marshaler {
+	ctx = graphql.WithResolverContext(ctx, &graphql.ResolverContext{
+		Object: "DeleteCommentOperation",
+		Args:   nil,
+		Field:  field,
+	})
+	return graphql.Defer(func() (ret graphql.Marshaler) {
+		defer func() {
+			if r := recover(); r != nil {
+				userErr := ec.Recover(ctx, r)
+				ec.Error(ctx, userErr)
+				ret = graphql.Null
+			}
+		}()
+
+		resTmp, err := ec.ResolverMiddleware(ctx, func(ctx context.Context) (interface{}, error) {
+			return ec.resolvers.DeleteCommentOperation_date(ctx, obj)
+		})
+		if err != nil {
+			ec.Error(ctx, err)
+			return graphql.Null
+		}
+		if resTmp == nil {
+			return graphql.Null
+		}
+		res := resTmp.(time.Time)
+		return graphql.MarshalTime(res)
+	})
+}
+
+func (ec *executionContext) _DeleteCommentOperation_target(ctx context.Context, field graphql.CollectedField, obj *operations.DeleteCommentOperation) graphql.Marshaler {
+	rctx := graphql.GetResolverContext(ctx)
+	rctx.Object = "DeleteCommentOperation"
+	rctx.Args = nil
+	rctx.Field = field
+	rctx.PushField(field.Alias)
+	defer rctx.Pop()
+	res := obj.Target
+	return res
+}
+
+func (ec *executionContext) _DeleteCommentOperation_forced(ctx context.Context, field graphql.CollectedField, obj *operations.DeleteCommentOperation) graphql.Marshaler {
+	rctx := graphql.GetResolverContext(ctx)
+	rctx.Object = "DeleteCommentOperation"
+	rctx.Args = nil
+	rctx.Field = field
+	rctx.PushField(field.Alias)
+	defer rctx.Pop()
+	res := obj.Forced
+	return graphql.MarshalBoolean(res)
+}
+
+var labelChangeOperationImplementors = []string{"LabelChangeOperation", "Operation", "Authored"}
+
+// nolint: gocyclo, errcheck, gas, goconst
+func (ec *executionContext) _LabelChangeOperation(ctx context.Context, sel []query.Selection, obj *operations.LabelChangeOperation) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.Doc, sel, labelChangeOperationImplementors, ec.Variables)
+
+	out := graphql.NewOrderedMap(len(fields))
+	for i, field := range fields {
+		out.Keys[i] = field.Alias
+
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("LabelChangeOperation")
+		case "author":
+			out.Values[i] = ec._LabelChangeOperation_author(ctx, field, obj)
+		case "date":
+			out.Values[i] = ec._LabelChangeOperation_date(ctx, field, obj)
+		case "added":
+			out.Values[i] = ec._LabelChangeOperation_added(ctx, field, obj)
+		case "removed":
+			out.Values[i] = ec._LabelChangeOperation_removed(ctx, field, obj)
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+
+	return out
+}
+
+func (ec *executionContext) _LabelChangeOperation_author(ctx context.Context, field graphql.CollectedField, obj *operations.LabelChangeOperation) graphql.Marshaler {
+	rctx := graphql.GetResolverContext(ctx)
+	rctx.Object = "LabelChangeOperation"
+	rctx.Args = nil
+	rctx.Field = field
+	rctx.PushField(field.Alias)
+	defer rctx.Pop()
+	res := obj.Author
+	return ec._Person(ctx, field.Selections, &res)
+}
+
+func (ec *executionContext) _LabelChangeOperation_date(ctx context.Context, field graphql.CollectedField, obj *operations.LabelChangeOperation) graphql.Marshaler {
+	ctx = graphql.WithResolverContext(ctx, &graphql.ResolverContext{
+		Object: "LabelChangeOperation",
+		Args:   nil,
+		Field:  field,
+	})
+	return graphql.Defer(func() (ret graphql.Marshaler) {
+		defer func() {
+			if r := recover(); r != nil {
+				userErr := ec.Recover(ctx, r)
+				ec.Error(ctx, userErr)
+				ret = graphql.Null
+			}
+		}()
+
+		resTmp, err := ec.ResolverMiddleware(ctx, func(ctx context.Context) (interface{}, error) {
+			return ec.resolvers.LabelChangeOperation_date(ctx, obj)
+		})
+		if err != nil {
+			ec.Error(ctx, err)
+			return graphql.Null
+		}
+		if resTmp == nil {
+			return graphql.Null
+		}
+		res := resTmp.(time.Time)
+		return graphql.MarshalTime(res)
+	})
+}
+
+func (ec *executionContext) _LabelChangeOperation_added(ctx context.Context, field graphql.CollectedField, obj *operations.LabelChangeOperation) graphql.Marshaler {
+	rctx := graphql.GetResolverContext(ctx)
+	rctx.Object = "LabelChangeOperation"
+	rctx.Args = nil
+	rctx.Field = field
+	rctx.PushField(field.Alias)
+	defer rctx.Pop()
+	res := obj.Added
+	arr1 := graphql.Array{}
+	for idx1 := range res {
+		arr1 = append(arr1, func() graphql.Marshaler {
+			rctx := graphql.GetResolverContext(ctx)
+			rctx.PushIndex(idx1)
+			defer rctx.Pop()
+			return res[idx1]
+		}())
+	}
+	return arr1
+}
+
+func (ec *executionContext) _LabelChangeOperation_removed(ctx context.Context, field graphql.CollectedField, obj *operations.LabelChangeOperation) graphql.Marshaler {
+	rctx := graphql.GetResolverContext(ctx)
+	rctx.Object = "LabelChangeOperation"
+	rctx.Args = nil
+	rctx.Field = field
+	rctx.PushField(field.Alias)
+	defer rctx.Pop()
+	res := obj.Removed
+	arr1 := graphql.Array{}
+	for idx1 := range res {
+		arr1 = append(arr1, func() graphql.Marshaler {
+			rctx := graphql.GetResolverContext(ctx)
+			rctx.PushIndex(idx1)
+			defer rctx.Pop()
+			return res[idx1]
+		}())
+	}
+	return arr1
+}
+
+var labelInfoImplementors = []string{"LabelInfo"}
+
+// nolint: gocyclo, errcheck, gas, goconst
+func (ec *executionContext) _LabelInfo(ctx context.Context, sel []query.Selection, obj *models.LabelInfo) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.Doc, sel, labelInfoImplementors, ec.Variables)
+
+	out := graphql.NewOrderedMap(len(fields))
+	for i, field := range fields {
+		out.Keys[i] = field.Alias
+
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("LabelInfo")
+		case "name":
+			out.Values[i] = ec._LabelInfo_name(ctx, field, obj)
+		case "color":
+			out.Values[i] = ec._LabelInfo_color(ctx, field, obj)
+		case "description":
+			out.Values[i] = ec._LabelInfo_description(ctx, field, obj)
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+
+	return out
+}
+
+func (ec *executionContext) _LabelInfo_name(ctx context.Context, field graphql.CollectedField, obj *models.LabelInfo) graphql.Marshaler {
+	rctx := graphql.GetResolverContext(ctx)
+	rctx.Object = "LabelInfo"
+	rctx.Args = nil
+	rctx.Field = field
+	rctx.PushField(field.Alias)
+	defer rctx.Pop()
+	res := obj.Name
+	return res
+}
+
+func (ec *executionContext) _LabelInfo_color(ctx context.Context, field graphql.CollectedField, obj *models.LabelInfo) graphql.Marshaler {
+	rctx := graphql.GetResolverContext(ctx)
+	rctx.Object = "LabelInfo"
+	rctx.Args = nil
+	rctx.Field = field
+	rctx.PushField(field.Alias)
+	defer rctx.Pop()
+	res := obj.Color
+	if res == nil {
+		return graphql.Null
+	}
+	return graphql.MarshalString(*res)
+}
+
+func (ec *executionContext) _LabelInfo_description(ctx context.Context, field graphql.CollectedField, obj *models.LabelInfo) graphql.Marshaler {
+	rctx := graphql.GetResolverContext(ctx)
+	rctx.Object = "LabelInfo"
+	rctx.Args = nil
+	rctx.Field = field
+	rctx.PushField(field.Alias)
+	defer rctx.Pop()
+	res := obj.Description
+	if res == nil {
+		return graphql.Null
+	}
+	return graphql.MarshalString(*res)
+}
+
+var mutationImplementors = []string{"Mutation"}
+
+// nolint: gocyclo, errcheck, gas, goconst
+func (ec *executionContext) _Mutation(ctx context.Context, sel []query.Selection) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.Doc, sel, mutationImplementors, ec.Variables)
+
+	ctx = graphql.WithResolverContext(ctx, &graphql.ResolverContext{
+		Object: "Mutation",
+	})
+
+	out := graphql.NewOrderedMap(len(fields))
+	for i, field := range fields {
+		out.Keys[i] = field.Alias
+
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("Mutation")
+		case "newBug":
+			out.Values[i] = ec._Mutation_newBug(ctx, field)
+		case "addComment":
+			out.Values[i] = ec._Mutation_addComment(ctx, field)
+		case "changeLabels":
+			out.Values[i] = ec._Mutation_changeLabels(ctx, field)
+		case "open":
+			out.Values[i] = ec._Mutation_open(ctx, field)
+		case "close":
+			out.Values[i] = ec._Mutation_close(ctx, field)
+		case "setTitle":
+			out.Values[i] = ec._Mutation_setTitle(ctx, field)
+		case "commit":
+			out.Values[i] = ec._Mutation_commit(ctx, field)
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+
+	return out
+}
+
+func (ec *executionContext) _Mutation_newBug(ctx context.Context, field graphql.CollectedField) graphql.Marshaler {
+	args := map[string]interface{}{}
+	var arg0 *string
+	if tmp, ok := field.Args["repoRef"]; ok {
+		var err error
+		var ptr1 string
+		if tmp != nil {
+			ptr1, err = graphql.UnmarshalString(tmp)
+			arg0 = &ptr1
+		}
+
+		if err != nil {
+			ec.Error(ctx, err)
+			return graphql.Null
+		}
+	}
+	args["repoRef"] = arg0
+	var arg1 string
+	if tmp, ok := field.Args["title"]; ok {
+		var err error
+		arg1, err = graphql.UnmarshalString(tmp)
+		if err != nil {
+			ec.Error(ctx, err)
+			return graphql.Null
+		}
+	}
+	args["title"] = arg1
+	var arg2 string
+	if tmp, ok := field.Args["message"]; ok {
+		var err error
+		arg2, err = graphql.UnmarshalString(tmp)
+		if err != nil {
+			ec.Error(ctx, err)
+			return graphql.Null
+		}
+	}
+	args["message"] = arg2
+	var arg3 []util.Hash
+	if tmp, ok := field.Args["files"]; ok {
+		var err error
+		var rawIf1 []interface{}
+		if tmp != nil {
+			if tmp1, ok := tmp.([]interface{}); ok {
+				rawIf1 = tmp1
+			}
+		}
+		arg3 = make([]util.Hash, len(rawIf1))
+		for idx1 := range rawIf1 {
+			err = (&arg3[idx1]).UnmarshalGQL(rawIf1[idx1])
+		}
+		if err != nil {
+			ec.Error(ctx, err)
+			return graphql.Null
+		}
+	}
+	args["files"] = arg3
+	rctx := graphql.GetResolverContext(ctx)
+	rctx.Object = "Mutation"
+	rctx.Args = args
+	rctx.Field = field
+	rctx.PushField(field.Alias)
+	defer rctx.Pop()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(ctx context.Context) (interface{}, error) {
+		return ec.resolvers.Mutation_newBug(ctx, args["repoRef"].(*string), args["title"].(string), args["message"].(string), args["files"].([]util.Hash))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(bug.Snapshot)
+	return ec._Bug(ctx, field.Selections, &res)
+}
+
+func (ec *executionContext) _Mutation_addComment(ctx context.Context, field graphql.CollectedField) graphql.Marshaler {
+	args := map[string]interface{}{}
+	var arg0 *string
+	if tmp, ok := field.Args["repoRef"]; ok {
+		var err error
+		var ptr1 string
+		if tmp != nil {
+			ptr1, err = graphql.UnmarshalString(tmp)
+			arg0 = &ptr1
+		}
+
+		if err != nil {
+			ec.Error(ctx, err)
+			return graphql.Null
+		}
+	}
+	args["repoRef"] = arg0
+	var arg1 string
+	if tmp, ok := field.Args["prefix"]; ok {
+		var err error
+		arg1, err = graphql.UnmarshalString(tmp)
+		if err != nil {
+			ec.Error(ctx, err)
+			return graphql.Null
+		}
+	}
+	args["prefix"] = arg1
+	var arg2 string
+	if tmp, ok := field.Args["message"]; ok {
+		var err error
+		arg2, err = graphql.UnmarshalString(tmp)
+		if err != nil {
+			ec.Error(ctx, err)
+			return graphql.Null
+		}
+	}
+	args["message"] = arg2
+	var arg3 []util.Hash
+	if tmp, ok := field.Args["files"]; ok {
+		var err error
+		var rawIf1 []interface{}
+		if tmp != nil {
+			if tmp1, ok := tmp.([]interface{}); ok {
+				rawIf1 = tmp1
+			}
+		}
+		arg3 = make([]util.Hash, len(rawIf1))
+		for idx1 := range rawIf1 {
+			err = (&arg3[idx1]).UnmarshalGQL(rawIf1[idx1])
+		}
+		if err != nil {
+			ec.Error(ctx, err)
+			return graphql.Null
+		}
+	}
+	args["files"] = arg3
+	rctx := graphql.GetResolverContext(ctx)
+	rctx.Object = "Mutation"
+	rctx.Args = args
+	rctx.Field = field
+	rctx.PushField(field.Alias)
+	defer rctx.Pop()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(ctx context.Context) (interface{}, error) {
+		return ec.resolvers.Mutation_addComment(ctx, args["repoRef"].(*string), args["prefix"].(string), args["message"].(string), args["files"].([]util.Hash))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(bug.Snapshot)
+	return ec._Bug(ctx, field.Selections, &res)
+}
+
+func (ec *executionContext) _Mutation_changeLabels(ctx context.Context, field graphql.CollectedField) graphql.Marshaler {
+	args := map[string]interface{}{}
+	var arg0 *string
+	if tmp, ok := field.Args["repoRef"]; ok {
+		var err error
+		var ptr1 string
+		if tmp != nil {
+			ptr1, err = graphql.UnmarshalString(tmp)
+			arg0 = &ptr1
+		}
+
+		if err != nil {
+			ec.Error(ctx, err)
+			return graphql.Null
+		}
+	}
+	args["repoRef"] = arg0
+	var arg1 string
+	if tmp, ok := field.Args["prefix"]; ok {
+		var err error
+		arg1, err = graphql.UnmarshalString(tmp)
+		if err != nil {
+			ec.Error(ctx, err)
+			return graphql.Null
+		}
+	}
+	args["prefix"] = arg1
+	var arg2 []string
+	if tmp, ok := field.Args["added"]; ok {
+		var err error
+		var rawIf1 []interface{}
+		if tmp != nil {
+			if tmp1, ok := tmp.([]interface{}); ok {
+				rawIf1 = tmp1
+			}
+		}
+		arg2 = make([]string, len(rawIf1))
+		for idx1 := range rawIf1 {
+			arg2[idx1], err = graphql.UnmarshalString(rawIf1[idx1])
+		}
+		if err != nil {
+			ec.Error(ctx, err)
+			return graphql.Null
+		}
+	}
+	args["added"] = arg2
+	var arg3 []string
+	if tmp, ok := field.Args["removed"]; ok {
+		var err error
+		var rawIf1 []interface{}
+		if tmp != nil {
+			if tmp1, ok := tmp.([]interface{}); ok {
+				rawIf1 = tmp1
+			}
+		}
+		arg3 = make([]string, len(rawIf1))
+		for idx1 := range rawIf1 {
+			arg3[idx1], err = graphql.UnmarshalString(rawIf1[idx1])
+		}
+		if err != nil {
+			ec.Error(ctx, err)
+			return graphql.Null
+		}
+	}
+	args["removed"] = arg3
+	rctx := graphql.GetResolverContext(ctx)
+	rctx.Object = "Mutation"
+	rctx.Args = args
+	rctx.Field = field
+	rctx.PushField(field.Alias)
+	defer rctx.Pop()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(ctx context.Context) (interface{}, error) {
+		return ec.resolvers.Mutation_changeLabels(ctx, args["repoRef"].(*string), args["prefix"].(string), args["added"].([]string), args["removed"].([]string))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(bug.Snapshot)
+	return ec._Bug(ctx, field.Selections, &res)
+}
+
+func (ec *executionContext) _Mutation_open(ctx context.Context, field graphql.CollectedField) graphql.Marshaler {
+	args := map[string]interface{}{}
+	var arg0 *string
+	if tmp, ok := field.Args["repoRef"]; ok {
+		var err error
+		var ptr1 string
+		if tmp != nil {
+			ptr1, err = graphql.UnmarshalString(tmp)
+			arg0 = &ptr1
+		}
+
+		if err != nil {
+			ec.Error(ctx, err)
+			return graphql.Null
+		}
+	}
+	args["repoRef"] = arg0
+	var arg1 string
+	if tmp, ok := field.Args["prefix"]; ok {
+		var err error
+		arg1, err = graphql.UnmarshalString(tmp)
+		if err != nil {
+			ec.Error(ctx, err)
+			return graphql.Null
+		}
+	}
+	args["prefix"] = arg1
+	rctx := graphql.GetResolverContext(ctx)
+	rctx.Object = "Mutation"
+	rctx.Args = args
+	rctx.Field = field
+	rctx.PushField(field.Alias)
+	defer rctx.Pop()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(ctx context.Context) (interface{}, error) {
+		return ec.resolvers.Mutation_open(ctx, args["repoRef"].(*string), args["prefix"].(string))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(bug.Snapshot)
+	return ec._Bug(ctx, field.Selections, &res)
+}
+
+func (ec *executionContext) _Mutation_close(ctx context.Context, field graphql.CollectedField) graphql.Marshaler {
+	args := map[string]interface{}{}
+	var arg0 *string
+	if tmp, ok := field.Args["repoRef"]; ok {
+		var err error
+		var ptr1 string
+		if tmp != nil {
+			ptr1, err = graphql.UnmarshalString(tmp)
+			arg0 = &ptr1
+		}
+
+		if err != nil {
+			ec.Error(ctx, err)
+			return graphql.Null
+		}
+	}
+	args["repoRef"] = arg0
+	var arg1 string
+	if tmp, ok := field.Args["prefix"]; ok {
+		var err error
+		arg1, err = graphql.UnmarshalString(tmp)
+		if err != nil {
+			ec.Error(ctx, err)
+			return graphql.Null
+		}
+	}
+	args["prefix"] = arg1
+	rctx := graphql.GetResolverContext(ctx)
+	rctx.Object = "Mutation"
+	rctx.Args = args
+	rctx.Field = field
+	rctx.PushField(field.Alias)
+	defer rctx.Pop()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(ctx context.Context) (interface{}, error) {
+		return ec.resolvers.Mutation_close(ctx, args["repoRef"].(*string), args["prefix"].(string))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(bug.Snapshot)
+	return ec._Bug(ctx, field.Selections, &res)
+}
+
+func (ec *executionContext) _Mutation_setTitle(ctx context.Context, field graphql.CollectedField) graphql.Marshaler {
+	args := map[string]interface{}{}
+	var arg0 *string
+	if tmp, ok := field.Args["repoRef"]; ok {
+		var err error
+		var ptr1 string
+		if tmp != nil {
+			ptr1, err = graphql.UnmarshalString(tmp)
+			arg0 = &ptr1
+		}
+
+		if err != nil {
 			ec.Error(ctx, err)
 			return graphql.Null
 		}
@@ -1473,267 +2805,536 @@ func (ec *executionContext) _Mutation_changeLabels(ctx context.Context, field gr
 		}
 	}
 	args["prefix"] = arg1
-	var arg2 []string
-	if tmp, ok := field.Args["added"]; ok {
+	var arg2 string
+	if tmp, ok := field.Args["title"]; ok {
 		var err error
-		var rawIf1 []interface{}
+		arg2, err = graphql.UnmarshalString(tmp)
+		if err != nil {
+			ec.Error(ctx, err)
+			return graphql.Null
+		}
+	}
+	args["title"] = arg2
+	rctx := graphql.GetResolverContext(ctx)
+	rctx.Object = "Mutation"
+	rctx.Args = args
+	rctx.Field = field
+	rctx.PushField(field.Alias)
+	defer rctx.Pop()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(ctx context.Context) (interface{}, error) {
+		return ec.resolvers.Mutation_setTitle(ctx, args["repoRef"].(*string), args["prefix"].(string), args["title"].(string))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(bug.Snapshot)
+	return ec._Bug(ctx, field.Selections, &res)
+}
+
+func (ec *executionContext) _Mutation_commit(ctx context.Context, field graphql.CollectedField) graphql.Marshaler {
+	args := map[string]interface{}{}
+	var arg0 *string
+	if tmp, ok := field.Args["repoRef"]; ok {
+		var err error
+		var ptr1 string
 		if tmp != nil {
-			if tmp1, ok := tmp.([]interface{}); ok {
-				rawIf1 = tmp1
-			}
+			ptr1, err = graphql.UnmarshalString(tmp)
+			arg0 = &ptr1
 		}
-		arg2 = make([]string, len(rawIf1))
-		for idx1 := range rawIf1 {
-			arg2[idx1], err = graphql.UnmarshalString(rawIf1[idx1])
+
+		if err != nil {
+			ec.Error(ctx, err)
+			return graphql.Null
 		}
+	}
+	args["repoRef"] = arg0
+	var arg1 string
+	if tmp, ok := field.Args["prefix"]; ok {
+		var err error
+		arg1, err = graphql.UnmarshalString(tmp)
 		if err != nil {
 			ec.Error(ctx, err)
 			return graphql.Null
 		}
 	}
-	args["added"] = arg2
-	var arg3 []string
-	if tmp, ok := field.Args["removed"]; ok {
-		var err error
-		var rawIf1 []interface{}
-		if tmp != nil {
-			if tmp1, ok := tmp.([]interface{}); ok {
-				rawIf1 = tmp1
-			}
-		}
-		arg3 = make([]string, len(rawIf1))
-		for idx1 := range rawIf1 {
-			arg3[idx1], err = graphql.UnmarshalString(rawIf1[idx1])
-		}
-		if err != nil {
-			ec.Error(ctx, err)
-			return graphql.Null
+	args["prefix"] = arg1
+	rctx := graphql.GetResolverContext(ctx)
+	rctx.Object = "Mutation"
+	rctx.Args = args
+	rctx.Field = field
+	rctx.PushField(field.Alias)
+	defer rctx.Pop()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(ctx context.Context) (interface{}, error) {
+		return ec.resolvers.Mutation_commit(ctx, args["repoRef"].(*string), args["prefix"].(string))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(bug.Snapshot)
+	return ec._Bug(ctx, field.Selections, &res)
+}
+
+var operationConnectionImplementors = []string{"OperationConnection"}
+
+// nolint: gocyclo, errcheck, gas, goconst
+func (ec *executionContext) _OperationConnection(ctx context.Context, sel []query.Selection, obj *models.OperationConnection) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.Doc, sel, operationConnectionImplementors, ec.Variables)
+
+	out := graphql.NewOrderedMap(len(fields))
+	for i, field := range fields {
+		out.Keys[i] = field.Alias
+
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("OperationConnection")
+		case "edges":
+			out.Values[i] = ec._OperationConnection_edges(ctx, field, obj)
+		case "nodes":
+			out.Values[i] = ec._OperationConnection_nodes(ctx, field, obj)
+		case "pageInfo":
+			out.Values[i] = ec._OperationConnection_pageInfo(ctx, field, obj)
+		case "totalCount":
+			out.Values[i] = ec._OperationConnection_totalCount(ctx, field, obj)
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+
+	return out
+}
+
+func (ec *executionContext) _OperationConnection_edges(ctx context.Context, field graphql.CollectedField, obj *models.OperationConnection) graphql.Marshaler {
+	rctx := graphql.GetResolverContext(ctx)
+	rctx.Object = "OperationConnection"
+	rctx.Args = nil
+	rctx.Field = field
+	rctx.PushField(field.Alias)
+	defer rctx.Pop()
+	res := obj.Edges
+	arr1 := graphql.Array{}
+	for idx1 := range res {
+		arr1 = append(arr1, func() graphql.Marshaler {
+			rctx := graphql.GetResolverContext(ctx)
+			rctx.PushIndex(idx1)
+			defer rctx.Pop()
+			return ec._OperationEdge(ctx, field.Selections, &res[idx1])
+		}())
+	}
+	return arr1
+}
+
+func (ec *executionContext) _OperationConnection_nodes(ctx context.Context, field graphql.CollectedField, obj *models.OperationConnection) graphql.Marshaler {
+	rctx := graphql.GetResolverContext(ctx)
+	rctx.Object = "OperationConnection"
+	rctx.Args = nil
+	rctx.Field = field
+	rctx.PushField(field.Alias)
+	defer rctx.Pop()
+	res := obj.Nodes
+	arr1 := graphql.Array{}
+	for idx1 := range res {
+		arr1 = append(arr1, func() graphql.Marshaler {
+			rctx := graphql.GetResolverContext(ctx)
+			rctx.PushIndex(idx1)
+			defer rctx.Pop()
+			return ec._Operation(ctx, field.Selections, &res[idx1])
+		}())
+	}
+	return arr1
+}
+
+func (ec *executionContext) _OperationConnection_pageInfo(ctx context.Context, field graphql.CollectedField, obj *models.OperationConnection) graphql.Marshaler {
+	rctx := graphql.GetResolverContext(ctx)
+	rctx.Object = "OperationConnection"
+	rctx.Args = nil
+	rctx.Field = field
+	rctx.PushField(field.Alias)
+	defer rctx.Pop()
+	res := obj.PageInfo
+	return ec._PageInfo(ctx, field.Selections, &res)
+}
+
+func (ec *executionContext) _OperationConnection_totalCount(ctx context.Context, field graphql.CollectedField, obj *models.OperationConnection) graphql.Marshaler {
+	rctx := graphql.GetResolverContext(ctx)
+	rctx.Object = "OperationConnection"
+	rctx.Args = nil
+	rctx.Field = field
+	rctx.PushField(field.Alias)
+	defer rctx.Pop()
+	res := obj.TotalCount
+	return graphql.MarshalInt(res)
+}
+
+var operationEdgeImplementors = []string{"OperationEdge"}
+
+// nolint: gocyclo, errcheck, gas, goconst
+func (ec *executionContext) _OperationEdge(ctx context.Context, sel []query.Selection, obj *models.OperationEdge) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.Doc, sel, operationEdgeImplementors, ec.Variables)
+
+	out := graphql.NewOrderedMap(len(fields))
+	for i, field := range fields {
+		out.Keys[i] = field.Alias
+
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("OperationEdge")
+		case "cursor":
+			out.Values[i] = ec._OperationEdge_cursor(ctx, field, obj)
+		case "node":
+			out.Values[i] = ec._OperationEdge_node(ctx, field, obj)
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+
+	return out
+}
+
+func (ec *executionContext) _OperationEdge_cursor(ctx context.Context, field graphql.CollectedField, obj *models.OperationEdge) graphql.Marshaler {
+	rctx := graphql.GetResolverContext(ctx)
+	rctx.Object = "OperationEdge"
+	rctx.Args = nil
+	rctx.Field = field
+	rctx.PushField(field.Alias)
+	defer rctx.Pop()
+	res := obj.Cursor
+	return graphql.MarshalString(res)
+}
+
+func (ec *executionContext) _OperationEdge_node(ctx context.Context, field graphql.CollectedField, obj *models.OperationEdge) graphql.Marshaler {
+	rctx := graphql.GetResolverContext(ctx)
+	rctx.Object = "OperationEdge"
+	rctx.Args = nil
+	rctx.Field = field
+	rctx.PushField(field.Alias)
+	defer rctx.Pop()
+	res := obj.Node
+	return ec._Operation(ctx, field.Selections, &res)
+}
+
+var pageInfoImplementors = []string{"PageInfo"}
+
+// nolint: gocyclo, errcheck, gas, goconst
+func (ec *executionContext) _PageInfo(ctx context.Context, sel []query.Selection, obj *models.PageInfo) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.Doc, sel, pageInfoImplementors, ec.Variables)
+
+	out := graphql.NewOrderedMap(len(fields))
+	for i, field := range fields {
+		out.Keys[i] = field.Alias
+
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("PageInfo")
+		case "hasNextPage":
+			out.Values[i] = ec._PageInfo_hasNextPage(ctx, field, obj)
+		case "hasPreviousPage":
+			out.Values[i] = ec._PageInfo_hasPreviousPage(ctx, field, obj)
+		case "startCursor":
+			out.Values[i] = ec._PageInfo_startCursor(ctx, field, obj)
+		case "endCursor":
+			out.Values[i] = ec._PageInfo_endCursor(ctx, field, obj)
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+
+	return out
+}
+
+func (ec *executionContext) _PageInfo_hasNextPage(ctx context.Context, field graphql.CollectedField, obj *models.PageInfo) graphql.Marshaler {
+	rctx := graphql.GetResolverContext(ctx)
+	rctx.Object = "PageInfo"
+	rctx.Args = nil
+	rctx.Field = field
+	rctx.PushField(field.Alias)
+	defer rctx.Pop()
+	res := obj.HasNextPage
+	return graphql.MarshalBoolean(res)
+}
+
+func (ec *executionContext) _PageInfo_hasPreviousPage(ctx context.Context, field graphql.CollectedField, obj *models.PageInfo) graphql.Marshaler {
+	rctx := graphql.GetResolverContext(ctx)
+	rctx.Object = "PageInfo"
+	rctx.Args = nil
+	rctx.Field = field
+	rctx.PushField(field.Alias)
+	defer rctx.Pop()
+	res := obj.HasPreviousPage
+	return graphql.MarshalBoolean(res)
+}
+
+func (ec *executionContext) _PageInfo_startCursor(ctx context.Context, field graphql.CollectedField, obj *models.PageInfo) graphql.Marshaler {
+	rctx := graphql.GetResolverContext(ctx)
+	rctx.Object = "PageInfo"
+	rctx.Args = nil
+	rctx.Field = field
+	rctx.PushField(field.Alias)
+	defer rctx.Pop()
+	res := obj.StartCursor
+	return graphql.MarshalString(res)
+}
+
+func (ec *executionContext) _PageInfo_endCursor(ctx context.Context, field graphql.CollectedField, obj *models.PageInfo) graphql.Marshaler {
+	rctx := graphql.GetResolverContext(ctx)
+	rctx.Object = "PageInfo"
+	rctx.Args = nil
+	rctx.Field = field
+	rctx.PushField(field.Alias)
+	defer rctx.Pop()
+	res := obj.EndCursor
+	return graphql.MarshalString(res)
+}
+
+var personImplementors = []string{"Person"}
+
+// nolint: gocyclo, errcheck, gas, goconst
+func (ec *executionContext) _Person(ctx context.Context, sel []query.Selection, obj *bug.Person) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.Doc, sel, personImplementors, ec.Variables)
+
+	out := graphql.NewOrderedMap(len(fields))
+	for i, field := range fields {
+		out.Keys[i] = field.Alias
+
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("Person")
+		case "email":
+			out.Values[i] = ec._Person_email(ctx, field, obj)
+		case "name":
+			out.Values[i] = ec._Person_name(ctx, field, obj)
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
 		}
 	}
-	args["removed"] = arg3
+
+	return out
+}
+
+func (ec *executionContext) _Person_email(ctx context.Context, field graphql.CollectedField, obj *bug.Person) graphql.Marshaler {
 	rctx := graphql.GetResolverContext(ctx)
-	rctx.Object = "Mutation"
-	rctx.Args = args
+	rctx.Object = "Person"
+	rctx.Args = nil
 	rctx.Field = field
 	rctx.PushField(field.Alias)
 	defer rctx.Pop()
-	resTmp, err := ec.ResolverMiddleware(ctx, func(ctx context.Context) (interface{}, error) {
-		return ec.resolvers.Mutation_changeLabels(ctx, args["repoRef"].(*string), args["prefix"].(string), args["added"].([]string), args["removed"].([]string))
+	res := obj.Email
+	return graphql.MarshalString(res)
+}
+
+func (ec *executionContext) _Person_name(ctx context.Context, field graphql.CollectedField, obj *bug.Person) graphql.Marshaler {
+	rctx := graphql.GetResolverContext(ctx)
+	rctx.Object = "Person"
+	rctx.Args = nil
+	rctx.Field = field
+	rctx.PushField(field.Alias)
+	defer rctx.Pop()
+	res := obj.Name
+	return graphql.MarshalString(res)
+}
+
+var queryImplementors = []string{"Query"}
+
+// nolint: gocyclo, errcheck, gas, goconst
+func (ec *executionContext) _Query(ctx context.Context, sel []query.Selection) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.Doc, sel, queryImplementors, ec.Variables)
+
+	ctx = graphql.WithResolverContext(ctx, &graphql.ResolverContext{
+		Object: "Query",
 	})
-	if err != nil {
-		ec.Error(ctx, err)
-		return graphql.Null
-	}
-	if resTmp == nil {
-		return graphql.Null
+
+	out := graphql.NewOrderedMap(len(fields))
+	for i, field := range fields {
+		out.Keys[i] = field.Alias
+
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("Query")
+		case "defaultRepository":
+			out.Values[i] = ec._Query_defaultRepository(ctx, field)
+		case "repository":
+			out.Values[i] = ec._Query_repository(ctx, field)
+		case "repositories":
+			out.Values[i] = ec._Query_repositories(ctx, field)
+		case "__schema":
+			out.Values[i] = ec._Query___schema(ctx, field)
+		case "__type":
+			out.Values[i] = ec._Query___type(ctx, field)
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
 	}
-	res := resTmp.(bug.Snapshot)
-	return ec._Bug(ctx, field.Selections, &res)
+
+	return out
 }
 
-func (ec *executionContext) _Mutation_open(ctx context.Context, field graphql.CollectedField) graphql.Marshaler {
-	args := map[string]interface{}{}
-	var arg0 *string
-	if tmp, ok := field.Args["repoRef"]; ok {
-		var err error
-		var ptr1 string
-		if tmp != nil {
-			ptr1, err = graphql.UnmarshalString(tmp)
-			arg0 = &ptr1
-		}
+func (ec *executionContext) _Query_defaultRepository(ctx context.Context, field graphql.CollectedField) graphql.Marshaler {
+	ctx = graphql.WithResolverContext(ctx, &graphql.ResolverContext{
+		Object: "Query",
+		Args:   nil,
+		Field:  field,
+	})
+	return graphql.Defer(func() (ret graphql.Marshaler) {
+		defer func() {
+			if r := recover(); r != nil {
+				userErr := ec.Recover(ctx, r)
+				ec.Error(ctx, userErr)
+				ret = graphql.Null
+			}
+		}()
 
+		resTmp, err := ec.ResolverMiddleware(ctx, func(ctx context.Context) (interface{}, error) {
+			return ec.resolvers.Query_defaultRepository(ctx)
+		})
 		if err != nil {
 			ec.Error(ctx, err)
 			return graphql.Null
 		}
-	}
-	args["repoRef"] = arg0
-	var arg1 string
-	if tmp, ok := field.Args["prefix"]; ok {
-		var err error
-		arg1, err = graphql.UnmarshalString(tmp)
-		if err != nil {
-			ec.Error(ctx, err)
+		if resTmp == nil {
 			return graphql.Null
 		}
-	}
-	args["prefix"] = arg1
-	rctx := graphql.GetResolverContext(ctx)
-	rctx.Object = "Mutation"
-	rctx.Args = args
-	rctx.Field = field
-	rctx.PushField(field.Alias)
-	defer rctx.Pop()
-	resTmp, err := ec.ResolverMiddleware(ctx, func(ctx context.Context) (interface{}, error) {
-		return ec.resolvers.Mutation_open(ctx, args["repoRef"].(*string), args["prefix"].(string))
+		res := resTmp.(*models.Repository)
+		if res == nil {
+			return graphql.Null
+		}
+		return ec._Repository(ctx, field.Selections, res)
 	})
-	if err != nil {
-		ec.Error(ctx, err)
-		return graphql.Null
-	}
-	if resTmp == nil {
-		return graphql.Null
-	}
-	res := resTmp.(bug.Snapshot)
-	return ec._Bug(ctx, field.Selections, &res)
 }
 
-func (ec *executionContext) _Mutation_close(ctx context.Context, field graphql.CollectedField) graphql.Marshaler {
+func (ec *executionContext) _Query_repository(ctx context.Context, field graphql.CollectedField) graphql.Marshaler {
 	args := map[string]interface{}{}
-	var arg0 *string
-	if tmp, ok := field.Args["repoRef"]; ok {
+	var arg0 string
+	if tmp, ok := field.Args["id"]; ok {
 		var err error
-		var ptr1 string
-		if tmp != nil {
-			ptr1, err = graphql.UnmarshalString(tmp)
-			arg0 = &ptr1
+		arg0, err = graphql.UnmarshalString(tmp)
+		if err != nil {
+			ec.Error(ctx, err)
+			return graphql.Null
 		}
+	}
+	args["id"] = arg0
+	ctx = graphql.WithResolverContext(ctx, &graphql.ResolverContext{
+		Object: "Query",
+		Args:   args,
+		Field:  field,
+	})
+	return graphql.Defer(func() (ret graphql.Marshaler) {
+		defer func() {
+			if r := recover(); r != nil {
+				userErr := ec.Recover(ctx, r)
+				ec.Error(ctx, userErr)
+				ret = graphql.Null
+			}
+		}()
 
+		resTmp, err := ec.ResolverMiddleware(ctx, func(ctx context.Context) (interface{}, error) {
+			return ec.resolvers.Query_repository(ctx, args["id"].(string))
+		})
 		if err != nil {
 			ec.Error(ctx, err)
 			return graphql.Null
 		}
-	}
-	args["repoRef"] = arg0
-	var arg1 string
-	if tmp, ok := field.Args["prefix"]; ok {
-		var err error
-		arg1, err = graphql.UnmarshalString(tmp)
-		if err != nil {
-			ec.Error(ctx, err)
+		if resTmp == nil {
 			return graphql.Null
 		}
-	}
-	args["prefix"] = arg1
-	rctx := graphql.GetResolverContext(ctx)
-	rctx.Object = "Mutation"
-	rctx.Args = args
-	rctx.Field = field
-	rctx.PushField(field.Alias)
-	defer rctx.Pop()
-	resTmp, err := ec.ResolverMiddleware(ctx, func(ctx context.Context) (interface{}, error) {
-		return ec.resolvers.Mutation_close(ctx, args["repoRef"].(*string), args["prefix"].(string))
+		res := resTmp.(*models.Repository)
+		if res == nil {
+			return graphql.Null
+		}
+		return ec._Repository(ctx, field.Selections, res)
 	})
-	if err != nil {
-		ec.Error(ctx, err)
-		return graphql.Null
-	}
-	if resTmp == nil {
-		return graphql.Null
-	}
-	res := resTmp.(bug.Snapshot)
-	return ec._Bug(ctx, field.Selections, &res)
 }
 
-func (ec *executionContext) _Mutation_setTitle(ctx context.Context, field graphql.CollectedField) graphql.Marshaler {
-	args := map[string]interface{}{}
-	var arg0 *string
-	if tmp, ok := field.Args["repoRef"]; ok {
-		var err error
-		var ptr1 string
-		if tmp != nil {
-			ptr1, err = graphql.UnmarshalString(tmp)
-			arg0 = &ptr1
-		}
+func (ec *executionContext) _Query_repositories(ctx context.Context, field graphql.CollectedField) graphql.Marshaler {
+	ctx = graphql.WithResolverContext(ctx, &graphql.ResolverContext{
+		Object: "Query",
+		Args:   nil,
+		Field:  field,
+	})
+	return graphql.Defer(func() (ret graphql.Marshaler) {
+		defer func() {
+			if r := recover(); r != nil {
+				userErr := ec.Recover(ctx, r)
+				ec.Error(ctx, userErr)
+				ret = graphql.Null
+			}
+		}()
 
+		resTmp, err := ec.ResolverMiddleware(ctx, func(ctx context.Context) (interface{}, error) {
+			return ec.resolvers.Query_repositories(ctx)
+		})
 		if err != nil {
 			ec.Error(ctx, err)
 			return graphql.Null
 		}
-	}
-	args["repoRef"] = arg0
-	var arg1 string
-	if tmp, ok := field.Args["prefix"]; ok {
-		var err error
-		arg1, err = graphql.UnmarshalString(tmp)
-		if err != nil {
-			ec.Error(ctx, err)
+		if resTmp == nil {
 			return graphql.Null
 		}
-	}
-	args["prefix"] = arg1
-	var arg2 string
-	if tmp, ok := field.Args["title"]; ok {
-		var err error
-		arg2, err = graphql.UnmarshalString(tmp)
-		if err != nil {
-			ec.Error(ctx, err)
-			return graphql.Null
+		res := resTmp.([]models.Repository)
+		arr1 := graphql.Array{}
+		for idx1 := range res {
+			arr1 = append(arr1, func() graphql.Marshaler {
+				rctx := graphql.GetResolverContext(ctx)
+				rctx.PushIndex(idx1)
+				defer rctx.Pop()
+				return ec._Repository(ctx, field.Selections, &res[idx1])
+			}())
 		}
-	}
-	args["title"] = arg2
+		return arr1
+	})
+}
+
+func (ec *executionContext) _Query___schema(ctx context.Context, field graphql.CollectedField) graphql.Marshaler {
 	rctx := graphql.GetResolverContext(ctx)
-	rctx.Object = "Mutation"
-	rctx.Args = args
+	rctx.Object = "Query"
+	rctx.Args = nil
 	rctx.Field = field
 	rctx.PushField(field.Alias)
 	defer rctx.Pop()
-	resTmp, err := ec.ResolverMiddleware(ctx, func(ctx context.Context) (interface{}, error) {
-		return ec.resolvers.Mutation_setTitle(ctx, args["repoRef"].(*string), args["prefix"].(string), args["title"].(string))
-	})
-	if err != nil {
-		ec.Error(ctx, err)
-		return graphql.Null
-	}
-	if resTmp == nil {
+	res := ec.introspectSchema()
+	if res == nil {
 		return graphql.Null
 	}
-	res := resTmp.(bug.Snapshot)
-	return ec._Bug(ctx, field.Selections, &res)
+	return ec.___Schema(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) _Mutation_commit(ctx context.Context, field graphql.CollectedField) graphql.Marshaler {
+func (ec *executionContext) _Query___type(ctx context.Context, field graphql.CollectedField) graphql.Marshaler {
 	args := map[string]interface{}{}
-	var arg0 *string
-	if tmp, ok := field.Args["repoRef"]; ok {
-		var err error
-		var ptr1 string
-		if tmp != nil {
-			ptr1, err = graphql.UnmarshalString(tmp)
-			arg0 = &ptr1
-		}
-
-		if err != nil {
-			ec.Error(ctx, err)
-			return graphql.Null
-		}
-	}
-	args["repoRef"] = arg0
-	var arg1 string
-	if tmp, ok := field.Args["prefix"]; ok {
+	var arg0 string
+	if tmp, ok := field.Args["name"]; ok {
 		var err error
-		arg1, err = graphql.UnmarshalString(tmp)
+		arg0, err = graphql.UnmarshalString(tmp)
 		if err != nil {
 			ec.Error(ctx, err)
 			return graphql.Null
 		}
 	}
-	args["prefix"] = arg1
+	args["name"] = arg0
 	rctx := graphql.GetResolverContext(ctx)
-	rctx.Object = "Mutation"
+	rctx.Object = "Query"
 	rctx.Args = args
 	rctx.Field = field
 	rctx.PushField(field.Alias)
 	defer rctx.Pop()
-	resTmp, err := ec.ResolverMiddleware(ctx, func(ctx context.Context) (interface{}, error) {
-		return ec.resolvers.Mutation_commit(ctx, args["repoRef"].(*string), args["prefix"].(string))
-	})
-	if err != nil {
-		ec.Error(ctx, err)
-		return graphql.Null
-	}
-	if resTmp == nil {
+	res := ec.introspectType(args["name"].(string))
+	if res == nil {
 		return graphql.Null
 	}
-	res := resTmp.(bug.Snapshot)
-	return ec._Bug(ctx, field.Selections, &res)
+	return ec.___Type(ctx, field.Selections, res)
 }
 
-var operationConnectionImplementors = []string{"OperationConnection"}
+var relationImplementors = []string{"Relation"}
 
 // nolint: gocyclo, errcheck, gas, goconst
-func (ec *executionContext) _OperationConnection(ctx context.Context, sel []query.Selection, obj *models.OperationConnection) graphql.Marshaler {
-	fields := graphql.CollectFields(ec.Doc, sel, operationConnectionImplementors, ec.Variables)
+func (ec *executionContext) _Relation(ctx context.Context, sel []query.Selection, obj *bug.Relation) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.Doc, sel, relationImplementors, ec.Variables)
 
 	out := graphql.NewOrderedMap(len(fields))
 	for i, field := range fields {
@@ -1741,15 +3342,11 @@ func (ec *executionContext) _OperationConnection(ctx context.Context, sel []quer
 
 		switch field.Name {
 		case "__typename":
-			out.Values[i] = graphql.MarshalString("OperationConnection")
-		case "edges":
-			out.Values[i] = ec._OperationConnection_edges(ctx, field, obj)
-		case "nodes":
-			out.Values[i] = ec._OperationConnection_nodes(ctx, field, obj)
-		case "pageInfo":
-			out.Values[i] = ec._OperationConnection_pageInfo(ctx, field, obj)
-		case "totalCount":
-			out.Values[i] = ec._OperationConnection_totalCount(ctx, field, obj)
+			out.Values[i] = graphql.MarshalString("Relation")
+		case "kind":
+			out.Values[i] = ec._Relation_kind(ctx, field, obj)
+		case "target":
+			out.Values[i] = ec._Relation_target(ctx, field, obj)
 		default:
 			panic("unknown field " + strconv.Quote(field.Name))
 		}
@@ -1758,73 +3355,140 @@ func (ec *executionContext) _OperationConnection(ctx context.Context, sel []quer
 	return out
 }
 
-func (ec *executionContext) _OperationConnection_edges(ctx context.Context, field graphql.CollectedField, obj *models.OperationConnection) graphql.Marshaler {
-	rctx := graphql.GetResolverContext(ctx)
-	rctx.Object = "OperationConnection"
-	rctx.Args = nil
-	rctx.Field = field
-	rctx.PushField(field.Alias)
-	defer rctx.Pop()
-	res := obj.Edges
-	arr1 := graphql.Array{}
-	for idx1 := range res {
-		arr1 = append(arr1, func() graphql.Marshaler {
-			rctx := graphql.GetResolverContext(ctx)
-			rctx.PushIndex(idx1)
-			defer rctx.Pop()
-			return ec._OperationEdge(ctx, field.Selections, &res[idx1])
-		}())
-	}
-	return arr1
+func (ec *executionContext) _Relation_kind(ctx context.Context, field graphql.CollectedField, obj *bug.Relation) graphql.Marshaler {
+	ctx = graphql.WithResolverContext(ctx, &graphql.ResolverContext{
+		Object: "Relation",
+		Args:   nil,
+		Field:  field,
+	})
+	return graphql.Defer(func() (ret graphql.Marshaler) {
+		defer func() {
+			if r := recover(); r != nil {
+				userErr := ec.Recover(ctx, r)
+				ec.Error(ctx, userErr)
+				ret = graphql.Null
+			}
+		}()
+
+		resTmp, err := ec.ResolverMiddleware(ctx, func(ctx context.Context) (interface{}, error) {
+			return ec.resolvers.Relation_kind(ctx, obj)
+		})
+		if err != nil {
+			ec.Error(ctx, err)
+			return graphql.Null
+		}
+		if resTmp == nil {
+			return graphql.Null
+		}
+		res := resTmp.(models.RelationKind)
+		return res
+	})
 }
 
-func (ec *executionContext) _OperationConnection_nodes(ctx context.Context, field graphql.CollectedField, obj *models.OperationConnection) graphql.Marshaler {
+func (ec *executionContext) _Relation_target(ctx context.Context, field graphql.CollectedField, obj *bug.Relation) graphql.Marshaler {
 	rctx := graphql.GetResolverContext(ctx)
-	rctx.Object = "OperationConnection"
+	rctx.Object = "Relation"
 	rctx.Args = nil
 	rctx.Field = field
 	rctx.PushField(field.Alias)
 	defer rctx.Pop()
-	res := obj.Nodes
-	arr1 := graphql.Array{}
-	for idx1 := range res {
-		arr1 = append(arr1, func() graphql.Marshaler {
-			rctx := graphql.GetResolverContext(ctx)
-			rctx.PushIndex(idx1)
-			defer rctx.Pop()
-			return ec._Operation(ctx, field.Selections, &res[idx1])
-		}())
+	res := obj.Target
+	return graphql.MarshalString(res)
+}
+
+var remapAuthorsOperationImplementors = []string{"RemapAuthorsOperation", "Operation", "Authored"}
+
+// nolint: gocyclo, errcheck, gas, goconst
+func (ec *executionContext) _RemapAuthorsOperation(ctx context.Context, sel []query.Selection, obj *operations.RemapAuthorsOperation) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.Doc, sel, remapAuthorsOperationImplementors, ec.Variables)
+
+	out := graphql.NewOrderedMap(len(fields))
+	for i, field := range fields {
+		out.Keys[i] = field.Alias
+
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("RemapAuthorsOperation")
+		case "author":
+			out.Values[i] = ec._RemapAuthorsOperation_author(ctx, field, obj)
+		case "date":
+			out.Values[i] = ec._RemapAuthorsOperation_date(ctx, field, obj)
+		case "remaps":
+			out.Values[i] = ec._RemapAuthorsOperation_remaps(ctx, field, obj)
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
 	}
-	return arr1
+
+	return out
 }
 
-func (ec *executionContext) _OperationConnection_pageInfo(ctx context.Context, field graphql.CollectedField, obj *models.OperationConnection) graphql.Marshaler {
+func (ec *executionContext) _RemapAuthorsOperation_author(ctx context.Context, field graphql.CollectedField, obj *operations.RemapAuthorsOperation) graphql.Marshaler {
 	rctx := graphql.GetResolverContext(ctx)
-	rctx.Object = "OperationConnection"
+	rctx.Object = "RemapAuthorsOperation"
 	rctx.Args = nil
 	rctx.Field = field
 	rctx.PushField(field.Alias)
 	defer rctx.Pop()
-	res := obj.PageInfo
-	return ec._PageInfo(ctx, field.Selections, &res)
+	res := obj.Author
+	return ec._Person(ctx, field.Selections, &res)
+}
+
+func (ec *executionContext) _RemapAuthorsOperation_date(ctx context.Context, field graphql.CollectedField, obj *operations.RemapAuthorsOperation) graphql.Marshaler {
+	ctx = graphql.WithResolverContext(ctx, &graphql.ResolverContext{
+		Object: "RemapAuthorsOperation",
+		Args:   nil,
+		Field:  field,
+	})
+	return graphql.Defer(func() (ret graphql.Marshaler) {
+		defer func() {
+			if r := recover(); r != nil {
+				userErr := ec.Recover(ctx, r)
+				ec.Error(ctx, userErr)
+				ret = graphql.Null
+			}
+		}()
+
+		resTmp, err := ec.ResolverMiddleware(ctx, func(ctx context.Context) (interface{}, error) {
+			return ec.resolvers.RemapAuthorsOperation_date(ctx, obj)
+		})
+		if err != nil {
+			ec.Error(ctx, err)
+			return graphql.Null
+		}
+		if resTmp == nil {
+			return graphql.Null
+		}
+		res := resTmp.(time.Time)
+		return graphql.MarshalTime(res)
+	})
 }
 
-func (ec *executionContext) _OperationConnection_totalCount(ctx context.Context, field graphql.CollectedField, obj *models.OperationConnection) graphql.Marshaler {
+func (ec *executionContext) _RemapAuthorsOperation_remaps(ctx context.Context, field graphql.CollectedField, obj *operations.RemapAuthorsOperation) graphql.Marshaler {
 	rctx := graphql.GetResolverContext(ctx)
-	rctx.Object = "OperationConnection"
+	rctx.Object = "RemapAuthorsOperation"
 	rctx.Args = nil
 	rctx.Field = field
 	rctx.PushField(field.Alias)
 	defer rctx.Pop()
-	res := obj.TotalCount
-	return graphql.MarshalInt(res)
+	res := obj.Remaps
+	arr1 := graphql.Array{}
+	for idx1 := range res {
+		arr1 = append(arr1, func() graphql.Marshaler {
+			rctx := graphql.GetResolverContext(ctx)
+			rctx.PushIndex(idx1)
+			defer rctx.Pop()
+			return ec._AuthorRemap(ctx, field.Selections, &res[idx1])
+		}())
+	}
+	return arr1
 }
 
-var operationEdgeImplementors = []string{"OperationEdge"}
+var repositoryImplementors = []string{"Repository"}
 
 // nolint: gocyclo, errcheck, gas, goconst
-func (ec *executionContext) _OperationEdge(ctx context.Context, sel []query.Selection, obj *models.OperationEdge) graphql.Marshaler {
-	fields := graphql.CollectFields(ec.Doc, sel, operationEdgeImplementors, ec.Variables)
+func (ec *executionContext) _Repository(ctx context.Context, sel []query.Selection, obj *models.Repository) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.Doc, sel, repositoryImplementors, ec.Variables)
 
 	out := graphql.NewOrderedMap(len(fields))
 	for i, field := range fields {
@@ -1832,11 +3496,15 @@ func (ec *executionContext) _OperationEdge(ctx context.Context, sel []query.Sele
 
 		switch field.Name {
 		case "__typename":
-			out.Values[i] = graphql.MarshalString("OperationEdge")
-		case "cursor":
-			out.Values[i] = ec._OperationEdge_cursor(ctx, field, obj)
-		case "node":
-			out.Values[i] = ec._OperationEdge_node(ctx, field, obj)
+			out.Values[i] = graphql.MarshalString("Repository")
+		case "id":
+			out.Values[i] = ec._Repository_id(ctx, field, obj)
+		case "allBugs":
+			out.Values[i] = ec._Repository_allBugs(ctx, field, obj)
+		case "bug":
+			out.Values[i] = ec._Repository_bug(ctx, field, obj)
+		case "labels":
+			out.Values[i] = ec._Repository_labels(ctx, field, obj)
 		default:
 			panic("unknown field " + strconv.Quote(field.Name))
 		}
@@ -1845,33 +3513,211 @@ func (ec *executionContext) _OperationEdge(ctx context.Context, sel []query.Sele
 	return out
 }
 
-func (ec *executionContext) _OperationEdge_cursor(ctx context.Context, field graphql.CollectedField, obj *models.OperationEdge) graphql.Marshaler {
+func (ec *executionContext) _Repository_id(ctx context.Context, field graphql.CollectedField, obj *models.Repository) graphql.Marshaler {
 	rctx := graphql.GetResolverContext(ctx)
-	rctx.Object = "OperationEdge"
+	rctx.Object = "Repository"
 	rctx.Args = nil
 	rctx.Field = field
 	rctx.PushField(field.Alias)
 	defer rctx.Pop()
-	res := obj.Cursor
+	res := obj.Id
 	return graphql.MarshalString(res)
 }
 
-func (ec *executionContext) _OperationEdge_node(ctx context.Context, field graphql.CollectedField, obj *models.OperationEdge) graphql.Marshaler {
-	rctx := graphql.GetResolverContext(ctx)
-	rctx.Object = "OperationEdge"
-	rctx.Args = nil
-	rctx.Field = field
-	rctx.PushField(field.Alias)
-	defer rctx.Pop()
-	res := obj.Node
-	return ec._Operation(ctx, field.Selections, &res)
+func (ec *executionContext) _Repository_allBugs(ctx context.Context, field graphql.CollectedField, obj *models.Repository) graphql.Marshaler {
+	args := map[string]interface{}{}
+	var arg0 *string
+	if tmp, ok := field.Args["query"]; ok {
+		var err error
+		var ptr1 string
+		if tmp != nil {
+			ptr1, err = graphql.UnmarshalString(tmp)
+			arg0 = &ptr1
+		}
+
+		if err != nil {
+			ec.Error(ctx, err)
+			return graphql.Null
+		}
+	}
+	args["query"] = arg0
+	var arg1 *string
+	if tmp, ok := field.Args["after"]; ok {
+		var err error
+		var ptr1 string
+		if tmp != nil {
+			ptr1, err = graphql.UnmarshalString(tmp)
+			arg1 = &ptr1
+		}
+
+		if err != nil {
+			ec.Error(ctx, err)
+			return graphql.Null
+		}
+	}
+	args["after"] = arg1
+	var arg2 *string
+	if tmp, ok := field.Args["before"]; ok {
+		var err error
+		var ptr1 string
+		if tmp != nil {
+			ptr1, err = graphql.UnmarshalString(tmp)
+			arg2 = &ptr1
+		}
+
+		if err != nil {
+			ec.Error(ctx, err)
+			return graphql.Null
+		}
+	}
+	args["before"] = arg2
+	var arg3 *int
+	if tmp, ok := field.Args["first"]; ok {
+		var err error
+		var ptr1 int
+		if tmp != nil {
+			ptr1, err = graphql.UnmarshalInt(tmp)
+			arg3 = &ptr1
+		}
+
+		if err != nil {
+			ec.Error(ctx, err)
+			return graphql.Null
+		}
+	}
+	args["first"] = arg3
+	var arg4 *int
+	if tmp, ok := field.Args["last"]; ok {
+		var err error
+		var ptr1 int
+		if tmp != nil {
+			ptr1, err = graphql.UnmarshalInt(tmp)
+			arg4 = &ptr1
+		}
+
+		if err != nil {
+			ec.Error(ctx, err)
+			return graphql.Null
+		}
+	}
+	args["last"] = arg4
+	ctx = graphql.WithResolverContext(ctx, &graphql.ResolverContext{
+		Object: "Repository",
+		Args:   args,
+		Field:  field,
+	})
+	return graphql.Defer(func() (ret graphql.Marshaler) {
+		defer func() {
+			if r := recover(); r != nil {
+				userErr := ec.Recover(ctx, r)
+				ec.Error(ctx, userErr)
+				ret = graphql.Null
+			}
+		}()
+
+		resTmp, err := ec.ResolverMiddleware(ctx, func(ctx context.Context) (interface{}, error) {
+			return ec.resolvers.Repository_allBugs(ctx, obj, args["query"].(*string), args["after"].(*string), args["before"].(*string), args["first"].(*int), args["last"].(*int))
+		})
+		if err != nil {
+			ec.Error(ctx, err)
+			return graphql.Null
+		}
+		if resTmp == nil {
+			return graphql.Null
+		}
+		res := resTmp.(models.BugConnection)
+		return ec._BugConnection(ctx, field.Selections, &res)
+	})
 }
 
-var pageInfoImplementors = []string{"PageInfo"}
+func (ec *executionContext) _Repository_bug(ctx context.Context, field graphql.CollectedField, obj *models.Repository) graphql.Marshaler {
+	args := map[string]interface{}{}
+	var arg0 string
+	if tmp, ok := field.Args["prefix"]; ok {
+		var err error
+		arg0, err = graphql.UnmarshalString(tmp)
+		if err != nil {
+			ec.Error(ctx, err)
+			return graphql.Null
+		}
+	}
+	args["prefix"] = arg0
+	ctx = graphql.WithResolverContext(ctx, &graphql.ResolverContext{
+		Object: "Repository",
+		Args:   args,
+		Field:  field,
+	})
+	return graphql.Defer(func() (ret graphql.Marshaler) {
+		defer func() {
+			if r := recover(); r != nil {
+				userErr := ec.Recover(ctx, r)
+				ec.Error(ctx, userErr)
+				ret = graphql.Null
+			}
+		}()
+
+		resTmp, err := ec.ResolverMiddleware(ctx, func(ctx context.Context) (interface{}, error) {
+			return ec.resolvers.Repository_bug(ctx, obj, args["prefix"].(string))
+		})
+		if err != nil {
+			ec.Error(ctx, err)
+			return graphql.Null
+		}
+		if resTmp == nil {
+			return graphql.Null
+		}
+		res := resTmp.(*bug.Snapshot)
+		if res == nil {
+			return graphql.Null
+		}
+		return ec._Bug(ctx, field.Selections, res)
+	})
+}
+
+func (ec *executionContext) _Repository_labels(ctx context.Context, field graphql.CollectedField, obj *models.Repository) graphql.Marshaler {
+	ctx = graphql.WithResolverContext(ctx, &graphql.ResolverContext{
+		Object: "Repository",
+		Args:   nil,
+		Field:  field,
+	})
+	return graphql.Defer(func() (ret graphql.Marshaler) {
+		defer func() {
+			if r := recover(); r != nil {
+				userErr := ec.Recover(ctx, r)
+				ec.Error(ctx, userErr)
+				ret = graphql.Null
+			}
+		}()
+
+		resTmp, err := ec.ResolverMiddleware(ctx, func(ctx context.Context) (interface{}, error) {
+			return ec.resolvers.Repository_labels(ctx, obj)
+		})
+		if err != nil {
+			ec.Error(ctx, err)
+			return graphql.Null
+		}
+		if resTmp == nil {
+			return graphql.Null
+		}
+		res := resTmp.([]models.LabelInfo)
+		arr1 := graphql.Array{}
+		for idx1 := range res {
+			arr1 = append(arr1, func() graphql.Marshaler {
+				rctx := graphql.GetResolverContext(ctx)
+				rctx.PushIndex(idx1)
+				defer rctx.Pop()
+				return ec._LabelInfo(ctx, field.Selections, &res[idx1])
+			}())
+		}
+		return arr1
+	})
+}
+
+var setChecklistOperationImplementors = []string{"SetChecklistOperation", "Operation", "Authored"}
 
 // nolint: gocyclo, errcheck, gas, goconst
-func (ec *executionContext) _PageInfo(ctx context.Context, sel []query.Selection, obj *models.PageInfo) graphql.Marshaler {
-	fields := graphql.CollectFields(ec.Doc, sel, pageInfoImplementors, ec.Variables)
+func (ec *executionContext) _SetChecklistOperation(ctx context.Context, sel []query.Selection, obj *operations.SetChecklistOperation) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.Doc, sel, setChecklistOperationImplementors, ec.Variables)
 
 	out := graphql.NewOrderedMap(len(fields))
 	for i, field := range fields {
@@ -1879,15 +3725,15 @@ func (ec *executionContext) _PageInfo(ctx context.Context, sel []query.Selection
 
 		switch field.Name {
 		case "__typename":
-			out.Values[i] = graphql.MarshalString("PageInfo")
-		case "hasNextPage":
-			out.Values[i] = ec._PageInfo_hasNextPage(ctx, field, obj)
-		case "hasPreviousPage":
-			out.Values[i] = ec._PageInfo_hasPreviousPage(ctx, field, obj)
-		case "startCursor":
-			out.Values[i] = ec._PageInfo_startCursor(ctx, field, obj)
-		case "endCursor":
-			out.Values[i] = ec._PageInfo_endCursor(ctx, field, obj)
+			out.Values[i] = graphql.MarshalString("SetChecklistOperation")
+		case "author":
+			out.Values[i] = ec._SetChecklistOperation_author(ctx, field, obj)
+		case "date":
+			out.Values[i] = ec._SetChecklistOperation_date(ctx, field, obj)
+		case "checklist":
+			out.Values[i] = ec._SetChecklistOperation_checklist(ctx, field, obj)
+		case "items":
+			out.Values[i] = ec._SetChecklistOperation_items(ctx, field, obj)
 		default:
 			panic("unknown field " + strconv.Quote(field.Name))
 		}
@@ -1896,55 +3742,83 @@ func (ec *executionContext) _PageInfo(ctx context.Context, sel []query.Selection
 	return out
 }
 
-func (ec *executionContext) _PageInfo_hasNextPage(ctx context.Context, field graphql.CollectedField, obj *models.PageInfo) graphql.Marshaler {
+func (ec *executionContext) _SetChecklistOperation_author(ctx context.Context, field graphql.CollectedField, obj *operations.SetChecklistOperation) graphql.Marshaler {
 	rctx := graphql.GetResolverContext(ctx)
-	rctx.Object = "PageInfo"
+	rctx.Object = "SetChecklistOperation"
 	rctx.Args = nil
 	rctx.Field = field
 	rctx.PushField(field.Alias)
 	defer rctx.Pop()
-	res := obj.HasNextPage
-	return graphql.MarshalBoolean(res)
+	res := obj.Author
+	return ec._Person(ctx, field.Selections, &res)
 }
 
-func (ec *executionContext) _PageInfo_hasPreviousPage(ctx context.Context, field graphql.CollectedField, obj *models.PageInfo) graphql.Marshaler {
-	rctx := graphql.GetResolverContext(ctx)
-	rctx.Object = "PageInfo"
-	rctx.Args = nil
-	rctx.Field = field
-	rctx.PushField(field.Alias)
-	defer rctx.Pop()
-	res := obj.HasPreviousPage
-	return graphql.MarshalBoolean(res)
+func (ec *executionContext) _SetChecklistOperation_date(ctx context.Context, field graphql.CollectedField, obj *operations.SetChecklistOperation) graphql.Marshaler {
+	ctx = graphql.WithResolverContext(ctx, &graphql.ResolverContext{
+		Object: "SetChecklistOperation",
+		Args:   nil,
+		Field:  field,
+	})
+	return graphql.Defer(func() (ret graphql.Marshaler) {
+		defer func() {
+			if r := recover(); r != nil {
+				userErr := ec.Recover(ctx, r)
+				ec.Error(ctx, userErr)
+				ret = graphql.Null
+			}
+		}()
+
+		resTmp, err := ec.ResolverMiddleware(ctx, func(ctx context.Context) (interface{}, error) {
+			return ec.resolvers.SetChecklistOperation_date(ctx, obj)
+		})
+		if err != nil {
+			ec.Error(ctx, err)
+			return graphql.Null
+		}
+		if resTmp == nil {
+			return graphql.Null
+		}
+		res := resTmp.(time.Time)
+		return graphql.MarshalTime(res)
+	})
 }
 
-func (ec *executionContext) _PageInfo_startCursor(ctx context.Context, field graphql.CollectedField, obj *models.PageInfo) graphql.Marshaler {
+func (ec *executionContext) _SetChecklistOperation_checklist(ctx context.Context, field graphql.CollectedField, obj *operations.SetChecklistOperation) graphql.Marshaler {
 	rctx := graphql.GetResolverContext(ctx)
-	rctx.Object = "PageInfo"
+	rctx.Object = "SetChecklistOperation"
 	rctx.Args = nil
 	rctx.Field = field
 	rctx.PushField(field.Alias)
 	defer rctx.Pop()
-	res := obj.StartCursor
+	res := obj.Checklist
 	return graphql.MarshalString(res)
 }
 
-func (ec *executionContext) _PageInfo_endCursor(ctx context.Context, field graphql.CollectedField, obj *models.PageInfo) graphql.Marshaler {
+func (ec *executionContext) _SetChecklistOperation_items(ctx context.Context, field graphql.CollectedField, obj *operations.SetChecklistOperation) graphql.Marshaler {
 	rctx := graphql.GetResolverContext(ctx)
-	rctx.Object = "PageInfo"
+	rctx.Object = "SetChecklistOperation"
 	rctx.Args = nil
 	rctx.Field = field
 	rctx.PushField(field.Alias)
 	defer rctx.Pop()
-	res := obj.EndCursor
-	return graphql.MarshalString(res)
+	res := obj.Items
+	arr1 := graphql.Array{}
+	for idx1 := range res {
+		arr1 = append(arr1, func() graphql.Marshaler {
+			rctx := graphql.GetResolverContext(ctx)
+			rctx.PushIndex(idx1)
+			defer rctx.Pop()
+			return ec._ChecklistItem(ctx, field.Selections, &res[idx1])
+		}())
+	}
+	return arr1
 }
 
-var personImplementors = []string{"Person"}
+var setCustomFieldOperationImplementors = []string{"SetCustomFieldOperation", "Operation", "Authored"}
 
 // nolint: gocyclo, errcheck, gas, goconst
-func (ec *executionContext) _Person(ctx context.Context, sel []query.Selection, obj *bug.Person) graphql.Marshaler {
-	fields := graphql.CollectFields(ec.Doc, sel, personImplementors, ec.Variables)
+func (ec *executionContext) _SetCustomFieldOperation(ctx context.Context, sel []query.Selection, obj *operations.SetCustomFieldOperation) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.Doc, sel, setCustomFieldOperationImplementors, ec.Variables)
 
 	out := graphql.NewOrderedMap(len(fields))
 	for i, field := range fields {
@@ -1952,11 +3826,15 @@ func (ec *executionContext) _Person(ctx context.Context, sel []query.Selection,
 
 		switch field.Name {
 		case "__typename":
-			out.Values[i] = graphql.MarshalString("Person")
-		case "email":
-			out.Values[i] = ec._Person_email(ctx, field, obj)
-		case "name":
-			out.Values[i] = ec._Person_name(ctx, field, obj)
+			out.Values[i] = graphql.MarshalString("SetCustomFieldOperation")
+		case "author":
+			out.Values[i] = ec._SetCustomFieldOperation_author(ctx, field, obj)
+		case "date":
+			out.Values[i] = ec._SetCustomFieldOperation_date(ctx, field, obj)
+		case "key":
+			out.Values[i] = ec._SetCustomFieldOperation_key(ctx, field, obj)
+		case "value":
+			out.Values[i] = ec._SetCustomFieldOperation_value(ctx, field, obj)
 		default:
 			panic("unknown field " + strconv.Quote(field.Name))
 		}
@@ -1965,37 +3843,74 @@ func (ec *executionContext) _Person(ctx context.Context, sel []query.Selection,
 	return out
 }
 
-func (ec *executionContext) _Person_email(ctx context.Context, field graphql.CollectedField, obj *bug.Person) graphql.Marshaler {
+func (ec *executionContext) _SetCustomFieldOperation_author(ctx context.Context, field graphql.CollectedField, obj *operations.SetCustomFieldOperation) graphql.Marshaler {
 	rctx := graphql.GetResolverContext(ctx)
-	rctx.Object = "Person"
+	rctx.Object = "SetCustomFieldOperation"
 	rctx.Args = nil
 	rctx.Field = field
 	rctx.PushField(field.Alias)
 	defer rctx.Pop()
-	res := obj.Email
+	res := obj.Author
+	return ec._Person(ctx, field.Selections, &res)
+}
+
+func (ec *executionContext) _SetCustomFieldOperation_date(ctx context.Context, field graphql.CollectedField, obj *operations.SetCustomFieldOperation) graphql.Marshaler {
+	ctx = graphql.WithResolverContext(ctx, &graphql.ResolverContext{
+		Object: "SetCustomFieldOperation",
+		Args:   nil,
+		Field:  field,
+	})
+	return graphql.Defer(func() (ret graphql.Marshaler) {
+		defer func() {
+			if r := recover(); r != nil {
+				userErr := ec.Recover(ctx, r)
+				ec.Error(ctx, userErr)
+				ret = graphql.Null
+			}
+		}()
+
+		resTmp, err := ec.ResolverMiddleware(ctx, func(ctx context.Context) (interface{}, error) {
+			return ec.resolvers.SetCustomFieldOperation_date(ctx, obj)
+		})
+		if err != nil {
+			ec.Error(ctx, err)
+			return graphql.Null
+		}
+		if resTmp == nil {
+			return graphql.Null
+		}
+		res := resTmp.(time.Time)
+		return graphql.MarshalTime(res)
+	})
+}
+
+func (ec *executionContext) _SetCustomFieldOperation_key(ctx context.Context, field graphql.CollectedField, obj *operations.SetCustomFieldOperation) graphql.Marshaler {
+	rctx := graphql.GetResolverContext(ctx)
+	rctx.Object = "SetCustomFieldOperation"
+	rctx.Args = nil
+	rctx.Field = field
+	rctx.PushField(field.Alias)
+	defer rctx.Pop()
+	res := obj.Key
 	return graphql.MarshalString(res)
 }
 
-func (ec *executionContext) _Person_name(ctx context.Context, field graphql.CollectedField, obj *bug.Person) graphql.Marshaler {
+func (ec *executionContext) _SetCustomFieldOperation_value(ctx context.Context, field graphql.CollectedField, obj *operations.SetCustomFieldOperation) graphql.Marshaler {
 	rctx := graphql.GetResolverContext(ctx)
-	rctx.Object = "Person"
+	rctx.Object = "SetCustomFieldOperation"
 	rctx.Args = nil
 	rctx.Field = field
 	rctx.PushField(field.Alias)
 	defer rctx.Pop()
-	res := obj.Name
+	res := obj.Value
 	return graphql.MarshalString(res)
 }
 
-var queryImplementors = []string{"Query"}
+var setPriorityOperationImplementors = []string{"SetPriorityOperation", "Operation", "Authored"}
 
 // nolint: gocyclo, errcheck, gas, goconst
-func (ec *executionContext) _Query(ctx context.Context, sel []query.Selection) graphql.Marshaler {
-	fields := graphql.CollectFields(ec.Doc, sel, queryImplementors, ec.Variables)
-
-	ctx = graphql.WithResolverContext(ctx, &graphql.ResolverContext{
-		Object: "Query",
-	})
+func (ec *executionContext) _SetPriorityOperation(ctx context.Context, sel []query.Selection, obj *operations.SetPriorityOperation) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.Doc, sel, setPriorityOperationImplementors, ec.Variables)
 
 	out := graphql.NewOrderedMap(len(fields))
 	for i, field := range fields {
@@ -2003,15 +3918,13 @@ func (ec *executionContext) _Query(ctx context.Context, sel []query.Selection) g
 
 		switch field.Name {
 		case "__typename":
-			out.Values[i] = graphql.MarshalString("Query")
-		case "defaultRepository":
-			out.Values[i] = ec._Query_defaultRepository(ctx, field)
-		case "repository":
-			out.Values[i] = ec._Query_repository(ctx, field)
-		case "__schema":
-			out.Values[i] = ec._Query___schema(ctx, field)
-		case "__type":
-			out.Values[i] = ec._Query___type(ctx, field)
+			out.Values[i] = graphql.MarshalString("SetPriorityOperation")
+		case "author":
+			out.Values[i] = ec._SetPriorityOperation_author(ctx, field, obj)
+		case "date":
+			out.Values[i] = ec._SetPriorityOperation_date(ctx, field, obj)
+		case "priority":
+			out.Values[i] = ec._SetPriorityOperation_priority(ctx, field, obj)
 		default:
 			panic("unknown field " + strconv.Quote(field.Name))
 		}
@@ -2020,9 +3933,20 @@ func (ec *executionContext) _Query(ctx context.Context, sel []query.Selection) g
 	return out
 }
 
-func (ec *executionContext) _Query_defaultRepository(ctx context.Context, field graphql.CollectedField) graphql.Marshaler {
+func (ec *executionContext) _SetPriorityOperation_author(ctx context.Context, field graphql.CollectedField, obj *operations.SetPriorityOperation) graphql.Marshaler {
+	rctx := graphql.GetResolverContext(ctx)
+	rctx.Object = "SetPriorityOperation"
+	rctx.Args = nil
+	rctx.Field = field
+	rctx.PushField(field.Alias)
+	defer rctx.Pop()
+	res := obj.Author
+	return ec._Person(ctx, field.Selections, &res)
+}
+
+func (ec *executionContext) _SetPriorityOperation_date(ctx context.Context, field graphql.CollectedField, obj *operations.SetPriorityOperation) graphql.Marshaler {
 	ctx = graphql.WithResolverContext(ctx, &graphql.ResolverContext{
-		Object: "Query",
+		Object: "SetPriorityOperation",
 		Args:   nil,
 		Field:  field,
 	})
@@ -2036,7 +3960,7 @@ func (ec *executionContext) _Query_defaultRepository(ctx context.Context, field
 		}()
 
 		resTmp, err := ec.ResolverMiddleware(ctx, func(ctx context.Context) (interface{}, error) {
-			return ec.resolvers.Query_defaultRepository(ctx)
+			return ec.resolvers.SetPriorityOperation_date(ctx, obj)
 		})
 		if err != nil {
 			ec.Error(ctx, err)
@@ -2045,29 +3969,15 @@ func (ec *executionContext) _Query_defaultRepository(ctx context.Context, field
 		if resTmp == nil {
 			return graphql.Null
 		}
-		res := resTmp.(*models.Repository)
-		if res == nil {
-			return graphql.Null
-		}
-		return ec._Repository(ctx, field.Selections, res)
+		res := resTmp.(time.Time)
+		return graphql.MarshalTime(res)
 	})
 }
 
-func (ec *executionContext) _Query_repository(ctx context.Context, field graphql.CollectedField) graphql.Marshaler {
-	args := map[string]interface{}{}
-	var arg0 string
-	if tmp, ok := field.Args["id"]; ok {
-		var err error
-		arg0, err = graphql.UnmarshalString(tmp)
-		if err != nil {
-			ec.Error(ctx, err)
-			return graphql.Null
-		}
-	}
-	args["id"] = arg0
+func (ec *executionContext) _SetPriorityOperation_priority(ctx context.Context, field graphql.CollectedField, obj *operations.SetPriorityOperation) graphql.Marshaler {
 	ctx = graphql.WithResolverContext(ctx, &graphql.ResolverContext{
-		Object: "Query",
-		Args:   args,
+		Object: "SetPriorityOperation",
+		Args:   nil,
 		Field:  field,
 	})
 	return graphql.Defer(func() (ret graphql.Marshaler) {
@@ -2080,7 +3990,7 @@ func (ec *executionContext) _Query_repository(ctx context.Context, field graphql
 		}()
 
 		resTmp, err := ec.ResolverMiddleware(ctx, func(ctx context.Context) (interface{}, error) {
-			return ec.resolvers.Query_repository(ctx, args["id"].(string))
+			return ec.resolvers.SetPriorityOperation_priority(ctx, obj)
 		})
 		if err != nil {
 			ec.Error(ctx, err)
@@ -2089,143 +3999,55 @@ func (ec *executionContext) _Query_repository(ctx context.Context, field graphql
 		if resTmp == nil {
 			return graphql.Null
 		}
-		res := resTmp.(*models.Repository)
-		if res == nil {
-			return graphql.Null
-		}
-		return ec._Repository(ctx, field.Selections, res)
+		res := resTmp.(models.Priority)
+		return res
 	})
 }
 
-func (ec *executionContext) _Query___schema(ctx context.Context, field graphql.CollectedField) graphql.Marshaler {
-	rctx := graphql.GetResolverContext(ctx)
-	rctx.Object = "Query"
-	rctx.Args = nil
-	rctx.Field = field
-	rctx.PushField(field.Alias)
-	defer rctx.Pop()
-	res := ec.introspectSchema()
-	if res == nil {
-		return graphql.Null
-	}
-	return ec.___Schema(ctx, field.Selections, res)
-}
-
-func (ec *executionContext) _Query___type(ctx context.Context, field graphql.CollectedField) graphql.Marshaler {
-	args := map[string]interface{}{}
-	var arg0 string
-	if tmp, ok := field.Args["name"]; ok {
-		var err error
-		arg0, err = graphql.UnmarshalString(tmp)
-		if err != nil {
-			ec.Error(ctx, err)
-			return graphql.Null
-		}
-	}
-	args["name"] = arg0
-	rctx := graphql.GetResolverContext(ctx)
-	rctx.Object = "Query"
-	rctx.Args = args
-	rctx.Field = field
-	rctx.PushField(field.Alias)
-	defer rctx.Pop()
-	res := ec.introspectType(args["name"].(string))
-	if res == nil {
-		return graphql.Null
-	}
-	return ec.___Type(ctx, field.Selections, res)
-}
-
-var repositoryImplementors = []string{"Repository"}
+var setRelationOperationImplementors = []string{"SetRelationOperation", "Operation", "Authored"}
 
 // nolint: gocyclo, errcheck, gas, goconst
-func (ec *executionContext) _Repository(ctx context.Context, sel []query.Selection, obj *models.Repository) graphql.Marshaler {
-	fields := graphql.CollectFields(ec.Doc, sel, repositoryImplementors, ec.Variables)
+func (ec *executionContext) _SetRelationOperation(ctx context.Context, sel []query.Selection, obj *operations.SetRelationOperation) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.Doc, sel, setRelationOperationImplementors, ec.Variables)
 
 	out := graphql.NewOrderedMap(len(fields))
 	for i, field := range fields {
-		out.Keys[i] = field.Alias
-
-		switch field.Name {
-		case "__typename":
-			out.Values[i] = graphql.MarshalString("Repository")
-		case "allBugs":
-			out.Values[i] = ec._Repository_allBugs(ctx, field, obj)
-		case "bug":
-			out.Values[i] = ec._Repository_bug(ctx, field, obj)
-		default:
-			panic("unknown field " + strconv.Quote(field.Name))
-		}
-	}
-
-	return out
-}
-
-func (ec *executionContext) _Repository_allBugs(ctx context.Context, field graphql.CollectedField, obj *models.Repository) graphql.Marshaler {
-	args := map[string]interface{}{}
-	var arg0 *string
-	if tmp, ok := field.Args["after"]; ok {
-		var err error
-		var ptr1 string
-		if tmp != nil {
-			ptr1, err = graphql.UnmarshalString(tmp)
-			arg0 = &ptr1
-		}
-
-		if err != nil {
-			ec.Error(ctx, err)
-			return graphql.Null
-		}
-	}
-	args["after"] = arg0
-	var arg1 *string
-	if tmp, ok := field.Args["before"]; ok {
-		var err error
-		var ptr1 string
-		if tmp != nil {
-			ptr1, err = graphql.UnmarshalString(tmp)
-			arg1 = &ptr1
-		}
-
-		if err != nil {
-			ec.Error(ctx, err)
-			return graphql.Null
-		}
-	}
-	args["before"] = arg1
-	var arg2 *int
-	if tmp, ok := field.Args["first"]; ok {
-		var err error
-		var ptr1 int
-		if tmp != nil {
-			ptr1, err = graphql.UnmarshalInt(tmp)
-			arg2 = &ptr1
-		}
-
-		if err != nil {
-			ec.Error(ctx, err)
-			return graphql.Null
-		}
-	}
-	args["first"] = arg2
-	var arg3 *int
-	if tmp, ok := field.Args["last"]; ok {
-		var err error
-		var ptr1 int
-		if tmp != nil {
-			ptr1, err = graphql.UnmarshalInt(tmp)
-			arg3 = &ptr1
-		}
-
-		if err != nil {
-			ec.Error(ctx, err)
-			return graphql.Null
+		out.Keys[i] = field.Alias
+
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("SetRelationOperation")
+		case "author":
+			out.Values[i] = ec._SetRelationOperation_author(ctx, field, obj)
+		case "date":
+			out.Values[i] = ec._SetRelationOperation_date(ctx, field, obj)
+		case "kind":
+			out.Values[i] = ec._SetRelationOperation_kind(ctx, field, obj)
+		case "target":
+			out.Values[i] = ec._SetRelationOperation_target(ctx, field, obj)
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
 		}
 	}
-	args["last"] = arg3
+
+	return out
+}
+
+func (ec *executionContext) _SetRelationOperation_author(ctx context.Context, field graphql.CollectedField, obj *operations.SetRelationOperation) graphql.Marshaler {
+	rctx := graphql.GetResolverContext(ctx)
+	rctx.Object = "SetRelationOperation"
+	rctx.Args = nil
+	rctx.Field = field
+	rctx.PushField(field.Alias)
+	defer rctx.Pop()
+	res := obj.Author
+	return ec._Person(ctx, field.Selections, &res)
+}
+
+func (ec *executionContext) _SetRelationOperation_date(ctx context.Context, field graphql.CollectedField, obj *operations.SetRelationOperation) graphql.Marshaler {
 	ctx = graphql.WithResolverContext(ctx, &graphql.ResolverContext{
-		Object: "Repository",
-		Args:   args,
+		Object: "SetRelationOperation",
+		Args:   nil,
 		Field:  field,
 	})
 	return graphql.Defer(func() (ret graphql.Marshaler) {
@@ -2238,7 +4060,7 @@ func (ec *executionContext) _Repository_allBugs(ctx context.Context, field graph
 		}()
 
 		resTmp, err := ec.ResolverMiddleware(ctx, func(ctx context.Context) (interface{}, error) {
-			return ec.resolvers.Repository_allBugs(ctx, obj, args["after"].(*string), args["before"].(*string), args["first"].(*int), args["last"].(*int))
+			return ec.resolvers.SetRelationOperation_date(ctx, obj)
 		})
 		if err != nil {
 			ec.Error(ctx, err)
@@ -2247,26 +4069,15 @@ func (ec *executionContext) _Repository_allBugs(ctx context.Context, field graph
 		if resTmp == nil {
 			return graphql.Null
 		}
-		res := resTmp.(models.BugConnection)
-		return ec._BugConnection(ctx, field.Selections, &res)
+		res := resTmp.(time.Time)
+		return graphql.MarshalTime(res)
 	})
 }
 
-func (ec *executionContext) _Repository_bug(ctx context.Context, field graphql.CollectedField, obj *models.Repository) graphql.Marshaler {
-	args := map[string]interface{}{}
-	var arg0 string
-	if tmp, ok := field.Args["prefix"]; ok {
-		var err error
-		arg0, err = graphql.UnmarshalString(tmp)
-		if err != nil {
-			ec.Error(ctx, err)
-			return graphql.Null
-		}
-	}
-	args["prefix"] = arg0
+func (ec *executionContext) _SetRelationOperation_kind(ctx context.Context, field graphql.CollectedField, obj *operations.SetRelationOperation) graphql.Marshaler {
 	ctx = graphql.WithResolverContext(ctx, &graphql.ResolverContext{
-		Object: "Repository",
-		Args:   args,
+		Object: "SetRelationOperation",
+		Args:   nil,
 		Field:  field,
 	})
 	return graphql.Defer(func() (ret graphql.Marshaler) {
@@ -2279,7 +4090,7 @@ func (ec *executionContext) _Repository_bug(ctx context.Context, field graphql.C
 		}()
 
 		resTmp, err := ec.ResolverMiddleware(ctx, func(ctx context.Context) (interface{}, error) {
-			return ec.resolvers.Repository_bug(ctx, obj, args["prefix"].(string))
+			return ec.resolvers.SetRelationOperation_kind(ctx, obj)
 		})
 		if err != nil {
 			ec.Error(ctx, err)
@@ -2288,14 +4099,22 @@ func (ec *executionContext) _Repository_bug(ctx context.Context, field graphql.C
 		if resTmp == nil {
 			return graphql.Null
 		}
-		res := resTmp.(*bug.Snapshot)
-		if res == nil {
-			return graphql.Null
-		}
-		return ec._Bug(ctx, field.Selections, res)
+		res := resTmp.(models.RelationKind)
+		return res
 	})
 }
 
+func (ec *executionContext) _SetRelationOperation_target(ctx context.Context, field graphql.CollectedField, obj *operations.SetRelationOperation) graphql.Marshaler {
+	rctx := graphql.GetResolverContext(ctx)
+	rctx.Object = "SetRelationOperation"
+	rctx.Args = nil
+	rctx.Field = field
+	rctx.PushField(field.Alias)
+	defer rctx.Pop()
+	res := obj.Target
+	return graphql.MarshalString(res)
+}
+
 var setStatusOperationImplementors = []string{"SetStatusOperation", "Operation", "Authored"}
 
 // nolint: gocyclo, errcheck, gas, goconst
@@ -2486,6 +4305,164 @@ func (ec *executionContext) _SetTitleOperation_was(ctx context.Context, field gr
 	return graphql.MarshalString(res)
 }
 
+var subscribeOperationImplementors = []string{"SubscribeOperation", "Operation", "Authored"}
+
+// nolint: gocyclo, errcheck, gas, goconst
+func (ec *executionContext) _SubscribeOperation(ctx context.Context, sel []query.Selection, obj *operations.SubscribeOperation) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.Doc, sel, subscribeOperationImplementors, ec.Variables)
+
+	out := graphql.NewOrderedMap(len(fields))
+	for i, field := range fields {
+		out.Keys[i] = field.Alias
+
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("SubscribeOperation")
+		case "author":
+			out.Values[i] = ec._SubscribeOperation_author(ctx, field, obj)
+		case "date":
+			out.Values[i] = ec._SubscribeOperation_date(ctx, field, obj)
+		case "subscriber":
+			out.Values[i] = ec._SubscribeOperation_subscriber(ctx, field, obj)
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+
+	return out
+}
+
+func (ec *executionContext) _SubscribeOperation_author(ctx context.Context, field graphql.CollectedField, obj *operations.SubscribeOperation) graphql.Marshaler {
+	rctx := graphql.GetResolverContext(ctx)
+	rctx.Object = "SubscribeOperation"
+	rctx.Args = nil
+	rctx.Field = field
+	rctx.PushField(field.Alias)
+	defer rctx.Pop()
+	res := obj.Author
+	return ec._Person(ctx, field.Selections, &res)
+}
+
+func (ec *executionContext) _SubscribeOperation_date(ctx context.Context, field graphql.CollectedField, obj *operations.SubscribeOperation) graphql.Marshaler {
+	ctx = graphql.WithResolverContext(ctx, &graphql.ResolverContext{
+		Object: "SubscribeOperation",
+		Args:   nil,
+		Field:  field,
+	})
+	return graphql.Defer(func() (ret graphql.Marshaler) {
+		defer func() {
+			if r := recover(); r != nil {
+				userErr := ec.Recover(ctx, r)
+				ec.Error(ctx, userErr)
+				ret = graphql.Null
+			}
+		}()
+
+		resTmp, err := ec.ResolverMiddleware(ctx, func(ctx context.Context) (interface{}, error) {
+			return ec.resolvers.SubscribeOperation_date(ctx, obj)
+		})
+		if err != nil {
+			ec.Error(ctx, err)
+			return graphql.Null
+		}
+		if resTmp == nil {
+			return graphql.Null
+		}
+		res := resTmp.(time.Time)
+		return graphql.MarshalTime(res)
+	})
+}
+
+func (ec *executionContext) _SubscribeOperation_subscriber(ctx context.Context, field graphql.CollectedField, obj *operations.SubscribeOperation) graphql.Marshaler {
+	rctx := graphql.GetResolverContext(ctx)
+	rctx.Object = "SubscribeOperation"
+	rctx.Args = nil
+	rctx.Field = field
+	rctx.PushField(field.Alias)
+	defer rctx.Pop()
+	res := obj.Subscriber
+	return ec._Person(ctx, field.Selections, &res)
+}
+
+var unsubscribeOperationImplementors = []string{"UnsubscribeOperation", "Operation", "Authored"}
+
+// nolint: gocyclo, errcheck, gas, goconst
+func (ec *executionContext) _UnsubscribeOperation(ctx context.Context, sel []query.Selection, obj *operations.UnsubscribeOperation) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.Doc, sel, unsubscribeOperationImplementors, ec.Variables)
+
+	out := graphql.NewOrderedMap(len(fields))
+	for i, field := range fields {
+		out.Keys[i] = field.Alias
+
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("UnsubscribeOperation")
+		case "author":
+			out.Values[i] = ec._UnsubscribeOperation_author(ctx, field, obj)
+		case "date":
+			out.Values[i] = ec._UnsubscribeOperation_date(ctx, field, obj)
+		case "subscriber":
+			out.Values[i] = ec._UnsubscribeOperation_subscriber(ctx, field, obj)
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+
+	return out
+}
+
+func (ec *executionContext) _UnsubscribeOperation_author(ctx context.Context, field graphql.CollectedField, obj *operations.UnsubscribeOperation) graphql.Marshaler {
+	rctx := graphql.GetResolverContext(ctx)
+	rctx.Object = "UnsubscribeOperation"
+	rctx.Args = nil
+	rctx.Field = field
+	rctx.PushField(field.Alias)
+	defer rctx.Pop()
+	res := obj.Author
+	return ec._Person(ctx, field.Selections, &res)
+}
+
+func (ec *executionContext) _UnsubscribeOperation_date(ctx context.Context, field graphql.CollectedField, obj *operations.UnsubscribeOperation) graphql.Marshaler {
+	ctx = graphql.WithResolverContext(ctx, &graphql.ResolverContext{
+		Object: "UnsubscribeOperation",
+		Args:   nil,
+		Field:  field,
+	})
+	return graphql.Defer(func() (ret graphql.Marshaler) {
+		defer func() {
+			if r := recover(); r != nil {
+				userErr := ec.Recover(ctx, r)
+				ec.Error(ctx, userErr)
+				ret = graphql.Null
+			}
+		}()
+
+		resTmp, err := ec.ResolverMiddleware(ctx, func(ctx context.Context) (interface{}, error) {
+			return ec.resolvers.UnsubscribeOperation_date(ctx, obj)
+		})
+		if err != nil {
+			ec.Error(ctx, err)
+			return graphql.Null
+		}
+		if resTmp == nil {
+			return graphql.Null
+		}
+		res := resTmp.(time.Time)
+		return graphql.MarshalTime(res)
+	})
+}
+
+func (ec *executionContext) _UnsubscribeOperation_subscriber(ctx context.Context, field graphql.CollectedField, obj *operations.UnsubscribeOperation) graphql.Marshaler {
+	rctx := graphql.GetResolverContext(ctx)
+	rctx.Object = "UnsubscribeOperation"
+	rctx.Args = nil
+	rctx.Field = field
+	rctx.PushField(field.Alias)
+	defer rctx.Pop()
+	res := obj.Subscriber
+	return ec._Person(ctx, field.Selections, &res)
+}
+
 var __DirectiveImplementors = []string{"__Directive"}
 
 // nolint: gocyclo, errcheck, gas, goconst
@@ -3204,6 +5181,46 @@ func (ec *executionContext) _Authored(ctx context.Context, sel []query.Selection
 		return ec._LabelChangeOperation(ctx, sel, &obj)
 	case *operations.LabelChangeOperation:
 		return ec._LabelChangeOperation(ctx, sel, obj)
+	case operations.SetRelationOperation:
+		return ec._SetRelationOperation(ctx, sel, &obj)
+	case *operations.SetRelationOperation:
+		return ec._SetRelationOperation(ctx, sel, obj)
+	case operations.DeleteCommentOperation:
+		return ec._DeleteCommentOperation(ctx, sel, &obj)
+	case *operations.DeleteCommentOperation:
+		return ec._DeleteCommentOperation(ctx, sel, obj)
+	case operations.SubscribeOperation:
+		return ec._SubscribeOperation(ctx, sel, &obj)
+	case *operations.SubscribeOperation:
+		return ec._SubscribeOperation(ctx, sel, obj)
+	case operations.UnsubscribeOperation:
+		return ec._UnsubscribeOperation(ctx, sel, &obj)
+	case *operations.UnsubscribeOperation:
+		return ec._UnsubscribeOperation(ctx, sel, obj)
+	case operations.SetCustomFieldOperation:
+		return ec._SetCustomFieldOperation(ctx, sel, &obj)
+	case *operations.SetCustomFieldOperation:
+		return ec._SetCustomFieldOperation(ctx, sel, obj)
+	case operations.AddTimeLogOperation:
+		return ec._AddTimeLogOperation(ctx, sel, &obj)
+	case *operations.AddTimeLogOperation:
+		return ec._AddTimeLogOperation(ctx, sel, obj)
+	case operations.SetPriorityOperation:
+		return ec._SetPriorityOperation(ctx, sel, &obj)
+	case *operations.SetPriorityOperation:
+		return ec._SetPriorityOperation(ctx, sel, obj)
+	case operations.ArchiveCommentsOperation:
+		return ec._ArchiveCommentsOperation(ctx, sel, &obj)
+	case *operations.ArchiveCommentsOperation:
+		return ec._ArchiveCommentsOperation(ctx, sel, obj)
+	case operations.SetChecklistOperation:
+		return ec._SetChecklistOperation(ctx, sel, &obj)
+	case *operations.SetChecklistOperation:
+		return ec._SetChecklistOperation(ctx, sel, obj)
+	case operations.RemapAuthorsOperation:
+		return ec._RemapAuthorsOperation(ctx, sel, &obj)
+	case *operations.RemapAuthorsOperation:
+		return ec._RemapAuthorsOperation(ctx, sel, obj)
 	default:
 		panic(fmt.Errorf("unexpected type %T", obj))
 	}
@@ -3233,6 +5250,46 @@ func (ec *executionContext) _Operation(ctx context.Context, sel []query.Selectio
 		return ec._LabelChangeOperation(ctx, sel, &obj)
 	case *operations.LabelChangeOperation:
 		return ec._LabelChangeOperation(ctx, sel, obj)
+	case operations.SetRelationOperation:
+		return ec._SetRelationOperation(ctx, sel, &obj)
+	case *operations.SetRelationOperation:
+		return ec._SetRelationOperation(ctx, sel, obj)
+	case operations.DeleteCommentOperation:
+		return ec._DeleteCommentOperation(ctx, sel, &obj)
+	case *operations.DeleteCommentOperation:
+		return ec._DeleteCommentOperation(ctx, sel, obj)
+	case operations.SubscribeOperation:
+		return ec._SubscribeOperation(ctx, sel, &obj)
+	case *operations.SubscribeOperation:
+		return ec._SubscribeOperation(ctx, sel, obj)
+	case operations.UnsubscribeOperation:
+		return ec._UnsubscribeOperation(ctx, sel, &obj)
+	case *operations.UnsubscribeOperation:
+		return ec._UnsubscribeOperation(ctx, sel, obj)
+	case operations.SetCustomFieldOperation:
+		return ec._SetCustomFieldOperation(ctx, sel, &obj)
+	case *operations.SetCustomFieldOperation:
+		return ec._SetCustomFieldOperation(ctx, sel, obj)
+	case operations.AddTimeLogOperation:
+		return ec._AddTimeLogOperation(ctx, sel, &obj)
+	case *operations.AddTimeLogOperation:
+		return ec._AddTimeLogOperation(ctx, sel, obj)
+	case operations.SetPriorityOperation:
+		return ec._SetPriorityOperation(ctx, sel, &obj)
+	case *operations.SetPriorityOperation:
+		return ec._SetPriorityOperation(ctx, sel, obj)
+	case operations.ArchiveCommentsOperation:
+		return ec._ArchiveCommentsOperation(ctx, sel, &obj)
+	case *operations.ArchiveCommentsOperation:
+		return ec._ArchiveCommentsOperation(ctx, sel, obj)
+	case operations.SetChecklistOperation:
+		return ec._SetChecklistOperation(ctx, sel, &obj)
+	case *operations.SetChecklistOperation:
+		return ec._SetChecklistOperation(ctx, sel, obj)
+	case operations.RemapAuthorsOperation:
+		return ec._RemapAuthorsOperation(ctx, sel, &obj)
+	case *operations.RemapAuthorsOperation:
+		return ec._RemapAuthorsOperation(ctx, sel, obj)
 	default:
 		panic(fmt.Errorf("unexpected type %T", obj))
 	}
@@ -3295,6 +5352,9 @@ type Comment implements Authored {
   # The message of this comment.
   message: String!
 
+  # The message of this comment rendered from markdown to sanitized HTML.
+  messageHtml: String!
+
   # All media's hash referenced in this comment
   files: [Hash!]!
 }
@@ -3304,6 +5364,14 @@ enum Status {
   CLOSED
 }
 
+# The priority of a bug, from the least to the most urgent.
+enum Priority {
+  LOW
+  NORMAL
+  HIGH
+  CRITICAL
+}
+
 # An object that has an author.
 interface Authored {
   # The author of this object.
@@ -3336,6 +5404,7 @@ type CreateOperation implements Operation, Authored {
 
   title: String!
   message: String!
+  messageHtml: String!
   files: [Hash!]!
 }
 
@@ -3352,6 +5421,7 @@ type AddCommentOperation implements Operation, Authored {
   date: Time!
 
   message: String!
+  messageHtml: String!
   files: [Hash!]!
 }
 
@@ -3370,6 +5440,133 @@ type LabelChangeOperation implements Operation, Authored {
   removed: [Label!]!
 }
 
+enum RelationKind {
+  DUPLICATE
+  BLOCKS
+  RELATED_TO
+}
+
+# Represents a link from a bug to another one.
+type Relation {
+  kind: RelationKind!
+  # The id of the other bug. This bug might not be known locally.
+  target: String!
+}
+
+type SetRelationOperation implements Operation, Authored {
+  author: Person!
+  date: Time!
+
+  kind: RelationKind!
+  target: String!
+}
+
+type DeleteCommentOperation implements Operation, Authored {
+  author: Person!
+  date: Time!
+
+  # The hash of the operation that created the deleted comment.
+  target: Hash!
+  # True if the comment was deleted by someone else than its author.
+  forced: Boolean!
+}
+
+type SubscribeOperation implements Operation, Authored {
+  author: Person!
+  date: Time!
+
+  subscriber: Person!
+}
+
+type UnsubscribeOperation implements Operation, Authored {
+  author: Person!
+  date: Time!
+
+  subscriber: Person!
+}
+
+type SetCustomFieldOperation implements Operation, Authored {
+  author: Person!
+  date: Time!
+
+  key: String!
+  # An empty value remove the field.
+  value: String!
+}
+
+type AddTimeLogOperation implements Operation, Authored {
+  author: Person!
+  date: Time!
+
+  # The time spent, in seconds.
+  duration: Int!
+  note: String!
+}
+
+type SetPriorityOperation implements Operation, Authored {
+  author: Person!
+  date: Time!
+
+  priority: Priority!
+}
+
+type ArchiveCommentsOperation implements Operation, Authored {
+  author: Person!
+  date: Time!
+
+  # The comments created before this time are archived.
+  before: Time!
+  # The blob storing the archived comments.
+  archive: Hash!
+}
+
+type SetChecklistOperation implements Operation, Authored {
+  author: Person!
+  date: Time!
+
+  checklist: String!
+  # Only the items changed.
+  items: [ChecklistItem!]!
+}
+
+type RemapAuthorsOperation implements Operation, Authored {
+  author: Person!
+  date: Time!
+
+  remaps: [AuthorRemap!]!
+}
+
+# A login of another bug tracker, and the person it's mapped to.
+type AuthorRemap {
+  login: String!
+  person: Person!
+}
+
+# The state of an item of a checklist. NA when the item doesn't apply to the
+# bug.
+enum ChecklistState {
+  PENDING
+  DONE
+  NA
+}
+
+type ChecklistItem {
+  name: String!
+  state: ChecklistState!
+}
+
+# A named list of items to go through for a bug, like the steps of a triage.
+type Checklist {
+  name: String!
+  items: [ChecklistItem!]!
+}
+
+# A free form field of a bug, like a severity or a component.
+type CustomField {
+  key: String!
+  value: String!
+}
+
 # The connection type for Bug.
 type BugConnection {
   # A list of edges.
@@ -3393,12 +5590,23 @@ type Bug {
   id: String!
   humanId: String!
   status: Status!
+  # NORMAL unless set.
+  priority: Priority!
   title: String!
   labels: [Label!]!
+  relations: [Relation!]!
+  # The people notified of the changes of the bug.
+  subscribers: [Person!]!
+  customFields: [CustomField!]!
+  checklists: [Checklist!]!
+  # The total time logged on the bug, in seconds.
+  timeSpent: Int!
   author: Person!
   createdAt: Time!
   lastEdit: Time!
 
+  # The number of old comments archived, not part of the comments.
+  archivedCount: Int!
   comments(
     # Returns the elements in the list that come after the specified cursor.
     after: String
@@ -3423,7 +5631,13 @@ type Bug {
 }
 
 type Repository {
+  # The identifier of the repository, to select it with the repository query
+  # and the repoRef of the mutations. Empty when it's the only one.
+  id: String!
   allBugs(
+    # Select and order the bugs with a query, as given to git bug ls, like
+    # "status:open label:UI sort:edit-desc".
+    query: String
     # Returns the elements in the list that come after the specified cursor.
     after: String
     # Returns the elements in the list that come before the specified cursor.
@@ -3434,11 +5648,23 @@ type Repository {
     last: Int
   ): BugConnection!
   bug(prefix: String!): Bug
+  # The labels given a color or a description with "git bug label edit"
+  labels: [LabelInfo!]!
+}
+
+# The metadata of a label, shared with the repository
+type LabelInfo {
+  name: Label!
+  # The color of the label, as #rrggbb
+  color: String
+  description: String
 }
 
 type Query {
   defaultRepository: Repository
   repository(id: String!): Repository
+  # All the repositories served, they are opened only when their bugs are queried
+  repositories: [Repository!]!
 }
 
 type Mutation {
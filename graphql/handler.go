@@ -3,6 +3,7 @@
 package graphql
 
 import (
+	"github.com/MichaelMure/git-bug/cache"
 	"github.com/MichaelMure/git-bug/graphql/graph"
 	"github.com/MichaelMure/git-bug/graphql/resolvers"
 	"github.com/MichaelMure/git-bug/repository"
@@ -17,3 +18,11 @@ func NewHandler(repo repository.Repo) http.Handler {
 
 	return handler.GraphQL(graph.NewExecutableSchema(backend))
 }
+
+// NewCacheHandler serve the repositories registered in the cache, which can
+// be selected by the reference they are registered with
+func NewCacheHandler(c cache.RootCache) http.Handler {
+	backend := resolvers.NewBackendWithCache(c)
+
+	return handler.GraphQL(graph.NewExecutableSchema(backend))
+}
@@ -31,6 +31,15 @@ type CommentEdge struct {
 	Cursor string      `json:"cursor"`
 	Node   bug.Comment `json:"node"`
 }
+type CustomField struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+type LabelInfo struct {
+	Name        bug.Label `json:"name"`
+	Color       *string   `json:"color"`
+	Description *string   `json:"description"`
+}
 type OperationConnection struct {
 	Edges      []OperationEdge `json:"edges"`
 	Nodes      []bug.Operation `json:"nodes"`
@@ -48,6 +57,118 @@ type PageInfo struct {
 	EndCursor       string `json:"endCursor"`
 }
 
+type ChecklistState string
+
+const (
+	ChecklistStatePending ChecklistState = "PENDING"
+	ChecklistStateDone    ChecklistState = "DONE"
+	ChecklistStateNa      ChecklistState = "NA"
+)
+
+func (e ChecklistState) IsValid() bool {
+	switch e {
+	case ChecklistStatePending, ChecklistStateDone, ChecklistStateNa:
+		return true
+	}
+	return false
+}
+
+func (e ChecklistState) String() string {
+	return string(e)
+}
+
+func (e *ChecklistState) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ChecklistState(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ChecklistState", str)
+	}
+	return nil
+}
+
+func (e ChecklistState) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type Priority string
+
+const (
+	PriorityLow      Priority = "LOW"
+	PriorityNormal   Priority = "NORMAL"
+	PriorityHigh     Priority = "HIGH"
+	PriorityCritical Priority = "CRITICAL"
+)
+
+func (e Priority) IsValid() bool {
+	switch e {
+	case PriorityLow, PriorityNormal, PriorityHigh, PriorityCritical:
+		return true
+	}
+	return false
+}
+
+func (e Priority) String() string {
+	return string(e)
+}
+
+func (e *Priority) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = Priority(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid Priority", str)
+	}
+	return nil
+}
+
+func (e Priority) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type RelationKind string
+
+const (
+	RelationKindDuplicate RelationKind = "DUPLICATE"
+	RelationKindBlocks    RelationKind = "BLOCKS"
+	RelationKindRelatedTo RelationKind = "RELATED_TO"
+)
+
+func (e RelationKind) IsValid() bool {
+	switch e {
+	case RelationKindDuplicate, RelationKindBlocks, RelationKindRelatedTo:
+		return true
+	}
+	return false
+}
+
+func (e RelationKind) String() string {
+	return string(e)
+}
+
+func (e *RelationKind) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = RelationKind(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid RelationKind", str)
+	}
+	return nil
+}
+
+func (e RelationKind) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
 type Status string
 
 const (
@@ -13,7 +13,23 @@ type ConnectionInput struct {
 
 type Repository struct {
 	Cache cache.Cacher
-	Repo  cache.RepoCacher
+	// The reference of the repository in the cache
+	Id string
+	// The repository, nil until it's actually needed
+	Repo cache.RepoCacher
+}
+
+// Resolve return the repository, opening it on first access
+func (r *Repository) Resolve() (cache.RepoCacher, error) {
+	if r.Repo == nil {
+		repo, err := r.Cache.ResolveRepo(r.Id)
+		if err != nil {
+			return nil, err
+		}
+		r.Repo = repo
+	}
+
+	return r.Repo, nil
 }
 
 type RepositoryMutation struct {
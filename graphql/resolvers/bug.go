@@ -2,10 +2,13 @@ package resolvers
 
 import (
 	"context"
+	"html"
+	"sort"
 
 	"github.com/MichaelMure/git-bug/bug"
 	"github.com/MichaelMure/git-bug/graphql/connections"
 	"github.com/MichaelMure/git-bug/graphql/models"
+	"github.com/MichaelMure/git-bug/util"
 )
 
 type bugResolver struct{}
@@ -14,6 +17,32 @@ func (bugResolver) Status(ctx context.Context, obj *bug.Snapshot) (models.Status
 	return convertStatus(obj.Status)
 }
 
+func (bugResolver) Priority(ctx context.Context, obj *bug.Snapshot) (models.Priority, error) {
+	return convertPriority(obj.Priority)
+}
+
+func (bugResolver) CustomFields(ctx context.Context, obj *bug.Snapshot) ([]models.CustomField, error) {
+	keys := make([]string, 0, len(obj.CustomFields))
+	for key := range obj.CustomFields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	fields := make([]models.CustomField, len(keys))
+	for i, key := range keys {
+		fields[i] = models.CustomField{
+			Key:   key,
+			Value: obj.CustomFields[key],
+		}
+	}
+
+	return fields, nil
+}
+
+func (bugResolver) TimeSpent(ctx context.Context, obj *bug.Snapshot) (int, error) {
+	return int(obj.TimeSpent().Seconds()), nil
+}
+
 func (bugResolver) Comments(ctx context.Context, obj *bug.Snapshot, after *string, before *string, first *int, last *int) (models.CommentConnection, error) {
 	input := models.ConnectionInput{
 		Before: before,
@@ -67,3 +96,18 @@ func (bugResolver) Operations(ctx context.Context, obj *bug.Snapshot, after *str
 
 	return connections.BugOperationCon(obj.Operations, edger, conMaker, input)
 }
+
+type commentResolver struct{}
+
+func (commentResolver) Message(ctx context.Context, obj *bug.Comment) (string, error) {
+	return obj.GetMessage(), nil
+}
+
+func (commentResolver) MessageHtml(ctx context.Context, obj *bug.Comment) (string, error) {
+	// the placeholder of a deleted comment is not markdown
+	if obj.IsDeleted() {
+		return html.EscapeString(obj.GetMessage()), nil
+	}
+
+	return util.MarkdownToHTML(obj.GetMessage()), nil
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/MichaelMure/git-bug/bug"
 	"github.com/MichaelMure/git-bug/bug/operations"
 	"github.com/MichaelMure/git-bug/graphql/models"
+	"github.com/MichaelMure/git-bug/util"
 )
 
 type addCommentOperationResolver struct{}
@@ -16,12 +17,34 @@ func (addCommentOperationResolver) Date(ctx context.Context, obj *operations.Add
 	return obj.Time(), nil
 }
 
+func (addCommentOperationResolver) Message(ctx context.Context, obj *operations.AddCommentOperation) (string, error) {
+	return obj.GetMessage(), nil
+}
+
+func (addCommentOperationResolver) MessageHtml(ctx context.Context, obj *operations.AddCommentOperation) (string, error) {
+	return util.MarkdownToHTML(obj.GetMessage()), nil
+}
+
 type createOperationResolver struct{}
 
 func (createOperationResolver) Date(ctx context.Context, obj *operations.CreateOperation) (time.Time, error) {
 	return obj.Time(), nil
 }
 
+func (createOperationResolver) Message(ctx context.Context, obj *operations.CreateOperation) (string, error) {
+	return obj.GetMessage(), nil
+}
+
+func (createOperationResolver) MessageHtml(ctx context.Context, obj *operations.CreateOperation) (string, error) {
+	return util.MarkdownToHTML(obj.GetMessage()), nil
+}
+
+type deleteCommentOperationResolver struct{}
+
+func (deleteCommentOperationResolver) Date(ctx context.Context, obj *operations.DeleteCommentOperation) (time.Time, error) {
+	return obj.Time(), nil
+}
+
 type labelChangeOperation struct{}
 
 func (labelChangeOperation) Date(ctx context.Context, obj *operations.LabelChangeOperation) (time.Time, error) {
@@ -44,6 +67,88 @@ func (setTitleOperationResolver) Date(ctx context.Context, obj *operations.SetTi
 	return obj.Time(), nil
 }
 
+type setRelationOperationResolver struct{}
+
+func (setRelationOperationResolver) Date(ctx context.Context, obj *operations.SetRelationOperation) (time.Time, error) {
+	return obj.Time(), nil
+}
+
+func (setRelationOperationResolver) Kind(ctx context.Context, obj *operations.SetRelationOperation) (models.RelationKind, error) {
+	return convertRelationKind(obj.Kind)
+}
+
+type subscribeOperationResolver struct{}
+
+func (subscribeOperationResolver) Date(ctx context.Context, obj *operations.SubscribeOperation) (time.Time, error) {
+	return obj.Time(), nil
+}
+
+type unsubscribeOperationResolver struct{}
+
+func (unsubscribeOperationResolver) Date(ctx context.Context, obj *operations.UnsubscribeOperation) (time.Time, error) {
+	return obj.Time(), nil
+}
+
+type setCustomFieldOperationResolver struct{}
+
+func (setCustomFieldOperationResolver) Date(ctx context.Context, obj *operations.SetCustomFieldOperation) (time.Time, error) {
+	return obj.Time(), nil
+}
+
+type addTimeLogOperationResolver struct{}
+
+func (addTimeLogOperationResolver) Date(ctx context.Context, obj *operations.AddTimeLogOperation) (time.Time, error) {
+	return obj.Time(), nil
+}
+
+func (addTimeLogOperationResolver) Duration(ctx context.Context, obj *operations.AddTimeLogOperation) (int, error) {
+	return int(obj.Duration.Seconds()), nil
+}
+
+type setPriorityOperationResolver struct{}
+
+func (setPriorityOperationResolver) Date(ctx context.Context, obj *operations.SetPriorityOperation) (time.Time, error) {
+	return obj.Time(), nil
+}
+
+func (setPriorityOperationResolver) Priority(ctx context.Context, obj *operations.SetPriorityOperation) (models.Priority, error) {
+	return convertPriority(obj.Priority)
+}
+
+type archiveCommentsOperationResolver struct{}
+
+func (archiveCommentsOperationResolver) Date(ctx context.Context, obj *operations.ArchiveCommentsOperation) (time.Time, error) {
+	return obj.Time(), nil
+}
+
+func (archiveCommentsOperationResolver) Before(ctx context.Context, obj *operations.ArchiveCommentsOperation) (time.Time, error) {
+	return time.Unix(obj.Before, 0), nil
+}
+
+type setChecklistOperationResolver struct{}
+
+func (setChecklistOperationResolver) Date(ctx context.Context, obj *operations.SetChecklistOperation) (time.Time, error) {
+	return obj.Time(), nil
+}
+
+type remapAuthorsOperationResolver struct{}
+
+func (remapAuthorsOperationResolver) Date(ctx context.Context, obj *operations.RemapAuthorsOperation) (time.Time, error) {
+	return obj.Time(), nil
+}
+
+type checklistItemResolver struct{}
+
+func (checklistItemResolver) State(ctx context.Context, obj *bug.ChecklistItem) (models.ChecklistState, error) {
+	return convertChecklistState(obj.State)
+}
+
+type relationResolver struct{}
+
+func (relationResolver) Kind(ctx context.Context, obj *bug.Relation) (models.RelationKind, error) {
+	return convertRelationKind(obj.Kind)
+}
+
 func convertStatus(status bug.Status) (models.Status, error) {
 	switch status {
 	case bug.OpenStatus:
@@ -54,3 +159,44 @@ func convertStatus(status bug.Status) (models.Status, error) {
 
 	return "", fmt.Errorf("Unknown status")
 }
+
+func convertPriority(priority bug.Priority) (models.Priority, error) {
+	switch priority {
+	case bug.LowPriority:
+		return models.PriorityLow, nil
+	case bug.NormalPriority:
+		return models.PriorityNormal, nil
+	case bug.HighPriority:
+		return models.PriorityHigh, nil
+	case bug.CriticalPriority:
+		return models.PriorityCritical, nil
+	}
+
+	return "", fmt.Errorf("Unknown priority")
+}
+
+func convertChecklistState(state bug.ChecklistState) (models.ChecklistState, error) {
+	switch state {
+	case bug.ChecklistPending:
+		return models.ChecklistStatePending, nil
+	case bug.ChecklistDone:
+		return models.ChecklistStateDone, nil
+	case bug.ChecklistNA:
+		return models.ChecklistStateNa, nil
+	}
+
+	return "", fmt.Errorf("Unknown checklist state")
+}
+
+func convertRelationKind(kind bug.RelationKind) (models.RelationKind, error) {
+	switch kind {
+	case bug.DuplicateRelation:
+		return models.RelationKindDuplicate, nil
+	case bug.BlocksRelation:
+		return models.RelationKindBlocks, nil
+	case bug.RelatedToRelation:
+		return models.RelationKindRelatedTo, nil
+	}
+
+	return "", fmt.Errorf("Unknown relation kind")
+}
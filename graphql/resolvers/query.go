@@ -2,6 +2,7 @@ package resolvers
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/MichaelMure/git-bug/cache"
 	"github.com/MichaelMure/git-bug/graphql/models"
@@ -12,16 +13,13 @@ type rootQueryResolver struct {
 }
 
 func (r rootQueryResolver) DefaultRepository(ctx context.Context) (*models.Repository, error) {
-	repo, err := r.cache.DefaultRepo()
+	refs := r.cache.Repositories()
 
-	if err != nil {
-		return nil, err
+	if len(refs) != 1 {
+		return nil, fmt.Errorf("repository is not unique")
 	}
 
-	return &models.Repository{
-		Cache: r.cache,
-		Repo:  repo,
-	}, nil
+	return r.Repository(ctx, refs[0])
 }
 
 func (r rootQueryResolver) Repository(ctx context.Context, id string) (*models.Repository, error) {
@@ -33,6 +31,21 @@ func (r rootQueryResolver) Repository(ctx context.Context, id string) (*models.R
 
 	return &models.Repository{
 		Cache: r.cache,
+		Id:    id,
 		Repo:  repo,
 	}, nil
 }
+
+func (r rootQueryResolver) Repositories(ctx context.Context) ([]models.Repository, error) {
+	refs := r.cache.Repositories()
+
+	repos := make([]models.Repository, len(refs))
+	for i, ref := range refs {
+		repos[i] = models.Repository{
+			Cache: r.cache,
+			Id:    ref,
+		}
+	}
+
+	return repos, nil
+}
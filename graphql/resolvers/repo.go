@@ -4,13 +4,14 @@ import (
 	"context"
 
 	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/cache"
 	"github.com/MichaelMure/git-bug/graphql/connections"
 	"github.com/MichaelMure/git-bug/graphql/models"
 )
 
 type repoResolver struct{}
 
-func (repoResolver) AllBugs(ctx context.Context, obj *models.Repository, after *string, before *string, first *int, last *int) (models.BugConnection, error) {
+func (repoResolver) AllBugs(ctx context.Context, obj *models.Repository, query *string, after *string, before *string, first *int, last *int) (models.BugConnection, error) {
 	input := models.ConnectionInput{
 		Before: before,
 		After:  after,
@@ -18,8 +19,13 @@ func (repoResolver) AllBugs(ctx context.Context, obj *models.Repository, after *
 		Last:   last,
 	}
 
+	repo, err := obj.Resolve()
+	if err != nil {
+		return models.BugConnection{}, err
+	}
+
 	// Simply pass a []string with the ids to the pagination algorithm
-	source, err := obj.Repo.AllBugIds()
+	source, err := queryBugIds(repo, query)
 
 	if err != nil {
 		return models.BugConnection{}, err
@@ -39,7 +45,7 @@ func (repoResolver) AllBugs(ctx context.Context, obj *models.Repository, after *
 		nodes := make([]bug.Snapshot, len(lazyBugEdges))
 
 		for i, lazyBugEdge := range lazyBugEdges {
-			b, err := obj.Repo.ResolveBug(lazyBugEdge.Id)
+			b, err := repo.ResolveBug(lazyBugEdge.Id)
 
 			if err != nil {
 				return models.BugConnection{}, err
@@ -65,8 +71,45 @@ func (repoResolver) AllBugs(ctx context.Context, obj *models.Repository, after *
 	return connections.StringCon(source, edger, conMaker, input)
 }
 
+// queryBugIds return the ids of the bugs selected by the query, in its
+// order, or of every bug without query
+func queryBugIds(repo cache.RepoCacher, query *string) ([]string, error) {
+	if query == nil || *query == "" {
+		return repo.AllBugIds()
+	}
+
+	parsed, err := cache.ParseQuery(*query)
+	if err != nil {
+		return nil, err
+	}
+
+	err = parsed.ResolveMe(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots, err := repo.Search(parsed.Match)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed.Sort(snapshots)
+
+	ids := make([]string, len(snapshots))
+	for i, snap := range snapshots {
+		ids[i] = snap.Id()
+	}
+
+	return ids, nil
+}
+
 func (repoResolver) Bug(ctx context.Context, obj *models.Repository, prefix string) (*bug.Snapshot, error) {
-	b, err := obj.Repo.ResolveBugPrefix(prefix)
+	repo, err := obj.Resolve()
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := repo.ResolveBugPrefix(prefix)
 
 	if err != nil {
 		return nil, err
@@ -74,3 +117,32 @@ func (repoResolver) Bug(ctx context.Context, obj *models.Repository, prefix stri
 
 	return b.Snapshot(), nil
 }
+
+func (repoResolver) Labels(ctx context.Context, obj *models.Repository) ([]models.LabelInfo, error) {
+	repo, err := obj.Resolve()
+	if err != nil {
+		return nil, err
+	}
+
+	registry, err := bug.ReadLabelRegistry(repo.Repository())
+	if err != nil {
+		return nil, err
+	}
+
+	labels := registry.Labels()
+	result := make([]models.LabelInfo, len(labels))
+
+	for i, label := range labels {
+		info, _ := registry.Lookup(label)
+
+		result[i] = models.LabelInfo{Name: label}
+		if info.Color != "" {
+			result[i].Color = &info.Color
+		}
+		if info.Description != "" {
+			result[i].Description = &info.Description
+		}
+	}
+
+	return result, nil
+}
@@ -10,8 +10,14 @@ type Backend struct {
 }
 
 func NewBackend() *Backend {
+	return NewBackendWithCache(cache.NewCache())
+}
+
+// NewBackendWithCache create a backend serving the repositories registered
+// in the given cache
+func NewBackendWithCache(c cache.RootCache) *Backend {
 	return &Backend{
-		RootCache: cache.NewCache(),
+		RootCache: c,
 	}
 }
 
@@ -35,10 +41,18 @@ func (r Backend) Bug() graph.BugResolver {
 	return &bugResolver{}
 }
 
+func (Backend) Comment() graph.CommentResolver {
+	return &commentResolver{}
+}
+
 func (Backend) CreateOperation() graph.CreateOperationResolver {
 	return &createOperationResolver{}
 }
 
+func (Backend) DeleteCommentOperation() graph.DeleteCommentOperationResolver {
+	return &deleteCommentOperationResolver{}
+}
+
 func (Backend) LabelChangeOperation() graph.LabelChangeOperationResolver {
 	return &labelChangeOperation{}
 }
@@ -47,6 +61,14 @@ func (r Backend) Repository() graph.RepositoryResolver {
 	return &repoResolver{}
 }
 
+func (Backend) Relation() graph.RelationResolver {
+	return &relationResolver{}
+}
+
+func (Backend) SetRelationOperation() graph.SetRelationOperationResolver {
+	return &setRelationOperationResolver{}
+}
+
 func (Backend) SetStatusOperation() graph.SetStatusOperationResolver {
 	return &setStatusOperationResolver{}
 }
@@ -54,3 +76,39 @@ func (Backend) SetStatusOperation() graph.SetStatusOperationResolver {
 func (Backend) SetTitleOperation() graph.SetTitleOperationResolver {
 	return &setTitleOperationResolver{}
 }
+
+func (Backend) SubscribeOperation() graph.SubscribeOperationResolver {
+	return &subscribeOperationResolver{}
+}
+
+func (Backend) UnsubscribeOperation() graph.UnsubscribeOperationResolver {
+	return &unsubscribeOperationResolver{}
+}
+
+func (Backend) SetCustomFieldOperation() graph.SetCustomFieldOperationResolver {
+	return &setCustomFieldOperationResolver{}
+}
+
+func (Backend) AddTimeLogOperation() graph.AddTimeLogOperationResolver {
+	return &addTimeLogOperationResolver{}
+}
+
+func (Backend) SetPriorityOperation() graph.SetPriorityOperationResolver {
+	return &setPriorityOperationResolver{}
+}
+
+func (Backend) ArchiveCommentsOperation() graph.ArchiveCommentsOperationResolver {
+	return &archiveCommentsOperationResolver{}
+}
+
+func (Backend) SetChecklistOperation() graph.SetChecklistOperationResolver {
+	return &setChecklistOperationResolver{}
+}
+
+func (Backend) RemapAuthorsOperation() graph.RemapAuthorsOperationResolver {
+	return &remapAuthorsOperationResolver{}
+}
+
+func (Backend) ChecklistItem() graph.ChecklistItemResolver {
+	return &checklistItemResolver{}
+}
@@ -0,0 +1,196 @@
+package input
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+// The directory of the comment drafts, shared by the worktrees. They are
+// plain files out of the refs, so they are never committed nor pushed.
+const draftsDir = "git-bug/drafts"
+
+// CommentDraft is a comment being written in the editor. It's kept until the
+// comment is committed, so that it can be resumed if it's interrupted.
+type CommentDraft struct {
+	// the name of the file: the id of the bug, suffixed with a number like
+	// "<id>.2" when the bug has several drafts
+	Name  string
+	BugId string
+	Path  string
+	// the last time the draft was saved
+	ModTime time.Time
+}
+
+func draftsPath(repo repository.Repo) string {
+	return filepath.Join(repo.GetCommonDir(), draftsDir)
+}
+
+func newCommentDraft(path string, info os.FileInfo) CommentDraft {
+	name := info.Name()
+	return CommentDraft{
+		Name:    name,
+		BugId:   strings.SplitN(name, ".", 2)[0],
+		Path:    path,
+		ModTime: info.ModTime(),
+	}
+}
+
+// ListCommentDrafts return the comment drafts of every bug, the most recent
+// first
+func ListCommentDrafts(repo repository.Repo) ([]CommentDraft, error) {
+	dir := draftsPath(repo)
+
+	infos, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var drafts []CommentDraft
+	for _, info := range infos {
+		if info.IsDir() {
+			continue
+		}
+		drafts = append(drafts, newCommentDraft(filepath.Join(dir, info.Name()), info))
+	}
+
+	sort.Slice(drafts, func(i, j int) bool {
+		return drafts[i].ModTime.After(drafts[j].ModTime)
+	})
+
+	return drafts, nil
+}
+
+// BugCommentDrafts return the comment drafts of a bug, the most recent first
+func BugCommentDrafts(repo repository.Repo, bugId string) ([]CommentDraft, error) {
+	drafts, err := ListCommentDrafts(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []CommentDraft
+	for _, draft := range drafts {
+		if draft.BugId == bugId {
+			result = append(result, draft)
+		}
+	}
+
+	return result, nil
+}
+
+// createCommentDraft create a new draft for a bug, holding the given content.
+// The name is suffixed if the bug already has a draft, like one being
+// written from another terminal, rather than replacing it.
+func createCommentDraft(repo repository.Repo, bugId string, content string) (CommentDraft, error) {
+	dir := draftsPath(repo)
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return CommentDraft{}, err
+	}
+
+	for i := 1; ; i++ {
+		name := bugId
+		if i > 1 {
+			name = fmt.Sprintf("%s.%d", bugId, i)
+		}
+
+		path := filepath.Join(dir, name)
+
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if os.IsExist(err) {
+			continue
+		}
+		if err != nil {
+			return CommentDraft{}, err
+		}
+
+		_, err = file.WriteString(content)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(path)
+			return CommentDraft{}, err
+		}
+
+		return CommentDraft{Name: name, BugId: bugId, Path: path, ModTime: time.Now()}, nil
+	}
+}
+
+// Message return the text of the draft, without the lines of the template
+func (d CommentDraft) Message() (string, error) {
+	raw, err := ioutil.ReadFile(d.Path)
+	if err != nil {
+		return "", err
+	}
+
+	return parseComment(string(raw)), nil
+}
+
+// Remove delete the draft, once the comment is committed or abandoned
+func (d CommentDraft) Remove() error {
+	err := os.Remove(d.Path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// ResumeCommentDraft offer to resume the drafts of a bug on the terminal, one
+// after the other, the most recent first. It return the one to resume, or nil
+// to start a new comment.
+func ResumeCommentDraft(repo repository.Repo, bugId string) (*CommentDraft, error) {
+	if !IsInteractive() {
+		return nil, nil
+	}
+
+	drafts, err := BugCommentDrafts(repo, bugId)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, draft := range drafts {
+		message, err := draft.Message()
+		if err != nil {
+			return nil, err
+		}
+
+		// nothing written yet, possibly still open in another terminal
+		if message == "" {
+			continue
+		}
+
+		question := fmt.Sprintf("A draft of a comment on this bug was saved on %s:\n  %s\nResume it?",
+			draft.ModTime.Format("Jan 2 15:04"), firstLine(message))
+
+		resume, err := Confirm(question)
+		if err != nil {
+			return nil, err
+		}
+		if resume {
+			draft := draft
+			return &draft, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// firstLine return the first line of a text, marked as truncated if there is
+// more
+func firstLine(text string) string {
+	split := strings.SplitN(text, "\n", 2)
+	if len(split) > 1 {
+		return split[0] + " [...]"
+	}
+	return split[0]
+}
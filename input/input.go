@@ -6,12 +6,15 @@ import (
 	"bufio"
 	"bytes"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 
 	"github.com/MichaelMure/git-bug/repository"
+	"github.com/mattn/go-isatty"
 	"github.com/pkg/errors"
 )
 
@@ -91,6 +94,53 @@ func BugCommentEditorInput(repo repository.Repo) (string, error) {
 		return "", err
 	}
 
+	message := parseComment(raw)
+
+	if message == "" {
+		return "", ErrEmptyMessage
+	}
+
+	return message, nil
+}
+
+// BugCommentDraftInput is BugCommentEditorInput, editing a draft of a comment
+// on the bug: the given one to resume it, or else a new one. The draft is
+// kept once written, and must be removed with CommentDraft.Remove when the
+// comment is committed. An empty message remove it, and abort like with
+// BugCommentEditorInput.
+func BugCommentDraftInput(repo repository.Repo, bugId string, resume *CommentDraft) (string, CommentDraft, error) {
+	var draft CommentDraft
+	var err error
+
+	if resume != nil {
+		draft = *resume
+	} else {
+		draft, err = createCommentDraft(repo, bugId, bugCommentTemplate)
+		if err != nil {
+			return "", CommentDraft{}, err
+		}
+	}
+
+	raw, err := editFile(repo, draft.Path)
+	if err != nil {
+		return "", draft, err
+	}
+
+	message := parseComment(raw)
+
+	if message == "" {
+		if err := draft.Remove(); err != nil {
+			return "", draft, err
+		}
+		return "", draft, ErrEmptyMessage
+	}
+
+	return message, draft, nil
+}
+
+// parseComment extract the comment written in the editor, without the lines
+// of the template
+func parseComment(raw string) string {
 	lines := strings.Split(raw, "\n")
 
 	var buffer bytes.Buffer
@@ -102,13 +152,7 @@ func BugCommentEditorInput(repo repository.Repo) (string, error) {
 		buffer.WriteString("\n")
 	}
 
-	message := strings.TrimSpace(buffer.String())
-
-	if message == "" {
-		return "", ErrEmptyMessage
-	}
-
-	return message, nil
+	return strings.TrimSpace(buffer.String())
 }
 
 const bugTitleTemplate = `%s
@@ -152,7 +196,7 @@ func BugTitleEditorInput(repo repository.Repo, preTitle string) (string, error)
 // launchEditorWithTemplate will launch an editor as launchEditor do, but with a
 // provided template.
 func launchEditorWithTemplate(repo repository.Repo, fileName string, template string) (string, error) {
-	path := fmt.Sprintf("%s/.git/%s", repo.GetPath(), fileName)
+	path := filepath.Join(repo.GetGitDir(), fileName)
 
 	err := ioutil.WriteFile(path, []byte(template), 0644)
 
@@ -167,15 +211,23 @@ func launchEditorWithTemplate(repo repository.Repo, fileName string, template st
 // method blocks until the editor command has returned.
 //
 // The specified filename should be a temporary file and provided as a relative path
-// from the repo (e.g. "FILENAME" will be converted to ".git/FILENAME"). This file
+// from the git directory of the working tree (e.g. "FILENAME" will be converted
+// to ".git/FILENAME", or to ".git/worktrees/<name>/FILENAME" in a linked
+// worktree). This file
 // will be deleted after the editor is closed and its contents have been read.
 //
 // This method returns the text that was read from the temporary file, or
 // an error if any step in the process failed.
 func launchEditor(repo repository.Repo, fileName string) (string, error) {
-	path := fmt.Sprintf("%s/.git/%s", repo.GetPath(), fileName)
+	path := filepath.Join(repo.GetGitDir(), fileName)
 	defer os.Remove(path)
 
+	return editFile(repo, path)
+}
+
+// editFile launches the default editor on a file, like launchEditor, but
+// keep it once read
+func editFile(repo repository.Repo, path string) (string, error) {
 	editor, err := repo.GetCoreEditor()
 	if err != nil {
 		return "", fmt.Errorf("Unable to detect default git editor: %v\n", err)
@@ -254,3 +306,73 @@ func startInlineCommand(command string, args ...string) (*exec.Cmd, error) {
 	err := cmd.Start()
 	return cmd, err
 }
+
+// Confirm ask a yes/no question on the terminal and return the answer.
+// Anything else than "y" or "yes" is a no.
+func Confirm(question string) (bool, error) {
+	fmt.Printf("%s [y/N] ", question)
+
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, err
+	}
+
+	answer = strings.ToLower(strings.TrimSpace(answer))
+
+	return answer == "y" || answer == "yes", nil
+}
+
+// IsInteractive tell if a user can answer the questions on the terminal
+func IsInteractive() bool {
+	return isatty.IsTerminal(os.Stdin.Fd()) && isatty.IsTerminal(os.Stdout.Fd())
+}
+
+// Choose ask a question on the terminal with several answers, and return the
+// index of the chosen one. An answer can be given by its first letter. The
+// question is asked again until a valid answer is given.
+func Choose(question string, answers []string) (int, error) {
+	reader := bufio.NewReader(os.Stdin)
+
+	for {
+		fmt.Printf("%s [%s] ", question, strings.Join(answers, "/"))
+
+		answer, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return 0, err
+		}
+
+		answer = strings.ToLower(strings.TrimSpace(answer))
+
+		for i, a := range answers {
+			if answer != "" && (answer == strings.ToLower(a) || answer == strings.ToLower(a[:1])) {
+				return i, nil
+			}
+		}
+
+		if err == io.EOF {
+			return 0, errors.New("no answer given")
+		}
+	}
+}
+
+// Prompt ask for a line of text on the terminal. The default value, if any,
+// is shown and returned for an empty answer.
+func Prompt(question string, defaultValue string) (string, error) {
+	if defaultValue != "" {
+		fmt.Printf("%s [%s] ", question, defaultValue)
+	} else {
+		fmt.Printf("%s ", question)
+	}
+
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+
+	answer = strings.TrimSpace(answer)
+	if answer == "" {
+		return defaultValue, nil
+	}
+
+	return answer, nil
+}
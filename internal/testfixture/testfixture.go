@@ -0,0 +1,220 @@
+// Package testfixture build bugs from a short description of their
+// operations, to write the tests without repeating the same boilerplate.
+//
+//	b, err := testfixture.BuildBug(repo, testfixture.New(rene).
+//		Create("title", "message").
+//		Comment("first comment").
+//		Commit().
+//		Label("bug", "ui").
+//		Close())
+package testfixture
+
+import (
+	"errors"
+	"io/ioutil"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/bug/operations"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+// Spec describe a sequence of operations to apply on a bug. Each method
+// add an operation and return the Spec to chain the calls.
+type Spec struct {
+	author  bug.Person
+	create  *createStep
+	steps   []step
+	invalid error
+}
+
+type createStep struct {
+	author  bug.Person
+	title   string
+	message string
+}
+
+type step struct {
+	// apply add an operation in the staging area of the bug, or is nil
+	// for a commit
+	apply func(b *bug.Bug) error
+}
+
+// New start a Spec whose operations are authored by the given person
+func New(author bug.Person) *Spec {
+	return &Spec{author: author}
+}
+
+// As change the author of the following operations
+func (s *Spec) As(author bug.Person) *Spec {
+	s.author = author
+	return s
+}
+
+// Create start the bug. It has to be the first operation, and only
+// BuildBug accept a Spec with a Create.
+func (s *Spec) Create(title string, message string) *Spec {
+	if s.create != nil || len(s.steps) > 0 {
+		s.invalid = errors.New("Create has to be the first operation")
+		return s
+	}
+
+	s.create = &createStep{
+		author:  s.author,
+		title:   title,
+		message: message,
+	}
+	return s
+}
+
+func (s *Spec) Comment(message string) *Spec {
+	author := s.author
+	return s.add(func(b *bug.Bug) error {
+		return operations.Comment(b, author, message)
+	})
+}
+
+func (s *Spec) SetTitle(title string) *Spec {
+	author := s.author
+	return s.add(func(b *bug.Bug) error {
+		return operations.SetTitle(b, author, title)
+	})
+}
+
+func (s *Spec) Open() *Spec {
+	author := s.author
+	return s.add(func(b *bug.Bug) error {
+		operations.Open(b, author)
+		return nil
+	})
+}
+
+func (s *Spec) Close() *Spec {
+	author := s.author
+	return s.add(func(b *bug.Bug) error {
+		operations.Close(b, author)
+		return nil
+	})
+}
+
+// Label add the given labels
+func (s *Spec) Label(labels ...string) *Spec {
+	author := s.author
+	return s.add(func(b *bug.Bug) error {
+		return operations.ChangeLabels(ioutil.Discard, b, author, labels, nil)
+	})
+}
+
+// Unlabel remove the given labels
+func (s *Spec) Unlabel(labels ...string) *Spec {
+	author := s.author
+	return s.add(func(b *bug.Bug) error {
+		return operations.ChangeLabels(ioutil.Discard, b, author, nil, labels)
+	})
+}
+
+// Commit store the previous operations in their own git commit. The
+// remaining operations are committed at the end anyway.
+func (s *Spec) Commit() *Spec {
+	s.steps = append(s.steps, step{})
+	return s
+}
+
+func (s *Spec) add(apply func(b *bug.Bug) error) *Spec {
+	s.steps = append(s.steps, step{apply: apply})
+	return s
+}
+
+// BuildBug create a bug in the repository following the Spec, which must
+// start with Create
+func BuildBug(repo repository.Repo, spec *Spec) (*bug.Bug, error) {
+	if spec.invalid != nil {
+		return nil, spec.invalid
+	}
+
+	if spec.create == nil {
+		return nil, errors.New("the spec of a new bug has to start with Create")
+	}
+
+	b, err := operations.Create(spec.create.author, spec.create.title, spec.create.message)
+	if err != nil {
+		return nil, err
+	}
+
+	err = apply(repo, b, spec.steps)
+	if err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// Edit apply the operations of the Spec, which must not have a Create, on
+// an existing bug and commit them
+func Edit(repo repository.Repo, b *bug.Bug, spec *Spec) error {
+	if spec.invalid != nil {
+		return spec.invalid
+	}
+
+	if spec.create != nil {
+		return errors.New("an existing bug can't be created again")
+	}
+
+	return apply(repo, b, spec.steps)
+}
+
+func apply(repo repository.Repo, b *bug.Bug, steps []step) error {
+	for _, step := range steps {
+		if step.apply != nil {
+			if err := step.apply(b); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if b.HasPendingOp() {
+			if err := b.Commit(repo); err != nil {
+				return err
+			}
+		}
+	}
+
+	if b.HasPendingOp() {
+		return b.Commit(repo)
+	}
+
+	return nil
+}
+
+// Fork copy a bug from a repository to another one through a common
+// remote, and return the copy
+func Fork(from repository.Repo, to repository.Repo, remote string, id string) (*bug.Bug, error) {
+	_, err := bug.Push(from, remote)
+	if err != nil {
+		return nil, err
+	}
+
+	return Pull(to, remote, id)
+}
+
+// Pull copy a bug from a remote, like the repository the bug has been built
+// in, and return the copy
+func Pull(to repository.Repo, remote string, id string) (*bug.Bug, error) {
+	err := bug.Pull(to, ioutil.Discard, remote)
+	if err != nil {
+		return nil, err
+	}
+
+	return bug.ReadLocalBug(to, id)
+}
+
+// Diverge edit independently the two copies of a bug, as two users would
+// do before a merge
+func Diverge(repoA repository.Repo, bugA *bug.Bug, specA *Spec,
+	repoB repository.Repo, bugB *bug.Bug, specB *Spec) error {
+
+	if err := Edit(repoA, bugA, specA); err != nil {
+		return err
+	}
+
+	return Edit(repoB, bugB, specB)
+}
@@ -0,0 +1,152 @@
+package testfixture
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/bug/operations"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+var rene = bug.Person{
+	Name:  "René Descartes",
+	Email: "rene@descartes.fr",
+}
+
+var isaac = bug.Person{
+	Name:  "Isaac Newton",
+	Email: "isaac@newton.uk",
+}
+
+func TestBuildBug(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+
+	b, err := BuildBug(repo, New(rene).
+		Create("title", "message").
+		Comment("comment").
+		Commit().
+		As(isaac).
+		Label("bug", "ui").
+		Unlabel("ui").
+		Close())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if b.HasPendingOp() {
+		t.Fatal("All the operations should be committed")
+	}
+
+	commits, err := repo.ListCommits("refs/" + bug.Namespace() + "/" + b.Id())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commits) != 2 {
+		t.Fatalf("Expected 2 commits, got %d", len(commits))
+	}
+
+	snap := b.Compile()
+
+	if snap.Title != "title" || snap.Status != bug.ClosedStatus {
+		t.Fatal("Unexpected title or status")
+	}
+	if len(snap.Comments) != 2 || snap.Comments[1].Message != "comment" {
+		t.Fatal("Unexpected comments")
+	}
+	if len(snap.Labels) != 1 || snap.Labels[0] != "bug" {
+		t.Fatal("Unexpected labels")
+	}
+	closeOp, ok := snap.Operations[len(snap.Operations)-1].(operations.SetStatusOperation)
+	if !ok || closeOp.Author != isaac {
+		t.Fatal("The last operation should be authored by isaac")
+	}
+}
+
+func TestBuildBugInvalid(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+
+	if _, err := BuildBug(repo, New(rene).Comment("comment")); err == nil {
+		t.Fatal("A spec without Create should be rejected")
+	}
+
+	if _, err := BuildBug(repo, New(rene).Comment("comment").Create("title", "message")); err == nil {
+		t.Fatal("A Create after another operation should be rejected")
+	}
+
+	b, err := BuildBug(repo, New(rene).Create("title", "message"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Edit(repo, b, New(rene).Create("title", "message")); err == nil {
+		t.Fatal("An existing bug can't be created again")
+	}
+}
+
+func TestForkDiverge(t *testing.T) {
+	repoA, repoB, remote := setupRepos(t)
+	defer os.RemoveAll(repoA.GetPath())
+	defer os.RemoveAll(repoB.GetPath())
+	defer os.RemoveAll(remote.GetPath())
+
+	bugA, err := BuildBug(repoA, New(rene).Create("title", "message"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bugB, err := Fork(repoA, repoB, "origin", bugA.Id())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = Diverge(
+		repoA, bugA, New(rene).Comment("from A"),
+		repoB, bugB, New(isaac).Comment("from B").Commit().Comment("again from B"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A --> remote --> B, then B --> remote --> A
+	if _, err := Fork(repoA, repoB, "origin", bugA.Id()); err != nil {
+		t.Fatal(err)
+	}
+	merged, err := Fork(repoB, repoA, "origin", bugA.Id())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(merged.Compile().Comments) != 4 {
+		t.Fatal("The merged bug should have the comments of both sides")
+	}
+}
+
+func setupRepos(t *testing.T) (repoA, repoB, remote *repository.GitRepo) {
+	create := func(init func(string) (*repository.GitRepo, error)) *repository.GitRepo {
+		dir, err := ioutil.TempDir("", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		repo, err := init(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return repo
+	}
+
+	repoA = create(repository.InitGitRepo)
+	repoB = create(repository.InitGitRepo)
+	remote = create(repository.InitBareGitRepo)
+
+	for _, repo := range []*repository.GitRepo{repoA, repoB} {
+		if err := repo.AddRemote("origin", "file://"+remote.GetPath()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return repoA, repoB, remote
+}
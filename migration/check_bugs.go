@@ -0,0 +1,32 @@
+package migration
+
+import (
+	"fmt"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+// checkBugs ensure that every local bug can be read and is valid before
+// the repository is marked as versioned
+func checkBugs(repo repository.Repo) error {
+	var err error
+
+	// keep draining the channel to not leak the reading goroutine
+	for streamed := range bug.ReadAllLocalBugs(repo) {
+		if err != nil {
+			continue
+		}
+
+		if streamed.Err != nil {
+			err = streamed.Err
+			continue
+		}
+
+		if !streamed.Bug.IsValid() {
+			err = fmt.Errorf("bug %s is invalid", streamed.Bug.HumanId())
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,48 @@
+package migration
+
+import (
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+// the ref of the identity mapping before it was stored per namespace
+const legacyIdentityMappingRef = "refs/git-bug/identity-mapping"
+
+// moveIdentityMapping move the identity mapping stored by older versions in
+// the namespace of the repository, unless it already has one
+func moveIdentityMapping(repo repository.Repo) error {
+	exist, err := repo.RefExist(legacyIdentityMappingRef)
+	if err != nil || !exist {
+		return err
+	}
+
+	hash, err := repo.ResolveRef(legacyIdentityMappingRef)
+	if err != nil {
+		return err
+	}
+
+	ref := bug.IdentityMappingRef(repo)
+
+	exist, err = repo.RefExist(ref)
+	if err != nil {
+		return err
+	}
+
+	tx := repo.Begin()
+
+	if !exist {
+		err = tx.UpdateRefFrom(ref, hash, repository.NoRef)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	err = tx.DeleteRef(legacyIdentityMappingRef)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,158 @@
+// Package migration upgrade the data stored in a repository by older
+// versions of git-bug to the format expected by this version.
+package migration
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+// VersionConfigKey is the git config key holding the format version of
+// the repository
+const VersionConfigKey = repository.PreferencePrefix + versionPreference
+
+const versionPreference = "version"
+
+// Migration upgrade a repository from the previous format version to
+// Version. Running a migration on an already migrated repository must have
+// no effect.
+type Migration struct {
+	Version     uint
+	Description string
+	Run         func(repo repository.Repo) error
+}
+
+// the known migrations, in order. Repositories without a format version
+// are at version 0.
+var migrations = []Migration{
+	{
+		Version:     1,
+		Description: "check that every bug can be read",
+		Run:         checkBugs,
+	},
+	{
+		Version:     2,
+		Description: "move the identity mapping in the namespace of the bugs",
+		Run:         moveIdentityMapping,
+	},
+}
+
+// CurrentVersion return the format version expected by this version of git-bug
+func CurrentVersion() uint {
+	return currentVersion(migrations)
+}
+
+func currentVersion(migrations []Migration) uint {
+	return migrations[len(migrations)-1].Version
+}
+
+// ReadVersion return the format version of the repository
+func ReadVersion(repo repository.Repo) (uint, error) {
+	raw, err := repository.GetUserPreference(repo, versionPreference)
+	if err == repository.ErrNoConfigEntry {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	version, err := strconv.ParseUint(raw, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid repository format version \"%s\"", raw)
+	}
+
+	return uint(version), nil
+}
+
+func writeVersion(repo repository.Repo, version uint) error {
+	return repository.SetUserPreference(repo, versionPreference, strconv.FormatUint(uint64(version), 10))
+}
+
+// Pending return the migrations needed to bring the repository to the
+// current format version. A repository without any bug need no migration.
+func Pending(repo repository.Repo) ([]Migration, error) {
+	return pending(repo, migrations)
+}
+
+func pending(repo repository.Repo, migrations []Migration) ([]Migration, error) {
+	version, err := ReadVersion(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	current := currentVersion(migrations)
+
+	if version > current {
+		return nil, fmt.Errorf("the repository format version %d is newer than the supported version %d, please upgrade git-bug", version, current)
+	}
+
+	if version == 0 {
+		ids, err := bug.ListLocalIds(repo)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(ids) == 0 {
+			return nil, nil
+		}
+	}
+
+	var result []Migration
+	for _, m := range migrations {
+		if m.Version > version {
+			result = append(result, m)
+		}
+	}
+
+	return result, nil
+}
+
+// Run apply the pending migrations in order, reporting the progress in out,
+// and record the current format version in the repository
+func Run(repo repository.Repo, out io.Writer) error {
+	return run(repo, out, migrations)
+}
+
+func run(repo repository.Repo, out io.Writer, migrations []Migration) error {
+	version, err := ReadVersion(repo)
+	if err != nil {
+		return err
+	}
+
+	todo, err := pending(repo, migrations)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range todo {
+		fmt.Fprintf(out, "Migration %d: %s ... ", m.Version, m.Description)
+
+		err := m.Run(repo)
+		if err != nil {
+			fmt.Fprintf(out, "failed\n")
+			return fmt.Errorf("migration %d failed: %v", m.Version, err)
+		}
+
+		// record the progress so that an interrupted run resume from here
+		err = writeVersion(repo, m.Version)
+		if err != nil {
+			return err
+		}
+		version = m.Version
+
+		fmt.Fprintf(out, "done\n")
+	}
+
+	// only write the config when the version change, as this run before
+	// every command writing in the repository
+	current := currentVersion(migrations)
+	if version != current {
+		return writeVersion(repo, current)
+	}
+
+	return nil
+}
@@ -0,0 +1,255 @@
+package migration
+
+import (
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/bug/operations"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+var rene = bug.Person{
+	Name:  "René Descartes",
+	Email: "rene@descartes.fr",
+}
+
+// fixtureRepo create a repository the way git-bug did before the format was
+// versioned
+func fixtureRepo(t *testing.T) repository.Repo {
+	repo := repository.NewMockRepoForTest()
+
+	for _, title := range []string{"bug1", "bug2"} {
+		b, err := operations.Create(rene, title, "message")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = b.Commit(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return repo
+}
+
+func TestEmptyRepo(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+
+	todo, err := Pending(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(todo) != 0 {
+		t.Fatal("A repository without bug should not need a migration")
+	}
+
+	err = Run(repo, ioutil.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	version, err := ReadVersion(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if version != CurrentVersion() {
+		t.Fatal("The current version should be recorded")
+	}
+}
+
+func TestRunOrderAndIdempotence(t *testing.T) {
+	repo := fixtureRepo(t)
+
+	var ran []uint
+	record := func(version uint) func(repository.Repo) error {
+		return func(repository.Repo) error {
+			ran = append(ran, version)
+			return nil
+		}
+	}
+
+	list := []Migration{
+		{Version: 1, Run: record(1)},
+		{Version: 2, Run: record(2)},
+		{Version: 3, Run: record(3)},
+	}
+
+	err := writeVersion(repo, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = run(repo, ioutil.Discard, list)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(ran) != 2 || ran[0] != 2 || ran[1] != 3 {
+		t.Fatalf("Unexpected migrations run: %v", ran)
+	}
+
+	err = run(repo, ioutil.Discard, list)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(ran) != 2 {
+		t.Fatal("A migrated repository should not be migrated again")
+	}
+}
+
+// countingRepo count the writes in the git config
+type countingRepo struct {
+	repository.Repo
+	writes int
+}
+
+func (r *countingRepo) SetConfig(key string, value string) error {
+	r.writes++
+	return r.Repo.SetConfig(key, value)
+}
+
+func TestRunWriteOnlyOnChange(t *testing.T) {
+	for name, repo := range map[string]*countingRepo{
+		"empty":    {Repo: repository.NewMockRepoForTest()},
+		"with bug": {Repo: fixtureRepo(t)},
+	} {
+		err := Run(repo, ioutil.Discard)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if repo.writes == 0 {
+			t.Fatalf("%s: the version should be recorded", name)
+		}
+
+		repo.writes = 0
+
+		err = Run(repo, ioutil.Discard)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if repo.writes != 0 {
+			t.Fatalf("%s: a migrated repository should not write its config", name)
+		}
+	}
+}
+
+func TestRunFailure(t *testing.T) {
+	repo := fixtureRepo(t)
+
+	list := []Migration{
+		{Version: 1, Run: func(repository.Repo) error { return nil }},
+		{Version: 2, Run: func(repository.Repo) error { return errors.New("failure") }},
+	}
+
+	err := run(repo, ioutil.Discard, list)
+	if err == nil {
+		t.Fatal("The failure should be reported")
+	}
+
+	version, err := ReadVersion(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if version != 1 {
+		t.Fatal("The version should record the last successful migration")
+	}
+}
+
+func TestNewerVersion(t *testing.T) {
+	repo := fixtureRepo(t)
+
+	err := writeVersion(repo, CurrentVersion()+1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = Pending(repo)
+	if err == nil {
+		t.Fatal("A newer format should be rejected")
+	}
+}
+
+func TestCheckBugs(t *testing.T) {
+	repo := fixtureRepo(t)
+
+	todo, err := Pending(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(todo) != len(migrations) {
+		t.Fatal("An unversioned repository should need every migration")
+	}
+
+	// run twice to check the idempotence
+	for i := 0; i < 2; i++ {
+		err = checkBugs(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err = Run(repo, ioutil.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	todo, err = Pending(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(todo) != 0 {
+		t.Fatal("The repository should be migrated")
+	}
+}
+
+func TestMoveIdentityMapping(t *testing.T) {
+	repo := fixtureRepo(t)
+
+	tree, err := repo.StoreTree(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commit, err := repo.StoreCommit(tree)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = repo.UpdateRef(legacyIdentityMappingRef, commit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// run twice to check the idempotence
+	for i := 0; i < 2; i++ {
+		err = moveIdentityMapping(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	exist, err := repo.RefExist(legacyIdentityMappingRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exist {
+		t.Fatal("The legacy ref should be removed")
+	}
+
+	hash, err := repo.ResolveRef(bug.IdentityMappingRef(repo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash != commit {
+		t.Fatal("The mapping should be moved in the namespace")
+	}
+}
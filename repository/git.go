@@ -2,27 +2,36 @@
 package repository
 
 import (
+	"bufio"
 	"bytes"
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"os/exec"
-	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/MichaelMure/git-bug/util"
 )
 
-const createClockFile = "/.git/git-bug/create-clock"
-const editClockFile = "/.git/git-bug/edit-clock"
+// the clocks are shared by the worktrees, in the common git directory
+const createClockFile = "git-bug/create-clock"
+const editClockFile = "git-bug/edit-clock"
 
 // ErrNotARepo is the error returned when the git repo root wan't be found
 var ErrNotARepo = errors.New("not a git repository")
 
 // GitRepo represents an instance of a (local) git repository.
 type GitRepo struct {
-	Path        string
+	Path string
+	// the git directory private to the working tree, and the one shared with
+	// the other worktrees. They only differ in a linked worktree.
+	gitDir      string
+	commonDir   string
 	createClock *util.PersistedLamport
 	editClock   *util.PersistedLamport
 }
@@ -33,6 +42,7 @@ func (repo *GitRepo) runGitCommandWithIO(stdin io.Reader, stdout, stderr io.Writ
 
 	cmd := exec.Command("git", args...)
 	cmd.Dir = repo.Path
+	util.ProtectCommand(cmd)
 	cmd.Stdin = stdin
 	cmd.Stdout = stdout
 	cmd.Stderr = stderr
@@ -55,7 +65,7 @@ func (repo *GitRepo) runGitCommandWithStdin(stdin io.Reader, args ...string) (st
 		if stderr == "" {
 			stderr = "Error running git command: " + strings.Join(args, " ")
 		}
-		err = fmt.Errorf(stderr)
+		err = errors.New(stderr)
 	}
 	return stdout, err
 }
@@ -78,17 +88,34 @@ func NewGitRepo(path string, witnesser func(repo *GitRepo) error) (*GitRepo, err
 	// Check the repo and retrieve the root path
 	stdout, err := repo.runGitCommand("rev-parse", "--show-toplevel")
 
-	if err != nil {
-		return nil, ErrNotARepo
-	}
+	if err == nil {
+		// Fix the path to be sure we are at the root
+		repo.Path = stdout
 
-	// Fix the path to be sure we are at the root
-	repo.Path = stdout
+		repo.gitDir, repo.commonDir, err = resolveGitDirs(repo.Path)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		// A bare repository has no working tree, its root is the git directory
+		stdout, err = repo.bareRoot()
+		if err != nil {
+			return nil, ErrNotARepo
+		}
+
+		repo.Path = stdout
+		repo.gitDir = stdout
+		repo.commonDir = stdout
+	}
 
 	err = repo.LoadClocks()
 
 	if err != nil {
-		// No clock yet, trying to initialize them
+		// No clock yet, or an unreadable one: rebuild them from the bugs
+		if !os.IsNotExist(err) {
+			fmt.Fprintf(os.Stderr, "Rebuilding the clocks of git-bug: %s\n", err)
+		}
+
 		repo.createClocks()
 
 		err = witnesser(repo)
@@ -107,9 +134,33 @@ func NewGitRepo(path string, witnesser func(repo *GitRepo) error) (*GitRepo, err
 	return repo, nil
 }
 
+// bareRoot return the absolute path of the bare repository, like the path
+// given to InitBareGitRepo
+func (repo *GitRepo) bareRoot() (string, error) {
+	bare, err := repo.runGitCommand("rev-parse", "--is-bare-repository")
+	if err != nil {
+		return "", err
+	}
+	if bare != "true" {
+		return "", ErrNotARepo
+	}
+
+	gitDir, err := repo.runGitCommand("rev-parse", "--git-dir")
+	if err != nil {
+		return "", err
+	}
+
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(repo.Path, gitDir)
+	}
+
+	return filepath.Abs(gitDir)
+}
+
 // InitGitRepo create a new empty git repo at the given path
 func InitGitRepo(path string) (*GitRepo, error) {
-	repo := &GitRepo{Path: path}
+	gitDir := filepath.Join(path, ".git")
+	repo := &GitRepo{Path: path, gitDir: gitDir, commonDir: gitDir}
 	repo.createClocks()
 
 	_, err := repo.runGitCommand("init", path)
@@ -122,7 +173,7 @@ func InitGitRepo(path string) (*GitRepo, error) {
 
 // InitBareGitRepo create a new --bare empty git repo at the given path
 func InitBareGitRepo(path string) (*GitRepo, error) {
-	repo := &GitRepo{Path: path}
+	repo := &GitRepo{Path: path, gitDir: path, commonDir: path}
 	repo.createClocks()
 
 	_, err := repo.runGitCommand("init", "--bare", path)
@@ -138,6 +189,17 @@ func (repo *GitRepo) GetPath() string {
 	return repo.Path
 }
 
+// GetGitDir return the git directory private to the working tree, like
+// .git/worktrees/<name> for a linked worktree
+func (repo *GitRepo) GetGitDir() string {
+	return repo.gitDir
+}
+
+// GetCommonDir return the git directory shared by all the worktrees
+func (repo *GitRepo) GetCommonDir() string {
+	return repo.commonDir
+}
+
 // GetUserName returns the name the the user has used to configure git
 func (repo *GitRepo) GetUserName() (string, error) {
 	return repo.runGitCommand("config", "user.name")
@@ -153,27 +215,122 @@ func (repo *GitRepo) GetCoreEditor() (string, error) {
 	return repo.runGitCommand("var", "GIT_EDITOR")
 }
 
+// GetConfig returns the value of a git config entry, or ErrNoConfigEntry
+// if it's not set
+func (repo *GitRepo) GetConfig(key string) (string, error) {
+	stdout, stderr, err := repo.runGitCommandRaw(nil, "config", key)
+
+	// git config exit with 1 when the key is not set
+	if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+		return "", ErrNoConfigEntry
+	}
+
+	if err != nil {
+		if stderr == "" {
+			stderr = "Error running git command: config " + key
+		}
+		return "", errors.New(stderr)
+	}
+
+	return stdout, nil
+}
+
+// SetConfig set the value of a git config entry in the repository
+func (repo *GitRepo) SetConfig(key string, value string) error {
+	_, err := repo.runGitCommand("config", key, value)
+
+	return err
+}
+
 // FetchRefs fetch git refs from a remote
-func (repo *GitRepo) FetchRefs(remote, refSpec string) (string, error) {
-	stdout, err := repo.runGitCommand("fetch", remote, refSpec)
+func (repo *GitRepo) FetchRefs(remote string, refSpecs ...string) (string, error) {
+	args := append([]string{"fetch", remote}, refSpecs...)
+	stdout, err := repo.runGitCommand(args...)
 
 	if err != nil {
-		return stdout, fmt.Errorf("failed to fetch from the remote '%s': %v", remote, err)
+		return stdout, remoteError("fetch from", remote, err.Error())
 	}
 
 	return stdout, err
 }
 
 // PushRefs push git refs to a remote
-func (repo *GitRepo) PushRefs(remote string, refSpec string) (string, error) {
-	stdout, stderr, err := repo.runGitCommandRaw(nil, "push", remote, refSpec)
+func (repo *GitRepo) PushRefs(remote string, refSpecs ...string) (string, error) {
+	return repo.PushRefsWithLease(remote, nil, refSpecs...)
+}
+
+// PushRefsWithLease push git refs to a remote, forcing the ones given with
+// --force-with-lease
+func (repo *GitRepo) PushRefsWithLease(remote string, leases map[string]util.Hash, refSpecs ...string) (string, error) {
+	args := []string{"push"}
+
+	refs := make([]string, 0, len(leases))
+	for ref := range leases {
+		refs = append(refs, ref)
+	}
+	sort.Strings(refs)
+
+	for _, ref := range refs {
+		args = append(args, fmt.Sprintf("--force-with-lease=%s:%s", ref, leases[ref]))
+	}
+
+	args = append(args, remote)
+	args = append(args, refSpecs...)
+
+	stdout, stderr, err := repo.runGitCommandRaw(nil, args...)
 
 	if err != nil {
-		return stdout + stderr, fmt.Errorf("failed to push to the remote '%s': %v", remote, err)
+		if stderr == "" {
+			stderr = err.Error()
+		}
+		return stdout + stderr, remoteError("push to", remote, stderr)
 	}
 	return stdout + stderr, nil
 }
 
+// the messages of git telling that a remote refused the credentials, or
+// couldn't be reached
+var (
+	remoteAuthMessages = []string{
+		"Authentication failed",
+		"Permission denied",
+		"could not read Username",
+		"Invalid username or password",
+		"HTTP Basic: Access denied",
+	}
+	remoteNetworkMessages = []string{
+		"Could not resolve host",
+		"Connection refused",
+		"Connection timed out",
+		"Network is unreachable",
+		"Operation timed out",
+	}
+)
+
+// remoteError describe the failure of a fetch or a push, with a hint when
+// it's due to the credentials or the network
+func remoteError(action string, remote string, message string) error {
+	hint := ""
+
+	switch {
+	case containsAny(message, remoteAuthMessages):
+		hint = fmt.Sprintf("\nThe remote '%s' refused the credentials, check your access rights and the credentials configured for git.", remote)
+	case containsAny(message, remoteNetworkMessages):
+		hint = fmt.Sprintf("\nThe remote '%s' can't be reached, check its url with \"git remote get-url %s\" and your network connection.", remote, remote)
+	}
+
+	return fmt.Errorf("failed to %s the remote '%s': %s%s", action, remote, message, hint)
+}
+
+func containsAny(s string, substrings []string) bool {
+	for _, sub := range substrings {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
+
 // StoreData will store arbitrary data and return the corresponding hash
 func (repo *GitRepo) StoreData(data []byte) (util.Hash, error) {
 	var stdin = bytes.NewReader(data)
@@ -197,6 +354,78 @@ func (repo *GitRepo) ReadData(hash util.Hash) ([]byte, error) {
 	return stdout.Bytes(), nil
 }
 
+// OpenData give a reader streaming the data of the given hash from git
+// cat-file
+func (repo *GitRepo) OpenData(hash util.Hash) (io.ReadCloser, error) {
+	cmd := exec.Command("git", "cat-file", "blob", string(hash))
+	cmd.Dir = repo.Path
+	util.ProtectCommand(cmd)
+
+	reader := &gitDataReader{cmd: cmd}
+	cmd.Stderr = &reader.stderr
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	reader.stdout = stdout
+
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	return reader, nil
+}
+
+// gitDataReader read the output of git cat-file as it is produced
+type gitDataReader struct {
+	cmd    *exec.Cmd
+	stdout io.Reader
+	stderr bytes.Buffer
+	waited bool
+}
+
+func (r *gitDataReader) Read(p []byte) (int, error) {
+	n, err := r.stdout.Read(p)
+
+	// a failure of git, like an unknown hash, is only known at the end
+	if err == io.EOF && !r.waited {
+		r.waited = true
+		if waitErr := r.cmd.Wait(); waitErr != nil {
+			message := strings.TrimSpace(r.stderr.String())
+			if message == "" {
+				message = waitErr.Error()
+			}
+			return n, errors.New(message)
+		}
+	}
+
+	return n, err
+}
+
+// Close stop git if the data was not read entirely
+func (r *gitDataReader) Close() error {
+	if r.waited {
+		return nil
+	}
+
+	r.waited = true
+	r.cmd.Process.Kill()
+	r.cmd.Wait()
+
+	return nil
+}
+
+// DataSize return the size in bytes of the data of the given hash
+func (repo *GitRepo) DataSize(hash util.Hash) (int64, error) {
+	stdout, err := repo.runGitCommand("cat-file", "-s", string(hash))
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseInt(stdout, 10, 64)
+}
+
 // StoreTree will store a mapping key-->Hash as a Git tree
 func (repo *GitRepo) StoreTree(entries []TreeEntry) (util.Hash, error) {
 	buffer := prepareTreeEntries(entries)
@@ -233,6 +462,18 @@ func (repo *GitRepo) StoreCommitWithParent(treeHash util.Hash, parent util.Hash)
 	return util.Hash(stdout), nil
 }
 
+// StoreMergeCommit will store a Git commit with the given Git tree and two parents
+func (repo *GitRepo) StoreMergeCommit(treeHash util.Hash, parent1 util.Hash, parent2 util.Hash) (util.Hash, error) {
+	stdout, err := repo.runGitCommand("commit-tree", string(treeHash),
+		"-p", string(parent1), "-p", string(parent2))
+
+	if err != nil {
+		return "", err
+	}
+
+	return util.Hash(stdout), nil
+}
+
 // UpdateRef will create or update a Git reference
 func (repo *GitRepo) UpdateRef(ref string, hash util.Hash) error {
 	_, err := repo.runGitCommand("update-ref", ref, string(hash))
@@ -240,6 +481,45 @@ func (repo *GitRepo) UpdateRef(ref string, hash util.Hash) error {
 	return err
 }
 
+// Begin start a Transaction to update several Git references atomically
+func (repo *GitRepo) Begin() Transaction {
+	return newRefTransaction(repo.UpdateRefs)
+}
+
+// UpdateRefs apply the updates in a single git update-ref transaction, which
+// lock every reference when prepared and update either all of them or none.
+// The expected old values are checked by git when locking.
+func (repo *GitRepo) UpdateRefs(updates []RefUpdate) error {
+	if len(updates) == 0 {
+		return nil
+	}
+
+	var stdin bytes.Buffer
+
+	stdin.WriteString("start\n")
+
+	for _, update := range updates {
+		if update.Hash == "" {
+			fmt.Fprintf(&stdin, "delete %s", update.Ref)
+		} else {
+			fmt.Fprintf(&stdin, "update %s %s", update.Ref, update.Hash)
+		}
+
+		if update.Old != "" {
+			fmt.Fprintf(&stdin, " %s", update.Old)
+		}
+
+		stdin.WriteString("\n")
+	}
+
+	stdin.WriteString("prepare\n")
+	stdin.WriteString("commit\n")
+
+	_, err := repo.runGitCommandWithStdin(&stdin, "update-ref", "--stdin")
+
+	return err
+}
+
 // ListRefs will return a list of Git ref matching the given refspec
 func (repo *GitRepo) ListRefs(refspec string) ([]string, error) {
 	stdout, err := repo.runGitCommand("for-each-ref", "--format=%(refname)", refspec)
@@ -251,12 +531,41 @@ func (repo *GitRepo) ListRefs(refspec string) ([]string, error) {
 	splitted := strings.Split(stdout, "\n")
 
 	if len(splitted) == 1 && splitted[0] == "" {
-		return []string{}, nil
+		return nil, nil
 	}
 
 	return splitted, nil
 }
 
+// ResolveRefs return the hash of the commit each reference matching the
+// refspec point to, in a single pass
+func (repo *GitRepo) ResolveRefs(refspec string) (map[string]util.Hash, error) {
+	result := make(map[string]util.Hash)
+
+	err := repo.ForEachRef(refspec, func(ref string, hash util.Hash) error {
+		result[ref] = hash
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ForEachRef call fn with each reference starting with the prefix and the
+// hash of the commit it point to, streamed from git for-each-ref
+func (repo *GitRepo) ForEachRef(prefix string, fn func(ref string, hash util.Hash) error) error {
+	return repo.streamGitCommand(func(line string) error {
+		fields := strings.SplitN(line, " ", 2)
+		if len(fields) != 2 {
+			return fmt.Errorf("unexpected output of for-each-ref: %s", line)
+		}
+
+		return fn(fields[1], util.Hash(fields[0]))
+	}, "for-each-ref", "--format=%(objectname) %(refname)", prefix)
+}
+
 // ListIds will return a list of Git ref matching the given refspec,
 // stripped to only the last part of the ref
 func (repo *GitRepo) ListIds(refspec string) ([]string, error) {
@@ -270,7 +579,7 @@ func (repo *GitRepo) ListIds(refspec string) ([]string, error) {
 	splitted := strings.Split(stdout, "\n")
 
 	if len(splitted) == 1 && splitted[0] == "" {
-		return []string{}, nil
+		return nil, nil
 	}
 
 	return splitted, nil
@@ -287,6 +596,18 @@ func (repo *GitRepo) RefExist(ref string) (bool, error) {
 	return stdout != "", nil
 }
 
+// ResolveRef return the hash of the commit a reference point to, or an
+// error if it doesn't exist
+func (repo *GitRepo) ResolveRef(ref string) (util.Hash, error) {
+	stdout, err := repo.runGitCommand("rev-parse", "--verify", "--quiet", ref+"^{commit}")
+
+	if err != nil {
+		return "", fmt.Errorf("reference %s not found", ref)
+	}
+
+	return util.Hash(stdout), nil
+}
+
 // CopyRef will create a new reference with the same value as another one
 func (repo *GitRepo) CopyRef(source string, dest string) error {
 	_, err := repo.runGitCommand("update-ref", dest, source)
@@ -294,9 +615,10 @@ func (repo *GitRepo) CopyRef(source string, dest string) error {
 	return err
 }
 
-// ListCommits will return the list of commit hashes of a ref, in chronological order
+// ListCommits will return the list of commit hashes of a ref, in topological
+// order: a commit is always listed after its parents
 func (repo *GitRepo) ListCommits(ref string) ([]util.Hash, error) {
-	stdout, err := repo.runGitCommand("rev-list", "--first-parent", "--reverse", ref)
+	stdout, err := repo.runGitCommand("rev-list", "--topo-order", "--reverse", ref)
 
 	if err != nil {
 		return nil, err
@@ -313,6 +635,78 @@ func (repo *GitRepo) ListCommits(ref string) ([]util.Hash, error) {
 
 }
 
+// WalkCommits call fn with each commit hash of a ref, in topological order,
+// as git list them
+func (repo *GitRepo) WalkCommits(ref string, fn func(hash util.Hash) error) error {
+	return repo.walkRevList(fn, ref)
+}
+
+// WalkCommitsSince call fn with each commit hash of a ref not reachable from
+// since, in topological order
+func (repo *GitRepo) WalkCommitsSince(ref string, since util.Hash, fn func(hash util.Hash) error) error {
+	return repo.walkRevList(fn, ref, "^"+string(since))
+}
+
+// walkRevList stream the output of git rev-list for the given revisions
+func (repo *GitRepo) walkRevList(fn func(hash util.Hash) error, revisions ...string) error {
+	args := append([]string{"rev-list", "--topo-order", "--reverse"}, revisions...)
+
+	return repo.streamGitCommand(func(line string) error {
+		return fn(util.Hash(line))
+	}, args...)
+}
+
+// streamGitCommand call fn with each line of the output of a git command,
+// as it is produced
+func (repo *GitRepo) streamGitCommand(fn func(line string) error, args ...string) error {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repo.Path
+	util.ProtectCommand(cmd)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	// stop git if the walk end early
+	abort := func(err error) error {
+		cmd.Process.Kill()
+		cmd.Wait()
+		return err
+	}
+
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		if scanner.Text() == "" {
+			continue
+		}
+		if err := fn(scanner.Text()); err != nil {
+			return abort(err)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return abort(err)
+	}
+
+	if err := cmd.Wait(); err != nil {
+		message := strings.TrimSpace(stderr.String())
+		if message == "" {
+			message = "Error running git command: " + strings.Join(args, " ")
+		}
+		return errors.New(message)
+	}
+
+	return nil
+}
+
 // ListEntries will return the list of entries in a Git tree
 func (repo *GitRepo) ListEntries(hash util.Hash) ([]TreeEntry, error) {
 	stdout, err := repo.runGitCommand("ls-tree", string(hash))
@@ -346,6 +740,40 @@ func (repo *GitRepo) GetTreeHash(commit util.Hash) (util.Hash, error) {
 	return util.Hash(stdout), nil
 }
 
+// AbbreviateHash return the shortest abbreviation of a hash that is
+// unique among the git objects
+func (repo *GitRepo) AbbreviateHash(hash util.Hash) (string, error) {
+	return repo.runGitCommand("rev-parse", "--short", string(hash))
+}
+
+// ShallowCommits return the commits at the boundary of a shallow clone,
+// as listed in the shallow file of git
+func (repo *GitRepo) ShallowCommits() ([]util.Hash, error) {
+	shallowPath, err := repo.runGitCommand("rev-parse", "--git-path", "shallow")
+	if err != nil {
+		return nil, err
+	}
+
+	if !filepath.IsAbs(shallowPath) {
+		shallowPath = filepath.Join(repo.Path, shallowPath)
+	}
+
+	data, err := ioutil.ReadFile(shallowPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var hashes []util.Hash
+	for _, line := range strings.Fields(string(data)) {
+		hashes = append(hashes, util.Hash(line))
+	}
+
+	return hashes, nil
+}
+
 // AddRemote add a new remote to the repository
 // Not in the interface because it's only used for testing
 func (repo *GitRepo) AddRemote(name string, url string) error {
@@ -355,20 +783,20 @@ func (repo *GitRepo) AddRemote(name string, url string) error {
 }
 
 func (repo *GitRepo) createClocks() {
-	createPath := path.Join(repo.Path, createClockFile)
+	createPath := filepath.Join(repo.commonDir, createClockFile)
 	repo.createClock = util.NewPersistedLamport(createPath)
 
-	editPath := path.Join(repo.Path, editClockFile)
+	editPath := filepath.Join(repo.commonDir, editClockFile)
 	repo.editClock = util.NewPersistedLamport(editPath)
 }
 
 func (repo *GitRepo) LoadClocks() error {
-	createClock, err := util.LoadPersistedLamport(repo.GetPath() + createClockFile)
+	createClock, err := util.LoadPersistedLamport(filepath.Join(repo.commonDir, createClockFile))
 	if err != nil {
 		return err
 	}
 
-	editClock, err := util.LoadPersistedLamport(repo.GetPath() + editClockFile)
+	editClock, err := util.LoadPersistedLamport(filepath.Join(repo.commonDir, editClockFile))
 	if err != nil {
 		return err
 	}
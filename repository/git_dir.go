@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// the content of the .git file of a linked worktree, pointing to its git
+// directory, like "gitdir: /path/to/main/.git/worktrees/name"
+const gitDirPointerPrefix = "gitdir:"
+
+// the file of the git directory of a linked worktree, pointing to the
+// directory it shares with the main worktree
+const commonDirFile = "commondir"
+
+// resolveGitDirs return the git directory of a working tree and the common
+// directory it shares with the other worktrees. They are the same .git
+// directory, except for a linked worktree whose .git is a file pointing to
+// its private git directory.
+func resolveGitDirs(root string) (gitDir string, commonDir string, err error) {
+	dotGit := filepath.Join(root, ".git")
+
+	info, err := os.Stat(dotGit)
+	if err != nil {
+		return "", "", err
+	}
+
+	if info.IsDir() {
+		return dotGit, dotGit, nil
+	}
+
+	data, err := ioutil.ReadFile(dotGit)
+	if err != nil {
+		return "", "", err
+	}
+
+	pointer := strings.TrimSpace(string(data))
+	if !strings.HasPrefix(pointer, gitDirPointerPrefix) {
+		return "", "", fmt.Errorf("invalid %s file, expected \"%s <path>\"", dotGit, gitDirPointerPrefix)
+	}
+
+	gitDir = resolvePath(root, strings.TrimSpace(strings.TrimPrefix(pointer, gitDirPointerPrefix)))
+
+	data, err = ioutil.ReadFile(filepath.Join(gitDir, commonDirFile))
+	if os.IsNotExist(err) {
+		// a submodule, not shared with another worktree
+		return gitDir, gitDir, nil
+	}
+	if err != nil {
+		return "", "", err
+	}
+
+	commonDir = resolvePath(gitDir, strings.TrimSpace(string(data)))
+
+	return gitDir, commonDir, nil
+}
+
+// resolvePath return a path given relative to a directory as an absolute one
+func resolvePath(dir string, path string) string {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	return filepath.Clean(path)
+}
+
+// HooksDir return the directory holding the hooks of a repository: the one
+// configured with core.hooksPath, relative to the root of the working tree,
+// or else the hooks directory shared by the worktrees
+func HooksDir(repo Repo) (string, error) {
+	value, err := repo.GetConfig("core.hooksPath")
+	if err == ErrNoConfigEntry {
+		return filepath.Join(repo.GetCommonDir(), "hooks"), nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if strings.HasPrefix(value, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		value = filepath.Join(home, value[len("~/"):])
+	}
+
+	return resolvePath(repo.GetPath(), value), nil
+}
@@ -1,34 +1,47 @@
 package repository
 
 import (
+	"bytes"
 	"crypto/sha1"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
 	"strings"
+	"sync"
 
 	"github.com/MichaelMure/git-bug/util"
 )
 
 // mockRepoForTest defines an instance of Repo that can be used for testing.
 type mockRepoForTest struct {
+	// the bugs can be read concurrently
+	mu sync.RWMutex
+
 	blobs       map[util.Hash][]byte
 	trees       map[util.Hash]string
 	commits     map[util.Hash]commit
 	refs        map[string]util.Hash
+	config      map[string]string
 	createClock util.LamportClock
 	editClock   util.LamportClock
 }
 
 type commit struct {
 	treeHash util.Hash
-	parent   util.Hash
+	parents  []util.Hash
 }
 
 func NewMockRepoForTest() Repo {
 	return &mockRepoForTest{
-		blobs:       make(map[util.Hash][]byte),
-		trees:       make(map[util.Hash]string),
-		commits:     make(map[util.Hash]commit),
-		refs:        make(map[string]util.Hash),
+		blobs:   make(map[util.Hash][]byte),
+		trees:   make(map[util.Hash]string),
+		commits: make(map[util.Hash]commit),
+		refs:    make(map[string]util.Hash),
+		config: map[string]string{
+			"user.name":  "René Descartes",
+			"user.email": "user@example.com",
+		},
 		createClock: util.NewLamportClock(),
 		editClock:   util.NewLamportClock(),
 	}
@@ -39,13 +52,21 @@ func (r *mockRepoForTest) GetPath() string {
 	return "~/mockRepo/"
 }
 
+func (r *mockRepoForTest) GetGitDir() string {
+	return "~/mockRepo/.git"
+}
+
+func (r *mockRepoForTest) GetCommonDir() string {
+	return "~/mockRepo/.git"
+}
+
 func (r *mockRepoForTest) GetUserName() (string, error) {
-	return "René Descartes", nil
+	return r.GetConfig("user.name")
 }
 
 // GetUserEmail returns the email address that the user has used to configure git.
 func (r *mockRepoForTest) GetUserEmail() (string, error) {
-	return "user@example.com", nil
+	return r.GetConfig("user.email")
 }
 
 // GetCoreEditor returns the name of the editor that the user has used to configure git.
@@ -53,16 +74,42 @@ func (r *mockRepoForTest) GetCoreEditor() (string, error) {
 	return "vi", nil
 }
 
+func (r *mockRepoForTest) GetConfig(key string) (string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	value, ok := r.config[key]
+	if !ok {
+		return "", ErrNoConfigEntry
+	}
+	return value, nil
+}
+
+func (r *mockRepoForTest) SetConfig(key string, value string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.config[key] = value
+	return nil
+}
+
 // PushRefs push git refs to a remote
-func (r *mockRepoForTest) PushRefs(remote string, refSpec string) (string, error) {
+func (r *mockRepoForTest) PushRefs(remote string, refSpecs ...string) (string, error) {
 	return "", nil
 }
 
-func (r *mockRepoForTest) FetchRefs(remote string, refSpec string) (string, error) {
+func (r *mockRepoForTest) PushRefsWithLease(remote string, leases map[string]util.Hash, refSpecs ...string) (string, error) {
+	return "", nil
+}
+
+func (r *mockRepoForTest) FetchRefs(remote string, refSpecs ...string) (string, error) {
 	return "", nil
 }
 
 func (r *mockRepoForTest) StoreData(data []byte) (util.Hash, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	rawHash := sha1.Sum(data)
 	hash := util.Hash(fmt.Sprintf("%x", rawHash))
 	r.blobs[hash] = data
@@ -70,6 +117,9 @@ func (r *mockRepoForTest) StoreData(data []byte) (util.Hash, error) {
 }
 
 func (r *mockRepoForTest) ReadData(hash util.Hash) ([]byte, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	data, ok := r.blobs[hash]
 
 	if !ok {
@@ -79,7 +129,28 @@ func (r *mockRepoForTest) ReadData(hash util.Hash) ([]byte, error) {
 	return data, nil
 }
 
+func (r *mockRepoForTest) OpenData(hash util.Hash) (io.ReadCloser, error) {
+	data, err := r.ReadData(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	return ioutil.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (r *mockRepoForTest) DataSize(hash util.Hash) (int64, error) {
+	data, err := r.ReadData(hash)
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(len(data)), nil
+}
+
 func (r *mockRepoForTest) StoreTree(entries []TreeEntry) (util.Hash, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	buffer := prepareTreeEntries(entries)
 	rawHash := sha1.Sum(buffer.Bytes())
 	hash := util.Hash(fmt.Sprintf("%x", rawHash))
@@ -89,6 +160,9 @@ func (r *mockRepoForTest) StoreTree(entries []TreeEntry) (util.Hash, error) {
 }
 
 func (r *mockRepoForTest) StoreCommit(treeHash util.Hash) (util.Hash, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	rawHash := sha1.Sum([]byte(treeHash))
 	hash := util.Hash(fmt.Sprintf("%x", rawHash))
 	r.commits[hash] = commit{
@@ -98,26 +172,125 @@ func (r *mockRepoForTest) StoreCommit(treeHash util.Hash) (util.Hash, error) {
 }
 
 func (r *mockRepoForTest) StoreCommitWithParent(treeHash util.Hash, parent util.Hash) (util.Hash, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	rawHash := sha1.Sum([]byte(treeHash + parent))
 	hash := util.Hash(fmt.Sprintf("%x", rawHash))
 	r.commits[hash] = commit{
 		treeHash: treeHash,
-		parent:   parent,
+		parents:  []util.Hash{parent},
+	}
+	return hash, nil
+}
+
+func (r *mockRepoForTest) StoreMergeCommit(treeHash util.Hash, parent1 util.Hash, parent2 util.Hash) (util.Hash, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rawHash := sha1.Sum([]byte(treeHash + parent1 + parent2))
+	hash := util.Hash(fmt.Sprintf("%x", rawHash))
+	r.commits[hash] = commit{
+		treeHash: treeHash,
+		parents:  []util.Hash{parent1, parent2},
 	}
 	return hash, nil
 }
 
 func (r *mockRepoForTest) UpdateRef(ref string, hash util.Hash) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	r.refs[ref] = hash
 	return nil
 }
 
+func (r *mockRepoForTest) Begin() Transaction {
+	return newRefTransaction(r.UpdateRefs)
+}
+
+// UpdateRefs check every update before applying any, like git does when
+// preparing the transaction: the objects must exist, the references must
+// have their expected old value, and a reference can't be updated twice
+func (r *mockRepoForTest) UpdateRefs(updates []RefUpdate) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	seen := make(map[string]bool, len(updates))
+
+	for _, update := range updates {
+		if seen[update.Ref] {
+			return fmt.Errorf("multiple updates for ref '%s' not allowed", update.Ref)
+		}
+		seen[update.Ref] = true
+
+		if err := r.checkOldRef(update); err != nil {
+			return err
+		}
+
+		if update.Hash == "" {
+			continue
+		}
+
+		_, isCommit := r.commits[update.Hash]
+		_, isBlob := r.blobs[update.Hash]
+		_, isTree := r.trees[update.Hash]
+		if !isCommit && !isBlob && !isTree {
+			return fmt.Errorf("trying to write ref '%s' with nonexistent object %s", update.Ref, update.Hash)
+		}
+	}
+
+	for _, update := range updates {
+		if update.Hash == "" {
+			delete(r.refs, update.Ref)
+		} else {
+			r.refs[update.Ref] = update.Hash
+		}
+	}
+
+	return nil
+}
+
+func (r *mockRepoForTest) checkOldRef(update RefUpdate) error {
+	current, exist := r.refs[update.Ref]
+
+	switch {
+	case update.Old == "":
+		return nil
+	case update.Old == NoRef && exist:
+		return fmt.Errorf("cannot lock ref '%s': reference already exists", update.Ref)
+	case update.Old != NoRef && current != update.Old:
+		return fmt.Errorf("cannot lock ref '%s': is at %s but expected %s", update.Ref, current, update.Old)
+	}
+
+	return nil
+}
+
 func (r *mockRepoForTest) RefExist(ref string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	_, exist := r.refs[ref]
 	return exist, nil
 }
 
+func (r *mockRepoForTest) ResolveRef(ref string) (util.Hash, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	hash, exist := r.refs[ref]
+
+	if !exist {
+		return "", fmt.Errorf("reference %s not found", ref)
+	}
+
+	return hash, nil
+}
+
 func (r *mockRepoForTest) CopyRef(source string, dest string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	hash, exist := r.refs[source]
 
 	if !exist {
@@ -129,52 +302,171 @@ func (r *mockRepoForTest) CopyRef(source string, dest string) error {
 }
 
 func (r *mockRepoForTest) ListRefs(refspec string) ([]string, error) {
-	keys := make([]string, len(r.refs))
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var keys []string
 
-	i := 0
 	for k := range r.refs {
-		keys[i] = k
-		i++
+		if strings.HasPrefix(k, refspec) {
+			keys = append(keys, k)
+		}
 	}
 
+	// sorted, like git
+	sort.Strings(keys)
+
 	return keys, nil
 }
 
+func (r *mockRepoForTest) ResolveRefs(refspec string) (map[string]util.Hash, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make(map[string]util.Hash)
+
+	for ref, hash := range r.refs {
+		if strings.HasPrefix(ref, refspec) {
+			result[ref] = hash
+		}
+	}
+
+	return result, nil
+}
+
+func (r *mockRepoForTest) ForEachRef(prefix string, fn func(ref string, hash util.Hash) error) error {
+	refs, err := r.ResolveRefs(prefix)
+	if err != nil {
+		return err
+	}
+
+	// sorted, like git, and outside of the lock as fn may use the repo
+	keys := make([]string, 0, len(refs))
+	for ref := range refs {
+		keys = append(keys, ref)
+	}
+	sort.Strings(keys)
+
+	for _, ref := range keys {
+		if err := fn(ref, refs[ref]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // ListIds will return a list of Git ref matching the given refspec,
 // stripped to only the last part of the ref
 func (r *mockRepoForTest) ListIds(refspec string) ([]string, error) {
-	keys := make([]string, len(r.refs))
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var keys []string
 
-	i := 0
 	for k := range r.refs {
-		splitted := strings.Split(k, "/")
-		keys[i] = splitted[len(splitted)-1]
-		i++
+		if strings.HasPrefix(k, refspec) {
+			splitted := strings.Split(k, "/")
+			keys = append(keys, splitted[len(splitted)-1])
+		}
 	}
 
+	// sorted, like git
+	sort.Strings(keys)
+
 	return keys, nil
 }
 
 func (r *mockRepoForTest) ListCommits(ref string) ([]util.Hash, error) {
+	return r.listCommits(ref, "")
+}
+
+// listCommits list the commits of a ref, or of a commit hash, without the
+// ones reachable from since
+func (r *mockRepoForTest) listCommits(ref string, since util.Hash) ([]util.Hash, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	var hashes []util.Hash
+	visited := make(map[util.Hash]bool)
 
-	hash := r.refs[ref]
+	// the ancestors of since are marked as visited first, and not listed
+	var exclude func(hash util.Hash)
+	exclude = func(hash util.Hash) {
+		commit, ok := r.commits[hash]
+		if !ok || visited[hash] {
+			return
+		}
+		visited[hash] = true
+
+		for _, parent := range commit.parents {
+			exclude(parent)
+		}
+	}
 
-	for {
+	if since != "" {
+		exclude(since)
+	}
+
+	// depth-first, each commit listed after its parents
+	var visit func(hash util.Hash)
+	visit = func(hash util.Hash) {
 		commit, ok := r.commits[hash]
+		if !ok || visited[hash] {
+			return
+		}
+		visited[hash] = true
 
-		if !ok {
-			break
+		for _, parent := range commit.parents {
+			visit(parent)
 		}
 
-		hashes = append([]util.Hash{hash}, hashes...)
-		hash = commit.parent
+		hashes = append(hashes, hash)
 	}
 
+	start, ok := r.refs[ref]
+	if !ok {
+		start = util.Hash(ref)
+	}
+	visit(start)
+
 	return hashes, nil
 }
 
+func (r *mockRepoForTest) WalkCommits(ref string, fn func(hash util.Hash) error) error {
+	hashes, err := r.ListCommits(ref)
+	if err != nil {
+		return err
+	}
+
+	for _, hash := range hashes {
+		if err := fn(hash); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *mockRepoForTest) WalkCommitsSince(ref string, since util.Hash, fn func(hash util.Hash) error) error {
+	hashes, err := r.listCommits(ref, since)
+	if err != nil {
+		return err
+	}
+
+	for _, hash := range hashes {
+		if err := fn(hash); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (r *mockRepoForTest) ListEntries(hash util.Hash) ([]TreeEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	var data string
 
 	data, ok := r.trees[hash]
@@ -205,6 +497,53 @@ func (r *mockRepoForTest) GetTreeHash(commit util.Hash) (util.Hash, error) {
 	panic("implement me")
 }
 
+func (r *mockRepoForTest) ShallowCommits() ([]util.Hash, error) {
+	return nil, nil
+}
+
+// the default abbreviation length of git
+const minAbbrevLength = 7
+
+func (r *mockRepoForTest) AbbreviateHash(hash util.Hash) (string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var objects []util.Hash
+
+	for h := range r.blobs {
+		objects = append(objects, h)
+	}
+	for h := range r.trees {
+		objects = append(objects, h)
+	}
+	for h := range r.commits {
+		objects = append(objects, h)
+	}
+
+	found := false
+	for _, h := range objects {
+		if h == hash {
+			found = true
+		}
+	}
+
+	if !found {
+		return "", fmt.Errorf("unknown hash %s", hash)
+	}
+
+	length := minAbbrevLength
+
+	for _, other := range objects {
+		// lengthen the abbreviation until it doesn't match the other object
+		for other != hash && length < len(hash) &&
+			strings.HasPrefix(string(other), string(hash[:length])) {
+			length++
+		}
+	}
+
+	return string(hash[:length]), nil
+}
+
 func (r *mockRepoForTest) LoadClocks() error {
 	return nil
 }
@@ -214,19 +553,17 @@ func (r *mockRepoForTest) WriteClocks() error {
 }
 
 func (r *mockRepoForTest) CreateTimeIncrement() (util.LamportTime, error) {
-	return r.createClock.Increment(), nil
+	return r.createClock.Increment()
 }
 
 func (r *mockRepoForTest) EditTimeIncrement() (util.LamportTime, error) {
-	return r.editClock.Increment(), nil
+	return r.editClock.Increment()
 }
 
 func (r *mockRepoForTest) CreateWitness(time util.LamportTime) error {
-	r.createClock.Witness(time)
-	return nil
+	return r.createClock.Witness(time)
 }
 
 func (r *mockRepoForTest) EditWitness(time util.LamportTime) error {
-	r.editClock.Witness(time)
-	return nil
+	return r.editClock.Witness(time)
 }
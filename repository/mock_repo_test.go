@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/MichaelMure/git-bug/util"
+)
+
+func TestMockAbbreviateHash(t *testing.T) {
+	repo := NewMockRepoForTest().(*mockRepoForTest)
+
+	hash := util.Hash("a85730cf5287d40a1e32d3a671ba2296c73387cb")
+	repo.blobs[hash] = nil
+
+	abbrev, err := repo.AbbreviateHash(hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if abbrev != "a85730c" {
+		t.Fatalf("expected the default length, got %s", abbrev)
+	}
+
+	// colliding on the first 9 characters
+	repo.trees[util.Hash("a85730cf5aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa")] = ""
+	// colliding on the first 8 characters
+	repo.commits[util.Hash("a85730cfbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb")] = commit{}
+
+	abbrev, err = repo.AbbreviateHash(hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if abbrev != "a85730cf52" {
+		t.Fatalf("the abbreviation should be lengthened to be unique, got %s", abbrev)
+	}
+
+	_, err = repo.AbbreviateHash(util.Hash("0000000000000000000000000000000000000000"))
+	if err == nil {
+		t.Fatal("an unknown hash should fail")
+	}
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"errors"
+	"strings"
+)
+
+// PreferencePrefix is the git config section holding the preferences of
+// git-bug, stored with the repository
+const PreferencePrefix = "git-bug."
+
+// GetUserPreference return the value of a preference of git-bug, like
+// "remote" for git-bug.remote, or ErrNoConfigEntry if it's not set
+func GetUserPreference(repo Repo, name string) (string, error) {
+	if err := checkPreferenceName(name); err != nil {
+		return "", err
+	}
+
+	return repo.GetConfig(PreferencePrefix + name)
+}
+
+// SetUserPreference store a preference of git-bug in the git config of
+// the repository
+func SetUserPreference(repo Repo, name string, value string) error {
+	if err := checkPreferenceName(name); err != nil {
+		return err
+	}
+
+	return repo.SetConfig(PreferencePrefix+name, value)
+}
+
+func checkPreferenceName(name string) error {
+	if name == "" || strings.HasPrefix(name, ".") || strings.HasSuffix(name, ".") {
+		return errors.New("invalid preference name: " + name)
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package repository
+
+import "testing"
+
+func TestUserPreference(t *testing.T) {
+	repo := NewMockRepoForTest()
+
+	if _, err := GetUserPreference(repo, "remote"); err != ErrNoConfigEntry {
+		t.Fatalf("expected ErrNoConfigEntry, got %v", err)
+	}
+
+	if err := SetUserPreference(repo, "remote", "bugs"); err != nil {
+		t.Fatal(err)
+	}
+
+	value, err := GetUserPreference(repo, "remote")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value != "bugs" {
+		t.Fatalf("expected bugs, got %s", value)
+	}
+
+	// stored in the git-bug section
+	value, err = repo.GetConfig("git-bug.remote")
+	if err != nil || value != "bugs" {
+		t.Fatalf("expected git-bug.remote to be set, got %s %v", value, err)
+	}
+
+	for _, name := range []string{"", ".remote", "remote."} {
+		if SetUserPreference(repo, name, "value") == nil {
+			t.Fatalf("the name %q should be invalid", name)
+		}
+	}
+}
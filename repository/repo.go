@@ -3,16 +3,29 @@ package repository
 
 import (
 	"bytes"
+	"errors"
+	"io"
 	"strings"
 
 	"github.com/MichaelMure/git-bug/util"
 )
 
+// ErrNoConfigEntry is the error returned when a git config entry is not set
+var ErrNoConfigEntry = errors.New("no config entry for the given key")
+
 // Repo represents a source code repository.
 type Repo interface {
 	// GetPath returns the path to the repo.
 	GetPath() string
 
+	// GetGitDir return the git directory private to the working tree, for
+	// the state of git-bug specific to it
+	GetGitDir() string
+
+	// GetCommonDir return the git directory shared by all the worktrees, for
+	// the state of git-bug common to them
+	GetCommonDir() string
+
 	// GetUserName returns the name the the user has used to configure git
 	GetUserName() (string, error)
 
@@ -22,11 +35,23 @@ type Repo interface {
 	// GetCoreEditor returns the name of the editor that the user has used to configure git.
 	GetCoreEditor() (string, error)
 
+	// GetConfig returns the value of a git config entry, or ErrNoConfigEntry
+	// if it's not set
+	GetConfig(key string) (string, error)
+
+	// SetConfig set the value of a git config entry in the repository
+	SetConfig(key string, value string) error
+
 	// FetchRefs fetch git refs from a remote
-	FetchRefs(remote string, refSpec string) (string, error)
+	FetchRefs(remote string, refSpecs ...string) (string, error)
 
 	// PushRefs push git refs to a remote
-	PushRefs(remote string, refSpec string) (string, error)
+	PushRefs(remote string, refSpecs ...string) (string, error)
+
+	// PushRefsWithLease push git refs to a remote like PushRefs, but the refs
+	// given with their expected remote hash are replaced even if the push is
+	// not a fast-forward, as long as the remote still holds that hash
+	PushRefsWithLease(remote string, leases map[string]util.Hash, refSpecs ...string) (string, error)
 
 	// StoreData will store arbitrary data and return the corresponding hash
 	StoreData(data []byte) (util.Hash, error)
@@ -34,6 +59,14 @@ type Repo interface {
 	// ReadData will attempt to read arbitrary data from the given hash
 	ReadData(hash util.Hash) ([]byte, error)
 
+	// OpenData give a reader streaming the data of the given hash, rather
+	// than reading it at once like ReadData. The reader must be closed.
+	OpenData(hash util.Hash) (io.ReadCloser, error)
+
+	// DataSize return the size in bytes of the data of the given hash,
+	// without reading it
+	DataSize(hash util.Hash) (int64, error)
+
 	// StoreTree will store a mapping key-->Hash as a Git tree
 	StoreTree(mapping []TreeEntry) (util.Hash, error)
 
@@ -43,25 +76,63 @@ type Repo interface {
 	// StoreCommit will store a Git commit with the given Git tree
 	StoreCommitWithParent(treeHash util.Hash, parent util.Hash) (util.Hash, error)
 
+	// StoreMergeCommit will store a Git commit with the given Git tree and
+	// two parents, joining two histories
+	StoreMergeCommit(treeHash util.Hash, parent1 util.Hash, parent2 util.Hash) (util.Hash, error)
+
 	// UpdateRef will create or update a Git reference
 	UpdateRef(ref string, hash util.Hash) error
 
-	// ListRefs will return a list of Git ref matching the given refspec
+	// UpdateRefs create, update or remove several Git references
+	// atomically: either all of them are applied, or none
+	UpdateRefs(updates []RefUpdate) error
+
+	// Begin start a Transaction to update several Git references atomically
+	Begin() Transaction
+
+	// ListRefs will return a list of Git ref matching the given refspec.
+	// A refspec matching nothing, like in a repository without any bug yet,
+	// give a nil list and no error.
 	ListRefs(refspec string) ([]string, error)
 
 	// ListIds will return a list of Git ref matching the given refspec,
-	// stripped to only the last part of the ref
+	// stripped to only the last part of the ref. As for ListRefs, a refspec
+	// matching nothing give a nil list and no error.
 	ListIds(refspec string) ([]string, error)
 
 	// RefExist will check if a reference exist in Git
 	RefExist(ref string) (bool, error)
 
+	// ResolveRef return the hash of the commit a reference point to, or
+	// an error if it doesn't exist
+	ResolveRef(ref string) (util.Hash, error)
+
+	// ResolveRefs return the hash of the commit each reference matching the
+	// refspec point to, in a single pass
+	ResolveRefs(refspec string) (map[string]util.Hash, error)
+
+	// ForEachRef call fn with each reference starting with the prefix and the
+	// hash of the commit it point to, in the order of the refs, without
+	// listing them all first. An error returned by fn stop the iteration and
+	// is returned.
+	ForEachRef(prefix string, fn func(ref string, hash util.Hash) error) error
+
 	// CopyRef will create a new reference with the same value as another one
 	CopyRef(source string, dest string) error
 
-	// ListCommits will return the list of tree hashes of a ref, in chronological order
+	// ListCommits will return the list of commit hashes of a ref, in
+	// topological order: a commit is always listed after its parents
 	ListCommits(ref string) ([]util.Hash, error)
 
+	// WalkCommits call fn with each commit hash of a ref, in the same order
+	// as ListCommits, without holding them all. The walk stop at the first
+	// error returned by fn, which is returned.
+	WalkCommits(ref string, fn func(hash util.Hash) error) error
+
+	// WalkCommitsSince is like WalkCommits, without the commits reachable
+	// from since: only the commits new since a previous state of the ref
+	WalkCommitsSince(ref string, since util.Hash, fn func(hash util.Hash) error) error
+
 	// ListEntries will return the list of entries in a Git tree
 	ListEntries(hash util.Hash) ([]TreeEntry, error)
 
@@ -71,6 +142,14 @@ type Repo interface {
 	// GetTreeHash return the git tree hash referenced in a commit
 	GetTreeHash(commit util.Hash) (util.Hash, error)
 
+	// AbbreviateHash return the shortest abbreviation of a hash that is
+	// unique among the git objects, like git does
+	AbbreviateHash(hash util.Hash) (string, error)
+
+	// ShallowCommits return the commits at the boundary of a shallow clone,
+	// whose parents are missing, or nothing if the clone is complete
+	ShallowCommits() ([]util.Hash, error)
+
 	LoadClocks() error
 
 	WriteClocks() error
@@ -97,8 +176,8 @@ func prepareTreeEntries(entries []TreeEntry) bytes.Buffer {
 func readTreeEntries(s string) ([]TreeEntry, error) {
 	splitted := strings.Split(s, "\n")
 
-	casted := make([]TreeEntry, len(splitted))
-	for i, line := range splitted {
+	casted := make([]TreeEntry, 0, len(splitted))
+	for _, line := range splitted {
 		if line == "" {
 			continue
 		}
@@ -109,7 +188,7 @@ func readTreeEntries(s string) ([]TreeEntry, error) {
 			return nil, err
 		}
 
-		casted[i] = entry
+		casted = append(casted, entry)
 	}
 
 	return casted, nil
@@ -0,0 +1,478 @@
+package repository
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MichaelMure/git-bug/util"
+)
+
+var _ Repo = &TracedRepo{}
+
+// TracedRepo wrap a repository to log every call with its arguments, its
+// duration and the size of its result, to find out which git invocations
+// make a command slow or fail. It's safe for concurrent use as long as the
+// wrapped repository is.
+//
+// The repository is only wrapped when the tracing is enabled, so that it
+// cost nothing otherwise.
+type TracedRepo struct {
+	repo Repo
+
+	mu    sync.Mutex
+	out   io.Writer
+	calls map[string]*traceStats
+}
+
+type traceStats struct {
+	count int
+	total time.Duration
+}
+
+// NewTracedRepo wrap a repository, logging its calls to out
+func NewTracedRepo(repo Repo, out io.Writer) *TracedRepo {
+	return &TracedRepo{
+		repo:  repo,
+		out:   out,
+		calls: make(map[string]*traceStats),
+	}
+}
+
+// trace log a call, started at start. The size describe the result, like
+// "12 refs" or "1024 bytes".
+func (t *TracedRepo) trace(call string, args []string, start time.Time, size string, err error) {
+	duration := time.Since(start)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	stats, ok := t.calls[call]
+	if !ok {
+		stats = &traceStats{}
+		t.calls[call] = stats
+	}
+	stats.count++
+	stats.total += duration
+
+	result := size
+	if err != nil {
+		result = fmt.Sprintf("error: %s", strings.Replace(err.Error(), "\n", " ", -1))
+	}
+
+	fmt.Fprintf(t.out, "trace: %s(%s) %s %s\n",
+		call, strings.Join(args, ", "), duration.Round(time.Microsecond), result)
+}
+
+// Summary describe the calls traced so far: their number and their total
+// duration, then the same by call, the slowest first
+func (t *TracedRepo) Summary() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var calls []string
+	var count int
+	var total time.Duration
+
+	for call, stats := range t.calls {
+		calls = append(calls, call)
+		count += stats.count
+		total += stats.total
+	}
+
+	sort.Slice(calls, func(i, j int) bool {
+		return t.calls[calls[i]].total > t.calls[calls[j]].total
+	})
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "trace: %d calls, %s in total\n", count, total.Round(time.Microsecond))
+	for _, call := range calls {
+		stats := t.calls[call]
+		fmt.Fprintf(&b, "trace:   %-22s %5d calls, %s\n", call, stats.count, stats.total.Round(time.Microsecond))
+	}
+
+	return b.String()
+}
+
+// WriteSummary log the Summary
+func (t *TracedRepo) WriteSummary() {
+	summary := t.Summary()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	fmt.Fprint(t.out, summary)
+}
+
+func traceCount(n int, unit string) string {
+	return fmt.Sprintf("%d %s", n, unit)
+}
+
+func traceString(s string) string {
+	return fmt.Sprintf("%d bytes", len(s))
+}
+
+func traceHashes(hashes ...util.Hash) []string {
+	result := make([]string, len(hashes))
+	for i, hash := range hashes {
+		result[i] = string(hash)
+	}
+	return result
+}
+
+func (t *TracedRepo) GetPath() string {
+	return t.repo.GetPath()
+}
+
+func (t *TracedRepo) GetGitDir() string {
+	return t.repo.GetGitDir()
+}
+
+func (t *TracedRepo) GetCommonDir() string {
+	return t.repo.GetCommonDir()
+}
+
+func (t *TracedRepo) GetUserName() (string, error) {
+	start := time.Now()
+	name, err := t.repo.GetUserName()
+	t.trace("GetUserName", nil, start, traceString(name), err)
+	return name, err
+}
+
+func (t *TracedRepo) GetUserEmail() (string, error) {
+	start := time.Now()
+	email, err := t.repo.GetUserEmail()
+	t.trace("GetUserEmail", nil, start, traceString(email), err)
+	return email, err
+}
+
+func (t *TracedRepo) GetCoreEditor() (string, error) {
+	start := time.Now()
+	editor, err := t.repo.GetCoreEditor()
+	t.trace("GetCoreEditor", nil, start, traceString(editor), err)
+	return editor, err
+}
+
+func (t *TracedRepo) GetConfig(key string) (string, error) {
+	start := time.Now()
+	value, err := t.repo.GetConfig(key)
+	t.trace("GetConfig", []string{key}, start, traceString(value), err)
+	return value, err
+}
+
+func (t *TracedRepo) SetConfig(key string, value string) error {
+	start := time.Now()
+	err := t.repo.SetConfig(key, value)
+	t.trace("SetConfig", []string{key, value}, start, "", err)
+	return err
+}
+
+func (t *TracedRepo) FetchRefs(remote string, refSpecs ...string) (string, error) {
+	start := time.Now()
+	out, err := t.repo.FetchRefs(remote, refSpecs...)
+	t.trace("FetchRefs", append([]string{remote}, refSpecs...), start, traceString(out), err)
+	return out, err
+}
+
+func (t *TracedRepo) PushRefs(remote string, refSpecs ...string) (string, error) {
+	start := time.Now()
+	out, err := t.repo.PushRefs(remote, refSpecs...)
+	t.trace("PushRefs", append([]string{remote}, refSpecs...), start, traceString(out), err)
+	return out, err
+}
+
+func (t *TracedRepo) PushRefsWithLease(remote string, leases map[string]util.Hash, refSpecs ...string) (string, error) {
+	start := time.Now()
+	out, err := t.repo.PushRefsWithLease(remote, leases, refSpecs...)
+	args := []string{remote, traceCount(len(leases), "leases")}
+	t.trace("PushRefsWithLease", append(args, refSpecs...), start, traceString(out), err)
+	return out, err
+}
+
+func (t *TracedRepo) StoreData(data []byte) (util.Hash, error) {
+	start := time.Now()
+	hash, err := t.repo.StoreData(data)
+	t.trace("StoreData", []string{traceCount(len(data), "bytes")}, start, string(hash), err)
+	return hash, err
+}
+
+func (t *TracedRepo) ReadData(hash util.Hash) ([]byte, error) {
+	start := time.Now()
+	data, err := t.repo.ReadData(hash)
+	t.trace("ReadData", traceHashes(hash), start, traceCount(len(data), "bytes"), err)
+	return data, err
+}
+
+func (t *TracedRepo) OpenData(hash util.Hash) (io.ReadCloser, error) {
+	start := time.Now()
+	reader, err := t.repo.OpenData(hash)
+	t.trace("OpenData", traceHashes(hash), start, "", err)
+	return reader, err
+}
+
+func (t *TracedRepo) DataSize(hash util.Hash) (int64, error) {
+	start := time.Now()
+	size, err := t.repo.DataSize(hash)
+	t.trace("DataSize", traceHashes(hash), start, traceCount(int(size), "bytes"), err)
+	return size, err
+}
+
+func (t *TracedRepo) StoreTree(mapping []TreeEntry) (util.Hash, error) {
+	start := time.Now()
+	hash, err := t.repo.StoreTree(mapping)
+	t.trace("StoreTree", []string{traceCount(len(mapping), "entries")}, start, string(hash), err)
+	return hash, err
+}
+
+func (t *TracedRepo) StoreCommit(treeHash util.Hash) (util.Hash, error) {
+	start := time.Now()
+	hash, err := t.repo.StoreCommit(treeHash)
+	t.trace("StoreCommit", traceHashes(treeHash), start, string(hash), err)
+	return hash, err
+}
+
+func (t *TracedRepo) StoreCommitWithParent(treeHash util.Hash, parent util.Hash) (util.Hash, error) {
+	start := time.Now()
+	hash, err := t.repo.StoreCommitWithParent(treeHash, parent)
+	t.trace("StoreCommitWithParent", traceHashes(treeHash, parent), start, string(hash), err)
+	return hash, err
+}
+
+func (t *TracedRepo) StoreMergeCommit(treeHash util.Hash, parent1 util.Hash, parent2 util.Hash) (util.Hash, error) {
+	start := time.Now()
+	hash, err := t.repo.StoreMergeCommit(treeHash, parent1, parent2)
+	t.trace("StoreMergeCommit", traceHashes(treeHash, parent1, parent2), start, string(hash), err)
+	return hash, err
+}
+
+func (t *TracedRepo) UpdateRef(ref string, hash util.Hash) error {
+	start := time.Now()
+	err := t.repo.UpdateRef(ref, hash)
+	t.trace("UpdateRef", []string{ref, string(hash)}, start, "", err)
+	return err
+}
+
+func (t *TracedRepo) UpdateRefs(updates []RefUpdate) error {
+	start := time.Now()
+	err := t.repo.UpdateRefs(updates)
+	t.trace("UpdateRefs", nil, start, traceCount(len(updates), "refs"), err)
+	return err
+}
+
+// Begin start a Transaction, whose calls are traced as well
+func (t *TracedRepo) Begin() Transaction {
+	return &tracedTransaction{tx: t.repo.Begin(), trace: t}
+}
+
+func (t *TracedRepo) ListRefs(refspec string) ([]string, error) {
+	start := time.Now()
+	refs, err := t.repo.ListRefs(refspec)
+	t.trace("ListRefs", []string{refspec}, start, traceCount(len(refs), "refs"), err)
+	return refs, err
+}
+
+func (t *TracedRepo) ListIds(refspec string) ([]string, error) {
+	start := time.Now()
+	ids, err := t.repo.ListIds(refspec)
+	t.trace("ListIds", []string{refspec}, start, traceCount(len(ids), "ids"), err)
+	return ids, err
+}
+
+func (t *TracedRepo) RefExist(ref string) (bool, error) {
+	start := time.Now()
+	exist, err := t.repo.RefExist(ref)
+	t.trace("RefExist", []string{ref}, start, fmt.Sprint(exist), err)
+	return exist, err
+}
+
+func (t *TracedRepo) ResolveRef(ref string) (util.Hash, error) {
+	start := time.Now()
+	hash, err := t.repo.ResolveRef(ref)
+	t.trace("ResolveRef", []string{ref}, start, string(hash), err)
+	return hash, err
+}
+
+func (t *TracedRepo) ResolveRefs(refspec string) (map[string]util.Hash, error) {
+	start := time.Now()
+	refs, err := t.repo.ResolveRefs(refspec)
+	t.trace("ResolveRefs", []string{refspec}, start, traceCount(len(refs), "refs"), err)
+	return refs, err
+}
+
+// ForEachRef trace the iteration as a whole, without the time spent in fn
+func (t *TracedRepo) ForEachRef(prefix string, fn func(ref string, hash util.Hash) error) error {
+	var inFn time.Duration
+	count := 0
+
+	start := time.Now()
+	err := t.repo.ForEachRef(prefix, func(ref string, hash util.Hash) error {
+		count++
+		fnStart := time.Now()
+		defer func() { inFn += time.Since(fnStart) }()
+		return fn(ref, hash)
+	})
+	t.trace("ForEachRef", []string{prefix}, start.Add(inFn), traceCount(count, "refs"), err)
+	return err
+}
+
+func (t *TracedRepo) CopyRef(source string, dest string) error {
+	start := time.Now()
+	err := t.repo.CopyRef(source, dest)
+	t.trace("CopyRef", []string{source, dest}, start, "", err)
+	return err
+}
+
+func (t *TracedRepo) ListCommits(ref string) ([]util.Hash, error) {
+	start := time.Now()
+	hashes, err := t.repo.ListCommits(ref)
+	t.trace("ListCommits", []string{ref}, start, traceCount(len(hashes), "commits"), err)
+	return hashes, err
+}
+
+// WalkCommits trace the walk as a whole, without the time spent in fn
+func (t *TracedRepo) WalkCommits(ref string, fn func(hash util.Hash) error) error {
+	var inFn time.Duration
+	count := 0
+
+	start := time.Now()
+	err := t.repo.WalkCommits(ref, func(hash util.Hash) error {
+		count++
+		fnStart := time.Now()
+		defer func() { inFn += time.Since(fnStart) }()
+		return fn(hash)
+	})
+	t.trace("WalkCommits", []string{ref}, start.Add(inFn), traceCount(count, "commits"), err)
+	return err
+}
+
+// WalkCommitsSince trace the walk as a whole, without the time spent in fn
+func (t *TracedRepo) WalkCommitsSince(ref string, since util.Hash, fn func(hash util.Hash) error) error {
+	var inFn time.Duration
+	count := 0
+
+	start := time.Now()
+	err := t.repo.WalkCommitsSince(ref, since, func(hash util.Hash) error {
+		count++
+		fnStart := time.Now()
+		defer func() { inFn += time.Since(fnStart) }()
+		return fn(hash)
+	})
+	t.trace("WalkCommitsSince", []string{ref, string(since)}, start.Add(inFn), traceCount(count, "commits"), err)
+	return err
+}
+
+func (t *TracedRepo) ListEntries(hash util.Hash) ([]TreeEntry, error) {
+	start := time.Now()
+	entries, err := t.repo.ListEntries(hash)
+	t.trace("ListEntries", traceHashes(hash), start, traceCount(len(entries), "entries"), err)
+	return entries, err
+}
+
+func (t *TracedRepo) FindCommonAncestor(hash1 util.Hash, hash2 util.Hash) (util.Hash, error) {
+	start := time.Now()
+	hash, err := t.repo.FindCommonAncestor(hash1, hash2)
+	t.trace("FindCommonAncestor", traceHashes(hash1, hash2), start, string(hash), err)
+	return hash, err
+}
+
+func (t *TracedRepo) GetTreeHash(commit util.Hash) (util.Hash, error) {
+	start := time.Now()
+	hash, err := t.repo.GetTreeHash(commit)
+	t.trace("GetTreeHash", traceHashes(commit), start, string(hash), err)
+	return hash, err
+}
+
+func (t *TracedRepo) AbbreviateHash(hash util.Hash) (string, error) {
+	start := time.Now()
+	abbrev, err := t.repo.AbbreviateHash(hash)
+	t.trace("AbbreviateHash", traceHashes(hash), start, abbrev, err)
+	return abbrev, err
+}
+
+func (t *TracedRepo) ShallowCommits() ([]util.Hash, error) {
+	start := time.Now()
+	hashes, err := t.repo.ShallowCommits()
+	t.trace("ShallowCommits", nil, start, traceCount(len(hashes), "commits"), err)
+	return hashes, err
+}
+
+func (t *TracedRepo) LoadClocks() error {
+	start := time.Now()
+	err := t.repo.LoadClocks()
+	t.trace("LoadClocks", nil, start, "", err)
+	return err
+}
+
+func (t *TracedRepo) WriteClocks() error {
+	start := time.Now()
+	err := t.repo.WriteClocks()
+	t.trace("WriteClocks", nil, start, "", err)
+	return err
+}
+
+func (t *TracedRepo) CreateTimeIncrement() (util.LamportTime, error) {
+	start := time.Now()
+	time, err := t.repo.CreateTimeIncrement()
+	t.trace("CreateTimeIncrement", nil, start, fmt.Sprint(time), err)
+	return time, err
+}
+
+func (t *TracedRepo) EditTimeIncrement() (util.LamportTime, error) {
+	start := time.Now()
+	time, err := t.repo.EditTimeIncrement()
+	t.trace("EditTimeIncrement", nil, start, fmt.Sprint(time), err)
+	return time, err
+}
+
+func (t *TracedRepo) CreateWitness(time util.LamportTime) error {
+	return t.repo.CreateWitness(time)
+}
+
+func (t *TracedRepo) EditWitness(time util.LamportTime) error {
+	return t.repo.EditWitness(time)
+}
+
+// tracedTransaction trace the calls of a Transaction with the ones of its
+// repository
+type tracedTransaction struct {
+	tx    Transaction
+	trace *TracedRepo
+}
+
+func (tx *tracedTransaction) UpdateRef(ref string, hash util.Hash) error {
+	start := time.Now()
+	err := tx.tx.UpdateRef(ref, hash)
+	tx.trace.trace("Transaction.UpdateRef", []string{ref, string(hash)}, start, "", err)
+	return err
+}
+
+func (tx *tracedTransaction) UpdateRefFrom(ref string, hash util.Hash, old util.Hash) error {
+	start := time.Now()
+	err := tx.tx.UpdateRefFrom(ref, hash, old)
+	tx.trace.trace("Transaction.UpdateRefFrom", []string{ref, string(hash), string(old)}, start, "", err)
+	return err
+}
+
+func (tx *tracedTransaction) DeleteRef(ref string) error {
+	start := time.Now()
+	err := tx.tx.DeleteRef(ref)
+	tx.trace.trace("Transaction.DeleteRef", []string{ref}, start, "", err)
+	return err
+}
+
+func (tx *tracedTransaction) Commit() error {
+	start := time.Now()
+	err := tx.tx.Commit()
+	tx.trace.trace("Transaction.Commit", nil, start, "", err)
+	return err
+}
+
+func (tx *tracedTransaction) Rollback() error {
+	start := time.Now()
+	err := tx.tx.Rollback()
+	tx.trace.trace("Transaction.Rollback", nil, start, "", err)
+	return err
+}
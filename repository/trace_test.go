@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/MichaelMure/git-bug/util"
+)
+
+func TestTracedRepo(t *testing.T) {
+	var out bytes.Buffer
+	repo := NewTracedRepo(NewMockRepoForTest(), &out)
+
+	hash, err := repo.StoreData([]byte("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.ReadData(hash); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.ReadData(util.Hash("missing")); err == nil {
+		t.Fatal("Reading a missing blob should fail")
+	}
+
+	tx := repo.Begin()
+	if err := tx.UpdateRef("refs/bugs/a", hash); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("Expected a line per call, got %q", lines)
+	}
+	if !strings.HasPrefix(lines[0], "trace: StoreData(5 bytes) ") || !strings.HasSuffix(lines[0], string(hash)) {
+		t.Fatalf("Unexpected trace %q", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "trace: ReadData("+string(hash)+") ") || !strings.HasSuffix(lines[1], " 5 bytes") {
+		t.Fatalf("Unexpected trace %q", lines[1])
+	}
+	if !strings.Contains(lines[2], " error: ") {
+		t.Fatalf("The error should be traced, got %q", lines[2])
+	}
+	if !strings.HasPrefix(lines[3], "trace: Transaction.UpdateRef(refs/bugs/a, ") {
+		t.Fatalf("Unexpected trace %q", lines[3])
+	}
+
+	summary := repo.Summary()
+	if !strings.HasPrefix(summary, "trace: 5 calls, ") {
+		t.Fatalf("Unexpected summary %q", summary)
+	}
+	if !strings.Contains(summary, "ReadData                   2 calls") {
+		t.Fatalf("The calls should be counted by kind, got %q", summary)
+	}
+}
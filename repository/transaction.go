@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/MichaelMure/git-bug/util"
+)
+
+// ErrTransactionClosed is returned when using a transaction already
+// committed or rolled back
+var ErrTransactionClosed = errors.New("transaction already closed")
+
+// Transaction group updates of git references so that they only land once
+// every other write (blobs, trees, commits) succeeded, and all together.
+// Objects written during a failed transaction are left unreferenced and will
+// be removed by the git garbage collection.
+type Transaction interface {
+	// UpdateRef schedule the creation or update of a Git reference
+	UpdateRef(ref string, hash util.Hash) error
+
+	// UpdateRefFrom schedule the update of a Git reference expected to
+	// point to old, or to not exist yet if old is NoRef. The Commit fails if
+	// the reference has been changed in the meantime.
+	UpdateRefFrom(ref string, hash util.Hash, old util.Hash) error
+
+	// DeleteRef schedule the removal of a Git reference
+	DeleteRef(ref string) error
+
+	// Commit apply all the scheduled reference updates, or none of them
+	Commit() error
+
+	// Rollback discard all the scheduled reference updates
+	Rollback() error
+}
+
+// NoRef is the expected old hash of a reference that must not exist yet
+const NoRef util.Hash = "0000000000000000000000000000000000000000"
+
+// RefUpdate is the update of a reference, or its removal if the hash is
+// empty
+type RefUpdate struct {
+	Ref  string
+	Hash util.Hash
+	// The hash the reference is expected to point to before the update, or
+	// NoRef if it must not exist. The update fails otherwise. Empty to not
+	// check it.
+	Old util.Hash
+}
+
+// refTransaction accumulate reference updates until they are applied all at
+// once by the repository, with Repo.UpdateRefs
+type refTransaction struct {
+	updates []RefUpdate
+	apply   func(updates []RefUpdate) error
+	closed  bool
+}
+
+func newRefTransaction(apply func(updates []RefUpdate) error) *refTransaction {
+	return &refTransaction{apply: apply}
+}
+
+// NewTransaction create a Transaction whose updates are applied by the given
+// function, like Repo.UpdateRefs, for the types wrapping a Repo
+func NewTransaction(apply func(updates []RefUpdate) error) Transaction {
+	return newRefTransaction(apply)
+}
+
+func (tx *refTransaction) UpdateRef(ref string, hash util.Hash) error {
+	if tx.closed {
+		return ErrTransactionClosed
+	}
+
+	tx.updates = append(tx.updates, RefUpdate{Ref: ref, Hash: hash})
+	return nil
+}
+
+func (tx *refTransaction) UpdateRefFrom(ref string, hash util.Hash, old util.Hash) error {
+	if tx.closed {
+		return ErrTransactionClosed
+	}
+
+	tx.updates = append(tx.updates, RefUpdate{Ref: ref, Hash: hash, Old: old})
+	return nil
+}
+
+func (tx *refTransaction) DeleteRef(ref string) error {
+	if tx.closed {
+		return ErrTransactionClosed
+	}
+
+	tx.updates = append(tx.updates, RefUpdate{Ref: ref})
+	return nil
+}
+
+func (tx *refTransaction) Commit() error {
+	if tx.closed {
+		return ErrTransactionClosed
+	}
+
+	tx.closed = true
+
+	if len(tx.updates) == 0 {
+		return nil
+	}
+
+	return tx.apply(tx.updates)
+}
+
+func (tx *refTransaction) Rollback() error {
+	if tx.closed {
+		return ErrTransactionClosed
+	}
+
+	tx.closed = true
+	tx.updates = nil
+	return nil
+}
@@ -0,0 +1,131 @@
+package static
+
+import (
+	"bytes"
+	"html/template"
+	"time"
+
+	"github.com/MichaelMure/git-bug/bug"
+)
+
+// HTMLOptions tune the rendering of ToHTML
+type HTMLOptions struct {
+	// Markdown render the comments as markdown, sanitized, instead of plain
+	// text
+	Markdown bool
+	// Labels color the labels as configured, instead of with a color
+	// derived from their name
+	Labels *bug.LabelRegistry
+}
+
+type htmlFragment struct {
+	Bug      bug.Snapshot
+	Markdown bool
+	Labels   *bug.LabelRegistry
+	Timeline []timelineItem
+}
+
+// timelineItem is a comment, or another edit of the bug
+type timelineItem struct {
+	Author  bug.Person
+	Time    time.Time
+	Comment *bug.Comment
+	// the edit, like "set-title", and its description
+	Edit    string
+	Summary string
+}
+
+// the classes are the ones of the bug page, so that the same style apply
+var fragmentTemplate = newTemplate("", `
+{{define "fragment"}}<div class="bug">
+<div>
+<span class="status {{.Bug.Status}}">{{.Bug.Status}}</span>
+<span class="title">{{.Bug.Title}}</span>
+<span class="id">{{.Bug.HumanId}}</span>
+</div>
+<div class="labels">{{range .Bug.Labels}}{{template "label" (label $.Labels (print .))}}{{end}}</div>
+<div class="timeline">
+{{range .Timeline}}{{if .Comment}}<div class="comment">
+<div class="comment-header"><b title="{{.Author.Email}}">{{.Author.Name}}</b> commented {{template "date" .Time}}</div>
+<div class="comment-message">
+{{if .Comment.IsDeleted}}<p class="deleted">{{.Comment.GetMessage}}</p>{{else if $.Markdown}}{{markdown .Comment.GetMessage}}{{else}}<p class="text">{{.Comment.GetMessage}}</p>{{end}}
+</div>
+</div>
+{{else}}<div class="details"><b title="{{.Author.Email}}">{{.Author.Name}}</b> {{.Edit}} {{.Summary}} {{template "date" .Time}}</div>
+{{end}}{{end}}</div>
+</div>
+{{end}}
+`)
+
+// ToHTML render a bug as an HTML fragment, to be embedded in a page: its
+// status as a badge, its labels as chips, then the timeline of its comments
+// and other edits. The text written by the users is always escaped, or
+// sanitized when rendered as markdown. The page can reuse the style of the
+// pages of Export.
+func ToHTML(snap bug.Snapshot, opts HTMLOptions) (template.HTML, error) {
+	registry := opts.Labels
+	if registry == nil {
+		registry = &bug.LabelRegistry{}
+	}
+
+	timeline, err := buildTimeline(snap)
+	if err != nil {
+		return "", err
+	}
+
+	data := htmlFragment{
+		Bug:      snap,
+		Markdown: opts.Markdown,
+		Labels:   registry,
+		Timeline: timeline,
+	}
+
+	var buffer bytes.Buffer
+	if err := fragmentTemplate.ExecuteTemplate(&buffer, "fragment", data); err != nil {
+		return "", err
+	}
+
+	return template.HTML(buffer.String()), nil
+}
+
+// buildTimeline interleave the comments with the other edits, in the order
+// of the operations. The archived comments are left out.
+func buildTimeline(snap bug.Snapshot) ([]timelineItem, error) {
+	var timeline []timelineItem
+	next := 0
+
+	for _, op := range snap.Operations {
+		switch op.OpType() {
+		case bug.CreateOp, bug.AddCommentOp:
+			hash, err := bug.HashOperation(op)
+			if err != nil {
+				return nil, err
+			}
+			if snap.IsArchivedComment(hash) || next >= len(snap.Comments) {
+				continue
+			}
+
+			comment := snap.Comments[next]
+			next++
+
+			timeline = append(timeline, timelineItem{
+				Author:  op.GetAuthor(),
+				Time:    op.Time(),
+				Comment: &comment,
+			})
+
+		case bug.ArchiveCommentsOp:
+			// not a change of the bug itself
+
+		default:
+			timeline = append(timeline, timelineItem{
+				Author:  op.GetAuthor(),
+				Time:    op.Time(),
+				Edit:    op.OpType().String(),
+				Summary: op.Summary(),
+			})
+		}
+	}
+
+	return timeline, nil
+}
@@ -0,0 +1,305 @@
+// Package static export the bugs of a repository as a static web site, to be
+// published read-only by any web server, like GitHub Pages.
+//
+// The site hold:
+//
+//	index.json         the summaries of the bugs, last edited first
+//	index.html
+//	bug/<id>.json      a bug with its comments
+//	bug/<id>.html
+//	files/<hash>.<ext> the files attached to the comments, named after their hash
+//
+// An export only write the bugs whose ref moved since the previous one, as
+// recorded in a state file in the output directory, so that it can run on
+// every push.
+package static
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util"
+)
+
+// StateFileName is the file of the output directory recording what has been
+// exported
+const StateFileName = ".git-bug-export.json"
+
+// changing the output invalidate the previous exports
+const stateVersion = 1
+
+// Person is the author of a bug or a comment
+type Person struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// Summary describe a bug in index.json
+type Summary struct {
+	Id           string    `json:"id"`
+	HumanId      string    `json:"human_id"`
+	Title        string    `json:"title"`
+	Status       string    `json:"status"`
+	Author       Person    `json:"author"`
+	Labels       []string  `json:"labels"`
+	CommentCount int       `json:"comment_count"`
+	CreatedAt    time.Time `json:"created_at"`
+	EditedAt     time.Time `json:"edited_at"`
+}
+
+// Comment is a comment of a bug, the first one being its description
+type Comment struct {
+	Author    Person    `json:"author"`
+	Message   string    `json:"message"`
+	CreatedAt time.Time `json:"created_at"`
+	Deleted   bool      `json:"deleted"`
+	// the path of the attached files, from the root of the site
+	Files []string `json:"files"`
+}
+
+// Bug is the content of bug/<id>.json
+type Bug struct {
+	Summary
+	CustomFields map[string]string `json:"custom_fields,omitempty"`
+	Comments     []Comment         `json:"comments"`
+}
+
+// Result count the bugs processed by an export
+type Result struct {
+	Exported  int
+	Unchanged int
+	Removed   int
+}
+
+type state struct {
+	Version int `json:"version"`
+	// the label registry the pages were rendered with
+	Labels util.Hash              `json:"labels"`
+	Bugs   map[string]exportedBug `json:"bugs"`
+}
+
+type exportedBug struct {
+	Head    util.Hash `json:"head"`
+	Summary Summary   `json:"summary"`
+}
+
+// Export write the bugs of the repository as a static site in dir. Only the
+// bugs that changed since the previous export are written, unless full is
+// true. The site is regenerated entirely when the labels were edited.
+func Export(repo repository.Repo, dir string, full bool) (Result, error) {
+	var result Result
+
+	heads, err := bug.ListLocalHeads(repo)
+	if err != nil {
+		return result, err
+	}
+
+	registry, err := bug.ReadLabelRegistry(repo)
+	if err != nil {
+		return result, err
+	}
+
+	previous := readState(dir)
+
+	reusable := previous.Bugs
+	if full || previous.Version != stateVersion || previous.Labels != registry.LastCommitHash() {
+		reusable = nil
+	}
+
+	current := state{
+		Version: stateVersion,
+		Labels:  registry.LastCommitHash(),
+		Bugs:    make(map[string]exportedBug, len(heads)),
+	}
+
+	for _, sub := range []string{"bug", "files"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0755); err != nil {
+			return result, err
+		}
+	}
+
+	ids := make([]string, 0, len(heads))
+	for id := range heads {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	abbreviations := bug.AbbreviateIds(ids)
+
+	for _, id := range ids {
+		// exported again when its abbreviation is lengthened by a new bug
+		exported, ok := reusable[id]
+		if ok && exported.Head == heads[id] && exported.Summary.HumanId == abbreviations[id] {
+			current.Bugs[id] = exported
+			result.Unchanged++
+			continue
+		}
+
+		b, err := bug.ReadLocalBug(repo, id)
+		if err != nil {
+			return result, err
+		}
+
+		snap := b.Compile()
+		snap.SetHumanId(abbreviations[id])
+
+		summary, err := exportBug(repo, dir, registry, snap)
+		if err != nil {
+			return result, err
+		}
+
+		current.Bugs[id] = exportedBug{Head: heads[id], Summary: summary}
+		result.Exported++
+	}
+
+	// the bugs removed since the previous export
+	for id := range previous.Bugs {
+		if _, ok := heads[id]; ok {
+			continue
+		}
+
+		for _, name := range []string{id + ".json", id + ".html"} {
+			err := os.Remove(filepath.Join(dir, "bug", name))
+			if err != nil && !os.IsNotExist(err) {
+				return result, err
+			}
+		}
+		result.Removed++
+	}
+
+	summaries := make([]Summary, 0, len(current.Bugs))
+	for _, exported := range current.Bugs {
+		summaries = append(summaries, exported.Summary)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		if !summaries[i].EditedAt.Equal(summaries[j].EditedAt) {
+			return summaries[i].EditedAt.After(summaries[j].EditedAt)
+		}
+		return summaries[i].Id < summaries[j].Id
+	})
+
+	if err := writeJSON(filepath.Join(dir, "index.json"), summaries); err != nil {
+		return result, err
+	}
+
+	if err := writePage(filepath.Join(dir, "index.html"), indexTemplate, indexPage{
+		Bugs:   summaries,
+		Labels: registry,
+	}); err != nil {
+		return result, err
+	}
+
+	// last, so that an interrupted export is done again
+	return result, writeJSON(filepath.Join(dir, StateFileName), current)
+}
+
+// readState read the state of the previous export. Without a readable one,
+// everything is exported.
+func readState(dir string) state {
+	var s state
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, StateFileName))
+	if err != nil {
+		return s
+	}
+
+	if err := json.Unmarshal(data, &s); err != nil {
+		return state{}
+	}
+
+	return s
+}
+
+func exportBug(repo repository.Repo, dir string, registry *bug.LabelRegistry, snap bug.Snapshot) (Summary, error) {
+	labels := make([]string, len(snap.Labels))
+	for i, label := range snap.Labels {
+		labels[i] = string(label)
+	}
+
+	exported := Bug{
+		Summary: Summary{
+			Id:           snap.Id(),
+			HumanId:      snap.HumanId(),
+			Title:        snap.Title,
+			Status:       snap.Status.String(),
+			Author:       Person{Name: snap.Author.Name, Email: snap.Author.Email},
+			Labels:       labels,
+			CommentCount: len(snap.Comments),
+			CreatedAt:    snap.CreatedAt.UTC(),
+			EditedAt:     snap.LastEdit().UTC(),
+		},
+		CustomFields: snap.CustomFields,
+		Comments:     make([]Comment, len(snap.Comments)),
+	}
+
+	for i, comment := range snap.Comments {
+		files := make([]string, len(comment.Files))
+		for j, hash := range comment.Files {
+			file, err := exportFile(repo, dir, hash)
+			if err != nil {
+				return Summary{}, err
+			}
+			files[j] = file
+		}
+
+		exported.Comments[i] = Comment{
+			Author:    Person{Name: comment.Author.Name, Email: comment.Author.Email},
+			Message:   comment.GetMessage(),
+			CreatedAt: time.Unix(comment.UnixTime, 0).UTC(),
+			Deleted:   comment.IsDeleted(),
+			Files:     files,
+		}
+	}
+
+	base := filepath.Join(dir, "bug", snap.Id())
+
+	if err := writeJSON(base+".json", exported); err != nil {
+		return Summary{}, err
+	}
+
+	err := writePage(base+".html", bugTemplate, bugPage{
+		Bug:    exported,
+		Labels: registry,
+	})
+
+	return exported.Summary, err
+}
+
+// exportFile copy an attached file in the files directory, and return its
+// path from the root of the site. As the files are named after their hash,
+// a file already there is never written again.
+func exportFile(repo repository.Repo, dir string, hash util.Hash) (string, error) {
+	existing, err := filepath.Glob(filepath.Join(dir, "files", string(hash)+"*"))
+	if err != nil {
+		return "", err
+	}
+	if len(existing) == 1 {
+		return path.Join("files", filepath.Base(existing[0])), nil
+	}
+
+	data, err := repo.ReadData(hash)
+	if err != nil {
+		return "", err
+	}
+
+	name := path.Join("files", string(hash)+bug.FileExtension(http.DetectContentType(data)))
+
+	return name, ioutil.WriteFile(filepath.Join(dir, filepath.FromSlash(name)), data, 0644)
+}
+
+func writeJSON(file string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(file, append(data, '\n'), 0644)
+}
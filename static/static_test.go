@@ -0,0 +1,262 @@
+package static
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/bug/operations"
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util"
+)
+
+var rene = bug.Person{
+	Name:  "René Descartes",
+	Email: "rene@descartes.fr",
+}
+
+func checkErr(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func export(t *testing.T, repo repository.Repo, dir string, full bool) Result {
+	t.Helper()
+	result, err := Export(repo, dir, full)
+	checkErr(t, err)
+	return result
+}
+
+func TestExport(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+
+	dir, err := ioutil.TempDir("", "export-static")
+	checkErr(t, err)
+	defer os.RemoveAll(dir)
+
+	b1, err := operations.Create(rene, "first <bug>", "**message**")
+	checkErr(t, err)
+	checkErr(t, operations.Comment(b1, rene, "comment"))
+	checkErr(t, operations.ChangeLabels(nil, b1, rene, []string{"ui"}, nil))
+	checkErr(t, b1.Commit(repo))
+
+	b2, err := operations.Create(rene, "second", "message")
+	checkErr(t, err)
+	checkErr(t, b2.Commit(repo))
+
+	result := export(t, repo, dir, false)
+	if result != (Result{Exported: 2}) {
+		t.Fatalf("Unexpected result %+v", result)
+	}
+
+	var index []Summary
+	data, err := ioutil.ReadFile(filepath.Join(dir, "index.json"))
+	checkErr(t, err)
+	checkErr(t, json.Unmarshal(data, &index))
+	if len(index) != 2 {
+		t.Fatalf("Unexpected index %+v", index)
+	}
+
+	var exported Bug
+	data, err = ioutil.ReadFile(filepath.Join(dir, "bug", b1.Id()+".json"))
+	checkErr(t, err)
+	checkErr(t, json.Unmarshal(data, &exported))
+	if exported.Title != "first <bug>" || len(exported.Comments) != 2 {
+		t.Fatalf("Unexpected bug %+v", exported)
+	}
+
+	page, err := ioutil.ReadFile(filepath.Join(dir, "bug", b1.Id()+".html"))
+	checkErr(t, err)
+	if !strings.Contains(string(page), "first &lt;bug&gt;") ||
+		!strings.Contains(string(page), "<strong>message</strong>") ||
+		!strings.Contains(string(page), `class="label"`) {
+		t.Fatalf("Unexpected page %s", page)
+	}
+
+	// only the changed bugs are written again
+	result = export(t, repo, dir, false)
+	if result != (Result{Unchanged: 2}) {
+		t.Fatalf("Unexpected result %+v", result)
+	}
+
+	checkErr(t, operations.Comment(b2, rene, "new comment"))
+	checkErr(t, b2.Commit(repo))
+
+	result = export(t, repo, dir, false)
+	if result != (Result{Exported: 1, Unchanged: 1}) {
+		t.Fatalf("Unexpected result %+v", result)
+	}
+
+	// the colors of the labels are on every page
+	registry, err := bug.ReadLabelRegistry(repo)
+	checkErr(t, err)
+	checkErr(t, registry.Edit("ui", "#ff8800", ""))
+	checkErr(t, registry.Write(repo))
+
+	result = export(t, repo, dir, false)
+	if result != (Result{Exported: 2}) {
+		t.Fatalf("Unexpected result %+v", result)
+	}
+
+	result = export(t, repo, dir, true)
+	if result != (Result{Exported: 2}) {
+		t.Fatalf("Unexpected result %+v", result)
+	}
+}
+
+func TestExportFiles(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+
+	dir, err := ioutil.TempDir("", "export-static")
+	checkErr(t, err)
+	defer os.RemoveAll(dir)
+	checkErr(t, os.MkdirAll(filepath.Join(dir, "bug"), 0755))
+	checkErr(t, os.MkdirAll(filepath.Join(dir, "files"), 0755))
+
+	png := []byte("\x89PNG\r\n\x1a\n fake image")
+	file, err := repo.StoreData(png)
+	checkErr(t, err)
+
+	b, err := operations.Create(rene, "title", "message")
+	checkErr(t, err)
+	checkErr(t, operations.CommentWithFiles(b, rene, "screenshot", []util.Hash{file}))
+	checkErr(t, b.Commit(repo))
+
+	registry, err := bug.ReadLabelRegistry(repo)
+	checkErr(t, err)
+
+	// the snapshot in memory, as the files are not read back from git
+	for i := 0; i < 2; i++ {
+		_, err = exportBug(repo, dir, registry, b.Compile())
+		checkErr(t, err)
+	}
+
+	var exported Bug
+	data, err := ioutil.ReadFile(filepath.Join(dir, "bug", b.Id()+".json"))
+	checkErr(t, err)
+	checkErr(t, json.Unmarshal(data, &exported))
+
+	attached := exported.Comments[1].Files
+	if len(attached) != 1 || attached[0] != "files/"+string(file)+".png" {
+		t.Fatalf("Unexpected files %v", attached)
+	}
+
+	copied, err := ioutil.ReadFile(filepath.Join(dir, attached[0]))
+	checkErr(t, err)
+	if string(copied) != string(png) {
+		t.Fatal("The file should be copied")
+	}
+}
+
+func TestLabelStyle(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+
+	registry, err := bug.ReadLabelRegistry(repo)
+	checkErr(t, err)
+	checkErr(t, registry.Edit("ui", "#ffeb3b", ""))
+
+	style := string(labelStyle(registry, "ui"))
+	if !strings.Contains(style, "background-color: #ffeb3b; color: #000000") {
+		t.Fatalf("Unexpected style %s", style)
+	}
+
+	// derived from the name, like the web UI
+	style = string(labelStyle(registry, "bug"))
+	if !strings.Contains(style, "background-color: #e91e63") {
+		t.Fatalf("Unexpected style %s", style)
+	}
+
+	// an invalid color stored as is, like a remote could
+	blob, err := repo.StoreData([]byte(`{"bug": {"color": "red;}</style>", "time": 1}}`))
+	checkErr(t, err)
+	tree, err := repo.StoreTree([]repository.TreeEntry{
+		{ObjectType: repository.Blob, Hash: blob, Name: "labels"},
+	})
+	checkErr(t, err)
+	commit, err := repo.StoreCommit(tree)
+	checkErr(t, err)
+	checkErr(t, repo.UpdateRef("refs/git-bug/labels/bugs", commit))
+
+	registry, err = bug.ReadLabelRegistry(repo)
+	checkErr(t, err)
+
+	style = string(labelStyle(registry, "bug"))
+	if !strings.Contains(style, "background-color: #e91e63") {
+		t.Fatalf("Unexpected style %s", style)
+	}
+}
+
+func TestToHTMLEscaping(t *testing.T) {
+	mallory := bug.Person{Name: "<b>Mallory</b>", Email: `"><script>alert(1)</script>`}
+
+	b, err := operations.Create(mallory, "<script>alert('title')</script>",
+		"<script>alert('message')</script>\n\n<img src=x onerror=alert(1)>")
+	checkErr(t, err)
+	checkErr(t, operations.Comment(b, mallory, "[link](javascript:alert(1)) ![image](javascript:alert(2))"))
+	checkErr(t, operations.ChangeLabels(nil, b, mallory, []string{`"><script>alert(3)</script>`}, nil))
+	checkErr(t, operations.SetTitle(b, mallory, "<iframe src=x>"))
+	checkErr(t, b.Commit(repository.NewMockRepoForTest()))
+
+	for _, markdown := range []bool{false, true} {
+		rendered, err := ToHTML(b.Compile(), HTMLOptions{Markdown: markdown})
+		checkErr(t, err)
+		html := string(rendered)
+
+		for _, forbidden := range []string{"<script", "<img", "<iframe", "<b>Mallory", `="javascript:`} {
+			if strings.Contains(html, forbidden) {
+				t.Fatalf("markdown %v: the user content should be escaped, found %s in:\n%s", markdown, forbidden, html)
+			}
+		}
+
+		for _, expected := range []string{
+			`class="status open"`,
+			`class="label"`,
+			"&lt;script&gt;alert(&#39;title&#39;)&lt;/script&gt;",
+			"&lt;b&gt;Mallory&lt;/b&gt;",
+			"set-title",
+		} {
+			if !strings.Contains(html, expected) {
+				t.Fatalf("markdown %v: expected %s in:\n%s", markdown, expected, html)
+			}
+		}
+	}
+}
+
+func TestToHTMLTimeline(t *testing.T) {
+	b, err := operations.Create(rene, "title", "**description**")
+	checkErr(t, err)
+	checkErr(t, operations.ChangeLabels(nil, b, rene, []string{"ui"}, nil))
+	checkErr(t, operations.Comment(b, rene, "comment"))
+	operations.Close(b, rene)
+	checkErr(t, b.Commit(repository.NewMockRepoForTest()))
+
+	rendered, err := ToHTML(b.Compile(), HTMLOptions{Markdown: true})
+	checkErr(t, err)
+	html := string(rendered)
+
+	// in the order of the edits
+	last := -1
+	for _, expected := range []string{"<strong>description</strong>", "label-change &#43;ui", "<p>comment</p>", "set-status closed"} {
+		i := strings.Index(html, expected)
+		if i <= last {
+			t.Fatalf("Expected %s after the previous items in:\n%s", expected, html)
+		}
+		last = i
+	}
+	if !strings.Contains(html, `class="status closed"`) {
+		t.Fatalf("Expected a closed badge in:\n%s", html)
+	}
+
+	// as written without markdown
+	rendered, err = ToHTML(b.Compile(), HTMLOptions{})
+	checkErr(t, err)
+	if !strings.Contains(string(rendered), `<p class="text">**description**</p>`) {
+		t.Fatalf("Expected the raw description in:\n%s", rendered)
+	}
+}
@@ -0,0 +1,236 @@
+package static
+
+import (
+	"html/template"
+	"math"
+	"os"
+	"strconv"
+	"unicode/utf16"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/util"
+)
+
+type indexPage struct {
+	Bugs   []Summary
+	Labels *bug.LabelRegistry
+}
+
+type bugPage struct {
+	Bug    Bug
+	Labels *bug.LabelRegistry
+}
+
+// The pages mimic the layout of the web UI, which can't be rendered without
+// a javascript runtime
+const pageStyle = `
+body { font-family: Roboto, Helvetica, Arial, sans-serif; margin: 0; color: rgba(0, 0, 0, 0.87); }
+header { background-color: #3f51b5; color: white; padding: 16px 24px; font-size: 1.3125rem; }
+header a { color: white; text-decoration: none; }
+main { max-width: 800px; margin: 32px auto; }
+a { color: inherit; text-decoration: none; }
+table { width: 100%; border-collapse: collapse; }
+td { border-bottom: 1px solid rgba(224, 224, 224, 1); padding: 8px; }
+.status { width: 60px; font-size: 0.8em; }
+.open { color: #28a745; }
+.closed { color: #cb2431; }
+.details { color: rgba(0, 0, 0, 0.54); font-size: 0.875rem; }
+.title { font-size: 1.5rem; }
+.id { font-size: 1rem; margin-left: 15px; color: rgba(0, 0, 0, 0.54); }
+.container { display: flex; margin-bottom: 30px; }
+.timeline { width: 70%; margin-top: 20px; margin-right: 20px; }
+.sidebar { width: 30%; margin-top: 20px; }
+.comment { margin-bottom: 16px; }
+.comment-header { padding: 3px 3px 3px 6px; background-color: #f1f8ff; border: 1px solid #d1d5da; border-top-left-radius: 3px; border-top-right-radius: 3px; font-size: 0.875rem; }
+.comment-message { border: 1px solid #d1d5da; border-top: none; border-bottom-left-radius: 3px; border-bottom-right-radius: 3px; min-height: 50px; padding: 5px; }
+.deleted { color: rgba(0, 0, 0, 0.54); font-style: italic; }
+.label { padding: 0 6px; font-size: 0.9em; margin: 0 1px; border-radius: 3px; display: inline-block; border-bottom: solid 1.5px; }
+.labels li { list-style: none; margin: 4px 0; }
+.labels { padding: 0; margin: 0; }
+`
+
+const layoutTemplate = `{{define "layout"}}<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{template "title" .}}</title>
+<style>{{style}}</style>
+</head>
+<body>
+<header><a href="{{root}}index.html">git-bug</a></header>
+<main>
+{{template "content" .}}
+</main>
+</body>
+</html>
+{{end}}
+{{define "label"}}<span class="label" style="{{labelStyle .Labels .Label}}" title="{{labelDescription .Labels .Label}}">{{.Label}}</span>{{end}}
+{{define "date"}}<span title="{{.Format "January 2, 2006, 3:04 pm"}}">{{.Format "Jan 2 2006"}}</span>{{end}}
+`
+
+var indexTemplate = newTemplate("", `
+{{define "title"}}Bugs{{end}}
+{{define "content"}}
+<table>
+{{range .Bugs}}
+<tr>
+<td class="status {{.Status}}">{{.Status}}</td>
+<td>
+<a href="bug/{{.Id}}.html">{{.Title}}</a>
+{{range .Labels}}{{template "label" (label $.Labels .)}}{{end}}
+<div class="details">#{{.HumanId}} opened {{template "date" .CreatedAt}} by {{.Author.Name}}</div>
+</td>
+</tr>
+{{end}}
+</table>
+{{end}}
+`)
+
+var bugTemplate = newTemplate("../", `
+{{define "title"}}{{.Bug.Title}}{{end}}
+{{define "content"}}
+<div>
+<span class="title">{{.Bug.Title}}</span>
+<span class="id">{{.Bug.HumanId}}</span>
+<div class="details"><span title="{{.Bug.Author.Email}}">{{.Bug.Author.Name}}</span> opened this bug {{template "date" .Bug.CreatedAt}}</div>
+</div>
+<div class="container">
+<div class="timeline">
+{{range .Bug.Comments}}
+<div class="comment">
+<div class="comment-header"><b title="{{.Author.Email}}">{{.Author.Name}}</b> commented {{template "date" .CreatedAt}}</div>
+<div class="comment-message">
+{{if .Deleted}}<p class="deleted">{{.Message}}</p>{{else}}{{markdown .Message}}{{end}}
+{{range .Files}}<p><a href="../{{.}}">{{.}}</a></p>{{end}}
+</div>
+</div>
+{{end}}
+</div>
+<div class="sidebar">
+<div>Labels</div>
+<ul class="labels">
+{{range .Bug.Labels}}<li>{{template "label" (label $.Labels .)}}</li>{{end}}
+</ul>
+</div>
+</div>
+{{end}}
+`)
+
+type labelArgs struct {
+	Labels *bug.LabelRegistry
+	Label  string
+}
+
+// newTemplate parse a page, root being the path to the root of the site
+func newTemplate(root string, content string) *template.Template {
+	funcs := template.FuncMap{
+		"root":  func() string { return root },
+		"style": func() template.CSS { return template.CSS(pageStyle) },
+		"markdown": func(text string) template.HTML {
+			// sanitized by the renderer
+			return template.HTML(util.MarkdownToHTML(text))
+		},
+		"label": func(registry *bug.LabelRegistry, label string) labelArgs {
+			return labelArgs{Labels: registry, Label: label}
+		},
+		"labelStyle":       labelStyle,
+		"labelDescription": labelDescription,
+	}
+
+	t := template.Must(template.New("layout").Funcs(funcs).Parse(layoutTemplate))
+	return template.Must(t.Parse(content))
+}
+
+func writePage(file string, t *template.Template, data interface{}) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+
+	err = t.ExecuteTemplate(f, "layout", data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+func labelDescription(registry *bug.LabelRegistry, label string) string {
+	info, _ := registry.Lookup(bug.Label(label))
+	return info.Description
+}
+
+// The 500 shade of the material colors, in the order the web UI pick them
+var labelColors = []string{
+	"#f44336", "#e91e63", "#9c27b0", "#673ab7", "#3f51b5", "#2196f3", "#03a9f4",
+	"#00bcd4", "#009688", "#4caf50", "#8bc34a", "#cddc39", "#ffeb3b", "#ffc107",
+	"#ff9800", "#ff5722", "#795548", "#9e9e9e", "#607d8b",
+}
+
+// labelStyle color a label like the web UI: with the color configured in
+// the registry, or else with one derived from its name
+func labelStyle(registry *bug.LabelRegistry, label string) template.CSS {
+	background := ""
+	// the color end up in the style attribute, check it again
+	if info, ok := registry.Lookup(bug.Label(label)); ok && bug.ValidateLabelColor(info.Color) == nil {
+		background = info.Color
+	}
+
+	if background == "" {
+		// the same hash as the web UI, on the UTF-16 code units
+		var hash int32
+		for _, c := range utf16.Encode([]rune(label)) {
+			hash = (hash << 5) - hash + int32(c)
+		}
+		n := int32(len(labelColors))
+		background = labelColors[((hash%n)+n)%n]
+	}
+
+	text := "#000000"
+	if contrast(luminance(background), 1) >= 2.5 {
+		text = "#ffffff"
+	}
+
+	return template.CSS("background-color: " + background + "; color: " + text +
+		"; border-bottom-color: " + darken(background, 0.2))
+}
+
+func parseColor(hex string) [3]float64 {
+	var rgb [3]float64
+	for i := range rgb {
+		v, _ := strconv.ParseUint(hex[1+2*i:3+2*i], 16, 8)
+		rgb[i] = float64(v)
+	}
+	return rgb
+}
+
+// luminance is the relative luminance of a #rrggbb color
+func luminance(hex string) float64 {
+	rgb := parseColor(hex)
+	for i, v := range rgb {
+		v /= 255
+		if v <= 0.03928 {
+			rgb[i] = v / 12.92
+		} else {
+			rgb[i] = math.Pow((v+0.055)/1.055, 2.4)
+		}
+	}
+	return 0.2126*rgb[0] + 0.7152*rgb[1] + 0.0722*rgb[2]
+}
+
+func contrast(l1 float64, l2 float64) float64 {
+	return (math.Max(l1, l2) + 0.05) / (math.Min(l1, l2) + 0.05)
+}
+
+func darken(hex string, coefficient float64) string {
+	rgb := parseColor(hex)
+	result := "rgb("
+	for i, v := range rgb {
+		if i > 0 {
+			result += ", "
+		}
+		result += strconv.Itoa(int(v * (1 - coefficient)))
+	}
+	return result + ")"
+}
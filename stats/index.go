@@ -0,0 +1,225 @@
+package stats
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+// Index hold the bugs of each label, of each author and the number of bugs
+// of each status, so that a dashboard can display them without reading all
+// the bugs again. It's kept in memory only: it's built from the repository,
+// then updated as the bugs change.
+//
+// An Index is safe for concurrent use.
+type Index struct {
+	mu sync.RWMutex
+
+	labels   map[bug.Label]map[string]bool
+	authors  map[bug.Person]map[string]bool
+	statuses map[bug.Status]int
+
+	// the facets of each bug, to remove them when the bug change
+	bugs map[string]indexEntry
+}
+
+type indexEntry struct {
+	labels []bug.Label
+	author bug.Person
+	status bug.Status
+}
+
+// NewIndex create an empty index
+func NewIndex() *Index {
+	return &Index{
+		labels:   make(map[bug.Label]map[string]bool),
+		authors:  make(map[bug.Person]map[string]bool),
+		statuses: make(map[bug.Status]int),
+		bugs:     make(map[string]indexEntry),
+	}
+}
+
+// BuildIndex read all the local bugs to index them
+func BuildIndex(repo repository.Repo) (*Index, error) {
+	index := NewIndex()
+
+	if err := index.Rebuild(repo); err != nil {
+		return nil, err
+	}
+
+	return index, nil
+}
+
+// Rebuild read all the local bugs again, replacing the current content of
+// the index
+func (idx *Index) Rebuild(repo repository.Repo) error {
+	rebuilt := NewIndex()
+
+	for streamed := range bug.ReadAllLocalBugs(repo) {
+		if streamed.Err != nil {
+			return streamed.Err
+		}
+
+		snap := streamed.Bug.Compile()
+		rebuilt.add(snap.Id(), &snap)
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.labels = rebuilt.labels
+	idx.authors = rebuilt.authors
+	idx.statuses = rebuilt.statuses
+	idx.bugs = rebuilt.bugs
+
+	return nil
+}
+
+// Update index a new or changed bug
+func (idx *Index) Update(b *bug.Bug) {
+	snap := b.Compile()
+	idx.UpdateSnapshot(&snap)
+}
+
+// UpdateSnapshot index a new or changed bug from its snapshot, like the ones
+// held by the cache
+func (idx *Index) UpdateSnapshot(snap *bug.Snapshot) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.remove(snap.Id())
+	idx.add(snap.Id(), snap)
+}
+
+// Remove a bug from the index, like a bug deleted from the repository
+func (idx *Index) Remove(id string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.remove(id)
+}
+
+func (idx *Index) add(id string, snap *bug.Snapshot) {
+	entry := indexEntry{
+		labels: append([]bug.Label(nil), snap.Labels...),
+		author: snap.Author,
+		status: snap.Status,
+	}
+
+	for _, label := range entry.labels {
+		if idx.labels[label] == nil {
+			idx.labels[label] = make(map[string]bool)
+		}
+		idx.labels[label][id] = true
+	}
+
+	if idx.authors[entry.author] == nil {
+		idx.authors[entry.author] = make(map[string]bool)
+	}
+	idx.authors[entry.author][id] = true
+
+	idx.statuses[entry.status]++
+
+	idx.bugs[id] = entry
+}
+
+func (idx *Index) remove(id string) {
+	entry, ok := idx.bugs[id]
+	if !ok {
+		return
+	}
+
+	for _, label := range entry.labels {
+		delete(idx.labels[label], id)
+		if len(idx.labels[label]) == 0 {
+			delete(idx.labels, label)
+		}
+	}
+
+	delete(idx.authors[entry.author], id)
+	if len(idx.authors[entry.author]) == 0 {
+		delete(idx.authors, entry.author)
+	}
+
+	idx.statuses[entry.status]--
+	if idx.statuses[entry.status] == 0 {
+		delete(idx.statuses, entry.status)
+	}
+
+	delete(idx.bugs, id)
+}
+
+// Len return the number of bugs indexed
+func (idx *Index) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	return len(idx.bugs)
+}
+
+// Labels return the ids of the bugs of each label, sorted
+func (idx *Index) Labels() map[bug.Label][]string {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	result := make(map[bug.Label][]string, len(idx.labels))
+	for label, ids := range idx.labels {
+		result[label] = sortedIds(ids)
+	}
+	return result
+}
+
+// Authors return the ids of the bugs opened by each person, sorted
+func (idx *Index) Authors() map[bug.Person][]string {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	result := make(map[bug.Person][]string, len(idx.authors))
+	for author, ids := range idx.authors {
+		result[author] = sortedIds(ids)
+	}
+	return result
+}
+
+// Statuses return the number of bugs of each status
+func (idx *Index) Statuses() map[bug.Status]int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	result := make(map[bug.Status]int, len(idx.statuses))
+	for status, count := range idx.statuses {
+		result[status] = count
+	}
+	return result
+}
+
+// LabelCounts return the number of bugs of each label, the most used first,
+// as in Stats.Labels
+func (idx *Index) LabelCounts() []LabelCount {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	var counts []LabelCount
+	for label, ids := range idx.labels {
+		counts = append(counts, LabelCount{Label: string(label), Count: len(ids)})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		a, b := counts[i], counts[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Label < b.Label
+	})
+	return counts
+}
+
+func sortedIds(ids map[string]bool) []string {
+	result := make([]string, 0, len(ids))
+	for id := range ids {
+		result = append(result, id)
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,129 @@
+package stats
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/internal/testfixture"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+// scan compute the facets of the index by reading all the bugs
+func scan(t *testing.T, repo repository.Repo) (map[bug.Label][]string, map[bug.Person][]string, map[bug.Status]int) {
+	labels := make(map[bug.Label][]string)
+	authors := make(map[bug.Person][]string)
+	statuses := make(map[bug.Status]int)
+
+	for streamed := range bug.ReadAllLocalBugs(repo) {
+		if streamed.Err != nil {
+			t.Fatal(streamed.Err)
+		}
+
+		snap := streamed.Bug.Compile()
+		for _, label := range snap.Labels {
+			labels[label] = append(labels[label], snap.Id())
+		}
+		authors[snap.Author] = append(authors[snap.Author], snap.Id())
+		statuses[snap.Status]++
+	}
+
+	for _, ids := range labels {
+		sort.Strings(ids)
+	}
+	for _, ids := range authors {
+		sort.Strings(ids)
+	}
+
+	return labels, authors, statuses
+}
+
+func checkIndex(t *testing.T, repo repository.Repo, index *Index) {
+	labels, authors, statuses := scan(t, repo)
+
+	if !reflect.DeepEqual(index.Labels(), labels) {
+		t.Fatalf("Unexpected labels %v, expected %v", index.Labels(), labels)
+	}
+	if !reflect.DeepEqual(index.Authors(), authors) {
+		t.Fatalf("Unexpected authors %v, expected %v", index.Authors(), authors)
+	}
+	if !reflect.DeepEqual(index.Statuses(), statuses) {
+		t.Fatalf("Unexpected statuses %v, expected %v", index.Statuses(), statuses)
+	}
+}
+
+func buildBug(t *testing.T, repo repository.Repo, spec *testfixture.Spec) *bug.Bug {
+	b, err := testfixture.BuildBug(repo, spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestBuildIndex(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+
+	buildBug(t, repo, testfixture.New(rene).Create("first", "message").Label("bug", "ui"))
+	buildBug(t, repo, testfixture.New(isaac).Create("second", "message").Label("bug"))
+	buildBug(t, repo, testfixture.New(rene).Create("third", "message").Commit().As(isaac).Close())
+
+	index, err := BuildIndex(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checkIndex(t, repo, index)
+
+	if index.Len() != 3 || index.Statuses()[bug.ClosedStatus] != 1 {
+		t.Fatalf("Unexpected index of %d bugs, statuses %v", index.Len(), index.Statuses())
+	}
+
+	expected := []LabelCount{{Label: "bug", Count: 2}, {Label: "ui", Count: 1}}
+	if counts := index.LabelCounts(); !reflect.DeepEqual(counts, expected) {
+		t.Fatalf("Unexpected label counts %v", counts)
+	}
+}
+
+func TestIndexUpdate(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+
+	first := buildBug(t, repo, testfixture.New(rene).Create("first", "message").Label("bug", "ui"))
+
+	index, err := BuildIndex(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a changed bug
+	err = testfixture.Edit(repo, first, testfixture.New(isaac).Label("crash").Unlabel("ui").Close())
+	if err != nil {
+		t.Fatal(err)
+	}
+	index.Update(first)
+	checkIndex(t, repo, index)
+
+	// a new bug
+	second := buildBug(t, repo, testfixture.New(isaac).Create("second", "message").Label("crash"))
+	index.Update(second)
+	checkIndex(t, repo, index)
+
+	// updating again an unchanged bug doesn't count it twice
+	index.Update(second)
+	checkIndex(t, repo, index)
+
+	if _, ok := index.Labels()["ui"]; ok {
+		t.Fatal("A label without bug should be removed from the index")
+	}
+
+	index.Remove(second.Id())
+	if index.Len() != 1 || len(index.Labels()["crash"]) != 1 || len(index.Authors()[isaac]) != 0 {
+		t.Fatalf("Unexpected index after removal %v %v", index.Labels(), index.Authors())
+	}
+
+	// rebuilt from the repository
+	if err := index.Rebuild(repo); err != nil {
+		t.Fatal(err)
+	}
+	checkIndex(t, repo, index)
+}
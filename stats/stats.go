@@ -0,0 +1,341 @@
+// Package stats compute aggregated metrics over the bugs of a repository,
+// like the activity per week or the time needed to close a bug.
+package stats
+
+import (
+	"sort"
+	"time"
+
+	"github.com/MichaelMure/git-bug/bug"
+)
+
+// The number of commenters listed in Stats.TopCommenters
+const topCommenters = 5
+
+const week = 7 * 24 * time.Hour
+
+// Options define the scope of the metrics
+type Options struct {
+	// Number of weeks of activity, ending with the week of Until
+	Weeks int
+	// Only account for the events within this window. A zero time leave
+	// the window open on that side.
+	Since time.Time
+	Until time.Time
+	// Compute the breakdown by author
+	ByAuthor bool
+	// The current time, to end the activity when Until is not set
+	Now time.Time
+}
+
+// Stats hold the metrics of a set of bugs
+type Stats struct {
+	Total  int `json:"total"`
+	Open   int `json:"open"`
+	Closed int `json:"closed"`
+
+	Weeks []WeekActivity `json:"weeks"`
+
+	TopCommenters []PersonCount `json:"top_commenters"`
+
+	// The median time between the opening of a bug and its first closing,
+	// see bug.Snapshot.TimeToClose, over the bugs closed at least once. Only
+	// meaningful if ClosedWithTime is not 0.
+	MedianTimeToClose time.Duration `json:"median_time_to_close_ns"`
+	ClosedWithTime    int           `json:"closed_with_time"`
+
+	Labels []LabelCount `json:"labels"`
+
+	// The total time logged on the bugs
+	TimeSpent time.Duration `json:"time_spent_ns"`
+
+	Authors []AuthorActivity `json:"authors,omitempty"`
+
+	// The number of bugs created before the operations had a timestamp,
+	// that are excluded from the time-based metrics
+	Untimed int `json:"untimed"`
+}
+
+// WeekActivity is the number of bugs opened and closed during a week
+type WeekActivity struct {
+	Start  time.Time `json:"start"`
+	Opened int       `json:"opened"`
+	Closed int       `json:"closed"`
+}
+
+type PersonCount struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	Count int    `json:"count"`
+}
+
+type LabelCount struct {
+	Label string `json:"label"`
+	Count int    `json:"count"`
+}
+
+// AuthorActivity is the number of bugs opened and closed and the number of
+// comments of a person
+type AuthorActivity struct {
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Opened   int    `json:"opened"`
+	Closed   int    `json:"closed"`
+	Comments int    `json:"comments"`
+}
+
+// Compute the metrics of the given bugs
+func Compute(snapshots []*bug.Snapshot, opts Options) *Stats {
+	c := newComputation(opts)
+
+	for _, snap := range snapshots {
+		c.add(snap)
+	}
+
+	return c.result()
+}
+
+type computation struct {
+	opts  Options
+	stats Stats
+
+	// the start of the first week of activity
+	firstWeek time.Time
+
+	commenters map[bug.Person]int
+	labels     map[bug.Label]int
+	authors    map[bug.Person]*AuthorActivity
+	closeTimes []time.Duration
+}
+
+func newComputation(opts Options) *computation {
+	end := opts.Until
+	if end.IsZero() {
+		end = opts.Now
+	}
+
+	c := &computation{
+		opts:       opts,
+		firstWeek:  end.Add(-time.Duration(opts.Weeks) * week),
+		commenters: make(map[bug.Person]int),
+		labels:     make(map[bug.Label]int),
+		authors:    make(map[bug.Person]*AuthorActivity),
+	}
+
+	c.stats.Weeks = make([]WeekActivity, opts.Weeks)
+	for i := range c.stats.Weeks {
+		c.stats.Weeks[i].Start = c.firstWeek.Add(time.Duration(i) * week)
+	}
+
+	return c
+}
+
+func (c *computation) windowed() bool {
+	return !c.opts.Since.IsZero() || !c.opts.Until.IsZero()
+}
+
+// hasTime tell if an event has a real timestamp, as the old operations
+// don't have one
+func hasTime(t time.Time) bool {
+	return t.Unix() > 0
+}
+
+func (c *computation) inWindow(t time.Time) bool {
+	if !hasTime(t) {
+		return false
+	}
+	if !c.opts.Since.IsZero() && t.Before(c.opts.Since) {
+		return false
+	}
+	if !c.opts.Until.IsZero() && !t.Before(c.opts.Until) {
+		return false
+	}
+	return true
+}
+
+// inScope tell if an event should be accounted for. Without window, the
+// events without timestamp are accounted for in the metrics that are not
+// based on time.
+func (c *computation) inScope(t time.Time) bool {
+	return !c.windowed() || c.inWindow(t)
+}
+
+func (c *computation) add(snap *bug.Snapshot) {
+	timed := hasTime(snap.CreatedAt)
+	if !timed {
+		c.stats.Untimed++
+	}
+
+	if c.inScope(snap.CreatedAt) {
+		c.stats.Total++
+		switch snap.Status {
+		case bug.OpenStatus:
+			c.stats.Open++
+		case bug.ClosedStatus:
+			c.stats.Closed++
+		}
+
+		for _, label := range snap.Labels {
+			c.labels[label]++
+		}
+
+		c.author(snap.Author).Opened++
+	}
+
+	if timed {
+		c.countWeek(snap.CreatedAt, func(w *WeekActivity) { w.Opened++ })
+	}
+
+	// the first comment is the description of the bug
+	for i := 1; i < len(snap.Comments); i++ {
+		comment := snap.Comments[i]
+		if comment.IsDeleted() || !c.inScope(time.Unix(comment.UnixTime, 0)) {
+			continue
+		}
+
+		c.commenters[comment.Author]++
+		c.author(comment.Author).Comments++
+	}
+
+	for _, log := range snap.TimeLogs {
+		if c.inScope(log.Time()) {
+			c.stats.TimeSpent += log.Duration
+		}
+	}
+
+	for _, change := range snap.StatusHistory() {
+		if change.Status != bug.ClosedStatus {
+			continue
+		}
+
+		if c.inScope(change.Time()) {
+			c.author(change.Author).Closed++
+		}
+
+		if hasTime(change.Time()) {
+			c.countWeek(change.Time(), func(w *WeekActivity) { w.Closed++ })
+		}
+	}
+
+	// the time to close is the one of the snapshot, up to the first closing
+	if duration, ok := snap.TimeToClose(); ok && timed && c.inWindow(snap.CreatedAt.Add(duration)) {
+		c.closeTimes = append(c.closeTimes, duration)
+	}
+}
+
+// countWeek update the week of activity of an event, if any
+func (c *computation) countWeek(t time.Time, update func(w *WeekActivity)) {
+	if !c.inWindow(t) || t.Before(c.firstWeek) {
+		return
+	}
+
+	i := int(t.Sub(c.firstWeek) / week)
+	if i < len(c.stats.Weeks) {
+		update(&c.stats.Weeks[i])
+	}
+}
+
+func (c *computation) author(person bug.Person) *AuthorActivity {
+	activity, ok := c.authors[person]
+	if !ok {
+		activity = &AuthorActivity{Name: person.Name, Email: person.Email}
+		c.authors[person] = activity
+	}
+	return activity
+}
+
+func (c *computation) result() *Stats {
+	stats := c.stats
+
+	for person, count := range c.commenters {
+		stats.TopCommenters = append(stats.TopCommenters, PersonCount{
+			Name:  person.Name,
+			Email: person.Email,
+			Count: count,
+		})
+	}
+	sort.Slice(stats.TopCommenters, func(i, j int) bool {
+		a, b := stats.TopCommenters[i], stats.TopCommenters[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Name < b.Name
+	})
+	if len(stats.TopCommenters) > topCommenters {
+		stats.TopCommenters = stats.TopCommenters[:topCommenters]
+	}
+
+	if len(c.closeTimes) > 0 {
+		sort.Slice(c.closeTimes, func(i, j int) bool {
+			return c.closeTimes[i] < c.closeTimes[j]
+		})
+		stats.MedianTimeToClose = median(c.closeTimes)
+		stats.ClosedWithTime = len(c.closeTimes)
+	}
+
+	for label, count := range c.labels {
+		stats.Labels = append(stats.Labels, LabelCount{
+			Label: string(label),
+			Count: count,
+		})
+	}
+	sort.Slice(stats.Labels, func(i, j int) bool {
+		a, b := stats.Labels[i], stats.Labels[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Label < b.Label
+	})
+
+	if c.opts.ByAuthor {
+		for _, activity := range c.authors {
+			if activity.Opened+activity.Closed+activity.Comments == 0 {
+				continue
+			}
+			stats.Authors = append(stats.Authors, *activity)
+		}
+		sort.Slice(stats.Authors, func(i, j int) bool {
+			a, b := stats.Authors[i], stats.Authors[j]
+			totalA := a.Opened + a.Closed + a.Comments
+			totalB := b.Opened + b.Closed + b.Comments
+			if totalA != totalB {
+				return totalA > totalB
+			}
+			return a.Name < b.Name
+		})
+	}
+
+	return &stats
+}
+
+// median of sorted durations
+func median(sorted []time.Duration) time.Duration {
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[middle]
+	}
+	return (sorted[middle-1] + sorted[middle]) / 2
+}
+
+var sparkTicks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline render a series of values as a single line of bars
+func Sparkline(values []int) string {
+	max := 0
+	for _, v := range values {
+		if v > max {
+			max = v
+		}
+	}
+
+	line := make([]rune, len(values))
+	for i, v := range values {
+		if max == 0 {
+			line[i] = sparkTicks[0]
+			continue
+		}
+		line[i] = sparkTicks[v*(len(sparkTicks)-1)/max]
+	}
+
+	return string(line)
+}
@@ -0,0 +1,198 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/bug/operations"
+)
+
+var rene = bug.Person{
+	Name:  "René Descartes",
+	Email: "rene@descartes.fr",
+}
+
+var isaac = bug.Person{
+	Name:  "Isaac Newton",
+	Email: "isaac@newton.uk",
+}
+
+var now = time.Date(2018, 9, 1, 12, 0, 0, 0, time.UTC)
+
+func daysAgo(days int) time.Time {
+	return now.Add(-time.Duration(days) * 24 * time.Hour)
+}
+
+func closeOp(author bug.Person, t time.Time) operations.SetStatusOperation {
+	op := operations.NewSetStatusOp(author, bug.ClosedStatus)
+	op.UnixTime = t.Unix()
+	return op
+}
+
+func reopenOp(author bug.Person, t time.Time) operations.SetStatusOperation {
+	op := operations.NewSetStatusOp(author, bug.OpenStatus)
+	op.UnixTime = t.Unix()
+	return op
+}
+
+func comment(author bug.Person, t time.Time) bug.Comment {
+	return bug.Comment{Author: author, Message: "message", UnixTime: t.Unix()}
+}
+
+func testSnapshots() []*bug.Snapshot {
+	return []*bug.Snapshot{
+		{
+			Status:    bug.ClosedStatus,
+			Author:    rene,
+			CreatedAt: daysAgo(10),
+			Labels:    []bug.Label{"bug", "ui"},
+			Comments: []bug.Comment{
+				comment(rene, daysAgo(10)),
+				comment(isaac, daysAgo(9)),
+				comment(isaac, daysAgo(8)),
+			},
+			Operations: []bug.Operation{closeOp(isaac, daysAgo(8))},
+			TimeLogs: []bug.TimeLog{
+				{Author: isaac, Duration: 2 * time.Hour, UnixTime: daysAgo(9).Unix()},
+				{Author: rene, Duration: 30 * time.Minute, UnixTime: daysAgo(20).Unix()},
+			},
+		},
+		{
+			Status:    bug.ClosedStatus,
+			Author:    isaac,
+			CreatedAt: daysAgo(30),
+			Labels:    []bug.Label{"bug"},
+			Comments: []bug.Comment{
+				comment(isaac, daysAgo(30)),
+				comment(rene, daysAgo(26)),
+			},
+			Operations: []bug.Operation{closeOp(rene, daysAgo(26))},
+		},
+		{
+			Status:    bug.OpenStatus,
+			Author:    rene,
+			CreatedAt: daysAgo(1),
+			Comments:  []bug.Comment{comment(rene, daysAgo(1))},
+		},
+		// created before the timestamps
+		{
+			Status:     bug.ClosedStatus,
+			Author:     isaac,
+			CreatedAt:  time.Unix(0, 0),
+			Labels:     []bug.Label{"bug"},
+			Comments:   []bug.Comment{comment(isaac, time.Unix(0, 0))},
+			Operations: []bug.Operation{closeOp(isaac, time.Unix(0, 0))},
+		},
+	}
+}
+
+func TestCompute(t *testing.T) {
+	s := Compute(testSnapshots(), Options{Weeks: 4, Now: now, ByAuthor: true})
+
+	if s.Total != 4 || s.Open != 1 || s.Closed != 3 {
+		t.Fatalf("unexpected counts: %d %d %d", s.Total, s.Open, s.Closed)
+	}
+
+	if s.Untimed != 1 {
+		t.Fatalf("expected 1 untimed bug, got %d", s.Untimed)
+	}
+
+	// the untimed bug is not in 1970
+	var opened, closed int
+	for _, w := range s.Weeks {
+		opened += w.Opened
+		closed += w.Closed
+	}
+	if len(s.Weeks) != 4 || opened != 2 || closed != 2 {
+		t.Fatalf("unexpected activity: %v", s.Weeks)
+	}
+	if s.Weeks[3].Opened != 1 || s.Weeks[2].Opened != 1 || s.Weeks[2].Closed != 1 {
+		t.Fatalf("unexpected weeks: %v", s.Weeks)
+	}
+
+	if s.ClosedWithTime != 2 || s.MedianTimeToClose != 3*24*time.Hour {
+		t.Fatalf("unexpected median: %v over %d", s.MedianTimeToClose, s.ClosedWithTime)
+	}
+
+	if len(s.TopCommenters) != 2 || s.TopCommenters[0].Name != isaac.Name || s.TopCommenters[0].Count != 2 {
+		t.Fatalf("unexpected commenters: %v", s.TopCommenters)
+	}
+
+	if len(s.Labels) != 2 || s.Labels[0] != (LabelCount{"bug", 3}) || s.Labels[1] != (LabelCount{"ui", 1}) {
+		t.Fatalf("unexpected labels: %v", s.Labels)
+	}
+
+	if s.TimeSpent != 150*time.Minute {
+		t.Fatalf("unexpected time spent: %v", s.TimeSpent)
+	}
+
+	if len(s.Authors) != 2 {
+		t.Fatalf("unexpected authors: %v", s.Authors)
+	}
+	expectedIsaac := AuthorActivity{Name: isaac.Name, Email: isaac.Email, Opened: 2, Closed: 2, Comments: 2}
+	if s.Authors[0] != expectedIsaac {
+		t.Fatalf("unexpected activity of isaac: %v", s.Authors[0])
+	}
+}
+
+func TestComputeReopened(t *testing.T) {
+	reopened := &bug.Snapshot{
+		Status:    bug.OpenStatus,
+		Author:    rene,
+		CreatedAt: daysAgo(10),
+		Comments:  []bug.Comment{comment(rene, daysAgo(10))},
+		Operations: []bug.Operation{
+			closeOp(isaac, daysAgo(8)),
+			reopenOp(rene, daysAgo(6)),
+			closeOp(isaac, daysAgo(2)),
+			reopenOp(rene, daysAgo(1)),
+		},
+	}
+
+	s := Compute([]*bug.Snapshot{reopened}, Options{Weeks: 4, Now: now})
+
+	// same as the TimeToClose of the snapshot
+	duration, ok := reopened.TimeToClose()
+	if !ok || s.ClosedWithTime != 1 || s.MedianTimeToClose != duration || duration != 2*24*time.Hour {
+		t.Fatalf("unexpected median: %v over %d", s.MedianTimeToClose, s.ClosedWithTime)
+	}
+}
+
+func TestComputeWindow(t *testing.T) {
+	s := Compute(testSnapshots(), Options{
+		Weeks: 4,
+		Now:   now,
+		Since: daysAgo(15),
+		Until: daysAgo(5),
+	})
+
+	if s.Total != 1 || s.Closed != 1 {
+		t.Fatalf("unexpected counts: %d %d", s.Total, s.Closed)
+	}
+
+	if s.ClosedWithTime != 1 || s.MedianTimeToClose != 2*24*time.Hour {
+		t.Fatalf("unexpected median: %v over %d", s.MedianTimeToClose, s.ClosedWithTime)
+	}
+
+	if len(s.TopCommenters) != 1 || s.TopCommenters[0].Count != 2 {
+		t.Fatalf("unexpected commenters: %v", s.TopCommenters)
+	}
+
+	if s.TimeSpent != 2*time.Hour {
+		t.Fatalf("unexpected time spent: %v", s.TimeSpent)
+	}
+
+	if s.Authors != nil {
+		t.Fatal("the breakdown by author was not requested")
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	if Sparkline([]int{0, 1, 2, 4, 8}) != "▁▁▂▄█" {
+		t.Fatalf("unexpected sparkline %s", Sparkline([]int{0, 1, 2, 4, 8}))
+	}
+	if Sparkline([]int{0, 0}) != "▁▁" {
+		t.Fatal("an empty activity should render the lowest bars")
+	}
+}
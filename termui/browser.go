@@ -0,0 +1,38 @@
+package termui
+
+import (
+	"github.com/MichaelMure/git-bug/bridge"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/webui"
+	"github.com/skratchdot/open-golang/open"
+)
+
+const msgPopupBrowserTitle = "Open in browser"
+
+// bugWebUrl return the web page of a bug: on the bug tracker it was imported
+// from, or else in the running web UI
+func bugWebUrl(repo cache.RepoCacher, b cache.BugCacher) (string, bool) {
+	snap := b.Snapshot()
+
+	if url, ok := bridge.BugUrl(snap); ok {
+		return url, true
+	}
+
+	return webui.BugUrl(repo.Repository(), snap.Id())
+}
+
+// openInBrowser open the web page of a bug, or explain why there is none
+func openInBrowser(repo cache.RepoCacher, b cache.BugCacher) error {
+	url, ok := bugWebUrl(repo, b)
+	if !ok {
+		ui.msgPopup.Activate(msgPopupBrowserTitle,
+			"This bug has not been imported from another bug tracker and the web UI is not running.\n\nStart it with \"git bug webui\".")
+		return nil
+	}
+
+	if err := open.Start(url); err != nil {
+		ui.msgPopup.Activate(msgPopupErrorTitle, err.Error())
+	}
+
+	return nil
+}
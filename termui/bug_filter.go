@@ -0,0 +1,85 @@
+package termui
+
+import (
+	"strings"
+
+	"github.com/MichaelMure/git-bug/cache"
+)
+
+// bugFilter is the query filtering the bug table, as given to git bug ls.
+// It's typed after '/', the table being filtered as it's typed.
+type bugFilter struct {
+	// the query typed
+	query string
+	// the query is being typed
+	editing bool
+	// the query applied, nil without filter. While typing, the last valid
+	// query stay applied.
+	applied *cache.Query
+	// the reason the query typed is invalid, if so
+	err error
+}
+
+// start the edition of the query, from the one applied if any
+func (f *bugFilter) start() {
+	f.editing = true
+}
+
+// input add a character to the query, and apply it if valid
+func (f *bugFilter) input(ch rune) {
+	f.query += string(ch)
+	f.parse()
+}
+
+// backspace remove the last character of the query, and apply it if valid
+func (f *bugFilter) backspace() {
+	runes := []rune(f.query)
+	if len(runes) == 0 {
+		return
+	}
+
+	f.query = string(runes[:len(runes)-1])
+	f.parse()
+}
+
+// clear remove the filter, all the bugs being listed again
+func (f *bugFilter) clear() {
+	*f = bugFilter{}
+}
+
+// validate end the edition, the query staying applied. An invalid query is
+// kept in edition instead, and false is returned.
+func (f *bugFilter) validate() bool {
+	if f.err != nil {
+		return false
+	}
+
+	f.editing = false
+
+	if f.applied == nil {
+		f.clear()
+	}
+
+	return true
+}
+
+func (f *bugFilter) parse() {
+	query, err := cache.ParseQuery(f.query)
+	if err != nil {
+		f.err = err
+		return
+	}
+
+	f.err = nil
+	f.applied = query
+
+	// an empty query match every bug, like no filter
+	if strings.TrimSpace(f.query) == "" {
+		f.applied = nil
+	}
+}
+
+// active tell if the bugs are filtered
+func (f *bugFilter) active() bool {
+	return f.applied != nil
+}
@@ -0,0 +1,94 @@
+package termui
+
+import (
+	"testing"
+
+	"github.com/MichaelMure/git-bug/bug"
+)
+
+func typeQuery(f *bugFilter, query string) {
+	for _, ch := range query {
+		f.input(ch)
+	}
+}
+
+func TestBugFilterTyping(t *testing.T) {
+	var f bugFilter
+
+	f.start()
+	if !f.editing || f.active() {
+		t.Fatal("The filter should be edited, without being applied yet")
+	}
+
+	// incomplete while typing, nothing applied yet
+	typeQuery(&f, "status:")
+	if f.err == nil || f.active() {
+		t.Fatal("An invalid query should not be applied")
+	}
+
+	typeQuery(&f, "open")
+	if f.err != nil || !f.active() || len(f.applied.Status) != 1 || f.applied.Status[0] != bug.OpenStatus {
+		t.Fatalf("The query should be applied, got %+v %v", f.applied, f.err)
+	}
+
+	// the last valid query stay applied while typing the next term
+	typeQuery(&f, " label:")
+	if f.err == nil || !f.active() || len(f.applied.Labels) != 0 {
+		t.Fatal("The previous query should stay applied")
+	}
+
+	typeQuery(&f, "UI")
+	if f.err != nil || len(f.applied.Labels) != 1 || f.applied.Labels[0] != "UI" {
+		t.Fatalf("Unexpected applied query %+v", f.applied)
+	}
+
+	// backspace edit the query, and apply it again
+	for range "label:UI" {
+		f.backspace()
+	}
+	if f.query != "status:open " || f.err != nil || len(f.applied.Labels) != 0 {
+		t.Fatalf("Unexpected query %q, applied %+v", f.query, f.applied)
+	}
+}
+
+func TestBugFilterValidate(t *testing.T) {
+	var f bugFilter
+
+	f.start()
+	typeQuery(&f, "status:closed")
+
+	if !f.validate() || f.editing || !f.active() || f.query != "status:closed" {
+		t.Fatal("Enter should keep the filter applied")
+	}
+
+	// edited again from the applied query
+	f.start()
+	typeQuery(&f, " foo:bar")
+	if f.validate() || !f.editing {
+		t.Fatal("An invalid query should stay in edition")
+	}
+
+	// emptied with backspace
+	for range "status:closed foo:bar" {
+		f.backspace()
+	}
+	f.backspace()
+	if f.query != "" || f.active() || f.err != nil {
+		t.Fatalf("Unexpected emptied filter %+v", f)
+	}
+	if !f.validate() || f.editing || f.active() {
+		t.Fatal("An empty query should be no filter")
+	}
+}
+
+func TestBugFilterClear(t *testing.T) {
+	var f bugFilter
+
+	f.start()
+	typeQuery(&f, "status:open foo:")
+	f.clear()
+
+	if f.editing || f.active() || f.query != "" || f.err != nil {
+		t.Fatalf("Esc should restore the full list, got %+v", f)
+	}
+}
@@ -3,20 +3,21 @@ package termui
 import (
 	"bytes"
 	"fmt"
+	"time"
 
 	"github.com/MichaelMure/git-bug/bug"
 	"github.com/MichaelMure/git-bug/cache"
 	"github.com/MichaelMure/git-bug/util"
 	"github.com/dustin/go-humanize"
 	"github.com/jroimartin/gocui"
+	"github.com/mattn/go-runewidth"
 )
 
 const bugTableView = "bugTableView"
 const bugTableHeaderView = "bugTableHeaderView"
 const bugTableFooterView = "bugTableFooterView"
 const bugTableInstructionView = "bugTableInstructionView"
-
-const remote = "origin"
+const bugTableSearchView = "bugTableSearchView"
 
 type bugTable struct {
 	repo         cache.RepoCacher
@@ -24,13 +25,48 @@ type bugTable struct {
 	bugs         []cache.BugCacher
 	pageCursor   int
 	selectCursor int
+	// time of the last click, to detect double-clicks
+	lastClick time.Time
+	// the remote to pull from and push to
+	remote string
+	// only list the bugs opened or edited by the user
+	mine bool
+	// only list the bugs matching a query
+	filter bugFilter
+	// the bugs are read again in the background, see refresh
+	refreshing     bool
+	refreshStarted time.Time
+
+	// show the selected bug next to the table, on wide enough terminals
+	split bool
+	// the preview is displayed, and has the focus
+	previewShown   bool
+	previewFocused bool
+	// the bug to preview once the selection settle, see schedulePreview
+	previewBug   cache.BugCacher
+	previewTimer *time.Timer
 }
 
-func newBugTable(cache cache.RepoCacher) *bugTable {
+// the frames of the spinner shown while refreshing
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+const spinnerInterval = 100 * time.Millisecond
+
+// Under this width, the preview is collapsed and the table take the whole
+// terminal
+const splitMinWidth = 120
+
+// The preview only follow the selection once it stayed on a bug for this
+// delay, to not render every bug when scrolling through the table
+const previewDelay = 150 * time.Millisecond
+
+func newBugTable(cache cache.RepoCacher, remote string) *bugTable {
 	return &bugTable{
 		repo:         cache,
 		pageCursor:   0,
 		selectCursor: 0,
+		remote:       remote,
+		split:        true,
 	}
 }
 
@@ -42,7 +78,14 @@ func (bt *bugTable) layout(g *gocui.Gui) error {
 		return nil
 	}
 
-	v, err := g.SetView(bugTableHeaderView, -1, -1, maxX, 3)
+	// the width of the table, the preview taking the rest
+	tableX := maxX
+	split := bt.split && maxX >= splitMinWidth
+	if split {
+		tableX = maxX * 11 / 20
+	}
+
+	v, err := g.SetView(bugTableHeaderView, -1, -1, tableX, 3)
 
 	if err != nil {
 		if err != gocui.ErrUnknownView {
@@ -53,9 +96,9 @@ func (bt *bugTable) layout(g *gocui.Gui) error {
 	}
 
 	v.Clear()
-	bt.renderHeader(v, maxX)
+	bt.renderHeader(v, tableX)
 
-	v, err = g.SetView(bugTableView, -1, 1, maxX, maxY-3)
+	v, err = g.SetView(bugTableView, -1, 1, tableX, maxY-3)
 
 	if err != nil {
 		if err != gocui.ErrUnknownView {
@@ -63,7 +106,6 @@ func (bt *bugTable) layout(g *gocui.Gui) error {
 		}
 
 		v.Frame = false
-		v.Highlight = true
 		v.SelBgColor = gocui.ColorWhite
 		v.SelFgColor = gocui.ColorBlack
 
@@ -72,6 +114,9 @@ func (bt *bugTable) layout(g *gocui.Gui) error {
 		_ = v.SetCursor(0, bt.selectCursor)
 	}
 
+	// the selection is only highlighted where the focus is
+	v.Highlight = !bt.previewFocused
+
 	_, viewHeight := v.Size()
 	err = bt.paginate(viewHeight)
 	if err != nil {
@@ -84,9 +129,9 @@ func (bt *bugTable) layout(g *gocui.Gui) error {
 	}
 
 	v.Clear()
-	bt.render(v, maxX)
+	bt.render(v, tableX)
 
-	v, err = g.SetView(bugTableFooterView, -1, maxY-4, maxX, maxY)
+	v, err = g.SetView(bugTableFooterView, -1, maxY-4, tableX, maxY)
 
 	if err != nil {
 		if err != gocui.ErrUnknownView {
@@ -97,7 +142,11 @@ func (bt *bugTable) layout(g *gocui.Gui) error {
 	}
 
 	v.Clear()
-	bt.renderFooter(v, maxX)
+	bt.renderFooter(v, tableX)
+
+	if err := bt.layoutSearch(g, tableX, maxY); err != nil {
+		return err
+	}
 
 	v, err = g.SetView(bugTableInstructionView, -1, maxY-2, maxX, maxY)
 
@@ -108,94 +157,239 @@ func (bt *bugTable) layout(g *gocui.Gui) error {
 
 		v.Frame = false
 		v.BgColor = gocui.ColorBlue
+	}
 
-		fmt.Fprintf(v, "[q] Quit [←↓↑→,hjkl] Navigation [enter] Open bug [n] New bug [i] Pull [o] Push")
+	if split {
+		err = bt.layoutPreview(g, tableX+1, maxX, maxY-2)
+	} else {
+		err = bt.collapsePreview(g)
+	}
+	if err != nil {
+		return err
 	}
 
-	_, err = g.SetCurrentView(bugTableView)
+	v.Clear()
+	renderButtons(v, bt.focusedButtons())
+
+	// the long bugs of the preview go below the instruction bar
+	if _, err := g.SetViewOnTop(bugTableInstructionView); err != nil {
+		return err
+	}
+
+	switch {
+	case bt.filter.editing:
+		_, err = g.SetCurrentView(bugTableSearchView)
+	case bt.previewFocused:
+		_, err = g.SetCurrentView(showBugView)
+	default:
+		_, err = g.SetCurrentView(bugTableView)
+	}
 	return err
 }
 
-func (bt *bugTable) keybindings(g *gocui.Gui) error {
-	// Quit
-	if err := g.SetKeybinding(bugTableView, 'q', gocui.ModNone, quit); err != nil {
-		return err
+// layoutSearch display the query filtering the table, above the footer,
+// while it's typed or applied
+func (bt *bugTable) layoutSearch(g *gocui.Gui, maxX, maxY int) error {
+	if !bt.filter.editing && !bt.filter.active() {
+		if err := g.DeleteView(bugTableSearchView); err != nil && err != gocui.ErrUnknownView {
+			return err
+		}
+		return nil
 	}
 
-	// Down
-	if err := g.SetKeybinding(bugTableView, 'j', gocui.ModNone,
-		bt.cursorDown); err != nil {
-		return err
+	v, err := g.SetView(bugTableSearchView, -1, maxY-4, maxX, maxY-2)
+
+	if err != nil {
+		if err != gocui.ErrUnknownView {
+			return err
+		}
+
+		v.Frame = false
+		v.Editable = true
+		v.Editor = gocui.EditorFunc(bt.editSearch)
 	}
-	if err := g.SetKeybinding(bugTableView, gocui.KeyArrowDown, gocui.ModNone,
-		bt.cursorDown); err != nil {
-		return err
+
+	v.Clear()
+	fmt.Fprintf(v, " /%s", bt.filter.query)
+	if bt.filter.err != nil {
+		fmt.Fprintf(v, "  %s", util.Red(bt.filter.err.Error()))
 	}
-	// Up
-	if err := g.SetKeybinding(bugTableView, 'k', gocui.ModNone,
-		bt.cursorUp); err != nil {
-		return err
+
+	if bt.filter.editing {
+		g.Cursor = true
+		// window is too small to set the cursor properly, ignoring the error
+		_ = v.SetCursor(2+runewidth.StringWidth(bt.filter.query), 0)
 	}
-	if err := g.SetKeybinding(bugTableView, gocui.KeyArrowUp, gocui.ModNone,
-		bt.cursorUp); err != nil {
+
+	_, err = g.SetViewOnTop(bugTableSearchView)
+	return err
+}
+
+// layoutPreview display the selected bug in the given area, at the right of
+// the table
+func (bt *bugTable) layoutPreview(g *gocui.Gui, x0, x1, y1 int) error {
+	if err := bt.schedulePreview(g); err != nil {
 		return err
 	}
 
-	// Previous page
-	if err := g.SetKeybinding(bugTableView, 'h', gocui.ModNone,
-		bt.previousPage); err != nil {
-		return err
+	bt.previewShown = ui.showBug.bug != nil
+	if !bt.previewShown {
+		return nil
 	}
-	if err := g.SetKeybinding(bugTableView, gocui.KeyArrowLeft, gocui.ModNone,
-		bt.previousPage); err != nil {
-		return err
+
+	return ui.showBug.layoutIn(g, x0, x1, y1)
+}
+
+// collapsePreview remove the preview, when toggled off or when the terminal
+// became too narrow
+func (bt *bugTable) collapsePreview(g *gocui.Gui) error {
+	bt.previewFocused = false
+	bt.previewBug = nil
+
+	if bt.previewTimer != nil {
+		bt.previewTimer.Stop()
 	}
-	if err := g.SetKeybinding(bugTableView, gocui.KeyPgup, gocui.ModNone,
-		bt.previousPage); err != nil {
-		return err
+
+	if !bt.previewShown {
+		return nil
 	}
-	// Next page
-	if err := g.SetKeybinding(bugTableView, 'l', gocui.ModNone,
-		bt.nextPage); err != nil {
-		return err
+
+	bt.previewShown = false
+	return ui.showBug.disable(g)
+}
+
+// schedulePreview preview the selected bug once the selection stayed on it
+// for previewDelay. The first preview is shown right away.
+//
+// The bugs of the table are the ones of the cache, so previewing one reuse
+// its snapshot rather than compiling it again.
+func (bt *bugTable) schedulePreview(g *gocui.Gui) error {
+	selected := bt.selectedBug()
+	if selected == nil || selected == bt.previewBug {
+		return nil
 	}
-	if err := g.SetKeybinding(bugTableView, gocui.KeyArrowRight, gocui.ModNone,
-		bt.nextPage); err != nil {
-		return err
+
+	first := bt.previewBug == nil
+	bt.previewBug = selected
+
+	if bt.previewTimer != nil {
+		bt.previewTimer.Stop()
 	}
-	if err := g.SetKeybinding(bugTableView, gocui.KeyPgdn, gocui.ModNone,
-		bt.nextPage); err != nil {
-		return err
+
+	if first {
+		return bt.preview(g, selected)
 	}
 
-	// New bug
-	if err := g.SetKeybinding(bugTableView, 'n', gocui.ModNone,
-		bt.newBug); err != nil {
+	bt.previewTimer = time.AfterFunc(previewDelay, func() {
+		ui.update(func(g *gocui.Gui) error {
+			// the selection moved meanwhile, or the bug was opened
+			if bt.previewBug != selected || ui.activeWindow != bt {
+				return nil
+			}
+
+			return bt.preview(g, selected)
+		})
+	})
+
+	return nil
+}
+
+func (bt *bugTable) preview(g *gocui.Gui, b cache.BugCacher) error {
+	// the views of the previous bug, which might have more operations
+	if err := ui.showBug.disable(g); err != nil {
 		return err
 	}
 
-	// Open bug
-	if err := g.SetKeybinding(bugTableView, gocui.KeyEnter, gocui.ModNone,
-		bt.openBug); err != nil {
-		return err
+	ui.showBug.SetBug(b)
+	return nil
+}
+
+func (bt *bugTable) selectedBug() cache.BugCacher {
+	if bt.selectCursor >= len(bt.bugs) {
+		return nil
 	}
 
-	// Pull
-	if err := g.SetKeybinding(bugTableView, 'i', gocui.ModNone,
-		bt.pull); err != nil {
-		return err
+	return bt.bugs[bt.selectCursor]
+}
+
+// focusedButtons return the buttons of the table or of the preview,
+// depending on the focus
+func (bt *bugTable) focusedButtons() []button {
+	if bt.filter.editing {
+		return []button{
+			{ui.keybindings.shortcut(bugTableSearchView, "validate"), "Keep the filter", bt.validateSearch},
+			{ui.keybindings.shortcut(bugTableSearchView, "cancel"), "Clear the filter", bt.clearSearch},
+		}
 	}
 
-	// Push
-	if err := g.SetKeybinding(bugTableView, 'o', gocui.ModNone,
-		bt.push); err != nil {
+	if bt.previewFocused {
+		return ui.showBug.buttons()
+	}
+
+	return bt.buttons()
+}
+
+func (bt *bugTable) buttons() []button {
+	key := func(action string) string {
+		return ui.keybindings.shortcut(bugTableView, action)
+	}
+
+	buttons := []button{
+		{key("quit"), "Quit", quit},
+		{"←↓↑→,hjkl", "Navigation", nil},
+		{key("open"), "Open bug", bt.openBug},
+		{key("browse"), "Web page", bt.openInBrowser},
+		{key("new"), "New bug", bt.newBug},
+		{key("pull"), "Pull", bt.pull},
+		{key("push"), "Push", bt.push},
+		{key("mine"), "My bugs", bt.toggleMine},
+		{key("mouse"), "Mouse", toggleMouse},
+		{key("preview"), "Preview", bt.toggleSplit},
+		{key("search"), "Search", bt.startSearch},
+	}
+
+	if bt.previewShown {
+		buttons = append(buttons, button{key("focus"), "Focus preview", bt.switchFocus})
+	}
+
+	return buttons
+}
+
+func (bt *bugTable) keybindings(r *keybindingRegistry) error {
+	err := r.register(bugTableView, []keybinding{
+		{"quit", keys('q'), "Quit", quit},
+		{"down", keys('j', gocui.KeyArrowDown), "Select the next bug", bt.cursorDown},
+		{"up", keys('k', gocui.KeyArrowUp), "Select the previous bug", bt.cursorUp},
+		{"previous-page", keys('h', gocui.KeyArrowLeft, gocui.KeyPgup), "Previous page", bt.previousPage},
+		{"next-page", keys('l', gocui.KeyArrowRight, gocui.KeyPgdn), "Next page", bt.nextPage},
+		{"open", keys(gocui.KeyEnter), "Open the selected bug", bt.openBug},
+		{"new", keys('n'), "New bug", bt.newBug},
+		{"browse", keys('w'), "Open the web page of the bug", bt.openInBrowser},
+		{"pull", keys('i'), "Pull from the remote", bt.pull},
+		{"push", keys('o'), "Push to the remote", bt.push},
+		{"mine", keys('u'), "Only list my bugs", bt.toggleMine},
+		{"preview", keys('p'), "Toggle the preview of the selected bug", bt.toggleSplit},
+		{"focus", keys(gocui.KeyTab), "Focus the preview", bt.switchFocus},
+		{"mouse", keys('m'), "Toggle the mouse support", toggleMouse},
+		{"help", keys('?'), "Show the keybindings", showHelp},
+		{"search", keys('/'), "Filter the bugs with a query, as git bug ls", bt.startSearch},
+		{"clear-filter", keys(gocui.KeyEsc), "Clear the filter", bt.clearSearch},
+	}...)
+	if err != nil {
 		return err
 	}
 
-	return nil
+	// the other keys are typed in the query, see editSearch
+	return r.register(bugTableSearchView, []keybinding{
+		{"cancel", keys(gocui.KeyEsc), "Clear the filter", bt.clearSearch},
+		{"validate", keys(gocui.KeyEnter), "Keep the filter", bt.validateSearch},
+	}...)
 }
 
 func (bt *bugTable) disable(g *gocui.Gui) error {
+	if err := bt.collapsePreview(g); err != nil {
+		return err
+	}
 	if err := g.DeleteView(bugTableView); err != nil && err != gocui.ErrUnknownView {
 		return err
 	}
@@ -208,11 +402,50 @@ func (bt *bugTable) disable(g *gocui.Gui) error {
 	if err := g.DeleteView(bugTableInstructionView); err != nil && err != gocui.ErrUnknownView {
 		return err
 	}
+	if err := g.DeleteView(bugTableSearchView); err != nil && err != gocui.ErrUnknownView {
+		return err
+	}
 	return nil
 }
 
+// listIds return the ids of the bugs to display, only the ones matching the
+// filter and the ones of the user if toggled
+func (bt *bugTable) listIds() ([]string, error) {
+	if !bt.mine && !bt.filter.active() {
+		return bt.repo.AllBugIds()
+	}
+
+	query := &cache.Query{}
+	if bt.filter.active() {
+		// a copy, to not keep the mine filter in the one typed
+		copied := *bt.filter.applied
+		query = &copied
+	}
+	if bt.mine {
+		query.Mine = cache.MineAll
+	}
+
+	if err := query.ResolveMe(bt.repo); err != nil {
+		return nil, err
+	}
+
+	snaps, err := bt.repo.Search(query.Match)
+	if err != nil {
+		return nil, err
+	}
+
+	query.Sort(snaps)
+
+	ids := make([]string, len(snaps))
+	for i, snap := range snaps {
+		ids[i] = snap.Id()
+	}
+
+	return ids, nil
+}
+
 func (bt *bugTable) paginate(max int) error {
-	allIds, err := bt.repo.AllBugIds()
+	allIds, err := bt.listIds()
 	if err != nil {
 		return err
 	}
@@ -240,6 +473,13 @@ func (bt *bugTable) doPaginate(allIds []string, max int) error {
 	bt.bugs = make([]cache.BugCacher, len(ids))
 
 	for i, id := range ids {
+		// while refreshing, the bugs not cached yet are shown once read
+		// rather than blocking the layout
+		if bt.refreshing {
+			bt.bugs[i], _ = bt.repo.CachedBug(id)
+			continue
+		}
+
 		b, err := bt.repo.ResolveBug(id)
 		if err != nil {
 			return err
@@ -259,8 +499,9 @@ func (bt *bugTable) getColumnWidths(maxX int) map[string]int {
 	m := make(map[string]int)
 	m["id"] = 10
 	m["status"] = 8
+	m["checks"] = 7
 
-	left := maxX - 5 - m["id"] - m["status"]
+	left := maxX - 6 - m["id"] - m["status"] - m["checks"]
 
 	m["summary"] = maxInt(11, left/6)
 	left -= m["summary"]
@@ -277,7 +518,13 @@ func (bt *bugTable) getColumnWidths(maxX int) map[string]int {
 func (bt *bugTable) render(v *gocui.View, maxX int) {
 	columnWidths := bt.getColumnWidths(maxX)
 
-	for _, b := range bt.bugs {
+	for i, b := range bt.bugs {
+		if b == nil {
+			id := util.LeftPaddedString(bt.repo.HumanId(bt.allIds[bt.pageCursor+i]), columnWidths["id"], 2)
+			fmt.Fprintf(v, "%s loading...\n", util.Cyan(id))
+			continue
+		}
+
 		person := bug.Person{}
 		snap := b.Snapshot()
 		if len(snap.Comments) > 0 {
@@ -290,19 +537,32 @@ func (bt *bugTable) render(v *gocui.View, maxX int) {
 		title := util.LeftPaddedString(snap.Title, columnWidths["title"], 2)
 		author := util.LeftPaddedString(person.Name, columnWidths["author"], 2)
 		summary := util.LeftPaddedString(snap.Summary(), columnWidths["summary"], 2)
+		checks := util.LeftPaddedString(checklistRatio(snap), columnWidths["checks"], 2)
 		lastEdit := util.LeftPaddedString(humanize.Time(snap.LastEdit()), columnWidths["lastEdit"], 2)
 
-		fmt.Fprintf(v, "%s %s %s %s %s %s\n",
+		fmt.Fprintf(v, "%s %s %s %s %s %s %s\n",
 			util.Cyan(id),
 			util.Yellow(status),
 			title,
 			util.Magenta(author),
 			summary,
+			checks,
 			lastEdit,
 		)
 	}
 }
 
+// checklistRatio is the completion of the checklists of a bug, empty without
+// any checklist
+func checklistRatio(snap *bug.Snapshot) string {
+	if len(snap.Checklists) == 0 {
+		return ""
+	}
+
+	done, total := snap.ChecklistCompletion()
+	return fmt.Sprintf("%d/%d", done, total)
+}
+
 func (bt *bugTable) renderHeader(v *gocui.View, maxX int) {
 	columnWidths := bt.getColumnWidths(maxX)
 
@@ -311,20 +571,78 @@ func (bt *bugTable) renderHeader(v *gocui.View, maxX int) {
 	title := util.LeftPaddedString("TITLE", columnWidths["title"], 2)
 	author := util.LeftPaddedString("AUTHOR", columnWidths["author"], 2)
 	summary := util.LeftPaddedString("SUMMARY", columnWidths["summary"], 2)
+	checks := util.LeftPaddedString("CHECKS", columnWidths["checks"], 2)
 	lastEdit := util.LeftPaddedString("LAST EDIT", columnWidths["lastEdit"], 2)
 
 	fmt.Fprintf(v, "\n")
-	fmt.Fprintf(v, "%s %s %s %s %s %s\n", id, status, title, author, summary, lastEdit)
+	fmt.Fprintf(v, "%s %s %s %s %s %s %s\n", id, status, title, author, summary, checks, lastEdit)
 
 }
 
 func (bt *bugTable) renderFooter(v *gocui.View, maxX int) {
-	fmt.Fprintf(v, " \nShowing %d of %d bugs", len(bt.bugs), len(bt.allIds))
+	which := "bugs"
+	if bt.filter.active() {
+		which = "matching bugs"
+	}
+	if bt.mine {
+		which = "of my " + which
+	}
+
+	fmt.Fprintf(v, " \nShowing %d of %d %s, syncing with %s", len(bt.bugs), len(bt.allIds), which, bt.remote)
+
+	if bt.refreshing {
+		frame := int(time.Since(bt.refreshStarted)/spinnerInterval) % len(spinnerFrames)
+		fmt.Fprintf(v, "  %s refreshing", spinnerFrames[frame])
+	}
+}
+
+// refresh read in the background the bugs whose ref moved and the ones not
+// cached yet, while the table is rendered from the cache. The cache is only
+// updated from the main loop, to not race with the layout.
+func (bt *bugTable) refresh() {
+	if bt.refreshing {
+		return
+	}
+
+	bt.refreshing = true
+	bt.refreshStarted = time.Now()
+
+	done := make(chan struct{})
+
+	go func() {
+		ticker := time.NewTicker(spinnerInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				ui.redraw()
+			}
+		}
+	}()
+
+	go func() {
+		r, err := bt.repo.ReadRefresh()
+		close(done)
+
+		ui.update(func(g *gocui.Gui) error {
+			bt.refreshing = false
+
+			if err != nil {
+				ui.msgPopup.Activate(msgPopupErrorTitle, err.Error())
+				return nil
+			}
+
+			r.Apply()
+			return nil
+		})
+	}()
 }
 
 func (bt *bugTable) cursorDown(g *gocui.Gui, v *gocui.View) error {
-	_, y := v.Cursor()
-	y = minInt(y+1, bt.getTableLength()-1)
+	y := minInt(bt.selectCursor+1, bt.getTableLength()-1)
 
 	// window is too small to set the cursor properly, ignoring the error
 	_ = v.SetCursor(0, y)
@@ -334,8 +652,7 @@ func (bt *bugTable) cursorDown(g *gocui.Gui, v *gocui.View) error {
 }
 
 func (bt *bugTable) cursorUp(g *gocui.Gui, v *gocui.View) error {
-	_, y := v.Cursor()
-	y = maxInt(y-1, 0)
+	y := maxInt(bt.selectCursor-1, 0)
 
 	// window is too small to set the cursor properly, ignoring the error
 	_ = v.SetCursor(0, y)
@@ -345,9 +662,9 @@ func (bt *bugTable) cursorUp(g *gocui.Gui, v *gocui.View) error {
 }
 
 func (bt *bugTable) cursorClamp(v *gocui.View) error {
-	_, y := v.Cursor()
-
-	y = minInt(y, bt.getTableLength()-1)
+	// the mouse events move the cursor of the view, the selection is kept
+	// in selectCursor instead
+	y := minInt(bt.selectCursor, bt.getTableLength()-1)
 	y = maxInt(y, 0)
 
 	// window is too small to set the cursor properly, ignoring the error
@@ -360,7 +677,7 @@ func (bt *bugTable) cursorClamp(v *gocui.View) error {
 func (bt *bugTable) nextPage(g *gocui.Gui, v *gocui.View) error {
 	_, max := v.Size()
 
-	allIds, err := bt.repo.AllBugIds()
+	allIds, err := bt.listIds()
 	if err != nil {
 		return err
 	}
@@ -378,7 +695,7 @@ func (bt *bugTable) nextPage(g *gocui.Gui, v *gocui.View) error {
 
 func (bt *bugTable) previousPage(g *gocui.Gui, v *gocui.View) error {
 	_, max := v.Size()
-	allIds, err := bt.repo.AllBugIds()
+	allIds, err := bt.listIds()
 	if err != nil {
 		return err
 	}
@@ -390,19 +707,184 @@ func (bt *bugTable) previousPage(g *gocui.Gui, v *gocui.View) error {
 	return bt.doPaginate(allIds, max)
 }
 
+func (bt *bugTable) toggleMine(g *gocui.Gui, v *gocui.View) error {
+	bt.mine = !bt.mine
+	bt.pageCursor = 0
+	bt.selectCursor = 0
+
+	return nil
+}
+
+func (bt *bugTable) startSearch(g *gocui.Gui, v *gocui.View) error {
+	bt.filter.start()
+	bt.previewFocused = false
+
+	// focus the query right away, as gocui handle the keys typed meanwhile
+	// before the next layout
+	return bt.layout(g)
+}
+
+// editSearch type the keys in the query, the table being filtered right
+// away
+func (bt *bugTable) editSearch(v *gocui.View, key gocui.Key, ch rune, mod gocui.Modifier) {
+	switch {
+	case key == gocui.KeyBackspace || key == gocui.KeyBackspace2:
+		bt.filter.backspace()
+	case key == gocui.KeySpace:
+		bt.filter.input(' ')
+	case ch != 0 && mod == gocui.ModNone:
+		bt.filter.input(ch)
+	default:
+		return
+	}
+
+	// the matching bugs are shown from the first
+	bt.pageCursor = 0
+	bt.selectCursor = 0
+}
+
+func (bt *bugTable) clearSearch(g *gocui.Gui, v *gocui.View) error {
+	if !bt.filter.editing && !bt.filter.active() {
+		return nil
+	}
+
+	bt.filter.clear()
+	bt.pageCursor = 0
+	bt.selectCursor = 0
+	return nil
+}
+
+func (bt *bugTable) validateSearch(g *gocui.Gui, v *gocui.View) error {
+	// an invalid query stay in edition, with its error
+	bt.filter.validate()
+	return nil
+}
+
+func (bt *bugTable) toggleSplit(g *gocui.Gui, v *gocui.View) error {
+	bt.split = !bt.split
+	return nil
+}
+
+// switchFocus move the focus between the table and the preview, where the
+// keybindings of the bug view are available
+func (bt *bugTable) switchFocus(g *gocui.Gui, v *gocui.View) error {
+	// Tab on the bug opened in full
+	if ui.activeWindow != bt {
+		return nil
+	}
+
+	bt.previewFocused = !bt.previewFocused && bt.previewShown
+	return nil
+}
+
 func (bt *bugTable) newBug(g *gocui.Gui, v *gocui.View) error {
 	return newBugWithEditor(bt.repo)
 }
 
 func (bt *bugTable) openBug(g *gocui.Gui, v *gocui.View) error {
-	_, y := v.Cursor()
-	ui.showBug.SetBug(bt.bugs[y])
+	if bt.selectCursor >= len(bt.bugs) || bt.bugs[bt.selectCursor] == nil {
+		return nil
+	}
+
+	ui.showBug.SetBug(bt.bugs[bt.selectCursor])
 	return ui.activateWindow(ui.showBug)
 }
 
+func (bt *bugTable) openInBrowser(g *gocui.Gui, v *gocui.View) error {
+	if bt.selectCursor >= len(bt.bugs) || bt.bugs[bt.selectCursor] == nil {
+		return nil
+	}
+
+	return openInBrowser(bt.repo, bt.bugs[bt.selectCursor])
+}
+
+func (bt *bugTable) click(g *gocui.Gui, v *gocui.View) error {
+	switch v.Name() {
+	case bugTableView:
+		// gocui already moved the cursor under the pointer
+		_, y := v.Cursor()
+		if y >= bt.getTableLength() {
+			return nil
+		}
+
+		now := time.Now()
+		double := y == bt.selectCursor && now.Sub(bt.lastClick) < doubleClickDelay
+
+		bt.selectCursor = y
+		bt.lastClick = now
+		bt.previewFocused = false
+
+		if double {
+			return bt.openBug(g, v)
+		}
+
+	case bugTableSearchView:
+		return bt.startSearch(g, v)
+
+	case bugTableInstructionView:
+		x, _ := v.Cursor()
+		if b, ok := buttonAt(bt.focusedButtons(), x); ok {
+			return b.action(g, v)
+		}
+
+	default:
+		if bt.previewShown {
+			bt.previewFocused = true
+			return ui.showBug.click(g, v)
+		}
+	}
+
+	return nil
+}
+
+func (bt *bugTable) wheelUp(g *gocui.Gui) error {
+	if bt.previewFocused {
+		return ui.showBug.wheelUp(g)
+	}
+
+	v, err := g.View(bugTableView)
+	if err != nil {
+		return err
+	}
+
+	if bt.selectCursor == 0 && bt.pageCursor > 0 {
+		err = bt.previousPage(g, v)
+		bt.selectCursor = bt.getTableLength() - 1
+		return err
+	}
+
+	return bt.cursorUp(g, v)
+}
+
+func (bt *bugTable) wheelDown(g *gocui.Gui) error {
+	if bt.previewFocused {
+		return ui.showBug.wheelDown(g)
+	}
+
+	v, err := g.View(bugTableView)
+	if err != nil {
+		return err
+	}
+
+	if bt.selectCursor == bt.getTableLength()-1 && bt.pageCursor+bt.getTableLength() < len(bt.allIds) {
+		err = bt.nextPage(g, v)
+		bt.selectCursor = 0
+		return err
+	}
+
+	return bt.cursorDown(g, v)
+}
+
 func (bt *bugTable) pull(g *gocui.Gui, v *gocui.View) error {
 	// Note: this is very hacky
 
+	remote := bt.remote
+
+	if err := bug.CheckRemote(bt.repo.Repository(), remote); err != nil {
+		ui.msgPopup.Activate(msgPopupErrorTitle, err.Error())
+		return nil
+	}
+
 	ui.msgPopup.Activate("Pull from remote "+remote, "...")
 
 	go func() {
@@ -451,6 +933,8 @@ func (bt *bugTable) pull(g *gocui.Gui, v *gocui.View) error {
 
 		g.Update(func(gui *gocui.Gui) error {
 			ui.msgPopup.UpdateMessage(buffer.String())
+			// the merged bugs moved under the cache
+			bt.refresh()
 			return nil
 		})
 
@@ -460,10 +944,16 @@ func (bt *bugTable) pull(g *gocui.Gui, v *gocui.View) error {
 }
 
 func (bt *bugTable) push(g *gocui.Gui, v *gocui.View) error {
+	remote := bt.remote
+
+	if err := bug.CheckRemote(bt.repo.Repository(), remote); err != nil {
+		ui.msgPopup.Activate(msgPopupErrorTitle, err.Error())
+		return nil
+	}
+
 	ui.msgPopup.Activate("Push to remote "+remote, "...")
 
 	go func() {
-		// TODO: make the remote configurable
 		stdout, err := bt.repo.Push(remote)
 
 		if err != nil {
@@ -0,0 +1,99 @@
+package termui
+
+import (
+	"fmt"
+
+	"github.com/MichaelMure/git-bug/util"
+	"github.com/jroimartin/gocui"
+)
+
+const confirmPopupView = "confirmPopupView"
+
+// confirmPopup ask a yes/no question, and run an action if the answer is yes
+type confirmPopup struct {
+	active   bool
+	title    string
+	question string
+	onYes    func(g *gocui.Gui) error
+}
+
+func newConfirmPopup() *confirmPopup {
+	return &confirmPopup{}
+}
+
+func (cp *confirmPopup) keybindings(r *keybindingRegistry) error {
+	return r.register(confirmPopupView, []keybinding{
+		{"yes", keys('y', gocui.KeyEnter), "Yes", cp.yes},
+		{"no", keys('n', 'q', gocui.KeyEsc), "No", cp.no},
+	}...)
+}
+
+func (cp *confirmPopup) layout(g *gocui.Gui) error {
+	if !cp.active {
+		return nil
+	}
+
+	maxX, maxY := g.Size()
+
+	message := fmt.Sprintf("%s\n\n[y] Yes [n] No", cp.question)
+
+	width := minInt(50, maxX)
+	wrapped, lines := util.TextWrap(message, width-2)
+	height := minInt(lines+1, maxY-3)
+	x0 := (maxX - width) / 2
+	y0 := (maxY - height) / 2
+
+	v, err := g.SetView(confirmPopupView, x0, y0, x0+width, y0+height)
+	if err != nil {
+		if err != gocui.ErrUnknownView {
+			return err
+		}
+
+		v.Frame = true
+	}
+
+	v.Title = cp.title
+
+	v.Clear()
+	fmt.Fprint(v, wrapped)
+
+	if _, err := g.SetCurrentView(confirmPopupView); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (cp *confirmPopup) close(g *gocui.Gui) error {
+	cp.active = false
+	cp.title = ""
+	cp.question = ""
+	cp.onYes = nil
+	return g.DeleteView(confirmPopupView)
+}
+
+func (cp *confirmPopup) yes(g *gocui.Gui, v *gocui.View) error {
+	onYes := cp.onYes
+
+	if err := cp.close(g); err != nil {
+		return err
+	}
+
+	if onYes != nil {
+		return onYes(g)
+	}
+
+	return nil
+}
+
+func (cp *confirmPopup) no(g *gocui.Gui, v *gocui.View) error {
+	return cp.close(g)
+}
+
+// Activate show the question, onYes is run if the user confirm
+func (cp *confirmPopup) Activate(title string, question string, onYes func(g *gocui.Gui) error) {
+	cp.active = true
+	cp.title = title
+	cp.question = question
+	cp.onYes = onYes
+}
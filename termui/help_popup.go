@@ -0,0 +1,134 @@
+package termui
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/MichaelMure/git-bug/util"
+	"github.com/jroimartin/gocui"
+	"github.com/mattn/go-runewidth"
+)
+
+const helpPopupView = "helpPopupView"
+
+// helpPopup list the keybindings of the view having the focus, until any
+// key is pressed
+type helpPopup struct {
+	active bool
+	// the view to list the keybindings of
+	view string
+}
+
+func newHelpPopup() *helpPopup {
+	return &helpPopup{}
+}
+
+func (hp *helpPopup) layout(g *gocui.Gui) error {
+	if !hp.active {
+		return nil
+	}
+
+	maxX, maxY := g.Size()
+
+	return hp.layoutSized(g, maxX, maxY)
+}
+
+func (hp *helpPopup) layoutSized(g *gocui.Gui, maxX, maxY int) error {
+	content, width, lines := renderHelp(hp.keybindings())
+
+	width = minInt(width+2, maxX)
+	height := minInt(lines+1, maxY-3)
+	x0 := (maxX - width) / 2
+	y0 := (maxY - height) / 2
+
+	v, err := g.SetView(helpPopupView, x0, y0, x0+width, y0+height)
+	if err != nil {
+		if err != gocui.ErrUnknownView {
+			return err
+		}
+
+		v.Frame = true
+		v.Title = "Keybindings"
+
+		// the keys that aren't bound to the view are given to its editor,
+		// so that any of them close the popup
+		v.Editable = true
+		v.Editor = gocui.EditorFunc(func(v *gocui.View, key gocui.Key, ch rune, mod gocui.Modifier) {
+			_ = hp.close(g, v)
+		})
+	}
+
+	v.Clear()
+	fmt.Fprint(v, content)
+
+	if _, err := g.SetCurrentView(helpPopupView); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (hp *helpPopup) close(g *gocui.Gui, v *gocui.View) error {
+	hp.active = false
+	return g.DeleteView(helpPopupView)
+}
+
+func (hp *helpPopup) Activate(view string) {
+	hp.active = true
+	hp.view = view
+}
+
+func showHelp(g *gocui.Gui, v *gocui.View) error {
+	ui.helpPopup.Activate(v.Name())
+	return nil
+}
+
+// keybindings return the keybindings of the view, then the ones available
+// in every view. The ones without description, like the mouse events, are
+// left out.
+func (hp *helpPopup) keybindings() []keybinding {
+	var result []keybinding
+
+	for _, view := range []string{hp.view, ""} {
+		for _, binding := range ui.keybindings.forView(view) {
+			if binding.help != "" {
+				result = append(result, binding)
+			}
+		}
+	}
+
+	return result
+}
+
+// renderHelp render the keybindings in two columns, the keys then their
+// description, and return the width and height of the result
+func renderHelp(bindings []keybinding) (string, int, int) {
+	names := make([]string, len(bindings))
+	keysWidth := 0
+
+	for i, binding := range bindings {
+		keyNames := make([]string, len(binding.keys))
+		for j, key := range binding.keys {
+			keyNames[j] = keyName(key)
+		}
+
+		names[i] = strings.Join(keyNames, ", ")
+		keysWidth = maxInt(keysWidth, runewidth.StringWidth(names[i]))
+	}
+
+	var buffer bytes.Buffer
+	width := 0
+
+	for i, binding := range bindings {
+		name := util.LeftPaddedString(names[i], keysWidth, 0)
+		width = maxInt(width, keysWidth+runewidth.StringWidth(binding.help)+4)
+
+		if i > 0 {
+			buffer.WriteString("\n")
+		}
+		fmt.Fprintf(&buffer, " %s  %s ", util.Cyan(name), binding.help)
+	}
+
+	return buffer.String(), width, len(bindings)
+}
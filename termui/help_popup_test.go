@@ -0,0 +1,71 @@
+package termui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jroimartin/gocui"
+)
+
+// newTestUI setup the components of the termUI, without a terminal
+func newTestUI(t *testing.T) {
+	ui = &termUI{
+		bugTable:     newBugTable(nil, "origin"),
+		showBug:      newShowBug(nil),
+		msgPopup:     newMsgPopup(),
+		inputPopup:   newInputPopup(),
+		historyPopup: newHistoryPopup(),
+		confirmPopup: newConfirmPopup(),
+		helpPopup:    newHelpPopup(),
+	}
+	ui.activeWindow = ui.bugTable
+
+	var err error
+	ui.keybindings, err = registerKeybindings()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHelpPopup(t *testing.T) {
+	newTestUI(t)
+
+	// not initialized, as there is no terminal
+	g := &gocui.Gui{}
+
+	table, _ := g.SetView(bugTableView, 0, 0, 80, 20)
+
+	if err := showHelp(g, table); err != nil {
+		t.Fatal(err)
+	}
+	if err := ui.helpPopup.layoutSized(g, 80, 24); err != nil {
+		t.Fatal(err)
+	}
+
+	v, err := g.View(helpPopupView)
+	if err != nil {
+		t.Fatal("The help popup view should be created")
+	}
+	if g.CurrentView() != v {
+		t.Fatal("The help popup should have the focus")
+	}
+
+	for _, binding := range ui.keybindings.forView(bugTableView) {
+		if !strings.Contains(v.Buffer(), binding.help) {
+			t.Fatalf("The keybinding \"%s\" is not listed", binding.help)
+		}
+	}
+	if !strings.Contains(v.Buffer(), "ctrl+c") {
+		t.Fatal("The global keybindings should be listed")
+	}
+
+	// any key close it
+	v.Editor.Edit(v, 0, 'x', gocui.ModNone)
+
+	if ui.helpPopup.active {
+		t.Fatal("The help popup should be closed")
+	}
+	if _, err := g.View(helpPopupView); err != gocui.ErrUnknownView {
+		t.Fatal("The help popup view should be removed")
+	}
+}
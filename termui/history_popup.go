@@ -0,0 +1,136 @@
+package termui
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/util"
+	"github.com/jroimartin/gocui"
+)
+
+const historyPopupView = "historyPopupView"
+
+// historyPopup display the successive revisions of a comment, with the
+// difference between each of them
+type historyPopup struct {
+	active  bool
+	comment bug.Comment
+	scroll  int
+}
+
+func newHistoryPopup() *historyPopup {
+	return &historyPopup{}
+}
+
+func (hp *historyPopup) keybindings(r *keybindingRegistry) error {
+	return r.register(historyPopupView, []keybinding{
+		{"close", keys(gocui.KeyEsc, 'q', 'h'), "Close", hp.close},
+		{"down", keys('j', gocui.KeyArrowDown), "Scroll down", hp.scrollDown},
+		{"up", keys('k', gocui.KeyArrowUp), "Scroll up", hp.scrollUp},
+		{"next-page", keys(gocui.KeyPgdn), "Next page", hp.pageDown},
+		{"previous-page", keys(gocui.KeyPgup), "Previous page", hp.pageUp},
+	}...)
+}
+
+func (hp *historyPopup) layout(g *gocui.Gui) error {
+	if !hp.active {
+		return nil
+	}
+
+	maxX, maxY := g.Size()
+
+	width := minInt(80, maxX)
+	content, lines := util.TextWrap(hp.render(), width-2)
+	height := minInt(lines+1, maxY-3)
+	x0 := (maxX - width) / 2
+	y0 := (maxY - height) / 2
+
+	v, err := g.SetView(historyPopupView, x0, y0, x0+width, y0+height)
+	if err != nil {
+		if err != gocui.ErrUnknownView {
+			return err
+		}
+
+		v.Frame = true
+		v.Title = "Comment history"
+	}
+
+	// clamp the scrolling
+	_, viewHeight := v.Size()
+	hp.scroll = minInt(hp.scroll, maxInt(lines-viewHeight, 0))
+	hp.scroll = maxInt(hp.scroll, 0)
+
+	v.Clear()
+	fmt.Fprint(v, content)
+
+	if err := v.SetOrigin(0, hp.scroll); err != nil {
+		return err
+	}
+
+	if _, err := g.SetCurrentView(historyPopupView); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (hp *historyPopup) render() string {
+	var buffer bytes.Buffer
+
+	for i, revision := range hp.comment.History {
+		if i > 0 {
+			buffer.WriteString("\n\n")
+		}
+
+		fmt.Fprintf(&buffer, "%s %s on %s\n\n",
+			util.Bold(fmt.Sprintf("#%d", i)),
+			util.Magenta(revision.Author.Name),
+			revision.FormatTime(),
+		)
+
+		if i == 0 {
+			buffer.WriteString(revision.GetMessage())
+			continue
+		}
+
+		diff := util.WordDiff(hp.comment.History[i-1].GetMessage(), revision.GetMessage())
+		buffer.WriteString(util.FormatWordDiff(diff))
+	}
+
+	return buffer.String()
+}
+
+func (hp *historyPopup) close(g *gocui.Gui, v *gocui.View) error {
+	hp.active = false
+	hp.comment = bug.Comment{}
+	return g.DeleteView(historyPopupView)
+}
+
+func (hp *historyPopup) scrollDown(g *gocui.Gui, v *gocui.View) error {
+	hp.scroll++
+	return nil
+}
+
+func (hp *historyPopup) scrollUp(g *gocui.Gui, v *gocui.View) error {
+	hp.scroll--
+	return nil
+}
+
+func (hp *historyPopup) pageDown(g *gocui.Gui, v *gocui.View) error {
+	_, maxY := v.Size()
+	hp.scroll += maxY / 2
+	return nil
+}
+
+func (hp *historyPopup) pageUp(g *gocui.Gui, v *gocui.View) error {
+	_, maxY := v.Size()
+	hp.scroll -= maxY / 2
+	return nil
+}
+
+func (hp *historyPopup) Activate(comment bug.Comment) {
+	hp.active = true
+	hp.comment = comment
+	hp.scroll = 0
+}
@@ -1,7 +1,9 @@
 package termui
 
 import (
+	"fmt"
 	"io/ioutil"
+	"strings"
 
 	"github.com/jroimartin/gocui"
 )
@@ -12,24 +14,31 @@ type inputPopup struct {
 	active bool
 	title  string
 	c      chan string
-}
 
-func newInputPopup() *inputPopup {
-	return &inputPopup{}
-}
+	// identify the input, to restore it if it was discarded
+	key string
+	// the text written in the popup when it's created
+	preset string
 
-func (ip *inputPopup) keybindings(g *gocui.Gui) error {
-	// Close
-	if err := g.SetKeybinding(inputPopupView, gocui.KeyEsc, gocui.ModNone, ip.close); err != nil {
-		return err
-	}
+	// the discarded inputs of the session, by key
+	stash map[string]string
 
-	// Validate
-	if err := g.SetKeybinding(inputPopupView, gocui.KeyEnter, gocui.ModNone, ip.validate); err != nil {
-		return err
+	// the words completed with Tab, most likely first
+	completions []string
+}
+
+func newInputPopup() *inputPopup {
+	return &inputPopup{
+		stash: make(map[string]string),
 	}
+}
 
-	return nil
+func (ip *inputPopup) keybindings(r *keybindingRegistry) error {
+	return r.register(inputPopupView, []keybinding{
+		{"cancel", keys(gocui.KeyEsc), "Close", ip.confirmClose},
+		{"validate", keys(gocui.KeyEnter), "Validate", ip.validate},
+		{"complete", keys(gocui.KeyTab), "Complete", ip.complete},
+	}...)
 }
 
 func (ip *inputPopup) layout(g *gocui.Gui) error {
@@ -55,6 +64,19 @@ func (ip *inputPopup) layout(g *gocui.Gui) error {
 		v.Editable = true
 	}
 
+	if ip.preset != "" {
+		v.Clear()
+		if err := v.SetCursor(0, 0); err != nil {
+			return err
+		}
+		if err := v.SetOrigin(0, 0); err != nil {
+			return err
+		}
+		fmt.Fprint(v, ip.preset)
+		v.MoveCursor(len([]rune(ip.preset)), 0, false)
+		ip.preset = ""
+	}
+
 	if _, err := g.SetCurrentView(inputPopupView); err != nil {
 		return err
 	}
@@ -62,8 +84,73 @@ func (ip *inputPopup) layout(g *gocui.Gui) error {
 	return nil
 }
 
+// dirty tell if some text has been written in the popup
+func (ip *inputPopup) dirty(g *gocui.Gui) bool {
+	if !ip.active {
+		return false
+	}
+
+	v, err := g.View(inputPopupView)
+	if err != nil {
+		return false
+	}
+
+	return strings.TrimSpace(v.Buffer()) != ""
+}
+
+// confirmClose close the popup, after a confirmation if the input would
+// be lost. The discarded input is kept to be restored later.
+func (ip *inputPopup) confirmClose(g *gocui.Gui, v *gocui.View) error {
+	if !ip.dirty(g) {
+		return ip.close(g, v)
+	}
+
+	content := strings.TrimSpace(v.Buffer())
+
+	ui.confirmPopup.Activate(ip.title, "Discard your input?", func(g *gocui.Gui) error {
+		if ip.key != "" {
+			ip.stash[ip.key] = content
+		}
+		return ip.close(g, v)
+	})
+
+	return nil
+}
+
+// complete the word being written, separated by a space or a comma, with
+// the first completion it's a prefix of
+func (ip *inputPopup) complete(g *gocui.Gui, v *gocui.View) error {
+	text := strings.TrimRight(v.Buffer(), "\n")
+	start := strings.LastIndexAny(text, " ,") + 1
+	word := strings.ToLower(text[start:])
+
+	for _, completion := range ip.completions {
+		if len(completion) <= len(word) || !strings.HasPrefix(strings.ToLower(completion), word) {
+			continue
+		}
+
+		text = text[:start] + completion
+
+		v.Clear()
+		if err := v.SetCursor(0, 0); err != nil {
+			return err
+		}
+		if err := v.SetOrigin(0, 0); err != nil {
+			return err
+		}
+		fmt.Fprint(v, text)
+		v.MoveCursor(len([]rune(text)), 0, false)
+
+		return nil
+	}
+
+	return nil
+}
+
 func (ip *inputPopup) close(g *gocui.Gui, v *gocui.View) error {
 	ip.title = ""
+	ip.key = ""
+	ip.completions = nil
 	ip.active = false
 	return g.DeleteView(inputPopupView)
 }
@@ -77,6 +164,8 @@ func (ip *inputPopup) validate(g *gocui.Gui, v *gocui.View) error {
 	}
 
 	ip.title = ""
+	ip.key = ""
+	ip.completions = nil
 	ip.active = false
 	err = g.DeleteView(inputPopupView)
 	if err != nil {
@@ -88,9 +177,28 @@ func (ip *inputPopup) validate(g *gocui.Gui, v *gocui.View) error {
 	return nil
 }
 
-func (ip *inputPopup) Activate(title string) <-chan string {
+// Activate open the popup and return a channel receiving the validated
+// input. The key identify the input, so that an input discarded earlier in
+// the session with the same key can be restored.
+func (ip *inputPopup) Activate(title string, key string) <-chan string {
 	ip.title = title
+	ip.key = key
 	ip.active = true
 	ip.c = make(chan string)
+
+	if stashed, ok := ip.stash[key]; ok && key != "" {
+		delete(ip.stash, key)
+		ui.confirmPopup.Activate(title, "Restore your discarded input?", func(g *gocui.Gui) error {
+			ip.preset = stashed
+			return nil
+		})
+	}
+
 	return ip.c
 }
+
+// SetCompletions define the words completed with Tab in the active popup,
+// most likely first
+func (ip *inputPopup) SetCompletions(words []string) {
+	ip.completions = words
+}
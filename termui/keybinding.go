@@ -0,0 +1,237 @@
+package termui
+
+import (
+	"fmt"
+
+	"github.com/jroimartin/gocui"
+)
+
+// keybinding is an action and the keys triggering it. The components
+// register theirs in the keybindingRegistry, which set them in gocui and
+// list them in the help, so that the help can't get out of sync.
+type keybinding struct {
+	// the name of the action, to configure its keys, see loadKeymap. The
+	// same action can be bound in several views.
+	name string
+	// runes, gocui.Key or modKey
+	keys   []interface{}
+	help   string
+	action func(g *gocui.Gui, v *gocui.View) error
+}
+
+func keys(keys ...interface{}) []interface{} {
+	return keys
+}
+
+// modKey is a key pressed along with a modifier
+type modKey struct {
+	key interface{}
+	mod gocui.Modifier
+}
+
+// alt is the given key pressed along with alt
+func alt(key interface{}) modKey {
+	return modKey{key: key, mod: gocui.ModAlt}
+}
+
+// splitKey return a key of a keybinding and its modifier
+func splitKey(key interface{}) (interface{}, gocui.Modifier) {
+	if k, ok := key.(modKey); ok {
+		return k.key, k.mod
+	}
+	return key, gocui.ModNone
+}
+
+// the identity of a keybinding in gocui, which can only have one action
+type registeredKey struct {
+	view string
+	key  interface{}
+	mod  gocui.Modifier
+}
+
+// keybindingRegistry hold the keybindings of all the components, by view.
+// The bindings of "" are available in every view.
+type keybindingRegistry struct {
+	bindings map[string][]keybinding
+	// the views, in registration order
+	views      []string
+	registered map[registeredKey]bool
+}
+
+func newKeybindingRegistry() *keybindingRegistry {
+	return &keybindingRegistry{
+		bindings:   make(map[string][]keybinding),
+		registered: make(map[registeredKey]bool),
+	}
+}
+
+// register add keybindings to a view. A key already bound in this view is
+// rejected, as gocui would run both actions.
+func (r *keybindingRegistry) register(view string, bindings ...keybinding) error {
+	for _, binding := range bindings {
+		ids := make(map[registeredKey]bool, len(binding.keys))
+
+		for _, key := range binding.keys {
+			k, mod := splitKey(key)
+
+			if !validKey(k) {
+				return fmt.Errorf("invalid key %v for \"%s\"", k, binding.help)
+			}
+
+			id := registeredKey{view: view, key: k, mod: mod}
+			if r.registered[id] || ids[id] {
+				return fmt.Errorf("duplicate keybinding %s in view \"%s\"", keyName(key), view)
+			}
+			ids[id] = true
+		}
+
+		for id := range ids {
+			r.registered[id] = true
+		}
+
+		if _, ok := r.bindings[view]; !ok {
+			r.views = append(r.views, view)
+		}
+		r.bindings[view] = append(r.bindings[view], binding)
+	}
+
+	return nil
+}
+
+// forView return the keybindings registered for a view, in registration
+// order
+func (r *keybindingRegistry) forView(view string) []keybinding {
+	return r.bindings[view]
+}
+
+// shortcut return the name of the first key of an action in a view, as
+// displayed in the instruction bars
+func (r *keybindingRegistry) shortcut(view string, action string) string {
+	for _, binding := range r.bindings[view] {
+		if binding.name == action && len(binding.keys) > 0 {
+			return keyName(binding.keys[0])
+		}
+	}
+
+	return ""
+}
+
+// apply set all the keybindings in gocui
+func (r *keybindingRegistry) apply(g *gocui.Gui) error {
+	for _, view := range r.views {
+		for _, binding := range r.bindings[view] {
+			for _, key := range binding.keys {
+				k, mod := splitKey(key)
+				if err := g.SetKeybinding(view, k, mod, binding.action); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// validKey tell if a key is accepted by gocui, a rune or a gocui.Key
+func validKey(key interface{}) bool {
+	switch key.(type) {
+	case gocui.Key, rune:
+		return true
+	default:
+		return false
+	}
+}
+
+var keyNames = map[gocui.Key]string{
+	gocui.KeyArrowUp:    "↑",
+	gocui.KeyArrowDown:  "↓",
+	gocui.KeyArrowLeft:  "←",
+	gocui.KeyArrowRight: "→",
+	gocui.KeyPgup:       "pgup",
+	gocui.KeyPgdn:       "pgdn",
+	gocui.KeyHome:       "home",
+	gocui.KeyEnd:        "end",
+	gocui.KeyInsert:     "insert",
+	gocui.KeyDelete:     "delete",
+	gocui.KeyBackspace2: "backspace",
+	gocui.KeyEnter:      "enter",
+	gocui.KeyTab:        "tab",
+	gocui.KeyEsc:        "esc",
+	gocui.KeySpace:      "space",
+	gocui.KeyF1:         "f1",
+	gocui.KeyF2:         "f2",
+	gocui.KeyF3:         "f3",
+	gocui.KeyF4:         "f4",
+	gocui.KeyF5:         "f5",
+	gocui.KeyF6:         "f6",
+	gocui.KeyF7:         "f7",
+	gocui.KeyF8:         "f8",
+	gocui.KeyF9:         "f9",
+	gocui.KeyF10:        "f10",
+	gocui.KeyF11:        "f11",
+	gocui.KeyF12:        "f12",
+}
+
+func init() {
+	// ctrl+i and ctrl+m are the same keys as tab and enter, they keep these
+	// names
+	for key := gocui.KeyCtrlA; key <= gocui.KeyCtrlZ; key++ {
+		if _, ok := keyNames[key]; !ok {
+			keyNames[key] = fmt.Sprintf("ctrl+%c", 'a'+rune(key-gocui.KeyCtrlA))
+		}
+	}
+}
+
+// keyName return the name of a key, as displayed in the help
+func keyName(key interface{}) string {
+	switch k := key.(type) {
+	case modKey:
+		if k.mod == gocui.ModAlt {
+			return "alt+" + keyName(k.key)
+		}
+		return keyName(k.key)
+	case rune:
+		return string(k)
+	case gocui.Key:
+		if name, ok := keyNames[k]; ok {
+			return name
+		}
+	}
+
+	return fmt.Sprint(key)
+}
+
+// registerKeybindings register the keybindings of every component
+func registerKeybindings() (*keybindingRegistry, error) {
+	r := newKeybindingRegistry()
+
+	err := r.register("",
+		keybinding{"interrupt", keys(gocui.KeyCtrlC), "Quit", quit},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	components := []interface {
+		keybindings(r *keybindingRegistry) error
+	}{
+		ui.bugTable,
+		ui.showBug,
+		ui.msgPopup,
+		ui.inputPopup,
+		ui.historyPopup,
+		ui.confirmPopup,
+	}
+
+	for _, component := range components {
+		if err := component.keybindings(r); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := mouseKeybindings(r); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
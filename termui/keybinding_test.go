@@ -0,0 +1,57 @@
+package termui
+
+import (
+	"testing"
+
+	"github.com/jroimartin/gocui"
+)
+
+func noop(g *gocui.Gui, v *gocui.View) error {
+	return nil
+}
+
+func TestKeybindingRegistry(t *testing.T) {
+	r := newKeybindingRegistry()
+
+	err := r.register("view",
+		keybinding{"first", keys('a', gocui.KeyEnter), "first", noop},
+		keybinding{"second", keys(alt('a')), "second", noop},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the same key in another view
+	if err := r.register("other", keybinding{"", keys('a'), "third", noop}); err != nil {
+		t.Fatal(err)
+	}
+
+	bindings := r.forView("view")
+	if len(bindings) != 2 || bindings[0].help != "first" || bindings[1].help != "second" {
+		t.Fatalf("Unexpected keybindings %v", bindings)
+	}
+	if name := keyName(bindings[1].keys[0]); name != "alt+a" {
+		t.Fatalf("Unexpected key name %s", name)
+	}
+
+	if err := r.register("view", keybinding{"", keys(gocui.KeyEnter), "again", noop}); err == nil {
+		t.Fatal("A key already bound in the view should be rejected")
+	}
+	if err := r.register("view", keybinding{"", keys("a"), "string", noop}); err == nil {
+		t.Fatal("A key of an unknown type should be rejected")
+	}
+}
+
+func TestKeybindingsNoDuplicate(t *testing.T) {
+	// fail on a duplicate
+	newTestUI(t)
+
+	if len(ui.keybindings.forView(bugTableView)) == 0 || len(ui.keybindings.forView(showBugView)) == 0 {
+		t.Fatal("The windows should register their keybindings")
+	}
+
+	g := &gocui.Gui{}
+	if err := ui.keybindings.apply(g); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,223 @@
+package termui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"unicode/utf8"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/jroimartin/gocui"
+)
+
+// keyConfigPrefix is the prefix of the git config entries setting the keys
+// of an action, like git-bug.tui.key.down = "j down"
+const keyConfigPrefix = "git-bug.tui.key."
+
+// the prefix of the entries written before keyConfigPrefix, still read when
+// the action isn't configured with the current one
+const legacyKeyConfigPrefix = "git-bug.key."
+
+// the names of the keys accepted in the configuration, in addition to the
+// ones of the help
+var keyAliases = map[string]gocui.Key{
+	"up":    gocui.KeyArrowUp,
+	"down":  gocui.KeyArrowDown,
+	"left":  gocui.KeyArrowLeft,
+	"right": gocui.KeyArrowRight,
+}
+
+// parseKey parse the name of a key: the name of a special key like enter,
+// pgdn or ctrl+n, a single character, or one of those prefixed by alt+
+func parseKey(name string) (interface{}, error) {
+	lower := strings.ToLower(name)
+
+	if strings.HasPrefix(lower, "alt+") {
+		key, err := parseKey(name[len("alt+"):])
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := key.(modKey); ok {
+			return nil, fmt.Errorf("invalid key \"%s\"", name)
+		}
+		return alt(key), nil
+	}
+
+	if key, ok := keyAliases[lower]; ok {
+		return key, nil
+	}
+
+	for key, keyName := range keyNames {
+		if keyName == lower {
+			return key, nil
+		}
+	}
+
+	if utf8.RuneCountInString(name) == 1 {
+		r, _ := utf8.DecodeRuneInString(name)
+		return r, nil
+	}
+
+	return nil, fmt.Errorf("unknown key \"%s\"", name)
+}
+
+// parseKeys parse the space separated keys of an action
+func parseKeys(value string) ([]interface{}, error) {
+	var result []interface{}
+
+	for _, name := range strings.Fields(value) {
+		key, err := parseKey(name)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, key)
+	}
+
+	if len(result) == 0 {
+		return nil, fmt.Errorf("no key given")
+	}
+
+	return result, nil
+}
+
+// actions return the names of the configurable actions, in registration
+// order
+func (r *keybindingRegistry) actions() []string {
+	var result []string
+	seen := make(map[string]bool)
+
+	for _, view := range r.views {
+		for _, binding := range r.bindings[view] {
+			if binding.name != "" && !seen[binding.name] {
+				seen[binding.name] = true
+				result = append(result, binding.name)
+			}
+		}
+	}
+
+	return result
+}
+
+// override replace the keys of an action in every view it is bound in. The
+// registry is unchanged if the keys conflict with another action.
+func (r *keybindingRegistry) override(action string, keys []interface{}) error {
+	rebuilt := newKeybindingRegistry()
+	found := false
+
+	for _, view := range r.views {
+		for _, binding := range r.bindings[view] {
+			if action != "" && binding.name == action {
+				binding.keys = keys
+				found = true
+			}
+
+			if err := rebuilt.register(view, binding); err != nil {
+				return err
+			}
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("unknown action \"%s\"", action)
+	}
+
+	*r = *rebuilt
+
+	return nil
+}
+
+// loadKeymap override the keys of the actions configured in git. An invalid
+// entry is skipped, the action keeping its default keys, and reported in the
+// returned warnings.
+func (r *keybindingRegistry) loadKeymap(repo repository.Repo) ([]string, error) {
+	var warnings []string
+
+	for _, action := range r.actions() {
+		key := keyConfigPrefix + action
+
+		value, err := repo.GetConfig(key)
+		if err == repository.ErrNoConfigEntry {
+			key = legacyKeyConfigPrefix + action
+			value, err = repo.GetConfig(key)
+		}
+		if err == repository.ErrNoConfigEntry {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		keys, err := parseKeys(value)
+		if err == nil {
+			err = r.override(action, keys)
+		}
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("%s: %s, using the default keys", key, err))
+		}
+	}
+
+	return warnings, nil
+}
+
+// ShowKeys print the keybindings of every view, as configured in git, and
+// the configuration entries that are invalid
+func ShowKeys(c cache.RepoCacher, out io.Writer) error {
+	var err error
+
+	ui, err = newTermUI(c, Options{})
+	if err != nil {
+		return err
+	}
+
+	r, err := registerKeybindings()
+	if err != nil {
+		return err
+	}
+
+	warnings, err := r.loadKeymap(c.Repository())
+	if err != nil {
+		return err
+	}
+
+	for _, warning := range warnings {
+		fmt.Fprintf(out, "warning: %s\n", warning)
+	}
+
+	return r.print(out)
+}
+
+// print list the keybindings by view, with their action and help. The ones
+// without description are left out, as in the help.
+func (r *keybindingRegistry) print(out io.Writer) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+
+	for i, view := range r.views {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+
+		if view == "" {
+			fmt.Fprintln(w, "every view")
+		} else {
+			fmt.Fprintln(w, view)
+		}
+
+		for _, binding := range r.bindings[view] {
+			// the mouse events, like in the help
+			if binding.help == "" {
+				continue
+			}
+
+			names := make([]string, len(binding.keys))
+			for j, key := range binding.keys {
+				names[j] = keyName(key)
+			}
+
+			fmt.Fprintf(w, "  %s\t%s\t%s\n", strings.Join(names, " "), binding.name, binding.help)
+		}
+	}
+
+	return w.Flush()
+}
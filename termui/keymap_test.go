@@ -0,0 +1,205 @@
+package termui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/jroimartin/gocui"
+)
+
+func TestParseKeys(t *testing.T) {
+	valid := map[string][]interface{}{
+		"j":            keys('j'),
+		"j down":       keys('j', gocui.KeyArrowDown),
+		"↓ PgDn":       keys(gocui.KeyArrowDown, gocui.KeyPgdn),
+		"ctrl+n enter": keys(gocui.KeyCtrlN, gocui.KeyEnter),
+		"alt+x alt+f1": keys(alt('x'), alt(gocui.KeyF1)),
+		"? ,":          keys('?', ','),
+	}
+
+	for value, expected := range valid {
+		parsed, err := parseKeys(value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(parsed, expected) {
+			t.Fatalf("Unexpected keys %v for %q", parsed, value)
+		}
+	}
+
+	for _, invalid := range []string{"", "  ", "foo", "j bar", "alt+alt+x", "ctrl+"} {
+		if _, err := parseKeys(invalid); err == nil {
+			t.Fatalf("The keys %q should be rejected", invalid)
+		}
+	}
+
+	// the names of the help can be read back
+	for key := range keyNames {
+		parsed, err := parseKey(keyName(key))
+		if err != nil || parsed != key {
+			t.Fatalf("Unexpected key %v for %s", parsed, keyName(key))
+		}
+	}
+}
+
+func TestKeymapOverride(t *testing.T) {
+	r := newKeybindingRegistry()
+
+	err := r.register("list",
+		keybinding{"down", keys('j', gocui.KeyArrowDown), "down", noop},
+		keybinding{"new", keys('n'), "new", noop},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = r.register("bug",
+		keybinding{"down", keys('j'), "down", noop},
+		keybinding{"comment", keys('c'), "comment", noop},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// overridden in every view
+	if err := r.override("down", keys(gocui.KeyCtrlN)); err != nil {
+		t.Fatal(err)
+	}
+	for _, view := range []string{"list", "bug"} {
+		if got := r.forView(view)[0].keys; !reflect.DeepEqual(got, keys(gocui.KeyCtrlN)) {
+			t.Fatalf("Unexpected keys %v in %s", got, view)
+		}
+	}
+	if r.shortcut("list", "down") != "ctrl+n" {
+		t.Fatalf("Unexpected shortcut %s", r.shortcut("list", "down"))
+	}
+
+	// the replaced keys are free again
+	if err := r.override("new", keys('j')); err != nil {
+		t.Fatal(err)
+	}
+
+	// a conflict leave the registry unchanged
+	if err := r.override("comment", keys(gocui.KeyCtrlN)); err == nil {
+		t.Fatal("A key bound to another action should be rejected")
+	}
+	if shortcut := r.shortcut("bug", "comment"); shortcut != "c" {
+		t.Fatalf("The action should keep its keys, got %s", shortcut)
+	}
+
+	if err := r.override("unknown", keys('x')); err == nil {
+		t.Fatal("An unknown action should be rejected")
+	}
+
+	g := &gocui.Gui{}
+	if err := r.apply(g); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadKeymap(t *testing.T) {
+	newTestUI(t)
+
+	repo := repository.NewMockRepoForTest()
+	for action, value := range map[string]string{
+		"down":    "ctrl+j",
+		"up":      "ctrl+k",
+		"new":     "foo",
+		"comment": "t",
+		"unknown": "x",
+	} {
+		if err := repo.SetConfig(keyConfigPrefix+action, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	warnings, err := ui.keybindings.loadKeymap(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the invalid key, and the one already bound to change the title
+	if len(warnings) != 2 ||
+		!strings.Contains(warnings[0], keyConfigPrefix+"new") ||
+		!strings.Contains(warnings[1], keyConfigPrefix+"comment") {
+		t.Fatalf("Unexpected warnings %v", warnings)
+	}
+
+	for _, view := range []string{bugTableView, showBugView, historyPopupView} {
+		if shortcut := ui.keybindings.shortcut(view, "down"); shortcut != "ctrl+j" {
+			t.Fatalf("Unexpected shortcut %s in %s", shortcut, view)
+		}
+	}
+	if shortcut := ui.keybindings.shortcut(bugTableView, "new"); shortcut != "n" {
+		t.Fatalf("The invalid entry should keep the default keys, got %s", shortcut)
+	}
+	if shortcut := ui.keybindings.shortcut(showBugView, "comment"); shortcut != "c" {
+		t.Fatalf("The conflicting entry should keep the default keys, got %s", shortcut)
+	}
+
+	g := &gocui.Gui{}
+	if err := ui.keybindings.apply(g); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLegacyKeymap(t *testing.T) {
+	newTestUI(t)
+
+	repo := repository.NewMockRepoForTest()
+	for key, value := range map[string]string{
+		legacyKeyConfigPrefix + "down": "ctrl+j",
+		legacyKeyConfigPrefix + "up":   "ctrl+k",
+		keyConfigPrefix + "up":         "ctrl+p",
+	} {
+		if err := repo.SetConfig(key, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	warnings, err := ui.keybindings.loadKeymap(repo)
+	if err != nil || len(warnings) != 0 {
+		t.Fatalf("Unexpected warnings %v %v", warnings, err)
+	}
+
+	if shortcut := ui.keybindings.shortcut(bugTableView, "down"); shortcut != "ctrl+j" {
+		t.Fatalf("The legacy entry should be read, got %s", shortcut)
+	}
+	if shortcut := ui.keybindings.shortcut(bugTableView, "up"); shortcut != "ctrl+p" {
+		t.Fatalf("The current entry should take precedence, got %s", shortcut)
+	}
+}
+
+func TestPrintKeymap(t *testing.T) {
+	r := newKeybindingRegistry()
+
+	err := r.register("",
+		keybinding{"interrupt", keys(gocui.KeyCtrlC), "Quit", noop},
+		keybinding{"", keys(gocui.MouseLeft), "", noop},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = r.register("list",
+		keybinding{"down", keys('j', gocui.KeyArrowDown), "Select the next bug", noop},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out strings.Builder
+	if err := r.print(&out); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `every view
+  ctrl+c  interrupt  Quit
+
+list
+  j ↓  down  Select the next bug
+`
+	if out.String() != expected {
+		t.Fatalf("Unexpected keymap:\n%s", out.String())
+	}
+}
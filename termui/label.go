@@ -0,0 +1,58 @@
+package termui
+
+import (
+	"hash/fnv"
+	"strconv"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/util"
+)
+
+// the colors of the terminal a label can take, along with their usual value
+var labelColors = []struct {
+	r, g, b int64
+	color   func(a ...interface{}) string
+}{
+	{205, 0, 0, util.Red},
+	{0, 205, 0, util.Green},
+	{205, 205, 0, util.Yellow},
+	{0, 0, 238, util.Blue},
+	{205, 0, 205, util.Magenta},
+	{0, 205, 205, util.Cyan},
+}
+
+// colorLabel color a label with the color configured in the registry, or
+// else with one derived from its name. As the terminal only has 8 colors,
+// the closest one is used.
+func colorLabel(registry *bug.LabelRegistry, label bug.Label) string {
+	if registry != nil {
+		if info, ok := registry.Lookup(label); ok && info.Color != "" {
+			return closestLabelColor(info.Color)(string(label))
+		}
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(label))
+
+	return labelColors[h.Sum32()%uint32(len(labelColors))].color(string(label))
+}
+
+// closestLabelColor return the terminal color the closest to a #rrggbb one
+func closestLabelColor(hex string) func(a ...interface{}) string {
+	r, _ := strconv.ParseInt(hex[1:3], 16, 0)
+	g, _ := strconv.ParseInt(hex[3:5], 16, 0)
+	b, _ := strconv.ParseInt(hex[5:7], 16, 0)
+
+	best := labelColors[0].color
+	bestDistance := int64(-1)
+
+	for _, c := range labelColors {
+		distance := (r-c.r)*(r-c.r) + (g-c.g)*(g-c.g) + (b-c.b)*(b-c.b)
+		if bestDistance < 0 || distance < bestDistance {
+			best = c.color
+			bestDistance = distance
+		}
+	}
+
+	return best
+}
@@ -0,0 +1,152 @@
+package termui
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/jroimartin/gocui"
+	"github.com/mattn/go-runewidth"
+	"github.com/nsf/termbox-go"
+)
+
+// The git config key to enable the mouse support. It's disabled by
+// default as it prevent the native text selection of the terminal.
+const mouseConfigKey = repository.PreferencePrefix + mousePreference
+
+const mousePreference = "tui.mouse"
+
+// Two clicks on the same row within this delay open the bug
+const doubleClickDelay = 400 * time.Millisecond
+
+// button is an action of the instruction bar, triggered by its key or by
+// a click on it
+type button struct {
+	key    string
+	label  string
+	action func(g *gocui.Gui, v *gocui.View) error
+}
+
+func renderButtons(w io.Writer, buttons []button) {
+	for _, b := range buttons {
+		fmt.Fprintf(w, "[%s] %s ", b.key, b.label)
+	}
+}
+
+// buttonAt return the button rendered at the given column, if any
+func buttonAt(buttons []button, x int) (button, bool) {
+	start := 0
+
+	for _, b := range buttons {
+		end := start + runewidth.StringWidth(fmt.Sprintf("[%s] %s", b.key, b.label))
+
+		if x >= start && x < end {
+			return b, b.action != nil
+		}
+
+		// the separating space
+		start = end + 1
+	}
+
+	return button{}, false
+}
+
+func readMouseConfig(repo repository.Repo) (bool, error) {
+	value, err := repository.GetUserPreference(repo, mousePreference)
+	if err == repository.ErrNoConfigEntry {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid value for %s: %s", mouseConfigKey, value)
+	}
+
+	return enabled, nil
+}
+
+func mouseKeybindings(r *keybindingRegistry) error {
+	// The mouse events are sent to the view under the pointer, so they are
+	// dispatched according to the active window and popups instead. Without
+	// a description, they are not listed in the help.
+	return r.register("", []keybinding{
+		{"", keys(gocui.MouseLeft), "", onClick},
+		{"", keys(gocui.MouseWheelUp), "", onWheelUp},
+		{"", keys(gocui.MouseWheelDown), "", onWheelDown},
+	}...)
+}
+
+func toggleMouse(g *gocui.Gui, v *gocui.View) error {
+	ui.mouse = !ui.mouse
+	g.Mouse = ui.mouse
+
+	// gocui only read g.Mouse when starting its main loop
+	mode := termbox.InputEsc
+	if ui.mouse {
+		mode |= termbox.InputMouse
+	}
+	termbox.SetInputMode(mode)
+
+	return nil
+}
+
+func popupActive() bool {
+	return ui.msgPopup.active || ui.inputPopup.active || ui.historyPopup.active || ui.confirmPopup.active ||
+		ui.helpPopup.active
+}
+
+func onClick(g *gocui.Gui, v *gocui.View) error {
+	if popupActive() {
+		return nil
+	}
+
+	switch ui.activeWindow {
+	case ui.bugTable:
+		return ui.bugTable.click(g, v)
+	case ui.showBug:
+		return ui.showBug.click(g, v)
+	}
+
+	return nil
+}
+
+func onWheelUp(g *gocui.Gui, v *gocui.View) error {
+	if ui.historyPopup.active {
+		return ui.historyPopup.scrollUp(g, v)
+	}
+	if popupActive() {
+		return nil
+	}
+
+	switch ui.activeWindow {
+	case ui.bugTable:
+		return ui.bugTable.wheelUp(g)
+	case ui.showBug:
+		return ui.showBug.wheelUp(g)
+	}
+
+	return nil
+}
+
+func onWheelDown(g *gocui.Gui, v *gocui.View) error {
+	if ui.historyPopup.active {
+		return ui.historyPopup.scrollDown(g, v)
+	}
+	if popupActive() {
+		return nil
+	}
+
+	switch ui.activeWindow {
+	case ui.bugTable:
+		return ui.bugTable.wheelDown(g)
+	case ui.showBug:
+		return ui.showBug.wheelDown(g)
+	}
+
+	return nil
+}
@@ -10,6 +10,7 @@ import (
 const msgPopupView = "msgPopupView"
 
 const msgPopupErrorTitle = "Error"
+const msgPopupKeymapTitle = "Invalid keybindings"
 
 type msgPopup struct {
 	active  bool
@@ -23,18 +24,10 @@ func newMsgPopup() *msgPopup {
 	}
 }
 
-func (ep *msgPopup) keybindings(g *gocui.Gui) error {
-	if err := g.SetKeybinding(msgPopupView, gocui.KeySpace, gocui.ModNone, ep.close); err != nil {
-		return err
-	}
-	if err := g.SetKeybinding(msgPopupView, gocui.KeyEnter, gocui.ModNone, ep.close); err != nil {
-		return err
-	}
-	if err := g.SetKeybinding(msgPopupView, 'q', gocui.ModNone, ep.close); err != nil {
-		return err
-	}
-
-	return nil
+func (ep *msgPopup) keybindings(r *keybindingRegistry) error {
+	return r.register(msgPopupView, []keybinding{
+		{"close", keys(gocui.KeySpace, gocui.KeyEnter, 'q'), "Close", ep.close},
+	}...)
 }
 
 func (ep *msgPopup) layout(g *gocui.Gui) error {
@@ -63,7 +56,12 @@ func (ep *msgPopup) layout(g *gocui.Gui) error {
 	v.Title = ep.title
 
 	v.Clear()
-	fmt.Fprintf(v, wrapped)
+	fmt.Fprint(v, wrapped)
+
+	// the preview can be created after the popup, as when starting
+	if _, err := g.SetViewOnTop(msgPopupView); err != nil {
+		return err
+	}
 
 	if _, err := g.SetCurrentView(msgPopupView); err != nil {
 		return err
@@ -4,9 +4,12 @@ import (
 	"bytes"
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/MichaelMure/git-bug/bug"
 	"github.com/MichaelMure/git-bug/bug/operations"
 	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/input"
 	"github.com/MichaelMure/git-bug/util"
 	"github.com/jroimartin/gocui"
 )
@@ -18,6 +21,9 @@ const showBugHeaderView = "showBugHeaderView"
 
 const timeLayout = "Jan 2 2006"
 
+// indentation of the operations following another one of the same edit
+const editIndent = 4
+
 type showBug struct {
 	cache              cache.RepoCacher
 	bug                cache.BugCacher
@@ -27,6 +33,12 @@ type showBug struct {
 	selected           string
 	isOnSide           bool
 	scroll             int
+
+	// the colors of the labels, read when the bug is shown
+	labels *bug.LabelRegistry
+
+	// the drafts of the comments added, removed once committed
+	drafts []input.CommentDraft
 }
 
 func newShowBug(cache cache.RepoCacher) *showBug {
@@ -35,158 +47,143 @@ func newShowBug(cache cache.RepoCacher) *showBug {
 	}
 }
 
-func (sb *showBug) SetBug(bug cache.BugCacher) {
-	sb.bug = bug
+func (sb *showBug) SetBug(b cache.BugCacher) {
+	sb.bug = b
 	sb.scroll = 0
 	sb.selected = ""
 	sb.isOnSide = false
+
+	// without a readable registry, the colors derive from the label names
+	sb.labels, _ = bug.ReadLabelRegistry(sb.cache.Repository())
 }
 
 func (sb *showBug) layout(g *gocui.Gui) error {
 	maxX, maxY := g.Size()
-	sb.childViews = nil
 
-	v, err := g.SetView(showBugView, 0, 0, maxX*2/3, maxY-2)
+	if err := sb.layoutIn(g, 0, maxX, maxY-2); err != nil {
+		return err
+	}
+
+	v, err := g.SetView(showBugInstructionView, -1, maxY-2, maxX, maxY)
 
 	if err != nil {
 		if err != gocui.ErrUnknownView {
 			return err
 		}
 
-		sb.childViews = append(sb.childViews, showBugView)
 		v.Frame = false
+		v.BgColor = gocui.ColorBlue
 	}
 
+	sb.childViews = append(sb.childViews, showBugInstructionView)
+
 	v.Clear()
-	err = sb.renderMain(g, v)
+	renderButtons(v, sb.buttons())
+
+	_, err = g.SetViewOnTop(showBugInstructionView)
 	if err != nil {
 		return err
 	}
 
-	v, err = g.SetView(showBugSidebarView, maxX*2/3+1, 0, maxX-1, maxY-2)
+	_, err = g.SetCurrentView(showBugView)
+	return err
+}
+
+// layoutIn display the bug between the columns x0 and x1, down to the row
+// y1, either in full or as the preview of the bug table
+func (sb *showBug) layoutIn(g *gocui.Gui, x0, x1, y1 int) error {
+	sb.childViews = nil
+
+	sideX := x0 + (x1-x0)*2/3
+
+	v, err := g.SetView(showBugView, x0, 0, sideX, y1)
 
 	if err != nil {
 		if err != gocui.ErrUnknownView {
 			return err
 		}
 
-		sb.childViews = append(sb.childViews, showBugSidebarView)
 		v.Frame = false
 	}
 
+	sb.childViews = append(sb.childViews, showBugView)
+
 	v.Clear()
-	err = sb.renderSidebar(g, v)
+	err = sb.renderMain(g, v)
 	if err != nil {
 		return err
 	}
 
-	v, err = g.SetView(showBugInstructionView, -1, maxY-2, maxX, maxY)
+	v, err = g.SetView(showBugSidebarView, sideX+1, 0, x1-1, y1)
 
 	if err != nil {
 		if err != gocui.ErrUnknownView {
 			return err
 		}
 
-		sb.childViews = append(sb.childViews, showBugInstructionView)
 		v.Frame = false
-		v.BgColor = gocui.ColorBlue
 	}
 
-	v.Clear()
-	fmt.Fprintf(v, "[q] Save and return [←↓↑→,hjkl] Navigation ")
+	sb.childViews = append(sb.childViews, showBugSidebarView)
 
-	if sb.isOnSide {
-		fmt.Fprint(v, "[a] Add label [r] Remove label")
-	} else {
-		fmt.Fprint(v, "[c] Comment [t] Change title")
-	}
-
-	_, err = g.SetViewOnTop(showBugInstructionView)
-	if err != nil {
-		return err
-	}
-
-	_, err = g.SetCurrentView(showBugView)
-	return err
+	v.Clear()
+	return sb.renderSidebar(g, v)
 }
 
-func (sb *showBug) keybindings(g *gocui.Gui) error {
-	// Return
-	if err := g.SetKeybinding(showBugView, 'q', gocui.ModNone, sb.saveAndBack); err != nil {
-		return err
+func (sb *showBug) buttons() []button {
+	key := func(action string) string {
+		return ui.keybindings.shortcut(showBugView, action)
 	}
 
-	// Scrolling
-	if err := g.SetKeybinding(showBugView, gocui.KeyPgup, gocui.ModNone,
-		sb.scrollUp); err != nil {
-		return err
-	}
-	if err := g.SetKeybinding(showBugView, gocui.KeyPgdn, gocui.ModNone,
-		sb.scrollDown); err != nil {
-		return err
-	}
-
-	// Down
-	if err := g.SetKeybinding(showBugView, 'j', gocui.ModNone,
-		sb.selectNext); err != nil {
-		return err
-	}
-	if err := g.SetKeybinding(showBugView, gocui.KeyArrowDown, gocui.ModNone,
-		sb.selectNext); err != nil {
-		return err
-	}
-	// Up
-	if err := g.SetKeybinding(showBugView, 'k', gocui.ModNone,
-		sb.selectPrevious); err != nil {
-		return err
-	}
-	if err := g.SetKeybinding(showBugView, gocui.KeyArrowUp, gocui.ModNone,
-		sb.selectPrevious); err != nil {
-		return err
-	}
+	var buttons []button
 
-	// Left
-	if err := g.SetKeybinding(showBugView, 'h', gocui.ModNone,
-		sb.left); err != nil {
-		return err
-	}
-	if err := g.SetKeybinding(showBugView, gocui.KeyArrowLeft, gocui.ModNone,
-		sb.left); err != nil {
-		return err
-	}
-	// Right
-	if err := g.SetKeybinding(showBugView, 'l', gocui.ModNone,
-		sb.right); err != nil {
-		return err
-	}
-	if err := g.SetKeybinding(showBugView, gocui.KeyArrowRight, gocui.ModNone,
-		sb.right); err != nil {
-		return err
+	if sb.previewed() {
+		buttons = append(buttons, button{key("focus"), "Focus bug list", ui.bugTable.switchFocus})
 	}
 
-	// Comment
-	if err := g.SetKeybinding(showBugView, 'c', gocui.ModNone,
-		sb.comment); err != nil {
-		return err
-	}
+	buttons = append(buttons,
+		button{key("back"), "Save and return", sb.saveAndBack},
+		button{"←↓↑→,hjkl", "Navigation", nil},
+	)
 
-	// Title
-	if err := g.SetKeybinding(showBugView, 't', gocui.ModNone,
-		sb.setTitle); err != nil {
-		return err
+	if sb.isOnSide {
+		buttons = append(buttons,
+			button{key("add-label"), "Add label", sb.addLabel},
+			button{key("remove-label"), "Remove label", sb.removeLabel},
+		)
+	} else {
+		buttons = append(buttons,
+			button{key("comment"), "Comment", sb.comment},
+			button{key("title"), "Change title", sb.setTitle},
+			button{key("history"), "Comment history", sb.commentHistory},
+		)
 	}
 
-	// Labels
-	if err := g.SetKeybinding(showBugView, 'a', gocui.ModNone,
-		sb.addLabel); err != nil {
-		return err
-	}
-	if err := g.SetKeybinding(showBugView, 'r', gocui.ModNone,
-		sb.removeLabel); err != nil {
-		return err
-	}
+	return append(buttons,
+		button{key("browse"), "Web page", sb.openInBrowser},
+		button{key("mouse"), "Mouse", toggleMouse},
+	)
+}
 
-	return nil
+func (sb *showBug) keybindings(r *keybindingRegistry) error {
+	return r.register(showBugView, []keybinding{
+		{"back", keys('q'), "Save and return to the bug list", sb.saveAndBack},
+		{"scroll-up", keys(gocui.KeyPgup), "Scroll up", sb.scrollUp},
+		{"scroll-down", keys(gocui.KeyPgdn), "Scroll down", sb.scrollDown},
+		{"down", keys('j', gocui.KeyArrowDown), "Select the next item", sb.selectNext},
+		{"up", keys('k', gocui.KeyArrowUp), "Select the previous item", sb.selectPrevious},
+		{"left", keys(gocui.KeyArrowLeft), "Move to the comments", sb.left},
+		{"right", keys('l', gocui.KeyArrowRight), "Move to the labels", sb.right},
+		{"history", keys('h'), "History of the selected comment, or move to the comments", sb.commentHistory},
+		{"comment", keys('c'), "Comment", sb.comment},
+		{"title", keys('t'), "Change the title", sb.setTitle},
+		{"add-label", keys('a'), "Add labels", sb.addLabel},
+		{"remove-label", keys('r'), "Remove labels", sb.removeLabel},
+		{"browse", keys('w'), "Open the web page of the bug", sb.openInBrowser},
+		{"focus", keys(gocui.KeyTab), "Focus the bug list, in the preview", ui.bugTable.switchFocus},
+		{"mouse", keys('m'), "Toggle the mouse support", toggleMouse},
+		{"help", keys('?'), "Show the keybindings", showHelp},
+	}...)
 }
 
 func (sb *showBug) disable(g *gocui.Gui) error {
@@ -217,7 +214,7 @@ func (sb *showBug) renderMain(g *gocui.Gui, mainView *gocui.View) error {
 	)
 	bugHeader, lines := util.TextWrap(bugHeader, maxX)
 
-	v, err := sb.createOpView(g, showBugHeaderView, x0, y0, maxX+1, lines, false)
+	v, err := sb.createOpView(g, showBugHeaderView, x0, y0, x0+maxX+1, lines, false)
 	if err != nil {
 		return err
 	}
@@ -225,19 +222,30 @@ func (sb *showBug) renderMain(g *gocui.Gui, mainView *gocui.View) error {
 	fmt.Fprint(v, bugHeader)
 	y0 += lines + 1
 
+	// index of the next comment in the snapshot, to display the deleted
+	// comments as such
+	commentIndex := 0
+
 	for i, op := range snap.Operations {
 		viewName := fmt.Sprintf("op%d", i)
 
+		// the operations made in the same edit session are grouped under
+		// the first one
+		opX0, width := x0, maxX
+		if snap.SameEdit(i) {
+			opX0, width = x0+editIndent, maxX-editIndent
+		}
+
 		// TODO: me might skip the rendering of blocks that are outside of the view
 		// but to do that we need to rework how sb.mainSelectableView is maintained
 
 		switch op.(type) {
 
 		case operations.CreateOperation:
-			create := op.(operations.CreateOperation)
-			content, lines := util.TextWrapPadded(create.Message, maxX, 4)
+			content, lines := renderComment(snap.Comments[commentIndex], width, 4)
+			commentIndex++
 
-			v, err := sb.createOpView(g, viewName, x0, y0, maxX+1, lines, true)
+			v, err := sb.createOpView(g, viewName, opX0, y0, x0+maxX+1, lines, true)
 			if err != nil {
 				return err
 			}
@@ -247,15 +255,21 @@ func (sb *showBug) renderMain(g *gocui.Gui, mainView *gocui.View) error {
 		case operations.AddCommentOperation:
 			comment := op.(operations.AddCommentOperation)
 
-			message, _ := util.TextWrapPadded(comment.Message, maxX, 4)
-			content := fmt.Sprintf("%s commented on %s\n\n%s",
+			if isArchivedComment(snap, op) {
+				continue
+			}
+
+			message, messageLines := renderComment(snap.Comments[commentIndex], width, 4)
+			commentIndex++
+			header := fmt.Sprintf("%s commented on %s",
 				util.Magenta(comment.Author.Name),
 				comment.Time().Format(timeLayout),
-				message,
 			)
-			content, lines = util.TextWrap(content, maxX)
+			header, headerLines := util.TextWrap(header, width)
+			content := fmt.Sprintf("%s\n\n%s", header, message)
+			lines = headerLines + 1 + messageLines
 
-			v, err := sb.createOpView(g, viewName, x0, y0, maxX+1, lines, true)
+			v, err := sb.createOpView(g, viewName, opX0, y0, x0+maxX+1, lines, true)
 			if err != nil {
 				return err
 			}
@@ -270,9 +284,9 @@ func (sb *showBug) renderMain(g *gocui.Gui, mainView *gocui.View) error {
 				util.Bold(setTitle.Title),
 				setTitle.Time().Format(timeLayout),
 			)
-			content, lines := util.TextWrap(content, maxX)
+			content, lines := util.TextWrap(content, width)
 
-			v, err := sb.createOpView(g, viewName, x0, y0, maxX+1, lines, true)
+			v, err := sb.createOpView(g, viewName, opX0, y0, x0+maxX+1, lines, true)
 			if err != nil {
 				return err
 			}
@@ -287,9 +301,148 @@ func (sb *showBug) renderMain(g *gocui.Gui, mainView *gocui.View) error {
 				util.Bold(setStatus.Status.Action()),
 				setStatus.Time().Format(timeLayout),
 			)
-			content, lines := util.TextWrap(content, maxX)
+			content, lines := util.TextWrap(content, width)
+
+			v, err := sb.createOpView(g, viewName, opX0, y0, x0+maxX+1, lines, true)
+			if err != nil {
+				return err
+			}
+			fmt.Fprint(v, content)
+			y0 += lines + 2
+
+		case operations.DeleteCommentOperation:
+			deleteComment := op.(operations.DeleteCommentOperation)
+
+			content := fmt.Sprintf("%s deleted a comment on %s",
+				util.Magenta(deleteComment.Author.Name),
+				deleteComment.Time().Format(timeLayout),
+			)
+			content, lines := util.TextWrap(content, width)
+
+			v, err := sb.createOpView(g, viewName, opX0, y0, x0+maxX+1, lines, true)
+			if err != nil {
+				return err
+			}
+			fmt.Fprint(v, content)
+			y0 += lines + 2
+
+		case operations.SetCustomFieldOperation:
+			setCustomField := op.(operations.SetCustomFieldOperation)
+
+			action := fmt.Sprintf("set %s to %s", util.Bold(setCustomField.Key), util.Bold(setCustomField.Value))
+			if setCustomField.Value == "" {
+				action = fmt.Sprintf("removed %s", util.Bold(setCustomField.Key))
+			}
+
+			content := fmt.Sprintf("%s %s on %s",
+				util.Magenta(setCustomField.Author.Name),
+				action,
+				setCustomField.Time().Format(timeLayout),
+			)
+			content, lines := util.TextWrap(content, width)
+
+			v, err := sb.createOpView(g, viewName, opX0, y0, x0+maxX+1, lines, true)
+			if err != nil {
+				return err
+			}
+			fmt.Fprint(v, content)
+			y0 += lines + 2
+
+		case operations.ArchiveCommentsOperation:
+			archive := op.(operations.ArchiveCommentsOperation)
+
+			content := fmt.Sprintf("%s archived the comments older than %s on %s",
+				util.Magenta(archive.Author.Name),
+				time.Unix(archive.Before, 0).Format(timeLayout),
+				archive.Time().Format(timeLayout),
+			)
+			content, lines := util.TextWrap(content, width)
+
+			v, err := sb.createOpView(g, viewName, opX0, y0, x0+maxX+1, lines, true)
+			if err != nil {
+				return err
+			}
+			fmt.Fprint(v, content)
+			y0 += lines + 2
+
+		case operations.SetPriorityOperation:
+			setPriority := op.(operations.SetPriorityOperation)
+
+			content := fmt.Sprintf("%s set the priority to %s on %s",
+				util.Magenta(setPriority.Author.Name),
+				util.Bold(setPriority.Priority.String()),
+				setPriority.Time().Format(timeLayout),
+			)
+			content, lines := util.TextWrap(content, width)
+
+			v, err := sb.createOpView(g, viewName, opX0, y0, x0+maxX+1, lines, true)
+			if err != nil {
+				return err
+			}
+			fmt.Fprint(v, content)
+			y0 += lines + 2
+
+		case operations.SetChecklistOperation:
+			setChecklist := op.(operations.SetChecklistOperation)
+
+			items := make([]string, len(setChecklist.Items))
+			for i, item := range setChecklist.Items {
+				items[i] = fmt.Sprintf("%s: %s", item.Name, util.Bold(item.State.String()))
+			}
+
+			content := fmt.Sprintf("%s updated the checklist %s on %s\n%s",
+				util.Magenta(setChecklist.Author.Name),
+				util.Bold(setChecklist.Checklist),
+				setChecklist.Time().Format(timeLayout),
+				strings.Join(items, ", "),
+			)
+			content, lines := util.TextWrap(content, width)
+
+			v, err := sb.createOpView(g, viewName, opX0, y0, x0+maxX+1, lines, true)
+			if err != nil {
+				return err
+			}
+			fmt.Fprint(v, content)
+			y0 += lines + 2
+
+		case operations.RemapAuthorsOperation:
+			remapAuthors := op.(operations.RemapAuthorsOperation)
+
+			remaps := make([]string, len(remapAuthors.Remaps))
+			for i, remap := range remapAuthors.Remaps {
+				remaps[i] = fmt.Sprintf("%s --> %s", remap.Login, util.Bold(remap.Person.Name))
+			}
+
+			content := fmt.Sprintf("%s remapped the imported authors on %s\n%s",
+				util.Magenta(remapAuthors.Author.Name),
+				remapAuthors.Time().Format(timeLayout),
+				strings.Join(remaps, ", "),
+			)
+			content, lines := util.TextWrap(content, width)
 
-			v, err := sb.createOpView(g, viewName, x0, y0, maxX+1, lines, true)
+			v, err := sb.createOpView(g, viewName, opX0, y0, x0+maxX+1, lines, true)
+			if err != nil {
+				return err
+			}
+			fmt.Fprint(v, content)
+			y0 += lines + 2
+
+		case operations.AddTimeLogOperation:
+			addTimeLog := op.(operations.AddTimeLogOperation)
+
+			action := fmt.Sprintf("logged %s", util.Bold(addTimeLog.Duration.String()))
+			if addTimeLog.Note != "" {
+				action = fmt.Sprintf("%s (%s)", action, addTimeLog.Note)
+			}
+
+			content := fmt.Sprintf("%s %s on %s",
+				util.Magenta(addTimeLog.Author.Name),
+				action,
+				addTimeLog.Time().Format(timeLayout),
+			)
+			content, lines := util.TextWrap(content, width)
+
+			v, err := sb.createOpView(g, viewName, opX0, y0, x0+maxX+1, lines, true)
 			if err != nil {
 				return err
 			}
@@ -336,9 +489,9 @@ func (sb *showBug) renderMain(g *gocui.Gui, mainView *gocui.View) error {
 				action.String(),
 				labelChange.Time().Format(timeLayout),
 			)
-			content, lines := util.TextWrap(content, maxX)
+			content, lines := util.TextWrap(content, width)
 
-			v, err := sb.createOpView(g, viewName, x0, y0, maxX+1, lines, true)
+			v, err := sb.createOpView(g, viewName, opX0, y0, x0+maxX+1, lines, true)
 			if err != nil {
 				return err
 			}
@@ -398,7 +551,7 @@ func (sb *showBug) renderSidebar(g *gocui.Gui, sideView *gocui.View) error {
 
 	labelStr := make([]string, len(snap.Labels))
 	for i, l := range snap.Labels {
-		labelStr[i] = string(l)
+		labelStr[i] = colorLabel(sb.labels, l)
 	}
 
 	labels := strings.Join(labelStr, "\n")
@@ -421,10 +574,46 @@ func (sb *showBug) saveAndBack(g *gocui.Gui, v *gocui.View) error {
 	if err != nil {
 		return err
 	}
+
+	err = sb.removeDrafts()
+	if err != nil {
+		return err
+	}
+
+	if sb.previewed() {
+		return ui.bugTable.switchFocus(g, v)
+	}
+
 	ui.activateWindow(ui.bugTable)
 	return nil
 }
 
+// removeDrafts remove the drafts of the comments of the bug, once committed.
+// The ones of the other bugs are kept until their bug is saved.
+func (sb *showBug) removeDrafts() error {
+	id := sb.bug.Snapshot().Id()
+	kept := sb.drafts[:0]
+
+	for _, draft := range sb.drafts {
+		if draft.BugId != id {
+			kept = append(kept, draft)
+			continue
+		}
+		if err := draft.Remove(); err != nil {
+			return err
+		}
+	}
+
+	sb.drafts = kept
+	return nil
+}
+
+// previewed tell if the bug is shown as the preview of the bug table rather
+// than in full
+func (sb *showBug) previewed() bool {
+	return ui.activeWindow == ui.bugTable
+}
+
 func (sb *showBug) scrollUp(g *gocui.Gui, v *gocui.View) error {
 	mainView, err := g.View(showBugView)
 	if err != nil {
@@ -466,6 +655,52 @@ func (sb *showBug) scrollDown(g *gocui.Gui, v *gocui.View) error {
 	return nil
 }
 
+func (sb *showBug) click(g *gocui.Gui, v *gocui.View) error {
+	if v.Name() == showBugInstructionView {
+		x, _ := v.Cursor()
+		if b, ok := buttonAt(sb.buttons(), x); ok {
+			return b.action(g, v)
+		}
+		return nil
+	}
+
+	for _, name := range sb.mainSelectableView {
+		if name == v.Name() {
+			sb.isOnSide = false
+			sb.selected = name
+			return sb.focusView(g)
+		}
+	}
+
+	for _, name := range sb.sideSelectableView {
+		if name == v.Name() {
+			sb.isOnSide = true
+			sb.selected = name
+			return sb.focusView(g)
+		}
+	}
+
+	return nil
+}
+
+func (sb *showBug) wheelUp(g *gocui.Gui) error {
+	v, err := g.View(showBugView)
+	if err != nil {
+		return err
+	}
+
+	return sb.scrollUp(g, v)
+}
+
+func (sb *showBug) wheelDown(g *gocui.Gui) error {
+	v, err := g.View(showBugView)
+	if err != nil {
+		return err
+	}
+
+	return sb.scrollDown(g, v)
+}
+
 func (sb *showBug) selectPrevious(g *gocui.Gui, v *gocui.View) error {
 	defer sb.focusView(g)
 
@@ -589,8 +824,68 @@ func (sb *showBug) setTitle(g *gocui.Gui, v *gocui.View) error {
 	return setTitleWithEditor(sb.bug)
 }
 
+func (sb *showBug) openInBrowser(g *gocui.Gui, v *gocui.View) error {
+	return openInBrowser(sb.cache, sb.bug)
+}
+
+func (sb *showBug) commentHistory(g *gocui.Gui, v *gocui.View) error {
+	comment, ok := sb.selectedComment()
+	if !ok {
+		return sb.left(g, v)
+	}
+
+	if comment.IsDeleted() {
+		ui.msgPopup.Activate("Comment history", "This comment has been deleted.")
+		return nil
+	}
+
+	ui.historyPopup.Activate(comment)
+
+	return nil
+}
+
+// selectedComment return the comment displayed in the selected view, if any
+func (sb *showBug) selectedComment() (bug.Comment, bool) {
+	if sb.isOnSide || sb.selected == "" {
+		return bug.Comment{}, false
+	}
+
+	snap := sb.bug.Snapshot()
+	commentIndex := 0
+
+	for i, op := range snap.Operations {
+		isComment := (op.OpType() == bug.CreateOp || op.OpType() == bug.AddCommentOp) &&
+			!isArchivedComment(snap, op)
+
+		if fmt.Sprintf("op%d", i) == sb.selected {
+			if !isComment || commentIndex >= len(snap.Comments) {
+				return bug.Comment{}, false
+			}
+			return snap.Comments[commentIndex], true
+		}
+
+		if isComment {
+			commentIndex++
+		}
+	}
+
+	return bug.Comment{}, false
+}
+
+// isArchivedComment tell if the operation created a comment now archived,
+// and so not in the comments of the snapshot
+func isArchivedComment(snap *bug.Snapshot, op bug.Operation) bool {
+	if snap.ArchivedCount == 0 {
+		return false
+	}
+
+	hash, err := bug.HashOperation(op)
+	return err == nil && snap.IsArchivedComment(hash)
+}
+
 func (sb *showBug) addLabel(g *gocui.Gui, v *gocui.View) error {
-	c := ui.inputPopup.Activate("Add labels")
+	c := ui.inputPopup.Activate("Add labels", "add-labels/"+sb.bug.Snapshot().Id())
+	ui.inputPopup.SetCompletions(knownLabels())
 
 	go func() {
 		input := <-c
@@ -599,7 +894,7 @@ func (sb *showBug) addLabel(g *gocui.Gui, v *gocui.View) error {
 			return r == ' ' || r == ','
 		})
 
-		err := sb.bug.ChangeLabels(trimLabels(labels), nil)
+		err := sb.bug.ChangeLabels(nil, trimLabels(labels), nil)
 		if err != nil {
 			ui.msgPopup.Activate(msgPopupErrorTitle, err.Error())
 		}
@@ -613,7 +908,13 @@ func (sb *showBug) addLabel(g *gocui.Gui, v *gocui.View) error {
 }
 
 func (sb *showBug) removeLabel(g *gocui.Gui, v *gocui.View) error {
-	c := ui.inputPopup.Activate("Remove labels")
+	c := ui.inputPopup.Activate("Remove labels", "remove-labels/"+sb.bug.Snapshot().Id())
+
+	var current []string
+	for _, label := range sb.bug.Snapshot().Labels {
+		current = append(current, string(label))
+	}
+	ui.inputPopup.SetCompletions(current)
 
 	go func() {
 		input := <-c
@@ -622,7 +923,7 @@ func (sb *showBug) removeLabel(g *gocui.Gui, v *gocui.View) error {
 			return r == ' ' || r == ','
 		})
 
-		err := sb.bug.ChangeLabels(nil, trimLabels(labels))
+		err := sb.bug.ChangeLabels(nil, nil, trimLabels(labels))
 		if err != nil {
 			ui.msgPopup.Activate(msgPopupErrorTitle, err.Error())
 		}
@@ -635,6 +936,22 @@ func (sb *showBug) removeLabel(g *gocui.Gui, v *gocui.View) error {
 	return nil
 }
 
+// knownLabels return the labels used in the repository, most used first,
+// for the completion
+func knownLabels() []string {
+	counts, err := ui.cache.Labels()
+	if err != nil {
+		return nil
+	}
+
+	labels := make([]string, len(counts))
+	for i, count := range counts {
+		labels[i] = string(count.Label)
+	}
+
+	return labels
+}
+
 func trimLabels(labels []string) []string {
 	var result []string
 
@@ -646,3 +963,30 @@ func trimLabels(labels []string) []string {
 	}
 	return result
 }
+
+// renderComment render the markdown of a comment, unless disabled or
+// deleted, to the width of the view with a left padding
+func renderComment(comment bug.Comment, width int, leftPad int) (string, int) {
+	if ui.rawMarkdown || comment.IsDeleted() {
+		return wrapMessage(comment.GetMessage(), width, leftPad)
+	}
+
+	rendered := util.RenderMarkdown(comment.GetMessage(), width-leftPad, util.BasicMarkdownStyle)
+
+	return padLines(strings.Split(rendered, "\n"), leftPad)
+}
+
+// wrapMessage wrap a message to the width of the view, with a left padding
+func wrapMessage(message string, width int, leftPad int) (string, int) {
+	return padLines(util.WrapText(message, width-leftPad), leftPad)
+}
+
+func padLines(lines []string, leftPad int) (string, int) {
+	pad := strings.Repeat(" ", leftPad)
+
+	for i, line := range lines {
+		lines[i] = pad + line
+	}
+
+	return strings.Join(lines, "\n"), len(lines)
+}
@@ -1,9 +1,12 @@
 package termui
 
 import (
+	"strings"
+	"sync"
+
+	"github.com/MichaelMure/git-bug/bug"
 	"github.com/MichaelMure/git-bug/cache"
 	"github.com/MichaelMure/git-bug/input"
-	"github.com/MichaelMure/git-bug/repository"
 	"github.com/jroimartin/gocui"
 	"github.com/pkg/errors"
 )
@@ -17,10 +20,26 @@ type termUI struct {
 
 	activeWindow window
 
-	bugTable   *bugTable
-	showBug    *showBug
-	msgPopup   *msgPopup
-	inputPopup *inputPopup
+	// mouse support, toggled at runtime
+	mouse bool
+
+	// show the comments without rendering their markdown
+	rawMarkdown bool
+
+	// guard g for the goroutines updating the gui, see update
+	gMu sync.Mutex
+	// the updates posted while the gui is closed for an editor
+	pending []func(*gocui.Gui) error
+
+	bugTable     *bugTable
+	showBug      *showBug
+	msgPopup     *msgPopup
+	inputPopup   *inputPopup
+	historyPopup *historyPopup
+	confirmPopup *confirmPopup
+	helpPopup    *helpPopup
+
+	keybindings *keybindingRegistry
 }
 
 func (tui *termUI) activateWindow(window window) error {
@@ -33,32 +52,99 @@ func (tui *termUI) activateWindow(window window) error {
 	return nil
 }
 
+// setGui make g the current gui, running the pending updates in it
+func (tui *termUI) setGui(g *gocui.Gui) {
+	tui.gMu.Lock()
+	defer tui.gMu.Unlock()
+
+	tui.g = g
+
+	for _, f := range tui.pending {
+		g.Update(f)
+	}
+	tui.pending = nil
+}
+
+// closeGui close the current gui, to start a new one later
+func (tui *termUI) closeGui() {
+	tui.gMu.Lock()
+	g := tui.g
+	tui.g = nil
+	tui.gMu.Unlock()
+
+	g.Close()
+}
+
+// update run f in the main loop, where it doesn't race with the layout. As
+// the gui is restarted around the editors, the goroutines must use it rather
+// than calling Update on a gui which might be closed already.
+func (tui *termUI) update(f func(*gocui.Gui) error) {
+	tui.gMu.Lock()
+	defer tui.gMu.Unlock()
+
+	if tui.g == nil {
+		tui.pending = append(tui.pending, f)
+		return
+	}
+
+	tui.g.Update(f)
+}
+
+// redraw trigger a layout, if the gui is running
+func (tui *termUI) redraw() {
+	tui.gMu.Lock()
+	defer tui.gMu.Unlock()
+
+	if tui.g != nil {
+		tui.g.Update(func(*gocui.Gui) error { return nil })
+	}
+}
+
 var ui *termUI
 
 type window interface {
-	keybindings(g *gocui.Gui) error
+	keybindings(r *keybindingRegistry) error
 	layout(g *gocui.Gui) error
 	disable(g *gocui.Gui) error
 }
 
+// Options tune the behavior of the terminal UI
+type Options struct {
+	// RawMarkdown disable the rendering of the markdown of the comments
+	RawMarkdown bool
+}
+
 // Run will launch the termUI in the terminal
-func Run(repo repository.Repo) error {
-	c := cache.NewRepoCache(repo)
-
-	ui = &termUI{
-		gError:     make(chan error, 1),
-		cache:      c,
-		bugTable:   newBugTable(c),
-		showBug:    newShowBug(c),
-		msgPopup:   newMsgPopup(),
-		inputPopup: newInputPopup(),
+func Run(c cache.RepoCacher, opts Options) error {
+	var err error
+
+	ui, err = newTermUI(c, opts)
+	if err != nil {
+		return err
 	}
 
 	ui.activeWindow = ui.bugTable
 
-	initGui(nil)
+	ui.keybindings, err = registerKeybindings()
+	if err != nil {
+		return err
+	}
+
+	warnings, err := ui.keybindings.loadKeymap(c.Repository())
+	if err != nil {
+		return err
+	}
+
+	// render right away from what is cached, and read the rest meanwhile
+	initGui(func(ui *termUI) error {
+		if len(warnings) > 0 {
+			ui.msgPopup.Activate(msgPopupKeymapTitle, strings.Join(warnings, "\n"))
+		}
+		ui.bugTable.refresh()
+		return nil
+	})
 
-	err := <-ui.gError
+	err = <-ui.gError
 
 	if err != nil && err != gocui.ErrQuit {
 		return err
@@ -67,6 +153,33 @@ func Run(repo repository.Repo) error {
 	return nil
 }
 
+// newTermUI create the components of the UI, without starting it
+func newTermUI(c cache.RepoCacher, opts Options) (*termUI, error) {
+	mouse, err := readMouseConfig(c.Repository())
+	if err != nil {
+		return nil, err
+	}
+
+	remote, err := bug.ConfiguredRemote(c.Repository())
+	if err != nil {
+		return nil, err
+	}
+
+	return &termUI{
+		gError:       make(chan error, 1),
+		cache:        c,
+		bugTable:     newBugTable(c, remote),
+		showBug:      newShowBug(c),
+		msgPopup:     newMsgPopup(),
+		inputPopup:   newInputPopup(),
+		historyPopup: newHistoryPopup(),
+		confirmPopup: newConfirmPopup(),
+		helpPopup:    newHelpPopup(),
+		mouse:        mouse,
+		rawMarkdown:  opts.RawMarkdown,
+	}, nil
+}
+
 func initGui(action func(ui *termUI) error) {
 	g, err := gocui.NewGui(gocui.OutputNormal)
 
@@ -75,15 +188,17 @@ func initGui(action func(ui *termUI) error) {
 		return
 	}
 
-	ui.g = g
+	ui.setGui(g)
+	ui.g.Mouse = ui.mouse
+	// a lone esc is a key, rather than the start of an alt combination
+	ui.g.InputEsc = true
 
 	ui.g.SetManagerFunc(layout)
 
-	err = keybindings(ui.g)
+	err = ui.keybindings.apply(ui.g)
 
 	if err != nil {
-		ui.g.Close()
-		ui.g = nil
+		ui.closeGui()
 		ui.gError <- err
 		return
 	}
@@ -91,8 +206,7 @@ func initGui(action func(ui *termUI) error) {
 	if action != nil {
 		err = action(ui)
 		if err != nil {
-			ui.g.Close()
-			ui.g = nil
+			ui.closeGui()
 			ui.gError <- err
 			return
 		}
@@ -125,36 +239,34 @@ func layout(g *gocui.Gui) error {
 		return err
 	}
 
-	return nil
-}
-
-func keybindings(g *gocui.Gui) error {
-	// Quit
-	if err := g.SetKeybinding("", gocui.KeyCtrlC, gocui.ModNone, quit); err != nil {
+	if err := ui.historyPopup.layout(g); err != nil {
 		return err
 	}
 
-	if err := ui.bugTable.keybindings(g); err != nil {
+	if err := ui.helpPopup.layout(g); err != nil {
 		return err
 	}
 
-	if err := ui.showBug.keybindings(g); err != nil {
-		return err
-	}
-
-	if err := ui.msgPopup.keybindings(g); err != nil {
-		return err
-	}
-
-	if err := ui.inputPopup.keybindings(g); err != nil {
+	// last, to be on top of the popup it's asking about
+	if err := ui.confirmPopup.layout(g); err != nil {
 		return err
 	}
 
 	return nil
 }
 
+// quit exit the termUI, after a confirmation if some input would be lost.
+// Quitting again while asked force the exit.
 func quit(g *gocui.Gui, v *gocui.View) error {
-	return gocui.ErrQuit
+	if ui.confirmPopup.active || !ui.inputPopup.dirty(g) {
+		return gocui.ErrQuit
+	}
+
+	ui.confirmPopup.Activate("Quit", "Quit and discard your input?", func(g *gocui.Gui) error {
+		return gocui.ErrQuit
+	})
+
+	return nil
 }
 
 func newBugWithEditor(repo cache.RepoCacher) error {
@@ -167,8 +279,7 @@ func newBugWithEditor(repo cache.RepoCacher) error {
 	// - a custom error (errTerminateMainloop) is used to terminate the original
 	//		instance's mainLoop. This error is then filtered.
 
-	ui.g.Close()
-	ui.g = nil
+	ui.closeGui()
 
 	title, message, err := input.BugCreateEditorInput(ui.cache.Repository(), "", "")
 
@@ -207,10 +318,17 @@ func addCommentWithEditor(bug cache.BugCacher) error {
 	// - a custom error (errTerminateMainloop) is used to terminate the original
 	//		instance's mainLoop. This error is then filtered.
 
-	ui.g.Close()
-	ui.g = nil
+	ui.closeGui()
+
+	repo := ui.cache.Repository()
+	id := bug.Snapshot().Id()
+
+	resume, err := input.ResumeCommentDraft(repo, id)
+	if err != nil {
+		return err
+	}
 
-	message, err := input.BugCommentEditorInput(ui.cache.Repository())
+	message, draft, err := input.BugCommentDraftInput(repo, id, resume)
 
 	if err != nil && err != input.ErrEmptyMessage {
 		return err
@@ -223,6 +341,9 @@ func addCommentWithEditor(bug cache.BugCacher) error {
 		if err != nil {
 			return err
 		}
+
+		// kept until the comment is committed
+		ui.showBug.drafts = append(ui.showBug.drafts, draft)
 	}
 
 	initGui(nil)
@@ -240,8 +361,7 @@ func setTitleWithEditor(bug cache.BugCacher) error {
 	// - a custom error (errTerminateMainloop) is used to terminate the original
 	//		instance's mainLoop. This error is then filtered.
 
-	ui.g.Close()
-	ui.g = nil
+	ui.closeGui()
 
 	title, err := input.BugTitleEditorInput(ui.cache.Repository(), bug.Snapshot().Title)
 
@@ -0,0 +1,126 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/bug/operations"
+	"github.com/MichaelMure/git-bug/cache"
+)
+
+func TestAbbreviateId(t *testing.T) {
+	repo := createRepo(false)
+	defer cleanupRepo(repo)
+
+	bug1, err := operations.Create(rene, "bug1", "message")
+	checkErr(t, err)
+	err = bug1.Commit(repo)
+	checkErr(t, err)
+
+	abbrev, err := bug.AbbreviateId(repo, bug1.Id())
+	checkErr(t, err)
+
+	if len(abbrev) < 7 || !strings.HasPrefix(bug1.Id(), abbrev) {
+		t.Fatalf("invalid abbreviation %s of %s", abbrev, bug1.Id())
+	}
+
+	// the abbreviation resolve to the bug
+	found, err := bug.FindLocalBug(repo, abbrev)
+	checkErr(t, err)
+	if found.Id() != bug1.Id() {
+		t.Fatal("The abbreviation should resolve to the same bug")
+	}
+}
+
+func TestAbbreviateIds(t *testing.T) {
+	ids := []string{
+		"0123456789abcdef0123456789abcdef01234567",
+		"0123456789abcdef0123456789abcdef01234568",
+		"0123456789abcdef0123456789abcdef0123",
+		"01234560000000000000000000000000000000000",
+		"0123457000000000000000000000000000000000",
+		"fedcba9876543210fedcba9876543210fedcba98",
+	}
+
+	abbreviations := bug.AbbreviateIds(ids)
+
+	expected := map[string]string{
+		"0123456789abcdef0123456789abcdef01234567":  "0123456789abcdef0123456789abcdef01234567",
+		"0123456789abcdef0123456789abcdef01234568":  "0123456789abcdef0123456789abcdef01234568",
+		"0123456789abcdef0123456789abcdef0123":      "0123456789abcdef0123456789abcdef0123",
+		"01234560000000000000000000000000000000000": "01234560",
+		"0123457000000000000000000000000000000000":  "0123457",
+		"fedcba9876543210fedcba9876543210fedcba98":  "fedcba9",
+	}
+
+	for id, abbrev := range expected {
+		if abbreviations[id] != abbrev {
+			t.Fatalf("Unexpected abbreviation %s of %s, expected %s", abbreviations[id], id, abbrev)
+		}
+	}
+}
+
+func TestLocalAbbreviations(t *testing.T) {
+	repo := createRepo(false)
+	defer cleanupRepo(repo)
+
+	for _, title := range []string{"bug1", "bug2", "bug3"} {
+		b, err := operations.Create(rene, title, "message")
+		checkErr(t, err)
+		checkErr(t, b.Commit(repo))
+	}
+
+	abbreviations, err := bug.LocalAbbreviations(repo)
+	checkErr(t, err)
+
+	ids, err := bug.ListLocalIds(repo)
+	checkErr(t, err)
+
+	if len(abbreviations) != len(ids) {
+		t.Fatalf("Unexpected abbreviations %v", abbreviations)
+	}
+
+	// the same as computed one by one
+	for _, id := range ids {
+		abbrev, err := bug.AbbreviateId(repo, id)
+		checkErr(t, err)
+		if abbreviations[id] != abbrev {
+			t.Fatalf("Unexpected abbreviation %s of %s, expected %s", abbreviations[id], id, abbrev)
+		}
+	}
+}
+
+func TestCacheHumanId(t *testing.T) {
+	repo := createRepo(false)
+	defer cleanupRepo(repo)
+
+	backend := cache.NewRepoCache(repo)
+	backend.SetAuthor(rene)
+
+	b, err := backend.NewBug("bug1", "message")
+	checkErr(t, err)
+
+	id := b.Snapshot().Id()
+	if b.Snapshot().HumanId() != bug.FormatHumanId(id) {
+		t.Fatalf("Unexpected HumanId %s", b.Snapshot().HumanId())
+	}
+
+	// another bug sharing the first 10 characters of the id
+	other := id[:10] + strings.Repeat("0", len(id)-10)
+	if other == id {
+		other = id[:10] + strings.Repeat("1", len(id)-10)
+	}
+	head, err := repo.ResolveRef("refs/bugs/" + id)
+	checkErr(t, err)
+	checkErr(t, repo.UpdateRef("refs/bugs/"+other, head))
+
+	if backend.HumanId(other) != other[:11] {
+		t.Fatalf("Unexpected HumanId %s of %s", backend.HumanId(other), other)
+	}
+
+	b.ClearSnapshot()
+	if b.Snapshot().HumanId() != id[:11] {
+		t.Fatalf("The HumanId should be lengthened, got %s", b.Snapshot().HumanId())
+	}
+}
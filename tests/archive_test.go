@@ -0,0 +1,153 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/bug/operations"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func addCommentAt(b *bug.Bug, message string, unixTime int64) {
+	op := operations.NewAddCommentOp(isaac, message, nil)
+	op.UnixTime = unixTime
+	b.Append(op)
+}
+
+func TestArchiveComments(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+
+	b, err := operations.Create(rene, "title", "description")
+	checkErr(t, err)
+	addCommentAt(b, "old 1", 1000)
+	addCommentAt(b, "old 2", 2000)
+	addCommentAt(b, "recent", 3000)
+	checkErr(t, b.Commit(repo))
+
+	hashes, err := b.Compile().CommentHashes()
+	checkErr(t, err)
+
+	_, err = operations.ArchiveComments(repo, b, rene, time.Unix(500, 0))
+	if err == nil {
+		t.Fatal("Archiving without any old comment should fail")
+	}
+
+	count, err := operations.ArchiveComments(repo, b, rene, time.Unix(3000, 0))
+	checkErr(t, err)
+	if count != 2 {
+		t.Fatalf("Expected 2 archived comments, got %d", count)
+	}
+	checkErr(t, b.Commit(repo))
+
+	read, err := bug.ReadLocalBug(repo, b.Id())
+	checkErr(t, err)
+	snap := read.Compile()
+
+	if len(snap.Comments) != 2 || snap.Comments[0].Message != "description" ||
+		snap.Comments[1].Message != "recent" || snap.ArchivedCount != 2 {
+		t.Fatalf("Unexpected snapshot %+v", snap)
+	}
+	if snap.Summary() != "C:3 L:0" {
+		t.Fatalf("The archived comments should be counted, got %s", snap.Summary())
+	}
+
+	// the comments are still identified by their operation
+	kept, err := snap.CommentHashes()
+	checkErr(t, err)
+	if len(kept) != 2 || kept[0] != hashes[0] || kept[1] != hashes[3] {
+		t.Fatalf("Unexpected comment hashes %v", kept)
+	}
+	if !snap.IsArchivedComment(hashes[1]) || snap.IsArchivedComment(hashes[3]) {
+		t.Fatal("Only the old comments should be archived")
+	}
+
+	// the edits of the kept comments still work
+	checkErr(t, operations.DeleteComment(read, isaac, string(hashes[3]), false))
+	if !read.Compile().Comments[1].IsDeleted() {
+		t.Fatal("The recent comment should be deleted")
+	}
+
+	// the archive blob is referenced by the bug
+	if files := snap.Operations[len(snap.Operations)-1].Files(); len(files) != 1 || files[0] != snap.Archives[0] {
+		t.Fatalf("Unexpected files %v", files)
+	}
+}
+
+func TestLoadArchivedComments(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+
+	b, err := operations.Create(rene, "title", "description")
+	checkErr(t, err)
+	addCommentAt(b, "old 1", 1000)
+	addCommentAt(b, "old 2", 2000)
+	addCommentAt(b, "recent", 3000)
+	checkErr(t, b.Commit(repo))
+
+	_, err = operations.ArchiveComments(repo, b, rene, time.Unix(1500, 0))
+	checkErr(t, err)
+	checkErr(t, b.Commit(repo))
+
+	_, err = operations.ArchiveComments(repo, b, rene, time.Unix(2500, 0))
+	checkErr(t, err)
+	checkErr(t, b.Commit(repo))
+
+	read, err := bug.ReadLocalBug(repo, b.Id())
+	checkErr(t, err)
+	snap := read.Compile()
+
+	if len(snap.Comments) != 2 || len(snap.Archives) != 2 || snap.ArchivedCount != 2 {
+		t.Fatalf("Unexpected snapshot %+v", snap)
+	}
+
+	// read back only when asked, oldest first
+	archived, err := snap.LoadArchivedComments(repo)
+	checkErr(t, err)
+
+	if len(archived) != 2 || archived[0].Message != "old 1" || archived[1].Message != "old 2" {
+		t.Fatalf("Unexpected archived comments %+v", archived)
+	}
+	if archived[0].Author != isaac || archived[0].UnixTime != 1000 {
+		t.Fatalf("Unexpected archived comment %+v", archived[0])
+	}
+
+	_, err = snap.LoadArchivedComments(repository.NewMockRepoForTest())
+	if err == nil {
+		t.Fatal("Loading from a repository without the archive should fail")
+	}
+}
+
+func TestArchiveCommentsConcurrent(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+
+	b, err := operations.Create(rene, "title", "description")
+	checkErr(t, err)
+	addCommentAt(b, "old", 1000)
+	addCommentAt(b, "recent", 3000)
+	checkErr(t, b.Commit(repo))
+
+	comments, hashes, err := b.Compile().ArchivedComments(2500)
+	checkErr(t, err)
+	archive, err := bug.StoreArchive(repo, comments)
+	checkErr(t, err)
+
+	// an older comment merged from a remote, ordered before the archive
+	// made without it
+	addCommentAt(b, "concurrent", 2000)
+	b.Append(operations.NewArchiveCommentsOp(rene, 2500, hashes, archive))
+	checkErr(t, b.Commit(repo))
+
+	read, err := bug.ReadLocalBug(repo, b.Id())
+	checkErr(t, err)
+	snap := read.Compile()
+
+	if len(snap.Comments) != 3 || snap.Comments[2].Message != "concurrent" || snap.ArchivedCount != 1 {
+		t.Fatalf("Only the comments in the archive should be archived, got %+v", snap.Comments)
+	}
+
+	archived, err := snap.LoadArchivedComments(repo)
+	checkErr(t, err)
+	if len(archived) != 1 || archived[0].Message != "old" {
+		t.Fatalf("Unexpected archived comments %+v", archived)
+	}
+}
@@ -0,0 +1,84 @@
+package tests
+
+import (
+	"bytes"
+	"io/ioutil"
+	"reflect"
+	"testing"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/bug/operations"
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util"
+)
+
+func TestAttachments(t *testing.T) {
+	gitRepo := createRepo(false)
+	defer cleanupRepo(gitRepo)
+
+	for _, repo := range []repository.Repo{gitRepo, repository.NewMockRepoForTest()} {
+		png := append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 1000)...)
+		log := bytes.Repeat([]byte("a log line\n"), 10000)
+
+		pngHash, err := repo.StoreData(png)
+		checkErr(t, err)
+		logHash, err := repo.StoreData(log)
+		checkErr(t, err)
+
+		b, err := operations.CreateWithFiles(rene, "title", "message", []util.Hash{pngHash})
+		checkErr(t, err)
+		checkErr(t, operations.Comment(b, rene, "no file"))
+		checkErr(t, operations.CommentWithFiles(b, rene, "the log", []util.Hash{logHash, pngHash}))
+		checkErr(t, b.Commit(repo))
+
+		// the files are kept in the operations, numbered across the comments
+		read, err := bug.ReadLocalBug(repo, b.Id())
+		checkErr(t, err)
+
+		attachments := read.Compile().Attachments()
+		if !reflect.DeepEqual(attachments, []util.Hash{pngHash, logHash, pngHash}) {
+			t.Fatalf("Unexpected attachments %v", attachments)
+		}
+
+		attachment, err := bug.ReadAttachment(repo, logHash)
+		checkErr(t, err)
+		if attachment.Size != int64(len(log)) || attachment.ContentType != "text/plain; charset=utf-8" {
+			t.Fatalf("Unexpected attachment %+v", attachment)
+		}
+		if attachment.Filename() != string(logHash)+".txt" || attachment.HumanSize() != "110 kB" {
+			t.Fatalf("Unexpected name %s or size %s", attachment.Filename(), attachment.HumanSize())
+		}
+
+		attachment, err = bug.ReadAttachment(repo, pngHash)
+		checkErr(t, err)
+		if attachment.ContentType != "image/png" || attachment.Filename() != string(pngHash)+".png" {
+			t.Fatalf("Unexpected attachment %+v", attachment)
+		}
+
+		reader, err := repo.OpenData(logHash)
+		checkErr(t, err)
+		data, err := ioutil.ReadAll(reader)
+		checkErr(t, err)
+		checkErr(t, reader.Close())
+		if !bytes.Equal(data, log) {
+			t.Fatal("The streamed data should be the stored one")
+		}
+
+		// closed before the end
+		reader, err = repo.OpenData(logHash)
+		checkErr(t, err)
+		checkErr(t, reader.Close())
+
+		if _, err := bug.ReadAttachment(repo, util.Hash("0123456789012345678901234567890123456789")); err == nil {
+			t.Fatal("An unknown file should be an error")
+		}
+
+		// the files of a deleted comment are hidden
+		hashes, err := read.Compile().CommentHashes()
+		checkErr(t, err)
+		checkErr(t, operations.DeleteComment(read, rene, string(hashes[2]), false))
+		if attachments := read.Compile().Attachments(); len(attachments) != 1 || attachments[0] != pngHash {
+			t.Fatalf("Unexpected attachments %v", attachments)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/bug/operations"
+)
+
+func TestAttribution(t *testing.T) {
+	b, err := operations.Create(rene, "title", "message")
+	checkErr(t, err)
+	checkErr(t, operations.ChangeLabels(nil, b, rene, []string{"bug", "UI"}, nil))
+	checkErr(t, operations.SetTitle(b, isaac, "new title"))
+	operations.Close(b, isaac)
+	checkErr(t, operations.ChangeLabels(nil, b, isaac, []string{"docs"}, []string{"UI"}))
+	checkErr(t, operations.SetCustomField(b, rene, "severity", "low"))
+	checkErr(t, operations.SetCustomField(b, isaac, "severity", "high"))
+	checkErr(t, operations.SetCustomField(b, rene, "component", "cli"))
+	checkErr(t, operations.SetCustomField(b, isaac, "component", ""))
+	// an unrelated edit doesn't change the attributions
+	checkErr(t, operations.Comment(b, rene, "comment"))
+
+	snap := b.Compile()
+	attribution := snap.Attribution()
+
+	hash := func(index int) string {
+		h, err := bug.HashOperation(snap.Operations[index])
+		checkErr(t, err)
+		return string(h)
+	}
+
+	expected := map[string]int{
+		"title":          2,
+		"status":         3,
+		"priority":       0,
+		"label:bug":      1,
+		"label:docs":     4,
+		"field:severity": 6,
+	}
+
+	if len(attribution) != len(expected) {
+		t.Fatalf("Unexpected attribution %+v", attribution)
+	}
+
+	for field, index := range expected {
+		a, ok := attribution[field]
+		if !ok {
+			t.Fatalf("Missing attribution of %s", field)
+		}
+		op := snap.Operations[index]
+		if string(a.OpId) != hash(index) || a.Author != op.GetAuthor() || !a.Time.Equal(op.Time()) {
+			t.Fatalf("The %s should be attributed to the operation %d, got %+v", field, index, a)
+		}
+	}
+
+	// without operations, like the decoded snapshots
+	if len(bug.NewSnapshot(snap.Id()).Attribution()) != 0 {
+		t.Fatal("A snapshot without operations should have no attribution")
+	}
+}
@@ -109,7 +109,7 @@ func TestPushPull(t *testing.T) {
 	}
 }
 
-func checkErr(t *testing.T, err error) {
+func checkErr(t testing.TB, err error) {
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -0,0 +1,84 @@
+package tests
+
+import (
+	"os"
+	"testing"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/bug/operations"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func TestBugIdFromContent(t *testing.T) {
+	create := operations.NewCreateOp(rene, "title", "message", nil)
+
+	var ids []string
+	for i := 0; i < 2; i++ {
+		b := bug.NewBug()
+		b.Append(create)
+		checkErr(t, b.Commit(repository.NewMockRepoForTest()))
+		ids = append(ids, b.Id())
+	}
+
+	// the same create operation with the same clock give the same id, even
+	// if stored in different commits
+	if ids[0] != ids[1] || !bug.IsValidId(ids[0]) {
+		t.Fatalf("Unexpected ids %v", ids)
+	}
+
+	other := bug.NewBug()
+	other.Append(operations.NewCreateOp(rene, "other title", "message", nil))
+	checkErr(t, other.Commit(repository.NewMockRepoForTest()))
+
+	if other.Id() == ids[0] {
+		t.Fatal("A different creation should give a different id")
+	}
+}
+
+func TestBugIdStableAfterRebase(t *testing.T) {
+	repoA, repoB, remote := setupRepos(t)
+	defer cleanupRepos(repoA, repoB, remote)
+
+	bugA, err := operations.Create(rene, "bug1", "message")
+	checkErr(t, err)
+	checkErr(t, bugA.Commit(repoA))
+	id := bugA.Id()
+
+	_, err = bug.Push(repoA, "origin")
+	checkErr(t, err)
+	checkErr(t, bug.Pull(repoB, os.Stdout, "origin"))
+
+	// concurrent edits, B is rebased on top of A when pulling
+	checkErr(t, operations.Comment(bugA, rene, "from A"))
+	checkErr(t, bugA.Commit(repoA))
+	_, err = bug.Push(repoA, "origin")
+	checkErr(t, err)
+
+	bugB, err := bug.ReadLocalBug(repoB, id)
+	checkErr(t, err)
+	checkErr(t, operations.Comment(bugB, isaac, "from B"))
+	checkErr(t, bugB.Commit(repoB))
+	beforeRebase := bugB.LastCommitHash()
+
+	checkErr(t, bug.Pull(repoB, os.Stdout, "origin"))
+	_, err = bug.Push(repoB, "origin")
+	checkErr(t, err)
+	checkErr(t, bug.Pull(repoA, os.Stdout, "origin"))
+
+	for _, repo := range []repository.Repo{repoA, repoB} {
+		ids, err := bug.ListLocalIds(repo)
+		checkErr(t, err)
+		if len(ids) != 1 || ids[0] != id {
+			t.Fatalf("The id should be kept, got %v", ids)
+		}
+
+		merged, err := bug.ReadLocalBug(repo, id)
+		checkErr(t, err)
+		if merged.Id() != id || len(merged.Compile().Comments) != 3 {
+			t.Fatalf("Unexpected bug %+v", merged.Compile())
+		}
+		if merged.LastCommitHash() == beforeRebase {
+			t.Fatal("The commit of B should be rebased")
+		}
+	}
+}
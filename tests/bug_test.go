@@ -1,8 +1,13 @@
 package tests
 
 import (
-	"github.com/MichaelMure/git-bug/bug"
+	"reflect"
 	"testing"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/bug/operations"
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util"
 )
 
 func TestBugId(t *testing.T) {
@@ -19,6 +24,29 @@ func TestBugId(t *testing.T) {
 	bug1.Id()
 }
 
+func TestBugIdSafe(t *testing.T) {
+	bug1 := bug.NewBug()
+	bug1.Append(createOp)
+
+	if _, err := bug1.IdSafe(); err != bug.ErrBugNotStored {
+		t.Fatalf("Expected ErrBugNotStored on a bug not stored yet, got %v", err)
+	}
+
+	err := bug1.Commit(mockRepo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id, err := bug1.IdSafe()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if id != bug1.Id() {
+		t.Fatalf("Expected the id %s, got %s", bug1.Id(), id)
+	}
+}
+
 func TestBugValidity(t *testing.T) {
 	bug1 := bug.NewBug()
 
@@ -73,3 +101,128 @@ func TestBugValidity(t *testing.T) {
 //		t.Fatalf("%v different than %v", bug1, bug2)
 //	}
 //}
+
+func TestBugCommitHash(t *testing.T) {
+	bug1 := bug.NewBug()
+
+	if bug1.FirstCommitHash() != "" || bug1.LastCommitHash() != "" {
+		t.Fatal("A bug never stored should have no commit hash")
+	}
+
+	bug1.Append(createOp)
+	err := bug1.Commit(mockRepo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first := bug1.FirstCommitHash()
+
+	if first == "" || string(first) == bug1.Id() {
+		t.Fatal("The bug id should be derived from the creation, not the first commit hash")
+	}
+
+	if bug1.LastCommitHash() != first {
+		t.Fatal("With a single commit, the first and last commit should be the same")
+	}
+
+	bug1.Append(setTitleOp)
+	err = bug1.Commit(mockRepo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bug1.Append(addCommentOp)
+	err = bug1.Commit(mockRepo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hashes, err := mockRepo.ListCommits("refs/bugs/" + bug1.Id())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(hashes) != 3 {
+		t.Fatalf("Unexpected number of commits %d", len(hashes))
+	}
+
+	if bug1.FirstCommitHash() != hashes[0] || bug1.FirstCommitHash() != first {
+		t.Fatal("Unexpected first commit hash")
+	}
+
+	if bug1.LastCommitHash() != hashes[2] {
+		t.Fatal("Unexpected last commit hash")
+	}
+
+	bug2, err := bug.ReadLocalBug(mockRepo, bug1.Id())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if bug2.FirstCommitHash() != bug1.FirstCommitHash() || bug2.LastCommitHash() != bug1.LastCommitHash() {
+		t.Fatal("Commit hashes should be the same after reading the bug")
+	}
+}
+
+func TestBugOperationsByAuthor(t *testing.T) {
+	b, err := operations.Create(rene, "title", "message")
+	checkErr(t, err)
+	checkErr(t, operations.Comment(b, isaac, "first"))
+	checkErr(t, operations.Comment(b, isaac, "second"))
+
+	// the same person, with another form of their email
+	checkErr(t, operations.SetTitle(b, bug.Person{Name: "René", Email: "Rene+bugs@Descartes.fr"}, "new title"))
+	checkErr(t, b.Commit(mockRepo))
+
+	// not committed yet
+	checkErr(t, operations.Comment(b, isaac, "staged"))
+	operations.Close(b, bug.Person{Name: "Anonymous"})
+
+	expected := map[string]int{
+		"rene@descartes.fr": 2,
+		"isaac@newton.uk":   2,
+	}
+	if counts := b.OperationsByAuthor(); !reflect.DeepEqual(counts, expected) {
+		t.Fatalf("Expected %v, got %v", expected, counts)
+	}
+
+	checkErr(t, b.Commit(mockRepo))
+
+	expected["isaac@newton.uk"] = 3
+	expected["Anonymous"] = 1
+	if counts := b.OperationsByAuthor(); !reflect.DeepEqual(counts, expected) {
+		t.Fatalf("Expected %v, got %v", expected, counts)
+	}
+}
+
+// listingRepo tell if a listing of the refs is running
+type listingRepo struct {
+	repository.Repo
+	listing bool
+}
+
+func (r *listingRepo) ForEachRef(prefix string, fn func(ref string, hash util.Hash) error) error {
+	r.listing = true
+	defer func() { r.listing = false }()
+	return r.Repo.ForEachRef(prefix, fn)
+}
+
+func TestReadAllBugsListFirst(t *testing.T) {
+	repo := &listingRepo{Repo: repository.NewMockRepoForTest()}
+
+	for _, title := range []string{"bug1", "bug2"} {
+		b, err := operations.Create(rene, title, "message")
+		checkErr(t, err)
+		checkErr(t, b.Commit(repo))
+	}
+
+	// a consumer stopping after the first bug
+	for streamed := range bug.ReadAllLocalBugs(repo) {
+		checkErr(t, streamed.Err)
+
+		if repo.listing {
+			t.Fatal("The refs should be listed before the bugs are sent")
+		}
+		break
+	}
+}
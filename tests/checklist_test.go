@@ -0,0 +1,171 @@
+package tests
+
+import (
+	"os"
+	"testing"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/bug/operations"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func defineChecklist(t *testing.T, repo repository.Repo, name string, items ...string) bug.ChecklistDefinition {
+	registry, err := bug.ReadChecklistRegistry(repo)
+	checkErr(t, err)
+	checkErr(t, registry.Define(name, items))
+	checkErr(t, registry.Write(repo))
+
+	registry, err = bug.ReadChecklistRegistry(repo)
+	checkErr(t, err)
+	definition, _ := registry.Lookup(name)
+	return definition
+}
+
+func TestChecklist(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+
+	registry, err := bug.ReadChecklistRegistry(repo)
+	checkErr(t, err)
+	for _, invalid := range []string{"", "tri age", "triage:done", "1st"} {
+		if registry.Define(invalid, []string{"item"}) == nil {
+			t.Fatalf("The name \"%s\" should be rejected", invalid)
+		}
+	}
+	if registry.Define("triage", []string{"a=b"}) == nil || registry.Define("triage", []string{"a", "a"}) == nil {
+		t.Fatal("Invalid items should be rejected")
+	}
+
+	triage := defineChecklist(t, repo, "triage", "reproduced", "regression", "has test")
+
+	b, err := operations.Create(rene, "title", "message")
+	checkErr(t, err)
+
+	err = operations.SetChecklist(b, rene, "triage", triage, map[string]bug.ChecklistState{"nope": bug.ChecklistDone})
+	if err == nil {
+		t.Fatal("An unknown item should be rejected")
+	}
+
+	// the missing items are added as pending the first time
+	checkErr(t, operations.SetChecklist(b, rene, "triage", triage, map[string]bug.ChecklistState{
+		"regression": bug.ChecklistNA,
+	}))
+	checkErr(t, operations.SetChecklist(b, rene, "triage", triage, map[string]bug.ChecklistState{
+		"reproduced": bug.ChecklistDone,
+		"regression": bug.ChecklistNA,
+	}))
+	checkErr(t, b.Commit(repo))
+
+	snap := b.Compile()
+	if len(snap.Operations) != 3 {
+		t.Fatalf("Expected 3 operations, got %d", len(snap.Operations))
+	}
+	// only the changed item is carried
+	if items := snap.Operations[2].(operations.SetChecklistOperation).Items; len(items) != 1 {
+		t.Fatalf("Unexpected items %+v", items)
+	}
+
+	checklist, ok := snap.Checklist("triage")
+	if !ok || len(checklist.Items) != 3 || checklist.Items[2].Name != "has test" {
+		t.Fatalf("Unexpected checklist %+v", checklist)
+	}
+	if done, total := checklist.Completion(); done != 1 || total != 2 {
+		t.Fatalf("Expected 1/2, got %d/%d", done, total)
+	}
+
+	// unchanged, nothing appended
+	checkErr(t, operations.SetChecklist(b, rene, "triage", triage, map[string]bug.ChecklistState{
+		"reproduced": bug.ChecklistDone,
+	}))
+	if b.HasPendingOp() {
+		t.Fatal("No operation should be appended without any change")
+	}
+
+	pending, err := cache.ParseQuery("checklist:triage:pending")
+	checkErr(t, err)
+	done, err := cache.ParseQuery("checklist:triage:done")
+	checkErr(t, err)
+	if !pending.Match(&snap) || done.Match(&snap) {
+		t.Fatal("The checklist should be pending")
+	}
+
+	checkErr(t, operations.SetChecklist(b, rene, "triage", triage, map[string]bug.ChecklistState{
+		"has test": bug.ChecklistDone,
+	}))
+	snap = b.Compile()
+	if pending.Match(&snap) || !done.Match(&snap) {
+		t.Fatal("The checklist should be done")
+	}
+
+	for _, invalid := range []string{"checklist:triage", "checklist::done", "checklist:triage:na", "checklist:triage:maybe"} {
+		if _, err := cache.ParseQuery(invalid); err == nil {
+			t.Fatalf("The query \"%s\" should be rejected", invalid)
+		}
+	}
+}
+
+func TestChecklistPushPull(t *testing.T) {
+	repoA, repoB, remote := setupRepos(t)
+	defer cleanupRepos(repoA, repoB, remote)
+
+	triage := defineChecklist(t, repoA, "triage", "reproduced", "regression", "has test")
+
+	b, err := operations.Create(rene, "bug1", "message")
+	checkErr(t, err)
+	checkErr(t, operations.SetChecklist(b, rene, "triage", triage, nil))
+	checkErr(t, b.Commit(repoA))
+
+	_, err = bug.Push(repoA, "origin")
+	checkErr(t, err)
+	checkErr(t, bug.Pull(repoB, os.Stdout, "origin"))
+
+	// the definitions are pulled with the bugs
+	registry, err := bug.ReadChecklistRegistry(repoB)
+	checkErr(t, err)
+	if names := registry.Names(); len(names) != 1 || names[0] != "triage" {
+		t.Fatalf("Unexpected checklists %v", names)
+	}
+
+	// concurrent updates of different items
+	bugA, err := bug.ReadLocalBug(repoA, b.Id())
+	checkErr(t, err)
+	checkErr(t, operations.SetChecklist(bugA, rene, "triage", triage, map[string]bug.ChecklistState{
+		"reproduced": bug.ChecklistDone,
+	}))
+	checkErr(t, bugA.Commit(repoA))
+	defineChecklist(t, repoA, "review", "approved")
+
+	bugB, err := bug.ReadLocalBug(repoB, b.Id())
+	checkErr(t, err)
+	checkErr(t, operations.SetChecklist(bugB, isaac, "triage", triage, map[string]bug.ChecklistState{
+		"regression": bug.ChecklistNA,
+	}))
+	checkErr(t, bugB.Commit(repoB))
+	defineChecklist(t, repoB, "release", "documented")
+
+	_, err = bug.Push(repoA, "origin")
+	checkErr(t, err)
+	checkErr(t, bug.Pull(repoB, os.Stdout, "origin"))
+	_, err = bug.Push(repoB, "origin")
+	checkErr(t, err)
+	checkErr(t, bug.Pull(repoA, os.Stdout, "origin"))
+
+	for _, repo := range []repository.Repo{repoA, repoB} {
+		merged, err := bug.ReadLocalBug(repo, b.Id())
+		checkErr(t, err)
+
+		checklist, _ := merged.Compile().Checklist("triage")
+		reproduced, _ := checklist.Item("reproduced")
+		regression, _ := checklist.Item("regression")
+		testState, _ := checklist.Item("has test")
+		if reproduced != bug.ChecklistDone || regression != bug.ChecklistNA || testState != bug.ChecklistPending {
+			t.Fatalf("Both updates should be kept, got %+v", checklist)
+		}
+
+		registry, err := bug.ReadChecklistRegistry(repo)
+		checkErr(t, err)
+		if names := registry.Names(); len(names) != 3 {
+			t.Fatalf("Unexpected checklists %v", names)
+		}
+	}
+}
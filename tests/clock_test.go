@@ -0,0 +1,136 @@
+package tests
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/bug/operations"
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util"
+)
+
+func TestPullWitnessClocks(t *testing.T) {
+	repoA, repoB, remote := setupRepos(t)
+	defer cleanupRepos(repoA, repoB, remote)
+
+	bug1, err := operations.Create(rene, "bug1", "message")
+	checkErr(t, err)
+	err = bug1.Commit(repoA)
+	checkErr(t, err)
+
+	// A --> remote --> B
+	_, err = bug.Push(repoA, "origin")
+	checkErr(t, err)
+	err = bug.Pull(repoB, os.Stdout, "origin")
+	checkErr(t, err)
+
+	// A move its clock forward
+	for i := 0; i < 5; i++ {
+		err = operations.Comment(bug1, rene, "message")
+		checkErr(t, err)
+		err = bug1.Commit(repoA)
+		checkErr(t, err)
+	}
+
+	// A --> remote --> B, merged in the existing bug
+	_, err = bug.Push(repoA, "origin")
+	checkErr(t, err)
+	err = bug.Pull(repoB, os.Stdout, "origin")
+	checkErr(t, err)
+
+	timeA, err := repoA.EditTimeIncrement()
+	checkErr(t, err)
+	timeB, err := repoB.EditTimeIncrement()
+	checkErr(t, err)
+
+	if timeB < timeA {
+		t.Fatalf("The edit clock of B (%d) should be ahead of the edits of A (%d)", timeB, timeA)
+	}
+}
+
+func TestConcurrentClockFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	checkErr(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "edit-clock")
+
+	const writers = 8
+	const increments = 50
+
+	var mu sync.Mutex
+	seen := make(map[util.LamportTime]bool)
+
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+
+		// each clock act as a different process sharing the file
+		go func(clock *util.PersistedLamport) {
+			defer wg.Done()
+
+			for j := 0; j < increments; j++ {
+				time, err := clock.Increment()
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				mu.Lock()
+				if seen[time] {
+					t.Errorf("The time %d has been given twice", time)
+				}
+				seen[time] = true
+				mu.Unlock()
+
+				// witness might write an older value
+				if err := clock.Witness(time / 2); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}(util.NewPersistedLamport(path))
+	}
+	wg.Wait()
+
+	clock, err := util.LoadPersistedLamport(path)
+	checkErr(t, err)
+
+	if len(seen) != writers*increments || clock.Time() != writers*increments+1 {
+		t.Fatalf("Unexpected clock %d after %d increments", clock.Time(), len(seen))
+	}
+}
+
+func TestCorruptedClockRebuild(t *testing.T) {
+	repo := createRepo(false)
+	defer cleanupRepo(repo)
+
+	b, err := operations.Create(rene, "title", "message")
+	checkErr(t, err)
+	checkErr(t, b.Commit(repo))
+
+	for _, content := range []string{"garbage", "", "42"} {
+		path := filepath.Join(repo.GetPath(), ".git", "git-bug", "edit-clock")
+		checkErr(t, ioutil.WriteFile(path, []byte(content), 0644))
+
+		reopened, err := repository.NewGitRepo(repo.GetPath(), bug.Witnesser)
+		checkErr(t, err)
+
+		time, err := reopened.EditTimeIncrement()
+		checkErr(t, err)
+		if time < 2 {
+			t.Fatalf("The clock should be rebuilt from the bugs, got %d", time)
+		}
+
+		data, err := ioutil.ReadFile(path)
+		checkErr(t, err)
+		if !strings.HasPrefix(string(data), "git-bug-clock 1\n") {
+			t.Fatalf("The clock should be written in the current format, got %q", data)
+		}
+	}
+}
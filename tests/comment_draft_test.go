@@ -0,0 +1,96 @@
+package tests
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MichaelMure/git-bug/input"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+// useEditor make the editor write the given text in the file, or leave it
+// untouched if empty
+func useEditor(t *testing.T, repo *repository.GitRepo, text string) {
+	script := "#!/bin/sh\n"
+	if text != "" {
+		script += "printf '" + text + "' > \"$1\"\n"
+	}
+
+	path := filepath.Join(repo.GetPath(), "editor.sh")
+	checkErr(t, ioutil.WriteFile(path, []byte(script), 0755))
+	checkErr(t, os.Setenv("GIT_EDITOR", path))
+}
+
+func TestCommentDraft(t *testing.T) {
+	repo := createRepo(false)
+	defer cleanupRepo(repo)
+	defer os.Unsetenv("GIT_EDITOR")
+
+	const id = "0123456789abcdef0123456789abcdef01234567"
+
+	useEditor(t, repo, "first draft")
+	message, first, err := input.BugCommentDraftInput(repo, id, nil)
+	checkErr(t, err)
+	if message != "first draft" || first.Name != id || first.BugId != id {
+		t.Fatalf("Unexpected draft %+v with message %s", first, message)
+	}
+	if filepath.Dir(first.Path) != filepath.Join(repo.GetCommonDir(), "git-bug", "drafts") {
+		t.Fatalf("Unexpected draft path %s", first.Path)
+	}
+
+	// another one at the same time, like from another terminal
+	useEditor(t, repo, "second draft")
+	_, second, err := input.BugCommentDraftInput(repo, id, nil)
+	checkErr(t, err)
+	if second.Name != id+".2" || second.BugId != id {
+		t.Fatalf("The second draft should be suffixed, got %+v", second)
+	}
+
+	drafts, err := input.BugCommentDrafts(repo, id)
+	checkErr(t, err)
+	if len(drafts) != 2 {
+		t.Fatalf("Expected both drafts, got %+v", drafts)
+	}
+
+	drafts, err = input.BugCommentDrafts(repo, "fedcba9876543210fedcba9876543210fedcba98")
+	checkErr(t, err)
+	if len(drafts) != 0 {
+		t.Fatalf("Unexpected drafts of another bug %+v", drafts)
+	}
+
+	// resumed in place
+	useEditor(t, repo, "first draft, resumed")
+	message, resumed, err := input.BugCommentDraftInput(repo, id, &first)
+	checkErr(t, err)
+	if message != "first draft, resumed" || resumed.Path != first.Path {
+		t.Fatalf("Unexpected resumed draft %+v with message %s", resumed, message)
+	}
+
+	saved, err := resumed.Message()
+	checkErr(t, err)
+	if saved != message {
+		t.Fatalf("The draft should be kept once written, got %s", saved)
+	}
+
+	// an empty message abort, without keeping the draft
+	useEditor(t, repo, "")
+	_, empty, err := input.BugCommentDraftInput(repo, id, nil)
+	if err != input.ErrEmptyMessage {
+		t.Fatalf("Expected an empty message, got %v", err)
+	}
+	if _, err := os.Stat(empty.Path); !os.IsNotExist(err) {
+		t.Fatal("The empty draft should be removed")
+	}
+
+	// once committed
+	checkErr(t, first.Remove())
+	checkErr(t, second.Remove())
+
+	drafts, err = input.ListCommentDrafts(repo)
+	checkErr(t, err)
+	if len(drafts) != 0 {
+		t.Fatalf("Unexpected drafts %+v", drafts)
+	}
+}
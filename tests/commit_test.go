@@ -0,0 +1,53 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/bug/operations"
+)
+
+func TestCommitSkipEmpty(t *testing.T) {
+	b, err := operations.Create(rene, "title", "message")
+	checkErr(t, err)
+	checkErr(t, b.CommitWithOptions(mockRepo, bug.CommitOptions{SkipEmpty: true}))
+
+	head := b.LastCommitHash()
+
+	// the label is added then removed in the same batch
+	checkErr(t, operations.ChangeLabels(nil, b, rene, []string{"ui"}, nil))
+	checkErr(t, operations.ChangeLabels(nil, b, isaac, nil, []string{"ui"}))
+	checkErr(t, operations.SetTitle(b, rene, "other"))
+	checkErr(t, operations.SetTitle(b, rene, "title"))
+
+	err = b.CommitWithOptions(mockRepo, bug.CommitOptions{SkipEmpty: true})
+	if err != bug.ErrEmptyCommit {
+		t.Fatalf("Expected ErrEmptyCommit, got %v", err)
+	}
+	if b.LastCommitHash() != head || b.HasPendingOp() {
+		t.Fatal("The pending operations should be discarded without commit")
+	}
+	if ops := b.Compile().Operations; len(ops) != 1 {
+		t.Fatalf("Expected only the create operation, got %d", len(ops))
+	}
+
+	// a batch with an actual change is committed
+	checkErr(t, operations.ChangeLabels(nil, b, rene, []string{"ui"}, nil))
+	checkErr(t, operations.ChangeLabels(nil, b, rene, []string{"bug"}, nil))
+	checkErr(t, operations.ChangeLabels(nil, b, rene, nil, []string{"ui"}))
+	checkErr(t, b.CommitWithOptions(mockRepo, bug.CommitOptions{SkipEmpty: true}))
+
+	if b.LastCommitHash() == head {
+		t.Fatal("The changes should be committed")
+	}
+	head = b.LastCommitHash()
+
+	// without the option, a net no-op batch is still committed
+	checkErr(t, operations.ChangeLabels(nil, b, rene, []string{"ui"}, nil))
+	checkErr(t, operations.ChangeLabels(nil, b, rene, nil, []string{"ui"}))
+	checkErr(t, b.Commit(mockRepo))
+
+	if b.LastCommitHash() == head {
+		t.Fatal("The operations should be committed without SkipEmpty")
+	}
+}
@@ -0,0 +1,80 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/MichaelMure/git-bug/bug/operations"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func TestSetCustomField(t *testing.T) {
+	bug1, err := operations.Create(rene, "title", "message")
+	checkErr(t, err)
+
+	checkErr(t, operations.SetCustomField(bug1, rene, "severity", "low"))
+	checkErr(t, operations.SetCustomField(bug1, rene, "component", "ui"))
+
+	snap := bug1.Compile()
+	if snap.CustomFields["severity"] != "low" || snap.CustomFields["component"] != "ui" {
+		t.Fatal("The fields should be set")
+	}
+
+	// overwrite
+	checkErr(t, operations.SetCustomField(bug1, rene, "severity", "high"))
+
+	snap = bug1.Compile()
+	if snap.CustomFields["severity"] != "high" {
+		t.Fatal("The field should be overwritten")
+	}
+
+	// remove
+	checkErr(t, operations.SetCustomField(bug1, rene, "component", ""))
+
+	snap = bug1.Compile()
+	if _, ok := snap.CustomFields["component"]; ok {
+		t.Fatal("The field should be removed")
+	}
+	if len(snap.CustomFields) != 1 {
+		t.Fatal("The other fields should be kept")
+	}
+
+	for _, key := range []string{"", "a=b", "a:b", "a b"} {
+		if operations.SetCustomField(bug1, rene, key, "value") == nil {
+			t.Fatalf("The field name %q should be rejected", key)
+		}
+	}
+}
+
+func TestQueryCustomField(t *testing.T) {
+	backend := cache.NewRepoCache(repository.NewMockRepoForTest())
+	backend.SetAuthor(rene)
+
+	high, err := backend.NewBug("high", "message")
+	checkErr(t, err)
+	checkErr(t, high.SetCustomField("severity", "high"))
+	checkErr(t, high.Commit())
+
+	low, err := backend.NewBug("low", "message")
+	checkErr(t, err)
+	checkErr(t, low.SetCustomField("severity", "low"))
+	checkErr(t, low.Commit())
+
+	_, err = backend.NewBug("none", "message")
+	checkErr(t, err)
+
+	query, err := cache.ParseQuery("status:open field:severity=high")
+	checkErr(t, err)
+
+	result, err := backend.Search(query.Match)
+	checkErr(t, err)
+	if len(result) != 1 || result[0].Id() != high.Snapshot().Id() {
+		t.Fatal("Only the bug with the field should match")
+	}
+
+	for _, invalid := range []string{"field:severity", "field:=high", "nope:x", "status:maybe"} {
+		if _, err := cache.ParseQuery(invalid); err == nil {
+			t.Fatalf("The query %q should be rejected", invalid)
+		}
+	}
+}
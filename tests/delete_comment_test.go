@@ -0,0 +1,75 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/bug/operations"
+)
+
+func TestDeleteComment(t *testing.T) {
+	isaac := bug.Person{
+		Name:  "Isaac Newton",
+		Email: "isaac@newton.uk",
+	}
+
+	bug1, err := operations.Create(rene, "title", "message")
+	checkErr(t, err)
+	err = operations.Comment(bug1, rene, "my password is hunter2")
+	checkErr(t, err)
+	err = operations.Comment(bug1, isaac, "leaked token")
+	checkErr(t, err)
+	err = bug1.Commit(mockRepo)
+	checkErr(t, err)
+
+	hashes, err := bug1.Compile().CommentHashes()
+	checkErr(t, err)
+
+	err = operations.DeleteComment(bug1, rene, "zzzzzzz", false)
+	if err == nil {
+		t.Fatal("Deleting an unknown comment should fail")
+	}
+
+	err = operations.DeleteComment(bug1, rene, string(hashes[2]), false)
+	if err == nil {
+		t.Fatal("Deleting the comment of someone else should require force")
+	}
+
+	err = operations.DeleteComment(bug1, rene, string(hashes[1]), false)
+	checkErr(t, err)
+	err = operations.DeleteComment(bug1, rene, string(hashes[2]), true)
+	checkErr(t, err)
+
+	err = operations.DeleteComment(bug1, rene, string(hashes[1]), false)
+	if err == nil {
+		t.Fatal("Deleting a comment twice should fail")
+	}
+
+	err = bug1.Commit(mockRepo)
+	checkErr(t, err)
+
+	bug2, err := bug.ReadLocalBug(mockRepo, bug1.Id())
+	checkErr(t, err)
+
+	snap := bug2.Compile()
+
+	if snap.Comments[0].IsDeleted() {
+		t.Fatal("Unexpected deletion")
+	}
+
+	own := snap.Comments[1]
+	if !own.IsDeleted() || own.Deletion.Forced || own.Deletion.Author != rene {
+		t.Fatalf("Unexpected deletion %v", own.Deletion)
+	}
+	if own.Message != own.Deletion.Tombstone() {
+		t.Fatal("The message should be replaced by a tombstone")
+	}
+	if own.OriginalMessage() != "my password is hunter2" {
+		t.Fatal("The original message should still be available")
+	}
+
+	forced := snap.Comments[2]
+	if !forced.IsDeleted() || !forced.Deletion.Forced {
+		t.Fatal("The override should be recorded")
+	}
+}
@@ -0,0 +1,88 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/bug/operations"
+)
+
+func TestDraft(t *testing.T) {
+	repoA, repoB, remote := setupRepos(t)
+	defer cleanupRepos(repoA, repoB, remote)
+
+	published, err := operations.Create(rene, "published", "message")
+	checkErr(t, err)
+	checkErr(t, published.Commit(repoA))
+
+	if published.SaveDraft(repoA) == nil {
+		t.Fatal("A published bug should not become a draft")
+	}
+
+	draft, err := operations.Create(rene, "draft", "message")
+	checkErr(t, err)
+	checkErr(t, draft.SaveDraft(repoA))
+	checkErr(t, operations.Comment(draft, isaac, "more details"))
+	checkErr(t, draft.SaveDraft(repoA))
+
+	if !draft.IsDraft() {
+		t.Fatal("The bug should be a draft")
+	}
+
+	// the drafts are listed apart
+	drafts, err := bug.ListDraftIds(repoA)
+	checkErr(t, err)
+	if len(drafts) != 1 || drafts[0] != draft.Id() {
+		t.Fatalf("Unexpected drafts %v", drafts)
+	}
+
+	ids, err := bug.ListLocalIds(repoA)
+	checkErr(t, err)
+	if len(ids) != 1 || ids[0] != published.Id() {
+		t.Fatalf("The draft should not be listed with the bugs: %v", ids)
+	}
+
+	read, err := bug.ReadDraft(repoA, draft.Id())
+	checkErr(t, err)
+	if !read.IsDraft() || len(read.Compile().Comments) != 2 {
+		t.Fatal("The draft should be read back")
+	}
+
+	// and never pushed
+	_, err = bug.Push(repoA, "origin")
+	checkErr(t, err)
+	_, err = bug.Fetch(repoB, "origin")
+	checkErr(t, err)
+	for result := range bug.MergeAll(repoB, "origin") {
+		checkErr(t, result.Err)
+	}
+
+	ids, err = bug.ListLocalIds(repoB)
+	checkErr(t, err)
+	if len(ids) != 1 {
+		t.Fatalf("Only the published bug should be pushed, got %v", ids)
+	}
+
+	// publishing move the ref
+	checkErr(t, operations.Comment(read, rene, "ready"))
+	checkErr(t, read.Publish(repoA))
+
+	if read.IsDraft() {
+		t.Fatal("The bug should not be a draft anymore")
+	}
+	if read.Publish(repoA) != bug.ErrNotADraft {
+		t.Fatal("A published bug can't be published again")
+	}
+
+	drafts, err = bug.ListDraftIds(repoA)
+	checkErr(t, err)
+	if len(drafts) != 0 {
+		t.Fatalf("The draft should be removed, got %v", drafts)
+	}
+
+	moved, err := bug.ReadLocalBug(repoA, draft.Id())
+	checkErr(t, err)
+	if moved.IsDraft() || len(moved.Compile().Comments) != 3 {
+		t.Fatal("The published bug should hold the whole draft")
+	}
+}
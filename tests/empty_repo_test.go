@@ -0,0 +1,75 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+// testEmptyRepo check that a repository without any bug yet give empty
+// results rather than errors
+func testEmptyRepo(t *testing.T, repo repository.Repo) {
+	ids, err := bug.ListLocalIds(repo)
+	checkErr(t, err)
+	if ids != nil {
+		t.Fatalf("Expected no id, got %#v", ids)
+	}
+
+	refs, err := repo.ListRefs("refs/bugs/")
+	checkErr(t, err)
+	if refs != nil {
+		t.Fatalf("Expected no ref, got %#v", refs)
+	}
+
+	heads, err := bug.ListLocalHeads(repo)
+	checkErr(t, err)
+	if len(heads) != 0 {
+		t.Fatalf("Expected no head, got %v", heads)
+	}
+
+	if bugs := allBugs(t, bug.ReadAllLocalBugs(repo)); len(bugs) != 0 {
+		t.Fatalf("Expected no bug, got %d", len(bugs))
+	}
+
+	b, suggestions, err := bug.FindBugWithSuggestions(repo, "abc")
+	if err != bug.ErrNoMatchingBug || b != nil || suggestions != nil {
+		t.Fatalf("Expected no matching bug, got %v %v %v", b, suggestions, err)
+	}
+
+	backend := cache.NewRepoCache(repo)
+
+	snaps, err := backend.Search(nil)
+	checkErr(t, err)
+	if snaps != nil {
+		t.Fatalf("Expected no bug, got %d", len(snaps))
+	}
+
+	query, err := cache.ParseQuery("status:open label:bug")
+	checkErr(t, err)
+	snaps, err = backend.Search(query.Match)
+	checkErr(t, err)
+	if snaps != nil {
+		t.Fatalf("Expected no bug, got %d", len(snaps))
+	}
+
+	if _, err := backend.ResolveBugPrefix("abc"); err != bug.ErrNoMatchingBug {
+		t.Fatalf("Expected no matching bug, got %v", err)
+	}
+
+	if _, err := backend.ResolveBugTitle("title"); err != bug.ErrNoMatchingBug {
+		t.Fatalf("Expected no matching bug, got %v", err)
+	}
+}
+
+func TestEmptyRepo(t *testing.T) {
+	repo := createRepo(false)
+	defer cleanupRepo(repo)
+
+	testEmptyRepo(t, repo)
+}
+
+func TestEmptyRepoMock(t *testing.T) {
+	testEmptyRepo(t, repository.NewMockRepoForTest())
+}
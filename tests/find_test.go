@@ -0,0 +1,83 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/bug/operations"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func TestFindBugWithSuggestions(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+
+	var bugs []*bug.Bug
+	for _, title := range []string{"bug1", "bug2", "bug3"} {
+		b, err := operations.Create(rene, title, "message")
+		checkErr(t, err)
+		err = b.Commit(repo)
+		checkErr(t, err)
+		bugs = append(bugs, b)
+	}
+
+	// exact
+	found, suggestions, err := bug.FindBugWithSuggestions(repo, bugs[0].HumanId())
+	checkErr(t, err)
+	if found.Id() != bugs[0].Id() || len(suggestions) != 0 {
+		t.Fatal("The bug should be found without suggestions")
+	}
+
+	// ambiguous
+	found, suggestions, err = bug.FindBugWithSuggestions(repo, "")
+	if err == nil || found != nil {
+		t.Fatal("An ambiguous prefix should fail")
+	}
+	if len(suggestions) != len(bugs) {
+		t.Fatal("All the matching bugs should be listed")
+	}
+
+	// no match, with a typo in the prefix
+	typo := []byte(bugs[1].HumanId())
+	if typo[3] == '0' {
+		typo[3] = '1'
+	} else {
+		typo[3] = '0'
+	}
+
+	found, suggestions, err = bug.FindBugWithSuggestions(repo, string(typo))
+	if err != bug.ErrNoMatchingBug || found != nil {
+		t.Fatal("A typo'd prefix should not match")
+	}
+	if !containsId(suggestions, bugs[1].Id()) {
+		t.Fatal("The bug with a close prefix should be suggested")
+	}
+
+	// no match, with a missing char
+	missing := bugs[2].HumanId()[:2] + bugs[2].HumanId()[3:]
+
+	_, suggestions, err = bug.FindBugWithSuggestions(repo, missing)
+	if err != bug.ErrNoMatchingBug && err != nil {
+		t.Fatal(err)
+	}
+	if err == bug.ErrNoMatchingBug && !containsId(suggestions, bugs[2].Id()) {
+		t.Fatal("The bug with a close prefix should be suggested")
+	}
+
+	// no match, nothing close
+	_, suggestions, err = bug.FindBugWithSuggestions(repo, "zzzzzzz")
+	if err != bug.ErrNoMatchingBug {
+		t.Fatal("An unknown prefix should not match")
+	}
+	if len(suggestions) != 0 {
+		t.Fatal("Unexpected suggestions")
+	}
+}
+
+func containsId(ids []string, id string) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+package tests
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/bug/operations"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func formatSnapshot(t *testing.T, repo repository.Repo, value string, snap *bug.Snapshot) string {
+	format, err := bug.LoadOutputFormat(repo, value)
+	checkErr(t, err)
+
+	var buf bytes.Buffer
+	checkErr(t, format.Execute(&buf, snap))
+	return buf.String()
+}
+
+func TestOutputFormat(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+
+	b, err := operations.Create(rene, "a rather long title", "message")
+	checkErr(t, err)
+	checkErr(t, operations.Comment(b, rene, "comment"))
+	b.Append(operations.NewLabelChangeOperation(rene, []bug.Label{"bug", "ui"}, nil))
+	snap := b.Compile()
+
+	output := formatSnapshot(t, repo, `{{.HumanId}} [{{.Status}}] {{.Title | truncate 10}} ({{len .Comments}}) {{join ", " .Labels}}`, &snap)
+	expected := snap.HumanId() + " [open] a rathe... (2) bug, ui\n"
+	if output != expected {
+		t.Fatalf("Unexpected output %q, expected %q", output, expected)
+	}
+
+	output = formatSnapshot(t, repo, `{{ago .CreatedAt}}`, &snap)
+	if !strings.HasSuffix(output, "ago\n") && output != "now\n" {
+		t.Fatalf("Unexpected relative time %q", output)
+	}
+
+	// the built-in formats, by name
+	if output := formatSnapshot(t, repo, "oneline", &snap); output != snap.Oneline()+"\n" {
+		t.Fatalf("Unexpected oneline output %q", output)
+	}
+	output = formatSnapshot(t, repo, "porcelain", &snap)
+	if !strings.HasPrefix(output, snap.Id()+"\topen\tnormal\t") || !strings.HasSuffix(output, "\tbug,ui\ta rather long title\n") {
+		t.Fatalf("Unexpected porcelain output %q", output)
+	}
+
+	// defined in the git config, even over a built-in one
+	checkErr(t, repo.SetConfig("git-bug.format.oneline", "{{.Title}}"))
+	if output := formatSnapshot(t, repo, "oneline", &snap); output != "a rather long title\n" {
+		t.Fatalf("The format should be read from the git config, got %q", output)
+	}
+
+	if _, err := bug.LoadOutputFormat(repo, "unknown"); err == nil {
+		t.Fatal("An unknown format name should be rejected")
+	}
+}
+
+func TestOutputFormatErrors(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+
+	// a syntax error, only located by its line
+	_, err := bug.ParseOutputFormat("{{.Title}}\n{{if .Title}}")
+	formatErr, ok := err.(bug.FormatError)
+	if !ok || formatErr.Line != 2 {
+		t.Fatalf("Expected a syntax error on line 2, got %v", err)
+	}
+
+	_, err = bug.ParseOutputFormat("{{.Title | nope}}")
+	formatErr, ok = err.(bug.FormatError)
+	if !ok || formatErr.Line != 1 || !strings.Contains(formatErr.Message, "nope") {
+		t.Fatalf("Expected an undefined function error on line 1, got %v", err)
+	}
+
+	// an execution error, located by its line and column
+	b, err := operations.Create(rene, "title", "message")
+	checkErr(t, err)
+	snap := b.Compile()
+
+	format, err := bug.LoadOutputFormat(repo, "title: {{.Title}}\nfoo: {{.Foo}}")
+	checkErr(t, err)
+
+	var buf bytes.Buffer
+	err = format.Execute(&buf, &snap)
+	formatErr, ok = err.(bug.FormatError)
+	if !ok || formatErr.Line != 2 || formatErr.Column != 8 {
+		t.Fatalf("Expected an error at line 2, column 8, got %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("Nothing should be written on error, got %q", buf.String())
+	}
+}
@@ -0,0 +1,131 @@
+package tests
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/MichaelMure/git-bug/bridge"
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/bug/operations"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func TestIdentityMappingSync(t *testing.T) {
+	repoA, repoB, remote := setupRepos(t)
+	defer cleanupRepos(repoA, repoB, remote)
+
+	mapUser := func(repo repository.Repo, login string, person bug.Person) {
+		mapping, err := bridge.ReadIdentityMapping(repo)
+		checkErr(t, err)
+		mapping.Map(login, person)
+		checkErr(t, mapping.Write(repo))
+	}
+
+	lookup := func(repo repository.Repo, login string) (bug.Person, bool) {
+		mapping, err := bridge.ReadIdentityMapping(repo)
+		checkErr(t, err)
+		return mapping.Lookup(login)
+	}
+
+	mapUser(repoA, "rdescartes", rene)
+	_, err := bug.Push(repoA, "origin")
+	checkErr(t, err)
+	checkErr(t, bug.Pull(repoB, ioutil.Discard, "origin"))
+
+	if person, ok := lookup(repoB, "rdescartes"); !ok || person != rene {
+		t.Fatal("The mapping should be pulled")
+	}
+
+	// mapped on both sides, the logins are joined
+	mapUser(repoA, "inewton", isaac)
+	_, err = bug.Push(repoA, "origin")
+	checkErr(t, err)
+
+	mapUser(repoB, "rdescartes", bug.Person{Name: "René", Email: "rene@example.com"})
+	checkErr(t, bug.Pull(repoB, ioutil.Discard, "origin"))
+	_, err = bug.Push(repoB, "origin")
+	checkErr(t, err)
+
+	if person, ok := lookup(repoB, "inewton"); !ok || person != isaac {
+		t.Fatal("The remote login should be merged")
+	}
+	if person, _ := lookup(repoB, "rdescartes"); person.Email != "rene@example.com" {
+		t.Fatal("The local mapping should win")
+	}
+}
+
+func TestIdentityMappingRemap(t *testing.T) {
+	repo := createRepo(false)
+	defer cleanupRepo(repo)
+
+	imported := bug.Person{Name: "rdescartes"}
+
+	b, err := operations.Create(imported, "title", "message")
+	checkErr(t, err)
+	checkErr(t, operations.Comment(b, isaac, "local"))
+	checkErr(t, operations.Comment(b, imported, "imported"))
+	checkErr(t, b.Commit(repo))
+
+	mapping, err := bridge.ReadIdentityMapping(repo)
+	checkErr(t, err)
+	mapping.Map("rdescartes", rene)
+	checkErr(t, mapping.Write(repo))
+
+	remapped, err := mapping.Remap(repo, isaac)
+	checkErr(t, err)
+	if remapped != 1 {
+		t.Fatalf("Expected 1 remapped bug, got %d", remapped)
+	}
+
+	b, err = bug.ReadLocalBug(repo, b.Id())
+	checkErr(t, err)
+	snap := b.Compile()
+
+	if snap.Author != rene || snap.Comments[1].Author != isaac || snap.Comments[2].Author != rene {
+		t.Fatal("The imported authors should be remapped")
+	}
+
+	// the history is left untouched
+	if snap.Operations[0].GetAuthor() != imported {
+		t.Fatal("The operations should not be rewritten")
+	}
+
+	remapped, err = mapping.Remap(repo, isaac)
+	checkErr(t, err)
+	if remapped != 0 {
+		t.Fatal("The bug should not be remapped twice")
+	}
+}
+
+func TestIdentityMappingNamespace(t *testing.T) {
+	defer bug.SetNamespace(bug.DefaultNamespace)
+
+	repoA, repoB, remote := setupRepos(t)
+	defer cleanupRepos(repoA, repoB, remote)
+
+	mapping, err := bridge.ReadIdentityMapping(repoA)
+	checkErr(t, err)
+	mapping.Map("rdescartes", rene)
+	checkErr(t, mapping.Write(repoA))
+	_, err = bug.Push(repoA, "origin")
+	checkErr(t, err)
+
+	// another namespace has its own mapping
+	checkErr(t, bug.SetNamespace("frontend"))
+	checkErr(t, bug.Pull(repoB, ioutil.Discard, "origin"))
+
+	mapping, err = bridge.ReadIdentityMapping(repoB)
+	checkErr(t, err)
+	if len(mapping.Logins()) != 0 {
+		t.Fatal("The mapping of another namespace should not be used")
+	}
+
+	checkErr(t, bug.SetNamespace(bug.DefaultNamespace))
+	checkErr(t, bug.Pull(repoB, ioutil.Discard, "origin"))
+
+	mapping, err = bridge.ReadIdentityMapping(repoB)
+	checkErr(t, err)
+	if person, ok := mapping.Lookup("rdescartes"); !ok || person != rene {
+		t.Fatal("The mapping should be pulled")
+	}
+}
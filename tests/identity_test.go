@@ -0,0 +1,64 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+// anonymousRepo has no user configured in git
+type anonymousRepo struct {
+	repository.Repo
+}
+
+func (r *anonymousRepo) GetConfig(key string) (string, error) {
+	if strings.HasPrefix(key, "user.") {
+		return "", repository.ErrNoConfigEntry
+	}
+	return r.Repo.GetConfig(key)
+}
+
+func TestDefaultIdentity(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+	checkErr(t, repo.SetConfig("user.name", isaac.Name))
+	checkErr(t, repo.SetConfig("user.email", isaac.Email))
+
+	identity, err := bug.DefaultIdentity(repo)
+	checkErr(t, err)
+	if identity != isaac {
+		t.Fatalf("Unexpected identity %v", identity)
+	}
+
+	// the new bugs use it by default
+	backend := cache.NewRepoCache(repo)
+	b, err := backend.NewBug("title", "message")
+	checkErr(t, err)
+	if b.Snapshot().Author != isaac {
+		t.Fatalf("Unexpected author %v", b.Snapshot().Author)
+	}
+}
+
+func TestDefaultIdentityMissing(t *testing.T) {
+	repo := &anonymousRepo{Repo: repository.NewMockRepoForTest()}
+
+	_, err := bug.DefaultIdentity(repo)
+	if err == nil || !strings.Contains(err.Error(), "user.name") {
+		t.Fatalf("A missing user.name should be reported, got %v", err)
+	}
+
+	backend := cache.NewRepoCache(repo)
+	if _, err := backend.NewBug("title", "message"); err == nil {
+		t.Fatal("A bug can't be created without identity")
+	}
+
+	// unless the author is given
+	backend.SetAuthor(rene)
+	b, err := backend.NewBug("title", "message")
+	checkErr(t, err)
+	if b.Snapshot().Author != rene {
+		t.Fatalf("Unexpected author %v", b.Snapshot().Author)
+	}
+}
@@ -0,0 +1,106 @@
+package tests
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/bug/operations"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util"
+)
+
+func opsEntry(t *testing.T, repo repository.Repo, commit util.Hash) util.Hash {
+	entries, err := repo.ListEntries(commit)
+	checkErr(t, err)
+
+	for _, entry := range entries {
+		if entry.Name == "ops" {
+			return entry.Hash
+		}
+	}
+
+	t.Fatal("No ops entry")
+	return ""
+}
+
+func TestShallowClone(t *testing.T) {
+	origin := createRepo(false)
+	defer cleanupRepo(origin)
+
+	b, err := operations.Create(rene, "title", "message")
+	checkErr(t, err)
+	checkErr(t, b.Commit(origin))
+
+	for i := 0; i < 2; i++ {
+		checkErr(t, operations.Comment(b, isaac, "comment"))
+		checkErr(t, b.Commit(origin))
+	}
+
+	shallow := createRepo(false)
+	defer cleanupRepo(shallow)
+
+	cmd := exec.Command("git", "fetch", "--depth=1", "file://"+origin.GetPath(), "refs/bugs/*:refs/bugs/*")
+	cmd.Dir = shallow.GetPath()
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("%s: %s", err, out)
+	}
+
+	_, err = bug.ReadLocalBug(shallow, b.Id())
+	incomplete, ok := err.(*bug.ErrIncompleteHistory)
+	if !ok {
+		t.Fatalf("The history cut by the shallow clone should be reported, got %v", err)
+	}
+	if incomplete.Id != b.Id() || !incomplete.Shallow || incomplete.Missing != b.LastCommitHash() {
+		t.Fatalf("Unexpected error %#v", incomplete)
+	}
+
+	// the other bugs are still listed
+	other, err := operations.Create(isaac, "other", "message")
+	checkErr(t, err)
+	checkErr(t, other.Commit(shallow))
+
+	backend := cache.NewRepoCache(shallow)
+	snapshots, err := backend.Search(nil)
+	checkErr(t, err)
+
+	if len(snapshots) != 1 || snapshots[0].Id() != other.Id() {
+		t.Fatal("The bugs with a complete history should be listed")
+	}
+	if skipped := backend.IncompleteBugs(); len(skipped) != 1 || skipped[0].Id != b.Id() {
+		t.Fatal("The bug with an incomplete history should be flagged")
+	}
+}
+
+func TestPartialCloneMissingObject(t *testing.T) {
+	repo := createRepo(false)
+	defer cleanupRepo(repo)
+
+	b, err := operations.Create(rene, "title", "message")
+	checkErr(t, err)
+	checkErr(t, b.Commit(repo))
+
+	// remove the loose object of the first operation pack
+	pack := opsEntry(t, repo, b.LastCommitHash())
+	checkErr(t, os.Remove(filepath.Join(repo.GetPath(), ".git", "objects", string(pack[:2]), string(pack[2:]))))
+
+	// in a complete clone, it's a corruption
+	_, err = bug.ReadLocalBug(repo, b.Id())
+	if _, ok := err.(*bug.ErrIncompleteHistory); ok || err == nil {
+		t.Fatalf("A missing object should be a plain error, got %v", err)
+	}
+
+	checkErr(t, repo.SetConfig("extensions.partialclone", "origin"))
+
+	_, err = bug.ReadLocalBug(repo, b.Id())
+	incomplete, ok := err.(*bug.ErrIncompleteHistory)
+	if !ok {
+		t.Fatalf("The object left out of the partial clone should be reported, got %v", err)
+	}
+	if incomplete.Shallow || incomplete.Missing != pack {
+		t.Fatalf("Unexpected error %#v", incomplete)
+	}
+}
@@ -0,0 +1,78 @@
+package tests
+
+import (
+	"os"
+	"testing"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/bug/operations"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+// mergeResults merge the bugs of the remote and return the status by bug id
+func mergeResults(t *testing.T, repo repository.Repo, remote string) map[string]string {
+	results := make(map[string]string)
+	for result := range bug.MergeAll(repo, remote) {
+		checkErr(t, result.Err)
+		results[result.Id] = result.Status
+	}
+	return results
+}
+
+func TestIncrementalPull(t *testing.T) {
+	repoA, repoB, remote := setupRepos(t)
+	defer cleanupRepos(repoA, repoB, remote)
+
+	var bugs []*bug.Bug
+	for i := 0; i < 3; i++ {
+		b, err := operations.Create(rene, "bug", "message")
+		checkErr(t, err)
+		checkErr(t, b.Commit(repoA))
+		bugs = append(bugs, b)
+	}
+
+	_, err := bug.Push(repoA, "origin")
+	checkErr(t, err)
+
+	_, err = bug.Fetch(repoB, "origin")
+	checkErr(t, err)
+
+	if results := mergeResults(t, repoB, "origin"); len(results) != 3 {
+		t.Fatalf("Every bug should be merged the first time, got %v", results)
+	}
+
+	// nothing changed on the remote
+	checkErr(t, bug.Pull(repoB, os.Stdout, "origin"))
+
+	if results := mergeResults(t, repoB, "origin"); len(results) != 0 {
+		t.Fatalf("No bug should be processed, got %v", results)
+	}
+
+	// a single bug changed on the remote
+	checkErr(t, operations.Comment(bugs[1], rene, "comment"))
+	checkErr(t, bugs[1].Commit(repoA))
+
+	_, err = bug.Push(repoA, "origin")
+	checkErr(t, err)
+
+	_, err = bug.Fetch(repoB, "origin")
+	checkErr(t, err)
+
+	results := mergeResults(t, repoB, "origin")
+	if len(results) != 1 || results[bugs[1].Id()] != bug.MsgMergeUpdated {
+		t.Fatalf("Only the changed bug should be merged, got %v", results)
+	}
+
+	b, err := bug.ReadLocalBug(repoB, bugs[1].Id())
+	checkErr(t, err)
+	if len(b.Compile().Comments) != 2 {
+		t.Fatal("The change should be merged")
+	}
+
+	// without the sync state, every bug is processed again
+	git(t, repoB, "update-ref", "-d", "refs/bugs-sync/origin")
+
+	if results := mergeResults(t, repoB, "origin"); len(results) != 3 {
+		t.Fatalf("Every bug should be processed without sync state, got %v", results)
+	}
+}
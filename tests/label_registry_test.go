@@ -0,0 +1,166 @@
+package tests
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/bug/operations"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func editLabel(t *testing.T, repo repository.Repo, label bug.Label, color string, description string) {
+	registry, err := bug.ReadLabelRegistry(repo)
+	checkErr(t, err)
+	checkErr(t, registry.Edit(label, color, description))
+	checkErr(t, registry.Write(repo))
+}
+
+func lookupLabel(t *testing.T, repo repository.Repo, label bug.Label) bug.LabelInfo {
+	registry, err := bug.ReadLabelRegistry(repo)
+	checkErr(t, err)
+	info, _ := registry.Lookup(label)
+	return info
+}
+
+// writeRawLabelRegistry store a registry as is, like a remote could
+func writeRawLabelRegistry(t *testing.T, repo repository.Repo, data string) {
+	blob, err := repo.StoreData([]byte(data))
+	checkErr(t, err)
+	tree, err := repo.StoreTree([]repository.TreeEntry{
+		{ObjectType: repository.Blob, Hash: blob, Name: "labels"},
+	})
+	checkErr(t, err)
+	commit, err := repo.StoreCommit(tree)
+	checkErr(t, err)
+	checkErr(t, repo.UpdateRef("refs/git-bug/labels/bugs", commit))
+}
+
+func TestLabelRegistry(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+
+	registry, err := bug.ReadLabelRegistry(repo)
+	checkErr(t, err)
+	if len(registry.Labels()) != 0 {
+		t.Fatal("The registry should be empty")
+	}
+
+	if registry.Edit("UI", "orange", "") == nil {
+		t.Fatal("An invalid color should be rejected")
+	}
+
+	editLabel(t, repo, "UI", "#FF8800", "anything termui/webui")
+	editLabel(t, repo, "bug", "", "something \n broken")
+
+	info := lookupLabel(t, repo, "UI")
+	if info.Color != "#ff8800" || info.Description != "anything termui/webui" {
+		t.Fatalf("Unexpected metadata %+v", info)
+	}
+
+	info = lookupLabel(t, repo, "bug")
+	if info.Color != "" || info.Description != "something broken" {
+		t.Fatalf("Unexpected metadata %+v", info)
+	}
+
+	// unset
+	editLabel(t, repo, "bug", "", "")
+
+	registry, err = bug.ReadLabelRegistry(repo)
+	checkErr(t, err)
+	if _, ok := registry.Lookup("bug"); ok {
+		t.Fatal("The metadata should be unset")
+	}
+	if labels := registry.Labels(); len(labels) != 1 || labels[0] != "UI" {
+		t.Fatalf("Unexpected labels %v", labels)
+	}
+}
+
+func TestLabelRegistryPushPull(t *testing.T) {
+	repoA, repoB, remote := setupRepos(t)
+	defer cleanupRepos(repoA, repoB, remote)
+
+	// the registry is synced even without any bug
+	editLabel(t, repoA, "UI", "#ff8800", "from A")
+	_, err := bug.Push(repoA, "origin")
+	checkErr(t, err)
+
+	checkErr(t, bug.Pull(repoB, os.Stdout, "origin"))
+
+	if info := lookupLabel(t, repoB, "UI"); info.Description != "from A" {
+		t.Fatalf("The registry should be pulled, got %+v", info)
+	}
+
+	// concurrent edits of both clones
+	editLabel(t, repoA, "UI", "#00ff00", "edited by A")
+	editLabel(t, repoA, "bug", "#ff0000", "")
+	_, err = bug.Push(repoA, "origin")
+	checkErr(t, err)
+
+	editLabel(t, repoB, "docs", "#0000ff", "")
+	editLabel(t, repoB, "UI", "#123456", "edited by B")
+	editLabel(t, repoB, "UI", "#654321", "edited by B again")
+
+	b, err := operations.Create(rene, "bug1", "message")
+	checkErr(t, err)
+	checkErr(t, b.Commit(repoB))
+
+	checkErr(t, bug.Pull(repoB, os.Stdout, "origin"))
+
+	// merged, so that it can be pushed
+	_, err = bug.Push(repoB, "origin")
+	checkErr(t, err)
+	checkErr(t, bug.Pull(repoA, os.Stdout, "origin"))
+
+	for _, repo := range []repository.Repo{repoA, repoB} {
+		registry, err := bug.ReadLabelRegistry(repo)
+		checkErr(t, err)
+
+		if labels := registry.Labels(); len(labels) != 3 {
+			t.Fatalf("Unexpected labels %v", labels)
+		}
+
+		// the last edit win
+		if info, _ := registry.Lookup("UI"); info.Description != "edited by B again" {
+			t.Fatalf("Unexpected metadata %+v", info)
+		}
+		if info, _ := registry.Lookup("bug"); info.Color != "#ff0000" {
+			t.Fatalf("Unexpected metadata %+v", info)
+		}
+	}
+
+	if git(t, repoA, "rev-parse", "refs/git-bug/labels/bugs") != git(t, repoB, "rev-parse", "refs/git-bug/labels/bugs") {
+		t.Fatal("Both repositories should have the same registry")
+	}
+}
+
+func TestLabelRegistryInvalidColor(t *testing.T) {
+	repoA, repoB, remote := setupRepos(t)
+	defer cleanupRepos(repoA, repoB, remote)
+
+	editLabel(t, repoB, "UI", "#ff8800", "from B")
+	editLabel(t, repoB, "bug", "#ff0000", "from B")
+
+	writeRawLabelRegistry(t, repoA, `{
+		"UI": {"color": "red;}</style><script>", "description": "from A", "time": 10},
+		"bug": {"color": "#00FF00", "description": "from A", "time": 10}
+	}`)
+
+	registry, err := bug.ReadLabelRegistry(repoA)
+	checkErr(t, err)
+	if labels := registry.Labels(); len(labels) != 1 || labels[0] != "bug" {
+		t.Fatalf("An edit with an invalid color should be dropped, got %v", labels)
+	}
+
+	_, err = bug.Push(repoA, "origin")
+	checkErr(t, err)
+	checkErr(t, bug.Pull(repoB, ioutil.Discard, "origin"))
+
+	// the edit with an invalid color is dropped, the valid one is merged
+	if info := lookupLabel(t, repoB, "UI"); info.Color != "#ff8800" || info.Description != "from B" {
+		t.Fatalf("Unexpected metadata %+v", info)
+	}
+	if info := lookupLabel(t, repoB, "bug"); info.Color != "#00FF00" || info.Description != "from A" {
+		t.Fatalf("Unexpected metadata %+v", info)
+	}
+}
@@ -0,0 +1,229 @@
+package tests
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/bug/operations"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+// permutations return every order of the given operations
+func permutations(ops []bug.Operation) [][]bug.Operation {
+	if len(ops) <= 1 {
+		return [][]bug.Operation{ops}
+	}
+
+	var result [][]bug.Operation
+
+	for i := range ops {
+		rest := make([]bug.Operation, 0, len(ops)-1)
+		rest = append(rest, ops[:i]...)
+		rest = append(rest, ops[i+1:]...)
+
+		for _, perm := range permutations(rest) {
+			result = append(result, append([]bug.Operation{ops[i]}, perm...))
+		}
+	}
+
+	return result
+}
+
+func TestConcurrentLabels(t *testing.T) {
+	b, err := operations.Create(rene, "title", "message")
+	checkErr(t, err)
+	checkErr(t, operations.ChangeLabels(nil, b, rene, []string{"bug", "ui"}, nil))
+	checkErr(t, b.Commit(mockRepo))
+
+	base := b.Compile()
+
+	// two clones edit the labels concurrently from the same state, each
+	// removing a label that the other add again
+	removeBug := operations.NewLabelChangeOperation(rene, []bug.Label{"urgent"}, []bug.Label{"bug"})
+	removeBug.Observe(base)
+
+	addBug := operations.NewLabelChangeOperation(isaac, []bug.Label{"bug"}, nil)
+
+	removeUi := operations.NewLabelChangeOperation(isaac, nil, []bug.Label{"ui"})
+	removeUi.Observe(base)
+
+	addUi := operations.NewLabelChangeOperation(rene, []bug.Label{"ui"}, nil)
+
+	concurrent := []bug.Operation{removeBug, addBug, removeUi, addUi}
+	expected := []bug.Label{"bug", "ui", "urgent"}
+
+	for _, order := range permutations(concurrent) {
+		snap := b.Compile()
+		for _, op := range order {
+			snap = op.Apply(snap)
+		}
+
+		if !reflect.DeepEqual(snap.Labels, expected) {
+			t.Fatalf("The concurrent additions should win, got %v", snap.Labels)
+		}
+	}
+}
+
+func TestRemoveObservedLabel(t *testing.T) {
+	b, err := operations.Create(rene, "title", "message")
+	checkErr(t, err)
+	checkErr(t, operations.ChangeLabels(nil, b, rene, []string{"bug"}, nil))
+	checkErr(t, b.Commit(mockRepo))
+
+	// a removal seeing every addition remove the label
+	checkErr(t, operations.ChangeLabels(nil, b, isaac, nil, []string{"bug"}))
+	checkErr(t, b.Commit(mockRepo))
+
+	if labels := b.Compile().Labels; len(labels) != 0 {
+		t.Fatalf("The label should be removed, got %v", labels)
+	}
+
+	// it can be added again afterward
+	checkErr(t, operations.ChangeLabels(nil, b, rene, []string{"bug"}, nil))
+	checkErr(t, b.Commit(mockRepo))
+
+	if labels := b.Compile().Labels; !reflect.DeepEqual(labels, []bug.Label{"bug"}) {
+		t.Fatalf("The label should be added again, got %v", labels)
+	}
+}
+
+func TestRemoveLabelWithoutObserved(t *testing.T) {
+	b, err := operations.Create(rene, "title", "message")
+	checkErr(t, err)
+	b.Append(operations.NewLabelChangeOperation(rene, []bug.Label{"bug"}, nil))
+	b.Append(operations.NewLabelChangeOperation(isaac, []bug.Label{"bug"}, nil))
+	// as written before the additions were tracked
+	b.Append(operations.NewLabelChangeOperation(rene, nil, []bug.Label{"bug"}))
+	checkErr(t, b.Commit(mockRepo))
+
+	if labels := b.Compile().Labels; len(labels) != 0 {
+		t.Fatalf("Every previous addition should be removed, got %v", labels)
+	}
+}
+
+func TestSimilarLabel(t *testing.T) {
+	known := []bug.Label{"UI", "bug", "documentation", "good first issue"}
+
+	cases := []struct {
+		label   bug.Label
+		similar bug.Label
+	}{
+		{"uI", "UI"},
+		{"u i", "UI"},
+		{"bugg", "bug"},
+		{"Bugs", "bug"},
+		{"documentaiton", "documentation"},
+		{"good-first-issue", "good first issue"},
+		{"security", ""},
+		{"x", ""},
+		{"bug", ""},
+	}
+
+	for _, c := range cases {
+		similar, ok := bug.SimilarLabel(known, c.label)
+		if similar != c.similar || ok != (c.similar != "") {
+			t.Fatalf("Unexpected suggestion %q for %q", similar, c.label)
+		}
+	}
+}
+
+func TestCacheLabels(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+	backend := cache.NewRepoCache(repo)
+
+	for _, labels := range [][]string{{"bug", "ui"}, {"bug"}, {"doc"}} {
+		b, err := backend.NewBug("title", "message")
+		checkErr(t, err)
+		checkErr(t, b.ChangeLabels(nil, labels, nil))
+		checkErr(t, b.Commit())
+	}
+
+	labels, err := backend.Labels()
+	checkErr(t, err)
+
+	expected := []cache.LabelCount{
+		{Label: "bug", Count: 2},
+		{Label: "doc", Count: 1},
+		{Label: "ui", Count: 1},
+	}
+
+	if !reflect.DeepEqual(labels, expected) {
+		t.Fatalf("Unexpected labels %v", labels)
+	}
+}
+
+func TestContainsLabel(t *testing.T) {
+	b, err := operations.Create(rene, "title", "message")
+	checkErr(t, err)
+	checkErr(t, operations.ChangeLabels(nil, b, rene, []string{"UI", "bug"}, nil))
+
+	snap := b.Compile()
+
+	for _, label := range []string{"UI", "bug"} {
+		if !snap.ContainsLabel(label) {
+			t.Fatalf("The label %q should be found", label)
+		}
+	}
+
+	// exact match only
+	for _, label := range []string{"ui", "Bug", "bu", " bug", ""} {
+		if snap.ContainsLabel(label) {
+			t.Fatalf("The label %q should not be found", label)
+		}
+	}
+}
+
+func TestNormalizeLabel(t *testing.T) {
+	label, err := bug.NormalizeLabel("  needs triage\t")
+	checkErr(t, err)
+	if label != "needs triage" {
+		t.Fatalf("Unexpected label %q", label)
+	}
+
+	for _, invalid := range []string{"", "   ", "a\nb", "bell\a", "del\x7f"} {
+		if _, err := bug.NormalizeLabel(invalid); err == nil {
+			t.Fatalf("The label %q should be rejected", invalid)
+		}
+	}
+}
+
+func TestValidateLabels(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+
+	b, err := operations.Create(rene, "title", "message")
+	checkErr(t, err)
+	checkErr(t, b.Commit(repo))
+
+	// the convenience function normalize the labels
+	checkErr(t, operations.ChangeLabels(nil, b, rene, []string{" ui "}, nil))
+	checkErr(t, b.Commit(repo))
+	if snap := b.Compile(); !snap.ContainsLabel("ui") {
+		t.Fatalf("Unexpected labels %v", snap.Labels)
+	}
+
+	if operations.ChangeLabels(nil, b, rene, []string{"a\nb"}, nil) == nil {
+		t.Fatal("A malformed label should be rejected")
+	}
+
+	// the operations built otherwise, like by an import, are validated
+	// when committed
+	for _, invalid := range []bug.Label{"", " ui", "a\x00b"} {
+		op := operations.NewLabelChangeOperation(rene, []bug.Label{invalid}, nil)
+		if op.Validate() == nil {
+			t.Fatalf("The label %q should be rejected", invalid)
+		}
+
+		b, err := bug.ReadLocalBug(repo, b.Id())
+		checkErr(t, err)
+		b.Append(op)
+		if b.Commit(repo) == nil {
+			t.Fatalf("The label %q should not be committed", invalid)
+		}
+	}
+
+	// a malformed label can still be removed
+	op := operations.NewLabelChangeOperation(rene, nil, []bug.Label{" junk"})
+	checkErr(t, op.Validate())
+}
@@ -0,0 +1,234 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/bug/operations"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func checkLimitExceeded(t *testing.T, err error, limit string) {
+	t.Helper()
+
+	exceeded, ok := err.(*bug.ErrLimitExceeded)
+	if !ok || exceeded.Limit != limit {
+		t.Fatalf("Expected the %s limit to be exceeded, got %v", limit, err)
+	}
+}
+
+func TestCommentLengthLimit(t *testing.T) {
+	bug.SetLimits(bug.Limits{MaxCommentLength: 10})
+	defer bug.SetLimits(bug.DefaultLimits)
+
+	checkErr(t, operations.NewAddCommentOp(rene, strings.Repeat("a", 10), nil).Validate())
+
+	err := operations.NewAddCommentOp(rene, strings.Repeat("a", 11), nil).Validate()
+	checkLimitExceeded(t, err, "comment-length")
+
+	err = operations.NewCreateOp(rene, "title", strings.Repeat("a", 11), nil).Validate()
+	checkLimitExceeded(t, err, "comment-length")
+
+	// written before the limit was lowered, and still read
+	bug.SetLimits(bug.DefaultLimits)
+	pack := bug.OperationPack{}
+	pack.Append(operations.NewAddCommentOp(rene, strings.Repeat("a", 11), nil))
+	data, err := pack.Serialize()
+	checkErr(t, err)
+
+	bug.SetLimits(bug.Limits{MaxCommentLength: 10})
+	_, err = bug.ParseOperationPack(data)
+	checkErr(t, err)
+
+	// only the hard limits apply when reading
+	defer setHardLimits(bug.Limits{MaxCommentLength: 10})()
+	_, err = bug.ParseOperationPack(data)
+	checkLimitExceeded(t, err, "comment-length")
+}
+
+// setHardLimits change the hard limits, and return the function restoring
+// them
+func setHardLimits(l bug.Limits) func() {
+	previous := bug.HardLimits
+	bug.HardLimits = l
+	return func() { bug.HardLimits = previous }
+}
+
+func TestLabelsPerOperationLimit(t *testing.T) {
+	bug.SetLimits(bug.Limits{MaxLabelsPerOperation: 3})
+	defer bug.SetLimits(bug.DefaultLimits)
+
+	op := operations.NewLabelChangeOperation(rene, []bug.Label{"a", "b"}, []bug.Label{"c"})
+	checkErr(t, op.Validate())
+
+	op = operations.NewLabelChangeOperation(rene, []bug.Label{"a", "b"}, []bug.Label{"c", "d"})
+	checkLimitExceeded(t, op.Validate(), "labels-per-operation")
+
+	pack := bug.OperationPack{}
+	pack.Append(op)
+	data, err := pack.Serialize()
+	checkErr(t, err)
+
+	_, err = bug.ParseOperationPack(data)
+	checkErr(t, err)
+
+	defer setHardLimits(bug.Limits{MaxLabelsPerOperation: 3})()
+	_, err = bug.ParseOperationPack(data)
+	checkLimitExceeded(t, err, "labels-per-operation")
+}
+
+func TestOperationsPerPackLimit(t *testing.T) {
+	bug.SetLimits(bug.Limits{MaxOperationsPerPack: 3})
+	defer bug.SetLimits(bug.DefaultLimits)
+
+	repo := repository.NewMockRepoForTest()
+
+	b, err := operations.Create(rene, "title", "message")
+	checkErr(t, err)
+	checkErr(t, operations.Comment(b, rene, "first"))
+	checkErr(t, operations.Comment(b, rene, "second"))
+	checkErr(t, b.Commit(repo))
+
+	for _, message := range []string{"third", "fourth", "fifth", "sixth"} {
+		checkErr(t, operations.Comment(b, rene, message))
+	}
+	checkLimitExceeded(t, b.Commit(repo), "operations-per-pack")
+
+	pack := bug.OperationPack{}
+	for i := 0; i < 4; i++ {
+		pack.Append(operations.NewAddCommentOp(rene, "message", nil))
+	}
+	data, err := pack.Serialize()
+	checkErr(t, err)
+
+	_, err = bug.ParseOperationPack(data)
+	checkErr(t, err)
+
+	defer setHardLimits(bug.Limits{MaxOperationsPerPack: 3})()
+	_, err = bug.ParseOperationPack(data)
+	checkLimitExceeded(t, err, "operations-per-pack")
+
+	pack.Operations = pack.Operations[:3]
+	data, err = pack.Serialize()
+	checkErr(t, err)
+
+	_, err = bug.ParseOperationPack(data)
+	checkErr(t, err)
+}
+
+func TestLoadLimits(t *testing.T) {
+	defer bug.SetLimits(bug.DefaultLimits)
+
+	repo := repository.NewMockRepoForTest()
+	checkErr(t, repo.SetConfig(bug.MaxLabelsPerOperationConfigKey, "5"))
+	checkErr(t, repo.SetConfig(bug.MaxCommentLengthConfigKey, "0"))
+	checkErr(t, bug.LoadLimits(repo))
+
+	expected := bug.DefaultLimits
+	expected.MaxLabelsPerOperation = 5
+	expected.MaxCommentLength = 0
+	if bug.CurrentLimits() != expected {
+		t.Fatalf("Unexpected limits %+v", bug.CurrentLimits())
+	}
+
+	// a zero limit disable the check
+	checkErr(t, operations.NewAddCommentOp(rene, strings.Repeat("a", 2*1024*1024), nil).Validate())
+
+	checkErr(t, repo.SetConfig(bug.MaxOperationsPerPackConfigKey, "many"))
+	if err := bug.LoadLimits(repo); err == nil {
+		t.Fatal("An invalid limit should be reported")
+	}
+}
+
+func TestMessageBlobLengthLimit(t *testing.T) {
+	defer bug.SetLimits(bug.DefaultLimits)
+
+	repo := repository.NewMockRepoForTest()
+
+	b, err := operations.Create(rene, "title", "message")
+	checkErr(t, err)
+	checkErr(t, operations.Comment(b, rene, largeMessage))
+	checkErr(t, b.Commit(repo))
+
+	// the message held by its own blob is above the configured limit, but
+	// it's already stored
+	bug.SetLimits(bug.Limits{MaxCommentLength: bug.MessageBlobThreshold})
+
+	read, err := bug.ReadLocalBug(repo, b.Id())
+	checkErr(t, err)
+
+	problems, err := read.CheckMessageBlobs(repo)
+	checkErr(t, err)
+	if len(problems) != 0 || lastComment(read).GetMessage() != largeMessage {
+		t.Fatalf("The stored message should be read, got %v", problems)
+	}
+
+	// above the hard limit
+	defer setHardLimits(bug.Limits{MaxCommentLength: bug.MessageBlobThreshold})()
+
+	read, err = bug.ReadLocalBug(repo, b.Id())
+	checkErr(t, err)
+
+	problems, err = read.CheckMessageBlobs(repo)
+	checkErr(t, err)
+	if len(problems) != 1 || !strings.Contains(problems[0], "comment-length") {
+		t.Fatalf("The size of the message blob should be checked, got %v", problems)
+	}
+
+	if message := lastComment(read).GetMessage(); !strings.HasPrefix(message, "[message unavailable") {
+		t.Fatal("The message above the limit should not be loaded")
+	}
+}
+
+func TestReceivedLimits(t *testing.T) {
+	defer bug.SetLimits(bug.DefaultLimits)
+
+	repoA, repoB, remote := setupRepos(t)
+	defer cleanupRepos(repoA, repoB, remote)
+
+	pushAndFetch := func() {
+		_, err := bug.Push(repoA, "origin")
+		checkErr(t, err)
+		_, err = bug.Fetch(repoB, "origin")
+		checkErr(t, err)
+	}
+
+	b, err := operations.Create(rene, "title", "message")
+	checkErr(t, err)
+	checkErr(t, operations.Comment(b, rene, strings.Repeat("a", 20)))
+	checkErr(t, b.Commit(repoA))
+	pushAndFetch()
+
+	if results := mergeResults(t, repoB, "origin"); results[b.Id()] != bug.MsgMergeNew {
+		t.Fatalf("Unexpected merge %v", results)
+	}
+
+	// the comment already merged is still accepted with a lower limit
+	bug.SetLimits(bug.Limits{MaxCommentLength: 10})
+
+	checkErr(t, operations.Comment(b, rene, "short"))
+	checkErr(t, b.Commit(repoA))
+	pushAndFetch()
+
+	if results := mergeResults(t, repoB, "origin"); results[b.Id()] != bug.MsgMergeUpdated {
+		t.Fatalf("Unexpected merge %v", results)
+	}
+
+	// a new comment above the limit is not
+	bug.SetLimits(bug.DefaultLimits)
+	checkErr(t, operations.Comment(b, rene, strings.Repeat("b", 20)))
+	checkErr(t, b.Commit(repoA))
+	pushAndFetch()
+
+	bug.SetLimits(bug.Limits{MaxCommentLength: 10})
+	if results := mergeResults(t, repoB, "origin"); results[b.Id()] != bug.MsgMergeInvalid {
+		t.Fatalf("Unexpected merge %v", results)
+	}
+
+	read, err := bug.ReadLocalBug(repoB, b.Id())
+	checkErr(t, err)
+	if len(read.Compile().Comments) != 3 {
+		t.Fatal("The comment above the limit should not be merged")
+	}
+}
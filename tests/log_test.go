@@ -0,0 +1,68 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/bug/operations"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func TestBugLog(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+
+	b, err := operations.Create(rene, "title", "first line\nsecond line")
+	checkErr(t, err)
+	checkErr(t, b.Commit(repo))
+	first := b.LastCommitHash()
+
+	b.Append(operations.NewLabelChangeOperation(rene, []bug.Label{"bug"}, []bug.Label{"ui"}))
+	checkErr(t, operations.SetTitle(b, rene, "new title"))
+	checkErr(t, b.Commit(repo))
+	second := b.LastCommitHash()
+
+	operations.Close(b, rene)
+
+	log, err := b.Log()
+	checkErr(t, err)
+
+	expected := []struct {
+		opType  bug.OperationType
+		summary string
+		commit  string
+	}{
+		{bug.CreateOp, `"title": first line...`, first.String()},
+		{bug.LabelChangeOp, "+bug -ui", second.String()},
+		{bug.SetTitleOp, `"title" -> "new title"`, second.String()},
+		// staged, not committed yet
+		{bug.SetStatusOp, "closed", ""},
+	}
+
+	if len(log) != len(expected) {
+		t.Fatalf("Expected %d operations, got %d", len(expected), len(log))
+	}
+
+	for i, entry := range log {
+		want := expected[i]
+
+		if entry.Operation.OpType() != want.opType {
+			t.Fatalf("Unexpected operation %s at %d, expected %s", entry.Operation.OpType(), i, want.opType)
+		}
+		if summary := entry.Operation.Summary(); summary != want.summary {
+			t.Fatalf("Unexpected summary %q, expected %q", summary, want.summary)
+		}
+		if entry.Commit.String() != want.commit {
+			t.Fatalf("Unexpected commit %s for %s, expected %s", entry.Commit, entry.Operation.OpType(), want.commit)
+		}
+
+		hash, err := bug.HashOperation(entry.Operation)
+		checkErr(t, err)
+		if entry.Hash != hash {
+			t.Fatalf("Unexpected hash %s, expected %s", entry.Hash, hash)
+		}
+	}
+
+	if log[1].EditTime <= log[0].EditTime {
+		t.Fatalf("The second commit should have a later edit time, got %d and %d", log[0].EditTime, log[1].EditTime)
+	}
+}
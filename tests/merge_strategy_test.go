@@ -0,0 +1,339 @@
+package tests
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/internal/testfixture"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+// setupMergeRepos create two repositories using each other as remote, with
+// the merge strategy
+func setupMergeRepos(t *testing.T) (repoA, repoB *repository.GitRepo) {
+	repoA = createRepo(false)
+	repoB = createRepo(false)
+
+	checkErr(t, repoA.AddRemote("b", "file://"+repoB.GetPath()))
+	checkErr(t, repoB.AddRemote("a", "file://"+repoA.GetPath()))
+
+	checkErr(t, repoA.SetConfig(bug.MergeStrategyConfigKey, "merge"))
+	checkErr(t, repoB.SetConfig(bug.MergeStrategyConfigKey, "merge"))
+
+	return repoA, repoB
+}
+
+func git(t *testing.T, repo *repository.GitRepo, args ...string) string {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repo.GetPath()
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %s: %s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func comments(t *testing.T, repo repository.Repo, id string) []string {
+	b, err := bug.ReadLocalBug(repo, id)
+	checkErr(t, err)
+
+	var result []string
+	for _, comment := range b.Compile().Comments {
+		result = append(result, comment.Message)
+	}
+	return result
+}
+
+func TestMergeStrategyDiverged(t *testing.T) {
+	repoA, repoB := setupMergeRepos(t)
+	defer cleanupRepo(repoA)
+	defer cleanupRepo(repoB)
+
+	bug1, err := testfixture.BuildBug(repoA, testfixture.New(rene).Create("bug1", "message"))
+	checkErr(t, err)
+
+	bug2, err := testfixture.Pull(repoB, "a", bug1.Id())
+	checkErr(t, err)
+
+	checkErr(t, testfixture.Diverge(
+		repoA, bug1, testfixture.New(rene).Comment("from A"),
+		repoB, bug2, testfixture.New(isaac).Comment("from B"),
+	))
+	localCommit := bug1.LastCommitHash()
+
+	checkErr(t, bug.Pull(repoA, os.Stdout, "b"))
+
+	ref := "refs/bugs/" + bug1.Id()
+
+	// the local commit is kept, not rewritten
+	git(t, repoA, "merge-base", "--is-ancestor", string(localCommit), ref)
+
+	if git(t, repoA, "rev-list", "--merges", "--count", ref) != "1" {
+		t.Fatal("The histories should be joined with a merge commit")
+	}
+
+	// the merge commit is simply fast-forwarded
+	checkErr(t, bug.Pull(repoB, os.Stdout, "a"))
+
+	if git(t, repoA, "rev-parse", ref) != git(t, repoB, "rev-parse", ref) {
+		t.Fatal("Both repositories should have the same history")
+	}
+
+	commentsA := comments(t, repoA, bug1.Id())
+	commentsB := comments(t, repoB, bug1.Id())
+
+	if len(commentsA) != 3 || strings.Join(commentsA, "|") != strings.Join(commentsB, "|") {
+		t.Fatalf("Unexpected comments %v and %v", commentsA, commentsB)
+	}
+}
+
+func TestMergeStrategyCrissCross(t *testing.T) {
+	repoA, repoB := setupMergeRepos(t)
+	defer cleanupRepo(repoA)
+	defer cleanupRepo(repoB)
+
+	bug1, err := testfixture.BuildBug(repoA, testfixture.New(rene).Create("bug1", "message"))
+	checkErr(t, err)
+
+	bug2, err := testfixture.Pull(repoB, "a", bug1.Id())
+	checkErr(t, err)
+
+	checkErr(t, testfixture.Diverge(
+		repoA, bug1, testfixture.New(rene).Comment("from A"),
+		repoB, bug2, testfixture.New(isaac).Comment("from B"),
+	))
+
+	// both merge the other side at the same time
+	_, err = bug.Fetch(repoB, "a")
+	checkErr(t, err)
+
+	checkErr(t, bug.Pull(repoA, os.Stdout, "b"))
+
+	for result := range bug.MergeAll(repoB, "a") {
+		checkErr(t, result.Err)
+	}
+
+	ref := "refs/bugs/" + bug1.Id()
+
+	if git(t, repoA, "rev-parse", ref) == git(t, repoB, "rev-parse", ref) {
+		t.Fatal("Each repository should have its own merge commit")
+	}
+
+	// the criss-cross merge converge
+	checkErr(t, bug.Pull(repoA, os.Stdout, "b"))
+	checkErr(t, bug.Pull(repoB, os.Stdout, "a"))
+
+	if git(t, repoA, "rev-parse", ref) != git(t, repoB, "rev-parse", ref) {
+		t.Fatal("Both repositories should have the same history")
+	}
+
+	if git(t, repoA, "rev-list", "--merges", "--count", ref) != "3" {
+		t.Fatal("Unexpected number of merge commits")
+	}
+
+	// edit on top of the merges
+	bug3, err := bug.ReadLocalBug(repoA, bug1.Id())
+	checkErr(t, err)
+	checkErr(t, testfixture.Edit(repoA, bug3, testfixture.New(rene).Comment("after the merges")))
+
+	checkErr(t, bug.Pull(repoB, os.Stdout, "a"))
+
+	commentsA := comments(t, repoA, bug1.Id())
+	commentsB := comments(t, repoB, bug1.Id())
+
+	if len(commentsA) != 4 || strings.Join(commentsA, "|") != strings.Join(commentsB, "|") {
+		t.Fatalf("Unexpected comments %v and %v", commentsA, commentsB)
+	}
+
+	if commentsA[3] != "after the merges" {
+		t.Fatalf("The last edit should come last, got %v", commentsA)
+	}
+}
+
+func TestMergeStrategyConfig(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+
+	b, err := testfixture.BuildBug(repo, testfixture.New(rene).Create("title", "message"))
+	checkErr(t, err)
+
+	checkErr(t, repo.SetConfig(bug.MergeStrategyConfigKey, "squash"))
+
+	_, err = b.Merge(repo, b)
+	if err == nil || !strings.Contains(err.Error(), bug.MergeStrategyConfigKey) {
+		t.Fatalf("An invalid strategy should be an error, got %v", err)
+	}
+}
+
+// divergeBug create a bug in repoA, pulled in repoB, then edited on both
+// sides. The last local and remote commits of repoA are returned.
+func divergeBug(t *testing.T, repoA, repoB *repository.GitRepo) (id string, local, remote string) {
+	bug1, err := testfixture.BuildBug(repoA, testfixture.New(rene).Create("bug1", "message"))
+	checkErr(t, err)
+
+	bug2, err := testfixture.Pull(repoB, "a", bug1.Id())
+	checkErr(t, err)
+
+	// the comment of A is logically the last one, rather than tied with the
+	// ones of B and ordered by its content
+	checkErr(t, repoA.EditWitness(10))
+	checkErr(t, testfixture.Diverge(
+		repoA, bug1, testfixture.New(rene).Comment("from A"),
+		repoB, bug2, testfixture.New(isaac).Comment("from B").Commit().Comment("from B again"),
+	))
+
+	return bug1.Id(), string(bug1.LastCommitHash()), string(bug2.LastCommitHash())
+}
+
+func isAncestor(t *testing.T, repo *repository.GitRepo, commit, ref string) bool {
+	cmd := exec.Command("git", "merge-base", "--is-ancestor", commit, ref)
+	cmd.Dir = repo.GetPath()
+	return cmd.Run() == nil
+}
+
+func TestMergeStrategyTimelines(t *testing.T) {
+	var timelines []string
+
+	for _, strategy := range []bug.MergeStrategy{bug.MergeStrategyRebase, bug.MergeStrategyRebaseRemote, bug.MergeStrategyMerge} {
+		repoA, repoB := setupMergeRepos(t)
+		// the strategy is only given to the pull of repoA
+		checkErr(t, repoB.SetConfig(bug.MergeStrategyConfigKey, "rebase"))
+
+		id, local, remote := divergeBug(t, repoA, repoB)
+		ref := "refs/bugs/" + id
+
+		checkErr(t, bug.PullWithOptions(repoA, os.Stdout, "b", bug.MergeOptions{Strategy: strategy}))
+
+		// the shape of the history depend on the strategy
+		keepLocal := isAncestor(t, repoA, local, ref)
+		keepRemote := isAncestor(t, repoA, remote, ref)
+		merges := git(t, repoA, "rev-list", "--merges", "--count", ref)
+
+		switch strategy {
+		case bug.MergeStrategyRebase:
+			if keepLocal || !keepRemote || merges != "0" {
+				t.Fatal("Only the local commits should be rewritten")
+			}
+		case bug.MergeStrategyRebaseRemote:
+			if !keepLocal || keepRemote || merges != "0" {
+				t.Fatal("Only the remote commits should be rewritten")
+			}
+		case bug.MergeStrategyMerge:
+			if !keepLocal || !keepRemote || merges != "1" {
+				t.Fatal("Both histories should be kept")
+			}
+		}
+
+		// but not the timeline, ordered by Lamport time
+		commentsA := comments(t, repoA, id)
+		if len(commentsA) != 4 {
+			t.Fatalf("Unexpected comments %v with %s", commentsA, strategy)
+		}
+		timelines = append(timelines, strings.Join(commentsA, "|"))
+
+		// the other side converge, without duplicating the rebased operations
+		checkErr(t, bug.Pull(repoB, os.Stdout, "a"))
+
+		if git(t, repoA, "rev-parse", ref) != git(t, repoB, "rev-parse", ref) {
+			t.Fatalf("Both repositories should have the same history with %s", strategy)
+		}
+		if commentsB := comments(t, repoB, id); strings.Join(commentsB, "|") != timelines[len(timelines)-1] {
+			t.Fatalf("Unexpected comments %v with %s", commentsB, strategy)
+		}
+
+		cleanupRepo(repoA)
+		cleanupRepo(repoB)
+	}
+
+	for _, timeline := range timelines {
+		if timeline != "message|from B|from B again|from A" {
+			t.Fatalf("The strategies should give the same timeline, got %v", timelines)
+		}
+	}
+
+	if strategy, err := bug.ParseMergeStrategy("interleave"); err != nil || strategy != bug.MergeStrategyMerge {
+		t.Fatalf("interleave should be another name for merge, got %v %v", strategy, err)
+	}
+	if strategy, err := bug.ParseMergeStrategy("rebase-local"); err != nil || strategy != bug.MergeStrategyRebase {
+		t.Fatalf("rebase-local should be another name for rebase, got %v %v", strategy, err)
+	}
+	if _, err := bug.ParseMergeStrategy("squash"); err == nil {
+		t.Fatal("An unknown strategy should be rejected")
+	}
+}
+
+func TestMergeStrategyRebaseRemotePush(t *testing.T) {
+	repoA, repoB, remote := setupRepos(t)
+	defer cleanupRepos(repoA, repoB, remote)
+
+	rebaseRemote := bug.MergeOptions{Strategy: bug.MergeStrategyRebaseRemote}
+
+	bugA, err := testfixture.BuildBug(repoA, testfixture.New(rene).Create("bug", "message"))
+	checkErr(t, err)
+
+	bugB, err := testfixture.Fork(repoA, repoB, "origin", bugA.Id())
+	checkErr(t, err)
+
+	// edited on both sides, the remote commits being rewritten in B
+	checkErr(t, testfixture.Diverge(
+		repoA, bugA, testfixture.New(rene).Comment("from A"),
+		repoB, bugB, testfixture.New(isaac).Comment("from B"),
+	))
+	_, err = bug.Push(repoA, "origin")
+	checkErr(t, err)
+	checkErr(t, bug.PullWithOptions(repoB, os.Stdout, "origin", rebaseRemote))
+
+	ref := "refs/bugs/" + bugA.Id()
+	if isAncestor(t, repoB, git(t, remote, "rev-parse", ref), ref) {
+		t.Fatal("The remote commits should be rewritten")
+	}
+
+	ids, err := bug.CheckPushable(repoB, "origin")
+	checkErr(t, err)
+	if len(ids) != 0 {
+		t.Fatalf("The rebased bug should be pushable, got %v", ids)
+	}
+
+	_, err = bug.Push(repoB, "origin")
+	checkErr(t, err)
+
+	if git(t, remote, "rev-parse", ref) != git(t, repoB, "rev-parse", ref) {
+		t.Fatal("The remote version should be replaced")
+	}
+
+	// pushed again without fetching
+	bugB, err = bug.ReadLocalBug(repoB, bugA.Id())
+	checkErr(t, err)
+	checkErr(t, testfixture.Edit(repoB, bugB, testfixture.New(isaac).Comment("from B again")))
+	_, err = bug.Push(repoB, "origin")
+	checkErr(t, err)
+
+	checkErr(t, bug.Pull(repoA, os.Stdout, "origin"))
+	if commentsA := comments(t, repoA, bugA.Id()); len(commentsA) != 4 {
+		t.Fatalf("Unexpected comments %v", commentsA)
+	}
+
+	// the remote changed since the last fetch of B, the lease protect it
+	bugA, err = bug.ReadLocalBug(repoA, bugA.Id())
+	checkErr(t, err)
+	checkErr(t, testfixture.Edit(repoA, bugA, testfixture.New(rene).Comment("from A again")))
+
+	bugB, err = bug.ReadLocalBug(repoB, bugA.Id())
+	checkErr(t, err)
+	checkErr(t, testfixture.Edit(repoB, bugB, testfixture.New(isaac).Comment("from B once more")))
+	checkErr(t, bug.PullWithOptions(repoB, os.Stdout, "origin", rebaseRemote))
+
+	_, err = bug.Push(repoA, "origin")
+	checkErr(t, err)
+	pushedA := git(t, remote, "rev-parse", ref)
+
+	_, err = bug.Push(repoB, "origin")
+	if err == nil {
+		t.Fatal("The push should be rejected once the remote changed")
+	}
+	if git(t, remote, "rev-parse", ref) != pushedA {
+		t.Fatal("The remote version should not be replaced")
+	}
+}
@@ -0,0 +1,195 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/bug/operations"
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util"
+)
+
+// the messages are cleaned up, so without trailing line break
+var largeMessage = strings.TrimSpace(strings.Repeat("a large message\n", bug.MessageBlobThreshold/8))
+
+func lastComment(b *bug.Bug) bug.Comment {
+	snap := b.Compile()
+	return snap.Comments[len(snap.Comments)-1]
+}
+
+func TestMessageBlobRoundTrip(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+
+	b, err := operations.Create(rene, "title", largeMessage)
+	checkErr(t, err)
+	checkErr(t, operations.Comment(b, rene, "a small message"))
+	checkErr(t, b.Commit(repo))
+
+	checkErr(t, operations.Comment(b, isaac, largeMessage))
+	checkErr(t, b.Commit(repo))
+
+	// the committed ops already reference the blob
+	last := b.LastOp().(operations.AddCommentOperation)
+	if last.MessageBlob == "" || last.Message != "" {
+		t.Fatal("The large message should be stored in its own blob")
+	}
+
+	read, err := bug.ReadLocalBug(repo, b.Id())
+	checkErr(t, err)
+
+	create := read.FirstOp().(operations.CreateOperation)
+	if create.MessageBlob == "" || create.Message != "" {
+		t.Fatal("The large message should be read from its own blob")
+	}
+
+	snap := read.Compile()
+	if len(snap.Comments) != 3 {
+		t.Fatalf("Unexpected number of comments %d", len(snap.Comments))
+	}
+	if snap.Comments[0].GetMessage() != largeMessage || snap.Comments[2].GetMessage() != largeMessage {
+		t.Fatal("The large messages should be loaded from their blob")
+	}
+	if snap.Comments[1].GetMessage() != "a small message" {
+		t.Fatal("The small message should be held by the operation")
+	}
+
+	problems, err := read.CheckMessageBlobs(repo)
+	checkErr(t, err)
+	if len(problems) != 0 {
+		t.Fatalf("Unexpected problems %v", problems)
+	}
+}
+
+// readCountingRepo count the data read from git
+type readCountingRepo struct {
+	repository.Repo
+	reads map[util.Hash]int
+}
+
+func (r *readCountingRepo) ReadData(hash util.Hash) ([]byte, error) {
+	r.reads[hash]++
+	return r.Repo.ReadData(hash)
+}
+
+func TestMessageBlobLazy(t *testing.T) {
+	repo := &readCountingRepo{
+		Repo:  repository.NewMockRepoForTest(),
+		reads: make(map[util.Hash]int),
+	}
+
+	b, err := operations.Create(rene, "title", largeMessage)
+	checkErr(t, err)
+	checkErr(t, b.Commit(repo))
+
+	blob := b.FirstOp().(operations.CreateOperation).MessageBlob
+
+	read, err := bug.ReadLocalBug(repo, b.Id())
+	checkErr(t, err)
+
+	// what ls needs
+	snap := read.Compile()
+	if snap.Title != "title" || len(snap.Comments) != 1 || snap.Comments[0].Author != rene {
+		t.Fatal("Unexpected snapshot")
+	}
+
+	if repo.reads[blob] != 0 {
+		t.Fatal("Compiling the bug should not read the message blob")
+	}
+
+	if snap.Comments[0].GetMessage() != largeMessage || snap.Comments[0].OriginalMessage() != largeMessage {
+		t.Fatal("The message should be read when displayed")
+	}
+
+	if repo.reads[blob] != 1 {
+		t.Fatalf("The message blob should be read once, got %d", repo.reads[blob])
+	}
+}
+
+func TestMessageBlobMerge(t *testing.T) {
+	repoA, repoB, remote := setupRepos(t)
+	defer cleanupRepos(repoA, repoB, remote)
+
+	bugA, err := operations.Create(rene, "bug", "message")
+	checkErr(t, err)
+	checkErr(t, bugA.Commit(repoA))
+
+	// A --> remote --> B
+	_, err = bug.Push(repoA, "origin")
+	checkErr(t, err)
+	checkErr(t, bug.Pull(repoB, os.Stdout, "origin"))
+
+	bugB, err := bug.ReadLocalBug(repoB, bugA.Id())
+	checkErr(t, err)
+
+	// concurrent edits, A's commit will be rebased on B's
+	checkErr(t, operations.Comment(bugB, isaac, largeMessage))
+	checkErr(t, bugB.Commit(repoB))
+
+	checkErr(t, operations.Comment(bugA, rene, largeMessage+"from A"))
+	checkErr(t, bugA.Commit(repoA))
+
+	_, err = bug.Push(repoB, "origin")
+	checkErr(t, err)
+	checkErr(t, bug.Pull(repoA, os.Stdout, "origin"))
+
+	// A --> remote --> B, with the rebased commit
+	_, err = bug.Push(repoA, "origin")
+	checkErr(t, err)
+	checkErr(t, bug.Pull(repoB, os.Stdout, "origin"))
+
+	for _, repo := range []repository.Repo{repoA, repoB} {
+		merged, err := bug.ReadLocalBug(repo, bugA.Id())
+		checkErr(t, err)
+
+		snap := merged.Compile()
+		if len(snap.Comments) != 3 {
+			t.Fatalf("Unexpected number of comments %d", len(snap.Comments))
+		}
+		// the order of the concurrent comments doesn't matter here
+		messages := map[string]bool{
+			snap.Comments[1].GetMessage(): true,
+			snap.Comments[2].GetMessage(): true,
+		}
+		if !messages[largeMessage] || !messages[largeMessage+"from A"] {
+			t.Fatal("The large messages should be carried by the merge")
+		}
+
+		problems, err := merged.CheckMessageBlobs(repo)
+		checkErr(t, err)
+		if len(problems) != 0 {
+			t.Fatalf("Unexpected problems %v", problems)
+		}
+	}
+}
+
+func TestMessageBlobMissing(t *testing.T) {
+	repo := createRepo(false)
+	defer cleanupRepo(repo)
+
+	b, err := operations.Create(rene, "title", "message")
+	checkErr(t, err)
+	checkErr(t, operations.Comment(b, rene, largeMessage))
+	checkErr(t, b.Commit(repo))
+
+	blob := b.LastOp().(operations.AddCommentOperation).MessageBlob
+
+	// remove the loose object of the blob
+	path := filepath.Join(repo.GetPath(), ".git", "objects", string(blob[:2]), string(blob[2:]))
+	checkErr(t, os.Remove(path))
+
+	read, err := bug.ReadLocalBug(repo, b.Id())
+	checkErr(t, err)
+
+	problems, err := read.CheckMessageBlobs(repo)
+	checkErr(t, err)
+	if len(problems) != 1 || !strings.Contains(problems[0], string(blob)) {
+		t.Fatalf("The missing blob should be reported, got %v", problems)
+	}
+
+	if !strings.HasPrefix(lastComment(read).GetMessage(), "[message unavailable") {
+		t.Fatal("A missing message should be replaced by a placeholder")
+	}
+}
@@ -0,0 +1,133 @@
+package tests
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func TestNormalizeEmail(t *testing.T) {
+	cases := map[string]string{
+		"rene@descartes.fr":           "rene@descartes.fr",
+		" Rene@Descartes.FR ":         "rene@descartes.fr",
+		"rene+bugs@descartes.fr":      "rene@descartes.fr",
+		"rene+bugs+more@descartes.fr": "rene@descartes.fr",
+		"+rene@descartes.fr":          "+rene@descartes.fr",
+		"not an email":                "not an email",
+	}
+
+	for email, expected := range cases {
+		if normalized := bug.NormalizeEmail(email); normalized != expected {
+			t.Fatalf("%q should be normalized as %q, got %q", email, expected, normalized)
+		}
+	}
+
+	if !rene.HasEmail("isaac@newton.uk", "Rene+git@DESCARTES.fr") {
+		t.Fatal("The email should match")
+	}
+	if rene.HasEmail("rene@descartes.com") || (bug.Person{Name: "anonymous"}).HasEmail("") {
+		t.Fatal("The email should not match")
+	}
+}
+
+func TestIdentityEmails(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+
+	emails, err := bug.IdentityEmails(repo, rene)
+	checkErr(t, err)
+	if len(emails) != 1 || emails[0] != rene.Email {
+		t.Fatalf("Unexpected emails %v", emails)
+	}
+
+	checkErr(t, repo.SetConfig(bug.AlternateEmailsConfigKey, "rene@work.fr, ,descartes@home.fr"))
+
+	emails, err = bug.IdentityEmails(repo, rene)
+	checkErr(t, err)
+	if len(emails) != 3 || emails[1] != "rene@work.fr" || emails[2] != "descartes@home.fr" {
+		t.Fatalf("Unexpected emails %v", emails)
+	}
+}
+
+func searchMine(t *testing.T, backend cache.RepoCacher, q string, mine cache.MineFilter) []string {
+	query, err := cache.ParseQuery(q)
+	checkErr(t, err)
+	query.Mine = mine
+	checkErr(t, query.ResolveMe(backend))
+
+	result, err := backend.Search(query.Match)
+	checkErr(t, err)
+
+	titles := make([]string, len(result))
+	for i, snap := range result {
+		titles[i] = snap.Title
+	}
+	sort.Strings(titles)
+
+	return titles
+}
+
+func checkTitles(t *testing.T, titles []string, expected ...string) {
+	t.Helper()
+	if len(titles) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, titles)
+	}
+	for i := range expected {
+		if titles[i] != expected[i] {
+			t.Fatalf("Expected %v, got %v", expected, titles)
+		}
+	}
+}
+
+func TestQueryMine(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+	checkErr(t, repo.SetConfig("user.name", rene.Name))
+	checkErr(t, repo.SetConfig("user.email", "Rene+git@descartes.fr"))
+	checkErr(t, repo.SetConfig(bug.AlternateEmailsConfigKey, "rene@work.fr"))
+
+	backend := cache.NewRepoCache(repo)
+
+	backend.SetAuthor(rene)
+	_, err := backend.NewBug("authored", "message")
+	checkErr(t, err)
+
+	backend.SetAuthor(isaac)
+	commented, err := backend.NewBug("commented", "message")
+	checkErr(t, err)
+	_, err = backend.NewBug("untouched", "message")
+	checkErr(t, err)
+
+	// with an alternate email
+	backend.SetAuthor(bug.Person{Name: rene.Name, Email: "Rene@Work.fr"})
+	checkErr(t, commented.AddComment("a comment"))
+	checkErr(t, commented.Commit())
+
+	// the identity configured in git
+	backend = cache.NewRepoCache(repo)
+
+	checkTitles(t, searchMine(t, backend, "", cache.MineNone), "authored", "commented", "untouched")
+	checkTitles(t, searchMine(t, backend, "", cache.MineAuthor), "authored")
+	checkTitles(t, searchMine(t, backend, "", cache.MineParticipating), "commented")
+	checkTitles(t, searchMine(t, backend, "", cache.MineAll), "authored", "commented")
+	checkTitles(t, searchMine(t, backend, "status:closed", cache.MineAll))
+
+	// the me token
+	checkTitles(t, searchMine(t, backend, "author:me", cache.MineNone), "authored")
+	checkTitles(t, searchMine(t, backend, "participant:me", cache.MineNone), "commented")
+	checkTitles(t, searchMine(t, backend, "author:ISAAC@newton.uk", cache.MineNone), "commented", "untouched")
+	checkTitles(t, searchMine(t, backend, "author:isaac@newton.uk participant:me", cache.MineNone), "commented")
+	checkTitles(t, searchMine(t, backend, "-author:me", cache.MineNone), "commented", "untouched")
+
+	for _, value := range []string{"author", "participating", "all"} {
+		if _, err := cache.ParseMineFilter(value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, value := range []string{"assigned", "mine", ""} {
+		if _, err := cache.ParseMineFilter(value); err == nil {
+			t.Fatalf("The filter %q should be rejected", value)
+		}
+	}
+}
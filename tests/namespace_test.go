@@ -0,0 +1,186 @@
+package tests
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/bug/operations"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func TestNamespaceIsolation(t *testing.T) {
+	defer bug.SetNamespace(bug.DefaultNamespace)
+
+	repo := repository.NewMockRepoForTest()
+
+	bug1, err := operations.Create(rene, "bug1", "message")
+	checkErr(t, err)
+	err = bug1.Commit(repo)
+	checkErr(t, err)
+
+	err = bug.SetNamespace("frontend")
+	checkErr(t, err)
+
+	ids, err := bug.ListLocalIds(repo)
+	checkErr(t, err)
+	if len(ids) != 0 {
+		t.Fatal("Bugs from another namespace should not be listed")
+	}
+
+	_, err = bug.FindLocalBug(repo, bug1.HumanId())
+	if err == nil {
+		t.Fatal("Bugs from another namespace should not be found")
+	}
+
+	bug2, err := operations.Create(rene, "bug2", "message")
+	checkErr(t, err)
+	err = bug2.Commit(repo)
+	checkErr(t, err)
+
+	bugs := allBugs(t, bug.ReadAllLocalBugs(repo))
+	if len(bugs) != 1 || bugs[0].Id() != bug2.Id() {
+		t.Fatal("Unexpected bugs in namespace")
+	}
+
+	err = bug.SetNamespace(bug.DefaultNamespace)
+	checkErr(t, err)
+
+	bugs = allBugs(t, bug.ReadAllLocalBugs(repo))
+	if len(bugs) != 1 || bugs[0].Id() != bug1.Id() {
+		t.Fatal("Unexpected bugs in default namespace")
+	}
+}
+
+func TestNamespaceValidation(t *testing.T) {
+	defer bug.SetNamespace(bug.DefaultNamespace)
+
+	for _, ns := range []string{"", "heads", "remotes", "git-bug", "a/b", "-foo", "foo bar"} {
+		if bug.SetNamespace(ns) == nil {
+			t.Fatalf("Namespace \"%s\" should be rejected", ns)
+		}
+	}
+
+	if bug.Namespace() != bug.DefaultNamespace {
+		t.Fatal("An invalid namespace should not be selected")
+	}
+
+	checkErr(t, bug.SetNamespace("front-end_2.0"))
+}
+
+func TestNamespaceConfig(t *testing.T) {
+	defer bug.SetNamespace(bug.DefaultNamespace)
+
+	repo := createRepo(false)
+	defer cleanupRepo(repo)
+
+	checkErr(t, bug.SetNamespace("other"))
+
+	err := bug.LoadNamespace(repo)
+	checkErr(t, err)
+	if bug.Namespace() != bug.DefaultNamespace {
+		t.Fatal("The default namespace should be used when not configured")
+	}
+
+	cmd := exec.Command("git", "config", bug.NamespaceConfigKey, "backend")
+	cmd.Dir = repo.GetPath()
+	checkErr(t, cmd.Run())
+
+	err = bug.LoadNamespace(repo)
+	checkErr(t, err)
+	if bug.Namespace() != "backend" {
+		t.Fatal("The configured namespace should be used")
+	}
+}
+
+func TestNamespacePushPull(t *testing.T) {
+	defer bug.SetNamespace(bug.DefaultNamespace)
+
+	repoA, repoB, remote := setupRepos(t)
+	defer cleanupRepos(repoA, repoB, remote)
+
+	checkErr(t, bug.SetNamespace("backend"))
+
+	bug1, err := operations.Create(rene, "bug1", "message")
+	checkErr(t, err)
+	err = bug1.Commit(repoA)
+	checkErr(t, err)
+
+	_, err = bug.Push(repoA, "origin")
+	checkErr(t, err)
+
+	err = bug.Pull(repoB, os.Stdout, "origin")
+	checkErr(t, err)
+
+	exist, err := repoB.RefExist("refs/remotes/origin/backend/" + bug1.Id())
+	checkErr(t, err)
+	if !exist {
+		t.Fatal("The remote ref should be in the same namespace")
+	}
+
+	bugs := allBugs(t, bug.ReadAllLocalBugs(repoB))
+	if len(bugs) != 1 {
+		t.Fatal("Unexpected number of bugs")
+	}
+
+	checkErr(t, bug.SetNamespace(bug.DefaultNamespace))
+
+	err = bug.Pull(repoB, os.Stdout, "origin")
+	checkErr(t, err)
+
+	bugs = allBugs(t, bug.ReadAllLocalBugs(repoB))
+	if len(bugs) != 0 {
+		t.Fatal("Bugs from another namespace should not be pulled")
+	}
+}
+
+func TestRepoNamespace(t *testing.T) {
+	mock := repository.NewMockRepoForTest()
+
+	frontend, err := bug.WithNamespace(mock, "frontend")
+	checkErr(t, err)
+
+	b, err := operations.Create(rene, "bug", "message")
+	checkErr(t, err)
+	checkErr(t, b.Commit(frontend))
+
+	// the namespace in use is untouched
+	if bug.Namespace() != bug.DefaultNamespace || bug.RepoNamespace(mock) != bug.DefaultNamespace {
+		t.Fatal("The namespace in use should not change")
+	}
+
+	ids, err := bug.ListLocalIds(mock)
+	checkErr(t, err)
+	if len(ids) != 0 {
+		t.Fatal("Bugs from another namespace should not be listed")
+	}
+
+	exist, err := mock.RefExist("refs/frontend/" + b.Id())
+	checkErr(t, err)
+	if !exist {
+		t.Fatal("The bug should be stored in the namespace of the repository")
+	}
+
+	read, err := bug.ReadLocalBug(frontend, b.Id())
+	checkErr(t, err)
+	if read.Compile().Title != "bug" {
+		t.Fatal("Unexpected bug")
+	}
+
+	// through the cache as well
+	backend := cache.NewRepoCache(frontend)
+	_, err = backend.NewBug("cached", "message")
+	checkErr(t, err)
+
+	ids, err = bug.ListLocalIds(frontend)
+	checkErr(t, err)
+	if len(ids) != 2 {
+		t.Fatalf("Unexpected bugs %v", ids)
+	}
+
+	if _, err := bug.WithNamespace(mock, "heads"); err == nil {
+		t.Fatal("A reserved namespace should be rejected")
+	}
+}
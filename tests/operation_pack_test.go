@@ -1,8 +1,19 @@
 package tests
 
 import (
-	"github.com/MichaelMure/git-bug/bug"
+	"bytes"
+	"compress/gzip"
+	"encoding/gob"
+	"math/rand"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/bug/operations"
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util"
 )
 
 func TestOperationPackSerialize(t *testing.T) {
@@ -28,3 +39,268 @@ func TestOperationPackSerialize(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestOperationPackCompressed(t *testing.T) {
+	opp := bug.OperationPack{}
+
+	opp.Append(createOp)
+	opp.Append(operations.NewAddCommentOp(rene, strings.Repeat("a verbose discussion ", 500), nil))
+
+	legacy, err := opp.Serialize()
+	checkErr(t, err)
+
+	compressed, err := opp.SerializeCompressed()
+	checkErr(t, err)
+
+	if len(compressed) >= len(legacy) {
+		t.Fatalf("compressed pack is not smaller: %d >= %d", len(compressed), len(legacy))
+	}
+
+	for _, data := range [][]byte{legacy, compressed} {
+		parsed, err := bug.ParseOperationPack(data)
+		checkErr(t, err)
+
+		if !reflect.DeepEqual(parsed.Operations, opp.Operations) {
+			t.Fatal("the operations are different after a round trip")
+		}
+	}
+
+	// a truncated compressed pack is an error, not a panic
+	_, err = bug.ParseOperationPack(compressed[:len(compressed)/2])
+	if err == nil {
+		t.Fatal("a truncated compressed pack should be rejected")
+	}
+}
+
+func TestBugCompressedPacks(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+
+	b, err := operations.Create(rene, "title", "message")
+	checkErr(t, err)
+	checkErr(t, b.Commit(repo))
+
+	checkErr(t, repo.SetConfig(bug.CompressConfigKey, "true"))
+
+	checkErr(t, operations.Comment(b, rene, "compressed comment"))
+	checkErr(t, b.Commit(repo))
+
+	// the same bug mix a legacy and a compressed pack
+	read, err := bug.ReadLocalBug(repo, b.Id())
+	checkErr(t, err)
+
+	snap := read.Compile()
+	if len(snap.Comments) != 2 || snap.Comments[1].Message != "compressed comment" {
+		t.Fatal("the compressed pack was not read back")
+	}
+
+	checkErr(t, repo.SetConfig(bug.CompressConfigKey, "maybe"))
+	checkErr(t, operations.Comment(b, rene, "comment"))
+	if err := b.Commit(repo); err == nil {
+		t.Fatal("an invalid config value should be rejected")
+	}
+}
+
+func TestOperationPackDeclaredCount(t *testing.T) {
+	// a million operations declared in a few kilobytes once compressed
+	var data bytes.Buffer
+	err := gob.NewEncoder(&data).Encode(struct{ Operations []bool }{make([]bool, 1<<20)})
+	checkErr(t, err)
+
+	var compressed bytes.Buffer
+	compressed.WriteString("\x00gz1")
+	writer := gzip.NewWriter(&compressed)
+	_, err = writer.Write(data.Bytes())
+	checkErr(t, err)
+	checkErr(t, writer.Close())
+
+	for _, data := range [][]byte{data.Bytes(), compressed.Bytes()} {
+		_, err = bug.ParseOperationPack(data)
+		if err == nil || !strings.Contains(err.Error(), "operations declared") {
+			t.Fatalf("the operation count should be checked before decoding, got %v", err)
+		}
+	}
+}
+
+// randomString generate a string mixing ascii, unicode and control characters
+func randomString(r *rand.Rand) string {
+	runes := []rune("aZ0 _-\n\té€😀\x00")
+	var b strings.Builder
+	for i := r.Intn(20); i > 0; i-- {
+		b.WriteRune(runes[r.Intn(len(runes))])
+	}
+	return b.String()
+}
+
+func randomPerson(r *rand.Rand) bug.Person {
+	return bug.Person{Name: randomString(r), Email: randomString(r)}
+}
+
+// randomOperation generate an operation of any type. The maps hold at most
+// one entry, as gob encode them in a random order.
+func randomOperation(r *rand.Rand) bug.Operation {
+	author := randomPerson(r)
+
+	var op bug.Operation
+	var base *bug.OpBase
+
+	switch r.Intn(11) {
+	case 0:
+		create := operations.NewCreateOp(author, randomString(r), randomString(r), nil)
+		op, base = &create, &create.OpBase
+	case 1:
+		setTitle := operations.NewSetTitleOp(author, randomString(r), randomString(r))
+		op, base = &setTitle, &setTitle.OpBase
+	case 2:
+		comment := operations.NewAddCommentOp(author, randomString(r), nil)
+		op, base = &comment, &comment.OpBase
+	case 3:
+		setStatus := operations.NewSetStatusOp(author, bug.Status(r.Intn(4)))
+		op, base = &setStatus, &setStatus.OpBase
+	case 4:
+		labelChange := operations.NewLabelChangeOperation(author,
+			[]bug.Label{bug.Label(randomString(r))}, []bug.Label{bug.Label(randomString(r))})
+		labelChange.Observed = map[bug.Label][]util.Hash{
+			labelChange.Removed[0]: {util.Hash(randomString(r))},
+		}
+		op, base = &labelChange, &labelChange.OpBase
+	case 5:
+		setRelation := operations.NewSetRelationOp(author, bug.RelationKind(r.Intn(5)), randomString(r))
+		op, base = &setRelation, &setRelation.OpBase
+	case 6:
+		deleteComment := operations.NewDeleteCommentOp(author, util.Hash(randomString(r)), r.Intn(2) == 0)
+		op, base = &deleteComment, &deleteComment.OpBase
+	case 7:
+		subscribe := operations.NewSubscribeOp(author, randomPerson(r))
+		op, base = &subscribe, &subscribe.OpBase
+	case 8:
+		unsubscribe := operations.NewUnsubscribeOp(author, randomPerson(r))
+		op, base = &unsubscribe, &unsubscribe.OpBase
+	case 9:
+		setField := operations.NewSetCustomFieldOp(author, randomString(r), randomString(r))
+		op, base = &setField, &setField.OpBase
+	default:
+		timeLog := operations.NewAddTimeLogOp(author, time.Duration(r.Int63()-r.Int63()), randomString(r))
+		op, base = &timeLog, &timeLog.OpBase
+	}
+
+	base.UnixTime = r.Int63() - r.Int63()
+	if r.Intn(2) == 0 {
+		base.SetMetadata(randomString(r), randomString(r))
+	}
+
+	// the packs hold the operations by value
+	return reflect.ValueOf(op).Elem().Interface().(bug.Operation)
+}
+
+func TestOperationPackRoundTripProperty(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+
+	for i := 0; i < 500; i++ {
+		opp := bug.OperationPack{}
+		for j := r.Intn(10) + 1; j > 0; j-- {
+			opp.Append(randomOperation(r))
+		}
+
+		for _, serialize := range []func(*bug.OperationPack) ([]byte, error){
+			(*bug.OperationPack).Serialize,
+			(*bug.OperationPack).SerializeCompressed,
+		} {
+			data, err := serialize(&opp)
+			checkErr(t, err)
+
+			parsed, err := bug.ParseOperationPack(data)
+			checkErr(t, err)
+
+			again, err := serialize(parsed)
+			checkErr(t, err)
+
+			if !bytes.Equal(data, again) {
+				t.Fatalf("pack %d is not serialized identically after a round trip", i)
+			}
+		}
+	}
+}
+
+func FuzzParseOperationPack(f *testing.F) {
+	opp := bug.OperationPack{}
+	opp.Append(createOp)
+	opp.Append(setTitleOp)
+	opp.Append(addCommentOp)
+	opp.Append(operations.NewLabelChangeOperation(rene, []bug.Label{"bug"}, nil))
+	opp.Append(operations.NewSetStatusOp(rene, bug.ClosedStatus))
+
+	legacy, err := opp.Serialize()
+	checkErr(f, err)
+	compressed, err := opp.SerializeCompressed()
+	checkErr(f, err)
+
+	f.Add(legacy)
+	f.Add(compressed)
+	f.Add(legacy[:len(legacy)/2])
+	f.Add([]byte{})
+	f.Add([]byte("\x00gz1"))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		parsed, err := bug.ParseOperationPack(data)
+		if err != nil {
+			if parsed != nil {
+				t.Fatal("A pack should not be returned along with an error")
+			}
+			return
+		}
+
+		// an accepted pack must be safe to use
+		if !parsed.IsValid() {
+			t.Fatal("An invalid pack should be an error")
+		}
+		for _, op := range parsed.Operations {
+			if op == nil {
+				t.Fatal("A missing operation should be an error")
+			}
+		}
+
+		serialized, err := parsed.Serialize()
+		checkErr(t, err)
+		if _, err := bug.ParseOperationPack(serialized); err != nil {
+			t.Fatalf("An accepted pack should survive a round trip: %s", err)
+		}
+	})
+}
+
+// The operations of an accepted pack can come from anyone and must be safe to
+// apply, whatever their content
+func FuzzApplyOperationPack(f *testing.F) {
+	r := rand.New(rand.NewSource(42))
+	for i := 0; i < 5; i++ {
+		opp := bug.OperationPack{}
+		for j := 0; j < 5; j++ {
+			opp.Append(randomOperation(r))
+		}
+		data, err := opp.Serialize()
+		checkErr(f, err)
+		f.Add(data)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		parsed, err := bug.ParseOperationPack(data)
+		if err != nil {
+			return
+		}
+
+		snap := bug.NewSnapshot(strings.Repeat("a", 40))
+		for _, op := range parsed.Operations {
+			if _, err := bug.HashOperation(op); err != nil {
+				t.Fatal(err)
+			}
+			op.Files()
+			snap = op.Apply(snap)
+		}
+		snap.Oneline()
+		snap.Summary()
+		snap.StatusHistory()
+		snap.TimeSpentByAuthor()
+		if _, err := snap.CommentHashes(); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
@@ -0,0 +1,116 @@
+package tests
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/bug/operations"
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util"
+)
+
+const zeroHash = util.Hash("0000000000000000000000000000000000000000")
+
+func verifyUpdates(t *testing.T, repo repository.Repo, lines ...string) []bug.RefVerification {
+	t.Helper()
+
+	updates, err := bug.ParseRefUpdates(strings.NewReader(strings.Join(lines, "\n")))
+	checkErr(t, err)
+
+	result, err := bug.VerifyRefUpdates(repo, updates)
+	checkErr(t, err)
+
+	return result
+}
+
+func updateLine(old util.Hash, new util.Hash, ref string) string {
+	return fmt.Sprintf("%s %s %s", old, new, ref)
+}
+
+// commitPack store a commit on top of the head of a bug, with the given pack
+// instead of the last one
+func commitPack(t *testing.T, repo repository.Repo, head util.Hash, pack bug.OperationPack) util.Hash {
+	data, err := pack.Serialize()
+	checkErr(t, err)
+	blob, err := repo.StoreData(data)
+	checkErr(t, err)
+
+	entries, err := repo.ListEntries(head)
+	checkErr(t, err)
+	for i := range entries {
+		if entries[i].Name == "ops" {
+			entries[i].Hash = blob
+		}
+	}
+
+	tree, err := repo.StoreTree(entries)
+	checkErr(t, err)
+	commit, err := repo.StoreCommitWithParent(tree, head)
+	checkErr(t, err)
+
+	return commit
+}
+
+func TestVerifyRefUpdates(t *testing.T) {
+	repo := createRepo(false)
+	defer cleanupRepo(repo)
+
+	b, err := operations.Create(rene, "title", "message")
+	checkErr(t, err)
+	checkErr(t, b.Commit(repo))
+
+	ref := "refs/bugs/" + b.Id()
+	created, err := repo.ResolveRef(ref)
+	checkErr(t, err)
+
+	checkErr(t, operations.Comment(b, rene, "comment"))
+	checkErr(t, b.Commit(repo))
+	commented, err := repo.ResolveRef(ref)
+	checkErr(t, err)
+
+	// a new bug, a fast-forward and a deletion are accepted, the other refs
+	// are ignored
+	result := verifyUpdates(t, repo,
+		updateLine(zeroHash, commented, ref),
+		updateLine(created, commented, ref),
+		updateLine(commented, zeroHash, ref),
+		updateLine(zeroHash, commented, "refs/heads/master"),
+	)
+	if len(result) != 2 {
+		t.Fatalf("Unexpected verifications %+v", result)
+	}
+	for _, v := range result {
+		if len(v.Problems) != 0 {
+			t.Fatalf("The update should be accepted, got %v", v.Problems)
+		}
+	}
+
+	// an invalid operation pushed on top
+	var junk bug.OperationPack
+	junk.Append(operations.NewLabelChangeOperation(rene, []bug.Label{"a\nb"}, nil))
+	invalidOp := commitPack(t, repo, commented, junk)
+
+	// a commit without operation
+	invalidTree := commitPack(t, repo, commented, bug.OperationPack{})
+
+	result = verifyUpdates(t, repo,
+		updateLine(commented, invalidOp, ref),
+		updateLine(zeroHash, invalidOp, ref),
+		updateLine(commented, invalidTree, ref),
+		updateLine(zeroHash, commented, "refs/bugs/not-an-id"),
+	)
+	if len(result) != 4 {
+		t.Fatalf("Unexpected verifications %+v", result)
+	}
+	for i, expected := range []string{"invalid operation", "invalid operation", "no operation", "invalid bug id"} {
+		if len(result[i].Problems) != 1 || !strings.Contains(result[i].Problems[0], expected) {
+			t.Fatalf("Expected a problem with %q for %s, got %v", expected, result[i].Update.Ref, result[i].Problems)
+		}
+	}
+
+	if _, err := bug.ParseRefUpdates(strings.NewReader("abc refs/bugs/x")); err == nil {
+		t.Fatal("A malformed line should be rejected")
+	}
+}
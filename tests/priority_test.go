@@ -0,0 +1,146 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/bug/operations"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func TestSetPriority(t *testing.T) {
+	bug1, err := operations.Create(rene, "title", "message")
+	checkErr(t, err)
+
+	if snap := bug1.Compile(); snap.Priority != bug.NormalPriority {
+		t.Fatalf("The default priority should be normal, got %s", snap.Priority)
+	}
+
+	checkErr(t, operations.SetPriority(bug1, rene, bug.HighPriority))
+
+	if snap := bug1.Compile(); snap.Priority != bug.HighPriority {
+		t.Fatalf("The priority should be set, got %s", snap.Priority)
+	}
+
+	// override
+	checkErr(t, operations.SetPriority(bug1, isaac, bug.LowPriority))
+
+	if snap := bug1.Compile(); snap.Priority != bug.LowPriority {
+		t.Fatalf("The priority should be overridden, got %s", snap.Priority)
+	}
+
+	if operations.SetPriority(bug1, rene, bug.Priority(42)) == nil {
+		t.Fatal("An invalid priority should be rejected")
+	}
+
+	// read back from git
+	repo := repository.NewMockRepoForTest()
+	checkErr(t, bug1.Commit(repo))
+
+	read, err := bug.ReadLocalBug(repo, bug1.Id())
+	checkErr(t, err)
+	if snap := read.Compile(); snap.Priority != bug.LowPriority {
+		t.Fatalf("The priority should be stored, got %s", snap.Priority)
+	}
+}
+
+func TestParsePriority(t *testing.T) {
+	for _, priority := range []bug.Priority{bug.LowPriority, bug.NormalPriority, bug.HighPriority, bug.CriticalPriority} {
+		parsed, err := bug.ParsePriority(priority.String())
+		checkErr(t, err)
+		if parsed != priority {
+			t.Fatalf("Unexpected priority %s", parsed)
+		}
+	}
+
+	if p, err := bug.ParsePriority("HIGH"); err != nil || p != bug.HighPriority {
+		t.Fatal("The priority should be parsed case insensitively")
+	}
+
+	if _, err := bug.ParsePriority("urgent"); err == nil {
+		t.Fatal("An unknown priority should be rejected")
+	}
+}
+
+func createPriorityBugs(t *testing.T) cache.RepoCacher {
+	backend := cache.NewRepoCache(repository.NewMockRepoForTest())
+	backend.SetAuthor(rene)
+
+	for _, title := range []string{"normal", "critical", "low", "high"} {
+		b, err := backend.NewBug(title, "message")
+		checkErr(t, err)
+
+		if title != "normal" {
+			priority, err := bug.ParsePriority(title)
+			checkErr(t, err)
+			checkErr(t, b.SetPriority(priority))
+			checkErr(t, b.Commit())
+		}
+	}
+
+	return backend
+}
+
+func searchTitles(t *testing.T, backend cache.RepoCacher, q string) []string {
+	query, err := cache.ParseQuery(q)
+	checkErr(t, err)
+
+	result, err := backend.Search(query.Match)
+	checkErr(t, err)
+
+	query.Sort(result)
+
+	titles := make([]string, len(result))
+	for i, snap := range result {
+		titles[i] = snap.Title
+	}
+
+	return titles
+}
+
+func TestQueryPriority(t *testing.T) {
+	backend := createPriorityBugs(t)
+
+	titles := searchTitles(t, backend, "priority:high")
+	if len(titles) != 1 || titles[0] != "high" {
+		t.Fatalf("Only the high priority bug should match, got %v", titles)
+	}
+
+	// never set
+	titles = searchTitles(t, backend, "priority:normal")
+	if len(titles) != 1 || titles[0] != "normal" {
+		t.Fatalf("Only the normal priority bug should match, got %v", titles)
+	}
+
+	titles = searchTitles(t, backend, "priority:high priority:critical sort:priority-desc")
+	if len(titles) != 2 || titles[0] != "critical" || titles[1] != "high" {
+		t.Fatalf("Unexpected bugs %v", titles)
+	}
+
+	for _, invalid := range []string{"priority:urgent", "sort:priority", "sort:title-desc"} {
+		if _, err := cache.ParseQuery(invalid); err == nil {
+			t.Fatalf("The query %q should be rejected", invalid)
+		}
+	}
+}
+
+func TestSortPriority(t *testing.T) {
+	backend := createPriorityBugs(t)
+
+	expected := []string{"critical", "high", "normal", "low"}
+
+	titles := searchTitles(t, backend, "sort:priority-desc")
+	for i := range expected {
+		if titles[i] != expected[i] {
+			t.Fatalf("Unexpected order %v", titles)
+		}
+	}
+
+	titles = searchTitles(t, backend, "sort:priority-asc")
+	for i := range expected {
+		if titles[i] != expected[len(expected)-1-i] {
+			t.Fatalf("Unexpected order %v", titles)
+		}
+	}
+}
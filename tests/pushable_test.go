@@ -0,0 +1,82 @@
+package tests
+
+import (
+	"os"
+	"testing"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/bug/operations"
+)
+
+func TestCheckPushable(t *testing.T) {
+	repoA, repoB, remote := setupRepos(t)
+	defer cleanupRepos(repoA, repoB, remote)
+
+	fastForward, err := operations.Create(rene, "fast-forward", "message")
+	checkErr(t, err)
+	checkErr(t, fastForward.Commit(repoA))
+
+	diverged, err := operations.Create(rene, "diverged", "message")
+	checkErr(t, err)
+	checkErr(t, diverged.Commit(repoA))
+
+	_, err = bug.Push(repoA, "origin")
+	checkErr(t, err)
+	checkErr(t, bug.Pull(repoB, os.Stdout, "origin"))
+
+	ids, err := bug.CheckPushable(repoB, "origin")
+	checkErr(t, err)
+	if len(ids) != 0 {
+		t.Fatalf("Nothing should have diverged, got %v", ids)
+	}
+
+	// edited on both sides
+	checkErr(t, operations.Comment(diverged, rene, "from A"))
+	checkErr(t, diverged.Commit(repoA))
+	_, err = bug.Push(repoA, "origin")
+	checkErr(t, err)
+
+	divergedB, err := bug.ReadLocalBug(repoB, diverged.Id())
+	checkErr(t, err)
+	checkErr(t, operations.Comment(divergedB, isaac, "from B"))
+	checkErr(t, divergedB.Commit(repoB))
+
+	// only edited locally
+	fastForwardB, err := bug.ReadLocalBug(repoB, fastForward.Id())
+	checkErr(t, err)
+	checkErr(t, operations.Comment(fastForwardB, isaac, "from B"))
+	checkErr(t, fastForwardB.Commit(repoB))
+
+	// not on the remote yet
+	newBug, err := operations.Create(isaac, "new", "message")
+	checkErr(t, err)
+	checkErr(t, newBug.Commit(repoB))
+
+	// the remote changes are not known before fetching
+	ids, err = bug.CheckPushable(repoB, "origin")
+	checkErr(t, err)
+	if len(ids) != 0 {
+		t.Fatalf("Nothing should have diverged yet, got %v", ids)
+	}
+
+	_, err = bug.Fetch(repoB, "origin")
+	checkErr(t, err)
+
+	ids, err = bug.CheckPushable(repoB, "origin")
+	checkErr(t, err)
+	if len(ids) != 1 || ids[0] != diverged.Id() {
+		t.Fatalf("Only the diverged bug should be reported, got %v", ids)
+	}
+
+	// pushable once merged
+	checkErr(t, bug.Pull(repoB, os.Stdout, "origin"))
+
+	ids, err = bug.CheckPushable(repoB, "origin")
+	checkErr(t, err)
+	if len(ids) != 0 {
+		t.Fatalf("Nothing should have diverged after the merge, got %v", ids)
+	}
+
+	_, err = bug.Push(repoB, "origin")
+	checkErr(t, err)
+}
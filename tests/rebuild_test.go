@@ -0,0 +1,85 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/bug/operations"
+	"github.com/MichaelMure/git-bug/cache"
+)
+
+func TestRebuild(t *testing.T) {
+	repo := createRepo(false)
+	defer cleanupRepo(repo)
+
+	backend := cache.NewRepoCache(repo)
+	backend.SetAuthor(rene)
+
+	var ids []string
+	for _, title := range []string{"first", "second", "third"} {
+		b, err := backend.NewBug(title, "message")
+		checkErr(t, err)
+		ids = append(ids, b.Snapshot().Id())
+	}
+
+	// staged before the rebuild, kept as is
+	staged, _ := backend.CachedBug(ids[0])
+	checkErr(t, staged.SetTitle("staged"))
+
+	// changed outside of the cache
+	outside, err := bug.ReadLocalBug(repo, ids[1])
+	checkErr(t, err)
+	checkErr(t, operations.SetTitle(outside, isaac, "from outside"))
+	checkErr(t, outside.Commit(repo))
+
+	// a ref that doesn't point to a bug
+	emptyTree := git(t, repo, "hash-object", "-t", "tree", "/dev/null")
+	commit := git(t, repo, "commit-tree", emptyTree, "-m", "not a bug")
+	brokenId := strings.Repeat("0", len(ids[0]))
+	git(t, repo, "update-ref", "refs/bugs/"+brokenId, commit)
+
+	var progress []cache.RebuildProgress
+	result, err := backend.Rebuild(cache.RebuildOptions{
+		Jobs: 2,
+		Progress: func(p cache.RebuildProgress) {
+			progress = append(progress, p)
+		},
+	})
+	checkErr(t, err)
+
+	if result.Loaded != 2 || len(result.Failed) != 1 || result.Failed[brokenId] == nil {
+		t.Fatalf("Unexpected result %+v", result)
+	}
+
+	if len(progress) != 4 {
+		t.Fatalf("Expected a progress for each bug, got %d", len(progress))
+	}
+	for i, p := range progress {
+		if p.Done != i+1 || p.Total != 4 {
+			t.Fatalf("Unexpected progress %+v", p)
+		}
+	}
+	if progress[3].ETA() != 0 {
+		t.Fatal("Nothing should be left once done")
+	}
+
+	titles := map[string]string{
+		ids[0]: "staged",
+		ids[1]: "from outside",
+		ids[2]: "third",
+	}
+	for id, title := range titles {
+		b, ok := backend.CachedBug(id)
+		if !ok || b.Snapshot().Title != title {
+			t.Fatalf("Expected %s to be cached with the title %q", id, title)
+		}
+	}
+	if _, ok := backend.CachedBug(brokenId); ok {
+		t.Fatal("The broken bug should not be cached")
+	}
+
+	if _, err := backend.Rebuild(cache.RebuildOptions{Jobs: -1}); err == nil {
+		t.Fatal("A negative number of jobs should be refused")
+	}
+}
@@ -0,0 +1,251 @@
+package tests
+
+import (
+	"bytes"
+	"io/ioutil"
+	"reflect"
+	"testing"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/bug/operations"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+const leakedSecret = "AKIA0123456789SECRET"
+
+// historyHolds tell if a pack or a message stored in the history of a ref
+// hold some text
+func historyHolds(t *testing.T, repo repository.Repo, ref string, text string) bool {
+	commits, err := repo.ListCommits(ref)
+	checkErr(t, err)
+
+	var blobs []repository.TreeEntry
+	for _, commit := range commits {
+		entries, err := repo.ListEntries(commit)
+		checkErr(t, err)
+
+		for _, entry := range entries {
+			switch entry.Name {
+			case "ops", "root":
+				blobs = append(blobs, entry)
+			case "messages":
+				messages, err := repo.ListEntries(entry.Hash)
+				checkErr(t, err)
+				blobs = append(blobs, messages...)
+			}
+		}
+	}
+
+	for _, blob := range blobs {
+		data, err := repo.ReadData(blob.Hash)
+		checkErr(t, err)
+		if bytes.Contains(data, []byte(text)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func pullWith(t *testing.T, repo repository.Repo, opts bug.MergeOptions) {
+	checkErr(t, bug.PullWithOptions(repo, ioutil.Discard, "origin", opts))
+}
+
+func TestRedact(t *testing.T) {
+	repoA, repoB, remote := setupRepos(t)
+	defer cleanupRepos(repoA, repoB, remote)
+
+	b, err := operations.Create(rene, "title", "message")
+	checkErr(t, err)
+	checkErr(t, b.Commit(repoA))
+	checkErr(t, operations.Comment(b, rene, "the key is "+leakedSecret))
+	checkErr(t, b.Commit(repoA))
+	checkErr(t, operations.Comment(b, isaac, "please remove it"))
+	checkErr(t, b.Commit(repoA))
+
+	id := b.Id()
+	ref := "refs/bugs/" + id
+
+	_, err = bug.Push(repoA, "origin")
+	checkErr(t, err)
+	pullWith(t, repoB, bug.MergeOptions{})
+
+	hashes, err := b.Compile().CommentHashes()
+	checkErr(t, err)
+
+	target, err := b.SearchOperation(string(hashes[1])[:10])
+	checkErr(t, err)
+
+	rewritten, err := b.Redact(repoA, target)
+	checkErr(t, err)
+
+	// the commits from the one holding the operation are rewritten
+	if len(rewritten) != 2 {
+		t.Fatalf("Expected 2 rewritten commits, got %d", len(rewritten))
+	}
+	if b.Id() != id || b.LastCommitHash() != rewritten[1].New {
+		t.Fatal("The redacted bug should keep its id and be updated")
+	}
+	if historyHolds(t, repoA, ref, leakedSecret) {
+		t.Fatal("The secret should be removed from the history")
+	}
+
+	expected := []string{"message", bug.RedactionMarker, "please remove it"}
+	if !reflect.DeepEqual(comments(t, repoA, id), expected) {
+		t.Fatalf("Unexpected comments %v", comments(t, repoA, id))
+	}
+
+	// the comment keep its hash, to be referenced by other operations
+	redactedHashes, err := b.Compile().CommentHashes()
+	checkErr(t, err)
+	if !reflect.DeepEqual(hashes, redactedHashes) {
+		t.Fatal("The redacted comment should keep its hash")
+	}
+
+	if _, err := b.Redact(repoA, target); err == nil {
+		t.Fatal("An operation can't be redacted twice")
+	}
+
+	// B comment on the original history, A pull it without the secret
+	bugB, err := bug.ReadLocalBug(repoB, id)
+	checkErr(t, err)
+	checkErr(t, operations.Comment(bugB, isaac, "from B"))
+	checkErr(t, bugB.Commit(repoB))
+	_, err = bug.Push(repoB, "origin")
+	checkErr(t, err)
+
+	pullWith(t, repoA, bug.MergeOptions{})
+
+	expected = append(expected, "from B")
+	if !reflect.DeepEqual(comments(t, repoA, id), expected) {
+		t.Fatalf("Unexpected comments %v", comments(t, repoA, id))
+	}
+	if historyHolds(t, repoA, ref, leakedSecret) {
+		t.Fatal("Merging the original history should not bring the secret back")
+	}
+
+	git(t, repoA, "push", "--force", "origin", ref)
+
+	// B doesn't take the rewritten history unless asked to
+	checkErr(t, operations.Comment(bugB, isaac, "local to B"))
+	checkErr(t, bugB.Commit(repoB))
+
+	_, err = bug.Fetch(repoB, "origin")
+	checkErr(t, err)
+
+	var statuses []string
+	for result := range bug.MergeAll(repoB, "origin") {
+		checkErr(t, result.Err)
+		statuses = append(statuses, result.Status)
+	}
+	if !reflect.DeepEqual(statuses, []string{bug.MsgMergeRedacted}) {
+		t.Fatalf("The redaction should be reported, got %v", statuses)
+	}
+	if !historyHolds(t, repoB, ref, leakedSecret) {
+		t.Fatal("The local history should not be rewritten without --force-redact")
+	}
+
+	pullWith(t, repoB, bug.MergeOptions{ForceRedact: true})
+
+	expected = append(expected, "local to B")
+	if !reflect.DeepEqual(comments(t, repoB, id), expected) {
+		t.Fatalf("Unexpected comments %v", comments(t, repoB, id))
+	}
+	if historyHolds(t, repoB, ref, leakedSecret) {
+		t.Fatal("The secret should be removed from the history of B")
+	}
+
+	// the histories are shared again: B push normally, A fast-forward
+	_, err = bug.Push(repoB, "origin")
+	checkErr(t, err)
+	pullWith(t, repoA, bug.MergeOptions{})
+
+	headA, err := repoA.ResolveRef(ref)
+	checkErr(t, err)
+	headB, err := repoB.ResolveRef(ref)
+	checkErr(t, err)
+	if headA != headB {
+		t.Fatal("Both clones should have the same history")
+	}
+}
+
+func TestRedactRoot(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+
+	b, err := operations.Create(rene, "title "+leakedSecret, "message")
+	checkErr(t, err)
+	checkErr(t, operations.ChangeLabels(nil, b, rene, []string{"bug"}, nil))
+	checkErr(t, b.Commit(repo))
+	checkErr(t, operations.Comment(b, isaac, "comment"))
+	checkErr(t, b.Commit(repo))
+
+	id := b.Id()
+	first := b.FirstCommitHash()
+
+	create, err := bug.HashOperation(b.FirstOp())
+	checkErr(t, err)
+
+	_, err = b.Redact(repo, create)
+	checkErr(t, err)
+
+	read, err := bug.ReadLocalBug(repo, id)
+	checkErr(t, err)
+
+	snap := read.Compile()
+	if read.FirstCommitHash() == first || snap.Title != bug.RedactionMarker || len(snap.Comments) != 2 {
+		t.Fatal("The root commit should be rewritten")
+	}
+	if historyHolds(t, repo, "refs/bugs/"+id, leakedSecret) {
+		t.Fatal("The secret should be removed from the history")
+	}
+
+	redactions, err := read.Redactions(repo)
+	checkErr(t, err)
+	if len(redactions) != 1 || redactions[0] != create {
+		t.Fatalf("Unexpected redactions %v", redactions)
+	}
+
+	// the labels have no text to redact
+	label, err := bug.HashOperation(snap.Operations[1])
+	checkErr(t, err)
+	if _, err := read.Redact(repo, label); err == nil {
+		t.Fatal("A label change can't be redacted")
+	}
+}
+
+func TestRedactionClaimForged(t *testing.T) {
+	repo := createRepo(false)
+	defer cleanupRepo(repo)
+
+	b, err := operations.Create(rene, "title", "message")
+	checkErr(t, err)
+	checkErr(t, operations.Comment(b, rene, "target"))
+	checkErr(t, b.Commit(repo))
+
+	hashes, err := b.Compile().CommentHashes()
+	checkErr(t, err)
+	target := hashes[1]
+
+	// an operation claiming to be the redaction of another one, without
+	// being listed in the redactions of the bug
+	forged := operations.NewAddCommentOp(isaac, "forged", nil)
+	forged.SetMetadata(bug.RedactedMetadataKey, string(target))
+	b.Append(forged)
+	checkErr(t, b.Commit(repo))
+
+	read, err := bug.ReadLocalBug(repo, b.Id())
+	checkErr(t, err)
+	snap := read.Compile()
+
+	hashes, err = snap.CommentHashes()
+	checkErr(t, err)
+	if len(hashes) != 3 || hashes[1] != target || hashes[2] == target {
+		t.Fatalf("The forged operation should be identified by its content, got %v", hashes)
+	}
+
+	index, err := snap.SearchComment(string(target))
+	checkErr(t, err)
+	if snap.Comments[index].Message != "target" {
+		t.Fatalf("The hash should identify the original comment, got %s", snap.Comments[index].Message)
+	}
+}
@@ -0,0 +1,97 @@
+package tests
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/MichaelMure/git-bug/bug/operations"
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util"
+)
+
+func TestResolveRef(t *testing.T) {
+	gitRepo := createRepo(false)
+	defer cleanupRepo(gitRepo)
+
+	for _, repo := range []repository.Repo{gitRepo, repository.NewMockRepoForTest()} {
+		b, err := operations.Create(rene, "title", "message")
+		checkErr(t, err)
+		checkErr(t, b.Commit(repo))
+
+		checkErr(t, operations.Comment(b, rene, "comment"))
+		checkErr(t, b.Commit(repo))
+
+		hash, err := repo.ResolveRef("refs/bugs/" + b.Id())
+		checkErr(t, err)
+
+		if hash != b.LastCommitHash() {
+			t.Fatalf("Expected the last commit %s, got %s", b.LastCommitHash(), hash)
+		}
+
+		if _, err := repo.ResolveRef("refs/bugs/missing"); err == nil {
+			t.Fatal("A missing ref should be an error")
+		}
+	}
+}
+
+func TestForEachRef(t *testing.T) {
+	gitRepo := createRepo(false)
+	defer cleanupRepo(gitRepo)
+
+	for _, repo := range []repository.Repo{gitRepo, repository.NewMockRepoForTest()} {
+		expected := make(map[string]util.Hash)
+
+		for i := 0; i < 5; i++ {
+			b, err := operations.Create(rene, "title", "message")
+			checkErr(t, err)
+			checkErr(t, b.Commit(repo))
+
+			if i%2 == 0 {
+				checkErr(t, operations.Comment(b, rene, "comment"))
+				checkErr(t, b.Commit(repo))
+			}
+
+			expected["refs/bugs/"+b.Id()] = b.LastCommitHash()
+		}
+
+		visited := make(map[string]util.Hash)
+		var order []string
+
+		err := repo.ForEachRef("refs/bugs/", func(ref string, hash util.Hash) error {
+			if _, ok := visited[ref]; ok {
+				t.Fatalf("The ref %s is visited twice", ref)
+			}
+			visited[ref] = hash
+			order = append(order, ref)
+			return nil
+		})
+		checkErr(t, err)
+
+		if !reflect.DeepEqual(visited, expected) {
+			t.Fatalf("Expected %v, got %v", expected, visited)
+		}
+		if !sort.StringsAreSorted(order) {
+			t.Fatalf("The refs should be visited in order, got %v", order)
+		}
+
+		// an error stop the iteration
+		stop := errors.New("stop")
+		count := 0
+		err = repo.ForEachRef("refs/bugs/", func(ref string, hash util.Hash) error {
+			count++
+			return stop
+		})
+		if err != stop || count != 1 {
+			t.Fatalf("Expected the error after one ref, got %v after %d", err, count)
+		}
+
+		// nothing to visit
+		err = repo.ForEachRef("refs/missing/", func(ref string, hash util.Hash) error {
+			t.Fatal("No ref should be visited")
+			return nil
+		})
+		checkErr(t, err)
+	}
+}
@@ -0,0 +1,85 @@
+package tests
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/bug/operations"
+	"github.com/MichaelMure/git-bug/cache"
+)
+
+func TestRefresh(t *testing.T) {
+	repo := createRepo(false)
+	defer cleanupRepo(repo)
+
+	backend := cache.NewRepoCache(repo)
+	backend.SetAuthor(rene)
+
+	moved, err := backend.NewBug("moved", "message")
+	checkErr(t, err)
+	movedId := moved.Snapshot().Id()
+
+	edited, err := backend.NewBug("edited", "message")
+	checkErr(t, err)
+	editedId := edited.Snapshot().Id()
+
+	removed, err := backend.NewBug("removed", "message")
+	checkErr(t, err)
+	removedId := removed.Snapshot().Id()
+
+	notCached, err := operations.Create(isaac, "not cached", "message")
+	checkErr(t, err)
+	checkErr(t, notCached.Commit(repo))
+
+	// the cache is up to date with its own commits
+	checkErr(t, moved.SetTitle("moved again"))
+	checkErr(t, moved.Commit())
+
+	refresh, err := backend.ReadRefresh()
+	checkErr(t, err)
+	if changed := refresh.Apply(); len(changed) != 0 {
+		t.Fatalf("Only the bug not cached should be read, got %v", changed)
+	}
+	if _, ok := backend.CachedBug(notCached.Id()); !ok {
+		t.Fatal("The bug not cached should be cached")
+	}
+
+	// changed outside of the cache
+	for _, id := range []string{movedId, editedId} {
+		b, err := bug.ReadLocalBug(repo, id)
+		checkErr(t, err)
+		checkErr(t, operations.Comment(b, isaac, "from outside"))
+		checkErr(t, b.Commit(repo))
+	}
+	git(t, repo, "update-ref", "-d", "refs/bugs/"+removedId)
+
+	// staged before the refresh, kept as is
+	checkErr(t, edited.AddComment("staged"))
+
+	refresh, err = backend.ReadRefresh()
+	checkErr(t, err)
+
+	if len(moved.Snapshot().Comments) != 1 {
+		t.Fatal("The cache should not change before the refresh is applied")
+	}
+
+	changed := refresh.Apply()
+	expected := []string{movedId, removedId}
+	sort.Strings(expected)
+	if !reflect.DeepEqual(changed, expected) {
+		t.Fatalf("Expected %v to change, got %v", expected, changed)
+	}
+
+	// updated in place
+	if len(moved.Snapshot().Comments) != 2 || moved.Snapshot().Title != "moved again" {
+		t.Fatalf("Unexpected snapshot %+v", moved.Snapshot())
+	}
+	if _, ok := backend.CachedBug(removedId); ok {
+		t.Fatal("The removed bug should be dropped")
+	}
+	if comments := edited.Snapshot().Comments; comments[len(comments)-1].Message != "staged" {
+		t.Fatal("The staged edits should be kept")
+	}
+}
@@ -0,0 +1,58 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/bug/operations"
+)
+
+func TestSetRelation(t *testing.T) {
+	target, err := operations.Create(rene, "target", "message")
+	checkErr(t, err)
+	err = target.Commit(mockRepo)
+	checkErr(t, err)
+
+	bug1, err := operations.Create(rene, "bug1", "message")
+	checkErr(t, err)
+
+	err = operations.SetRelation(bug1, rene, bug.DuplicateRelation, target.Id())
+	checkErr(t, err)
+
+	// the target doesn't have to be known locally
+	other := "0123456789012345678901234567890123456789"
+	err = operations.SetRelation(bug1, rene, bug.BlocksRelation, other)
+	checkErr(t, err)
+
+	// adding the same relation twice has no effect
+	err = operations.SetRelation(bug1, rene, bug.DuplicateRelation, target.Id())
+	checkErr(t, err)
+
+	err = operations.SetRelation(bug1, rene, bug.RelatedToRelation, "abcdef")
+	if err == nil {
+		t.Fatal("a malformed id should be rejected")
+	}
+
+	err = bug1.Commit(mockRepo)
+	checkErr(t, err)
+
+	bug2, err := bug.ReadLocalBug(mockRepo, bug1.Id())
+	checkErr(t, err)
+
+	snap := bug2.Compile()
+
+	expected := []bug.Relation{
+		{Kind: bug.DuplicateRelation, Target: target.Id()},
+		{Kind: bug.BlocksRelation, Target: other},
+	}
+
+	if len(snap.Relations) != len(expected) {
+		t.Fatalf("Unexpected relations %v", snap.Relations)
+	}
+
+	for i := range expected {
+		if snap.Relations[i] != expected[i] {
+			t.Fatalf("Unexpected relations %v", snap.Relations)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package tests
+
+import (
+	"errors"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/bug/operations"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+// fetchRecorder record the fetches, failing them with the given error
+type fetchRecorder struct {
+	repository.Repo
+	remote   string
+	refSpecs []string
+	err      error
+}
+
+func (r *fetchRecorder) FetchRefs(remote string, refSpecs ...string) (string, error) {
+	r.remote = remote
+	r.refSpecs = refSpecs
+	return "", r.err
+}
+
+func TestPullFetchRefSpecs(t *testing.T) {
+	repo := &fetchRecorder{Repo: repository.NewMockRepoForTest()}
+
+	checkErr(t, bug.Pull(repo, ioutil.Discard, "origin"))
+
+	if repo.remote != "origin" || len(repo.refSpecs) == 0 ||
+		repo.refSpecs[0] != "+refs/bugs/*:refs/remotes/origin/bugs/*" {
+		t.Fatalf("Unexpected fetch of %s %v", repo.remote, repo.refSpecs)
+	}
+
+	// the refs of the namespace are fetched
+	checkErr(t, bug.SetNamespace("frontend"))
+	defer bug.SetNamespace(bug.DefaultNamespace)
+
+	checkErr(t, bug.Pull(repo, ioutil.Discard, "upstream"))
+
+	if repo.remote != "upstream" || repo.refSpecs[0] != "+refs/frontend/*:refs/remotes/upstream/frontend/*" {
+		t.Fatalf("Unexpected fetch of %s %v", repo.remote, repo.refSpecs)
+	}
+
+	// nothing is merged when the fetch fail
+	repo.err = errors.New("failed to fetch from the remote 'upstream'")
+	if err := bug.Pull(repo, ioutil.Discard, "upstream"); err != repo.err {
+		t.Fatalf("Expected the fetch error, got %v", err)
+	}
+}
+
+func TestFetchErrorHint(t *testing.T) {
+	repo := createRepo(false)
+	defer cleanupRepo(repo)
+
+	checkErr(t, bug.AddRemote(repo, "unreachable", "https://git-bug.invalid/bugs.git"))
+
+	_, err := bug.Fetch(repo, "unreachable")
+	if err == nil {
+		t.Fatal("The fetch should fail")
+	}
+	if !strings.Contains(err.Error(), "can't be reached") {
+		t.Fatalf("Expected a network hint, got %v", err)
+	}
+}
+
+func TestConfiguredRemote(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+
+	remote, err := bug.ConfiguredRemote(repo)
+	checkErr(t, err)
+	if remote != bug.DefaultRemote {
+		t.Fatalf("Expected the default remote, got %s", remote)
+	}
+
+	if err := bug.CheckRemote(repo, remote); err == nil {
+		t.Fatal("A missing remote should be reported")
+	}
+
+	checkErr(t, repo.SetConfig(bug.RemoteConfigKey, "bugs"))
+
+	remote, err = bug.ConfiguredRemote(repo)
+	checkErr(t, err)
+	if remote != "bugs" {
+		t.Fatalf("Expected the configured remote, got %s", remote)
+	}
+}
+
+func TestAddRemote(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+
+	checkErr(t, bug.AddRemote(repo, "bugs", "https://example.com/bugs.git"))
+	checkErr(t, bug.CheckRemote(repo, "bugs"))
+
+	fetch, err := repo.GetConfig("remote.bugs.fetch")
+	checkErr(t, err)
+	if fetch != "+refs/bugs/*:refs/remotes/bugs/bugs/*" {
+		t.Fatalf("Unexpected fetch refspec %s", fetch)
+	}
+
+	push, err := repo.GetConfig("remote.bugs.push")
+	checkErr(t, err)
+	if push != "refs/bugs/*:refs/bugs/*" {
+		t.Fatalf("Unexpected push refspec %s", push)
+	}
+
+	if err := bug.AddRemote(repo, "bugs", "https://example.com/other.git"); err == nil {
+		t.Fatal("An existing remote should not be replaced")
+	}
+
+	if err := bug.AddRemote(repo, "../bugs", "https://example.com/bugs.git"); err == nil {
+		t.Fatal("An invalid name should be rejected")
+	}
+}
+
+func TestSyncWithBugsRemote(t *testing.T) {
+	repoA := createRepo(false)
+	repoB := createRepo(false)
+	remote := createRepo(true)
+	defer cleanupRepos(repoA, repoB, remote)
+
+	for _, repo := range []*repository.GitRepo{repoA, repoB} {
+		checkErr(t, bug.AddRemote(repo, "bugs", "file://"+remote.GetPath()))
+	}
+
+	bug1, err := operations.Create(rene, "bug1", "message")
+	checkErr(t, err)
+	checkErr(t, bug1.Commit(repoA))
+
+	_, err = bug.Push(repoA, "bugs")
+	checkErr(t, err)
+
+	checkErr(t, bug.Pull(repoB, ioutil.Discard, "bugs"))
+
+	if len(allBugs(t, bug.ReadAllLocalBugs(repoB))) != 1 {
+		t.Fatal("The bug should be synced through the bugs remote")
+	}
+}
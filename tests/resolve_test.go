@@ -0,0 +1,46 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func TestResolveBugTitle(t *testing.T) {
+	backend := cache.NewRepoCache(repository.NewMockRepoForTest())
+	backend.SetAuthor(rene)
+
+	crash, err := backend.NewBug("Crash on startup", "message")
+	checkErr(t, err)
+	_, err = backend.NewBug("Crash when saving", "message")
+	checkErr(t, err)
+	closed, err := backend.NewBug("Slow startup", "message")
+	checkErr(t, err)
+	checkErr(t, closed.Close())
+	checkErr(t, closed.Commit())
+
+	found, err := backend.ResolveBugTitle("crash ON startup")
+	checkErr(t, err)
+	if found.Snapshot().Id() != crash.Snapshot().Id() {
+		t.Fatal("The bug should be found by its title, ignoring the case")
+	}
+
+	// the closed bug is ignored
+	found, err = backend.ResolveBugTitle("startup")
+	checkErr(t, err)
+	if found.Snapshot().Id() != crash.Snapshot().Id() {
+		t.Fatal("Only the open bugs should match")
+	}
+
+	_, err = backend.ResolveBugTitle("crash")
+	if err == nil || err == bug.ErrNoMatchingBug {
+		t.Fatal("An ambiguous title should fail")
+	}
+
+	_, err = backend.ResolveBugTitle("nothing like this")
+	if err != bug.ErrNoMatchingBug {
+		t.Fatal("A title without match should return ErrNoMatchingBug")
+	}
+}
@@ -0,0 +1,110 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/bug/operations"
+	"github.com/MichaelMure/git-bug/cache"
+)
+
+func TestCommitResult(t *testing.T) {
+	result, err := operations.CreateAndCommit(mockRepo, rene, "title", "message")
+	checkErr(t, err)
+
+	b, err := bug.ReadLocalBug(mockRepo, result.Id)
+	checkErr(t, err)
+
+	if result.Commit != b.LastCommitHash() || result.Id != b.Id() {
+		t.Fatal("Unexpected commit hash after creation")
+	}
+
+	if result.Message != b.HumanId()+" created" {
+		t.Fatalf("Unexpected message %s", result.Message)
+	}
+
+	checkResult := func(result bug.Result, message string) {
+		if result.Id != b.Id() {
+			t.Fatal("Unexpected bug id")
+		}
+
+		hashes, err := mockRepo.ListCommits("refs/bugs/" + b.Id())
+		checkErr(t, err)
+
+		if result.Commit != hashes[len(hashes)-1] {
+			t.Fatal("The result should hold the new commit hash")
+		}
+
+		if result.Message != b.HumanId()+" "+message {
+			t.Fatalf("Unexpected message %s", result.Message)
+		}
+	}
+
+	result, err = operations.CommentAndCommit(mockRepo, b, rene, "comment")
+	checkErr(t, err)
+	checkResult(result, "commented")
+
+	result, err = operations.CloseAndCommit(mockRepo, b, rene)
+	checkErr(t, err)
+	checkResult(result, "closed")
+
+	result, err = operations.OpenAndCommit(mockRepo, b, rene)
+	checkErr(t, err)
+	checkResult(result, "opened")
+
+	_, err = operations.CommentAndCommit(mockRepo, b, rene, "\xff")
+	if err == nil {
+		t.Fatal("Invalid comment should be rejected")
+	}
+}
+
+func TestCacheResult(t *testing.T) {
+	repo := createRepo(false)
+	defer cleanupRepo(repo)
+
+	backend := cache.NewRepoCache(repo)
+	backend.SetAuthor(rene)
+
+	result, err := backend.CreateAndCommit("title", "message")
+	checkErr(t, err)
+
+	b, err := backend.ResolveBug(result.Id)
+	checkErr(t, err)
+
+	checkResult := func(result bug.Result, message string) {
+		read, err := bug.ReadLocalBug(repo, result.Id)
+		checkErr(t, err)
+
+		if result.Commit != read.LastCommitHash() {
+			t.Fatal("The result should hold the new commit hash")
+		}
+
+		if result.Message != b.Snapshot().HumanId()+" "+message {
+			t.Fatalf("Unexpected message %s", result.Message)
+		}
+	}
+
+	checkResult(result, "created")
+
+	result, err = b.CommentAndCommit("comment")
+	checkErr(t, err)
+	checkResult(result, "commented")
+
+	// committed along with the staged edits
+	checkErr(t, b.AddComment("staged"))
+	result, err = b.CloseAndCommit()
+	checkErr(t, err)
+	checkResult(result, "closed")
+	if len(b.Snapshot().Comments) != 3 {
+		t.Fatal("The staged comment should be committed")
+	}
+
+	result, err = b.OpenAndCommit()
+	checkErr(t, err)
+	checkResult(result, "opened")
+
+	_, err = b.CommentAndCommit("\xff")
+	if err == nil {
+		t.Fatal("Invalid comment should be rejected")
+	}
+}
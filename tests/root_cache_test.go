@@ -0,0 +1,127 @@
+package tests
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func TestRootCacheLazyRepositories(t *testing.T) {
+	c := cache.NewCache()
+
+	opened := make(map[string]int)
+	repos := make(map[string]repository.Repo)
+
+	for _, ref := range []string{"b", "a"} {
+		ref := ref
+		repos[ref] = repository.NewMockRepoForTest()
+
+		c.RegisterLazyRepository(ref, func() (repository.Repo, error) {
+			opened[ref]++
+			return repos[ref], nil
+		})
+	}
+
+	if refs := c.Repositories(); !reflect.DeepEqual(refs, []string{"a", "b"}) {
+		t.Fatalf("Unexpected repositories %v", refs)
+	}
+	if len(opened) != 0 {
+		t.Fatal("Listing the repositories should not open them")
+	}
+
+	if _, err := c.DefaultRepo(); err == nil {
+		t.Fatal("There should be no default repository with two of them")
+	}
+
+	for i := 0; i < 2; i++ {
+		a, err := c.ResolveRepo("a")
+		checkErr(t, err)
+		if a.Repository() != repos["a"] {
+			t.Fatal("Unexpected repository")
+		}
+	}
+
+	if opened["a"] != 1 || opened["b"] != 0 {
+		t.Fatalf("Only the accessed repository should be opened, and once, got %v", opened)
+	}
+
+	// the caches are independent
+	a, _ := c.ResolveRepo("a")
+	_, err := a.NewBug("title", "message")
+	checkErr(t, err)
+
+	b, err := c.ResolveRepo("b")
+	checkErr(t, err)
+	ids, err := b.AllBugIds()
+	checkErr(t, err)
+	if len(ids) != 0 {
+		t.Fatal("The bugs of a repository should not leak in another one")
+	}
+
+	if _, err := c.ResolveRepo("c"); err == nil {
+		t.Fatal("An unknown repository should be an error")
+	}
+}
+
+func TestRootCacheSlowOpen(t *testing.T) {
+	c := cache.NewCache()
+
+	opening := make(chan struct{})
+	release := make(chan struct{})
+
+	c.RegisterLazyRepository("slow", func() (repository.Repo, error) {
+		close(opening)
+		<-release
+		return repository.NewMockRepoForTest(), nil
+	})
+	c.RegisterLazyRepository("fast", func() (repository.Repo, error) {
+		return repository.NewMockRepoForTest(), nil
+	})
+
+	done := make(chan error)
+	go func() {
+		_, err := c.ResolveRepo("slow")
+		done <- err
+	}()
+
+	<-opening
+
+	// not blocked by the opening of the other repository
+	_, err := c.ResolveRepo("fast")
+	checkErr(t, err)
+	if refs := c.Repositories(); len(refs) != 2 {
+		t.Fatalf("Unexpected repositories %v", refs)
+	}
+
+	close(release)
+	checkErr(t, <-done)
+}
+
+func TestOpenBareRepo(t *testing.T) {
+	bare := createRepo(true)
+	defer cleanupRepo(bare)
+
+	repo, err := repository.NewGitRepo(filepath.Join(bare.GetPath(), "refs"), bug.Witnesser)
+	checkErr(t, err)
+
+	expected, err := filepath.EvalSymlinks(bare.GetPath())
+	checkErr(t, err)
+	actual, err := filepath.EvalSymlinks(repo.GetPath())
+	checkErr(t, err)
+
+	if actual != expected {
+		t.Fatalf("The root of the bare repository should be found, got %s", repo.GetPath())
+	}
+
+	notRepo := createRepo(false)
+	defer cleanupRepo(notRepo)
+
+	_, err = repository.NewGitRepo(filepath.Join(notRepo.GetPath(), ".git"), bug.Witnesser)
+	if err != repository.ErrNotARepo {
+		t.Fatalf("The git directory of a repository with a working tree is not a repository, got %v", err)
+	}
+}
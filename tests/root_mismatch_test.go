@@ -0,0 +1,134 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/bug/operations"
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util"
+)
+
+// forgeRemoteBug store a remote bug with the id of a local one, whose
+// history is another commit
+func forgeRemoteBug(t *testing.T, repo *repository.GitRepo, id string, commit string) *bug.Bug {
+	checkErr(t, repo.UpdateRef("refs/remotes/origin/bugs/"+id, util.Hash(commit)))
+
+	other, err := bug.ReadRemoteBug(repo, "origin", id)
+	checkErr(t, err)
+
+	return other
+}
+
+func checkRootMismatch(t *testing.T, repo *repository.GitRepo, local *bug.Bug, other *bug.Bug) {
+	_, err := local.Merge(repo, other)
+	if _, ok := err.(*bug.ErrRootMismatch); !ok {
+		t.Fatalf("Expected a root mismatch, got %v", err)
+	}
+
+	results := mergeResults(t, repo, "origin")
+	if results[local.Id()] != bug.MsgMergeInvalid {
+		t.Fatalf("The forged bug should be invalid, got %v", results)
+	}
+
+	merged, err := bug.ReadLocalBug(repo, local.Id())
+	checkErr(t, err)
+	if title := merged.Compile().Title; title != "original" {
+		t.Fatalf("The local bug should be untouched, got %s", title)
+	}
+}
+
+func TestMergeRootMismatch(t *testing.T) {
+	repo := createRepo(false)
+	defer cleanupRepo(repo)
+
+	original, err := operations.Create(rene, "original", "message")
+	checkErr(t, err)
+	checkErr(t, original.Commit(repo))
+
+	forged, err := operations.Create(isaac, "forged", "message")
+	checkErr(t, err)
+	checkErr(t, forged.Commit(repo))
+
+	// the history of another bug under the same id
+	other := forgeRemoteBug(t, repo, original.Id(), git(t, repo, "rev-parse", "refs/bugs/"+forged.Id()))
+
+	checkRootMismatch(t, repo, original, other)
+}
+
+func TestMergeFirstOperationMismatch(t *testing.T) {
+	repo := createRepo(false)
+	defer cleanupRepo(repo)
+
+	original, err := operations.Create(rene, "original", "message")
+	checkErr(t, err)
+	checkErr(t, original.Commit(repo))
+
+	forged, err := operations.Create(isaac, "forged", "message")
+	checkErr(t, err)
+	checkErr(t, forged.Commit(repo))
+
+	// the operations of another bug, claiming the root pack of the original
+	root := git(t, repo, "rev-parse", "refs/bugs/"+original.Id()+":root")
+
+	entries, err := repo.ListEntries(util.Hash(git(t, repo, "rev-parse", "refs/bugs/"+forged.Id())))
+	checkErr(t, err)
+	for i := range entries {
+		if entries[i].Name == "root" {
+			entries[i].Hash = util.Hash(root)
+		}
+	}
+
+	tree, err := repo.StoreTree(entries)
+	checkErr(t, err)
+	commit, err := repo.StoreCommit(tree)
+	checkErr(t, err)
+
+	other := forgeRemoteBug(t, repo, original.Id(), string(commit))
+
+	checkRootMismatch(t, repo, original, other)
+}
+
+func TestMergeForgedRedactionMismatch(t *testing.T) {
+	repo := createRepo(false)
+	defer cleanupRepo(repo)
+
+	original, err := operations.Create(rene, "original", "message")
+	checkErr(t, err)
+	checkErr(t, original.Commit(repo))
+
+	firstHash, err := bug.HashOperation(original.FirstOp())
+	checkErr(t, err)
+
+	// an unrelated creation, claiming to be the redaction of the original
+	// one and listed as such in its redactions
+	create := operations.NewCreateOp(isaac, "forged", "message", nil)
+	create.SetMetadata(bug.RedactedMetadataKey, string(firstHash))
+	forged := bug.NewBug()
+	forged.Append(create)
+	checkErr(t, forged.Commit(repo))
+
+	redactions, err := repo.StoreData([]byte(string(firstHash) + "\n"))
+	checkErr(t, err)
+
+	entries, err := repo.ListEntries(util.Hash(git(t, repo, "rev-parse", "refs/bugs/"+forged.Id())))
+	checkErr(t, err)
+	entries = append(entries, repository.TreeEntry{
+		ObjectType: repository.Blob,
+		Hash:       redactions,
+		Name:       "redactions",
+	})
+
+	tree, err := repo.StoreTree(entries)
+	checkErr(t, err)
+	commit, err := repo.StoreCommit(tree)
+	checkErr(t, err)
+
+	other := forgeRemoteBug(t, repo, original.Id(), string(commit))
+
+	if hash, _ := bug.HashOperation(other.FirstOp()); hash != firstHash {
+		t.Fatal("The claimed redaction should be listed, for the forgery to be tested")
+	}
+
+	checkRootMismatch(t, repo, original, other)
+}
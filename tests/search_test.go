@@ -0,0 +1,152 @@
+package tests
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/bug/operations"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/internal/testfixture"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func createBugs(t testing.TB, repo repository.Repo, count int) {
+	for i := 0; i < count; i++ {
+		spec := testfixture.New(rene).
+			Create(fmt.Sprintf("bug %d", i), "message").
+			As(isaac).
+			Comment("comment")
+		if i%3 == 0 {
+			spec.Close()
+		}
+
+		_, err := testfixture.BuildBug(repo, spec)
+		checkErr(t, err)
+	}
+}
+
+func searchWithWorkers(t testing.TB, repo repository.Repo, workers int) []*bug.Snapshot {
+	checkErr(t, repo.SetConfig(cache.WorkersConfigKey, strconv.Itoa(workers)))
+
+	backend := cache.NewRepoCache(repo)
+
+	result, err := backend.Search(func(snap *bug.Snapshot) bool {
+		return snap.Status == bug.OpenStatus
+	})
+	checkErr(t, err)
+
+	return result
+}
+
+func TestSearchConcurrent(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+	createBugs(t, repo, 50)
+
+	sequential := searchWithWorkers(t, repo, 1)
+	if len(sequential) != 33 {
+		t.Fatalf("Unexpected number of open bugs %d", len(sequential))
+	}
+
+	for _, workers := range []int{2, 8, 100} {
+		concurrent := searchWithWorkers(t, repo, workers)
+		if !reflect.DeepEqual(sequential, concurrent) {
+			t.Fatalf("Reading the bugs with %d workers should give the same result", workers)
+		}
+	}
+
+	checkErr(t, repo.SetConfig(cache.WorkersConfigKey, "0"))
+	if _, err := cache.NewRepoCache(repo).Search(nil); err == nil {
+		t.Fatal("An invalid number of workers should be an error")
+	}
+}
+
+func BenchmarkSearch(b *testing.B) {
+	repo := repository.NewMockRepoForTest()
+	createBugs(b, repo, 1000)
+
+	for _, workers := range []int{1, 4} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				searchWithWorkers(b, repo, workers)
+			}
+		})
+	}
+}
+
+func TestSortTime(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+
+	// created in order, edited in reverse order
+	for i, title := range []string{"first", "second", "third"} {
+		b := bug.NewBug()
+		create := operations.NewCreateOp(rene, title, "message", nil)
+		create.UnixTime = int64(1000 + i)
+		b.Append(create)
+		addCommentAt(b, "edit", int64(2000-i))
+		checkErr(t, b.Commit(repo))
+	}
+
+	backend := cache.NewRepoCache(repo)
+
+	expected := map[string][]string{
+		"sort:creation-asc":  {"first", "second", "third"},
+		"sort:creation-desc": {"third", "second", "first"},
+		"sort:edit-asc":      {"third", "second", "first"},
+		"sort:edit-desc":     {"first", "second", "third"},
+	}
+
+	for q, titles := range expected {
+		if result := searchTitles(t, backend, q); !reflect.DeepEqual(result, titles) {
+			t.Fatalf("Unexpected order %v for %q", result, q)
+		}
+	}
+}
+
+func TestQueryNegation(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+
+	labels := map[string][]string{
+		"unlabeled": nil,
+		"wontfix":   {"wontfix"},
+		"ui":        {"UI"},
+		"both":      {"UI", "wontfix"},
+	}
+	for _, title := range []string{"unlabeled", "wontfix", "ui", "both"} {
+		spec := testfixture.New(rene).Create(title, "message")
+		if len(labels[title]) > 0 {
+			spec.Label(labels[title]...)
+		}
+
+		_, err := testfixture.BuildBug(repo, spec)
+		checkErr(t, err)
+	}
+
+	backend := cache.NewRepoCache(repo)
+
+	search := func(q string) []string {
+		titles := searchTitles(t, backend, q)
+		sort.Strings(titles)
+		return titles
+	}
+
+	checkTitles(t, search("no:label"), "unlabeled")
+	checkTitles(t, search("-no:label"), "both", "ui", "wontfix")
+	checkTitles(t, search("-label:wontfix"), "ui", "unlabeled")
+	checkTitles(t, search("label!:wontfix"), "ui", "unlabeled")
+	checkTitles(t, search("label:UI -label:wontfix"), "ui")
+
+	// a bug matching any of the negated terms is excluded
+	checkTitles(t, search("-label:wontfix -label:UI"), "unlabeled")
+	checkTitles(t, search("-label:wontfix -status:open"))
+	checkTitles(t, search("-author:nobody@example.com"), "both", "ui", "unlabeled", "wontfix")
+
+	for _, invalid := range []string{"no:assignee", "no:milestone", "no:title", "-sort:edit-asc", "-foo:bar", "label!:"} {
+		if _, err := cache.ParseQuery(invalid); err == nil {
+			t.Fatalf("The query %q should be rejected", invalid)
+		}
+	}
+}
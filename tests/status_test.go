@@ -0,0 +1,81 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/bug/operations"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func TestStatusHistory(t *testing.T) {
+	b, err := operations.Create(rene, "title", "message")
+	checkErr(t, err)
+
+	checkErr(t, operations.Comment(b, isaac, "comment"))
+	operations.Close(b, isaac)
+	checkErr(t, operations.SetTitle(b, rene, "title2"))
+	operations.Open(b, rene)
+
+	snap := b.Compile()
+	history := snap.StatusHistory()
+
+	expected := []struct {
+		status bug.Status
+		author bug.Person
+	}{
+		{bug.OpenStatus, rene},
+		{bug.ClosedStatus, isaac},
+		{bug.OpenStatus, rene},
+	}
+
+	if len(history) != len(expected) {
+		t.Fatalf("Expected %d status changes, got %d", len(expected), len(history))
+	}
+
+	for i, change := range history {
+		if change.Status != expected[i].status || change.Author != expected[i].author {
+			t.Fatalf("Unexpected status change %d: %v", i, change)
+		}
+	}
+
+	if !history[0].Time().Equal(snap.CreatedAt) {
+		t.Fatal("The opening should be dated at the creation of the bug")
+	}
+
+	if history[len(history)-1].Status != snap.Status {
+		t.Fatal("The last change should be the current status")
+	}
+}
+
+func TestBugTitleStatus(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+
+	b, err := operations.Create(rene, "title", "message")
+	checkErr(t, err)
+
+	if b.Title() != "title" || b.Status() != bug.OpenStatus {
+		t.Fatalf("Unexpected title or status %s %s", b.Title(), b.Status())
+	}
+
+	checkErr(t, b.Commit(repo))
+
+	operations.Close(b, isaac)
+	checkErr(t, operations.SetTitle(b, rene, "title2"))
+	checkErr(t, operations.Comment(b, isaac, "comment"))
+
+	// the staged operations are accounted for
+	snap := b.Compile()
+	if b.Title() != snap.Title || b.Status() != snap.Status {
+		t.Fatalf("Expected %s %s, got %s %s", snap.Title, snap.Status, b.Title(), b.Status())
+	}
+
+	checkErr(t, b.Commit(repo))
+
+	read, err := bug.ReadLocalBug(repo, b.Id())
+	checkErr(t, err)
+
+	if read.Title() != "title2" || read.Status() != bug.ClosedStatus {
+		t.Fatalf("Unexpected title or status %s %s", read.Title(), read.Status())
+	}
+}
@@ -0,0 +1,87 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/bug/operations"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+var isaac = bug.Person{
+	Name:  "Isaac Newton",
+	Email: "isaac@newton.uk",
+}
+
+func TestSubscribe(t *testing.T) {
+	bug1, err := operations.Create(rene, "title", "message")
+	checkErr(t, err)
+
+	if bug1.Compile().IsSubscribed(rene) {
+		t.Fatal("Nobody should be subscribed yet")
+	}
+
+	// subscribing twice has no effect
+	operations.Subscribe(bug1, rene, rene)
+	operations.Subscribe(bug1, rene, rene)
+	// someone can be subscribed by someone else
+	operations.Subscribe(bug1, rene, isaac)
+
+	snap := bug1.Compile()
+	if len(snap.Subscribers) != 2 || !snap.IsSubscribed(rene) || !snap.IsSubscribed(isaac) {
+		t.Fatalf("Unexpected subscribers %v", snap.Subscribers)
+	}
+
+	// unsubscribing twice has no effect
+	operations.Unsubscribe(bug1, rene, rene)
+	operations.Unsubscribe(bug1, rene, rene)
+
+	err = bug1.Commit(mockRepo)
+	checkErr(t, err)
+
+	bug2, err := bug.ReadLocalBug(mockRepo, bug1.Id())
+	checkErr(t, err)
+
+	snap = bug2.Compile()
+	if len(snap.Subscribers) != 1 || snap.IsSubscribed(rene) || !snap.IsSubscribed(isaac) {
+		t.Fatalf("Unexpected subscribers %v", snap.Subscribers)
+	}
+}
+
+func TestAutoSubscribe(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+	c := cache.NewRepoCache(repo)
+
+	c.SetAuthor(rene)
+	b, err := c.NewBug("title", "message")
+	checkErr(t, err)
+
+	if !b.Snapshot().IsSubscribed(rene) {
+		t.Fatal("The author should be subscribed to a new bug")
+	}
+
+	c.SetAuthor(isaac)
+	err = b.AddComment("comment")
+	checkErr(t, err)
+	err = b.AddComment("another comment")
+	checkErr(t, err)
+
+	snap := b.Snapshot()
+	if !snap.IsSubscribed(isaac) || len(snap.Subscribers) != 2 {
+		t.Fatal("A commenter should be subscribed once")
+	}
+
+	err = b.Unsubscribe()
+	checkErr(t, err)
+
+	err = repo.SetConfig(cache.AutoSubscribeConfigKey, "false")
+	checkErr(t, err)
+
+	err = b.AddComment("no subscription")
+	checkErr(t, err)
+
+	if b.Snapshot().IsSubscribed(isaac) {
+		t.Fatal("The auto subscription should be disabled")
+	}
+}
@@ -0,0 +1,174 @@
+package tests
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/bug/operations"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+const crashTemplate = `Crash in {{component}}
+
+Steps to reproduce:
+{{steps}}
+
+Expected: {{expected}}
+Actual: {{actual}}
+Version: {{version?}}
+`
+
+func TestTemplateResolution(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+
+	_, err := bug.LoadTemplate(repo, "crash")
+	if err != bug.ErrTemplateNotFound {
+		t.Fatalf("Unexpected error for a missing template: %v", err)
+	}
+
+	// shared in a ref
+	checkErr(t, bug.StoreTemplate(repo, bug.Template{Name: "crash", Text: crashTemplate}))
+
+	template, err := bug.LoadTemplate(repo, "crash")
+	checkErr(t, err)
+	if template.Text != crashTemplate {
+		t.Fatalf("Unexpected template %q", template.Text)
+	}
+
+	// the git config take precedence
+	checkErr(t, repo.SetConfig("git-bug.template.crash", "Local {{title}}"))
+
+	template, err = bug.LoadTemplate(repo, "crash")
+	checkErr(t, err)
+	if template.Text != "Local {{title}}" {
+		t.Fatalf("The template should be read from the git config, got %q", template.Text)
+	}
+
+	for _, name := range []string{"", "with space", "dot.ted", "sl/ash", "-dash"} {
+		if _, err := bug.LoadTemplate(repo, name); err == nil {
+			t.Fatalf("The template name %q should be rejected", name)
+		}
+		if err := bug.StoreTemplate(repo, bug.Template{Name: name}); err == nil {
+			t.Fatalf("The template name %q should be rejected", name)
+		}
+	}
+}
+
+func TestTemplateFill(t *testing.T) {
+	template := bug.Template{Name: "crash", Text: crashTemplate}
+
+	placeholders := template.Placeholders()
+	if len(placeholders) != 5 || !placeholders["steps"] || placeholders["version"] {
+		t.Fatalf("Unexpected placeholders %v", placeholders)
+	}
+
+	title, message, err := template.Fill(map[string]string{
+		"component": "the parser",
+		"steps":     "1. parse\n2. crash",
+		"expected":  "no crash",
+		"actual":    "crash",
+	})
+	checkErr(t, err)
+
+	if title != "Crash in the parser" {
+		t.Fatalf("Unexpected title %q", title)
+	}
+
+	expected := "Steps to reproduce:\n1. parse\n2. crash\n\nExpected: no crash\nActual: crash\nVersion:"
+	if message != expected {
+		t.Fatalf("Unexpected message %q", message)
+	}
+
+	_, _, err = template.Fill(map[string]string{"component": "x", "expected": "y"})
+	if err == nil || !strings.Contains(err.Error(), "actual, steps") {
+		t.Fatalf("The missing values should be reported, got %v", err)
+	}
+
+	empty := bug.Template{Name: "empty", Text: "{{title?}}\nmessage"}
+	if _, _, err := empty.Fill(nil); err == nil {
+		t.Fatal("An empty title should be an error")
+	}
+}
+
+func TestNewBugFromTemplate(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+	checkErr(t, bug.StoreTemplate(repo, bug.Template{Name: "crash", Text: crashTemplate}))
+
+	_, err := operations.NewBugFromTemplate(repo, "crash", map[string]string{"component": "x"})
+	if err == nil {
+		t.Fatal("Missing values should be an error")
+	}
+
+	ids, err := bug.ListLocalIds(repo)
+	checkErr(t, err)
+	if len(ids) != 0 {
+		t.Fatal("Nothing should be committed")
+	}
+
+	b, err := operations.NewBugFromTemplate(repo, "crash", map[string]string{
+		"component": "the parser",
+		"steps":     "parse",
+		"expected":  "no crash",
+		"actual":    "crash",
+		"version":   "0.3",
+	})
+	checkErr(t, err)
+	checkErr(t, b.Commit(repo))
+
+	snap := b.Compile()
+	if snap.Title != "Crash in the parser" {
+		t.Fatalf("Unexpected title %q", snap.Title)
+	}
+	if !strings.HasSuffix(snap.Comments[0].Message, "Version: 0.3") {
+		t.Fatalf("Unexpected message %q", snap.Comments[0].Message)
+	}
+	if snap.Comments[0].Author.Name != "René Descartes" {
+		t.Fatal("The bug should be authored by the identity configured in git")
+	}
+}
+
+func TestTemplateSync(t *testing.T) {
+	repoA, repoB, remote := setupRepos(t)
+	defer cleanupRepos(repoA, repoB, remote)
+
+	sync := func(from, to repository.Repo) {
+		_, err := bug.Push(from, "origin")
+		checkErr(t, err)
+		checkErr(t, bug.Pull(to, ioutil.Discard, "origin"))
+	}
+
+	text := func(repo repository.Repo) string {
+		template, err := bug.LoadTemplate(repo, "crash")
+		checkErr(t, err)
+		return template.Text
+	}
+
+	checkErr(t, bug.StoreTemplate(repoA, bug.Template{Name: "crash", Text: crashTemplate}))
+	sync(repoA, repoB)
+
+	if text(repoB) != crashTemplate {
+		t.Fatal("The template should be pulled")
+	}
+
+	checkErr(t, bug.StoreTemplate(repoA, bug.Template{Name: "crash", Text: "Crash {{version}}"}))
+	sync(repoA, repoB)
+
+	if text(repoB) != "Crash {{version}}" {
+		t.Fatal("The edit of the template should be pulled")
+	}
+
+	// edited on both sides, the local version is kept and can be pushed
+	checkErr(t, bug.StoreTemplate(repoA, bug.Template{Name: "crash", Text: "From A"}))
+	_, err := bug.Push(repoA, "origin")
+	checkErr(t, err)
+
+	checkErr(t, bug.StoreTemplate(repoB, bug.Template{Name: "crash", Text: "From B"}))
+	checkErr(t, bug.Pull(repoB, ioutil.Discard, "origin"))
+	sync(repoB, repoA)
+
+	if text(repoA) != "From B" || text(repoB) != "From B" {
+		t.Fatalf("Unexpected templates %q and %q", text(repoA), text(repoB))
+	}
+}
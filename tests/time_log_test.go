@@ -0,0 +1,90 @@
+package tests
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/bug/operations"
+)
+
+func TestTimeLog(t *testing.T) {
+	b, err := operations.Create(rene, "title", "message")
+	checkErr(t, err)
+
+	checkErr(t, operations.AddTimeLog(b, rene, 90*time.Minute, "debugging"))
+	checkErr(t, operations.AddTimeLog(b, isaac, time.Hour, ""))
+	checkErr(t, operations.AddTimeLog(b, rene, time.Hour, "fixing"))
+
+	snap := b.Compile()
+
+	if snap.TimeSpent() != 210*time.Minute {
+		t.Fatalf("Unexpected time spent %s", snap.TimeSpent())
+	}
+
+	byAuthor := snap.TimeSpentByAuthor()
+	expected := []bug.AuthorTime{
+		{Author: rene, Duration: 150 * time.Minute},
+		{Author: isaac, Duration: time.Hour},
+	}
+	if len(byAuthor) != len(expected) || byAuthor[0] != expected[0] || byAuthor[1] != expected[1] {
+		t.Fatalf("Unexpected breakdown %v", byAuthor)
+	}
+
+	if snap.TimeLogs[0].Note != "debugging" {
+		t.Fatal("The note should be kept")
+	}
+
+	for _, invalid := range []time.Duration{0, -time.Hour} {
+		if operations.AddTimeLog(b, rene, invalid, "") == nil {
+			t.Fatalf("The duration %s should be invalid", invalid)
+		}
+	}
+}
+
+func TestTimeLogMerge(t *testing.T) {
+	repoA, repoB, remote := setupRepos(t)
+	defer cleanupRepos(repoA, repoB, remote)
+
+	bugA, err := operations.Create(rene, "bug", "message")
+	checkErr(t, err)
+	checkErr(t, operations.AddTimeLog(bugA, rene, time.Hour, ""))
+	checkErr(t, bugA.Commit(repoA))
+
+	// A --> remote --> B
+	_, err = bug.Push(repoA, "origin")
+	checkErr(t, err)
+	checkErr(t, bug.Pull(repoB, os.Stdout, "origin"))
+
+	bugB, err := bug.ReadLocalBug(repoB, bugA.Id())
+	checkErr(t, err)
+
+	// concurrent logs in both clones
+	checkErr(t, operations.AddTimeLog(bugA, rene, 30*time.Minute, ""))
+	checkErr(t, bugA.Commit(repoA))
+
+	checkErr(t, operations.AddTimeLog(bugB, isaac, 2*time.Hour, ""))
+	checkErr(t, operations.AddTimeLog(bugB, isaac, 15*time.Minute, ""))
+	checkErr(t, bugB.Commit(repoB))
+
+	_, err = bug.Push(repoB, "origin")
+	checkErr(t, err)
+	checkErr(t, bug.Pull(repoA, os.Stdout, "origin"))
+
+	_, err = bug.Push(repoA, "origin")
+	checkErr(t, err)
+	checkErr(t, bug.Pull(repoB, os.Stdout, "origin"))
+
+	mergedA, err := bug.ReadLocalBug(repoA, bugA.Id())
+	checkErr(t, err)
+	mergedB, err := bug.ReadLocalBug(repoB, bugA.Id())
+	checkErr(t, err)
+
+	for _, merged := range []*bug.Bug{mergedA, mergedB} {
+		snap := merged.Compile()
+		if snap.TimeSpent() != 225*time.Minute {
+			t.Fatalf("The concurrent logs should sum up, got %s", snap.TimeSpent())
+		}
+	}
+}
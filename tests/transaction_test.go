@@ -0,0 +1,590 @@
+package tests
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/bug/operations"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util"
+)
+
+// failingRepo fail to store commits, that is after the trees are written but
+// before the bug reference is updated
+type failingRepo struct {
+	repository.Repo
+	fail bool
+}
+
+var errStoreCommit = errors.New("simulated failure")
+
+func (r *failingRepo) StoreCommit(treeHash util.Hash) (util.Hash, error) {
+	if r.fail {
+		return "", errStoreCommit
+	}
+	return r.Repo.StoreCommit(treeHash)
+}
+
+func (r *failingRepo) StoreCommitWithParent(treeHash util.Hash, parent util.Hash) (util.Hash, error) {
+	if r.fail {
+		return "", errStoreCommit
+	}
+	return r.Repo.StoreCommitWithParent(treeHash, parent)
+}
+
+func TestCommitFailureBeforeRefUpdate(t *testing.T) {
+	repo := &failingRepo{Repo: repository.NewMockRepoForTest()}
+
+	// failure on the first commit: no ref, no id
+	repo.fail = true
+
+	b, err := operations.Create(rene, "title", "message")
+	checkErr(t, err)
+
+	err = b.Commit(repo)
+	if err != errStoreCommit {
+		t.Fatal("The failure should be reported")
+	}
+
+	ids, err := bug.ListLocalIds(repo)
+	checkErr(t, err)
+	if len(ids) != 0 {
+		t.Fatal("No ref should be created")
+	}
+	if !b.HasPendingOp() || b.LastCommitHash() != "" {
+		t.Fatal("The bug should be left unchanged")
+	}
+
+	// the same bug can be committed once the failure is gone
+	repo.fail = false
+
+	err = b.Commit(repo)
+	checkErr(t, err)
+
+	// failure on a later commit: the ref is left untouched
+	err = operations.Comment(b, rene, "comment")
+	checkErr(t, err)
+
+	before := b.LastCommitHash()
+	repo.fail = true
+
+	err = b.Commit(repo)
+	if err != errStoreCommit {
+		t.Fatal("The failure should be reported")
+	}
+
+	hashes, err := repo.ListCommits("refs/bugs/" + b.Id())
+	checkErr(t, err)
+	if len(hashes) != 1 || hashes[0] != before || b.LastCommitHash() != before {
+		t.Fatal("The ref should not be updated")
+	}
+
+	repo.fail = false
+
+	err = b.Commit(repo)
+	checkErr(t, err)
+
+	stored, err := bug.ReadLocalBug(repo, b.Id())
+	checkErr(t, err)
+	if len(stored.Compile().Comments) != 2 {
+		t.Fatal("Unexpected stored bug")
+	}
+}
+
+// stepFailingRepo fail at the given write of a commit, to simulate an
+// interruption at each step
+type stepFailingRepo struct {
+	repository.Repo
+	failAt int
+	writes int
+}
+
+func (r *stepFailingRepo) step() error {
+	r.writes++
+	if r.writes == r.failAt {
+		return errStoreCommit
+	}
+	return nil
+}
+
+func (r *stepFailingRepo) StoreData(data []byte) (util.Hash, error) {
+	if err := r.step(); err != nil {
+		return "", err
+	}
+	return r.Repo.StoreData(data)
+}
+
+func (r *stepFailingRepo) StoreTree(mapping []repository.TreeEntry) (util.Hash, error) {
+	if err := r.step(); err != nil {
+		return "", err
+	}
+	return r.Repo.StoreTree(mapping)
+}
+
+func (r *stepFailingRepo) StoreCommit(treeHash util.Hash) (util.Hash, error) {
+	if err := r.step(); err != nil {
+		return "", err
+	}
+	return r.Repo.StoreCommit(treeHash)
+}
+
+func (r *stepFailingRepo) StoreCommitWithParent(treeHash util.Hash, parent util.Hash) (util.Hash, error) {
+	if err := r.step(); err != nil {
+		return "", err
+	}
+	return r.Repo.StoreCommitWithParent(treeHash, parent)
+}
+
+func (r *stepFailingRepo) EditTimeIncrement() (util.LamportTime, error) {
+	if err := r.step(); err != nil {
+		return 0, err
+	}
+	return r.Repo.EditTimeIncrement()
+}
+
+func (r *stepFailingRepo) CreateTimeIncrement() (util.LamportTime, error) {
+	if err := r.step(); err != nil {
+		return 0, err
+	}
+	return r.Repo.CreateTimeIncrement()
+}
+
+func (r *stepFailingRepo) Begin() repository.Transaction {
+	return repository.NewTransaction(r.UpdateRefs)
+}
+
+func (r *stepFailingRepo) UpdateRefs(updates []repository.RefUpdate) error {
+	if err := r.step(); err != nil {
+		return err
+	}
+	return r.Repo.UpdateRefs(updates)
+}
+
+func TestCommitFailureAtEachStep(t *testing.T) {
+	steps := 0
+
+	for failAt := 1; ; failAt++ {
+		repo := &stepFailingRepo{Repo: repository.NewMockRepoForTest()}
+
+		b, err := operations.Create(rene, "title", "message")
+		checkErr(t, err)
+		checkErr(t, b.Commit(repo))
+		checkErr(t, operations.Comment(b, rene, "comment"))
+
+		before := b.LastCommitHash()
+
+		repo.failAt = repo.writes + failAt
+		if b.Commit(repo) == nil {
+			break
+		}
+		steps++
+
+		// at worst, some objects are left unreferenced
+		hashes, err := repo.ListCommits("refs/bugs/" + b.Id())
+		checkErr(t, err)
+		if len(hashes) != 1 || hashes[0] != before || b.LastCommitHash() != before || !b.HasPendingOp() {
+			t.Fatalf("The bug should be left unchanged by a failure at step %d", failAt)
+		}
+
+		report, err := bug.VerifyAll(repo)
+		checkErr(t, err)
+		if report.Valid != 1 {
+			t.Fatalf("The bug should stay valid after a failure at step %d: %v", failAt, report.Bugs)
+		}
+
+		checkErr(t, b.Commit(repo))
+
+		stored, err := bug.ReadLocalBug(repo, b.Id())
+		checkErr(t, err)
+		if len(stored.Compile().Comments) != 2 {
+			t.Fatalf("Unexpected bug committed after a failure at step %d", failAt)
+		}
+	}
+
+	// the blobs, the tree, the clock, the commit and the ref
+	if steps < 5 {
+		t.Fatalf("Only %d steps were tested", steps)
+	}
+}
+
+func TestStaleRefLock(t *testing.T) {
+	repo := createRepo(false)
+	defer cleanupRepo(repo)
+
+	b, err := operations.Create(rene, "title", "message")
+	checkErr(t, err)
+	checkErr(t, b.Commit(repo))
+
+	report, err := bug.VerifyAll(repo)
+	checkErr(t, err)
+	if len(report.StaleLocks) != 0 {
+		t.Fatalf("Unexpected stale locks %v", report.StaleLocks)
+	}
+
+	// as left by a killed git update-ref
+	lock := filepath.Join(repo.GetPath(), ".git", "refs", "bugs", b.Id()+".lock")
+	checkErr(t, ioutil.WriteFile(lock, []byte(b.LastCommitHash()), 0644))
+
+	checkErr(t, operations.Comment(b, rene, "comment"))
+	if b.Commit(repo) == nil {
+		t.Fatal("The locked ref should not be updated")
+	}
+
+	report, err = bug.VerifyAll(repo)
+	checkErr(t, err)
+	if len(report.StaleLocks) != 1 || report.StaleLocks[0] != "refs/bugs/"+b.Id()+".lock" {
+		t.Fatalf("Unexpected stale locks %v", report.StaleLocks)
+	}
+
+	checkErr(t, os.Remove(lock))
+	checkErr(t, b.Commit(repo))
+}
+
+func TestTransaction(t *testing.T) {
+	repo := createRepo(false)
+	defer cleanupRepo(repo)
+
+	b, err := operations.Create(rene, "title", "message")
+	checkErr(t, err)
+	err = b.Commit(repo)
+	checkErr(t, err)
+
+	hash := b.LastCommitHash()
+
+	exist := func(ref string) bool {
+		exist, err := repo.RefExist(ref)
+		checkErr(t, err)
+		return exist
+	}
+
+	// rollback
+	tx := repo.Begin()
+	checkErr(t, tx.UpdateRef("refs/test/a", hash))
+	checkErr(t, tx.Rollback())
+
+	if exist("refs/test/a") {
+		t.Fatal("A rolled back update should not land")
+	}
+
+	if tx.Commit() != repository.ErrTransactionClosed {
+		t.Fatal("A closed transaction should not be reusable")
+	}
+
+	// one invalid update: nothing land
+	tx = repo.Begin()
+	checkErr(t, tx.UpdateRef("refs/test/a", hash))
+	checkErr(t, tx.UpdateRef("refs/test/b", "0123456789012345678901234567890123456789"))
+
+	if tx.Commit() == nil {
+		t.Fatal("Updating a ref to an unknown object should fail")
+	}
+
+	if exist("refs/test/a") || exist("refs/test/b") {
+		t.Fatal("A failed transaction should not update any ref")
+	}
+
+	// success
+	tx = repo.Begin()
+	checkErr(t, tx.UpdateRef("refs/test/a", hash))
+	checkErr(t, tx.UpdateRef("refs/test/b", hash))
+	checkErr(t, tx.Commit())
+
+	if !exist("refs/test/a") || !exist("refs/test/b") {
+		t.Fatal("The refs should be updated")
+	}
+
+	// a removal along with an update
+	tx = repo.Begin()
+	checkErr(t, tx.DeleteRef("refs/test/a"))
+	checkErr(t, tx.UpdateRef("refs/test/c", hash))
+	checkErr(t, tx.Commit())
+
+	if exist("refs/test/a") || !exist("refs/test/c") {
+		t.Fatal("The ref should be moved")
+	}
+}
+
+func TestMultiOperationCommit(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+	backend := cache.NewRepoCache(repo)
+	backend.SetAuthor(rene)
+
+	b, err := backend.NewBug("title", "message")
+	checkErr(t, err)
+
+	// as with close -m
+	checkErr(t, b.AddComment("fixed in 1.2"))
+	checkErr(t, b.Close())
+	checkErr(t, b.Commit())
+
+	snap := b.Snapshot()
+
+	commits, err := repo.ListCommits("refs/bugs/" + snap.Id())
+	checkErr(t, err)
+	if len(commits) != 2 {
+		t.Fatalf("Expected 2 commits, got %d", len(commits))
+	}
+
+	// the creation also subscribe the author
+	last := len(snap.Operations) - 1
+	if snap.SameEdit(last-1) || !snap.SameEdit(last) {
+		t.Fatal("The comment and the closing should be in the same edit session")
+	}
+
+	// a failure after the first operation persist nothing
+	checkErr(t, b.AddComment("comment"))
+	if err := b.SetTitle("Ren\xe9"); err == nil {
+		t.Fatal("An invalid title should be rejected")
+	}
+
+	read, err := bug.ReadLocalBug(repo, snap.Id())
+	checkErr(t, err)
+	if len(read.Compile().Comments) != 2 {
+		t.Fatal("The staged comment should not be persisted")
+	}
+}
+
+func testUpdateRefs(t *testing.T, repo repository.Repo) {
+	b, err := operations.Create(rene, "title", "message")
+	checkErr(t, err)
+	checkErr(t, b.Commit(repo))
+
+	hash := b.LastCommitHash()
+
+	exist := func(ref string) bool {
+		exist, err := repo.RefExist(ref)
+		checkErr(t, err)
+		return exist
+	}
+
+	// an unknown object: nothing land
+	err = repo.UpdateRefs([]repository.RefUpdate{
+		{Ref: "refs/test/a", Hash: hash},
+		{Ref: "refs/test/b", Hash: "0123456789012345678901234567890123456789"},
+	})
+	if err == nil {
+		t.Fatal("Updating a ref to an unknown object should fail")
+	}
+	if exist("refs/test/a") || exist("refs/test/b") {
+		t.Fatal("A failed update should not update any ref")
+	}
+
+	// the same ref twice: nothing land
+	err = repo.UpdateRefs([]repository.RefUpdate{
+		{Ref: "refs/test/a", Hash: hash},
+		{Ref: "refs/test/a", Hash: hash},
+	})
+	if err == nil {
+		t.Fatal("Updating the same ref twice should fail")
+	}
+	if exist("refs/test/a") {
+		t.Fatal("A failed update should not update any ref")
+	}
+
+	checkErr(t, repo.UpdateRefs([]repository.RefUpdate{
+		{Ref: "refs/test/a", Hash: hash},
+		{Ref: "refs/test/b", Hash: hash},
+	}))
+	if !exist("refs/test/a") || !exist("refs/test/b") {
+		t.Fatal("The refs should be updated")
+	}
+
+	checkErr(t, repo.UpdateRefs([]repository.RefUpdate{{Ref: "refs/test/a"}}))
+	if exist("refs/test/a") || !exist("refs/test/b") {
+		t.Fatal("The ref should be removed")
+	}
+
+	other := b.LastCommitHash()
+	checkErr(t, operations.Comment(b, rene, "comment"))
+	checkErr(t, b.Commit(repo))
+	hash = b.LastCommitHash()
+
+	// a ref changed meanwhile: nothing land
+	err = repo.UpdateRefs([]repository.RefUpdate{
+		{Ref: "refs/test/c", Hash: hash, Old: repository.NoRef},
+		{Ref: "refs/test/b", Hash: hash, Old: hash},
+	})
+	if err == nil {
+		t.Fatal("Updating a ref from an unexpected value should fail")
+	}
+	if exist("refs/test/c") {
+		t.Fatal("A failed update should not update any ref")
+	}
+
+	// an existing ref expected to not exist
+	err = repo.UpdateRefs([]repository.RefUpdate{{Ref: "refs/test/b", Hash: hash, Old: repository.NoRef}})
+	if err == nil {
+		t.Fatal("Creating an existing ref should fail")
+	}
+
+	checkErr(t, repo.UpdateRefs([]repository.RefUpdate{
+		{Ref: "refs/test/c", Hash: hash, Old: repository.NoRef},
+		{Ref: "refs/test/b", Hash: hash, Old: other},
+	}))
+	resolved, err := repo.ResolveRef("refs/test/b")
+	checkErr(t, err)
+	if !exist("refs/test/c") || resolved != hash {
+		t.Fatal("The refs should be updated from their expected value")
+	}
+}
+
+func TestCommitConcurrent(t *testing.T) {
+	repo := createRepo(false)
+	defer cleanupRepo(repo)
+
+	b, err := operations.Create(rene, "title", "message")
+	checkErr(t, err)
+	checkErr(t, b.Commit(repo))
+
+	// the same bug, read by two processes
+	first, err := bug.ReadLocalBug(repo, b.Id())
+	checkErr(t, err)
+	second, err := bug.ReadLocalBug(repo, b.Id())
+	checkErr(t, err)
+
+	checkErr(t, operations.Comment(first, rene, "first"))
+	checkErr(t, first.Commit(repo))
+
+	checkErr(t, operations.Comment(second, isaac, "second"))
+	if err := second.Commit(repo); err == nil {
+		t.Fatal("A commit on an outdated bug should fail")
+	}
+
+	read, err := bug.ReadLocalBug(repo, b.Id())
+	checkErr(t, err)
+	if comments := read.Compile().Comments; len(comments) != 2 || comments[1].GetMessage() != "first" {
+		t.Fatal("The first commit should be kept")
+	}
+}
+
+// concurrentCommitRepo commit a comment on a bug right before the references
+// of a pull are updated, like another process would
+type concurrentCommitRepo struct {
+	repository.Repo
+	t  *testing.T
+	id string
+}
+
+func (r *concurrentCommitRepo) UpdateRefs(updates []repository.RefUpdate) error {
+	b, err := bug.ReadLocalBug(r.Repo, r.id)
+	checkErr(r.t, err)
+	checkErr(r.t, operations.Comment(b, isaac, "concurrent"))
+	checkErr(r.t, b.Commit(r.Repo))
+
+	return r.Repo.UpdateRefs(updates)
+}
+
+func TestPullConcurrentCommit(t *testing.T) {
+	repoA, repoB, remote := setupRepos(t)
+	defer cleanupRepos(repoA, repoB, remote)
+
+	b, err := operations.Create(rene, "title", "message")
+	checkErr(t, err)
+	checkErr(t, b.Commit(repoA))
+
+	_, err = bug.Push(repoA, "origin")
+	checkErr(t, err)
+	checkErr(t, bug.Pull(repoB, ioutil.Discard, "origin"))
+
+	checkErr(t, operations.Comment(b, rene, "from A"))
+	checkErr(t, b.Commit(repoA))
+	_, err = bug.Push(repoA, "origin")
+	checkErr(t, err)
+
+	err = bug.Pull(&concurrentCommitRepo{Repo: repoB, t: t, id: b.Id()}, ioutil.Discard, "origin")
+	if err == nil {
+		t.Fatal("The pull should fail instead of overwriting the concurrent commit")
+	}
+
+	read, err := bug.ReadLocalBug(repoB, b.Id())
+	checkErr(t, err)
+	comments := read.Compile().Comments
+	if comments[len(comments)-1].GetMessage() != "concurrent" {
+		t.Fatal("The concurrent commit should be kept")
+	}
+}
+
+func TestUpdateRefs(t *testing.T) {
+	repo := createRepo(false)
+	defer cleanupRepo(repo)
+
+	testUpdateRefs(t, repo)
+}
+
+func TestUpdateRefsMock(t *testing.T) {
+	testUpdateRefs(t, repository.NewMockRepoForTest())
+}
+
+// failingRefsRepo fail to update several references at once
+type failingRefsRepo struct {
+	repository.Repo
+}
+
+func (r *failingRefsRepo) UpdateRefs(updates []repository.RefUpdate) error {
+	return errors.New("simulated failure")
+}
+
+func TestMergeAllTransaction(t *testing.T) {
+	repoA, repoB, remote := setupRepos(t)
+	defer cleanupRepos(repoA, repoB, remote)
+
+	for _, title := range []string{"bug1", "bug2"} {
+		b, err := operations.Create(rene, title, "message")
+		checkErr(t, err)
+		checkErr(t, b.Commit(repoA))
+	}
+
+	_, err := bug.Push(repoA, "origin")
+	checkErr(t, err)
+
+	_, err = bug.Fetch(repoB, "origin")
+	checkErr(t, err)
+
+	merge := func(repo repository.Repo, opts bug.MergeOptions) error {
+		var failure error
+		for result := range bug.MergeAllWithOptions(repo, "origin", opts) {
+			if result.Err != nil {
+				failure = result.Err
+			}
+		}
+		return failure
+	}
+
+	// the bugs are applied all at once, or not at all
+	if merge(&failingRefsRepo{Repo: repoB}, bug.MergeOptions{}) == nil {
+		t.Fatal("The failure to update the refs should be reported")
+	}
+
+	ids, err := bug.ListLocalIds(repoB)
+	checkErr(t, err)
+	if len(ids) != 0 {
+		t.Fatalf("No bug should be merged, got %d", len(ids))
+	}
+
+	// without transaction, each bug is updated on its own
+	checkErr(t, merge(&failingRefsRepo{Repo: repoB}, bug.MergeOptions{NoTransaction: true}))
+
+	ids, err = bug.ListLocalIds(repoB)
+	checkErr(t, err)
+	if len(ids) != 2 {
+		t.Fatalf("The bugs should be merged, got %d", len(ids))
+	}
+
+	// and with, once the failure gone
+	repoC := createRepo(false)
+	defer cleanupRepo(repoC)
+	checkErr(t, repoC.AddRemote("origin", "file://"+remote.GetPath()))
+
+	checkErr(t, bug.Pull(repoC, os.Stdout, "origin"))
+
+	ids, err = bug.ListLocalIds(repoC)
+	checkErr(t, err)
+	if len(ids) != 2 {
+		t.Fatalf("The bugs should be merged, got %d", len(ids))
+	}
+}
@@ -0,0 +1,95 @@
+package tests
+
+import (
+	"os"
+	"testing"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/bug/operations"
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util"
+)
+
+// countingRepo count the tree lookups of each commit
+type countingRepo struct {
+	repository.Repo
+	entries map[util.Hash]int
+	trees   map[util.Hash]int
+}
+
+func newCountingRepo(repo repository.Repo) *countingRepo {
+	return &countingRepo{
+		Repo:    repo,
+		entries: make(map[util.Hash]int),
+		trees:   make(map[util.Hash]int),
+	}
+}
+
+func (r *countingRepo) ListEntries(hash util.Hash) ([]repository.TreeEntry, error) {
+	r.entries[hash]++
+	return r.Repo.ListEntries(hash)
+}
+
+func (r *countingRepo) GetTreeHash(commit util.Hash) (util.Hash, error) {
+	r.trees[commit]++
+	return r.Repo.GetTreeHash(commit)
+}
+
+func TestMergeTreeLookups(t *testing.T) {
+	repoA, repoB, remote := setupRepos(t)
+	defer cleanupRepos(repoA, repoB, remote)
+
+	b, err := operations.Create(rene, "title", "message")
+	checkErr(t, err)
+	checkErr(t, b.Commit(repoA))
+	for _, message := range []string{"first", "second", "third"} {
+		checkErr(t, operations.Comment(b, rene, message))
+		checkErr(t, b.Commit(repoA))
+	}
+
+	_, err = bug.Push(repoA, "origin")
+	checkErr(t, err)
+	checkErr(t, bug.Pull(repoB, os.Stdout, "origin"))
+
+	// edited on both sides, so that the local edits are rebased
+	checkErr(t, operations.Comment(b, rene, "from A"))
+	checkErr(t, b.Commit(repoA))
+	_, err = bug.Push(repoA, "origin")
+	checkErr(t, err)
+
+	bugB, err := bug.ReadLocalBug(repoB, b.Id())
+	checkErr(t, err)
+	for _, message := range []string{"from B", "again from B"} {
+		checkErr(t, operations.Comment(bugB, isaac, message))
+		checkErr(t, bugB.Commit(repoB))
+	}
+
+	_, err = bug.Fetch(repoB, "origin")
+	checkErr(t, err)
+
+	counting := newCountingRepo(repoB)
+
+	updated := false
+	for result := range bug.MergeAll(counting, "origin") {
+		checkErr(t, result.Err)
+		updated = updated || result.Status == bug.MsgMergeUpdated
+	}
+	if !updated {
+		t.Fatal("The bug should be merged")
+	}
+
+	// the history shared by both sides is only read once
+	if len(counting.entries) < 5 || len(counting.trees) != 2 {
+		t.Fatalf("Unexpected lookups %v %v", counting.entries, counting.trees)
+	}
+	for hash, count := range counting.entries {
+		if count > 1 {
+			t.Fatalf("The entries of %s were listed %d times", hash, count)
+		}
+	}
+	for hash, count := range counting.trees {
+		if count > 1 {
+			t.Fatalf("The tree of %s was read %d times", hash, count)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package tests
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/bug/operations"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func TestVerifyAll(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+
+	valid, err := operations.Create(rene, "valid", "message")
+	checkErr(t, err)
+	checkErr(t, valid.Commit(repo))
+	checkErr(t, operations.Comment(valid, isaac, "comment"))
+	checkErr(t, valid.Commit(repo))
+
+	// a bug without create operation
+	noCreate := bug.NewBug()
+	noCreate.Append(operations.NewSetTitleOp(rene, "title", ""))
+	checkErr(t, noCreate.Commit(repo))
+
+	// a bug whose last commit hold a garbage OperationPack
+	garbage, err := operations.Create(rene, "garbage", "message")
+	checkErr(t, err)
+	checkErr(t, garbage.Commit(repo))
+
+	blob, err := repo.StoreData([]byte("not an operation pack"))
+	checkErr(t, err)
+	tree, err := repo.StoreTree([]repository.TreeEntry{
+		{ObjectType: repository.Blob, Hash: blob, Name: "ops"},
+		{ObjectType: repository.Blob, Hash: blob, Name: "root"},
+	})
+	checkErr(t, err)
+	commit, err := repo.StoreCommitWithParent(tree, garbage.LastCommitHash())
+	checkErr(t, err)
+	checkErr(t, repo.UpdateRef("refs/bugs/"+garbage.Id(), commit))
+
+	other, err := operations.Create(isaac, "other valid", "message")
+	checkErr(t, err)
+	checkErr(t, other.Commit(repo))
+
+	report, err := bug.VerifyAll(repo)
+	checkErr(t, err)
+
+	if len(report.Bugs) != 4 || report.Valid != 2 || report.Invalid != 2 || report.Incomplete != 0 {
+		t.Fatalf("unexpected report: %s", report.Summary())
+	}
+
+	invalid := report.InvalidIds()
+	sort.Strings(invalid)
+	expected := []string{noCreate.Id(), garbage.Id()}
+	sort.Strings(expected)
+	if !reflect.DeepEqual(invalid, expected) {
+		t.Fatalf("expected the invalid bugs %v, got %v", expected, invalid)
+	}
+
+	for _, v := range report.Bugs {
+		if v.IsValid() == (len(v.Problems) > 0) {
+			t.Fatalf("%s: the validity doesn't match the problems %v", v.Id, v.Problems)
+		}
+	}
+}
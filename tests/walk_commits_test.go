@@ -0,0 +1,129 @@
+package tests
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/bug/operations"
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util"
+)
+
+func walked(t *testing.T, repo repository.Repo, ref string) []util.Hash {
+	var hashes []util.Hash
+	err := repo.WalkCommits(ref, func(hash util.Hash) error {
+		hashes = append(hashes, hash)
+		return nil
+	})
+	checkErr(t, err)
+	return hashes
+}
+
+func checkWalkCommits(t *testing.T, repo repository.Repo, ref string) {
+	listed, err := repo.ListCommits(ref)
+	checkErr(t, err)
+
+	hashes := walked(t, repo, ref)
+
+	if len(hashes) != len(listed) {
+		t.Fatalf("Expected %d commits, got %d", len(listed), len(hashes))
+	}
+	for i := range listed {
+		if hashes[i] != listed[i] {
+			t.Fatalf("Commit %d: expected %s, got %s", i, listed[i], hashes[i])
+		}
+	}
+
+	// the walk stop at the first error
+	errStop := errors.New("stop")
+	calls := 0
+	err = repo.WalkCommits(ref, func(hash util.Hash) error {
+		calls++
+		if calls == 2 {
+			return errStop
+		}
+		return nil
+	})
+	if err != errStop {
+		t.Fatalf("The error of the callback should be returned, got %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("The walk should stop after 2 commits, got %d", calls)
+	}
+}
+
+func commitComments(t *testing.T, repo repository.Repo, b *bug.Bug, n int) {
+	for i := 0; i < n; i++ {
+		checkErr(t, operations.Comment(b, rene, "comment"))
+		checkErr(t, b.Commit(repo))
+	}
+}
+
+func TestWalkCommits(t *testing.T) {
+	repo := createRepo(false)
+	defer cleanupRepo(repo)
+
+	b, err := operations.Create(rene, "title", "message")
+	checkErr(t, err)
+	checkErr(t, b.Commit(repo))
+	commitComments(t, repo, b, 3)
+
+	checkWalkCommits(t, repo, "refs/bugs/"+b.Id())
+
+	err = repo.WalkCommits("refs/bugs/missing", func(hash util.Hash) error {
+		t.Fatal("No commit should be walked")
+		return nil
+	})
+	if err == nil {
+		t.Fatal("Walking a missing ref should fail")
+	}
+}
+
+func TestWalkCommitsMergeHistory(t *testing.T) {
+	repoA, repoB := setupMergeRepos(t)
+	defer cleanupRepo(repoA)
+	defer cleanupRepo(repoB)
+
+	b1, err := operations.Create(rene, "title", "message")
+	checkErr(t, err)
+	checkErr(t, b1.Commit(repoA))
+
+	checkErr(t, bug.Pull(repoB, os.Stdout, "a"))
+
+	b2, err := bug.ReadLocalBug(repoB, b1.Id())
+	checkErr(t, err)
+
+	commitComments(t, repoA, b1, 2)
+	commitComments(t, repoB, b2, 2)
+
+	checkErr(t, bug.Pull(repoA, os.Stdout, "b"))
+
+	ref := "refs/bugs/" + b1.Id()
+
+	checkWalkCommits(t, repoA, ref)
+
+	// a commit is always walked after its parents
+	seen := make(map[util.Hash]bool)
+	for _, hash := range walked(t, repoA, ref) {
+		for _, parent := range strings.Fields(git(t, repoA, "rev-parse", string(hash)+"^@")) {
+			if !seen[util.Hash(parent)] {
+				t.Fatalf("Commit %s walked before its parent %s", hash, parent)
+			}
+		}
+		seen[hash] = true
+	}
+}
+
+func TestWalkCommitsMock(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+
+	b, err := operations.Create(rene, "title", "message")
+	checkErr(t, err)
+	checkErr(t, b.Commit(repo))
+	commitComments(t, repo, b, 3)
+
+	checkWalkCommits(t, repo, "refs/bugs/"+b.Id())
+}
@@ -0,0 +1,100 @@
+package tests
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func samePath(t *testing.T, actual, expected string) {
+	t.Helper()
+
+	actual, err := filepath.EvalSymlinks(actual)
+	checkErr(t, err)
+	expected, err = filepath.EvalSymlinks(expected)
+	checkErr(t, err)
+
+	if actual != expected {
+		t.Fatalf("Expected %s, got %s", expected, actual)
+	}
+}
+
+func TestWorktree(t *testing.T) {
+	main := createRepo(false)
+	defer cleanupRepo(main)
+
+	dir, err := ioutil.TempDir("", "")
+	checkErr(t, err)
+	defer os.RemoveAll(dir)
+	linkedPath := filepath.Join(dir, "linked")
+
+	git(t, main, "commit", "--allow-empty", "-m", "first")
+	git(t, main, "worktree", "add", linkedPath)
+
+	// opened from a sub-directory of the linked worktree
+	checkErr(t, os.Mkdir(filepath.Join(linkedPath, "sub"), 0755))
+	linked, err := repository.NewGitRepo(filepath.Join(linkedPath, "sub"), bug.Witnesser)
+	checkErr(t, err)
+
+	samePath(t, linked.GetPath(), linkedPath)
+	samePath(t, linked.GetCommonDir(), filepath.Join(main.GetPath(), ".git"))
+	samePath(t, linked.GetGitDir(), filepath.Join(main.GetPath(), ".git", "worktrees", "linked"))
+
+	// the bugs created from a worktree are visible from the other
+	backend := cache.NewRepoCache(linked)
+	backend.SetAuthor(rene)
+	b, err := backend.NewBug("from the worktree", "message")
+	checkErr(t, err)
+	checkErr(t, b.AddComment("a comment"))
+	checkErr(t, b.Commit())
+
+	reopened, err := repository.NewGitRepo(main.GetPath(), bug.Witnesser)
+	checkErr(t, err)
+	samePath(t, reopened.GetGitDir(), reopened.GetCommonDir())
+
+	read, err := bug.ReadLocalBug(reopened, b.Snapshot().Id())
+	checkErr(t, err)
+	if snap := read.Compile(); snap.Title != "from the worktree" || len(snap.Comments) != 2 {
+		t.Fatalf("Unexpected bug %+v", snap)
+	}
+
+	// the clocks are shared with the main worktree
+	if _, err := os.Stat(filepath.Join(main.GetPath(), ".git", "git-bug", "edit-clock")); err != nil {
+		t.Fatalf("The clocks should be in the common directory: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(linked.GetGitDir(), "git-bug")); !os.IsNotExist(err) {
+		t.Fatal("No state should be written in the directory of the worktree")
+	}
+}
+
+func TestHooksDir(t *testing.T) {
+	repo := createRepo(false)
+	defer cleanupRepo(repo)
+
+	dir, err := repository.HooksDir(repo)
+	checkErr(t, err)
+	samePath(t, filepath.Dir(dir), filepath.Join(repo.GetPath(), ".git"))
+	if filepath.Base(dir) != "hooks" {
+		t.Fatalf("Unexpected hooks directory %s", dir)
+	}
+
+	// relative to the root of the working tree
+	checkErr(t, repo.SetConfig("core.hooksPath", "ci/hooks"))
+	dir, err = repository.HooksDir(repo)
+	checkErr(t, err)
+	if dir != filepath.Join(repo.GetPath(), "ci", "hooks") {
+		t.Fatalf("Unexpected hooks directory %s", dir)
+	}
+
+	checkErr(t, repo.SetConfig("core.hooksPath", "/srv/hooks"))
+	dir, err = repository.HooksDir(repo)
+	checkErr(t, err)
+	if dir != "/srv/hooks" {
+		t.Fatalf("Unexpected hooks directory %s", dir)
+	}
+}
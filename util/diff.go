@@ -0,0 +1,204 @@
+package util
+
+import (
+	"bytes"
+	"unicode"
+)
+
+type DiffType int
+
+const (
+	DiffEqual DiffType = iota
+	DiffInsert
+	DiffDelete
+)
+
+// DiffChunk is a piece of text that is either common to both side of a diff,
+// inserted or deleted.
+type DiffChunk struct {
+	Type DiffType
+	Text string
+}
+
+// WordDiff compute a word level diff between two texts, using the Myers
+// algorithm. Whitespace is preserved so that the original texts can be
+// rebuilt from the chunks.
+func WordDiff(a, b string) []DiffChunk {
+	wordsA := splitWords(a)
+	wordsB := splitWords(b)
+
+	var chunks []DiffChunk
+
+	push := func(t DiffType, word string) {
+		if len(chunks) > 0 && chunks[len(chunks)-1].Type == t {
+			chunks[len(chunks)-1].Text += word
+			return
+		}
+		chunks = append(chunks, DiffChunk{Type: t, Text: word})
+	}
+
+	for _, e := range myersDiff(wordsA, wordsB) {
+		switch e.t {
+		case DiffEqual:
+			push(DiffEqual, wordsA[e.i])
+		case DiffDelete:
+			push(DiffDelete, wordsA[e.i])
+		case DiffInsert:
+			push(DiffInsert, wordsB[e.j])
+		}
+	}
+
+	return chunks
+}
+
+// FormatWordDiff render a diff with colors for a terminal, additions being
+// highlighted in green and deletions in red.
+func FormatWordDiff(chunks []DiffChunk) string {
+	var buffer bytes.Buffer
+
+	for _, chunk := range chunks {
+		switch chunk.Type {
+		case DiffEqual:
+			buffer.WriteString(chunk.Text)
+		case DiffInsert:
+			buffer.WriteString(GreenBg(chunk.Text))
+		case DiffDelete:
+			buffer.WriteString(RedBg(chunk.Text))
+		}
+	}
+
+	return buffer.String()
+}
+
+// splitWords split a text into words and runs of whitespace
+func splitWords(text string) []string {
+	var words []string
+	start := 0
+	inSpace := false
+
+	for i, r := range text {
+		space := unicode.IsSpace(r)
+		if i > start && space != inSpace {
+			words = append(words, text[start:i])
+			start = i
+		}
+		inSpace = space
+	}
+
+	if start < len(text) {
+		words = append(words, text[start:])
+	}
+
+	return words
+}
+
+type diffEdit struct {
+	t DiffType
+	// index in a for DiffEqual and DiffDelete, in b for DiffInsert
+	i, j int
+}
+
+// maxDiffEdits bound the cost of a diff: past this number of edits, the
+// texts are considered as fully replaced
+const maxDiffEdits = 1000
+
+// myersDiff compute the shortest edit script to transform a into b. The
+// trace only keep the 2d+1 diagonals reached at each step d, so that the
+// memory used is O(D²), D being bounded by maxDiffEdits.
+func myersDiff(a, b []string) []diffEdit {
+	n, m := len(a), len(b)
+	max := n + m
+	offset := max + 1
+
+	v := make([]int, 2*max+3)
+	var trace [][]int
+
+	found := false
+	for d := 0; d <= max && !found; d++ {
+		if d > maxDiffEdits {
+			return replaceDiff(n, m)
+		}
+
+		trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+
+			v[offset+k] = x
+
+			if x >= n && y >= m {
+				found = true
+				break
+			}
+		}
+	}
+
+	// backtrack to build the edit script, in reverse
+	var edits []diffEdit
+	x, y := n, m
+
+	for d := len(trace) - 1; d >= 0; d-- {
+		// the diagonals -d to d, as they were before the step d
+		v := trace[d]
+		k := x - y
+
+		var prevK int
+		if k == -d || (k != d && v[d+k-1] < v[d+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+
+		prevX := 0
+		if d > 0 {
+			prevX = v[d+prevK]
+		}
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			x--
+			y--
+			edits = append(edits, diffEdit{t: DiffEqual, i: x, j: y})
+		}
+
+		if d > 0 {
+			if x == prevX {
+				edits = append(edits, diffEdit{t: DiffInsert, i: x, j: prevY})
+			} else {
+				edits = append(edits, diffEdit{t: DiffDelete, i: prevX, j: y})
+			}
+		}
+
+		x, y = prevX, prevY
+	}
+
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+
+	return edits
+}
+
+// replaceDiff is the edit script deleting the whole of a and inserting the
+// whole of b
+func replaceDiff(n, m int) []diffEdit {
+	edits := make([]diffEdit, 0, n+m)
+	for i := 0; i < n; i++ {
+		edits = append(edits, diffEdit{t: DiffDelete, i: i})
+	}
+	for j := 0; j < m; j++ {
+		edits = append(edits, diffEdit{t: DiffInsert, j: j})
+	}
+	return edits
+}
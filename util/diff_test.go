@@ -0,0 +1,112 @@
+package util
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWordDiff(t *testing.T) {
+	cases := []struct {
+		A, B   string
+		Chunks []DiffChunk
+	}{
+		// Identical texts
+		{
+			"foo bar",
+			"foo bar",
+			[]DiffChunk{{DiffEqual, "foo bar"}},
+		},
+		// Empty texts
+		{
+			"",
+			"",
+			nil,
+		},
+		// Insertion from nothing
+		{
+			"",
+			"foo",
+			[]DiffChunk{{DiffInsert, "foo"}},
+		},
+		// Deletion of everything
+		{
+			"foo",
+			"",
+			[]DiffChunk{{DiffDelete, "foo"}},
+		},
+		// A word is replaced
+		{
+			"foo bar baz",
+			"foo qux baz",
+			[]DiffChunk{{DiffEqual, "foo "}, {DiffDelete, "bar"}, {DiffInsert, "qux"}, {DiffEqual, " baz"}},
+		},
+		// Words are added at the end
+		{
+			"foo bar",
+			"foo bar baz qux",
+			[]DiffChunk{{DiffEqual, "foo bar"}, {DiffInsert, " baz qux"}},
+		},
+		// A word is removed in the middle
+		{
+			"foo bar baz",
+			"foo baz",
+			[]DiffChunk{{DiffEqual, "foo "}, {DiffDelete, "bar "}, {DiffEqual, "baz"}},
+		},
+	}
+
+	for i, tc := range cases {
+		chunks := WordDiff(tc.A, tc.B)
+		if !reflect.DeepEqual(chunks, tc.Chunks) {
+			t.Fatalf("Case %d:\n\nExpected:\n\n%v\n\nActual:\n\n%v", i, tc.Chunks, chunks)
+		}
+
+		// both texts can be rebuilt from the chunks
+		var a, b string
+		for _, chunk := range chunks {
+			if chunk.Type != DiffInsert {
+				a += chunk.Text
+			}
+			if chunk.Type != DiffDelete {
+				b += chunk.Text
+			}
+		}
+		if a != tc.A || b != tc.B {
+			t.Fatalf("Case %d: texts can't be rebuilt: `%s` `%s`", i, a, b)
+		}
+	}
+}
+
+func TestWordDiffLarge(t *testing.T) {
+	var wordsA, wordsB []string
+	for i := 0; i < 2000; i++ {
+		wordsA = append(wordsA, fmt.Sprintf("a%d", i))
+		wordsB = append(wordsB, fmt.Sprintf("b%d", i))
+	}
+	a := strings.Join(wordsA, " ")
+	b := strings.Join(wordsB, " ")
+
+	// too many edits: the whole text is replaced
+	chunks := WordDiff(a, b)
+	expected := []DiffChunk{{DiffDelete, a}, {DiffInsert, b}}
+	if !reflect.DeepEqual(chunks, expected) {
+		t.Fatalf("Unexpected chunks %d", len(chunks))
+	}
+
+	// a few edits in a large text are still found
+	wordsB = append([]string(nil), wordsA...)
+	wordsB[1000] = "changed"
+	b = strings.Join(wordsB, " ")
+
+	chunks = WordDiff(a, b)
+	expected = []DiffChunk{
+		{DiffEqual, strings.Join(wordsA[:1000], " ") + " "},
+		{DiffDelete, "a1000"},
+		{DiffInsert, "changed"},
+		{DiffEqual, " " + strings.Join(wordsA[1001:], " ")},
+	}
+	if !reflect.DeepEqual(chunks, expected) {
+		t.Fatalf("Unexpected chunks %d", len(chunks))
+	}
+}
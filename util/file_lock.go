@@ -0,0 +1,41 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// FileLock is an exclusive lock held on a file by the process, to
+// coordinate with the other processes using the same repository
+type FileLock struct {
+	file *os.File
+}
+
+// LockFile wait for and take an exclusive lock on the given file, created
+// if needed. The lock is released by Unlock, or when the process exit.
+func LockFile(path string) (*FileLock, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := lockFile(file); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return &FileLock{file: file}, nil
+}
+
+// Unlock release the lock
+func (l *FileLock) Unlock() error {
+	err := unlockFile(l.file)
+	if closeErr := l.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,164 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// InterruptGracePeriod bound how long an interruption wait for the critical
+// sections in progress, before terminating the process anyway
+const InterruptGracePeriod = 5 * time.Second
+
+// ErrInterrupted is returned by Critical when the process is terminating
+var ErrInterrupted = errors.New("interrupted")
+
+// interruptHandler defer the termination of the process on SIGINT or
+// SIGTERM until the critical sections in progress are done
+type interruptHandler struct {
+	once  sync.Once
+	grace time.Duration
+	exit  func(code int)
+
+	mu          sync.Mutex
+	running     int
+	idle        chan struct{}
+	interrupted bool
+	cleanups    []func()
+}
+
+var interrupts = newInterruptHandler(InterruptGracePeriod, os.Exit)
+
+func newInterruptHandler(grace time.Duration, exit func(code int)) *interruptHandler {
+	idle := make(chan struct{})
+	close(idle)
+
+	return &interruptHandler{
+		grace: grace,
+		exit:  exit,
+		idle:  idle,
+	}
+}
+
+// Critical run f without being interrupted: a SIGINT or SIGTERM received
+// meanwhile only terminate the process once f returned, or after the
+// InterruptGracePeriod. Once the process is terminating, f is not run and
+// ErrInterrupted is returned.
+func Critical(f func() error) error {
+	interrupts.install()
+	return interrupts.critical(f)
+}
+
+// ProtectCommand make a command started during a critical section run in its
+// own process group, as a Ctrl-C on the terminal signal the whole foreground
+// group: a git command writing the changes would be killed before the
+// critical section is done otherwise. It must be called before starting the
+// command. Outside of a critical section, the command is interrupted along
+// with the process.
+func ProtectCommand(cmd *exec.Cmd) {
+	interrupts.protectCommand(cmd)
+}
+
+// OnInterrupt register a function to run before the process is terminated
+// by a SIGINT or SIGTERM, like to remove a temporary file
+func OnInterrupt(cleanup func()) {
+	interrupts.install()
+
+	interrupts.mu.Lock()
+	defer interrupts.mu.Unlock()
+
+	interrupts.cleanups = append(interrupts.cleanups, cleanup)
+}
+
+// install start handling the signals, on the first use only so that the
+// default behavior is kept when nothing need to be protected
+func (h *interruptHandler) install() {
+	h.once.Do(func() {
+		signals := make(chan os.Signal, 2)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+		go func() {
+			sig := <-signals
+			go h.interrupt(exitCode(sig))
+
+			// a second signal terminate right away
+			sig = <-signals
+			h.exit(exitCode(sig))
+		}()
+	})
+}
+
+func (h *interruptHandler) critical(f func() error) error {
+	h.mu.Lock()
+	if h.interrupted {
+		h.mu.Unlock()
+		return ErrInterrupted
+	}
+	if h.running == 0 {
+		h.idle = make(chan struct{})
+	}
+	h.running++
+	h.mu.Unlock()
+
+	defer func() {
+		h.mu.Lock()
+		h.running--
+		if h.running == 0 {
+			close(h.idle)
+		}
+		h.mu.Unlock()
+	}()
+
+	return f()
+}
+
+func (h *interruptHandler) protectCommand(cmd *exec.Cmd) {
+	h.mu.Lock()
+	running := h.running
+	h.mu.Unlock()
+
+	if running > 0 {
+		setProcessGroup(cmd)
+	}
+}
+
+// interrupt wait for the critical sections in progress, within the grace
+// period, then run the cleanups and terminate the process
+func (h *interruptHandler) interrupt(code int) {
+	h.mu.Lock()
+	h.interrupted = true
+	idle := h.idle
+	running := h.running
+	cleanups := h.cleanups
+	h.mu.Unlock()
+
+	if running > 0 {
+		fmt.Fprintln(os.Stderr, "Interrupted, waiting for the changes in progress to be saved...")
+
+		select {
+		case <-idle:
+		case <-time.After(h.grace):
+			fmt.Fprintln(os.Stderr, "The changes in progress couldn't be saved in time")
+		}
+	}
+
+	for _, cleanup := range cleanups {
+		cleanup()
+	}
+
+	h.exit(code)
+}
+
+// exitCode return the exit code of a process terminated by a signal, as the
+// shells do
+func exitCode(sig os.Signal) int {
+	if s, ok := sig.(syscall.Signal); ok {
+		return 128 + int(s)
+	}
+	return 1
+}
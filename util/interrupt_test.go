@@ -0,0 +1,100 @@
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInterruptWaitCritical(t *testing.T) {
+	exited := make(chan int, 1)
+	h := newInterruptHandler(time.Minute, func(code int) { exited <- code })
+
+	cleaned := false
+	h.cleanups = append(h.cleanups, func() { cleaned = true })
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan error)
+
+	go func() {
+		done <- h.critical(func() error {
+			close(started)
+			<-release
+			return nil
+		})
+	}()
+
+	<-started
+	go h.interrupt(130)
+
+	select {
+	case <-exited:
+		t.Fatal("The process should not exit during a critical section")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+
+	if code := <-exited; code != 130 {
+		t.Fatalf("Unexpected exit code %d", code)
+	}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if !cleaned {
+		t.Fatal("The cleanups should run before exiting")
+	}
+
+	// no new critical section once interrupted
+	err := h.critical(func() error {
+		t.Fatal("The critical section should not run")
+		return nil
+	})
+	if err != ErrInterrupted {
+		t.Fatalf("Unexpected error %v", err)
+	}
+}
+
+func TestInterruptGracePeriod(t *testing.T) {
+	exited := make(chan int, 1)
+	h := newInterruptHandler(10*time.Millisecond, func(code int) { exited <- code })
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+
+	go h.critical(func() error {
+		close(started)
+		<-release
+		return nil
+	})
+
+	<-started
+	go h.interrupt(143)
+
+	select {
+	case code := <-exited:
+		if code != 143 {
+			t.Fatalf("Unexpected exit code %d", code)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("The process should exit after the grace period")
+	}
+}
+
+func TestInterruptIdle(t *testing.T) {
+	exited := make(chan int, 1)
+	h := newInterruptHandler(time.Minute, func(code int) { exited <- code })
+
+	if err := h.critical(func() error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+
+	h.interrupt(130)
+
+	select {
+	case <-exited:
+	default:
+		t.Fatal("The process should exit right away without critical section")
+	}
+}
@@ -0,0 +1,64 @@
+//go:build !windows
+// +build !windows
+
+package util
+
+import (
+	"os/exec"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestProtectCommand(t *testing.T) {
+	h := newInterruptHandler(time.Minute, func(code int) {})
+
+	// the foreground process group of a terminal, receiving the Ctrl-C
+	foreground := exec.Command("sleep", "30")
+	foreground.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if err := foreground.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer foreground.Process.Kill()
+	group := foreground.Process.Pid
+
+	start := func() *exec.Cmd {
+		cmd := exec.Command("sleep", "30")
+		cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true, Pgid: group}
+		h.protectCommand(cmd)
+		if err := cmd.Start(); err != nil {
+			t.Fatal(err)
+		}
+		return cmd
+	}
+
+	unprotected := start()
+	defer unprotected.Process.Kill()
+
+	var protected *exec.Cmd
+	err := h.critical(func() error {
+		protected = start()
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer protected.Process.Kill()
+
+	if err := syscall.Kill(-group, syscall.SIGINT); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := unprotected.Wait(); err == nil {
+		t.Fatal("The command started outside of a critical section should be interrupted")
+	}
+
+	exited := make(chan error, 1)
+	go func() { exited <- protected.Wait() }()
+
+	select {
+	case err := <-exited:
+		t.Fatalf("The command started in a critical section should still run, got %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -0,0 +1,21 @@
+//go:build windows
+// +build windows
+
+package util
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// createNewProcessGroup is the CREATE_NEW_PROCESS_GROUP creation flag
+const createNewProcessGroup = 0x00000200
+
+// setProcessGroup start the command in a new process group, which doesn't
+// receive the Ctrl-C of the console
+func setProcessGroup(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.CreationFlags |= createNewProcessGroup
+}
@@ -28,9 +28,14 @@
 package util
 
 import (
+	"errors"
+	"math"
 	"sync/atomic"
 )
 
+// ErrClockOverflow is returned when a lamport clock would wrap around
+var ErrClockOverflow = errors.New("lamport clock overflow")
+
 // LamportClock is a thread safe implementation of a lamport clock. It
 // uses efficient atomic operations for all of its functions, falling back
 // to a heavy lock only if there are enough CAS failures.
@@ -59,19 +64,34 @@ func (l *LamportClock) Time() LamportTime {
 }
 
 // Increment is used to return the value of the lamport clock and increment it afterwards
-func (l *LamportClock) Increment() LamportTime {
-	return LamportTime(atomic.AddUint64(&l.counter, 1) - 1)
+func (l *LamportClock) Increment() (LamportTime, error) {
+	for {
+		cur := atomic.LoadUint64(&l.counter)
+		if cur == math.MaxUint64 {
+			return 0, ErrClockOverflow
+		}
+
+		if atomic.CompareAndSwapUint64(&l.counter, cur, cur+1) {
+			return LamportTime(cur), nil
+		}
+	}
 }
 
 // Witness is called to update our local clock if necessary after
 // witnessing a clock value received from another process
-func (l *LamportClock) Witness(v LamportTime) {
+func (l *LamportClock) Witness(v LamportTime) error {
+	other := uint64(v)
+
+	// The local clock couldn't be ahead of this value
+	if other == math.MaxUint64 {
+		return ErrClockOverflow
+	}
+
 WITNESS:
 	// If the other value is old, we do not need to do anything
 	cur := atomic.LoadUint64(&l.counter)
-	other := uint64(v)
 	if other < cur {
-		return
+		return nil
 	}
 
 	// Ensure that our local clock is at least one ahead.
@@ -82,4 +102,6 @@ WITNESS:
 		// will end.
 		goto WITNESS
 	}
+
+	return nil
 }
@@ -28,6 +28,7 @@
 package util
 
 import (
+	"math"
 	"testing"
 )
 
@@ -38,7 +39,7 @@ func TestLamportClock(t *testing.T) {
 		t.Fatalf("bad time value")
 	}
 
-	if l.Increment() != 0 {
+	if time, err := l.Increment(); err != nil || time != 0 {
 		t.Fatalf("bad time value")
 	}
 
@@ -64,3 +65,29 @@ func TestLamportClock(t *testing.T) {
 		t.Fatalf("bad time value")
 	}
 }
+
+func TestLamportClockOverflow(t *testing.T) {
+	l := NewLamportClockWithTime(math.MaxUint64 - 1)
+
+	if _, err := l.Increment(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := l.Increment(); err != ErrClockOverflow {
+		t.Fatalf("the clock should not wrap")
+	}
+
+	if l.Time() != math.MaxUint64 {
+		t.Fatalf("bad time value")
+	}
+
+	l = NewLamportClock()
+
+	if err := l.Witness(math.MaxUint64); err != ErrClockOverflow {
+		t.Fatalf("the clock should not wrap")
+	}
+
+	if l.Time() != 1 {
+		t.Fatalf("bad time value")
+	}
+}
@@ -0,0 +1,332 @@
+package util
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/russross/blackfriday"
+)
+
+const markdownExtensions = blackfriday.EXTENSION_NO_INTRA_EMPHASIS |
+	blackfriday.EXTENSION_TABLES |
+	blackfriday.EXTENSION_FENCED_CODE |
+	blackfriday.EXTENSION_AUTOLINK |
+	blackfriday.EXTENSION_STRIKETHROUGH |
+	blackfriday.EXTENSION_SPACE_HEADERS |
+	blackfriday.EXTENSION_HARD_LINE_BREAK
+
+// the user content is never trusted: no raw HTML, no style and only links
+// to safe protocols
+const markdownHTMLFlags = blackfriday.HTML_SKIP_HTML |
+	blackfriday.HTML_SKIP_STYLE |
+	blackfriday.HTML_SAFELINK |
+	blackfriday.HTML_NOFOLLOW_LINKS |
+	blackfriday.HTML_NOREFERRER_LINKS |
+	blackfriday.HTML_HREF_TARGET_BLANK
+
+// mark the lines of code blocks, so that they are not wrapped
+const verbatimMarker = "\x00"
+
+// MarkdownStyle define how the markdown elements are rendered in a terminal
+type MarkdownStyle struct {
+	Header    func(a ...interface{}) string
+	Emphasis  func(a ...interface{}) string
+	Strong    func(a ...interface{}) string
+	Strike    func(a ...interface{}) string
+	Code      func(a ...interface{}) string
+	CodeBlock func(a ...interface{}) string
+	Link      func(a ...interface{}) string
+}
+
+// AnsiMarkdownStyle use the common ANSI attributes of the terminals
+var AnsiMarkdownStyle = MarkdownStyle{
+	Header:    color.New(color.Bold, color.Underline).SprintFunc(),
+	Emphasis:  color.New(color.Italic).SprintFunc(),
+	Strong:    color.New(color.Bold).SprintFunc(),
+	Strike:    color.New(color.CrossedOut).SprintFunc(),
+	Code:      color.New(color.FgCyan).SprintFunc(),
+	CodeBlock: color.New(color.BgHiBlack).SprintFunc(),
+	Link:      color.New(color.FgBlue, color.Underline).SprintFunc(),
+}
+
+// BasicMarkdownStyle only use the 8 colors, bold and underline, as
+// supported by gocui
+var BasicMarkdownStyle = MarkdownStyle{
+	Header:    color.New(color.Bold, color.Underline).SprintFunc(),
+	Emphasis:  color.New(color.Underline).SprintFunc(),
+	Strong:    color.New(color.Bold).SprintFunc(),
+	Strike:    fmt.Sprint,
+	Code:      color.New(color.FgCyan).SprintFunc(),
+	CodeBlock: color.New(color.FgCyan).SprintFunc(),
+	Link:      color.New(color.FgBlue, color.Underline).SprintFunc(),
+}
+
+// RenderMarkdown render a markdown text for a terminal, wrapped in lines
+// no wider than width, except for the code blocks that are kept verbatim.
+// A width of 0 disable the wrapping. On a malformed input, the raw text is
+// returned.
+func RenderMarkdown(text string, width int, style MarkdownStyle) (result string) {
+	defer func() {
+		if r := recover(); r != nil {
+			result = text
+		}
+	}()
+
+	renderer := &terminalRenderer{style: style}
+	output := blackfriday.Markdown([]byte(text), renderer, markdownExtensions)
+
+	var lines []string
+
+	for _, line := range strings.Split(strings.TrimRight(string(output), "\n"), "\n") {
+		if strings.Contains(line, verbatimMarker) {
+			lines = append(lines, strings.Replace(line, verbatimMarker, "", -1))
+			continue
+		}
+
+		lines = append(lines, WrapText(line, width)...)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// MarkdownToHTML render a markdown text as HTML, sanitized to be safely
+// included in a web page. On a malformed input, the escaped raw text is
+// returned.
+func MarkdownToHTML(text string) (result string) {
+	defer func() {
+		if r := recover(); r != nil {
+			result = "<pre>" + html.EscapeString(text) + "</pre>"
+		}
+	}()
+
+	renderer := safeImageRenderer{blackfriday.HtmlRenderer(markdownHTMLFlags, "", "")}
+	return string(blackfriday.Markdown([]byte(text), renderer, markdownExtensions))
+}
+
+// safeImageRenderer only keep the images from safe sources, as blackfriday
+// only check the links
+type safeImageRenderer struct {
+	blackfriday.Renderer
+}
+
+func (r safeImageRenderer) Image(out *bytes.Buffer, link []byte, title []byte, alt []byte) {
+	lower := strings.ToLower(string(link))
+
+	if strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://") {
+		r.Renderer.Image(out, link, title, alt)
+		return
+	}
+
+	out.WriteString(html.EscapeString(string(alt)))
+}
+
+// terminalRenderer is a blackfriday renderer producing text styled with
+// ANSI escape codes
+type terminalRenderer struct {
+	style MarkdownStyle
+	// the number of the next item of each nested ordered list
+	listCounters []int
+}
+
+var _ blackfriday.Renderer = &terminalRenderer{}
+
+// capture run a callback writing in out and return what it wrote, removed
+// from the buffer
+func capture(out *bytes.Buffer, text func() bool) (string, bool) {
+	marker := out.Len()
+	ok := text()
+	content := string(out.Bytes()[marker:])
+	out.Truncate(marker)
+	return content, ok
+}
+
+// indentLines prefix each line of a text except the first one
+func indentLines(text string, prefix string) string {
+	return strings.Replace(text, "\n", "\n"+prefix, -1)
+}
+
+func (r *terminalRenderer) BlockCode(out *bytes.Buffer, text []byte, lang string) {
+	lines := strings.Split(strings.TrimRight(string(text), "\n"), "\n")
+
+	width := 0
+	for _, line := range lines {
+		width = maxInt(width, wordLen(line))
+	}
+
+	for _, line := range lines {
+		padded := line + strings.Repeat(" ", width-wordLen(line))
+		out.WriteString(verbatimMarker + r.style.CodeBlock(" "+padded+" ") + "\n")
+	}
+	out.WriteString("\n")
+}
+
+func (r *terminalRenderer) BlockQuote(out *bytes.Buffer, text []byte) {
+	quote := strings.TrimRight(string(text), "\n")
+	out.WriteString("│ " + indentLines(quote, "│ ") + "\n\n")
+}
+
+func (r *terminalRenderer) BlockHtml(out *bytes.Buffer, text []byte) {
+	out.Write(text)
+	out.WriteString("\n")
+}
+
+func (r *terminalRenderer) Header(out *bytes.Buffer, text func() bool, level int, id string) {
+	content, ok := capture(out, text)
+	if !ok {
+		return
+	}
+
+	out.WriteString(r.style.Header(content) + "\n\n")
+}
+
+func (r *terminalRenderer) HRule(out *bytes.Buffer) {
+	out.WriteString("────────\n\n")
+}
+
+func (r *terminalRenderer) List(out *bytes.Buffer, text func() bool, flags int) {
+	r.listCounters = append(r.listCounters, 1)
+	defer func() {
+		r.listCounters = r.listCounters[:len(r.listCounters)-1]
+	}()
+
+	// a nested list start on a new line of its item
+	if out.Len() > 0 && out.Bytes()[out.Len()-1] != '\n' {
+		out.WriteString("\n")
+	}
+
+	content, ok := capture(out, text)
+	if !ok {
+		return
+	}
+
+	out.WriteString(content)
+
+	if len(r.listCounters) == 1 {
+		out.WriteString("\n")
+	}
+}
+
+func (r *terminalRenderer) ListItem(out *bytes.Buffer, text []byte, flags int) {
+	bullet := "• "
+
+	if flags&blackfriday.LIST_TYPE_ORDERED != 0 {
+		counter := &r.listCounters[len(r.listCounters)-1]
+		bullet = fmt.Sprintf("%d. ", *counter)
+		*counter++
+	}
+
+	item := strings.TrimRight(string(text), "\n")
+	padding := strings.Repeat(" ", wordLen(bullet))
+
+	out.WriteString(bullet + indentLines(item, padding) + "\n")
+}
+
+func (r *terminalRenderer) Paragraph(out *bytes.Buffer, text func() bool) {
+	content, ok := capture(out, text)
+	if !ok {
+		return
+	}
+
+	out.WriteString(content + "\n\n")
+}
+
+func (r *terminalRenderer) Table(out *bytes.Buffer, header []byte, body []byte, columnData []int) {
+	out.WriteString(r.style.Strong(strings.TrimRight(string(header), "\n")) + "\n")
+	out.Write(body)
+	out.WriteString("\n")
+}
+
+func (r *terminalRenderer) TableRow(out *bytes.Buffer, text []byte) {
+	out.WriteString(strings.TrimSuffix(string(text), " | ") + "\n")
+}
+
+func (r *terminalRenderer) TableHeaderCell(out *bytes.Buffer, text []byte, flags int) {
+	r.TableCell(out, text, flags)
+}
+
+func (r *terminalRenderer) TableCell(out *bytes.Buffer, text []byte, flags int) {
+	out.Write(text)
+	out.WriteString(" | ")
+}
+
+func (r *terminalRenderer) Footnotes(out *bytes.Buffer, text func() bool) {
+	text()
+}
+
+func (r *terminalRenderer) FootnoteItem(out *bytes.Buffer, name, text []byte, flags int) {
+	out.WriteString(fmt.Sprintf("[%s] ", name))
+	out.Write(text)
+}
+
+func (r *terminalRenderer) TitleBlock(out *bytes.Buffer, text []byte) {
+	out.Write(text)
+	out.WriteString("\n\n")
+}
+
+func (r *terminalRenderer) AutoLink(out *bytes.Buffer, link []byte, kind int) {
+	out.WriteString(r.style.Link(string(link)))
+}
+
+func (r *terminalRenderer) CodeSpan(out *bytes.Buffer, text []byte) {
+	out.WriteString(r.style.Code(string(text)))
+}
+
+func (r *terminalRenderer) DoubleEmphasis(out *bytes.Buffer, text []byte) {
+	out.WriteString(r.style.Strong(string(text)))
+}
+
+func (r *terminalRenderer) Emphasis(out *bytes.Buffer, text []byte) {
+	out.WriteString(r.style.Emphasis(string(text)))
+}
+
+func (r *terminalRenderer) Image(out *bytes.Buffer, link []byte, title []byte, alt []byte) {
+	out.WriteString(fmt.Sprintf("[%s](%s)", alt, r.style.Link(string(link))))
+}
+
+func (r *terminalRenderer) LineBreak(out *bytes.Buffer) {
+	out.WriteString("\n")
+}
+
+func (r *terminalRenderer) Link(out *bytes.Buffer, link []byte, title []byte, content []byte) {
+	if string(content) == string(link) {
+		out.WriteString(r.style.Link(string(link)))
+		return
+	}
+
+	out.WriteString(fmt.Sprintf("%s (%s)", content, r.style.Link(string(link))))
+}
+
+func (r *terminalRenderer) RawHtmlTag(out *bytes.Buffer, tag []byte) {
+	out.Write(tag)
+}
+
+func (r *terminalRenderer) TripleEmphasis(out *bytes.Buffer, text []byte) {
+	out.WriteString(r.style.Strong(r.style.Emphasis(string(text))))
+}
+
+func (r *terminalRenderer) StrikeThrough(out *bytes.Buffer, text []byte) {
+	out.WriteString(r.style.Strike(string(text)))
+}
+
+func (r *terminalRenderer) FootnoteRef(out *bytes.Buffer, ref []byte, id int) {
+	out.WriteString(fmt.Sprintf("[%s]", ref))
+}
+
+func (r *terminalRenderer) Entity(out *bytes.Buffer, entity []byte) {
+	out.WriteString(html.UnescapeString(string(entity)))
+}
+
+func (r *terminalRenderer) NormalText(out *bytes.Buffer, text []byte) {
+	out.Write(text)
+}
+
+func (r *terminalRenderer) DocumentHeader(out *bytes.Buffer) {}
+
+func (r *terminalRenderer) DocumentFooter(out *bytes.Buffer) {}
+
+func (r *terminalRenderer) GetFlags() int {
+	return 0
+}
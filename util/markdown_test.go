@@ -0,0 +1,77 @@
+package util
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	cases := []struct {
+		input    string
+		width    int
+		expected string
+	}{
+		// headings and emphasis loose their markers
+		{"# Title\n\nsome *emphasis* and **strong**", 0, "Title\n\nsome emphasis and strong"},
+		// paragraphs are wrapped
+		{"a long line to be wrapped", 10, "a long\nline to be\nwrapped"},
+		// lists
+		{"- one\n- two\n  - nested", 0, "• one\n• two\n  • nested"},
+		{"1. one\n2. two", 0, "1. one\n2. two"},
+		// line breaks are kept
+		{"line one\nline two", 0, "line one\nline two"},
+		// links
+		{"[text](http://example.com)", 0, "text (http://example.com)"},
+		// entities
+		{"a &amp; b", 0, "a & b"},
+		// code blocks are kept verbatim and not wrapped
+		{"```\nfoo  bar   baz qux\n```", 5, " foo  bar   baz qux "},
+	}
+
+	for i, tc := range cases {
+		result := RenderMarkdown(tc.input, tc.width, AnsiMarkdownStyle)
+		if result != tc.expected {
+			t.Fatalf("Case %d:\nInput: %q\nExpected: %q\nGot: %q", i, tc.input, tc.expected, result)
+		}
+	}
+}
+
+func TestRenderMarkdownMalformed(t *testing.T) {
+	fragments := []string{"*", "**", "`", "```", "[", "](", ")", "#", "-", "1.", ">", "\n", " ", "|", "~~", "&", "<b>", "a", "é", "\t"}
+
+	r := rand.New(rand.NewSource(42))
+
+	for i := 0; i < 1000; i++ {
+		var b strings.Builder
+		for j := 0; j < r.Intn(40); j++ {
+			b.WriteString(fragments[r.Intn(len(fragments))])
+		}
+
+		// should never panic
+		RenderMarkdown(b.String(), r.Intn(20), AnsiMarkdownStyle)
+		MarkdownToHTML(b.String())
+	}
+}
+
+func TestMarkdownToHTML(t *testing.T) {
+	result := MarkdownToHTML("**strong** <script>alert(1)</script>")
+	if !strings.Contains(result, "<strong>strong</strong>") {
+		t.Fatalf("The markdown should be rendered: %s", result)
+	}
+	if strings.Contains(result, "<script>") {
+		t.Fatalf("Raw HTML should be removed: %s", result)
+	}
+
+	for _, input := range []string{"[x](javascript:alert(1))", "![x](javascript:alert(1))"} {
+		result := MarkdownToHTML(input)
+		if strings.Contains(result, "javascript:") {
+			t.Fatalf("Unsafe links should be removed: %s", result)
+		}
+	}
+
+	result = MarkdownToHTML("![x](https://example.com/a.png)")
+	if !strings.Contains(result, `<img src="https://example.com/a.png"`) {
+		t.Fatalf("Safe images should be kept: %s", result)
+	}
+}
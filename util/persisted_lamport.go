@@ -5,11 +5,24 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 )
 
+// clockFileHeader is the first line of a clock file. A file with another
+// header, or written before the header existed, is invalid and the clock is
+// rebuilt from the bugs.
+const clockFileHeader = "git-bug-clock 1"
+
+// PersistedLamport is a LamportClock stored in a file. Several processes can
+// use the same file: each change happens under a file lock, after catching up
+// with the value written by the others, and the file is replaced atomically.
 type PersistedLamport struct {
 	LamportClock
 	filePath string
+
+	// the bugs can be read concurrently, the file is written by one at a time
+	mu sync.Mutex
 }
 
 func NewPersistedLamport(filePath string) *PersistedLamport {
@@ -25,54 +38,114 @@ func LoadPersistedLamport(filePath string) (*PersistedLamport, error) {
 		filePath: filePath,
 	}
 
-	err := clock.read()
+	value, err := readClockFile(filePath)
 	if err != nil {
 		return nil, err
 	}
 
+	clock.LamportClock = NewLamportClockWithTime(uint64(value))
+
 	return clock, nil
 }
 
 func (c *PersistedLamport) Increment() (LamportTime, error) {
-	time := c.LamportClock.Increment()
-	return time, c.Write()
+	var time LamportTime
+
+	err := c.update(func() error {
+		var err error
+		time, err = c.LamportClock.Increment()
+		return err
+	})
+
+	return time, err
 }
 
 func (c *PersistedLamport) Witness(time LamportTime) error {
-	// TODO: rework so that we write only when the clock was actually updated
-	c.LamportClock.Witness(time)
-	return c.Write()
+	// the clock is only written when moved forward
+	if time < c.Time() {
+		return nil
+	}
+
+	return c.update(func() error {
+		return c.LamportClock.Witness(time)
+	})
+}
+
+// Write store the clock in its file
+func (c *PersistedLamport) Write() error {
+	return c.update(func() error { return nil })
 }
 
-func (c *PersistedLamport) read() error {
-	content, err := ioutil.ReadFile(c.filePath)
+// update apply a change to the clock and write it, holding the file lock
+func (c *PersistedLamport) update(change func() error) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	lock, err := LockFile(c.filePath + ".lock")
 	if err != nil {
 		return err
 	}
+	defer lock.Unlock()
+
+	// catch up with the other processes. An invalid file is simply replaced.
+	if value, err := readClockFile(c.filePath); err == nil && value > c.Time() {
+		// the clock then hold exactly the value of the file
+		if err := c.LamportClock.Witness(value - 1); err != nil {
+			return err
+		}
+	}
 
-	var value uint64
-	n, err := fmt.Sscanf(string(content), "%d", &value)
+	if err := change(); err != nil {
+		return err
+	}
+
+	return c.write()
+}
 
+// write replace the file atomically, so that it's never seen half written
+func (c *PersistedLamport) write() error {
+	dir := filepath.Dir(c.filePath)
+	err := os.MkdirAll(dir, 0777)
 	if err != nil {
 		return err
 	}
 
-	if n != 1 {
-		return fmt.Errorf("could not read the clock")
+	tmp, err := ioutil.TempFile(dir, filepath.Base(c.filePath)+".tmp")
+	if err != nil {
+		return err
 	}
 
-	c.LamportClock = NewLamportClockWithTime(value)
+	_, err = fmt.Fprintf(tmp, "%s\n%d\n", clockFileHeader, c.Time())
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), c.filePath)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
 
 	return nil
 }
 
-func (c *PersistedLamport) Write() error {
-	dir := filepath.Dir(c.filePath)
-	err := os.MkdirAll(dir, 0777)
+func readClockFile(filePath string) (LamportTime, error) {
+	content, err := ioutil.ReadFile(filePath)
 	if err != nil {
-		return err
+		return 0, err
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	if len(lines) != 2 || lines[0] != clockFileHeader {
+		return 0, fmt.Errorf("%s: unsupported clock format", filePath)
+	}
+
+	var value uint64
+	n, err := fmt.Sscanf(lines[1], "%d", &value)
+	if err != nil || n != 1 {
+		return 0, fmt.Errorf("%s: could not read the clock", filePath)
 	}
 
-	data := []byte(fmt.Sprintf("%d", c.counter))
-	return ioutil.WriteFile(c.filePath, data, 0644)
+	return LamportTime(value), nil
 }
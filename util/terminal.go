@@ -0,0 +1,16 @@
+package util
+
+import "os"
+
+// DefaultTerminalWidth is the width used when the output is not a terminal
+const DefaultTerminalWidth = 80
+
+// TerminalWidth return the width in cells of the terminal the file is
+// attached to, or DefaultTerminalWidth if it's not a terminal
+func TerminalWidth(file *os.File) int {
+	width, err := terminalWidth(file)
+	if err != nil || width <= 0 {
+		return DefaultTerminalWidth
+	}
+	return width
+}
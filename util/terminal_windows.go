@@ -0,0 +1,13 @@
+//go:build windows
+// +build windows
+
+package util
+
+import (
+	"errors"
+	"os"
+)
+
+func terminalWidth(file *os.File) (int, error) {
+	return 0, errors.New("terminal size is not supported on windows")
+}
@@ -2,9 +2,112 @@ package util
 
 import (
 	"bytes"
+	"errors"
 	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mattn/go-runewidth"
 )
 
+// ErrInvalidUTF8 is returned when a text is not valid UTF-8
+var ErrInvalidUTF8 = errors.New("text is not valid UTF-8")
+
+// CleanupText normalize a text entered by a user, to avoid differences
+// depending on the editor or the platform used:
+// - the text must be valid UTF-8
+// - a leading BOM is removed
+// - CRLF line endings are converted to LF
+// - trailing whitespace on each line is removed, as well as trailing blank lines
+func CleanupText(text string) (string, error) {
+	if !utf8.ValidString(text) {
+		return "", ErrInvalidUTF8
+	}
+
+	text = strings.TrimPrefix(text, "\ufeff")
+	text = strings.Replace(text, "\r\n", "\n", -1)
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRightFunc(line, unicode.IsSpace)
+	}
+
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n"), nil
+}
+
+// WrapText wrap a text in lines no wider than width terminal cells. Lines are
+// only broken between words, unless a single word is wider than a line.
+// Wide runes (CJK ...) take two cells and terminal color escape codes none.
+// Explicit line breaks and the indentation of the first line of each
+// paragraph are preserved, tabs being expanded to 4 spaces.
+func WrapText(text string, width int) []string {
+	var result []string
+
+	for _, paragraph := range strings.Split(text, "\n") {
+		words := strings.Fields(paragraph)
+
+		if len(words) == 0 {
+			result = append(result, "")
+			continue
+		}
+
+		indent := paragraph[:len(paragraph)-len(strings.TrimLeftFunc(paragraph, unicode.IsSpace))]
+		indent = strings.Replace(indent, "\t", "    ", -1)
+
+		if width <= 0 {
+			result = append(result, indent+strings.Join(words, " "))
+			continue
+		}
+
+		if len(indent) >= width {
+			indent = ""
+		}
+
+		line := indent
+		lineWidth := len(indent)
+		lineEmpty := true
+
+		for _, word := range words {
+			wordWidth := wordLen(word)
+
+			// the word fit in the current line
+			if lineEmpty && lineWidth+wordWidth <= width {
+				line += word
+				lineWidth += wordWidth
+				lineEmpty = false
+				continue
+			}
+			if !lineEmpty && lineWidth+1+wordWidth <= width {
+				line += " " + word
+				lineWidth += 1 + wordWidth
+				continue
+			}
+
+			if !lineEmpty {
+				result = append(result, line)
+			}
+
+			// break a word wider than a line
+			for wordWidth > width {
+				part, leftover := splitWord(word, width)
+				if leftover == "" {
+					// a single rune wider than a line
+					break
+				}
+				result = append(result, part)
+				word = leftover
+				wordWidth = wordLen(word)
+			}
+
+			line, lineWidth, lineEmpty = word, wordWidth, false
+		}
+
+		result = append(result, line)
+	}
+
+	return result
+}
+
 func WordWrap(text string, lineWidth int) (string, int) {
 	words := strings.Fields(strings.TrimSpace(text))
 	if len(words) == 0 {
@@ -130,7 +233,7 @@ func wordLen(word string) int {
 		}
 
 		if !escape {
-			length++
+			length += runewidth.RuneWidth(char)
 		}
 
 		if char == 'm' {
@@ -155,13 +258,19 @@ func splitWord(word string, length int) (string, string) {
 			escape = true
 		}
 
-		result += string(char)
-
 		if !escape {
-			added++
-			if added == length {
+			width := runewidth.RuneWidth(char)
+			// a wide rune that doesn't fit is left for the next part
+			if added > 0 && added+width > length {
 				break
 			}
+			added += width
+		}
+
+		result += string(char)
+
+		if !escape && added >= length {
+			break
 		}
 
 		if char == 'm' {
@@ -180,3 +289,10 @@ func minInt(a, b int) int {
 	}
 	return a
 }
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -1,6 +1,7 @@
 package util
 
 import (
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -106,6 +107,95 @@ func TestTextWrap(t *testing.T) {
 	}
 }
 
+func TestWrapText(t *testing.T) {
+	cases := []struct {
+		Input  string
+		Width  int
+		Output []string
+	}{
+		// A simple line passes through
+		{
+			"foo bar",
+			10,
+			[]string{"foo bar"},
+		},
+		// Lines are broken between words
+		{
+			"foo bar baz",
+			7,
+			[]string{"foo bar", "baz"},
+		},
+		// Runs of whitespace are collapsed
+		{
+			"foo \t  bar  ",
+			10,
+			[]string{"foo bar"},
+		},
+		// Indentation is preserved
+		{
+			"foo\n\t* bar baz\n  qux",
+			10,
+			[]string{"foo", "    * bar", "baz", "  qux"},
+		},
+		// Explicit line breaks are preserved
+		{
+			"foo\n\nbar\n",
+			10,
+			[]string{"foo", "", "bar", ""},
+		},
+		// A word longer than a line is broken
+		{
+			"a foobarbazqux b",
+			4,
+			[]string{"a", "foob", "arba", "zqux", "b"},
+		},
+		// Colors sequences don't take any space
+		{
+			"\x1b[31mfoo\x1b[0m bar",
+			7,
+			[]string{"\x1b[31mfoo\x1b[0m bar"},
+		},
+		// Multi-byte runes count as one cell
+		{
+			"héllo wörld",
+			11,
+			[]string{"héllo wörld"},
+		},
+		// Wide runes count as two cells
+		{
+			"日本 語",
+			6,
+			[]string{"日本", "語"},
+		},
+		// A word of wide runes is broken between runes
+		{
+			"日本語です",
+			5,
+			[]string{"日本", "語で", "す"},
+		},
+		// A rune wider than the line stay in one piece
+		{
+			"日本",
+			1,
+			[]string{"日", "本"},
+		},
+		// No wrapping without width
+		{
+			"foo bar",
+			0,
+			[]string{"foo bar"},
+		},
+	}
+
+	for i, tc := range cases {
+		actual := WrapText(tc.Input, tc.Width)
+		if !reflect.DeepEqual(actual, tc.Output) {
+			t.Fatalf("Case %d Input:\n\n`%s`\n\nExpected Output:\n\n%q\n\nActual Output:\n\n%q",
+				i, tc.Input, tc.Output, actual)
+		}
+	}
+}
+
 func TestWordLen(t *testing.T) {
 	cases := []struct {
 		Input  string
@@ -126,6 +216,16 @@ func TestWordLen(t *testing.T) {
 			"foo\x1b[31mfoobarHoy\x1b[0mbaaar",
 			17,
 		},
+		// Wide runes take two cells
+		{
+			"日本語",
+			6,
+		},
+		// Multi-byte but narrow runes take one cell
+		{
+			"héllo",
+			5,
+		},
 	}
 
 	for i, tc := range cases {
@@ -179,6 +279,18 @@ func TestSplitWord(t *testing.T) {
 			0,
 			"", "foo",
 		},
+		// Don't cut a wide rune in half
+		{
+			"日本語",
+			3,
+			"日", "本語",
+		},
+		// Cut properly mixed width runes
+		{
+			"a日本",
+			3,
+			"a日", "本",
+		},
 	}
 
 	for i, tc := range cases {
@@ -189,3 +301,83 @@ func TestSplitWord(t *testing.T) {
 		}
 	}
 }
+
+func TestCleanupText(t *testing.T) {
+	cases := []struct {
+		Input, Output string
+		Err           error
+	}{
+		// A simple text passes through.
+		{
+			"foo bar",
+			"foo bar",
+			nil,
+		},
+		// CRLF are converted to LF
+		{
+			"foo\r\nbar\r\n\r\nbaz",
+			"foo\nbar\n\nbaz",
+			nil,
+		},
+		// A lone CR is kept
+		{
+			"foo\rbar",
+			"foo\rbar",
+			nil,
+		},
+		// A leading BOM is removed, but not in the middle of the text
+		{
+			"\ufefffoo\ufeffbar",
+			"foo\ufeffbar",
+			nil,
+		},
+		// Trailing whitespace on each line is removed
+		{
+			"foo  \n\tbar\t\n baz \u00a0",
+			"foo\n\tbar\n baz",
+			nil,
+		},
+		// Trailing blank lines are removed, leading and inner ones are kept
+		{
+			"\n\nfoo\n\n\nbar\n \n\t\r\n\n",
+			"\n\nfoo\n\n\nbar",
+			nil,
+		},
+		// Whitespace only
+		{
+			"\ufeff \r\n\t\n",
+			"",
+			nil,
+		},
+		// Non-ASCII text passes through
+		{
+			"René Descartes — cogito\r\n",
+			"René Descartes — cogito",
+			nil,
+		},
+		// Invalid UTF-8 is rejected
+		{
+			"foo\xff\xfebar",
+			"",
+			ErrInvalidUTF8,
+		},
+		// Latin-1 encoded text is rejected
+		{
+			"Ren\xe9",
+			"",
+			ErrInvalidUTF8,
+		},
+	}
+
+	for i, tc := range cases {
+		actual, err := CleanupText(tc.Input)
+		if err != tc.Err {
+			t.Fatalf("Case %d Input:\n\n`%q`\n\nExpected error: %v\n\nActual error: %v",
+				i, tc.Input, tc.Err, err)
+		}
+		if actual != tc.Output {
+			t.Fatalf("Case %d Input:\n\n`%q`\n\nExpected Output:\n\n`%q`\n\nActual Output:\n\n`%q`",
+				i, tc.Input, tc.Output, actual)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package webui
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+// The file recording the port of the web UI serving a repository, so that
+// the other tools can link to it while it's running. It's shared by the
+// worktrees, as they serve the same bugs.
+const portFile = "git-bug/webui.port"
+
+func portPath(repo repository.Repo) string {
+	return path.Join(repo.GetCommonDir(), portFile)
+}
+
+// WritePort record the port the web UI of the repository is listening to.
+// When the web UI serve several repositories, ref is the name of this one in
+// the URLs.
+func WritePort(repo repository.Repo, port int, ref string) error {
+	filePath := portPath(repo)
+
+	if err := os.MkdirAll(path.Dir(filePath), 0777); err != nil {
+		return err
+	}
+
+	content := fmt.Sprintf("%d\n", port)
+	if ref != "" {
+		content = fmt.Sprintf("%d %s\n", port, ref)
+	}
+
+	return ioutil.WriteFile(filePath, []byte(content), 0644)
+}
+
+// RemovePort remove the port recorded for the web UI of the repository
+func RemovePort(repo repository.Repo) error {
+	err := os.Remove(portPath(repo))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// RunningPort return the port of the web UI serving the repository, and the
+// name of the repository if it serve several ones, if one is running. As the
+// file is left behind if the web UI is killed, the port must also be
+// accepting connections.
+func RunningPort(repo repository.Repo) (int, string, bool) {
+	data, err := ioutil.ReadFile(portPath(repo))
+	if err != nil {
+		return 0, "", false
+	}
+
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 || len(fields) > 2 {
+		return 0, "", false
+	}
+
+	port, err := strconv.Atoi(fields[0])
+	if err != nil || port <= 0 || port > 65535 {
+		return 0, "", false
+	}
+
+	ref := ""
+	if len(fields) == 2 {
+		ref = fields[1]
+	}
+
+	conn, err := net.DialTimeout("tcp", fmt.Sprintf("127.0.0.1:%d", port), 200*time.Millisecond)
+	if err != nil {
+		return 0, "", false
+	}
+	conn.Close()
+
+	return port, ref, true
+}
+
+// BugUrl return the page of a bug in the running web UI of the repository,
+// if there is one
+func BugUrl(repo repository.Repo, id string) (string, bool) {
+	port, ref, ok := RunningPort(repo)
+	if !ok {
+		return "", false
+	}
+
+	if ref != "" {
+		return fmt.Sprintf("http://localhost:%d/r/%s/bug/%s", port, url.PathEscape(ref), id), true
+	}
+
+	return fmt.Sprintf("http://localhost:%d/bug/%s", port, id), true
+}
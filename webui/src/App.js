@@ -9,6 +9,8 @@ import { Link } from 'react-router-dom'
 
 import BugQuery from './bug/BugQuery'
 import ListQuery from './list/ListQuery'
+import Home from './repos/Home'
+import RepoSelector from './repos/RepoSelector'
 
 const styles = theme => ({
   appTitle: {
@@ -27,11 +29,16 @@ const App = ({location, classes}) => (
             git-bug webui
           </Typography>
         </Link>
+        <RepoSelector/>
       </Toolbar>
     </AppBar>
     <Switch>
-      <Route path="/" exact component={ListQuery}/>
+      <Route path="/" exact component={Home}/>
       <Route path="/bug/:id" exact component={BugQuery}/>
+      <Route path="/r/:repo" exact render={({match}) => (
+        <ListQuery repo={decodeURIComponent(match.params.repo)}/>
+      )}/>
+      <Route path="/r/:repo/bug/:id" exact component={BugQuery}/>
     </Switch>
   </React.Fragment>
 )
@@ -1,3 +1,4 @@
+import gql from "graphql-tag";
 import React from "react";
 import { withStyles } from "@material-ui/core/styles";
 import {
@@ -39,8 +40,28 @@ const _genStyle = background => ({
   borderBottomColor: darken(background, 0.2)
 });
 
-// Generate a style object (text, background and border colors) from the label
-const genStyle = label => _genStyle(getColor(label));
+// Generate a style object (text, background and border colors) from the label,
+// using the color configured in the registry if any
+const genStyle = (label, info) =>
+  _genStyle(info && info.color ? info.color : getColor(label));
+
+// The colors and descriptions of the labels of the repository, by label
+export const LabelRegistry = React.createContext({});
+
+// Index the labels of a repository query by name
+export const labelRegistry = labels =>
+  labels.reduce((registry, info) => ({ ...registry, [info.name]: info }), {});
+
+// The fragment to query the registry along with the repository
+export const labelsFragment = gql`
+  fragment Labels on Repository {
+    labels {
+      name
+      color
+      description
+    }
+  }
+`;
 
 const styles = theme => ({
   label: {
@@ -56,9 +77,20 @@ const styles = theme => ({
 });
 
 const Label = ({ label, classes }) => (
-  <span className={classes.label} style={genStyle(label)}>
-    {label}
-  </span>
+  <LabelRegistry.Consumer>
+    {registry => {
+      const info = registry[label];
+      return (
+        <span
+          className={classes.label}
+          style={genStyle(label, info)}
+          title={info && info.description ? info.description : undefined}
+        >
+          {label}
+        </span>
+      );
+    }}
+  </LabelRegistry.Consumer>
 );
 
 export default withStyles(styles)(Label);
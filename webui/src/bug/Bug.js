@@ -46,7 +46,7 @@ const styles = theme => ({
   }
 });
 
-const Bug = ({ bug, classes }) => (
+const Bug = ({ repo, bug, classes }) => (
   <main className={classes.main}>
     <div className={classes.header}>
       <span className={classes.title}>{bug.title}</span>
@@ -61,7 +61,7 @@ const Bug = ({ bug, classes }) => (
 
     <div className={classes.container}>
       <div className={classes.timeline}>
-        <TimelineQuery id={bug.id} />
+        <TimelineQuery repo={repo} id={bug.id} />
       </div>
       <div className={classes.sidebar}>
         <Typography variant={"subheading"}>Labels</Typography>
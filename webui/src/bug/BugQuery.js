@@ -4,27 +4,38 @@ import React from 'react'
 import { Query } from 'react-apollo'
 
 import Bug from './Bug'
+import { LabelRegistry, labelRegistry, labelsFragment } from '../Label'
 
 const QUERY = gql`
-  query GetBug($id: String!) {
-    defaultRepository {
+  query GetBug($repo: String!, $id: String!) {
+    repository(id: $repo) {
       bug(prefix: $id) {
         ...Bug
       }
+      ...Labels
     }
   }
 
   ${Bug.fragment}
+  ${labelsFragment}
 `
 
-const BugQuery = ({match}) => (
-  <Query query={QUERY} variables={{id: match.params.id}}>
-    {({loading, error, data}) => {
-      if (loading) return <CircularProgress/>
-      if (error) return <p>Error: {error}</p>
-      return <Bug bug={data.defaultRepository.bug}/>
-    }}
-  </Query>
-)
+const BugQuery = ({match}) => {
+  const repo = match.params.repo ? decodeURIComponent(match.params.repo) : ''
+
+  return (
+    <Query query={QUERY} variables={{repo, id: match.params.id}}>
+      {({loading, error, data}) => {
+        if (loading) return <CircularProgress/>
+        if (error) return <p>Error: {error}</p>
+        return (
+          <LabelRegistry.Provider value={labelRegistry(data.repository.labels)}>
+            <Bug repo={repo} bug={data.repository.bug}/>
+          </LabelRegistry.Provider>
+        )
+      }}
+    </Query>
+  )
+}
 
 export default BugQuery
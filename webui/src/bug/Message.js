@@ -23,7 +23,6 @@ const styles = theme => ({
     backgroundColor: '#fff',
     minHeight: 50,
     padding: 5,
-    whiteSpace: 'pre-wrap',
   }
 })
 
@@ -35,7 +34,8 @@ const Message = ({op, classes}) => (
       <Date date={op.date} />
     </div>
     <div className={classes.message}>
-      <Typography>{op.message}</Typography>
+      {/* rendered and sanitized by the backend */}
+      <Typography component="div" dangerouslySetInnerHTML={{__html: op.messageHtml}} />
     </div>
   </div>
 )
@@ -48,7 +48,7 @@ Message.createFragment = gql`
         name
         email
       }
-      message
+      messageHtml
     }
   }
 `
@@ -61,7 +61,7 @@ Message.commentFragment = gql`
         name
         email
       }
-      message
+      messageHtml
     }
   }
 `
@@ -9,8 +9,8 @@ import Timeline from './Timeline'
 import Message from './Message'
 
 const QUERY = gql`
-  query($id: String!, $first: Int = 10, $after: String) {
-    defaultRepository {
+  query($repo: String!, $id: String!, $first: Int = 10, $after: String) {
+    repository(id: $repo) {
       bug(prefix: $id) {
         operations(first: $first, after: $after) {
           nodes {
@@ -35,12 +35,12 @@ const QUERY = gql`
   ${SetStatus.fragment}
 `
 
-const TimelineQuery = ({id}) => (
-  <Query query={QUERY} variables={{id, first: 100}}>
+const TimelineQuery = ({repo, id}) => (
+  <Query query={QUERY} variables={{repo, id, first: 100}}>
     {({loading, error, data, fetchMore}) => {
       if (loading) return <CircularProgress/>
       if (error) return <p>Error: {error}</p>
-      return <Timeline ops={data.defaultRepository.bug.operations.nodes} fetchMore={fetchMore}/>
+      return <Timeline ops={data.repository.bug.operations.nodes} fetchMore={fetchMore}/>
     }}
   </Query>
 )
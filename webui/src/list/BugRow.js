@@ -9,6 +9,7 @@ import React from "react";
 import { Link } from "react-router-dom";
 import Date from "../Date";
 import Label from "../Label";
+import { repoPath } from "../repo";
 
 const Open = ({ className }) => (
   <Tooltip title="Open">
@@ -55,12 +56,12 @@ const styles = theme => ({
   }
 });
 
-const BugRow = ({ bug, classes }) => (
+const BugRow = ({ repo, bug, classes }) => (
   <TableRow hover>
     <TableCell className={classes.cell}>
       <Status status={bug.status} className={classes.status} />
       <div className={classes.expand}>
-        <Link to={"bug/" + bug.humanId}>
+        <Link to={repoPath(repo, "/bug/" + bug.humanId)}>
           <div className={classes.expand}>
             <Typography variant={"title"} className={classes.title}>
               {bug.title}
@@ -1,72 +1,46 @@
-import { withStyles } from '@material-ui/core/styles'
 import Table from '@material-ui/core/Table/Table'
 import TableBody from '@material-ui/core/TableBody/TableBody'
 import TablePagination from '@material-ui/core/TablePagination/TablePagination'
 import React from 'react'
 import BugRow from './BugRow'
 
-const styles = theme => ({
-  main: {
-    maxWidth: 600,
-    margin: 'auto',
-    marginTop: theme.spacing.unit * 4
-  }
-})
-
+// The page of bugs, paginated with the cursors put in the URL by navigate,
+// so that each page can be linked
 class List extends React.Component {
 
   props: {
+    repo: string,
     bugs: Array,
-    fetchMore: (any) => any,
-    classes: any,
-  }
-
-  state = {
-    page: 0,
-    rowsPerPage: 10,
-    lastQuery: {}
+    page: number,
+    rowsPerPage: number,
+    navigate: (any) => any,
   }
 
   handleChangePage = (event, page) => {
-    const {bugs, fetchMore} = this.props
-    const {rowsPerPage} = this.state
+    const {bugs, navigate} = this.props
     const pageInfo = bugs.pageInfo
 
-    if (page === this.state.page + 1) {
+    if (page === this.props.page + 1) {
       if (!pageInfo.hasNextPage) {
         return
       }
 
-      const variables = {
-        after: pageInfo.endCursor,
-        first: rowsPerPage
-      }
-
-      fetchMore({
-        variables,
-        updateQuery: this.updateQuery,
-      })
-
-      this.setState({page, lastQuery: variables})
+      navigate({after: pageInfo.endCursor, before: null, page})
       return
     }
 
-    if (page === this.state.page - 1) {
+    if (page === this.props.page - 1) {
       if (!pageInfo.hasPreviousPage) {
         return
       }
 
-      const variables = {
-        before: pageInfo.startCursor,
-        last: rowsPerPage
+      // the first page has no cursor, like when the list is opened
+      if (page === 0) {
+        navigate({after: null, before: null, page: null})
+        return
       }
 
-      fetchMore({
-        variables,
-        updateQuery: this.updateQuery,
-      })
-
-      this.setState({page, lastQuery: variables})
+      navigate({after: null, before: pageInfo.startCursor, page})
       return
     }
 
@@ -74,42 +48,19 @@ class List extends React.Component {
   }
 
   handleChangeRowsPerPage = event => {
-    const {fetchMore} = this.props
-    const {lastQuery} = this.state
-    const rowsPerPage = event.target.value
-
-    const variables = lastQuery
-
-    if (lastQuery.first) {
-      variables.first = rowsPerPage
-    } else if (lastQuery.last) {
-      variables.last = rowsPerPage
-    } else {
-      variables.first = rowsPerPage
-    }
-
-    fetchMore({
-      variables,
-      updateQuery: this.updateQuery,
-    })
-
-    this.setState({rowsPerPage, lastQuery: variables})
-  }
-
-  updateQuery = (previousResult, {fetchMoreResult}) => {
-    return fetchMoreResult ? fetchMoreResult : previousResult
+    // the pages change with their size, start again from the first one
+    this.props.navigate({rows: event.target.value, after: null, before: null, page: null})
   }
 
   render() {
-    const {classes, bugs} = this.props
-    const {page, rowsPerPage} = this.state
+    const {repo, bugs, page, rowsPerPage} = this.props
 
     return (
-      <main className={classes.main}>
-        <Table className={classes.table}>
+      <React.Fragment>
+        <Table>
           <TableBody>
             {bugs.edges.map(({cursor, node}) => (
-              <BugRow bug={node} key={cursor}/>
+              <BugRow repo={repo} bug={node} key={cursor}/>
             ))}
           </TableBody>
         </Table>
@@ -127,9 +78,9 @@ class List extends React.Component {
           onChangePage={this.handleChangePage}
           onChangeRowsPerPage={this.handleChangeRowsPerPage}
         />
-      </main>
+      </React.Fragment>
     )
   }
 }
 
-export default withStyles(styles)(List)
+export default List
@@ -1,15 +1,20 @@
 // @flow
 import CircularProgress from '@material-ui/core/CircularProgress'
+import { withStyles } from '@material-ui/core/styles'
+import Typography from '@material-ui/core/Typography'
 import gql from 'graphql-tag'
 import React from 'react'
 import { Query } from 'react-apollo'
+import { withRouter } from 'react-router'
+import { LabelRegistry, labelRegistry, labelsFragment } from '../Label'
 import BugRow from './BugRow'
 import List from './List'
+import Search from './Search'
 
 const QUERY = gql`
-  query($first: Int = 10, $last: Int, $after: String, $before: String) {
-    defaultRepository {
-      bugs: allBugs(first: $first, last: $last, after: $after, before: $before) {
+  query($repo: String!, $query: String, $first: Int, $last: Int, $after: String, $before: String) {
+    repository(id: $repo) {
+      bugs: allBugs(query: $query, first: $first, last: $last, after: $after, before: $before) {
         totalCount
         edges {
           cursor
@@ -24,21 +29,104 @@ const QUERY = gql`
           endCursor
         }
       }
+      ...Labels
     }
   }
 
 
   ${BugRow.fragment}
+  ${labelsFragment}
 `
 
-const ListQuery = () => (
-  <Query query={QUERY}>
-    {({loading, error, data, fetchMore}) => {
-      if (loading) return <CircularProgress/>
-      if (error) return <p>Error: {error}</p>
-      return <List bugs={data.defaultRepository.bugs} fetchMore={fetchMore}/>
-    }}
-  </Query>
-)
-
-export default ListQuery
+const defaultRowsPerPage = 10
+
+const styles = theme => ({
+  main: {
+    maxWidth: 600,
+    margin: 'auto',
+    marginTop: theme.spacing.unit * 4
+  },
+  error: {
+    padding: theme.spacing.unit * 2
+  }
+})
+
+// The state of the list is kept in the parameters of the URL, so that it
+// can be bookmarked: q the query, as given to git bug ls, then the cursor
+// of the page and its number.
+const parseParams = search => {
+  const params = new URLSearchParams(search)
+  const rows = parseInt(params.get('rows'), 10) || defaultRowsPerPage
+  const after = params.get('after')
+  const before = params.get('before')
+
+  const variables = {query: params.get('q') || ''}
+  if (before) {
+    variables.before = before
+    variables.last = rows
+  } else {
+    variables.first = rows
+    if (after) variables.after = after
+  }
+
+  return {
+    variables,
+    page: parseInt(params.get('page'), 10) || 0,
+    rowsPerPage: rows,
+  }
+}
+
+// The message of the errors of the server, like an invalid query
+const errorMessage = error =>
+  error.graphQLErrors && error.graphQLErrors.length
+    ? error.graphQLErrors.map(e => e.message).join(', ')
+    : error.message
+
+const ListQuery = ({repo, location, history, classes}) => {
+  const {variables, page, rowsPerPage} = parseParams(location.search)
+
+  // update the parameters of the URL, the null ones being removed
+  const navigate = changes => {
+    const params = new URLSearchParams(location.search)
+    Object.keys(changes).forEach(key => {
+      if (changes[key] === null || changes[key] === '') {
+        params.delete(key)
+      } else {
+        params.set(key, changes[key])
+      }
+    })
+    history.push({search: params.toString()})
+  }
+
+  // a new query start again from the first page
+  const search = query => navigate({q: query, after: null, before: null, page: null})
+
+  return (
+    <main className={classes.main}>
+      <Search query={variables.query} onSearch={search}/>
+      <Query query={QUERY} variables={{repo, ...variables}}>
+        {({loading, error, data}) => {
+          if (loading) return <CircularProgress/>
+          if (error) return (
+            <Typography color="error" className={classes.error}>
+              {errorMessage(error)}
+            </Typography>
+          )
+          return (
+            <LabelRegistry.Provider value={labelRegistry(data.repository.labels)}>
+              <List
+                repo={repo}
+                bugs={data.repository.bugs}
+                page={page}
+                rowsPerPage={rowsPerPage}
+                navigate={navigate}
+              />
+            </LabelRegistry.Provider>
+          )
+        }}
+      </Query>
+    </main>
+  )
+}
+
+export default withStyles(styles)(withRouter(ListQuery))
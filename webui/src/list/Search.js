@@ -0,0 +1,59 @@
+// @flow
+import { withStyles } from '@material-ui/core/styles'
+import TextField from '@material-ui/core/TextField'
+import React from 'react'
+
+const styles = theme => ({
+  form: {
+    padding: `0 ${theme.spacing.unit * 2}px`
+  }
+})
+
+// The query selecting the bugs, in the language of git bug ls, submitted
+// with enter
+class Search extends React.Component {
+
+  props: {
+    query: string,
+    onSearch: (string) => any,
+    classes: any,
+  }
+
+  state = {
+    query: this.props.query
+  }
+
+  componentDidUpdate(prevProps) {
+    // the query changed with the URL, by going back for instance
+    if (prevProps.query !== this.props.query) {
+      this.setState({query: this.props.query})
+    }
+  }
+
+  handleChange = event => {
+    this.setState({query: event.target.value})
+  }
+
+  handleSubmit = event => {
+    event.preventDefault()
+    this.props.onSearch(this.state.query.trim())
+  }
+
+  render() {
+    const {classes} = this.props
+
+    return (
+      <form className={classes.form} onSubmit={this.handleSubmit}>
+        <TextField
+          fullWidth
+          margin="normal"
+          placeholder="status:open label:UI sort:edit-desc"
+          value={this.state.query}
+          onChange={this.handleChange}
+        />
+      </form>
+    )
+  }
+}
+
+export default withStyles(styles)(Search)
@@ -0,0 +1,4 @@
+// The path of a page of a repository. The only repository served by
+// default has an empty id, and keeps the short paths.
+export const repoPath = (repo, path) =>
+  repo ? `/r/${encodeURIComponent(repo)}${path}` : path
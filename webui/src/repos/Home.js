@@ -0,0 +1,48 @@
+import CircularProgress from '@material-ui/core/CircularProgress'
+import List from '@material-ui/core/List'
+import ListItem from '@material-ui/core/ListItem'
+import ListItemText from '@material-ui/core/ListItemText'
+import { withStyles } from '@material-ui/core/styles'
+import React from 'react'
+import { Query } from 'react-apollo'
+import { Link } from 'react-router-dom'
+
+import ListQuery from '../list/ListQuery'
+import { repoPath } from '../repo'
+import { REPOS_QUERY } from './ReposQuery'
+
+const styles = theme => ({
+  main: {
+    maxWidth: 600,
+    margin: 'auto',
+    marginTop: theme.spacing.unit * 4
+  }
+})
+
+// The bugs of the repository when there is only one, or else the
+// repositories to choose from
+const Home = ({classes}) => (
+  <Query query={REPOS_QUERY}>
+    {({loading, error, data}) => {
+      if (loading) return <CircularProgress/>
+      if (error) return <p>Error: {error}</p>
+
+      const repos = data.repositories
+      if (repos.length === 1) return <ListQuery repo={repos[0].id}/>
+
+      return (
+        <main className={classes.main}>
+          <List>
+            {repos.map(({id}) => (
+              <ListItem button component={Link} to={repoPath(id, '/')} key={id}>
+                <ListItemText primary={id}/>
+              </ListItem>
+            ))}
+          </List>
+        </main>
+      )
+    }}
+  </Query>
+)
+
+export default withStyles(styles)(Home)
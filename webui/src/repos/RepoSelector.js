@@ -0,0 +1,46 @@
+import MenuItem from '@material-ui/core/MenuItem'
+import Select from '@material-ui/core/Select'
+import { withStyles } from '@material-ui/core/styles'
+import React from 'react'
+import { Query } from 'react-apollo'
+import { matchPath, withRouter } from 'react-router'
+
+import { repoPath } from '../repo'
+import { REPOS_QUERY } from './ReposQuery'
+
+const styles = theme => ({
+  select: {
+    marginLeft: theme.spacing.unit * 4,
+    color: 'white'
+  }
+})
+
+// Switch between the repositories, shown only when several are served
+const RepoSelector = ({classes, history, location}) => {
+  const match = matchPath(location.pathname, {path: '/r/:repo'})
+  const current = match ? decodeURIComponent(match.params.repo) : ''
+
+  return (
+    <Query query={REPOS_QUERY}>
+      {({loading, error, data}) => {
+        if (loading || error || data.repositories.length < 2) return null
+
+        return (
+          <Select
+            className={classes.select}
+            value={current}
+            displayEmpty
+            onChange={event => history.push(repoPath(event.target.value, '/'))}
+          >
+            <MenuItem value="" disabled>Repository</MenuItem>
+            {data.repositories.map(({id}) => (
+              <MenuItem value={id} key={id}>{id}</MenuItem>
+            ))}
+          </Select>
+        )
+      }}
+    </Query>
+  )
+}
+
+export default withStyles(styles)(withRouter(RepoSelector))
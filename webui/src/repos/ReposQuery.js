@@ -0,0 +1,9 @@
+import gql from 'graphql-tag'
+
+export const REPOS_QUERY = gql`
+  query {
+    repositories {
+      id
+    }
+  }
+`